@@ -3,6 +3,7 @@ package base
 
 import (
 	"context"
+	"math"
 
 	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
@@ -114,6 +115,8 @@ func CollisionGeometry(cfg *referenceframe.LinkConfig) (spatialmath.Geometry, er
 		r += geoCfg.R
 	case spatialmath.CapsuleType:
 		r += geoCfg.L / 2
+	case spatialmath.CylinderType:
+		r += math.Hypot(geoCfg.L/2, geoCfg.R)
 	case spatialmath.UnknownType:
 		// no type specified, iterate through supported types and try to infer intent
 		if norm := (r3.Vector{X: geoCfg.X, Y: geoCfg.Y, Z: geoCfg.Z}).Norm(); norm > 0 {
@@ -124,6 +127,15 @@ func CollisionGeometry(cfg *referenceframe.LinkConfig) (spatialmath.Geometry, er
 			r += geoCfg.R
 		}
 	case spatialmath.PointType:
+	case spatialmath.MeshType:
+		mesh, err := geoCfg.ParseConfig()
+		if err != nil {
+			return nil, err
+		}
+		r = 0
+		for _, pt := range mesh.ToPoints(0) {
+			r = math.Max(r, pt.Norm())
+		}
 	default:
 		return nil, spatialmath.ErrGeometryTypeUnsupported
 	}
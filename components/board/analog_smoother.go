@@ -2,6 +2,7 @@ package board
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,16 +16,72 @@ import (
 
 var errStopReading = errors.New("stop reading")
 
+// An AnalogSample is a single filtered reading produced by an AnalogSmoother.
+type AnalogSample struct {
+	Value int
+	Time  time.Time
+}
+
+// An AnalogStreamer is an AnalogReader that can push each new filtered
+// sample to interested listeners as it is taken.
+type AnalogStreamer interface {
+	AnalogReader
+
+	// AddCallback adds a listener that is sent every new sample. Sends
+	// never block the sampling routine; if the channel is full the sample
+	// is dropped for that listener.
+	AddCallback(c chan AnalogSample)
+
+	// RemoveCallback removes a listener for samples.
+	RemoveCallback(c chan AnalogSample)
+
+	// SamplesSince returns the recent samples taken after the given time,
+	// oldest first. Only the samples of the last few seconds are kept.
+	SamplesSince(t time.Time) []AnalogSample
+}
+
+// How long an AnalogSmoother keeps its samples around for SamplesSince.
+const analogHistoryDuration = 5 * time.Second
+
+// An analogWindow combines the most recent samples into a single value.
+type analogWindow interface {
+	Add(x int)
+	Value() int
+}
+
+type averageWindow struct {
+	*utils.RollingAverage
+}
+
+func (w averageWindow) Value() int {
+	return w.Average()
+}
+
+type medianWindow struct {
+	*utils.RollingMedian
+}
+
+func (w medianWindow) Value() int {
+	return w.Median()
+}
+
 // An AnalogSmoother smooths the readings out from an underlying reader.
 type AnalogSmoother struct {
 	Raw                     AnalogReader
 	AverageOverMillis       int
 	SamplesPerSecond        int
-	data                    *utils.RollingAverage
+	Filter                  string
+	data                    analogWindow
 	lastError               atomic.Pointer[errValue]
 	logger                  golog.Logger
 	cancel                  func()
 	activeBackgroundWorkers sync.WaitGroup
+
+	callbacksMu sync.RWMutex
+	callbacks   []chan AnalogSample
+
+	historyMu sync.Mutex
+	history   []AnalogSample
 }
 
 // SmoothAnalogReader wraps the given reader in a smoother.
@@ -34,6 +91,7 @@ func SmoothAnalogReader(r AnalogReader, c AnalogConfig, logger golog.Logger) *An
 		Raw:               r,
 		AverageOverMillis: c.AverageOverMillis,
 		SamplesPerSecond:  c.SamplesPerSecond,
+		Filter:            c.Filter,
 		logger:            logger,
 		cancel:            cancel,
 	}
@@ -54,9 +112,13 @@ func (as *AnalogSmoother) Close(ctx context.Context) error {
 	return nil
 }
 
-// Read returns the smoothed out reading.
+// Read returns the smoothed out reading. If no sampling rate is configured
+// there is nothing to smooth over and the underlying reader is read directly.
 func (as *AnalogSmoother) Read(ctx context.Context, extra map[string]interface{}) (int, error) {
-	avg := as.data.Average()
+	if as.SamplesPerSecond <= 0 {
+		return as.Raw.Read(ctx, extra)
+	}
+	avg := as.data.Value()
 	lastErr := as.lastError.Load()
 	if lastErr == nil {
 		return avg, nil
@@ -68,6 +130,56 @@ func (as *AnalogSmoother) Read(ctx context.Context, extra map[string]interface{}
 	return avg, nil
 }
 
+// AddCallback adds a listener for samples.
+func (as *AnalogSmoother) AddCallback(c chan AnalogSample) {
+	as.callbacksMu.Lock()
+	defer as.callbacksMu.Unlock()
+	as.callbacks = append(as.callbacks, c)
+}
+
+// RemoveCallback removes a listener for samples.
+func (as *AnalogSmoother) RemoveCallback(c chan AnalogSample) {
+	as.callbacksMu.Lock()
+	defer as.callbacksMu.Unlock()
+	for id := range as.callbacks {
+		if as.callbacks[id] == c {
+			as.callbacks[id] = as.callbacks[len(as.callbacks)-1]
+			as.callbacks = as.callbacks[:len(as.callbacks)-1]
+			break
+		}
+	}
+}
+
+// SamplesSince returns the samples of the last few seconds taken after t.
+func (as *AnalogSmoother) SamplesSince(t time.Time) []AnalogSample {
+	as.historyMu.Lock()
+	defer as.historyMu.Unlock()
+	idx := sort.Search(len(as.history), func(i int) bool { return as.history[i].Time.After(t) })
+	samples := make([]AnalogSample, len(as.history)-idx)
+	copy(samples, as.history[idx:])
+	return samples
+}
+
+func (as *AnalogSmoother) publish(sample AnalogSample) {
+	as.historyMu.Lock()
+	as.history = append(as.history, sample)
+	dropped := 0
+	for dropped < len(as.history) && sample.Time.Sub(as.history[dropped].Time) > analogHistoryDuration {
+		dropped++
+	}
+	as.history = as.history[dropped:]
+	as.historyMu.Unlock()
+
+	as.callbacksMu.RLock()
+	defer as.callbacksMu.RUnlock()
+	for _, c := range as.callbacks {
+		select {
+		case c <- sample:
+		default:
+		}
+	}
+}
+
 // Start begins the smoothing routine that reads from the underlying
 // analog reader.
 func (as *AnalogSmoother) Start(ctx context.Context) {
@@ -87,7 +199,17 @@ func (as *AnalogSmoother) Start(ctx context.Context) {
 	//    numSamples        4
 
 	numSamples := (as.SamplesPerSecond * as.AverageOverMillis) / 1000
-	as.data = utils.NewRollingAverage(numSamples)
+	if numSamples < 1 {
+		numSamples = 1
+	}
+	if as.Filter == AnalogFilterMedian {
+		as.data = medianWindow{utils.NewRollingMedian(numSamples)}
+	} else {
+		as.data = averageWindow{utils.NewRollingAverage(numSamples)}
+	}
+	if as.SamplesPerSecond <= 0 {
+		return
+	}
 	nanosBetween := 1e9 / as.SamplesPerSecond
 
 	as.activeBackgroundWorkers.Add(1)
@@ -105,6 +227,7 @@ func (as *AnalogSmoother) Start(ctx context.Context) {
 			}
 
 			as.data.Add(reading)
+			as.publish(AnalogSample{Value: as.data.Value(), Time: start})
 
 			end := time.Now()
 
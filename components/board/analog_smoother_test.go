@@ -65,3 +65,81 @@ func TestAnalogSmoother1(t *testing.T) {
 
 	test.That(t, as.Close(context.Background()), test.ShouldBeNil)
 }
+
+type constReader struct {
+	mu   sync.Mutex
+	vals []int
+	n    int
+}
+
+func (c *constReader) Read(ctx context.Context, extra map[string]interface{}) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.n >= len(c.vals) {
+		return 0, errStopReading
+	}
+	v := c.vals[c.n]
+	c.n++
+	return v, nil
+}
+
+func (c *constReader) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestAnalogSmootherMedian(t *testing.T) {
+	reader := &constReader{vals: []int{10, 10, 1000, 10, 10}}
+
+	logger := golog.NewTestLogger(t)
+	as := SmoothAnalogReader(reader, AnalogConfig{
+		AverageOverMillis: 5,
+		SamplesPerSecond:  1000,
+		Filter:            AnalogFilterMedian,
+	}, logger)
+
+	testutils.WaitForAssertionWithSleep(t, 10*time.Millisecond, 200, func(tb testing.TB) {
+		tb.Helper()
+		v, err := as.Read(context.Background(), nil)
+		test.That(tb, err, test.ShouldEqual, errStopReading)
+		test.That(tb, v, test.ShouldEqual, 10)
+	})
+
+	test.That(t, as.Close(context.Background()), test.ShouldBeNil)
+}
+
+func TestAnalogSmootherCallbacks(t *testing.T) {
+	reader := &constReader{vals: []int{4, 8, 12}}
+
+	logger := golog.NewTestLogger(t)
+	as := &AnalogSmoother{
+		Raw:               reader,
+		AverageOverMillis: 10,
+		SamplesPerSecond:  100,
+		logger:            logger,
+	}
+	samples := make(chan AnalogSample, 10)
+	as.AddCallback(samples)
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	as.cancel = cancel
+	as.Start(cancelCtx)
+
+	var got []int
+	for i := 0; i < 3; i++ {
+		got = append(got, (<-samples).Value)
+	}
+	test.That(t, got, test.ShouldResemble, []int{4, 8, 12})
+
+	as.RemoveCallback(samples)
+	test.That(t, as.callbacks, test.ShouldBeEmpty)
+	test.That(t, as.Close(context.Background()), test.ShouldBeNil)
+}
+
+func TestAnalogSmootherNoRate(t *testing.T) {
+	reader := &constReader{vals: []int{42}}
+	as := SmoothAnalogReader(reader, AnalogConfig{}, golog.NewTestLogger(t))
+	v, err := as.Read(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, v, test.ShouldEqual, 42)
+	test.That(t, as.Close(context.Background()), test.ShouldBeNil)
+}
@@ -0,0 +1,161 @@
+package board
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+)
+
+// AnalogSamplesCommand is the DoCommand that returns the recent samples of an analog reader that streams them, such as
+// {"analog_samples": {"reader": "a1", "since": "2023-01-02T15:04:05.999999999Z"}}. The response holds the samples taken
+// after "since", or all the recent ones if it is left out, as {"samples": [{"value": 12, "time": "..."}]}. This is how
+// clients stream the samples of a remote analog reader, since the board API has no streaming call for them.
+const AnalogSamplesCommand = "analog_samples"
+
+// How often clients ask a remote board for new samples of a streamed analog reader.
+const analogSamplesPollInterval = 100 * time.Millisecond
+
+// DoAnalogSamplesCommand answers an AnalogSamplesCommand with the samples of the board's analog reader. It returns false
+// if cmd is not an AnalogSamplesCommand, so that boards can handle their other commands.
+func DoAnalogSamplesCommand(b Board, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	args, ok := cmd[AnalogSamplesCommand].(map[string]interface{})
+	if !ok {
+		return nil, false, nil
+	}
+	name, ok := args["reader"].(string)
+	if !ok {
+		return nil, true, errors.New("analog samples command needs the name of a reader")
+	}
+	reader, ok := b.AnalogReaderByName(name)
+	if !ok {
+		return nil, true, errors.Errorf("no analog reader named %q", name)
+	}
+	streamer, ok := reader.(AnalogStreamer)
+	if !ok {
+		return nil, true, errors.Errorf("analog reader %q does not stream samples", name)
+	}
+	var since time.Time
+	if s, ok := args["since"].(string); ok {
+		var err error
+		if since, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			return nil, true, err
+		}
+	}
+	samples := streamer.SamplesSince(since)
+	encoded := make([]interface{}, 0, len(samples))
+	for _, s := range samples {
+		encoded = append(encoded, map[string]interface{}{"value": s.Value, "time": s.Time.Format(time.RFC3339Nano)})
+	}
+	return map[string]interface{}{"samples": encoded}, true, nil
+}
+
+// analogSamplesFromResponse decodes the samples of an AnalogSamplesCommand response.
+func analogSamplesFromResponse(resp map[string]interface{}) ([]AnalogSample, error) {
+	encoded, ok := resp["samples"].([]interface{})
+	if !ok {
+		return nil, errors.New("analog samples response has no samples")
+	}
+	samples := make([]AnalogSample, 0, len(encoded))
+	for _, e := range encoded {
+		fields, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("malformed analog sample %v", e)
+		}
+		var value int
+		switch v := fields["value"].(type) {
+		case float64:
+			value = int(v)
+		case int:
+			value = v
+		default:
+			return nil, errors.Errorf("malformed analog sample value %v", fields["value"])
+		}
+		ts, ok := fields["time"].(string)
+		if !ok {
+			return nil, errors.Errorf("malformed analog sample time %v", fields["time"])
+		}
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, AnalogSample{Value: value, Time: t})
+	}
+	return samples, nil
+}
+
+// analogSamplePoller streams the samples of a remote analog reader to listeners by polling the board for new ones
+// with AnalogSamplesCommand while there are any listeners.
+type analogSamplePoller struct {
+	mu        sync.Mutex
+	callbacks []chan AnalogSample
+	cancel    func()
+	done      chan struct{}
+}
+
+func (p *analogSamplePoller) add(c chan AnalogSample, samplesSince func(ctx context.Context, t time.Time) ([]AnalogSample, error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.callbacks = append(p.callbacks, c)
+	if p.cancel != nil {
+		return
+	}
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	p.cancel = cancel
+	p.done = done
+	goutils.ManagedGo(func() {
+		// the samples from before the first poll are only used to know where to start, since the clock of the
+		// board may not match ours
+		var last time.Time
+		started := false
+		for goutils.SelectContextOrWait(cancelCtx, analogSamplesPollInterval) {
+			samples, err := samplesSince(cancelCtx, last)
+			if err != nil {
+				continue
+			}
+			if len(samples) > 0 {
+				last = samples[len(samples)-1].Time
+			}
+			if !started {
+				started = true
+				continue
+			}
+			p.mu.Lock()
+			for _, s := range samples {
+				for _, c := range p.callbacks {
+					select {
+					case c <- s:
+					default:
+					}
+				}
+			}
+			p.mu.Unlock()
+		}
+	}, func() { close(done) })
+}
+
+func (p *analogSamplePoller) remove(c chan AnalogSample) {
+	p.mu.Lock()
+	for id := range p.callbacks {
+		if p.callbacks[id] == c {
+			p.callbacks[id] = p.callbacks[len(p.callbacks)-1]
+			p.callbacks = p.callbacks[:len(p.callbacks)-1]
+			break
+		}
+	}
+	var cancel func()
+	var done chan struct{}
+	if len(p.callbacks) == 0 && p.cancel != nil {
+		cancel, done = p.cancel, p.done
+		p.cancel, p.done = nil, nil
+	}
+	p.mu.Unlock()
+	// the poller takes the lock to publish, so it is waited on outside of it
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
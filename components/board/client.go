@@ -214,6 +214,7 @@ type analogReaderClient struct {
 	*client
 	boardName        string
 	analogReaderName string
+	poller           analogSamplePoller
 }
 
 func (arc *analogReaderClient) Read(ctx context.Context, extra map[string]interface{}) (int, error) {
@@ -232,6 +233,41 @@ func (arc *analogReaderClient) Read(ctx context.Context, extra map[string]interf
 	return int(resp.Value), nil
 }
 
+// AddCallback adds a listener for the samples of the remote reader, which are polled for with AnalogSamplesCommand
+// while there are listeners.
+func (arc *analogReaderClient) AddCallback(c chan AnalogSample) {
+	arc.poller.add(c, func(ctx context.Context, t time.Time) ([]AnalogSample, error) {
+		return arc.samplesSince(ctx, t)
+	})
+}
+
+// RemoveCallback removes a listener for samples.
+func (arc *analogReaderClient) RemoveCallback(c chan AnalogSample) {
+	arc.poller.remove(c)
+}
+
+// SamplesSince returns the recent samples of the remote reader taken after t, or none if they cannot be fetched.
+func (arc *analogReaderClient) SamplesSince(t time.Time) []AnalogSample {
+	samples, err := arc.samplesSince(context.Background(), t)
+	if err != nil {
+		arc.logger.Debugw("failed to get analog samples", "reader", arc.analogReaderName, "error", err)
+		return nil
+	}
+	return samples
+}
+
+func (arc *analogReaderClient) samplesSince(ctx context.Context, t time.Time) ([]AnalogSample, error) {
+	args := map[string]interface{}{"reader": arc.analogReaderName}
+	if !t.IsZero() {
+		args["since"] = t.Format(time.RFC3339Nano)
+	}
+	resp, err := arc.DoCommand(ctx, map[string]interface{}{AnalogSamplesCommand: args})
+	if err != nil {
+		return nil, err
+	}
+	return analogSamplesFromResponse(resp)
+}
+
 // digitalInterruptClient satisfies a gRPC based board.DigitalInterrupt. Refer to the
 // interface for descriptions of its methods.
 type digitalInterruptClient struct {
@@ -291,3 +291,60 @@ func TestClientWithoutStatus(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, conn.Close(), test.ShouldBeNil)
 }
+
+func TestClientAnalogSamples(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+
+	value := 0
+	injectAnalogReader := &inject.AnalogReader{}
+	injectAnalogReader.ReadFunc = func(ctx context.Context, extra map[string]interface{}) (int, error) {
+		value++
+		return value, nil
+	}
+	smoother := board.SmoothAnalogReader(injectAnalogReader, board.AnalogConfig{SamplesPerSecond: 100}, logger)
+	defer func() {
+		test.That(t, smoother.Close(context.Background()), test.ShouldBeNil)
+	}()
+	injectBoard := &inject.Board{}
+	injectBoard.StatusFunc = func(ctx context.Context, extra map[string]interface{}) (*commonpb.BoardStatus, error) {
+		return &commonpb.BoardStatus{}, nil
+	}
+	injectBoard.AnalogReaderByNameFunc = func(name string) (board.AnalogReader, bool) {
+		return smoother, name == "analog1"
+	}
+	injectBoard.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		resp, _, err := board.DoAnalogSamplesCommand(injectBoard, cmd)
+		return resp, err
+	}
+
+	listener, cleanup := setupService(t, injectBoard)
+	defer cleanup()
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	client, err := board.NewClientFromConn(context.Background(), conn, "", board.Named(testBoardName), logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	reader, ok := client.AnalogReaderByName("analog1")
+	test.That(t, ok, test.ShouldBeTrue)
+	streamer, ok := reader.(board.AnalogStreamer)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	samples := make(chan board.AnalogSample, 100)
+	streamer.AddCallback(samples)
+	first := <-samples
+	second := <-samples
+	test.That(t, second.Time.After(first.Time), test.ShouldBeTrue)
+	streamer.RemoveCallback(samples)
+
+	recent := streamer.SamplesSince(first.Time)
+	test.That(t, recent, test.ShouldNotBeEmpty)
+	test.That(t, recent[0].Time.After(first.Time), test.ShouldBeTrue)
+
+	_, err = client.DoCommand(context.Background(), map[string]interface{}{
+		board.AnalogSamplesCommand: map[string]interface{}{"reader": "analog2"},
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, client.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, conn.Close(), test.ShouldBeNil)
+}
@@ -1,6 +1,7 @@
 package board
 
 import (
+	"github.com/pkg/errors"
 	"go.viam.com/utils"
 )
 
@@ -43,13 +44,32 @@ type AnalogConfig struct {
 	ChipSelect        string `json:"chip_select"` // the CS line for the ADC chip, typically a pin number on the board
 	AverageOverMillis int    `json:"average_over_ms,omitempty"`
 	SamplesPerSecond  int    `json:"samples_per_sec,omitempty"`
+	Filter            string `json:"filter,omitempty"` // how samples in the window are combined, "average" or "median"
 }
 
+// The supported analog filters. An empty filter means AnalogFilterAverage.
+const (
+	AnalogFilterAverage = "average"
+	AnalogFilterMedian  = "median"
+)
+
 // Validate ensures all parts of the config are valid.
 func (config *AnalogConfig) Validate(path string) error {
 	if config.Name == "" {
 		return utils.NewConfigValidationFieldRequiredError(path, "name")
 	}
+	if config.AverageOverMillis < 0 {
+		return utils.NewConfigValidationError(path, errors.New("average_over_ms cannot be negative"))
+	}
+	if config.SamplesPerSecond < 0 {
+		return utils.NewConfigValidationError(path, errors.New("samples_per_sec cannot be negative"))
+	}
+	switch config.Filter {
+	case "", AnalogFilterAverage, AnalogFilterMedian:
+	default:
+		return utils.NewConfigValidationError(path,
+			errors.Errorf("unknown filter %q, must be %q or %q", config.Filter, AnalogFilterAverage, AnalogFilterMedian))
+	}
 	return nil
 }
 
@@ -71,3 +91,31 @@ func (config *DigitalInterruptConfig) Validate(path string) error {
 	}
 	return nil
 }
+
+// The supported ways of generating a PWM signal on a pin.
+const (
+	PWMTypeHardware = "hardware"
+	PWMTypeSoftware = "software"
+)
+
+// PWMConfig describes how PWM signals are generated on a single pin. Pins
+// without a PWMConfig use whatever the board considers its default.
+type PWMConfig struct {
+	Pin         string `json:"pin"`
+	Type        string `json:"type,omitempty"`         // "hardware" or "software"
+	FrequencyHz uint   `json:"frequency_hz,omitempty"` // initial frequency; can be changed at runtime with SetPWMFreq
+}
+
+// Validate ensures all parts of the config are valid.
+func (config *PWMConfig) Validate(path string) error {
+	if config.Pin == "" {
+		return utils.NewConfigValidationFieldRequiredError(path, "pin")
+	}
+	switch config.Type {
+	case "", PWMTypeHardware, PWMTypeSoftware:
+	default:
+		return utils.NewConfigValidationError(path,
+			errors.Errorf("unknown pwm type %q, must be %q or %q", config.Type, PWMTypeHardware, PWMTypeSoftware))
+	}
+	return nil
+}
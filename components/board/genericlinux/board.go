@@ -185,21 +185,21 @@ func (b *sysfsBoard) reconfigureAnalogs(ctx context.Context, newConf *Config) er
 
 		stillExists[c.Name] = struct{}{}
 		if curr, ok := b.analogs[c.Name]; ok {
-			if curr.chipSelect != c.ChipSelect {
+			if curr.cfg != c {
 				ar := &board.MCP3008AnalogReader{channel, bus, c.ChipSelect}
-				curr.reset(ctx, curr.chipSelect, board.SmoothAnalogReader(ar, c, b.logger))
+				curr.reset(ctx, c, board.SmoothAnalogReader(ar, c, b.logger))
 			}
 			continue
 		}
 		ar := &board.MCP3008AnalogReader{channel, bus, c.ChipSelect}
-		b.analogs[c.Name] = newWrappedAnalog(ctx, c.ChipSelect, board.SmoothAnalogReader(ar, c, b.logger))
+		b.analogs[c.Name] = newWrappedAnalog(ctx, c, board.SmoothAnalogReader(ar, c, b.logger))
 	}
 
 	for name := range b.analogs {
 		if _, ok := stillExists[name]; ok {
 			continue
 		}
-		b.analogs[name].reset(ctx, "", nil)
+		b.analogs[name].reset(ctx, board.AnalogConfig{}, nil)
 		delete(b.analogs, name)
 	}
 	return nil
@@ -221,6 +221,7 @@ func (b *sysfsBoard) reconfigureGpios(newConf *Config) error {
 		b.cancelCtx,
 		b.gpioMappings,
 		newConf.DigitalInterrupts,
+		newConf.PWMs,
 		b.logger)
 	if err != nil {
 		return err
@@ -231,14 +232,15 @@ func (b *sysfsBoard) reconfigureGpios(newConf *Config) error {
 }
 
 type wrappedAnalog struct {
-	mu         sync.RWMutex
-	chipSelect string
-	reader     *board.AnalogSmoother
+	mu        sync.RWMutex
+	cfg       board.AnalogConfig
+	reader    *board.AnalogSmoother
+	callbacks []chan board.AnalogSample
 }
 
-func newWrappedAnalog(ctx context.Context, chipSelect string, reader *board.AnalogSmoother) *wrappedAnalog {
+func newWrappedAnalog(ctx context.Context, cfg board.AnalogConfig, reader *board.AnalogSmoother) *wrappedAnalog {
 	var wrapped wrappedAnalog
-	wrapped.reset(ctx, chipSelect, reader)
+	wrapped.reset(ctx, cfg, reader)
 	return &wrapped
 }
 
@@ -255,14 +257,56 @@ func (a *wrappedAnalog) Close(ctx context.Context) error {
 	return nil
 }
 
-func (a *wrappedAnalog) reset(ctx context.Context, chipSelect string, reader *board.AnalogSmoother) {
+// AddCallback adds a listener for samples. Listeners survive the reader
+// being replaced on reconfiguration.
+func (a *wrappedAnalog) AddCallback(c chan board.AnalogSample) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.callbacks = append(a.callbacks, c)
+	if a.reader != nil {
+		a.reader.AddCallback(c)
+	}
+}
+
+// RemoveCallback removes a listener for samples.
+func (a *wrappedAnalog) RemoveCallback(c chan board.AnalogSample) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for id := range a.callbacks {
+		if a.callbacks[id] == c {
+			a.callbacks[id] = a.callbacks[len(a.callbacks)-1]
+			a.callbacks = a.callbacks[:len(a.callbacks)-1]
+			break
+		}
+	}
+	if a.reader != nil {
+		a.reader.RemoveCallback(c)
+	}
+}
+
+// SamplesSince returns the recent samples of the current reader.
+func (a *wrappedAnalog) SamplesSince(t time.Time) []board.AnalogSample {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.reader == nil {
+		return nil
+	}
+	return a.reader.SamplesSince(t)
+}
+
+func (a *wrappedAnalog) reset(ctx context.Context, cfg board.AnalogConfig, reader *board.AnalogSmoother) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	if a.reader != nil {
 		goutils.UncheckedError(a.reader.Close(ctx))
 	}
 	a.reader = reader
-	a.chipSelect = chipSelect
+	a.cfg = cfg
+	if a.reader != nil {
+		for _, c := range a.callbacks {
+			a.reader.AddCallback(c)
+		}
+	}
 }
 
 type sysfsBoard struct {
@@ -653,6 +697,14 @@ func (b *sysfsBoard) ModelAttributes() board.ModelAttributes {
 	return board.ModelAttributes{}
 }
 
+// DoCommand supports board.AnalogSamplesCommand, which returns the recent samples of an analog reader.
+func (b *sysfsBoard) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := board.DoAnalogSamplesCommand(b, cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
 func (b *sysfsBoard) SetPowerMode(ctx context.Context, mode pb.PowerMode, duration *time.Duration) error {
 	return grpc.UnimplementedError
 }
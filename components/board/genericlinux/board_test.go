@@ -223,4 +223,24 @@ func TestConfigValidate(t *testing.T) {
 	validConfig.DigitalInterrupts = []board.DigitalInterruptConfig{{Name: "bar", Pin: "3"}}
 	_, err = validConfig.Validate("path")
 	test.That(t, err, test.ShouldBeNil)
+
+	validConfig.PWMs = []board.PWMConfig{{}}
+	_, err = validConfig.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `path.pwms.0`)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `"pin" is required`)
+
+	validConfig.PWMs = []board.PWMConfig{{Pin: "32", Type: "magic"}}
+	_, err = validConfig.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `unknown pwm type`)
+
+	validConfig.PWMs = []board.PWMConfig{{Pin: "32", Type: board.PWMTypeHardware}, {Pin: "32"}}
+	_, err = validConfig.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `path.pwms.1`)
+
+	validConfig.PWMs = []board.PWMConfig{{Pin: "32", Type: board.PWMTypeHardware, FrequencyHz: 50}, {Pin: "33"}}
+	_, err = validConfig.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
 }
@@ -3,6 +3,9 @@ package genericlinux
 import (
 	"fmt"
 
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
 	"go.viam.com/rdk/components/board"
 	"go.viam.com/rdk/utils"
 )
@@ -13,6 +16,7 @@ type Config struct {
 	SPIs              []board.SPIConfig              `json:"spis,omitempty"`
 	Analogs           []board.AnalogConfig           `json:"analogs,omitempty"`
 	DigitalInterrupts []board.DigitalInterruptConfig `json:"digital_interrupts,omitempty"`
+	PWMs              []board.PWMConfig              `json:"pwms,omitempty"`
 	Attributes        utils.AttributeMap             `json:"attributes,omitempty"`
 }
 
@@ -38,5 +42,16 @@ func (conf *Config) Validate(path string) ([]string, error) {
 			return nil, err
 		}
 	}
+	seenPWMPins := map[string]struct{}{}
+	for idx, c := range conf.PWMs {
+		pwmPath := fmt.Sprintf("%s.%s.%d", path, "pwms", idx)
+		if err := c.Validate(pwmPath); err != nil {
+			return nil, err
+		}
+		if _, ok := seenPWMPins[c.Pin]; ok {
+			return nil, goutils.NewConfigValidationError(pwmPath, errors.Errorf("pin %s has more than one pwm config", c.Pin))
+		}
+		seenPWMPins[c.Pin] = struct{}{}
+	}
 	return nil, nil
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/edaniels/golog"
 	"github.com/mkch/gpio"
+	"github.com/pkg/errors"
 	"go.uber.org/multierr"
 	"go.viam.com/utils"
 
@@ -288,7 +289,7 @@ func (pin *gpioPin) Close() error {
 }
 
 func (b *sysfsBoard) gpioInitialize(cancelCtx context.Context, gpioMappings map[int]GPIOBoardMapping,
-	interruptConfigs []board.DigitalInterruptConfig, logger golog.Logger,
+	interruptConfigs []board.DigitalInterruptConfig, pwmConfigs []board.PWMConfig, logger golog.Logger,
 ) (map[string]*gpioPin, map[string]*digitalInterrupt, error) {
 	interrupts := make(map[string]*digitalInterrupt, len(interruptConfigs))
 	for _, config := range interruptConfigs {
@@ -323,5 +324,21 @@ func (b *sysfsBoard) gpioInitialize(cancelCtx context.Context, gpioMappings map[
 		}
 		pins[fmt.Sprintf("%d", pinNumber)] = pin
 	}
+
+	for _, config := range pwmConfigs {
+		pin, ok := pins[config.Pin]
+		if !ok {
+			return nil, nil, errors.Errorf("cannot configure pwm on unknown pin %s", config.Pin)
+		}
+		switch config.Type {
+		case board.PWMTypeHardware:
+			if pin.hwPwm == nil {
+				return nil, nil, errors.Errorf("pin %s does not support hardware pwm", config.Pin)
+			}
+		case board.PWMTypeSoftware:
+			pin.hwPwm = nil
+		}
+		pin.pwmFreqHz = config.FrequencyHz
+	}
 	return pins, interrupts, nil
 }
@@ -6,7 +6,9 @@ package piimpl
 /*
 	This driver contains various functionalities of raspberry pi board using the
 	pigpio library (https://abyz.me.uk/rpi/pigpio/pdif2.html).
-	NOTE: This driver only supports software PWM functionality of raspberry pi.
+	NOTE: This driver uses software PWM unless a pin is configured with a "hardware"
+		  pwm type, in which case the pin's hardware PWM channel is used instead
+		  (broadcom 12 and 18 share channel 0, broadcom 13 and 19 share channel 1).
 		  For software PWM, we currently support the default sample rate of
 		  5 microseconds, which supports the following 18 frequencies (Hz):
 		  8000  4000  2000 1600 1000  800  500  400  320
@@ -80,6 +82,7 @@ type piPigpio struct {
 	spis            map[string]board.SPI
 	interrupts      map[string]board.DigitalInterrupt
 	interruptsHW    map[uint]board.DigitalInterrupt
+	hwPWMs          map[int]*hwPWMSetting
 	logger          golog.Logger
 	isClosed        bool
 }
@@ -182,6 +185,37 @@ func NewPigpio(ctx context.Context, name resource.Name, cfg *genericlinux.Config
 		piInstance.analogs[ac.Name] = board.SmoothAnalogReader(ar, ac, logger)
 	}
 
+	// setup pwms
+	piInstance.hwPWMs = map[int]*hwPWMSetting{}
+	hwPWMChannelsUsed := map[int]string{}
+	for _, pc := range cfg.PWMs {
+		bcom, have := broadcomPinFromHardwareLabel(pc.Pin)
+		if !have {
+			return nil, errors.Errorf("no hw mapping for %s", pc.Pin)
+		}
+		if pc.Type != board.PWMTypeHardware {
+			if pc.FrequencyHz != 0 {
+				if err := piInstance.SetPWMFreqBcom(int(bcom), pc.FrequencyHz); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		channel, ok := hardwarePWMChannel(bcom)
+		if !ok {
+			return nil, errors.Errorf("pin %s does not support hardware pwm", pc.Pin)
+		}
+		if other, used := hwPWMChannelsUsed[channel]; used {
+			return nil, errors.Errorf("pins %s and %s share hardware pwm channel %d", other, pc.Pin, channel)
+		}
+		hwPWMChannelsUsed[channel] = pc.Pin
+		freqHz := pc.FrequencyHz
+		if freqHz == 0 {
+			freqHz = defaultPWMFreqHz
+		}
+		piInstance.hwPWMs[int(bcom)] = &hwPWMSetting{freqHz: freqHz}
+	}
+
 	// setup interrupts
 	piInstance.interrupts = map[string]board.DigitalInterrupt{}
 	piInstance.interruptsHW = map[uint]board.DigitalInterrupt{}
@@ -296,16 +330,60 @@ func (pi *piPigpio) SetGPIOBcom(bcom int, high bool) error {
 	return nil
 }
 
+// defaultPWMFreqHz is the original default frequency from libpigpio.
+const defaultPWMFreqHz = 800
+
+// hwPWMSetting is the last requested state of a pin driven by a hardware PWM
+// channel. pigpio sets frequency and duty cycle together for hardware PWM, so
+// we have to remember both.
+type hwPWMSetting struct {
+	freqHz       uint
+	dutyCyclePct float64
+}
+
+// hardwarePWMChannel returns the hardware PWM channel of the given broadcom
+// pin, if it has one that is exposed on the header.
+func hardwarePWMChannel(bcom uint) (int, bool) {
+	switch bcom {
+	case 12, 18:
+		return 0, true
+	case 13, 19:
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+// applyHardwarePWM must be called with the mutex held.
+func (pi *piPigpio) applyHardwarePWM(bcom int, setting *hwPWMSetting) error {
+	dutyCycle := rdkutils.ScaleByPct(1e6, setting.dutyCyclePct)
+	res := C.gpioHardwarePWM(C.uint(bcom), C.uint(setting.freqHz), C.uint(dutyCycle))
+	if res != 0 {
+		return picommon.ConvertErrorCodeToMessage(int(res), "hardware pwm set failed")
+	}
+	return nil
+}
+
 func (pi *piPigpio) pwmBcom(bcom int) (float64, error) {
+	pi.mu.Lock()
+	if setting, isHW := pi.hwPWMs[bcom]; isHW {
+		defer pi.mu.Unlock()
+		return setting.dutyCyclePct, nil
+	}
+	pi.mu.Unlock()
 	res := C.gpioGetPWMdutycycle(C.uint(bcom))
 	return float64(res) / 255, nil
 }
 
 // SetPWMBcom sets the given broadcom pin to the given PWM duty cycle.
 func (pi *piPigpio) SetPWMBcom(bcom int, dutyCyclePct float64) error {
-	dutyCycle := rdkutils.ScaleByPct(255, dutyCyclePct)
 	pi.mu.Lock()
 	defer pi.mu.Unlock()
+	if setting, isHW := pi.hwPWMs[bcom]; isHW {
+		setting.dutyCyclePct = dutyCyclePct
+		return pi.applyHardwarePWM(bcom, setting)
+	}
+	dutyCycle := rdkutils.ScaleByPct(255, dutyCyclePct)
 	pi.duty = int(C.gpioPWM(C.uint(bcom), C.uint(dutyCycle)))
 	if pi.duty != 0 {
 		return errors.Errorf("pwm set fail %d", pi.duty)
@@ -314,6 +392,12 @@ func (pi *piPigpio) SetPWMBcom(bcom int, dutyCyclePct float64) error {
 }
 
 func (pi *piPigpio) pwmFreqBcom(bcom int) (uint, error) {
+	pi.mu.Lock()
+	if setting, isHW := pi.hwPWMs[bcom]; isHW {
+		defer pi.mu.Unlock()
+		return setting.freqHz, nil
+	}
+	pi.mu.Unlock()
 	res := C.gpioGetPWMfrequency(C.uint(bcom))
 	return uint(res), nil
 }
@@ -321,8 +405,21 @@ func (pi *piPigpio) pwmFreqBcom(bcom int) (uint, error) {
 // SetPWMFreqBcom sets the given broadcom pin to the given PWM frequency.
 func (pi *piPigpio) SetPWMFreqBcom(bcom int, freqHz uint) error {
 	if freqHz == 0 {
-		freqHz = 800 // Original default from libpigpio
+		freqHz = defaultPWMFreqHz
 	}
+
+	pi.mu.Lock()
+	if setting, isHW := pi.hwPWMs[bcom]; isHW {
+		defer pi.mu.Unlock()
+		setting.freqHz = freqHz
+		if setting.dutyCyclePct == 0 {
+			// nothing is being output yet; the frequency is applied with the next duty cycle.
+			return nil
+		}
+		return pi.applyHardwarePWM(bcom, setting)
+	}
+	pi.mu.Unlock()
+
 	newRes := C.gpioSetPWMfrequency(C.uint(bcom), C.uint(freqHz))
 
 	if newRes == C.PI_BAD_USER_GPIO {
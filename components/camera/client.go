@@ -35,6 +35,8 @@ type client struct {
 	activeBackgroundWorkers sync.WaitGroup
 	cancelCtx               context.Context
 	cancel                  func()
+	// noPointCloudChunks is set once the server is found to not send point clouds in chunks.
+	noPointCloudChunks bool
 }
 
 // NewClientFromConn constructs a new Client from connection passed in.
@@ -135,6 +137,29 @@ func (c *client) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, err
 	ctx, span := trace.StartSpan(ctx, "camera::client::NextPointCloud")
 	defer span.End()
 
+	c.mu.Lock()
+	chunked := !c.noPointCloudChunks
+	c.mu.Unlock()
+	if !chunked {
+		return c.nextPointCloudInOneMessage(ctx)
+	}
+	pc, err := c.NextPointCloudChunks(ctx, false, nil)
+	if err == nil {
+		return pc, nil
+	}
+	// servers from before point clouds were sent in chunks can only send them in one message. If the server can,
+	// it is not asked for chunks again.
+	pc, err = c.nextPointCloudInOneMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.noPointCloudChunks = true
+	c.mu.Unlock()
+	return pc, nil
+}
+
+func (c *client) nextPointCloudInOneMessage(ctx context.Context) (pointcloud.PointCloud, error) {
 	ctx, getPcdSpan := trace.StartSpan(ctx, "camera::client::NextPointCloud::GetPointCloud")
 	resp, err := c.client.GetPointCloud(ctx, &pb.GetPointCloudRequest{
 		Name:     c.name,
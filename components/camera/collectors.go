@@ -3,6 +3,10 @@ package camera
 import (
 	"bytes"
 	"context"
+	"image"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
@@ -77,6 +81,23 @@ func newReadImageCollector(resource interface{}, params data.CollectorParams) (d
 		}
 	}
 
+	// If similar_image_threshold is set, images whose hashes differ from the last stored image's by at most that many
+	// bits are not stored, which saves a lot of space for cameras that look at a mostly still scene.
+	similarThreshold := -1
+	if threshold := params.MethodParams["similar_image_threshold"]; threshold != nil {
+		thresholdStr := new(wrapperspb.StringValue)
+		if err := threshold.UnmarshalTo(thresholdStr); err != nil {
+			return nil, err
+		}
+		similarThreshold, err = strconv.Atoi(thresholdStr.Value)
+		if err != nil || similarThreshold < 0 || similarThreshold > 64 {
+			return nil, errors.Errorf("similar_image_threshold must be a number of bits from 0 to 64, got %q", thresholdStr.Value)
+		}
+	}
+	var lastHashMu sync.Mutex
+	var lastHash uint64
+	hasLastHash := false
+
 	cFunc := data.CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (interface{}, error) {
 		_, span := trace.StartSpan(ctx, "camera::data::collector::CaptureFunc::ReadImage")
 		defer span.End()
@@ -91,20 +112,52 @@ func newReadImageCollector(resource interface{}, params data.CollectorParams) (d
 			}
 		}()
 
+		var hash uint64
+		if similarThreshold >= 0 {
+			hash = rimage.DifferenceHash(img)
+			lastHashMu.Lock()
+			similar := hasLastHash && rimage.HashDistance(hash, lastHash) <= similarThreshold
+			lastHashMu.Unlock()
+			if similar {
+				return nil, data.ErrNoCaptureToStore
+			}
+		}
+
 		mimeStr := new(wrapperspb.StringValue)
 		if err := mimeType.UnmarshalTo(mimeStr); err != nil {
 			return nil, err
 		}
 
-		outBytes, err := rimage.EncodeImage(ctx, img, mimeStr.Value)
+		outBytes, err := rimage.EncodeImage(ctx, withCaptureMetadata(img, params.ComponentName), mimeStr.Value)
 		if err != nil {
 			return nil, err
 		}
+		if similarThreshold >= 0 {
+			lastHashMu.Lock()
+			lastHash, hasLastHash = hash, true
+			lastHashMu.Unlock()
+		}
 		return outBytes, nil
 	})
 	return data.NewCollector(cFunc, params)
 }
 
+// withCaptureMetadata returns the image with metadata recording when and by which camera it was captured, for the
+// formats that can hold it, keeping any metadata it already has.
+func withCaptureMetadata(img image.Image, componentName string) image.Image {
+	md := rimage.MetadataOf(img)
+	if md.Time.IsZero() {
+		md.Time = time.Now()
+	}
+	extra := make(map[string]string, len(md.Extra)+1)
+	for k, v := range md.Extra {
+		extra[k] = v
+	}
+	extra["component_name"] = componentName
+	md.Extra = extra
+	return rimage.WithMetadata(img, md)
+}
+
 func assertCamera(resource interface{}) (Camera, error) {
 	cam, ok := resource.(Camera)
 	if !ok {
@@ -0,0 +1,231 @@
+package camera
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/pointcloud"
+)
+
+// GetPointCloudChunkCommand is the DoCommand key camera clients use to get point clouds in chunks until the camera API
+// has a streaming RPC for them, as large clouds do not fit in one message. Its argument starts a transfer of the next
+// point cloud of the camera when it has no "transfer_id", and otherwise gets the chunk at "index" of that transfer.
+// Each chunk is a binary PCD of at most "chunk_bytes", and with "coarse_first" the points are ordered by
+// pointcloud.CoarseToFine so that the first chunks are a preview of the whole cloud.
+const GetPointCloudChunkCommand = "get_point_cloud_chunk"
+
+const (
+	// pointCloudChunkBytes is the size of the chunks camera clients ask for, which is well under the maximum size of
+	// a message even once base64 encoded. Clouds that fit in one chunk are got in a single request.
+	pointCloudChunkBytes = 4 << 20
+	// pcdPointBytes is the size of a point with color in a binary PCD.
+	pcdPointBytes = 4 * 4
+	// pointCloudTransferTimeout is how long a transfer is kept without any of its chunks being asked for.
+	pointCloudTransferTimeout = time.Minute
+)
+
+// maxPointCloudMessagePoints is the most points sent in a GetPointCloud response, which leaves room in the message
+// for the PCD header and the rest of the response.
+var maxPointCloudMessagePoints = (rpc.MaxMessageSize - 1<<10) / pcdPointBytes
+
+// pointCloudForMessage returns the point cloud to send in a GetPointCloud response. Clouds too large for one message,
+// which clients that cannot get them in chunks, such as the web UI, would fail to get, are sent at the finest level
+// of detail that fits.
+func pointCloudForMessage(pc pointcloud.PointCloud) (pointcloud.PointCloud, error) {
+	if pc.Size() <= maxPointCloudMessagePoints {
+		return pc, nil
+	}
+	octree, ok := pc.(*pointcloud.LODOctree)
+	if !ok {
+		var err error
+		if octree, err = pointcloud.NewLODOctreeFromPointCloud(pc, 0); err != nil {
+			return nil, err
+		}
+	}
+	return octree.LevelOfDetail(maxPointCloudMessagePoints)
+}
+
+// ChunkedPointCloudSource is a camera that can get point clouds in chunks, such as a camera client.
+type ChunkedPointCloudSource interface {
+	// NextPointCloudChunks gets the next point cloud in chunks, calling onChunk, if given, with the cloud received so
+	// far after each of them. With coarseFirst, every chunk adds detail evenly over the whole cloud.
+	NextPointCloudChunks(
+		ctx context.Context,
+		coarseFirst bool,
+		onChunk func(soFar pointcloud.PointCloud, chunk, totalChunks int),
+	) (pointcloud.PointCloud, error)
+}
+
+// pointCloudTransfer is a point cloud being sent in chunks.
+type pointCloudTransfer struct {
+	points         []pointcloud.PointAndData
+	pointsPerChunk int
+	lastUsed       time.Time
+}
+
+// totalChunks returns the number of chunks of the transfer, which is sent as one empty chunk if its cloud is empty.
+func (t *pointCloudTransfer) totalChunks() int {
+	if len(t.points) == 0 {
+		return 1
+	}
+	return (len(t.points) + t.pointsPerChunk - 1) / t.pointsPerChunk
+}
+
+// pointCloudTransfers holds the point clouds a camera server is sending in chunks.
+type pointCloudTransfers struct {
+	mu        sync.Mutex
+	transfers map[string]*pointCloudTransfer
+}
+
+// getChunk handles a GetPointCloudChunkCommand, starting a transfer with the next point cloud of the camera if asked.
+func (pt *pointCloudTransfers) getChunk(ctx context.Context, cam Camera, args map[string]interface{}) (map[string]interface{}, error) {
+	id, _ := args["transfer_id"].(string)
+	index, _ := args["index"].(float64)
+	if id == "" {
+		chunkBytes, _ := args["chunk_bytes"].(float64)
+		coarseFirst, _ := args["coarse_first"].(bool)
+		var err error
+		if id, err = pt.start(ctx, cam, int(chunkBytes), coarseFirst); err != nil {
+			return nil, err
+		}
+	}
+
+	pt.mu.Lock()
+	transfer, ok := pt.transfers[id]
+	if ok {
+		transfer.lastUsed = time.Now()
+		if int(index) == transfer.totalChunks()-1 {
+			delete(pt.transfers, id)
+		}
+	}
+	pt.mu.Unlock()
+	if !ok {
+		return nil, errors.Errorf("no point cloud transfer %q, it may have timed out", id)
+	}
+	if index < 0 || int(index) >= transfer.totalChunks() {
+		return nil, errors.Errorf("point cloud transfer %q has no chunk %v", id, index)
+	}
+
+	start := int(index) * transfer.pointsPerChunk
+	end := start + transfer.pointsPerChunk
+	if end > len(transfer.points) {
+		end = len(transfer.points)
+	}
+	chunk := pointcloud.NewWithPrealloc(end - start)
+	for _, pd := range transfer.points[start:end] {
+		if err := chunk.Set(pd.P, pd.D); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	buf.Grow(200 + chunk.Size()*pcdPointBytes)
+	if err := pointcloud.ToPCD(chunk, &buf, pointcloud.PCDBinary); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"transfer_id":  id,
+		"index":        int(index),
+		"total_chunks": transfer.totalChunks(),
+		"total_points": len(transfer.points),
+		"pcd":          base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// start gets the next point cloud of the camera to send in chunks, and drops any transfers that have timed out.
+func (pt *pointCloudTransfers) start(ctx context.Context, cam Camera, chunkBytes int, coarseFirst bool) (string, error) {
+	pc, err := cam.NextPointCloud(ctx)
+	if err != nil {
+		return "", err
+	}
+	var points []pointcloud.PointAndData
+	if coarseFirst {
+		points = pointcloud.CoarseToFine(pc)
+	} else {
+		points = make([]pointcloud.PointAndData, 0, pc.Size())
+		pc.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+			points = append(points, pointcloud.PointAndData{P: p, D: d})
+			return true
+		})
+	}
+	transfer := &pointCloudTransfer{points: points, pointsPerChunk: chunkBytes / pcdPointBytes, lastUsed: time.Now()}
+	if transfer.pointsPerChunk < 1 {
+		transfer.pointsPerChunk = 1
+	}
+
+	id := uuid.NewString()
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if pt.transfers == nil {
+		pt.transfers = map[string]*pointCloudTransfer{}
+	}
+	for otherID, other := range pt.transfers {
+		if time.Since(other.lastUsed) > pointCloudTransferTimeout {
+			delete(pt.transfers, otherID)
+		}
+	}
+	pt.transfers[id] = transfer
+	return id, nil
+}
+
+// errPointCloudChunksUnsupported is returned when a camera server does not know GetPointCloudChunkCommand.
+var errPointCloudChunksUnsupported = errors.New("camera server cannot send point clouds in chunks")
+
+// NextPointCloudChunks gets the next point cloud of the camera in chunks with GetPointCloudChunkCommand.
+func (c *client) NextPointCloudChunks(
+	ctx context.Context,
+	coarseFirst bool,
+	onChunk func(soFar pointcloud.PointCloud, chunk, totalChunks int),
+) (pointcloud.PointCloud, error) {
+	ctx, span := trace.StartSpan(ctx, "camera::client::NextPointCloudChunks")
+	defer span.End()
+
+	var cloud pointcloud.PointCloud
+	args := map[string]interface{}{"chunk_bytes": pointCloudChunkBytes, "coarse_first": coarseFirst}
+	for index, total := 0, 1; index < total; index++ {
+		resp, err := c.DoCommand(ctx, map[string]interface{}{GetPointCloudChunkCommand: args})
+		if err != nil {
+			return nil, err
+		}
+		id, _ := resp["transfer_id"].(string)
+		totalChunks, ok := resp["total_chunks"].(float64)
+		encoded, _ := resp["pcd"].(string)
+		if id == "" || !ok {
+			return nil, errPointCloudChunksUnsupported
+		}
+		total = int(totalChunks)
+		if cloud == nil {
+			totalPoints, _ := resp["total_points"].(float64)
+			cloud = pointcloud.NewWithPrealloc(int(totalPoints))
+		}
+
+		pcd, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+		chunk, err := pointcloud.ReadPCD(bytes.NewReader(pcd))
+		if err != nil {
+			return nil, err
+		}
+		chunk.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+			err = cloud.Set(p, d)
+			return err == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if onChunk != nil {
+			onChunk(cloud, index, total)
+		}
+		args = map[string]interface{}{"transfer_id": id, "index": index + 1}
+	}
+	return cloud, nil
+}
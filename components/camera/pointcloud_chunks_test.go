@@ -0,0 +1,86 @@
+package camera
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image/color"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestGetPointCloudChunks(t *testing.T) {
+	colorOf := func(i int) color.NRGBA {
+		return color.NRGBA{uint8(i), 0, uint8(255 - i), 255}
+	}
+	pc := pointcloud.New()
+	for i := 0; i < 100; i++ {
+		test.That(t, pc.Set(pointcloud.NewVector(float64(i), float64(i%10), 0), pointcloud.NewColoredData(colorOf(i))), test.ShouldBeNil)
+	}
+	injectCamera := &inject.Camera{}
+	injectCamera.NextPointCloudFunc = func(ctx context.Context) (pointcloud.PointCloud, error) {
+		return pc, nil
+	}
+
+	for _, coarseFirst := range []bool{false, true} {
+		var transfers pointCloudTransfers
+		// arguments are given as they are decoded from a DoCommand request
+		args := map[string]interface{}{"chunk_bytes": float64(30 * pcdPointBytes), "coarse_first": coarseFirst}
+		got := pointcloud.New()
+		for index := 0; ; index++ {
+			resp, err := transfers.getChunk(context.Background(), injectCamera, args)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, resp["index"], test.ShouldEqual, index)
+			test.That(t, resp["total_chunks"], test.ShouldEqual, 4)
+			test.That(t, resp["total_points"], test.ShouldEqual, 100)
+
+			pcd, err := base64.StdEncoding.DecodeString(resp["pcd"].(string))
+			test.That(t, err, test.ShouldBeNil)
+			chunk, err := pointcloud.ReadPCD(bytes.NewReader(pcd))
+			test.That(t, err, test.ShouldBeNil)
+			if index < 3 {
+				test.That(t, chunk.Size(), test.ShouldEqual, 30)
+			}
+			chunk.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+				err = got.Set(p, d)
+				return err == nil
+			})
+			test.That(t, err, test.ShouldBeNil)
+			if index == 3 {
+				break
+			}
+			args = map[string]interface{}{"transfer_id": resp["transfer_id"], "index": float64(index + 1)}
+		}
+		test.That(t, got.Size(), test.ShouldEqual, 100)
+		d, got0 := got.At(42, 2, 0)
+		test.That(t, got0, test.ShouldBeTrue)
+		test.That(t, d.Color(), test.ShouldResemble, colorOf(42))
+
+		// the transfer is dropped once its last chunk is sent
+		_, err := transfers.getChunk(context.Background(), injectCamera, args)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, transfers.transfers, test.ShouldBeEmpty)
+	}
+}
+
+func TestPointCloudForMessage(t *testing.T) {
+	pc := pointcloud.New()
+	for i := 0; i < 1000; i++ {
+		test.That(t, pc.Set(pointcloud.NewVector(float64(i%10), float64(i/10%10), float64(i/100)), nil), test.ShouldBeNil)
+	}
+	sent, err := pointCloudForMessage(pc)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sent, test.ShouldEqual, pc)
+
+	// clouds too large for a message are sent at a level of detail that fits
+	defer func(max int) { maxPointCloudMessagePoints = max }(maxPointCloudMessagePoints)
+	maxPointCloudMessagePoints = 200
+	sent, err = pointCloudForMessage(pc)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sent.Size(), test.ShouldBeBetweenOrEqual, 1, 200)
+}
@@ -3,6 +3,7 @@ package camera
 import (
 	"bytes"
 	"context"
+	"errors"
 
 	"github.com/edaniels/golog"
 	"github.com/edaniels/gostream"
@@ -10,6 +11,7 @@ import (
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/camera/v1"
 	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/pointcloud"
 	"go.viam.com/rdk/protoutils"
@@ -21,9 +23,10 @@ import (
 // serviceServer implements the CameraService from camera.proto.
 type serviceServer struct {
 	pb.UnimplementedCameraServiceServer
-	coll     resource.APIResourceCollection[Camera]
-	imgTypes map[string]ImageType
-	logger   golog.Logger
+	coll        resource.APIResourceCollection[Camera]
+	imgTypes    map[string]ImageType
+	logger      golog.Logger
+	pointClouds pointCloudTransfers
 }
 
 // NewRPCServiceServer constructs an camera gRPC service server.
@@ -83,6 +86,12 @@ func (s *serviceServer) GetImage(
 		MimeType: actualMIME,
 	}
 	outBytes, err := rimage.EncodeImage(ctx, img, req.MimeType)
+	if errors.Is(err, rimage.ErrEncodingUnsupported) {
+		// clients already handle getting a different MIME type than the one they asked for
+		s.logger.Debugw("cannot encode to the requested MIME type, sending a JPEG instead", "requested", actualMIME)
+		resp.MimeType = utils.MimeTypeJPEG
+		outBytes, err = rimage.EncodeImage(ctx, img, utils.MimeTypeJPEG)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -129,6 +138,13 @@ func (s *serviceServer) GetPointCloud(
 	if err != nil {
 		return nil, err
 	}
+	size := pc.Size()
+	if pc, err = pointCloudForMessage(pc); err != nil {
+		return nil, err
+	}
+	if pc.Size() != size {
+		s.logger.Debugw("point cloud too large for one message, sending a level of detail of it", "points", size, "sent", pc.Size())
+	}
 
 	var buf bytes.Buffer
 	buf.Grow(200 + (pc.Size() * 4 * 4)) // 4 numbers per point, each 4 bytes
@@ -187,5 +203,16 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if args, ok := req.Command.AsMap()[GetPointCloudChunkCommand].(map[string]interface{}); ok {
+		resp, err := s.pointClouds.getChunk(ctx, camera, args)
+		if err != nil {
+			return nil, err
+		}
+		result, err := structpb.NewStruct(resp)
+		if err != nil {
+			return nil, err
+		}
+		return &commonpb.DoCommandResponse{Result: result}, nil
+	}
 	return protoutils.DoFromResourceServer(ctx, camera, req)
 }
@@ -16,11 +16,41 @@ import (
 	"go.viam.com/rdk/utils"
 )
 
-// depthToPretty takes a depth image and turns into a colorful image, with blue being
+// depthToPrettyConfig are the attributes for a depth_to_pretty transform.
+type depthToPrettyConfig struct {
+	Colormap     string `json:"colormap,omitempty"`
+	MinDepthMm   int    `json:"min_depth_mm,omitempty"`
+	MaxDepthMm   int    `json:"max_depth_mm,omitempty"`
+	InvalidColor string `json:"invalid_color,omitempty"`
+}
+
+// colorOptions turns the attributes into options for coloring depth maps. Depths that are not
+// given are scaled to those of each frame, and pixels without depth are black by default.
+func (cfg *depthToPrettyConfig) colorOptions() (rimage.DepthColorOptions, error) {
+	if cfg.MinDepthMm < 0 || cfg.MaxDepthMm < 0 || cfg.MaxDepthMm > int(rimage.MaxDepth) {
+		return rimage.DepthColorOptions{}, errors.Errorf("min_depth_mm and max_depth_mm must be between 0 and %d", rimage.MaxDepth)
+	}
+	opts := rimage.DepthColorOptions{
+		Colormap: rimage.DepthColormap(cfg.Colormap),
+		MinDepth: rimage.Depth(cfg.MinDepthMm),
+		MaxDepth: rimage.Depth(cfg.MaxDepthMm),
+	}
+	if cfg.InvalidColor != "" {
+		c, err := rimage.NewColorFromHex(cfg.InvalidColor)
+		if err != nil {
+			return rimage.DepthColorOptions{}, errors.Wrap(err, "invalid_color must be a hex color like #000000")
+		}
+		opts.InvalidColor = c
+	}
+	return opts, opts.Validate()
+}
+
+// depthToPretty takes a depth image and turns into a colorful image, by default with blue being
 // farther away, and red being closest. Actual depth information is lost in the transform.
 type depthToPretty struct {
 	originalStream gostream.VideoStream
 	cameraModel    *transform.PinholeCameraModel
+	colorOpts      rimage.DepthColorOptions
 }
 
 func propsFromVideoSource(ctx context.Context, source gostream.VideoSource) (camera.Properties, error) {
@@ -40,11 +70,20 @@ func newDepthToPrettyTransform(
 	ctx context.Context,
 	source gostream.VideoSource,
 	stream camera.ImageType,
+	am utils.AttributeMap,
 ) (gostream.VideoSource, camera.ImageType, error) {
 	if stream != camera.DepthStream {
 		return nil, camera.UnspecifiedStream,
 			errors.Errorf("source has stream type %s, depth_to_pretty only supports depth stream inputs", stream)
 	}
+	conf, err := resource.TransformAttributeMap[*depthToPrettyConfig](am)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	colorOpts, err := conf.colorOptions()
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
 	props, err := propsFromVideoSource(ctx, source)
 	if err != nil {
 		return nil, camera.UnspecifiedStream, err
@@ -59,6 +98,7 @@ func newDepthToPrettyTransform(
 	reader := &depthToPretty{
 		originalStream: depthStream,
 		cameraModel:    &cameraModel,
+		colorOpts:      colorOpts,
 	}
 	src, err := camera.NewVideoSourceFromReader(ctx, reader, &cameraModel, camera.ColorStream)
 	if err != nil {
@@ -78,7 +118,11 @@ func (dtp *depthToPretty) Read(ctx context.Context) (image.Image, func(), error)
 	if err != nil {
 		return nil, nil, errors.Wrapf(err, "source camera does not make depth maps")
 	}
-	return dm.ToPrettyPicture(0, rimage.MaxDepth), release, nil
+	img, err := dm.ToColormapPicture(dtp.colorOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return img, release, nil
 }
 
 func (dtp *depthToPretty) Close(ctx context.Context) error {
@@ -100,7 +144,10 @@ func (dtp *depthToPretty) PointCloud(ctx context.Context) (pointcloud.PointCloud
 	if err != nil {
 		return nil, errors.Wrapf(err, "source camera does not make depth maps")
 	}
-	img := dm.ToPrettyPicture(0, rimage.MaxDepth)
+	img, err := dm.ToColormapPicture(dtp.colorOpts)
+	if err != nil {
+		return nil, err
+	}
 	return dtp.cameraModel.RGBDToPointCloud(img, dm)
 }
 
@@ -101,3 +101,24 @@ func TestComposed(t *testing.T) {
 	test.That(t, err, test.ShouldNotBeNil)
 	test.That(t, err, test.ShouldWrap, transform.ErrNoIntrinsics)
 }
+
+func TestDepthToPrettyColorOptions(t *testing.T) {
+	conf := &depthToPrettyConfig{Colormap: "turbo", MaxDepthMm: 4000, InvalidColor: "#ff00ff"}
+	opts, err := conf.colorOptions()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opts, test.ShouldResemble, rimage.DepthColorOptions{
+		Colormap:     rimage.DepthColormapTurbo,
+		MaxDepth:     4000,
+		InvalidColor: rimage.NewColor(255, 0, 255),
+	})
+
+	for _, bad := range []*depthToPrettyConfig{
+		{Colormap: "rainbow"},
+		{InvalidColor: "magenta"},
+		{MinDepthMm: 3000, MaxDepthMm: 2000},
+		{MinDepthMm: -1},
+	} {
+		_, err := bad.colorOptions()
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
@@ -59,8 +59,10 @@ var registeredTransformConfigs = map[transformType]*transformRegistration{
 	},
 	transformTypeDepthPretty: {
 		string(transformTypeDepthPretty),
-		&emptyConfig{},
-		"Turns a depth image source into a colorful image, with blue indicating distant points and red indicating nearby points.",
+		&depthToPrettyConfig{},
+		"Turns a depth image source into a colorful image, by default with blue indicating distant points and red indicating nearby points. " +
+			"The colormap can be hue, grayscale, jet, turbo or viridis, and runs from min_depth_mm to max_depth_mm, " +
+			"which are taken from each image when not given.",
 	},
 	transformTypeOverlay: {
 		string(transformTypeOverlay),
@@ -131,7 +133,7 @@ func buildTransform(
 	case transformTypeResize:
 		return newResizeTransform(ctx, source, stream, tr.Attributes)
 	case transformTypeDepthPretty:
-		return newDepthToPrettyTransform(ctx, source, stream)
+		return newDepthToPrettyTransform(ctx, source, stream, tr.Attributes)
 	case transformTypeOverlay:
 		return newOverlayTransform(ctx, source, stream, tr.Attributes)
 	case transformTypeUndistort:
@@ -114,6 +114,12 @@ func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map
 	return rprotoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
 }
 
+// Home homes the remote gantry with HomeCommand.
+func (c *client) Home(ctx context.Context) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{HomeCommand: true})
+	return err
+}
+
 func (c *client) IsMoving(ctx context.Context) (bool, error) {
 	resp, err := c.client.IsMoving(ctx, &pb.IsMovingRequest{Name: c.name})
 	if err != nil {
@@ -107,6 +107,11 @@ func TestClient(t *testing.T) {
 		test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
 		test.That(t, resp["data"], test.ShouldEqual, testutils.TestCommand["data"])
 
+		// Home is sent as a DoCommand
+		homer, ok := gantry1Client.(gantry.Homer)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, homer.Home(context.Background()), test.ShouldBeNil)
+
 		pos, err := gantry1Client.Position(context.Background(), map[string]interface{}{"foo": 123, "bar": "234"})
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, pos, test.ShouldResemble, pos1)
@@ -58,6 +58,22 @@ type Gantry interface {
 	Lengths(ctx context.Context, extra map[string]interface{}) ([]float64, error)
 }
 
+// SpeedExtraKey is the key in the extra map of MoveToPosition that gantry
+// models may honor to move at the given speed (in mm/sec) instead of their
+// configured default. It is how a multi-axis gantry synchronizes its axes.
+const SpeedExtraKey = "speed_mm_per_sec"
+
+// A Homer is a gantry that can re-establish where its axes are, e.g. by driving
+// into its limit switches. Gantry clients are Homers that home the remote gantry
+// with HomeCommand.
+type Homer interface {
+	Home(ctx context.Context) error
+}
+
+// HomeCommand is the DoCommand, {"home": true}, that homes gantries which are
+// Homers. It is how homing reaches a gantry over the API, which has no call for it.
+const HomeCommand = "home"
+
 // FromDependencies is a helper for getting the named gantry from a collection of
 // dependencies.
 func FromDependencies(deps resource.Dependencies, name string) (Gantry, error) {
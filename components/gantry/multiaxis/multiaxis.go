@@ -3,10 +3,12 @@ package multiaxis
 
 import (
 	"context"
+	"math"
 	"sync"
 
 	"github.com/edaniels/golog"
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	"go.viam.com/utils"
 
 	"go.viam.com/rdk/components/gantry"
@@ -20,6 +22,12 @@ var model = resource.DefaultModelFamily.WithModel("multiaxis")
 // Config is used for converting multiAxis config attributes.
 type Config struct {
 	SubAxes []string `json:"subaxes_list"`
+
+	// If a max speed is set, all subaxes move at the same time along synchronized
+	// trapezoidal profiles so that they arrive together. Otherwise subaxes are
+	// moved one after another.
+	MaxSpeedMmPerSec    float64 `json:"max_speed_mm_per_sec,omitempty"`
+	AccelMmPerSecPerSec float64 `json:"acceleration_mm_per_sec_per_sec,omitempty"`
 }
 
 type multiAxis struct {
@@ -27,6 +35,8 @@ type multiAxis struct {
 	resource.AlwaysRebuild
 	subAxes   []gantry.Gantry
 	lengthsMm []float64
+	maxSpeed  float64
+	accel     float64
 	logger    golog.Logger
 	model     referenceframe.Model
 	opMgr     operation.SingleOperationManager
@@ -41,6 +51,16 @@ func (conf *Config) Validate(path string) ([]string, error) {
 		return nil, utils.NewConfigValidationError(path, errors.New("need at least one axis"))
 	}
 
+	if conf.MaxSpeedMmPerSec < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("max_speed_mm_per_sec cannot be negative"))
+	}
+	if conf.AccelMmPerSecPerSec < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("acceleration_mm_per_sec_per_sec cannot be negative"))
+	}
+	if conf.AccelMmPerSecPerSec > 0 && conf.MaxSpeedMmPerSec == 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("acceleration requires max_speed_mm_per_sec to be set"))
+	}
+
 	deps = append(deps, conf.SubAxes...)
 	return deps, nil
 }
@@ -64,8 +84,10 @@ func newMultiAxis(
 	}
 
 	mAx := &multiAxis{
-		Named:  conf.ResourceName().AsNamed(),
-		logger: logger,
+		Named:    conf.ResourceName().AsNamed(),
+		maxSpeed: newConf.MaxSpeedMmPerSec,
+		accel:    newConf.AccelMmPerSecPerSec,
+		logger:   logger,
 	}
 
 	for _, s := range newConf.SubAxes {
@@ -100,6 +122,10 @@ func (g *multiAxis) MoveToPosition(ctx context.Context, positions []float64, ext
 		)
 	}
 
+	if g.maxSpeed > 0 {
+		return g.moveCoordinated(ctx, positions, extra)
+	}
+
 	idx := 0
 	for _, subAx := range g.subAxes {
 		subAxNum, err := subAx.Lengths(ctx, extra)
@@ -118,6 +144,123 @@ func (g *multiAxis) MoveToPosition(ctx context.Context, positions []float64, ext
 	return nil
 }
 
+// How long each of the segments that the ramps of a coordinated move are split into lasts, in seconds.
+const coordinatedStepSec = 0.1
+
+// moveCoordinated moves all subaxes at once along synchronized trapezoidal
+// profiles. Each profile is split into segments of constant speed, and every
+// subaxis is sent the end of its segment at the segment's speed, so that the
+// subaxes accelerate, cruise and decelerate together and arrive at the same time.
+func (g *multiAxis) moveCoordinated(ctx context.Context, positions []float64, extra map[string]interface{}) error {
+	current, err := g.Position(ctx, extra)
+	if err != nil {
+		return err
+	}
+	if len(current) != len(positions) {
+		return errors.Errorf("gantry reported %v positions, expected %v", len(current), len(positions))
+	}
+
+	// split the start and goal per subaxis and find how far each one has to travel
+	starts := make([][]float64, 0, len(g.subAxes))
+	goals := make([][]float64, 0, len(g.subAxes))
+	distances := make([]float64, 0, len(g.subAxes))
+	idx := 0
+	for _, subAx := range g.subAxes {
+		subAxNum, err := subAx.Lengths(ctx, extra)
+		if err != nil {
+			return err
+		}
+		start := current[idx : idx+len(subAxNum)]
+		goal := positions[idx : idx+len(subAxNum)]
+		var sumSq float64
+		for i, p := range goal {
+			sumSq += math.Pow(p-start[i], 2)
+		}
+		idx += len(subAxNum)
+		starts = append(starts, start)
+		goals = append(goals, goal)
+		distances = append(distances, math.Sqrt(sumSq))
+	}
+
+	profiles := gantry.SynchronizedProfiles(distances, g.maxSpeed, g.accel)
+	segments := make([][]gantry.ProfileSegment, len(profiles))
+	numSegments := 0
+	for i, p := range profiles {
+		segments[i] = p.Segments(coordinatedStepSec)
+		if len(segments[i]) > numSegments {
+			numSegments = len(segments[i])
+		}
+	}
+
+	for k := 0; k < numSegments; k++ {
+		errs := make([]error, len(g.subAxes))
+		var wg sync.WaitGroup
+		for i, subAx := range g.subAxes {
+			if k >= len(segments[i]) {
+				continue
+			}
+			segment := segments[i][k]
+			frac := segment.PositionMm / distances[i]
+			target := make([]float64, len(goals[i]))
+			for j := range target {
+				target[j] = starts[i][j] + (goals[i][j]-starts[i][j])*frac
+			}
+			subExtra := make(map[string]interface{}, len(extra)+1)
+			for key, v := range extra {
+				subExtra[key] = v
+			}
+			subExtra[gantry.SpeedExtraKey] = segment.SpeedMmPerSec
+
+			i, subAx := i, subAx
+			wg.Add(1)
+			utils.ManagedGo(func() {
+				errs[i] = subAx.MoveToPosition(ctx, target, subExtra)
+			}, wg.Done)
+		}
+		wg.Wait()
+
+		var combined error
+		for _, err := range errs {
+			if err != nil && !errors.Is(err, context.Canceled) {
+				combined = multierr.Combine(combined, err)
+			}
+		}
+		if combined != nil {
+			return combined
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Home homes every subaxis that supports it, in the order they are configured.
+func (g *multiAxis) Home(ctx context.Context) error {
+	ctx, done := g.opMgr.New(ctx)
+	defer done()
+
+	for _, subAx := range g.subAxes {
+		homer, ok := subAx.(gantry.Homer)
+		if !ok {
+			g.logger.Debugf("subaxis %v does not support homing, skipping", subAx.Name())
+			continue
+		}
+		if err := homer.Home(ctx); err != nil {
+			return errors.Wrapf(err, "failed to home subaxis %v", subAx.Name())
+		}
+	}
+	return nil
+}
+
+// DoCommand supports gantry.HomeCommand, which homes the subaxes that support it.
+func (g *multiAxis) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := cmd[gantry.HomeCommand]; ok {
+		return map[string]interface{}{}, g.Home(ctx)
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
 // GoToInputs moves the gantry to a goal position in the Gantry frame.
 func (g *multiAxis) GoToInputs(ctx context.Context, goal []referenceframe.Input) error {
 	if len(g.subAxes) == 0 {
@@ -2,6 +2,7 @@ package multiaxis
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/edaniels/golog"
@@ -348,3 +349,115 @@ func TestComplexMultiAxis(t *testing.T) {
 			}
 		})
 }
+
+type homingGantry struct {
+	*inject.Gantry
+	homed bool
+}
+
+func (g *homingGantry) Home(ctx context.Context) error {
+	g.homed = true
+	return nil
+}
+
+func TestCoordinatedMoveToPosition(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	speeds := map[string]float64{}
+	makeAxis := func(name string, pos float64) *inject.Gantry {
+		ax := createFakeOneaAxis(100, []float64{pos})
+		ax.MoveToPositionFunc = func(ctx context.Context, pos []float64, extra map[string]interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			speed, ok := extra[gantry.SpeedExtraKey].(float64)
+			if !ok {
+				speed = -1
+			}
+			speeds[name] = speed
+			return nil
+		}
+		return ax
+	}
+
+	fakemultiaxis := &multiAxis{
+		subAxes:   []gantry.Gantry{makeAxis("x", 0), makeAxis("y", 0), makeAxis("z", 10)},
+		lengthsMm: []float64{100, 100, 100},
+		maxSpeed:  50,
+		logger:    golog.NewTestLogger(t),
+	}
+	err := fakemultiaxis.MoveToPosition(ctx, []float64{100, 25, 10}, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	// z is already in place and is not moved at all
+	test.That(t, len(speeds), test.ShouldEqual, 2)
+	test.That(t, speeds["x"], test.ShouldAlmostEqual, 50)
+	test.That(t, speeds["y"], test.ShouldAlmostEqual, 12.5)
+}
+
+func TestCoordinatedMoveSegments(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	speeds := map[string][]float64{}
+	targets := map[string][]float64{}
+	makeAxis := func(name string) *inject.Gantry {
+		ax := createFakeOneaAxis(1000, []float64{0})
+		ax.MoveToPositionFunc = func(ctx context.Context, pos []float64, extra map[string]interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			speeds[name] = append(speeds[name], extra[gantry.SpeedExtraKey].(float64))
+			targets[name] = append(targets[name], pos[0])
+			return nil
+		}
+		return ax
+	}
+
+	// x accelerates for 1s, cruises for 1s and decelerates for 1s, and y follows at a quarter of its pace
+	fakemultiaxis := &multiAxis{
+		subAxes:   []gantry.Gantry{makeAxis("x"), makeAxis("y")},
+		lengthsMm: []float64{1000, 1000},
+		maxSpeed:  100,
+		accel:     100,
+		logger:    golog.NewTestLogger(t),
+	}
+	err := fakemultiaxis.MoveToPosition(ctx, []float64{200, 50}, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, len(speeds["x"]), test.ShouldEqual, 21)
+	test.That(t, len(speeds["y"]), test.ShouldEqual, 21)
+	test.That(t, speeds["x"][0], test.ShouldAlmostEqual, 5)
+	test.That(t, speeds["x"][10], test.ShouldAlmostEqual, 100)
+	test.That(t, speeds["x"][20], test.ShouldAlmostEqual, 5)
+	for i := range speeds["x"] {
+		test.That(t, speeds["y"][i], test.ShouldAlmostEqual, speeds["x"][i]/4)
+		test.That(t, targets["y"][i], test.ShouldAlmostEqual, targets["x"][i]/4)
+	}
+	test.That(t, targets["x"][20], test.ShouldAlmostEqual, 200)
+	test.That(t, targets["y"][20], test.ShouldAlmostEqual, 50)
+}
+
+func TestHome(t *testing.T) {
+	homer := &homingGantry{Gantry: createFakeOneaAxis(1, []float64{1})}
+	fakemultiaxis := &multiAxis{
+		subAxes: []gantry.Gantry{homer, createFakeOneaAxis(2, []float64{5})},
+		logger:  golog.NewTestLogger(t),
+	}
+	test.That(t, fakemultiaxis.Home(context.Background()), test.ShouldBeNil)
+	test.That(t, homer.homed, test.ShouldBeTrue)
+
+	homer.homed = false
+	_, err := fakemultiaxis.DoCommand(context.Background(), map[string]interface{}{gantry.HomeCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, homer.homed, test.ShouldBeTrue)
+}
+
+func TestValidateSpeeds(t *testing.T) {
+	fakecfg := &Config{SubAxes: []string{"x"}, AccelMmPerSecPerSec: 10}
+	_, err := fakecfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	fakecfg = &Config{SubAxes: []string{"x"}, MaxSpeedMmPerSec: 10, AccelMmPerSecPerSec: 10}
+	_, err = fakecfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+}
@@ -4,6 +4,7 @@ package oneaxis
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/edaniels/golog"
@@ -199,6 +200,8 @@ func newOneAxis(ctx context.Context, deps resource.Dependencies, conf resource.C
 	return oAx, nil
 }
 
+// Home finds the position limits of the axis again, e.g. by running into the
+// limit switches.
 func (g *oneAxis) Home(ctx context.Context) error {
 	ctx, done := g.opMgr.New(ctx)
 	defer done()
@@ -284,6 +287,17 @@ func (g *oneAxis) homeEncoder(ctx context.Context) error {
 	return nil
 }
 
+// rpmForExtra returns the rpm to move at, honoring gantry.SpeedExtraKey if it is
+// present in extra.
+func (g *oneAxis) rpmForExtra(extra map[string]interface{}) float64 {
+	speed, ok := extra[gantry.SpeedExtraKey].(float64)
+	if !ok || speed <= 0 {
+		return g.rpm
+	}
+	revPerMm := (g.positionLimits[1] - g.positionLimits[0]) / g.lengthMm
+	return math.Abs(speed * revPerMm * 60)
+}
+
 func (g *oneAxis) linearToRotational(positions float64) float64 {
 	theRange := g.positionLimits[1] - g.positionLimits[0]
 	x := positions / g.lengthMm
@@ -380,6 +394,7 @@ func (g *oneAxis) MoveToPosition(ctx context.Context, positions []float64, extra
 	}
 
 	x := g.linearToRotational(positions[0])
+	rpm := g.rpmForExtra(extra)
 	// Limit switch errors that stop the motors.
 	// Currently needs to be moved by underlying gantry motor.
 	if len(g.limitSwitchPins) > 0 {
@@ -392,7 +407,7 @@ func (g *oneAxis) MoveToPosition(ctx context.Context, positions []float64, extra
 		if hit {
 			if x < g.positionLimits[0] {
 				dir := float64(1)
-				return g.motor.GoFor(ctx, dir*g.rpm, 2, extra)
+				return g.motor.GoFor(ctx, dir*rpm, 2, extra)
 			}
 			return g.motor.Stop(ctx, extra)
 		}
@@ -405,19 +420,19 @@ func (g *oneAxis) MoveToPosition(ctx context.Context, positions []float64, extra
 		if hit {
 			if x > g.positionLimits[1] {
 				dir := float64(-1)
-				return g.motor.GoFor(ctx, dir*g.rpm, 2, extra)
+				return g.motor.GoFor(ctx, dir*rpm, 2, extra)
 			}
 			return g.motor.Stop(ctx, extra)
 		}
 
-		err = g.motor.GoTo(ctx, g.rpm, x, extra)
+		err = g.motor.GoTo(ctx, rpm, x, extra)
 		if err != nil {
 			return err
 		}
 	}
 
-	g.logger.Debugf("going to %.2f at speed %.2f", x, g.rpm)
-	err := g.motor.GoTo(ctx, g.rpm, x, extra)
+	g.logger.Debugf("going to %.2f at speed %.2f", x, rpm)
+	err := g.motor.GoTo(ctx, rpm, x, extra)
 	if err != nil {
 		return err
 	}
@@ -431,6 +446,14 @@ func (g *oneAxis) Stop(ctx context.Context, extra map[string]interface{}) error
 	return g.motor.Stop(ctx, extra)
 }
 
+// DoCommand supports gantry.HomeCommand, which homes the axis.
+func (g *oneAxis) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := cmd[gantry.HomeCommand]; ok {
+		return map[string]interface{}{}, g.Home(ctx)
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
 // Close calls stop.
 func (g *oneAxis) Close(ctx context.Context) error {
 	return g.Stop(ctx, nil)
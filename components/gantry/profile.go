@@ -0,0 +1,152 @@
+package gantry
+
+import "math"
+
+// A TrapezoidalProfile describes a move of a single axis that accelerates at a
+// constant rate up to a cruising speed, cruises, and decelerates at the same rate
+// to a stop. If the distance is too short to reach the cruising speed the profile
+// degenerates into a triangle.
+type TrapezoidalProfile struct {
+	DistanceMm          float64
+	MaxSpeedMmPerSec    float64
+	AccelMmPerSecPerSec float64
+}
+
+// peakSpeed returns the highest speed actually reached during the move.
+func (p TrapezoidalProfile) peakSpeed() float64 {
+	d := math.Abs(p.DistanceMm)
+	if p.AccelMmPerSecPerSec <= 0 {
+		return p.MaxSpeedMmPerSec
+	}
+	return math.Min(p.MaxSpeedMmPerSec, math.Sqrt(d*p.AccelMmPerSecPerSec))
+}
+
+// Duration returns how long the move takes, in seconds.
+func (p TrapezoidalProfile) Duration() float64 {
+	d := math.Abs(p.DistanceMm)
+	if d == 0 || p.MaxSpeedMmPerSec <= 0 {
+		return 0
+	}
+	if p.AccelMmPerSecPerSec <= 0 {
+		return d / p.MaxSpeedMmPerSec
+	}
+	v := p.peakSpeed()
+	rampTime := v / p.AccelMmPerSecPerSec
+	rampDist := v * rampTime // both ramps together
+	return 2*rampTime + (d-rampDist)/v
+}
+
+// PositionAt returns how far along the move the axis is after the given number
+// of seconds. The result has the same sign as DistanceMm.
+func (p TrapezoidalProfile) PositionAt(t float64) float64 {
+	total := p.Duration()
+	if total == 0 || t >= total {
+		return p.DistanceMm
+	}
+	if t <= 0 {
+		return 0
+	}
+	sign := 1.0
+	if p.DistanceMm < 0 {
+		sign = -1
+	}
+	if p.AccelMmPerSecPerSec <= 0 {
+		return sign * p.MaxSpeedMmPerSec * t
+	}
+
+	v := p.peakSpeed()
+	a := p.AccelMmPerSecPerSec
+	rampTime := v / a
+	switch {
+	case t < rampTime:
+		return sign * 0.5 * a * t * t
+	case t < total-rampTime:
+		return sign * (0.5*v*rampTime + v*(t-rampTime))
+	default:
+		remaining := total - t
+		return p.DistanceMm - sign*0.5*a*remaining*remaining
+	}
+}
+
+// AverageSpeed returns the average speed over the whole move in mm/sec.
+func (p TrapezoidalProfile) AverageSpeed() float64 {
+	total := p.Duration()
+	if total == 0 {
+		return 0
+	}
+	return math.Abs(p.DistanceMm) / total
+}
+
+// SynchronizedProfiles returns one profile per distance such that every axis
+// starts and stops at the same time. The axis with the longest distance moves
+// with the given limits and every other axis has its speed and acceleration
+// scaled down proportionally, so all of the profiles share the same shape.
+func SynchronizedProfiles(distancesMm []float64, maxSpeedMmPerSec, accelMmPerSecPerSec float64) []TrapezoidalProfile {
+	longest := 0.0
+	for _, d := range distancesMm {
+		longest = math.Max(longest, math.Abs(d))
+	}
+
+	profiles := make([]TrapezoidalProfile, 0, len(distancesMm))
+	for _, d := range distancesMm {
+		scale := 0.0
+		if longest > 0 {
+			scale = math.Abs(d) / longest
+		}
+		profiles = append(profiles, TrapezoidalProfile{
+			DistanceMm:          d,
+			MaxSpeedMmPerSec:    maxSpeedMmPerSec * scale,
+			AccelMmPerSecPerSec: accelMmPerSecPerSec * scale,
+		})
+	}
+	return profiles
+}
+
+// A ProfileSegment is a piece of a profile during which the axis moves at a
+// constant speed, ending at PositionMm after EndSec seconds.
+type ProfileSegment struct {
+	EndSec        float64
+	PositionMm    float64
+	SpeedMmPerSec float64
+}
+
+// Segments approximates the profile by segments of constant speed that can each
+// be commanded as a move to a position at a speed. The ramps are split into
+// segments of at most stepSec seconds and the cruise is a single segment. All
+// profiles returned by SynchronizedProfiles share the same segment times.
+func (p TrapezoidalProfile) Segments(stepSec float64) []ProfileSegment {
+	total := p.Duration()
+	if total == 0 {
+		return nil
+	}
+	var times []float64
+	if p.AccelMmPerSecPerSec <= 0 || stepSec <= 0 {
+		times = []float64{total}
+	} else {
+		rampTime := p.peakSpeed() / p.AccelMmPerSecPerSec
+		steps := int(math.Ceil(rampTime / stepSec))
+		for i := 1; i <= steps; i++ {
+			times = append(times, rampTime*float64(i)/float64(steps))
+		}
+		// a triangular profile has no cruise
+		if total-2*rampTime > 1e-9 {
+			times = append(times, total-rampTime)
+		}
+		for i := steps - 1; i >= 0; i-- {
+			times = append(times, total-rampTime*float64(i)/float64(steps))
+		}
+	}
+
+	segments := make([]ProfileSegment, 0, len(times))
+	lastTime, lastPos := 0.0, 0.0
+	for _, t := range times {
+		pos := p.PositionAt(t)
+		segments = append(segments, ProfileSegment{
+			EndSec:        t,
+			PositionMm:    pos,
+			SpeedMmPerSec: math.Abs(pos-lastPos) / (t - lastTime),
+		})
+		lastTime, lastPos = t, pos
+	}
+	return segments
+}
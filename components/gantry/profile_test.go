@@ -0,0 +1,72 @@
+package gantry
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestTrapezoidalProfile(t *testing.T) {
+	// reaches cruising speed: 1s ramp up, 1s cruise, 1s ramp down
+	p := TrapezoidalProfile{DistanceMm: 200, MaxSpeedMmPerSec: 100, AccelMmPerSecPerSec: 100}
+	test.That(t, p.Duration(), test.ShouldAlmostEqual, 3)
+	test.That(t, p.PositionAt(0), test.ShouldEqual, 0)
+	test.That(t, p.PositionAt(1), test.ShouldAlmostEqual, 50)
+	test.That(t, p.PositionAt(1.5), test.ShouldAlmostEqual, 100)
+	test.That(t, p.PositionAt(2), test.ShouldAlmostEqual, 150)
+	test.That(t, p.PositionAt(3), test.ShouldEqual, 200)
+	test.That(t, p.PositionAt(10), test.ShouldEqual, 200)
+	test.That(t, p.AverageSpeed(), test.ShouldAlmostEqual, 200./3)
+
+	// too short to reach cruising speed
+	p = TrapezoidalProfile{DistanceMm: -25, MaxSpeedMmPerSec: 100, AccelMmPerSecPerSec: 100}
+	test.That(t, p.Duration(), test.ShouldAlmostEqual, 1)
+	test.That(t, p.PositionAt(0.5), test.ShouldAlmostEqual, -12.5)
+
+	// no acceleration limit means constant speed
+	p = TrapezoidalProfile{DistanceMm: 50, MaxSpeedMmPerSec: 100}
+	test.That(t, p.Duration(), test.ShouldAlmostEqual, 0.5)
+	test.That(t, p.PositionAt(0.25), test.ShouldAlmostEqual, 25)
+
+	test.That(t, TrapezoidalProfile{MaxSpeedMmPerSec: 100}.Duration(), test.ShouldEqual, 0)
+}
+
+func TestSynchronizedProfiles(t *testing.T) {
+	profiles := SynchronizedProfiles([]float64{200, -50, 0}, 100, 100)
+	test.That(t, len(profiles), test.ShouldEqual, 3)
+	test.That(t, profiles[1].Duration(), test.ShouldAlmostEqual, profiles[0].Duration())
+	test.That(t, profiles[1].PositionAt(1.5), test.ShouldAlmostEqual, -25)
+	test.That(t, profiles[2].Duration(), test.ShouldEqual, 0)
+	test.That(t, profiles[2].AverageSpeed(), test.ShouldEqual, 0)
+}
+
+func TestProfileSegments(t *testing.T) {
+	// 1s ramps split in halves around a 1s cruise
+	p := TrapezoidalProfile{DistanceMm: 200, MaxSpeedMmPerSec: 100, AccelMmPerSecPerSec: 100}
+	segments := p.Segments(0.5)
+	test.That(t, len(segments), test.ShouldEqual, 5)
+	test.That(t, segments[0].EndSec, test.ShouldAlmostEqual, 0.5)
+	test.That(t, segments[0].PositionMm, test.ShouldAlmostEqual, 12.5)
+	test.That(t, segments[0].SpeedMmPerSec, test.ShouldAlmostEqual, 25)
+	test.That(t, segments[1].SpeedMmPerSec, test.ShouldAlmostEqual, 75)
+	test.That(t, segments[2].EndSec, test.ShouldAlmostEqual, 2)
+	test.That(t, segments[2].SpeedMmPerSec, test.ShouldAlmostEqual, 100)
+	test.That(t, segments[3].SpeedMmPerSec, test.ShouldAlmostEqual, 75)
+	test.That(t, segments[4].EndSec, test.ShouldAlmostEqual, 3)
+	test.That(t, segments[4].PositionMm, test.ShouldAlmostEqual, 200)
+
+	// a triangular profile has no cruise segment
+	p = TrapezoidalProfile{DistanceMm: -25, MaxSpeedMmPerSec: 100, AccelMmPerSecPerSec: 100}
+	segments = p.Segments(0.5)
+	test.That(t, len(segments), test.ShouldEqual, 2)
+	test.That(t, segments[1].PositionMm, test.ShouldAlmostEqual, -25)
+
+	// synchronized profiles share their segment times
+	profiles := SynchronizedProfiles([]float64{200, 50}, 100, 100)
+	long, short := profiles[0].Segments(0.5), profiles[1].Segments(0.5)
+	test.That(t, len(short), test.ShouldEqual, len(long))
+	for i := range long {
+		test.That(t, short[i].EndSec, test.ShouldAlmostEqual, long[i].EndSec)
+	}
+	test.That(t, TrapezoidalProfile{}.Segments(0.5), test.ShouldBeEmpty)
+}
@@ -43,6 +43,12 @@ type Config struct {
 	UseAlternateI2CAddress bool            `json:"use_alternate_i2c_address,omitempty"`
 	SingleTap              *TapConfig      `json:"tap,omitempty"`
 	FreeFall               *FreeFallConfig `json:"free_fall,omitempty"`
+
+	// Sampling settings. The chip defaults to 100Hz and +/-2g. Low power mode trades
+	// slightly more noise for lower current draw.
+	SampleRateHz float64 `json:"sample_rate_hz,omitempty"`
+	AccelRangeG  int     `json:"accel_range_g,omitempty"`
+	LowPower     bool    `json:"low_power,omitempty"`
 }
 
 // TapConfig is a description of the configs for tap registers.
@@ -119,6 +125,9 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 			return nil, err
 		}
 	}
+	if _, err := newChipSettings(cfg); err != nil {
+		return nil, utils.NewConfigValidationError(path, err)
+	}
 	var deps []string
 	deps = append(deps, cfg.BoardName)
 	return deps, nil
@@ -139,6 +148,7 @@ type adxl345 struct {
 
 	bus                      board.I2C
 	i2cAddress               byte
+	settings                 chipSettings
 	logger                   golog.Logger
 	interruptsEnabled        byte
 	interruptsFound          map[InterruptID]int
@@ -188,6 +198,11 @@ func NewAdxl345(
 		address = 0x53
 	}
 
+	settings, err := newChipSettings(newConf)
+	if err != nil {
+		return nil, err
+	}
+
 	interruptConfigurations := getInterruptConfigurations(newConf)
 	configuredRegisterValues := getFreeFallRegisterValues(newConf.FreeFall)
 	for k, v := range getSingleTapRegisterValues(newConf.SingleTap) {
@@ -199,6 +214,7 @@ func NewAdxl345(
 		Named:                    conf.ResourceName().AsNamed(),
 		bus:                      bus,
 		i2cAddress:               address,
+		settings:                 settings,
 		interruptsEnabled:        interruptConfigurations[IntEnableAddr],
 		logger:                   logger,
 		cancelContext:            cancelContext,
@@ -224,6 +240,12 @@ func NewAdxl345(
 			address, deviceID)
 	}
 
+	for register, value := range settings.registerValues() {
+		if err := sensor.writeByte(ctx, register, value); err != nil {
+			return nil, errors.Wrapf(err, "unable to configure ADXL345 register %d", register)
+		}
+	}
+
 	// The chip starts out in standby mode. Set it to measurement mode so we can get data from it.
 	// To do this, we set the Power Control register (0x2D) to turn on the 8's bit.
 	if err = sensor.writeByte(ctx, 0x2D, 0x08); err != nil {
@@ -235,8 +257,8 @@ func NewAdxl345(
 	sensor.activeBackgroundWorkers.Add(1)
 	utils.PanicCapturingGo(func() {
 		defer sensor.activeBackgroundWorkers.Done()
-		// Reading data a thousand times per second is probably fast enough.
-		timer := time.NewTicker(time.Millisecond)
+		// Read as often as the chip produces new data, up to a thousand times per second.
+		timer := time.NewTicker(sensor.settings.pollPeriod())
 		defer timer.Stop()
 
 		for {
@@ -251,7 +273,7 @@ func NewAdxl345(
 					continue
 				}
 
-				linearAcceleration := toLinearAcceleration(rawData)
+				linearAcceleration := toLinearAcceleration(rawData, sensor.settings.accelRangeG)
 				// Only lock the mutex to write to the shared data, so other threads can read the
 				// data as often as they want.
 				sensor.mu.Lock()
@@ -472,14 +494,14 @@ func setScale(value int, maxValue float64) float64 {
 	return float64(value) * maxValue / (1 << 9)
 }
 
-func toLinearAcceleration(data []byte) r3.Vector {
+func toLinearAcceleration(data []byte, rangeG int) r3.Vector {
 	// Vectors take ints, but we've got int16's, so we need to convert.
 	x := int(rutils.Int16FromBytesLE(data[0:2]))
 	y := int(rutils.Int16FromBytesLE(data[2:4]))
 	z := int(rutils.Int16FromBytesLE(data[4:6]))
 
-	// The default scale is +/- 2G's, but our units should be m/sec/sec.
-	maxAcceleration := 2.0 * 9.81 /* m/sec/sec */
+	// The scale is in G's, but our units should be m/sec/sec.
+	maxAcceleration := float64(rangeG) * 9.81 /* m/sec/sec */
 	return r3.Vector{
 		X: setScale(x, maxAcceleration),
 		Y: setScale(y, maxAcceleration),
@@ -541,8 +563,10 @@ func (adxl *adxl345) Readings(ctx context.Context, extra map[string]interface{})
 func (adxl *adxl345) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
 	// We don't implement any of the MovementSensor interface yet, though hopefully
 	// LinearAcceleration will be added to the interface soon.
+	settings := adxl.settings.sensorSettings()
 	return &movementsensor.Properties{
 		LinearAccelerationSupported: true,
+		Settings:                    &settings,
 	}, nil
 }
 
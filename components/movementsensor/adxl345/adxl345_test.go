@@ -232,3 +232,31 @@ func TestReadInterrupts(t *testing.T) {
 		test.That(t, sensor.interruptsFound[FreeFall], test.ShouldEqual, 0)
 	})
 }
+
+func TestChipSettings(t *testing.T) {
+	s, err := newChipSettings(&Config{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, s.pollPeriod(), test.ShouldEqual, 10*time.Millisecond)
+	test.That(t, s.registerValues(), test.ShouldResemble, map[byte]byte{BwRateAddr: 0xA, DataFormatAddr: 0})
+
+	s, err = newChipSettings(&Config{SampleRateHz: 3200, AccelRangeG: 16})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, s.pollPeriod(), test.ShouldEqual, time.Millisecond)
+	test.That(t, s.registerValues(), test.ShouldResemble, map[byte]byte{BwRateAddr: 0xF, DataFormatAddr: 3})
+	test.That(t, s.sensorSettings().ToMap(), test.ShouldResemble, map[string]interface{}{
+		"sample_rate_hz":        3200.0,
+		"low_pass_bandwidth_hz": 1600.0,
+		"accel_range_g":         16.0,
+	})
+
+	s, err = newChipSettings(&Config{SampleRateHz: 25, LowPower: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, s.registerValues()[BwRateAddr], test.ShouldEqual, 0x18)
+
+	_, err = newChipSettings(&Config{SampleRateHz: 30})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = newChipSettings(&Config{SampleRateHz: 1600, LowPower: true})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = newChipSettings(&Config{AccelRangeG: 5})
+	test.That(t, err, test.ShouldNotBeNil)
+}
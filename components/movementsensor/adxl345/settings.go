@@ -0,0 +1,99 @@
+package adxl345
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/movementsensor"
+)
+
+// Registers that configure sampling.
+const (
+	// the output data rate in the low 4 bits, and the low power bit.
+	BwRateAddr byte = 0x2C
+	// the measurement range in the low 2 bits.
+	DataFormatAddr byte = 0x31
+)
+
+const (
+	defaultSampleRateHz = 100
+	defaultAccelRangeG  = 2
+	lowPowerBit         = 1 << 4
+	// Polling faster than this only rereads the same sample.
+	maxPollRateHz = 1000
+)
+
+// The rate codes for each supported output data rate, in Hz.
+var sampleRates = map[float64]byte{
+	3200: 0xF, 1600: 0xE, 800: 0xD, 400: 0xC, 200: 0xB, 100: 0xA, 50: 0x9, 25: 0x8, 12.5: 0x7, 6.25: 0x6,
+}
+
+// The range codes for each supported measurement range, in g.
+var accelRanges = map[int]byte{2: 0, 4: 1, 8: 2, 16: 3}
+
+// chipSettings is the sampling configuration of the chip, checked against what it supports.
+type chipSettings struct {
+	sampleRateHz float64
+	accelRangeG  int
+	lowPower     bool
+}
+
+func newChipSettings(cfg *Config) (chipSettings, error) {
+	s := chipSettings{
+		sampleRateHz: defaultSampleRateHz,
+		accelRangeG:  defaultAccelRangeG,
+		lowPower:     cfg.LowPower,
+	}
+	if cfg.SampleRateHz != 0 {
+		s.sampleRateHz = cfg.SampleRateHz
+	}
+	if cfg.AccelRangeG != 0 {
+		s.accelRangeG = cfg.AccelRangeG
+	}
+
+	if _, ok := sampleRates[s.sampleRateHz]; !ok {
+		rates := make([]float64, 0, len(sampleRates))
+		for r := range sampleRates {
+			rates = append(rates, r)
+		}
+		sort.Float64s(rates)
+		return s, errors.Errorf("sample_rate_hz must be one of %v", rates)
+	}
+	if _, ok := accelRanges[s.accelRangeG]; !ok {
+		return s, errors.New("accel_range_g must be one of [2 4 8 16]")
+	}
+	// The datasheet only documents reduced power operation between these rates.
+	if s.lowPower && (s.sampleRateHz < 12.5 || s.sampleRateHz > 400) {
+		return s, errors.New("low_power is only supported with a sample_rate_hz between 12.5 and 400")
+	}
+	return s, nil
+}
+
+// pollPeriod is the output data period, capped at maxPollRateHz.
+func (s chipSettings) pollPeriod() time.Duration {
+	return time.Duration(float64(time.Second) / math.Min(s.sampleRateHz, maxPollRateHz))
+}
+
+// registerValues maps each sampling register to the value that selects these settings.
+func (s chipSettings) registerValues() map[byte]byte {
+	bwRate := sampleRates[s.sampleRateHz]
+	if s.lowPower {
+		bwRate |= lowPowerBit
+	}
+	return map[byte]byte{
+		BwRateAddr:     bwRate,
+		DataFormatAddr: accelRanges[s.accelRangeG],
+	}
+}
+
+func (s chipSettings) sensorSettings() movementsensor.SensorSettings {
+	return movementsensor.SensorSettings{
+		SampleRateHz: s.sampleRateHz,
+		// The chip's digital filter always has a bandwidth of half the output data rate.
+		LowPassBandwidthHz: s.sampleRateHz / 2,
+		AccelRangeG:        float64(s.accelRangeG),
+	}
+}
@@ -0,0 +1,235 @@
+package movementsensor
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+	"gonum.org/v1/gonum/mat"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// The DoCommand keys IMU models use to run calibration routines. Each command
+// takes an optional "duration_sec" and returns the resulting calibration under
+// "calibration", in the same shape as IMUCalibration. The models also save it to
+// their calibration file, so that it survives restarts.
+const (
+	CalibrateGyroCommand         = "calibrate_gyro"
+	CalibrateMagnetometerCommand = "calibrate_magnetometer"
+)
+
+// IMUCalibration holds calibration results that IMU models apply to their raw readings.
+type IMUCalibration struct {
+	// GyroBias is subtracted from every angular velocity reading, in deg/sec.
+	GyroBias *r3.Vector `json:"gyro_bias,omitempty"`
+	// Magnetometer corrects hard- and soft-iron distortions of the magnetometer.
+	Magnetometer *MagnetometerCalibration `json:"magnetometer,omitempty"`
+}
+
+// MagnetometerCalibration describes an axis-aligned ellipsoid correction of a
+// magnetometer: raw readings are offset by the hard-iron vector, then each axis is
+// scaled so the ellipsoid becomes a sphere.
+type MagnetometerCalibration struct {
+	HardIron r3.Vector `json:"hard_iron"`
+	SoftIron r3.Vector `json:"soft_iron_scale"`
+}
+
+// DefaultCalibrationDirectory is where IMU models save their calibration unless configured otherwise.
+var DefaultCalibrationDirectory = filepath.Join(os.Getenv("HOME"), ".viam", "calibration")
+
+// CalibrationPath returns the file the calibration of the named sensor is saved to: the configured one, or a file
+// named after the sensor in DefaultCalibrationDirectory.
+func CalibrationPath(configured, name string) string {
+	if configured != "" {
+		return configured
+	}
+	return filepath.Join(DefaultCalibrationDirectory, name+".json")
+}
+
+// LoadCalibration returns the calibration an IMU model starts with: the one in its config if there is one, and
+// otherwise the one last saved to path, if any.
+func LoadCalibration(configured *IMUCalibration, path string) (*IMUCalibration, error) {
+	if configured != nil {
+		return configured, nil
+	}
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var calibration IMUCalibration
+	if err := json.Unmarshal(data, &calibration); err != nil {
+		return nil, errors.Wrapf(err, "failed to read calibration %q", path)
+	}
+	return &calibration, nil
+}
+
+// Save writes the calibration to a temporary file and then moves it into place, so that the file is never left half
+// written.
+func (c *IMUCalibration) Save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ApplyGyro returns the angular velocity with the gyro bias removed.
+func (c *IMUCalibration) ApplyGyro(av spatialmath.AngularVelocity) spatialmath.AngularVelocity {
+	if c == nil || c.GyroBias == nil {
+		return av
+	}
+	return spatialmath.AngularVelocity{X: av.X - c.GyroBias.X, Y: av.Y - c.GyroBias.Y, Z: av.Z - c.GyroBias.Z}
+}
+
+// ApplyMagnetometer returns the magnetic field with hard- and soft-iron distortion removed.
+func (c *IMUCalibration) ApplyMagnetometer(field r3.Vector) r3.Vector {
+	if c == nil || c.Magnetometer == nil {
+		return field
+	}
+	centered := field.Sub(c.Magnetometer.HardIron)
+	return r3.Vector{
+		X: centered.X * c.Magnetometer.SoftIron.X,
+		Y: centered.Y * c.Magnetometer.SoftIron.Y,
+		Z: centered.Z * c.Magnetometer.SoftIron.Z,
+	}
+}
+
+// TiltCompensatedHeading returns the compass heading in degrees clockwise from magnetic north of a sensor with the
+// given roll and pitch in radians, from the magnetic field it measures along its x (forward), y (right) and z (down)
+// axes. The field should already have had ApplyMagnetometer applied.
+func TiltCompensatedHeading(field r3.Vector, roll, pitch float64) float64 {
+	// rotate the field back into the horizontal plane
+	x := field.X*math.Cos(pitch) + field.Y*math.Sin(roll)*math.Sin(pitch) + field.Z*math.Cos(roll)*math.Sin(pitch)
+	y := field.Y*math.Cos(roll) - field.Z*math.Sin(roll)
+	heading := math.Atan2(-y, x) * 180 / math.Pi
+	if heading < 0 {
+		heading += 360
+	}
+	return heading
+}
+
+// ToMap converts the calibration into the form returned by DoCommand.
+func (c *IMUCalibration) ToMap() map[string]interface{} {
+	vecToMap := func(v r3.Vector) map[string]interface{} {
+		return map[string]interface{}{"x": v.X, "y": v.Y, "z": v.Z}
+	}
+	out := map[string]interface{}{}
+	if c.GyroBias != nil {
+		out["gyro_bias"] = vecToMap(*c.GyroBias)
+	}
+	if c.Magnetometer != nil {
+		out["magnetometer"] = map[string]interface{}{
+			"hard_iron":       vecToMap(c.Magnetometer.HardIron),
+			"soft_iron_scale": vecToMap(c.Magnetometer.SoftIron),
+		}
+	}
+	return out
+}
+
+// EstimateGyroBias returns the mean of angular velocity samples taken while the
+// sensor was held still.
+func EstimateGyroBias(samples []spatialmath.AngularVelocity) (r3.Vector, error) {
+	if len(samples) == 0 {
+		return r3.Vector{}, errors.New("no gyro samples to estimate a bias from")
+	}
+	var sum r3.Vector
+	for _, s := range samples {
+		sum = sum.Add(r3.Vector(s))
+	}
+	return sum.Mul(1 / float64(len(samples))), nil
+}
+
+// FitMagnetometerCalibration fits an axis-aligned ellipsoid
+// Ax² + By² + Cz² + Dx + Ey + Fz = 1 to magnetometer samples taken while the sensor
+// was rotated through as many orientations as possible, and returns the correction
+// that maps it onto a sphere of the ellipsoid's mean radius.
+func FitMagnetometerCalibration(samples []r3.Vector) (*MagnetometerCalibration, error) {
+	if len(samples) < 6 {
+		return nil, errors.Errorf("need at least 6 magnetometer samples to fit an ellipsoid, have %d", len(samples))
+	}
+
+	design := mat.NewDense(len(samples), 6, nil)
+	ones := mat.NewVecDense(len(samples), nil)
+	for i, s := range samples {
+		design.SetRow(i, []float64{s.X * s.X, s.Y * s.Y, s.Z * s.Z, s.X, s.Y, s.Z})
+		ones.SetVec(i, 1)
+	}
+
+	var coeffs mat.VecDense
+	if err := coeffs.SolveVec(design, ones); err != nil {
+		return nil, errors.Wrap(err, "could not fit ellipsoid to magnetometer samples, rotate the sensor through more orientations")
+	}
+	a, b, c := coeffs.AtVec(0), coeffs.AtVec(1), coeffs.AtVec(2)
+	d, e, f := coeffs.AtVec(3), coeffs.AtVec(4), coeffs.AtVec(5)
+	// the coefficients only describe an ellipsoid if they all share a sign; they
+	// are negative when the origin lies outside of the ellipsoid.
+	if !(a > 0 && b > 0 && c > 0) && !(a < 0 && b < 0 && c < 0) {
+		return nil, errors.New("magnetometer samples do not describe an ellipsoid, rotate the sensor through more orientations")
+	}
+
+	center := r3.Vector{X: -d / (2 * a), Y: -e / (2 * b), Z: -f / (2 * c)}
+	g := 1 + d*d/(4*a) + e*e/(4*b) + f*f/(4*c)
+	radii := r3.Vector{X: math.Sqrt(g / a), Y: math.Sqrt(g / b), Z: math.Sqrt(g / c)}
+	mean := (radii.X + radii.Y + radii.Z) / 3
+
+	return &MagnetometerCalibration{
+		HardIron: center,
+		SoftIron: r3.Vector{X: mean / radii.X, Y: mean / radii.Y, Z: mean / radii.Z},
+	}, nil
+}
+
+// CollectSamples calls read at the given period for the given duration and
+// returns every successful reading. It is meant for calibration routines run
+// from DoCommand.
+func CollectSamples[T any](ctx context.Context, duration, period time.Duration, read func() (T, error)) ([]T, error) {
+	var samples []T
+	var lastErr error
+	deadline := time.Now().Add(duration)
+	for time.Now().Before(deadline) {
+		sample, err := read()
+		if err != nil {
+			lastErr = err
+		} else {
+			samples = append(samples, sample)
+		}
+		if !goutils.SelectContextOrWait(ctx, period) {
+			return nil, ctx.Err()
+		}
+	}
+	if len(samples) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return samples, nil
+}
+
+// CalibrationDuration returns the "duration_sec" argument of a calibration
+// command, or the given default.
+func CalibrationDuration(args interface{}, defaultDuration time.Duration) time.Duration {
+	argMap, ok := args.(map[string]interface{})
+	if !ok {
+		return defaultDuration
+	}
+	secs, ok := argMap["duration_sec"].(float64)
+	if !ok || secs <= 0 {
+		return defaultDuration
+	}
+	return time.Duration(secs * float64(time.Second))
+}
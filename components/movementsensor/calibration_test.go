@@ -0,0 +1,134 @@
+package movementsensor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestEstimateGyroBias(t *testing.T) {
+	_, err := EstimateGyroBias(nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	bias, err := EstimateGyroBias([]spatialmath.AngularVelocity{{X: 1, Y: 2, Z: -1}, {X: 3, Y: 2, Z: -3}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, bias, test.ShouldResemble, r3.Vector{X: 2, Y: 2, Z: -2})
+
+	cal := &IMUCalibration{GyroBias: &bias}
+	test.That(t, cal.ApplyGyro(spatialmath.AngularVelocity{X: 2, Y: 2, Z: -2}), test.ShouldResemble, spatialmath.AngularVelocity{})
+
+	var noCal *IMUCalibration
+	test.That(t, noCal.ApplyGyro(spatialmath.AngularVelocity{X: 1}), test.ShouldResemble, spatialmath.AngularVelocity{X: 1})
+}
+
+func TestFitMagnetometerCalibration(t *testing.T) {
+	_, err := FitMagnetometerCalibration([]r3.Vector{{X: 1}})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// sample an ellipsoid centered at (10, -5, 3) with radii (2, 1, 1.5)
+	center := r3.Vector{X: 10, Y: -5, Z: 3}
+	radii := r3.Vector{X: 2, Y: 1, Z: 1.5}
+	var samples []r3.Vector
+	for theta := 0.1; theta < math.Pi; theta += 0.3 {
+		for phi := 0.0; phi < 2*math.Pi; phi += 0.3 {
+			samples = append(samples, r3.Vector{
+				X: center.X + radii.X*math.Sin(theta)*math.Cos(phi),
+				Y: center.Y + radii.Y*math.Sin(theta)*math.Sin(phi),
+				Z: center.Z + radii.Z*math.Cos(theta),
+			})
+		}
+	}
+
+	mag, err := FitMagnetometerCalibration(samples)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mag.HardIron.X, test.ShouldAlmostEqual, center.X, 1e-6)
+	test.That(t, mag.HardIron.Y, test.ShouldAlmostEqual, center.Y, 1e-6)
+	test.That(t, mag.HardIron.Z, test.ShouldAlmostEqual, center.Z, 1e-6)
+
+	// every corrected sample should now lie on the same sphere
+	cal := &IMUCalibration{Magnetometer: mag}
+	r := cal.ApplyMagnetometer(samples[0]).Norm()
+	for _, s := range samples {
+		test.That(t, cal.ApplyMagnetometer(s).Norm(), test.ShouldAlmostEqual, r, 1e-6)
+	}
+
+	out := cal.ToMap()
+	test.That(t, out["magnetometer"], test.ShouldNotBeNil)
+	test.That(t, out["gyro_bias"], test.ShouldBeNil)
+}
+
+func TestCollectSamples(t *testing.T) {
+	n := 0
+	samples, err := CollectSamples(context.Background(), 50*time.Millisecond, time.Millisecond, func() (int, error) {
+		n++
+		if n%2 == 0 {
+			return 0, errors.New("flaky")
+		}
+		return n, nil
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(samples), test.ShouldBeGreaterThan, 0)
+	for _, s := range samples {
+		test.That(t, s%2, test.ShouldEqual, 1)
+	}
+
+	_, err = CollectSamples(context.Background(), 10*time.Millisecond, time.Millisecond, func() (int, error) {
+		return 0, errors.New("broken")
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestCalibrationDuration(t *testing.T) {
+	test.That(t, CalibrationDuration(true, time.Second), test.ShouldEqual, time.Second)
+	test.That(t, CalibrationDuration(map[string]interface{}{"duration_sec": 0.5}, time.Second), test.ShouldEqual, 500*time.Millisecond)
+}
+
+func TestSaveAndLoadCalibration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "imu.json")
+	test.That(t, CalibrationPath(path, "imu"), test.ShouldEqual, path)
+	test.That(t, CalibrationPath("", "imu"), test.ShouldEqual, filepath.Join(DefaultCalibrationDirectory, "imu.json"))
+
+	cal, err := LoadCalibration(nil, path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cal, test.ShouldBeNil)
+
+	bias := r3.Vector{X: 1, Y: 2, Z: 3}
+	saved := &IMUCalibration{
+		GyroBias:     &bias,
+		Magnetometer: &MagnetometerCalibration{HardIron: r3.Vector{X: 4}, SoftIron: r3.Vector{X: 1, Y: 1, Z: 2}},
+	}
+	test.That(t, saved.Save(path), test.ShouldBeNil)
+
+	cal, err = LoadCalibration(nil, path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cal, test.ShouldResemble, saved)
+
+	// a calibration in the config takes precedence over the saved one
+	configured := &IMUCalibration{GyroBias: &r3.Vector{}}
+	cal, err = LoadCalibration(configured, path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cal, test.ShouldEqual, configured)
+}
+
+func TestTiltCompensatedHeading(t *testing.T) {
+	// level, facing north and then east
+	test.That(t, TiltCompensatedHeading(r3.Vector{X: 0.2, Z: 0.4}, 0, 0), test.ShouldAlmostEqual, 0, 1e-9)
+	test.That(t, TiltCompensatedHeading(r3.Vector{Y: -0.2, Z: 0.4}, 0, 0), test.ShouldAlmostEqual, 90, 1e-9)
+
+	// facing east with the nose pitched up, so part of the downward field shows up along x
+	pitch := math.Pi / 6
+	field := r3.Vector{X: -0.4 * math.Sin(pitch), Y: -0.2, Z: 0.4 * math.Cos(pitch)}
+	test.That(t, TiltCompensatedHeading(field, 0, pitch), test.ShouldAlmostEqual, 90, 1e-9)
+
+	// hard-iron offsets are removed before the heading is computed
+	cal := &IMUCalibration{Magnetometer: &MagnetometerCalibration{HardIron: r3.Vector{X: 1}, SoftIron: r3.Vector{X: 1, Y: 1, Z: 1}}}
+	test.That(t, TiltCompensatedHeading(cal.ApplyMagnetometer(r3.Vector{X: 1, Y: 0.2, Z: 0.4}), 0, 0), test.ShouldAlmostEqual, 270, 1e-9)
+}
@@ -166,7 +166,13 @@ func (c *client) Properties(ctx context.Context, extra map[string]interface{}) (
 	if err != nil {
 		return nil, err
 	}
-	return (*Properties)(resp), nil
+	props := ProtoFeaturesToProperties(resp)
+	// servers that predate PropertiesDetailsCommand pass it on to the sensor, which will not know it, so the
+	// details are left out rather than failing
+	if details, err := c.DoCommand(ctx, map[string]interface{}{PropertiesDetailsCommand: true}); err == nil {
+		addPropertiesDetails(props, details)
+	}
+	return props, nil
 }
 
 func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
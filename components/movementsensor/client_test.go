@@ -41,7 +41,11 @@ func TestClient(t *testing.T) {
 	ori := spatialmath.NewEulerAngles()
 	ori.Roll = 1.1
 	heading := 202.
-	props := &movementsensor.Properties{LinearVelocitySupported: true}
+	props := &movementsensor.Properties{
+		LinearVelocitySupported: true,
+		FixType:                 "rtk_fixed",
+		Settings:                &movementsensor.SensorSettings{SampleRateHz: 100, AccelRangeG: 4},
+	}
 	aclZ := 1.0
 	acy := map[string]float32{"x": 1.1}
 	rs := map[string]interface{}{
@@ -162,6 +166,8 @@ func TestClient(t *testing.T) {
 		props1, err := gps1Client.Properties(context.Background(), map[string]interface{}{"foo": "bar"})
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, props1.LinearVelocitySupported, test.ShouldResemble, props.LinearVelocitySupported)
+		test.That(t, props1.FixType, test.ShouldEqual, "rtk_fixed")
+		test.That(t, props1.Settings, test.ShouldResemble, props.Settings)
 		test.That(t, injectMovementSensor.PropertiesFuncExtraCap, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
 
 		acc1, err := gps1Client.Accuracy(context.Background(), map[string]interface{}{"foo": "bar"})
@@ -0,0 +1,90 @@
+package fusion
+
+import (
+	"math"
+
+	geo "github.com/kellydunn/golang-geo"
+
+	"go.viam.com/rdk/utils"
+)
+
+// complementaryFilter fuses a heading and a planar position out of relative
+// measurements (gyro yaw rate, wheel odometry) that are smooth but drift and
+// absolute measurements (compass, GPS) that are noisy but don't. Positions are
+// tracked in meters east and north of the first GPS fix.
+type complementaryFilter struct {
+	// headingGyroWeight is how much the integrated gyro is trusted over an
+	// absolute heading, in [0, 1].
+	headingGyroWeight float64
+	// positionOdometryWeight is how much dead reckoning is trusted over GPS, in [0, 1].
+	positionOdometryWeight float64
+
+	headingKnown bool
+	heading      float64 // degrees, [0, 360) with 0 north and 90 east
+
+	origin      *geo.Point
+	east, north float64
+}
+
+func newComplementaryFilter(headingGyroWeight, positionOdometryWeight float64) *complementaryFilter {
+	return &complementaryFilter{
+		headingGyroWeight:      headingGyroWeight,
+		positionOdometryWeight: positionOdometryWeight,
+	}
+}
+
+// predict advances the state by dt seconds given the yaw rate around the up axis
+// in degrees/sec (counterclockwise positive, as reported by movement sensors) and
+// the velocity in the robot's frame in m/sec, Y forward and X right.
+func (f *complementaryFilter) predict(dt, yawRate, forward, right float64) {
+	f.heading = normalizeHeading(f.heading - yawRate*dt)
+
+	h := utils.DegToRad(f.heading)
+	f.east += (forward*math.Sin(h) + right*math.Cos(h)) * dt
+	f.north += (forward*math.Cos(h) - right*math.Sin(h)) * dt
+}
+
+// correctHeading blends an absolute compass heading in degrees into the state.
+func (f *complementaryFilter) correctHeading(measured float64) {
+	measured = normalizeHeading(measured)
+	if !f.headingKnown {
+		f.heading = measured
+		f.headingKnown = true
+		return
+	}
+	// blend along the shortest way around the circle
+	diff := math.Mod(measured-f.heading+540, 360) - 180
+	f.heading = normalizeHeading(f.heading + (1-f.headingGyroWeight)*diff)
+}
+
+// correctPosition blends a GPS fix into the state. The first fix becomes the origin.
+func (f *complementaryFilter) correctPosition(p *geo.Point) {
+	if f.origin == nil {
+		f.origin = p
+		f.east, f.north = 0, 0
+		return
+	}
+	distM := f.origin.GreatCircleDistance(p) * 1000
+	bearing := utils.DegToRad(f.origin.BearingTo(p))
+	w := f.positionOdometryWeight
+	f.east = w*f.east + (1-w)*distM*math.Sin(bearing)
+	f.north = w*f.north + (1-w)*distM*math.Cos(bearing)
+}
+
+// position returns the fused position, or nil if there has never been a GPS fix.
+func (f *complementaryFilter) position() *geo.Point {
+	if f.origin == nil {
+		return nil
+	}
+	distKm := math.Hypot(f.east, f.north) / 1000
+	bearing := utils.RadToDeg(math.Atan2(f.east, f.north))
+	return f.origin.PointAtDistanceAndBearing(distKm, bearing)
+}
+
+func normalizeHeading(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
@@ -0,0 +1,49 @@
+package fusion
+
+import (
+	"testing"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+)
+
+func TestFilterHeading(t *testing.T) {
+	f := newComplementaryFilter(0.5, 1)
+
+	// turning counterclockwise at 90 deg/sec for a second ends up facing west
+	f.predict(1, 90, 0, 0)
+	test.That(t, f.heading, test.ShouldAlmostEqual, 270)
+
+	// the first absolute heading is taken as is
+	f.correctHeading(10)
+	test.That(t, f.heading, test.ShouldAlmostEqual, 10)
+
+	// later ones are blended the short way around the circle
+	f.correctHeading(350)
+	test.That(t, f.heading, test.ShouldAlmostEqual, 0)
+}
+
+func TestFilterPosition(t *testing.T) {
+	f := newComplementaryFilter(1, 0.5)
+	test.That(t, f.position(), test.ShouldBeNil)
+
+	origin := geo.NewPoint(40, -74)
+	f.correctPosition(origin)
+	test.That(t, f.position().Lat(), test.ShouldAlmostEqual, 40)
+	test.That(t, f.position().Lng(), test.ShouldAlmostEqual, -74)
+
+	// drive north at 10 m/s for 10 seconds
+	f.predict(10, 0, 10, 0)
+	test.That(t, f.north, test.ShouldAlmostEqual, 100)
+	test.That(t, f.east, test.ShouldAlmostEqual, 0)
+	test.That(t, origin.GreatCircleDistance(f.position())*1000, test.ShouldAlmostEqual, 100, 1e-3)
+
+	// a gps fix 200m north pulls the estimate halfway there
+	f.correctPosition(origin.PointAtDistanceAndBearing(0.2, 0))
+	test.That(t, f.north, test.ShouldAlmostEqual, 150, 1e-3)
+
+	// facing east, strafing right moves south
+	f.heading = 90
+	f.predict(1, 0, 0, 10)
+	test.That(t, f.north, test.ShouldAlmostEqual, 140, 1e-3)
+}
@@ -0,0 +1,319 @@
+// Package fusion implements a movement sensor that fuses an IMU, wheel odometry
+// and optionally a GPS into a single pose and velocity estimate using a
+// complementary filter.
+package fusion
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+var model = resource.DefaultModelFamily.WithModel("fusion")
+
+const (
+	defaultRateHz                 = 20
+	defaultHeadingGyroWeight      = 0.98
+	defaultPositionOdometryWeight = 0.9
+)
+
+// Config is used for converting config attributes of a fusion movement sensor.
+type Config struct {
+	IMU      string `json:"imu"`
+	Odometry string `json:"odometry,omitempty"`
+	GPS      string `json:"gps,omitempty"`
+
+	RateHz                 float64  `json:"rate_hz,omitempty"`
+	HeadingGyroWeight      *float64 `json:"heading_gyro_weight,omitempty"`
+	PositionOdometryWeight *float64 `json:"position_odometry_weight,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.IMU == "" {
+		return nil, utils.NewConfigValidationFieldRequiredError(path, "imu")
+	}
+	deps := []string{cfg.IMU}
+	if cfg.Odometry != "" {
+		deps = append(deps, cfg.Odometry)
+	}
+	if cfg.GPS != "" {
+		deps = append(deps, cfg.GPS)
+	}
+
+	if cfg.RateHz < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("rate_hz cannot be negative"))
+	}
+	for name, w := range map[string]*float64{
+		"heading_gyro_weight":      cfg.HeadingGyroWeight,
+		"position_odometry_weight": cfg.PositionOdometryWeight,
+	} {
+		if w != nil && (*w < 0 || *w > 1) {
+			return nil, utils.NewConfigValidationError(path, errors.Errorf("%s must be between 0 and 1", name))
+		}
+	}
+	return deps, nil
+}
+
+func init() {
+	resource.RegisterComponent(
+		movementsensor.API,
+		model,
+		resource.Registration[movementsensor.MovementSensor, *Config]{
+			Constructor: newFusion,
+		})
+}
+
+type fusion struct {
+	resource.Named
+	resource.AlwaysRebuild
+	logger golog.Logger
+
+	imu      movementsensor.MovementSensor
+	odometry movementsensor.MovementSensor
+	gps      movementsensor.MovementSensor
+
+	imuHasCompass bool
+	period        time.Duration
+
+	mu       sync.Mutex
+	filter   *complementaryFilter
+	velocity r3.Vector
+	altitude float64
+	err      movementsensor.LastError
+
+	cancelFunc              func()
+	activeBackgroundWorkers sync.WaitGroup
+}
+
+func newFusion(
+	ctx context.Context,
+	deps resource.Dependencies,
+	conf resource.Config,
+	logger golog.Logger,
+) (movementsensor.MovementSensor, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &fusion{
+		Named:  conf.ResourceName().AsNamed(),
+		logger: logger,
+		err:    movementsensor.NewLastError(5, 5),
+	}
+
+	if f.imu, err = movementsensor.FromDependencies(deps, newConf.IMU); err != nil {
+		return nil, err
+	}
+	if newConf.Odometry != "" {
+		if f.odometry, err = movementsensor.FromDependencies(deps, newConf.Odometry); err != nil {
+			return nil, err
+		}
+	}
+	if newConf.GPS != "" {
+		if f.gps, err = movementsensor.FromDependencies(deps, newConf.GPS); err != nil {
+			return nil, err
+		}
+	}
+
+	props, err := f.imu.Properties(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !props.AngularVelocitySupported {
+		return nil, errors.Errorf("imu %q must support angular velocity", newConf.IMU)
+	}
+	f.imuHasCompass = props.CompassHeadingSupported
+
+	rate := newConf.RateHz
+	if rate == 0 {
+		rate = defaultRateHz
+	}
+	f.period = time.Duration(float64(time.Second) / rate)
+
+	headingWeight := defaultHeadingGyroWeight
+	if newConf.HeadingGyroWeight != nil {
+		headingWeight = *newConf.HeadingGyroWeight
+	}
+	positionWeight := defaultPositionOdometryWeight
+	if newConf.PositionOdometryWeight != nil {
+		positionWeight = *newConf.PositionOdometryWeight
+	}
+	f.filter = newComplementaryFilter(headingWeight, positionWeight)
+
+	cancelCtx, cancelFunc := context.WithCancel(context.Background())
+	f.cancelFunc = cancelFunc
+	f.activeBackgroundWorkers.Add(1)
+	utils.ManagedGo(func() {
+		f.run(cancelCtx)
+	}, f.activeBackgroundWorkers.Done)
+
+	return f, nil
+}
+
+func (f *fusion) run(ctx context.Context) {
+	last := time.Now()
+	for {
+		if !utils.SelectContextOrWait(ctx, f.period) {
+			return
+		}
+		now := time.Now()
+		integrated, err := f.step(ctx, now.Sub(last).Seconds())
+		f.err.Set(err)
+		// a step that could not integrate leaves its interval to the next one
+		if integrated {
+			last = now
+		}
+	}
+}
+
+// step reads every source once and advances the filter by dt seconds. The
+// interval is only integrated if the gyro could be read, and a failing compass,
+// odometry or GPS only skips its own part, so no interval of motion is lost.
+// It returns whether the interval was integrated along with any source errors.
+func (f *fusion) step(ctx context.Context, dt float64) (bool, error) {
+	angVel, err := f.imu.AngularVelocity(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	var errs error
+	var (
+		heading    float64
+		headingErr error
+	)
+	if f.imuHasCompass {
+		heading, headingErr = f.imu.CompassHeading(ctx, nil)
+		errs = multierr.Combine(errs, headingErr)
+	}
+	var (
+		vel    r3.Vector
+		velErr error
+	)
+	if f.odometry != nil {
+		vel, velErr = f.odometry.LinearVelocity(ctx, nil)
+		errs = multierr.Combine(errs, velErr)
+	}
+	var (
+		fix      *geo.Point
+		altitude float64
+	)
+	if f.gps != nil {
+		var gpsErr error
+		if fix, altitude, gpsErr = f.gps.Position(ctx, nil); gpsErr != nil {
+			fix = nil
+			errs = multierr.Combine(errs, gpsErr)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// dead reckon with the last velocity if odometry could not be read
+	if velErr != nil {
+		vel = f.velocity
+	}
+	f.filter.predict(dt, angVel.Z, vel.Y, vel.X)
+	if f.imuHasCompass && headingErr == nil {
+		f.filter.correctHeading(heading)
+	}
+	if fix != nil {
+		f.filter.correctPosition(fix)
+		f.altitude = altitude
+	}
+	f.velocity = vel
+	return true, errs
+}
+
+// Position returns the fused position. It requires a GPS to be configured.
+func (f *fusion) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+	if f.gps == nil {
+		return geo.NewPoint(0, 0), 0, movementsensor.ErrMethodUnimplementedPosition
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := f.filter.position()
+	if p == nil {
+		return geo.NewPoint(0, 0), 0, errors.New("no gps fix yet")
+	}
+	return p, f.altitude, f.err.Get()
+}
+
+// LinearVelocity returns the velocity reported by odometry, in the robot's frame.
+func (f *fusion) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	if f.odometry == nil {
+		return r3.Vector{}, movementsensor.ErrMethodUnimplementedLinearVelocity
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.velocity, f.err.Get()
+}
+
+// AngularVelocity returns the angular velocity of the IMU.
+func (f *fusion) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+	return f.imu.AngularVelocity(ctx, extra)
+}
+
+// LinearAcceleration returns the linear acceleration of the IMU.
+func (f *fusion) LinearAcceleration(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	return f.imu.LinearAcceleration(ctx, extra)
+}
+
+// CompassHeading returns the fused heading. It requires the IMU to have a compass, since the gyro alone only
+// tracks changes of heading.
+func (f *fusion) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	if !f.imuHasCompass {
+		return 0, movementsensor.ErrMethodUnimplementedCompassHeading
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.filter.heading, f.err.Get()
+}
+
+// Orientation returns the orientation of the IMU.
+func (f *fusion) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	return f.imu.Orientation(ctx, extra)
+}
+
+// Readings returns all of the fused readings.
+func (f *fusion) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	return movementsensor.Readings(ctx, f, extra)
+}
+
+// Accuracy is unimplemented.
+func (f *fusion) Accuracy(ctx context.Context, extra map[string]interface{}) (map[string]float32, error) {
+	return map[string]float32{}, movementsensor.ErrMethodUnimplementedAccuracy
+}
+
+// Properties returns what the fused sensor supports given the configured sources.
+func (f *fusion) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+	imuProps, err := f.imu.Properties(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	return &movementsensor.Properties{
+		PositionSupported:           f.gps != nil,
+		LinearVelocitySupported:     f.odometry != nil,
+		AngularVelocitySupported:    true,
+		CompassHeadingSupported:     f.imuHasCompass,
+		OrientationSupported:        imuProps.OrientationSupported,
+		LinearAccelerationSupported: imuProps.LinearAccelerationSupported,
+	}, nil
+}
+
+// Close stops the fusion loop.
+func (f *fusion) Close(ctx context.Context) error {
+	f.cancelFunc()
+	f.activeBackgroundWorkers.Wait()
+	return nil
+}
@@ -0,0 +1,149 @@
+package fusion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestValidate(t *testing.T) {
+	cfg := &Config{}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `"imu" is required`)
+
+	bad := 1.5
+	cfg = &Config{IMU: "imu", HeadingGyroWeight: &bad}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cfg = &Config{IMU: "imu", Odometry: "odom", GPS: "gps"}
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"imu", "odom", "gps"})
+}
+
+func TestFusion(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+
+	imu := inject.NewMovementSensor("imu")
+	imu.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+		return &movementsensor.Properties{AngularVelocitySupported: true, CompassHeadingSupported: true}, nil
+	}
+	imu.AngularVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+		return spatialmath.AngularVelocity{}, nil
+	}
+	imu.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		return 45, nil
+	}
+
+	odom := inject.NewMovementSensor("odom")
+	odom.LinearVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+		return r3.Vector{Y: 1}, nil
+	}
+
+	gps := inject.NewMovementSensor("gps")
+	gps.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		return geo.NewPoint(40, -74), 12, nil
+	}
+
+	deps := resource.Dependencies{
+		imu.Name():  imu,
+		odom.Name(): odom,
+		gps.Name():  gps,
+	}
+	conf := resource.Config{
+		Name:                "fused",
+		ConvertedAttributes: &Config{IMU: "imu", Odometry: "odom", GPS: "gps", RateHz: 100},
+	}
+	ms, err := newFusion(ctx, deps, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, ms.Close(ctx), test.ShouldBeNil)
+	}()
+
+	testutils.WaitForAssertionWithSleep(t, 10*time.Millisecond, 100, func(tb testing.TB) {
+		tb.Helper()
+		heading, err := ms.CompassHeading(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, heading, test.ShouldAlmostEqual, 45)
+
+		pos, alt, err := ms.Position(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, pos, test.ShouldNotBeNil)
+		test.That(tb, alt, test.ShouldEqual, 12)
+	})
+
+	vel, err := ms.LinearVelocity(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, vel, test.ShouldResemble, r3.Vector{Y: 1})
+
+	props, err := ms.Properties(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props.PositionSupported, test.ShouldBeTrue)
+	test.That(t, props.LinearVelocitySupported, test.ShouldBeTrue)
+	test.That(t, props.OrientationSupported, test.ShouldBeFalse)
+}
+
+func TestFusionStep(t *testing.T) {
+	ctx := context.Background()
+
+	gyroErr := errors.New("gyro")
+	var failGyro, failCompass bool
+	imu := inject.NewMovementSensor("imu")
+	imu.AngularVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+		if failGyro {
+			return spatialmath.AngularVelocity{}, gyroErr
+		}
+		return spatialmath.AngularVelocity{Z: -10}, nil
+	}
+	imu.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		if failCompass {
+			return 0, errors.New("compass")
+		}
+		return 0, nil
+	}
+	imu.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+		return &movementsensor.Properties{AngularVelocitySupported: true}, nil
+	}
+	f := &fusion{imu: imu, imuHasCompass: true, filter: newComplementaryFilter(1, 1)}
+
+	integrated, err := f.step(ctx, 1)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, integrated, test.ShouldBeTrue)
+	test.That(t, f.filter.heading, test.ShouldAlmostEqual, 0)
+
+	// a failing gyro leaves the interval to the next step
+	failGyro = true
+	integrated, err = f.step(ctx, 1)
+	test.That(t, err, test.ShouldBeError, gyroErr)
+	test.That(t, integrated, test.ShouldBeFalse)
+
+	// a failing compass still integrates the gyro
+	failGyro, failCompass = false, true
+	integrated, err = f.step(ctx, 2)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, integrated, test.ShouldBeTrue)
+	test.That(t, f.filter.heading, test.ShouldAlmostEqual, 20)
+
+	// without a compass there is no absolute heading to report
+	f.imuHasCompass = false
+	_, err = f.CompassHeading(ctx, nil)
+	test.That(t, err, test.ShouldBeError, movementsensor.ErrMethodUnimplementedCompassHeading)
+	props, err := f.Properties(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props.CompassHeadingSupported, test.ShouldBeFalse)
+}
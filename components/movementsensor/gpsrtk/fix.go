@@ -0,0 +1,36 @@
+package gpsrtk
+
+// The fix qualities a GPS reports in its GGA sentences. An RTK rover should
+// reach fixRTKFloat once it starts receiving corrections and fixRTKFixed once
+// the carrier phase ambiguities are resolved.
+const (
+	fixInvalid       = 0
+	fixGPS           = 1
+	fixDGPS          = 2
+	fixPPS           = 3
+	fixRTKFixed      = 4
+	fixRTKFloat      = 5
+	fixDeadReckoning = 6
+)
+
+// FixType returns a human readable name for the given GGA fix quality.
+func FixType(fix int) string {
+	switch fix {
+	case fixInvalid:
+		return "invalid"
+	case fixGPS:
+		return "gps"
+	case fixDGPS:
+		return "dgps"
+	case fixPPS:
+		return "pps"
+	case fixRTKFixed:
+		return "rtk_fixed"
+	case fixRTKFloat:
+		return "rtk_float"
+	case fixDeadReckoning:
+		return "dead_reckoning"
+	default:
+		return "unknown"
+	}
+}
@@ -659,14 +659,23 @@ func (g *RTKMovementSensor) ReadFix(ctx context.Context) (int, error) {
 	return g.nmeamovementsensor.ReadFix(ctx)
 }
 
-// Properties passthrough.
+// Properties passthrough, with the fix type so that clients can tell an RTK float fix from a fixed one.
 func (g *RTKMovementSensor) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
 	lastError := g.err.Get()
 	if lastError != nil {
 		return &movementsensor.Properties{}, lastError
 	}
 
-	return g.nmeamovementsensor.Properties(ctx, extra)
+	props, err := g.nmeamovementsensor.Properties(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	fix, err := g.nmeamovementsensor.ReadFix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	props.FixType = FixType(fix)
+	return props, nil
 }
 
 // Accuracy passthrough.
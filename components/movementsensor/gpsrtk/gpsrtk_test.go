@@ -275,6 +275,21 @@ func TestReadingsRTK(t *testing.T) {
 	fix1, err := g.ReadFix(ctx)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, fix1, test.ShouldEqual, fix)
+
+	readings, err := g.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings["fix"], test.ShouldEqual, fix)
+
+	props, err := g.Properties(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props.PositionSupported, test.ShouldBeTrue)
+	test.That(t, props.FixType, test.ShouldEqual, "gps")
+}
+
+func TestFixType(t *testing.T) {
+	test.That(t, FixType(4), test.ShouldEqual, "rtk_fixed")
+	test.That(t, FixType(5), test.ShouldEqual, "rtk_float")
+	test.That(t, FixType(42), test.ShouldEqual, "unknown")
 }
 
 func TestCloseRTK(t *testing.T) {
@@ -28,6 +28,7 @@ import (
 	"io"
 	"math"
 	"sync"
+	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/golang/geo/r3"
@@ -50,6 +51,12 @@ var baudRateList = [...]uint{115200, 9600, 0}
 type Config struct {
 	Port     string `json:"serial_path"`
 	BaudRate uint   `json:"serial_baud_rate,omitempty"`
+
+	// Calibration is typically the output of the "calibrate_gyro" and
+	// "calibrate_magnetometer" DoCommands. If it is left out, the calibration last
+	// saved to CalibrationFile is used.
+	Calibration     *movementsensor.IMUCalibration `json:"calibration,omitempty"`
+	CalibrationFile string                         `json:"calibration_file,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -87,6 +94,8 @@ type wit struct {
 	acceleration    r3.Vector
 	magnetometer    r3.Vector
 	numBadReadings  uint32
+	calibration     *movementsensor.IMUCalibration
+	calibrationPath string
 	err             movementsensor.LastError
 
 	mu sync.Mutex
@@ -100,7 +109,7 @@ type wit struct {
 func (imu *wit) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
 	imu.mu.Lock()
 	defer imu.mu.Unlock()
-	return imu.angularVelocity, imu.err.Get()
+	return imu.calibration.ApplyGyro(imu.angularVelocity), imu.err.Get()
 }
 
 func (imu *wit) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
@@ -126,11 +135,75 @@ func (imu *wit) LinearAcceleration(ctx context.Context, extra map[string]interfa
 func (imu *wit) GetMagnetometer(ctx context.Context) (r3.Vector, error) {
 	imu.mu.Lock()
 	defer imu.mu.Unlock()
-	return imu.magnetometer, imu.err.Get()
+	return imu.calibration.ApplyMagnetometer(imu.magnetometer), imu.err.Get()
+}
+
+// DoCommand supports "calibrate_gyro", which estimates the gyro bias while the
+// sensor is held still, and "calibrate_magnetometer", which fits hard- and
+// soft-iron corrections while the sensor is rotated through every orientation.
+// The results are applied to subsequent readings, saved to the calibration file
+// and returned.
+func (imu *wit) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if args, ok := cmd[movementsensor.CalibrateGyroCommand]; ok {
+		duration := movementsensor.CalibrationDuration(args, 2*time.Second)
+		samples, err := movementsensor.CollectSamples(ctx, duration, 10*time.Millisecond,
+			func() (spatialmath.AngularVelocity, error) {
+				imu.mu.Lock()
+				defer imu.mu.Unlock()
+				return imu.angularVelocity, imu.err.Get()
+			})
+		if err != nil {
+			return nil, err
+		}
+		bias, err := movementsensor.EstimateGyroBias(samples)
+		if err != nil {
+			return nil, err
+		}
+		return imu.updateCalibration(func(c *movementsensor.IMUCalibration) { c.GyroBias = &bias })
+	}
+
+	if args, ok := cmd[movementsensor.CalibrateMagnetometerCommand]; ok {
+		duration := movementsensor.CalibrationDuration(args, 30*time.Second)
+		samples, err := movementsensor.CollectSamples(ctx, duration, 20*time.Millisecond,
+			func() (r3.Vector, error) {
+				imu.mu.Lock()
+				defer imu.mu.Unlock()
+				return imu.magnetometer, imu.err.Get()
+			})
+		if err != nil {
+			return nil, err
+		}
+		magCalibration, err := movementsensor.FitMagnetometerCalibration(samples)
+		if err != nil {
+			return nil, err
+		}
+		return imu.updateCalibration(func(c *movementsensor.IMUCalibration) { c.Magnetometer = magCalibration })
+	}
+
+	return nil, resource.ErrDoUnimplemented
+}
+
+func (imu *wit) updateCalibration(update func(c *movementsensor.IMUCalibration)) (map[string]interface{}, error) {
+	imu.mu.Lock()
+	defer imu.mu.Unlock()
+	calibration := movementsensor.IMUCalibration{}
+	if imu.calibration != nil {
+		calibration = *imu.calibration
+	}
+	update(&calibration)
+	imu.calibration = &calibration
+	if err := calibration.Save(imu.calibrationPath); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"calibration": calibration.ToMap()}, nil
 }
 
+// CompassHeading is computed from the calibrated magnetometer, tilt compensated with the roll and pitch of the imu.
 func (imu *wit) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
-	return 0, movementsensor.ErrMethodUnimplementedCompassHeading
+	imu.mu.Lock()
+	defer imu.mu.Unlock()
+	field := imu.calibration.ApplyMagnetometer(imu.magnetometer)
+	return movementsensor.TiltCompensatedHeading(field, imu.orientation.Roll, imu.orientation.Pitch), imu.err.Get()
 }
 
 func (imu *wit) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
@@ -161,6 +234,7 @@ func (imu *wit) Properties(ctx context.Context, extra map[string]interface{}) (*
 		AngularVelocitySupported:    true,
 		OrientationSupported:        true,
 		LinearAccelerationSupported: true,
+		CompassHeadingSupported:     true,
 	}, nil
 }
 
@@ -192,10 +266,18 @@ func NewWit(
 		)
 	}
 
+	calibrationPath := movementsensor.CalibrationPath(newConf.CalibrationFile, conf.ResourceName().ShortName())
+	calibration, err := movementsensor.LoadCalibration(newConf.Calibration, calibrationPath)
+	if err != nil {
+		return nil, err
+	}
+
 	i := wit{
-		Named:  conf.ResourceName().AsNamed(),
-		logger: logger,
-		err:    movementsensor.NewLastError(1, 1),
+		Named:           conf.ResourceName().AsNamed(),
+		logger:          logger,
+		calibration:     calibration,
+		calibrationPath: calibrationPath,
+		err:             movementsensor.NewLastError(1, 1),
 	}
 	logger.Debugf("initializing wit serial connection with parameters: %+v", options)
 	i.port, err = slib.Open(options)
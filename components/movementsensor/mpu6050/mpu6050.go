@@ -50,6 +50,18 @@ type Config struct {
 	BoardName              string `json:"board"`
 	I2cBus                 string `json:"i2c_bus"`
 	UseAlternateI2CAddress bool   `json:"use_alt_i2c_address,omitempty"`
+
+	// Sampling settings. Any that are left unset use the chip's power-on defaults:
+	// an 8kHz gyro rate with the low pass filter disabled, +/-250 deg/sec and +/-2g.
+	SampleRateHz       float64 `json:"sample_rate_hz,omitempty"`
+	LowPassBandwidthHz int     `json:"low_pass_bandwidth_hz,omitempty"`
+	GyroRangeDegPerSec int     `json:"gyro_range_deg_per_sec,omitempty"`
+	AccelRangeG        int     `json:"accel_range_g,omitempty"`
+
+	// Calibration is typically the output of the "calibrate_gyro" DoCommand. If it
+	// is left out, the calibration last saved to CalibrationFile is used.
+	Calibration     *movementsensor.IMUCalibration `json:"calibration,omitempty"`
+	CalibrationFile string                         `json:"calibration_file,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid, and then returns the list of things we
@@ -61,6 +73,9 @@ func (conf *Config) Validate(path string) ([]string, error) {
 	if conf.I2cBus == "" {
 		return nil, utils.NewConfigValidationFieldRequiredError(path, "i2c_bus")
 	}
+	if _, err := newChipSettings(conf); err != nil {
+		return nil, utils.NewConfigValidationError(path, err)
+	}
 
 	var deps []string
 	deps = append(deps, conf.BoardName)
@@ -78,6 +93,7 @@ type mpu6050 struct {
 	resource.AlwaysRebuild
 	bus        board.I2C
 	i2cAddress byte
+	settings   chipSettings
 	mu         sync.Mutex
 
 	// The 3 things we can measure: lock the mutex before reading or writing these.
@@ -87,6 +103,10 @@ type mpu6050 struct {
 	// Stores the most recent error from the background goroutine
 	err movementsensor.LastError
 
+	// Lock the mutex before reading or writing this too.
+	calibration     *movementsensor.IMUCalibration
+	calibrationPath string
+
 	// Used to shut down the background goroutine which polls the sensor.
 	backgroundContext       context.Context
 	cancelFunc              func()
@@ -138,14 +158,28 @@ func NewMpu6050(
 	}
 	logger.Debugf("Using address %d for MPU6050 sensor", address)
 
+	settings, err := newChipSettings(newConf)
+	if err != nil {
+		return nil, err
+	}
+
+	calibrationPath := movementsensor.CalibrationPath(newConf.CalibrationFile, conf.ResourceName().ShortName())
+	calibration, err := movementsensor.LoadCalibration(newConf.Calibration, calibrationPath)
+	if err != nil {
+		return nil, err
+	}
+
 	backgroundContext, cancelFunc := context.WithCancel(ctx)
 	sensor := &mpu6050{
 		Named:             conf.ResourceName().AsNamed(),
 		bus:               bus,
 		i2cAddress:        address,
+		settings:          settings,
 		logger:            logger,
 		backgroundContext: backgroundContext,
 		cancelFunc:        cancelFunc,
+		calibration:       calibration,
+		calibrationPath:   calibrationPath,
 		// On overloaded boards, the I2C bus can become flaky. Only report errors if at least 5 of
 		// the last 10 attempts to talk to the device have failed.
 		err: movementsensor.NewLastError(10, 5),
@@ -169,13 +203,19 @@ func NewMpu6050(
 		return nil, errors.Errorf("Unable to wake up MPU6050: '%s'", err.Error())
 	}
 
+	for register, value := range settings.registerValues() {
+		if err := sensor.writeByte(ctx, register, value); err != nil {
+			return nil, errors.Wrapf(err, "unable to configure MPU6050 register %d", register)
+		}
+	}
+
 	// Now, turn on the background goroutine that constantly reads from the chip and stores data in
 	// the object we created.
 	sensor.activeBackgroundWorkers.Add(1)
 	utils.PanicCapturingGo(func() {
 		defer sensor.activeBackgroundWorkers.Done()
-		// Reading data a thousand times per second is probably fast enough.
-		timer := time.NewTicker(time.Millisecond)
+		// Read as often as the chip produces new data, up to a thousand times per second.
+		timer := time.NewTicker(sensor.settings.pollPeriod())
 		defer timer.Stop()
 
 		for {
@@ -189,10 +229,10 @@ func NewMpu6050(
 					continue
 				}
 
-				linearAcceleration := toLinearAcceleration(rawData[0:6])
+				linearAcceleration := toLinearAcceleration(rawData[0:6], sensor.settings.accelRangeG)
 				// Taken straight from the MPU6050 register map. Yes, these are weird constants.
 				temperature := float64(rutils.Int16FromBytesBE(rawData[6:8]))/340.0 + 36.53
-				angularVelocity := toAngularVelocity(rawData[8:14])
+				angularVelocity := toAngularVelocity(rawData[8:14], sensor.settings.gyroRangeDegPerSec)
 
 				// Lock the mutex before modifying the state within the object. By keeping the mutex
 				// unlocked for everything else, we maximize the time when another thread can read the
@@ -256,13 +296,13 @@ func setScale(value int, maxValue float64) float64 {
 }
 
 // A helper function to abstract out shared code: takes 6 bytes and gives back AngularVelocity, in
-// radians per second.
-func toAngularVelocity(data []byte) spatialmath.AngularVelocity {
+// degrees per second, given the configured range.
+func toAngularVelocity(data []byte, rangeDegPerSec int) spatialmath.AngularVelocity {
 	gx := int(rutils.Int16FromBytesBE(data[0:2]))
 	gy := int(rutils.Int16FromBytesBE(data[2:4]))
 	gz := int(rutils.Int16FromBytesBE(data[4:6]))
 
-	maxRotation := 250.0 // Maximum degrees per second measurable in the default configuration
+	maxRotation := float64(rangeDegPerSec) // Maximum degrees per second measurable
 	return spatialmath.AngularVelocity{
 		X: setScale(gx, maxRotation),
 		Y: setScale(gy, maxRotation),
@@ -270,14 +310,15 @@ func toAngularVelocity(data []byte) spatialmath.AngularVelocity {
 	}
 }
 
-// A helper function that takes 6 bytes and gives back linear acceleration.
-func toLinearAcceleration(data []byte) r3.Vector {
+// A helper function that takes 6 bytes and gives back linear acceleration, given the configured
+// range in g's.
+func toLinearAcceleration(data []byte, rangeG int) r3.Vector {
 	x := int(rutils.Int16FromBytesBE(data[0:2]))
 	y := int(rutils.Int16FromBytesBE(data[2:4]))
 	z := int(rutils.Int16FromBytesBE(data[4:6]))
 
-	// The scale is +/- 2G's, but our units should be m/sec/sec.
-	maxAcceleration := 2.0 * 9.81 /* m/sec/sec */
+	// The scale is in G's, but our units should be m/sec/sec.
+	maxAcceleration := float64(rangeG) * 9.81 /* m/sec/sec */
 	return r3.Vector{
 		X: setScale(x, maxAcceleration),
 		Y: setScale(y, maxAcceleration),
@@ -288,7 +329,45 @@ func toLinearAcceleration(data []byte) r3.Vector {
 func (mpu *mpu6050) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
 	mpu.mu.Lock()
 	defer mpu.mu.Unlock()
-	return mpu.angularVelocity, mpu.err.Get()
+	return mpu.calibration.ApplyGyro(mpu.angularVelocity), mpu.err.Get()
+}
+
+// DoCommand supports "calibrate_gyro", which estimates the gyro bias while the
+// sensor is held still, applies it to subsequent readings and saves it to the
+// calibration file.
+func (mpu *mpu6050) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	args, ok := cmd[movementsensor.CalibrateGyroCommand]
+	if !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+
+	duration := movementsensor.CalibrationDuration(args, 2*time.Second)
+	samples, err := movementsensor.CollectSamples(ctx, duration, 10*time.Millisecond,
+		func() (spatialmath.AngularVelocity, error) {
+			mpu.mu.Lock()
+			defer mpu.mu.Unlock()
+			return mpu.angularVelocity, mpu.err.Get()
+		})
+	if err != nil {
+		return nil, err
+	}
+	bias, err := movementsensor.EstimateGyroBias(samples)
+	if err != nil {
+		return nil, err
+	}
+
+	mpu.mu.Lock()
+	defer mpu.mu.Unlock()
+	calibration := movementsensor.IMUCalibration{}
+	if mpu.calibration != nil {
+		calibration = *mpu.calibration
+	}
+	calibration.GyroBias = &bias
+	mpu.calibration = &calibration
+	if err := calibration.Save(mpu.calibrationPath); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"calibration": calibration.ToMap()}, nil
 }
 
 func (mpu *mpu6050) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
@@ -329,15 +408,17 @@ func (mpu *mpu6050) Readings(ctx context.Context, extra map[string]interface{})
 	readings := make(map[string]interface{})
 	readings["linear_acceleration"] = mpu.linearAcceleration
 	readings["temperature_celsius"] = mpu.temperature
-	readings["angular_velocity"] = mpu.angularVelocity
+	readings["angular_velocity"] = mpu.calibration.ApplyGyro(mpu.angularVelocity)
 
 	return readings, mpu.err.Get()
 }
 
 func (mpu *mpu6050) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+	settings := mpu.settings.sensorSettings()
 	return &movementsensor.Properties{
 		AngularVelocitySupported:    true,
 		LinearAccelerationSupported: true,
+		Settings:                    &settings,
 	}, nil
 }
 
@@ -3,6 +3,7 @@ package mpu6050
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/pkg/errors"
@@ -49,6 +50,52 @@ func TestValidateConfig(t *testing.T) {
 		test.That(t, len(deps), test.ShouldEqual, 1)
 		test.That(t, deps[0], test.ShouldResemble, boardName)
 	})
+
+	t.Run("fails with unsupported sampling settings", func(t *testing.T) {
+		cfg := Config{
+			BoardName:   boardName,
+			I2cBus:      "thing2",
+			AccelRangeG: 3,
+		}
+		_, err := cfg.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "accel_range_g")
+
+		cfg = Config{
+			BoardName:          boardName,
+			I2cBus:             "thing2",
+			LowPassBandwidthHz: 42,
+			SampleRateHz:       2000,
+		}
+		_, err = cfg.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "sample_rate_hz")
+	})
+}
+
+func TestChipSettings(t *testing.T) {
+	s, err := newChipSettings(&Config{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, s.sampleRateHz(), test.ShouldEqual, 8000)
+	test.That(t, s.pollPeriod(), test.ShouldEqual, time.Millisecond)
+	test.That(t, s.registerValues(), test.ShouldResemble, map[byte]byte{25: 0, 26: 0, 27: 0, 28: 0})
+
+	s, err = newChipSettings(&Config{
+		SampleRateHz:       100,
+		LowPassBandwidthHz: 42,
+		GyroRangeDegPerSec: 1000,
+		AccelRangeG:        8,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, s.sampleRateHz(), test.ShouldEqual, 100)
+	test.That(t, s.pollPeriod(), test.ShouldEqual, 10*time.Millisecond)
+	test.That(t, s.registerValues(), test.ShouldResemble, map[byte]byte{25: 9, 26: 3, 27: 2 << 3, 28: 2 << 3})
+	test.That(t, s.sensorSettings().ToMap(), test.ShouldResemble, map[string]interface{}{
+		"sample_rate_hz":         100.0,
+		"low_pass_bandwidth_hz":  42.0,
+		"gyro_range_deg_per_sec": 1000.0,
+		"accel_range_g":          8.0,
+	})
 }
 
 func TestInitializationFailureOnChipCommunication(t *testing.T) {
@@ -0,0 +1,135 @@
+package mpu6050
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/movementsensor"
+)
+
+// Registers that configure sampling, from the register map.
+const (
+	sampleRateDividerRegister = 25
+	configRegister            = 26
+	gyroConfigRegister        = 27
+	accelConfigRegister       = 28
+)
+
+const (
+	defaultGyroRangeDegPerSec = 250
+	defaultAccelRangeG        = 2
+	// The background goroutine never polls the chip faster than this.
+	maxPollRateHz = 1000
+)
+
+// The FS_SEL values for each supported gyro range, in deg/sec.
+var gyroRanges = map[int]byte{250: 0, 500: 1, 1000: 2, 2000: 3}
+
+// The AFS_SEL values for each supported accelerometer range, in g.
+var accelRanges = map[int]byte{2: 0, 4: 1, 8: 2, 16: 3}
+
+// The DLPF_CFG values for each supported low pass filter bandwidth, in Hz. The
+// accelerometer's bandwidths differ from the gyro's by a few Hz; these are the gyro's.
+var filterBandwidths = map[int]byte{256: 0, 188: 1, 98: 2, 42: 3, 20: 4, 10: 5, 5: 6}
+
+func validValues(m map[int]byte) []int {
+	values := make([]int, 0, len(m))
+	for v := range m {
+		values = append(values, v)
+	}
+	sort.Ints(values)
+	return values
+}
+
+// gyroOutputRate returns the rate, in Hz, that the sample rate divider divides down.
+func gyroOutputRate(filterConfig byte) float64 {
+	// The gyro runs at 8kHz only when the low pass filter is disabled.
+	if filterConfig == 0 {
+		return 8000
+	}
+	return 1000
+}
+
+// chipSettings are the register values derived from a Config.
+type chipSettings struct {
+	sampleRateDivider byte
+	filterConfig      byte
+	gyroRangeSel      byte
+	accelRangeSel     byte
+
+	bandwidthHz        int
+	gyroRangeDegPerSec int
+	accelRangeG        int
+}
+
+func newChipSettings(conf *Config) (chipSettings, error) {
+	s := chipSettings{
+		bandwidthHz:        256,
+		gyroRangeDegPerSec: defaultGyroRangeDegPerSec,
+		accelRangeG:        defaultAccelRangeG,
+	}
+	if conf.GyroRangeDegPerSec != 0 {
+		s.gyroRangeDegPerSec = conf.GyroRangeDegPerSec
+	}
+	if conf.AccelRangeG != 0 {
+		s.accelRangeG = conf.AccelRangeG
+	}
+	if conf.LowPassBandwidthHz != 0 {
+		s.bandwidthHz = conf.LowPassBandwidthHz
+	}
+
+	var ok bool
+	if s.gyroRangeSel, ok = gyroRanges[s.gyroRangeDegPerSec]; !ok {
+		return s, errors.Errorf("gyro_range_deg_per_sec must be one of %v", validValues(gyroRanges))
+	}
+	if s.accelRangeSel, ok = accelRanges[s.accelRangeG]; !ok {
+		return s, errors.Errorf("accel_range_g must be one of %v", validValues(accelRanges))
+	}
+	if s.filterConfig, ok = filterBandwidths[s.bandwidthHz]; !ok {
+		return s, errors.Errorf("low_pass_bandwidth_hz must be one of %v", validValues(filterBandwidths))
+	}
+
+	if conf.SampleRateHz != 0 {
+		outputRate := gyroOutputRate(s.filterConfig)
+		// checked before rounding, which would otherwise turn a rate above the output rate into a divider of 0
+		if conf.SampleRateHz < outputRate/256 || conf.SampleRateHz > outputRate {
+			return s, errors.Errorf("sample_rate_hz must be between %.2f and %.0f with a low pass bandwidth of %dHz",
+				outputRate/256, outputRate, s.bandwidthHz)
+		}
+		s.sampleRateDivider = byte(math.Min(math.Round(outputRate/conf.SampleRateHz)-1, 255))
+	}
+	return s, nil
+}
+
+// sampleRateHz returns the rate at which the chip updates its data registers.
+func (s chipSettings) sampleRateHz() float64 {
+	return gyroOutputRate(s.filterConfig) / (1 + float64(s.sampleRateDivider))
+}
+
+// pollPeriod returns how often to read the chip so that no sample is missed,
+// without polling faster than maxPollRateHz.
+func (s chipSettings) pollPeriod() time.Duration {
+	return time.Duration(float64(time.Second) / math.Min(s.sampleRateHz(), maxPollRateHz))
+}
+
+// registerValues returns the values to write to each configuration register.
+func (s chipSettings) registerValues() map[byte]byte {
+	return map[byte]byte{
+		sampleRateDividerRegister: s.sampleRateDivider,
+		configRegister:            s.filterConfig,
+		gyroConfigRegister:        s.gyroRangeSel << 3,
+		accelConfigRegister:       s.accelRangeSel << 3,
+	}
+}
+
+func (s chipSettings) sensorSettings() movementsensor.SensorSettings {
+	return movementsensor.SensorSettings{
+		SampleRateHz:       s.sampleRateHz(),
+		LowPassBandwidthHz: float64(s.bandwidthHz),
+		GyroRangeDegPerSec: float64(s.gyroRangeDegPerSec),
+		AccelRangeG:        float64(s.accelRangeG),
+	}
+}
@@ -0,0 +1,26 @@
+package movementsensor
+
+import (
+	"context"
+	"time"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// RecordOrientation reads the orientation of a movement sensor, such as one on a pan-tilt mount, and adds it to history
+// as a pose with no translation at the time it was read. A referenceframe.DynamicFrame whose pose is given by the
+// history's PoseAt then follows the sensor as it is polled.
+func RecordOrientation(
+	ctx context.Context,
+	ms MovementSensor,
+	history *referenceframe.PoseHistory,
+	extra map[string]interface{},
+) error {
+	o, err := ms.Orientation(ctx, extra)
+	if err != nil {
+		return err
+	}
+	history.Add(time.Now(), spatialmath.NewPoseFromOrientation(o))
+	return nil
+}
@@ -0,0 +1,41 @@
+package movementsensor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestRecordOrientation(t *testing.T) {
+	ms := inject.NewMovementSensor("pan_tilt")
+	ori := &spatialmath.OrientationVectorDegrees{OX: 1, Theta: 30}
+	ms.OrientationFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+		return ori, nil
+	}
+	history := referenceframe.NewPoseHistory(10)
+	frame, err := referenceframe.NewDynamicFrame("camera", history.PoseAt)
+	test.That(t, err, test.ShouldBeNil)
+
+	_, err = frame.Transform(nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	err = movementsensor.RecordOrientation(context.Background(), ms, history, nil)
+	test.That(t, err, test.ShouldBeNil)
+	pose, err := frame.PoseAt(time.Now())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqual(pose, spatialmath.NewPoseFromOrientation(ori)), test.ShouldBeTrue)
+
+	ms.OrientationFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+		return nil, errors.New("no orientation")
+	}
+	err = movementsensor.RecordOrientation(context.Background(), ms, history, nil)
+	test.That(t, err, test.ShouldBeError, errors.New("no orientation"))
+}
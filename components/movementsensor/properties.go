@@ -0,0 +1,71 @@
+package movementsensor
+
+import (
+	pb "go.viam.com/api/component/movementsensor/v1"
+)
+
+// PropertiesDetailsCommand is the DoCommand the movement sensor server answers with the properties that
+// GetPropertiesResponse has no fields for, such as {"fix_type": "rtk_float"}. Clients use it to fill in those
+// properties of remote sensors.
+const PropertiesDetailsCommand = "movement_sensor_properties_details"
+
+// Properties tells you what a MovementSensor supports.
+type Properties struct {
+	LinearVelocitySupported     bool
+	AngularVelocitySupported    bool
+	OrientationSupported        bool
+	PositionSupported           bool
+	CompassHeadingSupported     bool
+	LinearAccelerationSupported bool
+	// FixType is the kind of position fix a GPS has, such as "rtk_float" or "rtk_fixed". It is empty for sensors
+	// without one.
+	FixType string
+	// Settings is how an IMU or accelerometer is sampling. It is nil for sensors that do not report it.
+	Settings *SensorSettings
+}
+
+// ProtoFeaturesToProperties takes a GetPropertiesResponse and returns the Properties it describes.
+func ProtoFeaturesToProperties(resp *pb.GetPropertiesResponse) *Properties {
+	return &Properties{
+		LinearVelocitySupported:     resp.LinearVelocitySupported,
+		AngularVelocitySupported:    resp.AngularVelocitySupported,
+		OrientationSupported:        resp.OrientationSupported,
+		PositionSupported:           resp.PositionSupported,
+		CompassHeadingSupported:     resp.CompassHeadingSupported,
+		LinearAccelerationSupported: resp.LinearAccelerationSupported,
+	}
+}
+
+// PropertiesToProtoResponse takes Properties and converts them to a GetPropertiesResponse.
+func PropertiesToProtoResponse(props *Properties) *pb.GetPropertiesResponse {
+	return &pb.GetPropertiesResponse{
+		LinearVelocitySupported:     props.LinearVelocitySupported,
+		AngularVelocitySupported:    props.AngularVelocitySupported,
+		OrientationSupported:        props.OrientationSupported,
+		PositionSupported:           props.PositionSupported,
+		CompassHeadingSupported:     props.CompassHeadingSupported,
+		LinearAccelerationSupported: props.LinearAccelerationSupported,
+	}
+}
+
+// propertiesDetails encodes the properties that are sent through PropertiesDetailsCommand.
+func propertiesDetails(props *Properties) map[string]interface{} {
+	details := map[string]interface{}{}
+	if props.FixType != "" {
+		details["fix_type"] = props.FixType
+	}
+	if props.Settings != nil {
+		details["settings"] = props.Settings.ToMap()
+	}
+	return details
+}
+
+// addPropertiesDetails fills in props with the details of a PropertiesDetailsCommand response.
+func addPropertiesDetails(props *Properties, details map[string]interface{}) {
+	if fixType, ok := details["fix_type"].(string); ok {
+		props.FixType = fixType
+	}
+	if settings, ok := details["settings"].(map[string]interface{}); ok {
+		props.Settings = sensorSettingsFromMap(settings)
+	}
+}
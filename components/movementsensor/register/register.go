@@ -6,6 +6,7 @@ import (
 	_ "go.viam.com/rdk/components/movementsensor/adxl345"
 	_ "go.viam.com/rdk/components/movementsensor/cameramono"
 	_ "go.viam.com/rdk/components/movementsensor/fake"
+	_ "go.viam.com/rdk/components/movementsensor/fusion"
 	_ "go.viam.com/rdk/components/movementsensor/gpsnmea"
 	_ "go.viam.com/rdk/components/movementsensor/gpsrtk"
 	_ "go.viam.com/rdk/components/movementsensor/imuvectornav"
@@ -6,6 +6,7 @@ import (
 	"github.com/golang/geo/r3"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/movementsensor/v1"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -116,7 +117,10 @@ func (s *serviceServer) GetProperties(
 		return nil, err
 	}
 	prop, err := msDevice.Properties(ctx, req.Extra.AsMap())
-	return (*pb.GetPropertiesResponse)(prop), err
+	if err != nil {
+		return nil, err
+	}
+	return PropertiesToProtoResponse(prop), nil
 }
 
 func (s *serviceServer) GetAccuracy(
@@ -148,7 +152,7 @@ func (s *serviceServer) GetLinearAcceleration(
 	}, nil
 }
 
-// DoCommand receives arbitrary commands.
+// DoCommand receives arbitrary commands. PropertiesDetailsCommand is answered here for every movement sensor.
 func (s *serviceServer) DoCommand(ctx context.Context,
 	req *commonpb.DoCommandRequest,
 ) (*commonpb.DoCommandResponse, error) {
@@ -156,5 +160,16 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := req.GetCommand().AsMap()[PropertiesDetailsCommand]; ok {
+		prop, err := msDevice.Properties(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		res, err := structpb.NewStruct(propertiesDetails(prop))
+		if err != nil {
+			return nil, err
+		}
+		return &commonpb.DoCommandResponse{Result: res}, nil
+	}
 	return protoutils.DoFromResourceServer(ctx, msDevice, req)
 }
@@ -0,0 +1,46 @@
+package movementsensor
+
+// SensorSettings describes how an IMU or accelerometer is sampling. Fields that do
+// not apply to a chip are left at zero.
+type SensorSettings struct {
+	// SampleRateHz is the output data rate of the chip.
+	SampleRateHz float64
+	// LowPassBandwidthHz is the bandwidth of the on-device low-pass filter.
+	LowPassBandwidthHz float64
+	// GyroRangeDegPerSec is the full scale range of the gyroscope, +/- deg/sec.
+	GyroRangeDegPerSec float64
+	// AccelRangeG is the full scale range of the accelerometer, +/- g.
+	AccelRangeG float64
+}
+
+// ToMap converts the settings into the form they are sent to clients in.
+func (s SensorSettings) ToMap() map[string]interface{} {
+	out := map[string]interface{}{}
+	if s.SampleRateHz != 0 {
+		out["sample_rate_hz"] = s.SampleRateHz
+	}
+	if s.LowPassBandwidthHz != 0 {
+		out["low_pass_bandwidth_hz"] = s.LowPassBandwidthHz
+	}
+	if s.GyroRangeDegPerSec != 0 {
+		out["gyro_range_deg_per_sec"] = s.GyroRangeDegPerSec
+	}
+	if s.AccelRangeG != 0 {
+		out["accel_range_g"] = s.AccelRangeG
+	}
+	return out
+}
+
+// sensorSettingsFromMap reads settings in the form ToMap writes them in.
+func sensorSettingsFromMap(m map[string]interface{}) *SensorSettings {
+	get := func(key string) float64 {
+		v, _ := m[key].(float64)
+		return v
+	}
+	return &SensorSettings{
+		SampleRateHz:       get("sample_rate_hz"),
+		LowPassBandwidthHz: get("low_pass_bandwidth_hz"),
+		GyroRangeDegPerSec: get("gyro_range_deg_per_sec"),
+		AccelRangeG:        get("accel_range_g"),
+	}
+}
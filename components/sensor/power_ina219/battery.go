@@ -0,0 +1,189 @@
+package ina219
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Supported battery chemistries.
+const (
+	ChemistryLiPo     = "lipo"
+	ChemistryLiIon    = "li-ion"
+	ChemistryLiFePO4  = "lifepo4"
+	ChemistryLeadAcid = "lead_acid"
+)
+
+const (
+	defaultLowBatteryPercent = 20
+	// How heavily each new current sample is weighted when averaging the current
+	// draw used to estimate the time remaining.
+	currentSmoothing = 0.05
+)
+
+// voltagePoint maps a resting cell voltage to a state of charge in percent.
+type voltagePoint struct {
+	volts   float64
+	percent float64
+}
+
+// Resting voltage per cell against state of charge, ordered by voltage.
+var chemistryCurves = map[string][]voltagePoint{
+	ChemistryLiPo: {
+		{3.27, 0}, {3.61, 5}, {3.69, 10}, {3.73, 20}, {3.77, 30}, {3.80, 40},
+		{3.84, 50}, {3.87, 60}, {3.95, 70}, {4.02, 80}, {4.11, 90}, {4.20, 100},
+	},
+	ChemistryLiIon: {
+		{3.00, 0}, {3.45, 5}, {3.55, 10}, {3.65, 20}, {3.70, 30}, {3.75, 40},
+		{3.79, 50}, {3.83, 60}, {3.89, 70}, {3.96, 80}, {4.06, 90}, {4.20, 100},
+	},
+	ChemistryLiFePO4: {
+		{2.50, 0}, {3.00, 10}, {3.20, 20}, {3.25, 40}, {3.28, 60}, {3.30, 70},
+		{3.33, 90}, {3.40, 99}, {3.60, 100},
+	},
+	ChemistryLeadAcid: {
+		{1.750, 0}, {1.885, 10}, {1.930, 20}, {1.958, 30}, {1.983, 40}, {2.010, 50},
+		{2.033, 60}, {2.053, 70}, {2.070, 80}, {2.083, 90}, {2.117, 100},
+	},
+}
+
+// BatteryConfig describes the battery a power sensor is monitoring.
+type BatteryConfig struct {
+	CapacityAmpHours float64 `json:"capacity_amp_hours"`
+	// Chemistry selects the voltage curve used to estimate the initial state of
+	// charge. Defaults to lipo.
+	Chemistry string `json:"chemistry,omitempty"`
+	// Cells is the number of cells in series. Defaults to 1.
+	Cells int `json:"cells,omitempty"`
+	// LowBatteryPercent is the state of charge below which the battery is
+	// reported as low. Defaults to 20.
+	LowBatteryPercent float64 `json:"low_battery_percent,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *BatteryConfig) Validate() error {
+	if cfg.CapacityAmpHours <= 0 {
+		return errors.New("capacity_amp_hours must be positive")
+	}
+	if cfg.Chemistry != "" {
+		if _, ok := chemistryCurves[cfg.Chemistry]; !ok {
+			chemistries := make([]string, 0, len(chemistryCurves))
+			for c := range chemistryCurves {
+				chemistries = append(chemistries, c)
+			}
+			sort.Strings(chemistries)
+			return errors.Errorf("chemistry must be one of %v", chemistries)
+		}
+	}
+	if cfg.Cells < 0 {
+		return errors.New("cells cannot be negative")
+	}
+	if cfg.LowBatteryPercent < 0 || cfg.LowBatteryPercent > 100 {
+		return errors.New("low_battery_percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// stateOfChargeFromVoltage linearly interpolates the state of charge of a cell
+// from its voltage.
+func stateOfChargeFromVoltage(curve []voltagePoint, cellVolts float64) float64 {
+	if cellVolts <= curve[0].volts {
+		return curve[0].percent
+	}
+	for i := 1; i < len(curve); i++ {
+		if cellVolts <= curve[i].volts {
+			lo, hi := curve[i-1], curve[i]
+			return lo.percent + (cellVolts-lo.volts)/(hi.volts-lo.volts)*(hi.percent-lo.percent)
+		}
+	}
+	return curve[len(curve)-1].percent
+}
+
+// batteryEstimator tracks the state of charge of a battery by coulomb counting,
+// starting from an estimate based on the voltage of the first sample.
+type batteryEstimator struct {
+	capacityAmpHours  float64
+	curve             []voltagePoint
+	cells             int
+	lowBatteryPercent float64
+
+	initialized bool
+	lastSample  time.Time
+	ampHours    float64
+	avgAmps     float64
+}
+
+func newBatteryEstimator(cfg *BatteryConfig) *batteryEstimator {
+	chemistry := cfg.Chemistry
+	if chemistry == "" {
+		chemistry = ChemistryLiPo
+	}
+	cells := cfg.Cells
+	if cells == 0 {
+		cells = 1
+	}
+	low := cfg.LowBatteryPercent
+	if low == 0 {
+		low = defaultLowBatteryPercent
+	}
+	return &batteryEstimator{
+		capacityAmpHours:  cfg.CapacityAmpHours,
+		curve:             chemistryCurves[chemistry],
+		cells:             cells,
+		lowBatteryPercent: low,
+	}
+}
+
+// update records a sample of the battery's voltage and the current drawn from
+// it. Positive current discharges the battery.
+func (b *batteryEstimator) update(volts, amps float64, now time.Time) {
+	if !b.initialized {
+		percent := stateOfChargeFromVoltage(b.curve, volts/float64(b.cells))
+		b.ampHours = b.capacityAmpHours * percent / 100
+		b.avgAmps = amps
+		b.lastSample = now
+		b.initialized = true
+		return
+	}
+	hours := now.Sub(b.lastSample).Hours()
+	b.lastSample = now
+	b.ampHours = math.Max(0, math.Min(b.capacityAmpHours, b.ampHours-amps*hours))
+	b.avgAmps += currentSmoothing * (amps - b.avgAmps)
+}
+
+// stateOfCharge returns the estimated state of charge in percent.
+func (b *batteryEstimator) stateOfCharge() float64 {
+	return 100 * b.ampHours / b.capacityAmpHours
+}
+
+// timeRemaining returns how long the battery will last at the average current
+// draw, and false if the battery is not discharging.
+func (b *batteryEstimator) timeRemaining() (time.Duration, bool) {
+	if b.avgAmps <= 0 {
+		return 0, false
+	}
+	return time.Duration(b.ampHours / b.avgAmps * float64(time.Hour)), true
+}
+
+func (b *batteryEstimator) low() bool {
+	return b.initialized && b.stateOfCharge() < b.lowBatteryPercent
+}
+
+// readings returns the estimates in the form reported by Readings.
+func (b *batteryEstimator) readings() map[string]interface{} {
+	if !b.initialized {
+		return map[string]interface{}{}
+	}
+	readings := map[string]interface{}{
+		"capacity_amp_hours":  b.capacityAmpHours,
+		"remaining_amp_hours": b.ampHours,
+		"state_of_charge_pct": b.stateOfCharge(),
+		"low_battery":         b.low(),
+	}
+	if remaining, ok := b.timeRemaining(); ok {
+		readings["time_remaining_sec"] = remaining.Seconds()
+	}
+	return readings
+}
@@ -0,0 +1,55 @@
+package ina219
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestBatteryConfigValidate(t *testing.T) {
+	cfg := &BatteryConfig{}
+	test.That(t, cfg.Validate(), test.ShouldNotBeNil)
+
+	cfg = &BatteryConfig{CapacityAmpHours: 2, Chemistry: "nicad"}
+	test.That(t, cfg.Validate().Error(), test.ShouldContainSubstring, "chemistry")
+
+	cfg = &BatteryConfig{CapacityAmpHours: 2, LowBatteryPercent: 120}
+	test.That(t, cfg.Validate(), test.ShouldNotBeNil)
+
+	cfg = &BatteryConfig{CapacityAmpHours: 2, Chemistry: ChemistryLiFePO4, Cells: 4}
+	test.That(t, cfg.Validate(), test.ShouldBeNil)
+}
+
+func TestStateOfChargeFromVoltage(t *testing.T) {
+	curve := chemistryCurves[ChemistryLiPo]
+	test.That(t, stateOfChargeFromVoltage(curve, 3.0), test.ShouldEqual, 0.0)
+	test.That(t, stateOfChargeFromVoltage(curve, 4.3), test.ShouldEqual, 100.0)
+	test.That(t, stateOfChargeFromVoltage(curve, 3.84), test.ShouldAlmostEqual, 50)
+	test.That(t, stateOfChargeFromVoltage(curve, 3.82), test.ShouldAlmostEqual, 45)
+}
+
+func TestBatteryEstimator(t *testing.T) {
+	b := newBatteryEstimator(&BatteryConfig{CapacityAmpHours: 2, Cells: 3})
+	test.That(t, b.readings(), test.ShouldBeEmpty)
+
+	start := time.Now()
+	// 3 cells at 3.84V is half full.
+	b.update(3*3.84, 1, start)
+	test.That(t, b.stateOfCharge(), test.ShouldAlmostEqual, 50)
+	test.That(t, b.low(), test.ShouldBeFalse)
+	remaining, ok := b.timeRemaining()
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, remaining.Hours(), test.ShouldAlmostEqual, 1)
+
+	// Drawing 1A for 36 minutes uses 0.6Ah of the remaining 1Ah.
+	b.update(3*3.7, 1, start.Add(36*time.Minute))
+	test.That(t, b.stateOfCharge(), test.ShouldAlmostEqual, 20)
+	b.update(3*3.7, 1, start.Add(37*time.Minute))
+	test.That(t, b.low(), test.ShouldBeTrue)
+	test.That(t, b.readings()["low_battery"], test.ShouldEqual, true)
+
+	// Charging never goes past full.
+	b.update(3*4.2, -100, start.Add(2*time.Hour))
+	test.That(t, b.stateOfCharge(), test.ShouldEqual, 100.0)
+}
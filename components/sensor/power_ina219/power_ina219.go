@@ -8,6 +8,8 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/edaniels/golog"
 	"go.viam.com/utils"
@@ -32,6 +34,7 @@ const (
 	powerRegister              = 0x03
 	currentRegister            = 0x04
 	calibrationRegister        = 0x05
+	batteryPollPeriod          = 100 * time.Millisecond
 )
 
 // Config is used for converting config attributes.
@@ -39,6 +42,10 @@ type Config struct {
 	Board   string `json:"board"`
 	I2CBus  string `json:"i2c_bus"`
 	I2cAddr int    `json:"i2c_addr,omitempty"`
+
+	// Battery enables state of charge and time remaining estimation for the
+	// battery whose current is being measured.
+	Battery *BatteryConfig `json:"battery,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -51,6 +58,11 @@ func (conf *Config) Validate(path string) ([]string, error) {
 	if len(conf.I2CBus) == 0 {
 		return nil, utils.NewConfigValidationFieldRequiredError(path, "i2c_bus")
 	}
+	if conf.Battery != nil {
+		if err := conf.Battery.Validate(); err != nil {
+			return nil, utils.NewConfigValidationError(path+".battery", err)
+		}
+	}
 	return deps, nil
 }
 
@@ -110,6 +122,16 @@ func newSensor(
 		return nil, err
 	}
 
+	if conf.Battery != nil {
+		s.battery = newBatteryEstimator(conf.Battery)
+		cancelCtx, cancelFunc := context.WithCancel(context.Background())
+		s.cancelFunc = cancelFunc
+		s.activeBackgroundWorkers.Add(1)
+		utils.ManagedGo(func() {
+			s.trackBattery(cancelCtx)
+		}, s.activeBackgroundWorkers.Done)
+	}
+
 	return s, nil
 }
 
@@ -117,13 +139,18 @@ func newSensor(
 type ina219 struct {
 	resource.Named
 	resource.AlwaysRebuild
-	resource.TriviallyCloseable
 	logger     golog.Logger
 	bus        board.I2C
 	addr       byte
 	currentLSB int64
 	powerLSB   int64
 	cal        uint16
+
+	// Only set if a battery is configured.
+	mu                      sync.Mutex
+	battery                 *batteryEstimator
+	cancelFunc              func()
+	activeBackgroundWorkers sync.WaitGroup
 }
 
 type powerMonitor struct {
@@ -155,8 +182,62 @@ func (d *ina219) calibrate() error {
 	return nil
 }
 
-// Readings returns a list containing three items (voltage, current, and power).
+// Readings returns the voltage, current, and power, along with the battery
+// estimates if a battery is configured.
 func (d *ina219) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	pm, err := d.readPowerMonitor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	readings := map[string]interface{}{
+		"volts": pm.Voltage,
+		"amps":  pm.Current,
+		"watts": pm.Power,
+	}
+	if d.battery != nil {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		for k, v := range d.battery.readings() {
+			readings[k] = v
+		}
+	}
+	return readings, nil
+}
+
+// Status reports the battery estimates through the robot's status API, so that a
+// low battery can be noticed without polling readings.
+func (d *ina219) Status(ctx context.Context) (map[string]interface{}, error) {
+	if d.battery == nil {
+		return map[string]interface{}{}, nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.battery.readings(), nil
+}
+
+// trackBattery samples the current continuously so that the charge drawn from the
+// battery can be integrated.
+func (d *ina219) trackBattery(ctx context.Context) {
+	for {
+		if !utils.SelectContextOrWait(ctx, batteryPollPeriod) {
+			return
+		}
+		pm, err := d.readPowerMonitor(ctx)
+		if err != nil {
+			d.logger.Debugw("failed to sample battery", "error", err)
+			continue
+		}
+		d.mu.Lock()
+		wasLow := d.battery.low()
+		d.battery.update(pm.Voltage, pm.Current, time.Now())
+		if !wasLow && d.battery.low() {
+			d.logger.Warnf("battery is low: %.1f%% remaining", d.battery.stateOfCharge())
+		}
+		d.mu.Unlock()
+	}
+}
+
+func (d *ina219) readPowerMonitor(ctx context.Context) (*powerMonitor, error) {
 	handle, err := d.bus.OpenHandle(d.addr)
 	if err != nil {
 		d.logger.Errorf("can't open ina219 i2c: %s", err)
@@ -209,7 +290,8 @@ func (d *ina219) Readings(ctx context.Context, extra map[string]interface{}) (ma
 		return nil, err
 	}
 
-	pm.Current = float64(int64(binary.BigEndian.Uint16(current))*d.currentLSB) / 1000000000
+	// The current register is signed: negative values mean the battery is charging.
+	pm.Current = float64(int64(int16(binary.BigEndian.Uint16(current)))*d.currentLSB) / 1000000000
 
 	power, err := handle.ReadBlockData(ctx, powerRegister, 2)
 	if err != nil {
@@ -217,9 +299,14 @@ func (d *ina219) Readings(ctx context.Context, extra map[string]interface{}) (ma
 	}
 	pm.Power = float64(int64(binary.BigEndian.Uint16(power))*d.powerLSB) / 1000000000
 
-	return map[string]interface{}{
-		"volts": pm.Voltage,
-		"amps":  pm.Current,
-		"watts": pm.Power,
-	}, handle.Close()
+	return &pm, handle.Close()
+}
+
+// Close stops tracking the battery.
+func (d *ina219) Close(ctx context.Context) error {
+	if d.cancelFunc != nil {
+		d.cancelFunc()
+		d.activeBackgroundWorkers.Wait()
+	}
+	return nil
 }
@@ -17,6 +17,9 @@ func init() {
 		RPCServiceHandler:           pb.RegisterSensorServiceHandlerFromEndpoint,
 		RPCServiceDesc:              &pb.SensorService_ServiceDesc,
 		RPCClient:                   NewClientFromConn,
+		Status: func(ctx context.Context, s Sensor) (interface{}, error) {
+			return CreateStatus(ctx, s)
+		},
 	})
 	data.RegisterCollector(data.MethodMetadata{
 		API:        API,
@@ -43,6 +46,22 @@ type Sensor interface {
 	Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error)
 }
 
+// A StatusReporter is a Sensor with state worth surfacing through the robot's
+// status API, such as a battery running low.
+type StatusReporter interface {
+	Status(ctx context.Context) (map[string]interface{}, error)
+}
+
+// CreateStatus creates a status from the sensor. Sensors that are not
+// StatusReporters have an empty status.
+func CreateStatus(ctx context.Context, s Sensor) (map[string]interface{}, error) {
+	reporter, ok := s.(StatusReporter)
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+	return reporter.Status(ctx)
+}
+
 // FromRobot is a helper for getting the named Sensor from the given Robot.
 func FromRobot(r robot.Robot, name string) (Sensor, error) {
 	return robot.ResourceFromRobot[Sensor](r, Named(name))
@@ -25,6 +25,10 @@ import (
 // The cutoff at which if interval < cutoff, a sleep based capture func is used instead of a ticker.
 var sleepCaptureCutoff = 2 * time.Millisecond
 
+// ErrNoCaptureToStore is returned by a CaptureFunc that captured nothing worth storing, such as an image that is the
+// same as the last one. The reading is skipped without logging an error.
+var ErrNoCaptureToStore = errors.New("no capture to store")
+
 // CaptureFunc allows the creation of simple Capturers with anonymous functions.
 type CaptureFunc func(ctx context.Context, params map[string]*anypb.Any) (interface{}, error)
 
@@ -185,6 +189,9 @@ func (c *collector) getAndPushNextReading() {
 	timeRequested := timestamppb.New(c.clock.Now().UTC())
 	reading, err := c.captureFunc(c.cancelCtx, c.params)
 	timeReceived := timestamppb.New(c.clock.Now().UTC())
+	if errors.Is(err, ErrNoCaptureToStore) {
+		return
+	}
 	if err != nil {
 		c.captureErrors <- errors.Wrap(err, "error while capturing data")
 		return
@@ -279,6 +279,41 @@ func TestCtxCancelledNotLoggedAfterClose(t *testing.T) {
 	test.That(t, logs.FilterLevelExact(zapcore.ErrorLevel).Len(), test.ShouldEqual, 0)
 }
 
+// TestNoCaptureToStoreNotLogged verifies that readings a CaptureFunc skips with ErrNoCaptureToStore are neither
+// written nor logged.
+func TestNoCaptureToStoreNotLogged(t *testing.T) {
+	logger, logs := golog.NewObservedTestLogger(t)
+	tmpDir := t.TempDir()
+	target := datacapture.NewBuffer(tmpDir, &v1.DataCaptureMetadata{})
+	captured := make(chan struct{})
+	skippingCapturer := CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (interface{}, error) {
+		select {
+		case <-ctx.Done():
+		case captured <- struct{}{}:
+		}
+		return nil, ErrNoCaptureToStore
+	})
+
+	params := CollectorParams{
+		ComponentName: "testComponent",
+		Interval:      time.Millisecond * 1,
+		MethodParams:  map[string]*anypb.Any{"name": fakeVal},
+		Target:        target,
+		QueueSize:     queueSize,
+		BufferSize:    bufferSize,
+		Logger:        logger,
+	}
+	c, _ := NewCollector(skippingCapturer, params)
+	c.Collect()
+	<-captured
+	<-captured
+	c.Close()
+	close(captured)
+
+	test.That(t, logs.FilterLevelExact(zapcore.ErrorLevel).Len(), test.ShouldEqual, 0)
+	test.That(t, getAllFiles(tmpDir), test.ShouldBeEmpty)
+}
+
 func validateReadings(t *testing.T, act []*v1.SensorData, n int) {
 	t.Helper()
 	for i := 0; i < n; i++ {
@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	tflite "github.com/mattn/go-tflite"
+	"github.com/mattn/go-tflite/delegates"
 	"github.com/pkg/errors"
 
 	tfliteSchema "go.viam.com/rdk/ml/inference/tflite"
@@ -22,6 +23,7 @@ type TFLiteStruct struct {
 	model              *tflite.Model
 	interpreter        Interpreter
 	interpreterOptions *tflite.InterpreterOptions
+	delegates          []delegates.Delegater
 	Info               *TFLiteInfo
 	modelPath          string
 	mu                 sync.Mutex
@@ -43,6 +45,7 @@ type TFLiteModelLoader struct {
 	newModelFromFile   func(path string) *tflite.Model
 	newInterpreter     func(model *tflite.Model, options *tflite.InterpreterOptions) (Interpreter, error)
 	interpreterOptions *tflite.InterpreterOptions
+	delegates          []delegates.Delegater
 	getInfo            func(inter Interpreter) *TFLiteInfo
 }
 
@@ -123,6 +126,7 @@ func (loader TFLiteModelLoader) Load(modelPath string) (*TFLiteStruct, error) {
 		model:              tfLiteModel,
 		interpreter:        interpreter,
 		interpreterOptions: loader.interpreterOptions,
+		delegates:          loader.delegates,
 		Info:               info,
 		modelPath:          modelPath,
 	}
@@ -291,6 +295,7 @@ func (model *TFLiteStruct) Close() error {
 	model.model.Delete()
 	model.interpreterOptions.Delete()
 	model.interpreter.Delete()
+	deleteDelegates(model.delegates)
 	return nil
 }
 
@@ -0,0 +1,93 @@
+//go:build !arm && !windows
+
+package inference
+
+import (
+	"runtime"
+
+	"github.com/mattn/go-tflite/delegates"
+	"github.com/pkg/errors"
+)
+
+// The TFLite delegates, which run all or part of a model on an accelerator instead of the CPU. Each needs its
+// library, and rdk to be built with its build tag: edgetpu, tflitegpu or xnnpack.
+const (
+	// DelegateEdgeTPU runs models compiled for the Coral Edge TPU on it, over USB or PCIe.
+	DelegateEdgeTPU = "edgetpu"
+	// DelegateGPU runs models on the GPU, through OpenGL ES or OpenCL, as on phone-class boards.
+	DelegateGPU = "gpu"
+	// DelegateXNNPACK runs floating point models on the CPU with the optimized kernels of XNNPACK.
+	DelegateXNNPACK = "xnnpack"
+)
+
+// TFLiteDelegateConfig selects a delegate for a TFLite model.
+type TFLiteDelegateConfig struct {
+	Type string `json:"type"`
+	// Device selects the Edge TPU to use, by its type, "usb" or "pci", or by its path. The first one found is used if
+	// it is empty.
+	Device string `json:"device,omitempty"`
+	// NumThreads is the number of threads XNNPACK uses. It defaults to the number of CPUs.
+	NumThreads int `json:"num_threads,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg TFLiteDelegateConfig) Validate() error {
+	switch cfg.Type {
+	case DelegateEdgeTPU, DelegateGPU, DelegateXNNPACK:
+	default:
+		return errors.Errorf("unknown TFLite delegate %q", cfg.Type)
+	}
+	if cfg.NumThreads < 0 {
+		return errors.New("num_threads cannot be negative")
+	}
+	return nil
+}
+
+// NewTFLiteModelLoaderWithDelegates returns a loader that runs its model on the given delegates, in order of
+// preference, and on numThreads CPU threads, or as many as there are CPUs if it is not positive, for whatever the
+// delegates cannot run. A loader with delegates should only load one model.
+func NewTFLiteModelLoaderWithDelegates(numThreads int, configs []TFLiteDelegateConfig) (*TFLiteModelLoader, error) {
+	if numThreads <= 0 {
+		numThreads = runtime.NumCPU()
+	}
+	loader, err := NewTFLiteModelLoader(numThreads)
+	if err != nil {
+		return nil, err
+	}
+	for _, cfg := range configs {
+		d, err := newDelegate(cfg)
+		if err != nil {
+			deleteDelegates(loader.delegates)
+			loader.interpreterOptions.Delete()
+			return nil, errors.Wrapf(err, "could not create TFLite %s delegate", cfg.Type)
+		}
+		loader.interpreterOptions.AddDelegate(d)
+		loader.delegates = append(loader.delegates, d)
+	}
+	return loader, nil
+}
+
+func newDelegate(cfg TFLiteDelegateConfig) (delegates.Delegater, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	switch cfg.Type {
+	case DelegateEdgeTPU:
+		return newEdgeTPUDelegate(cfg.Device)
+	case DelegateGPU:
+		return newGPUDelegate()
+	default:
+		numThreads := cfg.NumThreads
+		if numThreads == 0 {
+			numThreads = runtime.NumCPU()
+		}
+		return newXNNPACKDelegate(numThreads)
+	}
+}
+
+// deleteDelegates deletes the delegates, which must outlive the interpreters that use them.
+func deleteDelegates(ds []delegates.Delegater) {
+	for _, d := range ds {
+		d.Delete()
+	}
+}
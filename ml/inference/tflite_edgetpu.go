@@ -0,0 +1,39 @@
+//go:build !arm && !windows && edgetpu
+
+package inference
+
+import (
+	"strings"
+
+	"github.com/mattn/go-tflite/delegates"
+	"github.com/mattn/go-tflite/delegates/edgetpu"
+	"github.com/pkg/errors"
+)
+
+// newEdgeTPUDelegate returns a delegate for the Edge TPU of the given type, "usb" or "pci", or path, or for the first
+// Edge TPU found.
+func newEdgeTPUDelegate(device string) (delegates.Delegater, error) {
+	devices, err := edgetpu.DeviceList()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range devices {
+		switch {
+		case device == "",
+			device == d.Path,
+			strings.EqualFold(device, "usb") && d.Type == edgetpu.TypeApexUSB,
+			strings.EqualFold(device, "pci") && d.Type == edgetpu.TypeApexPCI:
+		default:
+			continue
+		}
+		delegate := edgetpu.New(d)
+		if delegate == nil {
+			return nil, FailedToLoadError("Edge TPU delegate")
+		}
+		return delegate, nil
+	}
+	if device == "" {
+		return nil, errors.New("no Edge TPU found")
+	}
+	return nil, errors.Errorf("no Edge TPU %q found", device)
+}
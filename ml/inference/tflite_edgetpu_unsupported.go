@@ -0,0 +1,12 @@
+//go:build !arm && !windows && !edgetpu
+
+package inference
+
+import (
+	"github.com/mattn/go-tflite/delegates"
+	"github.com/pkg/errors"
+)
+
+func newEdgeTPUDelegate(device string) (delegates.Delegater, error) {
+	return nil, errors.New("support for the Edge TPU needs libedgetpu and building with the edgetpu build tag")
+}
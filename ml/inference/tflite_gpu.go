@@ -0,0 +1,39 @@
+//go:build !arm && !windows && tflitegpu
+
+package inference
+
+/*
+#cgo LDFLAGS: -ltensorflowlite_gpu_delegate
+#include <tensorflow/lite/delegates/gpu/delegate.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/mattn/go-tflite/delegates"
+)
+
+// gpuDelegate is the GPU delegate of TFLite, which go-tflite has no binding for.
+type gpuDelegate struct {
+	d *C.TfLiteDelegate
+}
+
+func newGPUDelegate() (delegates.Delegater, error) {
+	options := C.TfLiteGpuDelegateOptionsV2Default()
+	d := C.TfLiteGpuDelegateV2Create(&options)
+	if d == nil {
+		return nil, FailedToLoadError("GPU delegate")
+	}
+	return &gpuDelegate{d: d}, nil
+}
+
+// Delete deletes the delegate.
+func (g *gpuDelegate) Delete() {
+	C.TfLiteGpuDelegateV2Delete(g.d)
+}
+
+// Ptr returns the TfLiteDelegate to add to interpreter options.
+func (g *gpuDelegate) Ptr() unsafe.Pointer {
+	return unsafe.Pointer(g.d)
+}
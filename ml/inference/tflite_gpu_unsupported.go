@@ -0,0 +1,12 @@
+//go:build !arm && !windows && !tflitegpu
+
+package inference
+
+import (
+	"github.com/mattn/go-tflite/delegates"
+	"github.com/pkg/errors"
+)
+
+func newGPUDelegate() (delegates.Delegater, error) {
+	return nil, errors.New("GPU support needs the TFLite GPU delegate library and building with the tflitegpu build tag")
+}
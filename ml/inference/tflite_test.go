@@ -162,6 +162,21 @@ func TestNoMetadata(t *testing.T) {
 	tfliteStruct.Close()
 }
 
+func TestTFLiteDelegates(t *testing.T) {
+	test.That(t, TFLiteDelegateConfig{Type: DelegateXNNPACK, NumThreads: 2}.Validate(), test.ShouldBeNil)
+	test.That(t, TFLiteDelegateConfig{Type: "abacus"}.Validate(), test.ShouldNotBeNil)
+	test.That(t, TFLiteDelegateConfig{Type: DelegateXNNPACK, NumThreads: -1}.Validate(), test.ShouldNotBeNil)
+
+	_, err := NewTFLiteModelLoaderWithDelegates(1, []TFLiteDelegateConfig{{Type: "abacus"}})
+	test.That(t, err.Error(), test.ShouldContainSubstring, "abacus")
+
+	loader, err := NewTFLiteModelLoaderWithDelegates(0, nil)
+	test.That(t, err, test.ShouldBeNil)
+	tfliteStruct, err := loader.Load(artifact.MustPath("ml/inference/fizzbuzz_model.tflite"))
+	test.That(t, err, test.ShouldBeNil)
+	tfliteStruct.Close()
+}
+
 func modelLoader(path string) *tflite.Model {
 	if path == badPath {
 		return nil
@@ -0,0 +1,16 @@
+//go:build !arm && !windows && xnnpack
+
+package inference
+
+import (
+	"github.com/mattn/go-tflite/delegates"
+	"github.com/mattn/go-tflite/delegates/xnnpack"
+)
+
+func newXNNPACKDelegate(numThreads int) (delegates.Delegater, error) {
+	delegate := xnnpack.New(xnnpack.DelegateOptions{NumThreads: int32(numThreads)})
+	if delegate == nil {
+		return nil, FailedToLoadError("XNNPACK delegate")
+	}
+	return delegate, nil
+}
@@ -0,0 +1,12 @@
+//go:build !arm && !windows && !xnnpack
+
+package inference
+
+import (
+	"github.com/mattn/go-tflite/delegates"
+	"github.com/pkg/errors"
+)
+
+func newXNNPACKDelegate(numThreads int) (delegates.Delegater, error) {
+	return nil, errors.New("XNNPACK support needs a TFLite library built with it and building with the xnnpack build tag")
+}
@@ -0,0 +1,215 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/edaniels/golog"
+	"go.uber.org/multierr"
+	"gonum.org/v1/gonum/optimize"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	bfgsEvalsPerIter = 4001
+
+	// Weight of the squared distance outside of the joint limits, which keeps the unbounded BFGS search within them.
+	boundsPenaltyWeight = 1e4
+)
+
+// BFGSIK is a pure Go inverse kinematics solver which minimizes metrics with BFGS. Unlike NloptIK it needs no cgo, so it is also
+// available on platforms that nlopt cannot be built for. Joint limits are kept by clamping inputs to them and penalizing the distance
+// the search strays outside of them.
+type BFGSIK struct {
+	id            int
+	model         referenceframe.Frame
+	lowerBound    []float64
+	upperBound    []float64
+	maxIterations int
+	epsilon       float64
+	solveEpsilon  float64
+	logger        golog.Logger
+	jump          float64
+}
+
+// CreateBFGSIKSolver creates a BFGSIK object that can perform gradient descent on metrics for Frames. The parameters are the Frame on
+// which Transform() will be called, a logger, and the number of iterations to run. If the iteration count is less than 1, it will be set
+// to the default of 5000.
+func CreateBFGSIKSolver(mdl referenceframe.Frame, logger golog.Logger, iter int) (*BFGSIK, error) {
+	ik := &BFGSIK{logger: logger}
+
+	ik.model = mdl
+	// How close we want to get to the goal
+	ik.epsilon = defaultEpsilon
+	// Stop optimizing when iterations change by less than this much
+	ik.solveEpsilon = math.Pow(ik.epsilon, 4)
+	if iter < 1 {
+		// default value
+		iter = 5000
+	}
+	ik.maxIterations = iter
+	ik.lowerBound, ik.upperBound = limitsToArrays(mdl.DoF())
+	// How much to adjust joints to determine slope
+	ik.jump = 0.00000001
+
+	return ik, nil
+}
+
+// Solve runs the actual solver and sends any solutions found to the given channel.
+func (ik *BFGSIK) Solve(ctx context.Context,
+	c chan<- []referenceframe.Input,
+	seed []referenceframe.Input,
+	m StateMetric,
+	rseed int,
+) error {
+	//nolint: gosec
+	randSeed := rand.New(rand.NewSource(int64(rseed)))
+	var err error
+
+	if len(ik.lowerBound) == 0 || len(ik.upperBound) == 0 {
+		return errBadBounds
+	}
+
+	tries := 1
+	iterations := 0
+	solutionsFound := 0
+	startingPos := seed
+	lower, upper := ik.lowerBound, ik.upperBound
+
+	if ik.id > 0 {
+		// Solver with ID 1 seeds off current angles
+		if ik.id == 1 {
+			if len(seed) > len(ik.model.DoF()) {
+				return errTooManyVals
+			}
+			// Set initial restrictions on joints for more intuitive movement
+			lower, upper = constrainedBounds(ik.lowerBound, ik.upperBound, seed, tries)
+		} else {
+			// Solvers whose ID is not 1 should skip ahead directly to trying random seeds
+			startingPos = ik.GenerateRandomPositions(randSeed)
+			tries = constrainedTries
+		}
+	}
+
+	for iterations < ik.maxIterations {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		iterations++
+		solution, dist, optErr := ik.optimize(referenceframe.InputsToFloats(startingPos), lower, upper, m)
+		if optErr != nil {
+			// As with nlopt, this happens sometimes on nonlinear randomized problems. Something else will find a solution.
+			err = multierr.Combine(err, optErr)
+		}
+
+		if dist < ik.epsilon*ik.epsilon {
+			select {
+			case <-ctx.Done():
+				return err
+			case c <- referenceframe.FloatsToInputs(solution):
+			}
+			solutionsFound++
+		}
+		tries++
+		if ik.id > 0 && tries < constrainedTries {
+			lower, upper = constrainedBounds(ik.lowerBound, ik.upperBound, seed, tries)
+		} else {
+			lower, upper = ik.lowerBound, ik.upperBound
+			startingPos = ik.GenerateRandomPositions(randSeed)
+		}
+	}
+	if solutionsFound > 0 {
+		return nil
+	}
+	return multierr.Combine(err, errNoSolve)
+}
+
+// optimize minimizes the metric from the starting inputs within the given limits, returning the inputs it ends at and their distance.
+func (ik *BFGSIK) optimize(start, lower, upper []float64, m StateMetric) ([]float64, float64, error) {
+	mInput := &State{Frame: ik.model}
+	clamped := make([]float64, len(start))
+
+	// dist returns the metric at x clamped to the limits, plus the penalty for being outside of them.
+	dist := func(x []float64) float64 {
+		penalty := 0.
+		for i, v := range x {
+			clamped[i] = math.Min(math.Max(v, lower[i]), upper[i])
+			penalty += (v - clamped[i]) * (v - clamped[i])
+		}
+		// Requesting an out-of-bounds transform will result in a non-nil error but will optionally return a correct if invalid pose.
+		inputs := referenceframe.FloatsToInputs(clamped)
+		eePos, err := ik.model.Transform(inputs)
+		if eePos == nil || (err != nil && !strings.Contains(err.Error(), referenceframe.OOBErrString)) {
+			return math.Inf(1)
+		}
+		mInput.Configuration = inputs
+		mInput.Position = eePos
+		return m(mInput) + boundsPenaltyWeight*penalty
+	}
+
+	problem := optimize.Problem{
+		Func: dist,
+		Grad: func(grad, x []float64) {
+			base := dist(x)
+			for i := range grad {
+				x[i] += ik.jump
+				grad[i] = (dist(x) - base) / ik.jump
+				x[i] -= ik.jump
+			}
+		},
+	}
+	settings := &optimize.Settings{
+		Converger: &stopValConverger{
+			stopVal:   ik.epsilon * ik.epsilon,
+			converger: &optimize.FunctionConverge{Absolute: ik.solveEpsilon, Relative: ik.solveEpsilon, Iterations: 20},
+		},
+		FuncEvaluations: bfgsEvalsPerIter,
+	}
+
+	x0 := make([]float64, len(start))
+	for i, v := range start {
+		x0[i] = math.Min(math.Max(v, lower[i]), upper[i])
+	}
+	result, err := optimize.Minimize(problem, x0, settings, &optimize.BFGS{})
+	if result == nil {
+		return x0, math.Inf(1), err
+	}
+	solution := make([]float64, len(result.X))
+	for i, v := range result.X {
+		solution[i] = math.Min(math.Max(v, lower[i]), upper[i])
+	}
+	return solution, dist(solution), err
+}
+
+// GenerateRandomPositions generates a random set of positions within the limits of this solver.
+func (ik *BFGSIK) GenerateRandomPositions(randSeed *rand.Rand) []referenceframe.Input {
+	return randomInputs(ik.lowerBound, ik.upperBound, randSeed)
+}
+
+// Frame returns the associated referenceframe.
+func (ik *BFGSIK) Frame() referenceframe.Frame {
+	return ik.model
+}
+
+// stopValConverger stops an optimization as soon as the function value is below stopVal, like nlopt's stop value, and otherwise
+// defers to another converger.
+type stopValConverger struct {
+	stopVal   float64
+	converger optimize.Converger
+}
+
+func (s *stopValConverger) Init(dim int) {
+	s.converger.Init(dim)
+}
+
+func (s *stopValConverger) Converged(loc *optimize.Location) optimize.Status {
+	if loc.F < s.stopVal {
+		return optimize.Success
+	}
+	return s.converger.Converged(loc)
+}
@@ -0,0 +1,40 @@
+package motionplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestCreateBFGSIKSolver(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	m, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm6_kinematics.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	ik, err := CreateBFGSIKSolver(m, logger, 50)
+	test.That(t, err, test.ShouldBeNil)
+	ik.id = 1
+
+	// matches xarm home end effector position
+	pos := spatialmath.NewPoseFromPoint(r3.Vector{X: 207, Z: 112})
+	seed := referenceframe.FloatsToInputs([]float64{1, 1, 1, 1, 1, 0})
+	solutions, err := solveTest(context.Background(), ik, pos, seed)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, solutions, test.ShouldNotBeEmpty)
+
+	// every solution is within the joint limits and reaches the goal
+	for _, solution := range solutions {
+		for i, limit := range m.DoF() {
+			test.That(t, solution[i].Value, test.ShouldBeBetweenOrEqual, limit.Min, limit.Max)
+		}
+		solved, err := m.Transform(solution)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(solved, pos, 0.1), test.ShouldBeTrue)
+	}
+}
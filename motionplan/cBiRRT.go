@@ -1,5 +1,3 @@
-//go:build !windows
-
 package motionplan
 
 import (
@@ -85,7 +83,7 @@ func newCbirrtOptions(planOpts *plannerOptions, frame referenceframe.Frame) (*cb
 // https://ieeexplore.ieee.org/document/5152399/
 type cBiRRTMotionPlanner struct {
 	*planner
-	fastGradDescent *NloptIK
+	fastGradDescent InverseKinematics
 	algOpts         *cbirrtOptions
 	corners         map[node]bool
 }
@@ -104,8 +102,8 @@ func newCBiRRTMotionPlanner(
 	if err != nil {
 		return nil, err
 	}
-	// nlopt should try only once
-	nlopt, err := CreateNloptIKSolver(frame, logger, 1)
+	// the solver should try only once
+	fastGradDescent, err := newIKSolver(frame, logger, 1, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +113,7 @@ func newCBiRRTMotionPlanner(
 	}
 	return &cBiRRTMotionPlanner{
 		planner:         mp,
-		fastGradDescent: nlopt,
+		fastGradDescent: fastGradDescent,
 		algOpts:         algOpts,
 		corners:         map[node]bool{},
 	}, nil
@@ -7,6 +7,7 @@ import (
 
 	pb "go.viam.com/api/service/motion/v1"
 
+	"go.viam.com/rdk/pointcloud"
 	"go.viam.com/rdk/referenceframe"
 	spatial "go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/utils"
@@ -242,6 +243,10 @@ func newCollisionGraph(x, y []spatial.Geometry, reference *collisionGraph, repor
 // checkCollision takes a pair of geometries and returns the distance between them.
 // If this number is less than the CollisionBuffer they can be considered to be in collision.
 func (cg *collisionGraph) checkCollision(x, y spatial.Geometry) (float64, error) {
+	// octrees know how to check against the geometries in spatialmath, but not the other way around
+	if _, ok := y.(*pointcloud.BasicOctree); ok {
+		x, y = y, x
+	}
 	if cg.reportDistances {
 		return x.DistanceFrom(y)
 	}
@@ -0,0 +1,149 @@
+package motionplan
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	pb "go.viam.com/api/service/motion/v1"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// defaultCollisionCacheSize is how many collision environments are kept, enough for the handful of poses a pick and place cycle plans from.
+const defaultCollisionCacheSize = 16
+
+// collisionCache keeps the collision constraints built for recent plans, so that planning again in an unchanged world, such as on every
+// cycle of a pick and place task, does not check every geometry against every obstacle from scratch. Entries are keyed by a hash of
+// everything the constraints are built from, and the least recently used entry is dropped once the cache is full.
+type collisionCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]map[string]StateConstraint
+	// order holds the keys of entries, least recently used first.
+	order []string
+}
+
+var planCollisionCache = newCollisionCache(defaultCollisionCacheSize)
+
+func newCollisionCache(size int) *collisionCache {
+	return &collisionCache{size: size, entries: map[string]map[string]StateConstraint{}}
+}
+
+func (cc *collisionCache) get(key string) (map[string]StateConstraint, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	constraints, ok := cc.entries[key]
+	if ok {
+		cc.touch(key)
+	}
+	return constraints, ok
+}
+
+func (cc *collisionCache) put(key string, constraints map[string]StateConstraint) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if _, ok := cc.entries[key]; ok {
+		cc.touch(key)
+	} else {
+		cc.order = append(cc.order, key)
+	}
+	cc.entries[key] = constraints
+	for len(cc.order) > cc.size {
+		delete(cc.entries, cc.order[0])
+		cc.order = cc.order[1:]
+	}
+}
+
+// touch moves the key to the most recently used end of order. It must be called with mu held.
+func (cc *collisionCache) touch(key string) {
+	for i, k := range cc.order {
+		if k == key {
+			cc.order = append(append(cc.order[:i:i], cc.order[i+1:]...), key)
+			return
+		}
+	}
+}
+
+// collisionEnvironmentKey hashes everything the collision constraints of a plan are built from: the allowed collisions, which frames
+// move, the geometry of every frame at the current inputs and the obstacles in the world frame. Any change to the world or to the
+// robot's geometry therefore changes the key.
+func collisionEnvironmentKey(
+	frame *solverFrame,
+	frameSystemGeometries map[string]*referenceframe.GeometriesInFrame,
+	obstacles *referenceframe.GeometriesInFrame,
+	pbConstraint []*pb.CollisionSpecification,
+) (string, error) {
+	h := sha256.New()
+	marshal := proto.MarshalOptions{Deterministic: true}
+	writeGeometries := func(geometries *referenceframe.GeometriesInFrame) error {
+		writeBytes(h, []byte(geometries.Parent()))
+		for _, geometry := range geometries.Geometries() {
+			// an octree is sent over the API as only its bounds, so its points must be hashed too
+			if octree, ok := geometry.(*pointcloud.BasicOctree); ok {
+				octree.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+					writeVector(h, p)
+					return true
+				})
+			}
+			data, err := marshal.Marshal(geometry.ToProtobuf())
+			if err != nil {
+				return err
+			}
+			writeBytes(h, data)
+		}
+		return nil
+	}
+
+	for _, spec := range pbConstraint {
+		data, err := marshal.Marshal(spec)
+		if err != nil {
+			return "", err
+		}
+		writeBytes(h, data)
+	}
+	for _, name := range sortedKeys(frameSystemGeometries) {
+		writeBytes(h, []byte(name))
+		if frame.movingFrame(name) {
+			writeBytes(h, []byte("moving"))
+		}
+		if err := writeGeometries(frameSystemGeometries[name]); err != nil {
+			return "", err
+		}
+	}
+	if err := writeGeometries(obstacles); err != nil {
+		return "", err
+	}
+	return string(h.Sum(nil)), nil
+}
+
+// writeBytes writes data to the hash prefixed with its length, so that consecutive fields cannot run together.
+func writeBytes(h hash.Hash, data []byte) {
+	var length [8]byte
+	binary.LittleEndian.PutUint64(length[:], uint64(len(data)))
+	h.Write(length[:])
+	h.Write(data)
+}
+
+func writeVector(h hash.Hash, v r3.Vector) {
+	var bits [24]byte
+	binary.LittleEndian.PutUint64(bits[:], math.Float64bits(v.X))
+	binary.LittleEndian.PutUint64(bits[8:], math.Float64bits(v.Y))
+	binary.LittleEndian.PutUint64(bits[16:], math.Float64bits(v.Z))
+	h.Write(bits[:])
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
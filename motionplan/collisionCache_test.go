@@ -0,0 +1,71 @@
+package motionplan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestCollisionCache(t *testing.T) {
+	model, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm6_kinematics.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, model.Name(), frame.World, frame.StartPositions(fs))
+	test.That(t, err, test.ShouldBeNil)
+
+	worldWithBoxAt := func(pt r3.Vector) *frame.WorldState {
+		box, err := spatial.NewBox(spatial.NewPoseFromPoint(pt), r3.Vector{2, 2, 2}, "box")
+		test.That(t, err, test.ShouldBeNil)
+		ws, err := frame.NewWorldState([]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, []spatial.Geometry{box})}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		return ws
+	}
+	sameMap := func(a, b map[string]StateConstraint) bool {
+		return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+	}
+
+	inputs := frame.StartPositions(fs)
+	first, err := createAllCollisionConstraints(sf, fs, worldWithBoxAt(r3.Vector{-130, 0, 300}), inputs, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("an unchanged world is cached", func(t *testing.T) {
+		again, err := createAllCollisionConstraints(sf, fs, worldWithBoxAt(r3.Vector{-130, 0, 300}), inputs, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, sameMap(first, again), test.ShouldBeTrue)
+	})
+
+	t.Run("a moved obstacle is not cached", func(t *testing.T) {
+		moved, err := createAllCollisionConstraints(sf, fs, worldWithBoxAt(r3.Vector{-130, 0, 301}), inputs, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, sameMap(first, moved), test.ShouldBeFalse)
+	})
+
+	t.Run("different robot inputs are not cached", func(t *testing.T) {
+		moved := map[string][]frame.Input{model.Name(): frame.FloatsToInputs([]float64{1, 0, 0, 0, 0, 0})}
+		constraints, err := createAllCollisionConstraints(sf, fs, worldWithBoxAt(r3.Vector{-130, 0, 300}), moved, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, sameMap(first, constraints), test.ShouldBeFalse)
+	})
+
+	t.Run("the least recently used entry is dropped", func(t *testing.T) {
+		cc := newCollisionCache(2)
+		cc.put("a", map[string]StateConstraint{})
+		cc.put("b", map[string]StateConstraint{})
+		_, ok := cc.get("a")
+		test.That(t, ok, test.ShouldBeTrue)
+		cc.put("c", map[string]StateConstraint{})
+		_, ok = cc.get("b")
+		test.That(t, ok, test.ShouldBeFalse)
+		_, ok = cc.get("a")
+		test.That(t, ok, test.ShouldBeTrue)
+		_, ok = cc.get("c")
+		test.That(t, ok, test.ShouldBeTrue)
+	})
+}
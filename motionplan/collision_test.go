@@ -6,6 +6,7 @@ import (
 	"github.com/golang/geo/r3"
 	"go.viam.com/test"
 
+	"go.viam.com/rdk/pointcloud"
 	frame "go.viam.com/rdk/referenceframe"
 	spatial "go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/utils"
@@ -117,3 +118,30 @@ func TestUniqueCollisions(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, collisionListsAlmostEqual(cg.collisions(), expectedCollisions[:1]), test.ShouldBeTrue)
 }
+
+func TestOctreeCollisions(t *testing.T) {
+	pc := pointcloud.New()
+	for _, pt := range []r3.Vector{{X: 100}, {X: 110}, {X: 100, Y: 10}} {
+		test.That(t, pc.Set(pt, nil), test.ShouldBeNil)
+	}
+	octree, err := pointcloud.NewBasicOctreeFromPointCloud(pc)
+	test.That(t, err, test.ShouldBeNil)
+	octree.SetLabel("cloud")
+
+	hit, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{X: 100}), r3.Vector{2, 2, 2}, "hit")
+	test.That(t, err, test.ShouldBeNil)
+	miss, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{X: 100, Z: 10}), r3.Vector{2, 2, 2}, "miss")
+	test.That(t, err, test.ShouldBeNil)
+
+	// the octree can be on either side of the collision graph
+	for _, reportDistances := range []bool{true, false} {
+		cg, err := newCollisionGraph([]spatial.Geometry{hit, miss}, []spatial.Geometry{octree}, nil, reportDistances)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, cg.collisionBetween("hit", "cloud"), test.ShouldBeTrue)
+		test.That(t, cg.collisionBetween("miss", "cloud"), test.ShouldBeFalse)
+
+		cg, err = newCollisionGraph([]spatial.Geometry{octree}, []spatial.Geometry{miss}, nil, reportDistances)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, cg.collisions(), test.ShouldBeEmpty)
+	}
+}
@@ -1,5 +1,3 @@
-//go:build !windows
-
 package motionplan
 
 import (
@@ -20,9 +18,9 @@ type CombinedIK struct {
 	logger  golog.Logger
 }
 
-// CreateCombinedIKSolver creates a combined parallel IK solver with a number of nlopt solvers equal to the nCPU
-// passed in. Each will be given a different random seed. When asked to solve, all solvers will be run in parallel
-// and the first valid found solution will be returned.
+// CreateCombinedIKSolver creates a combined parallel IK solver with a number of solvers equal to the nCPU passed in. These are nlopt
+// solvers, except on Windows where nlopt cannot be built and pure Go BFGS solvers are used instead. Each will be given a different
+// random seed. When asked to solve, all solvers will be run in parallel and the first valid found solution will be returned.
 func CreateCombinedIKSolver(model referenceframe.Frame, logger golog.Logger, nCPU int) (*CombinedIK, error) {
 	ik := &CombinedIK{}
 	ik.model = model
@@ -30,12 +28,11 @@ func CreateCombinedIKSolver(model referenceframe.Frame, logger golog.Logger, nCP
 		nCPU = 1
 	}
 	for i := 1; i <= nCPU; i++ {
-		nlopt, err := CreateNloptIKSolver(model, logger, -1)
-		nlopt.id = i
+		solver, err := newIKSolver(model, logger, -1, i)
 		if err != nil {
 			return nil, err
 		}
-		ik.solvers = append(ik.solvers, nlopt)
+		ik.solvers = append(ik.solvers, solver)
 	}
 	ik.logger = logger
 	return ik, nil
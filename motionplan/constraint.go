@@ -273,6 +273,14 @@ func createAllCollisionConstraints(
 		return nil, err
 	}
 
+	key, err := collisionEnvironmentKey(frame, frameSystemGeometries, obstacles, pbConstraint)
+	if err != nil {
+		return nil, err
+	}
+	if constraints, ok := planCollisionCache.get(key); ok {
+		return constraints, nil
+	}
+
 	allowedCollisions, err := collisionSpecificationsFromProto(pbConstraint, frameSystemGeometries, worldState)
 	if err != nil {
 		return nil, err
@@ -301,11 +309,13 @@ func createAllCollisionConstraints(
 	if err != nil {
 		return nil, err
 	}
-	return map[string]StateConstraint{
+	constraints := map[string]StateConstraint{
 		defaultObstacleConstraintDesc:       obstacleConstraint,
 		defaultSelfCollisionConstraintDesc:  selfCollisionConstraint,
 		defaultRobotCollisionConstraintDesc: robotConstraint,
-	}, nil
+	}
+	planCollisionCache.put(key, constraints)
+	return constraints, nil
 }
 
 // newCollisionConstraint is the most general method to create a collision constraint, which will be violated if geometries constituting
@@ -457,6 +467,67 @@ func NewLineConstraint(pt1, pt2 r3.Vector, tolerance float64) (StateConstraint,
 	return validFunc, gradFunc
 }
 
+// NewFixedOrientationConstraint returns a constraint which will determine whether an orientation is within tolerance degrees of the given
+// orientation, as well as a metric which returns how far past that tolerance an orientation is. This keeps the orientation of the moving
+// frame fixed throughout a path, for example to carry an open container without spilling it.
+func NewFixedOrientationConstraint(orientation spatial.Orientation, tolerance float64) (StateConstraint, StateMetric) {
+	gradFunc := func(state *State) float64 {
+		return math.Max(orientDist(orientation, state.Position.Orientation())-tolerance, 0)
+	}
+
+	validFunc := func(state *State) bool {
+		err := resolveStatesToPositions(state)
+		if err != nil {
+			return false
+		}
+		return gradFunc(state) == 0
+	}
+
+	return validFunc, gradFunc
+}
+
+// NewInfiniteLineConstraint is used to define a constraint space for the line through pt in the given direction, and will return 1) a
+// constraint function which will determine whether a point is within tolerance mm of the line, and 2) a distance function which will
+// bring a pose into the valid constraint space. Unlike NewLineConstraint, the line does not end.
+func NewInfiniteLineConstraint(pt, direction r3.Vector, tolerance float64) (StateConstraint, StateMetric) {
+	dir := direction.Normalize()
+
+	gradFunc := func(state *State) float64 {
+		return math.Max(state.Position.Point().Sub(pt).Cross(dir).Norm()-tolerance, 0)
+	}
+
+	validFunc := func(state *State) bool {
+		err := resolveStatesToPositions(state)
+		if err != nil {
+			return false
+		}
+		return gradFunc(state) == 0
+	}
+
+	return validFunc, gradFunc
+}
+
+// NewPositionPlaneConstraint is used to define a constraint space for the plane through pt with the given normal, and will return 1) a
+// constraint function which will determine whether a point is within tolerance mm of the plane, and 2) a distance function which will
+// bring a pose into the valid constraint space. Unlike NewPlaneConstraint, it places no restriction on orientation.
+func NewPositionPlaneConstraint(pNorm, pt r3.Vector, tolerance float64) (StateConstraint, StateMetric) {
+	norm := pNorm.Normalize()
+
+	gradFunc := func(state *State) float64 {
+		return math.Max(math.Abs(state.Position.Point().Sub(pt).Dot(norm))-tolerance, 0)
+	}
+
+	validFunc := func(state *State) bool {
+		err := resolveStatesToPositions(state)
+		if err != nil {
+			return false
+		}
+		return gradFunc(state) == 0
+	}
+
+	return validFunc, gradFunc
+}
+
 // NewOctreeCollisionConstraint takes an octree and will return a constraint that checks whether any of the geometries in the solver frame
 // intersect with points in the octree. Threshold sets the confidence level required for a point to be considered, and buffer is the
 // distance to a point that is considered a collision in mm.
@@ -270,3 +270,46 @@ func BenchmarkCollisionConstraints(b *testing.B) {
 	}
 	bt = b1
 }
+
+func TestToolConstraints(t *testing.T) {
+	at := func(pt r3.Vector, o spatial.Orientation) *State {
+		return &State{Position: spatial.NewPose(pt, o)}
+	}
+	level := &spatial.OrientationVectorDegrees{OZ: 1}
+	tilted := &spatial.OrientationVectorDegrees{OX: 1}
+
+	t.Run("fixed orientation", func(t *testing.T) {
+		constraint, metric := NewFixedOrientationConstraint(level, 2)
+		test.That(t, constraint(at(r3.Vector{X: 500}, level)), test.ShouldBeTrue)
+		test.That(t, constraint(at(r3.Vector{}, tilted)), test.ShouldBeFalse)
+		test.That(t, metric(at(r3.Vector{}, tilted)), test.ShouldAlmostEqual, 88)
+	})
+
+	t.Run("line", func(t *testing.T) {
+		constraint, metric := NewInfiniteLineConstraint(r3.Vector{Y: 10}, r3.Vector{X: 2}, 1)
+		test.That(t, constraint(at(r3.Vector{X: -1000, Y: 10.5}, tilted)), test.ShouldBeTrue)
+		test.That(t, constraint(at(r3.Vector{X: 5, Y: 20}, level)), test.ShouldBeFalse)
+		test.That(t, metric(at(r3.Vector{X: 5, Y: 20}, level)), test.ShouldAlmostEqual, 9)
+	})
+
+	t.Run("plane", func(t *testing.T) {
+		constraint, metric := NewPositionPlaneConstraint(r3.Vector{Z: 3}, r3.Vector{Z: 100}, 1)
+		test.That(t, constraint(at(r3.Vector{X: 300, Y: -200, Z: 100}, tilted)), test.ShouldBeTrue)
+		test.That(t, constraint(at(r3.Vector{Z: 50}, level)), test.ShouldBeFalse)
+		test.That(t, metric(at(r3.Vector{Z: 50}, level)), test.ShouldAlmostEqual, 49)
+	})
+
+	t.Run("goal must satisfy constraints", func(t *testing.T) {
+		from := spatial.NewPose(r3.Vector{}, level)
+		opt := newBasicPlannerOptions()
+		added, err := opt.addToolConstraints(from, spatial.NewPose(r3.Vector{X: 100}, level),
+			&ToolConstraints{OrientationLock: &OrientationLock{}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, added, test.ShouldBeTrue)
+		test.That(t, opt.StateConstraints(), test.ShouldContain, defaultFixedOrientationConstraintDesc)
+
+		_, err = newBasicPlannerOptions().addToolConstraints(from, spatial.NewPose(r3.Vector{X: 100}, tilted),
+			&ToolConstraints{OrientationLock: &OrientationLock{}})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
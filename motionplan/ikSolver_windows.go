@@ -0,0 +1,21 @@
+//go:build windows
+
+package motionplan
+
+import (
+	"github.com/edaniels/golog"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+// newIKSolver creates the inverse kinematics solver used on this platform. nlopt cannot be built on Windows, so the pure Go BFGS
+// solver is used instead. The id decides how the solver seeds itself: 1 seeds off the given inputs, higher ids use random seeds, and 0
+// only ever starts from the given inputs.
+func newIKSolver(model referenceframe.Frame, logger golog.Logger, iter, id int) (InverseKinematics, error) {
+	ik, err := CreateBFGSIKSolver(model, logger, iter)
+	if err != nil {
+		return nil, err
+	}
+	ik.id = id
+	return ik, nil
+}
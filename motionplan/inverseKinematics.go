@@ -2,10 +2,23 @@ package motionplan
 
 import (
 	"context"
+	"math"
+	"math/rand"
+
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/referenceframe"
 )
 
+var (
+	errNoSolve     = errors.New("kinematics could not solve for position")
+	errBadBounds   = errors.New("cannot set upper or lower bounds for nlopt, slice is empty. Are you trying to move a static frame?")
+	errTooManyVals = errors.New("passed in too many joint positions")
+)
+
+// constrainedTries is how many times a solver seeded off the current inputs narrows its bounds around them before trying random seeds.
+const constrainedTries = 30
+
 // InverseKinematics defines an interface which, provided with seed inputs and a Metric to minimize to zero, will output all found
 // solutions to the provided channel until cancelled or otherwise completes.
 type InverseKinematics interface {
@@ -21,3 +34,45 @@ func limitsToArrays(limits []referenceframe.Limit) ([]float64, []float64) {
 	}
 	return min, max
 }
+
+// randomInputs generates a random set of inputs within the given limits.
+func randomInputs(lowerBound, upperBound []float64, randSeed *rand.Rand) []referenceframe.Input {
+	pos := make([]referenceframe.Input, len(lowerBound))
+	for i, l := range lowerBound {
+		u := upperBound[i]
+
+		// Default to [-999,999] as range if limits are infinite
+		if l == math.Inf(-1) {
+			l = -999
+		}
+		if u == math.Inf(1) {
+			u = 999
+		}
+
+		jRange := math.Abs(u - l)
+		// Note that rand is unseeded and so will produce the same sequence of floats every time
+		// However, since this will presumably happen at different positions to different joints, this shouldn't matter
+		pos[i] = referenceframe.Input{randSeed.Float64()*jRange + l}
+	}
+	return pos
+}
+
+// constrainedBounds returns limits narrowed around the seed to disincentivise large swings before small swings have been tried.
+// The limits widen with the number of tries.
+func constrainedBounds(lowerBound, upperBound []float64, seed []referenceframe.Input, tries int) ([]float64, []float64) {
+	rangeStep := 0.1
+	newLower := make([]float64, len(lowerBound))
+	newUpper := make([]float64, len(upperBound))
+
+	for i, pos := range seed {
+		newLower[i] = math.Max(lowerBound[i], pos.Value-(rangeStep*float64(tries*(i+1))))
+		newUpper[i] = math.Min(upperBound[i], pos.Value+(rangeStep*float64(tries*(i+1))))
+
+		// Allow full freedom of movement for the two most distal joints
+		if i > len(seed)-2 {
+			newLower[i] = lowerBound[i]
+			newUpper[i] = upperBound[i]
+		}
+	}
+	return newLower, newUpper
+}
@@ -0,0 +1,104 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	pb "go.viam.com/api/service/motion/v1"
+	"go.viam.com/utils"
+
+	frame "go.viam.com/rdk/referenceframe"
+)
+
+// PlanMotionToAny plans a motion for the given frame to whichever of several goals it can best reach, such as the candidate poses of
+// a grasp. Every goal is planned to concurrently across the available CPU cores, and the plan that moves the robot's joints the least
+// is returned along with the index of the goal it reaches. An error is only returned if no goal can be reached.
+func PlanMotionToAny(ctx context.Context,
+	logger golog.Logger,
+	goals []*frame.PoseInFrame,
+	f frame.Frame,
+	seedMap map[string][]frame.Input,
+	fs frame.FrameSystem,
+	worldState *frame.WorldState,
+	constraintSpec *pb.Constraints,
+	planningOpts map[string]interface{},
+) ([]map[string][]frame.Input, int, error) {
+	if len(goals) == 0 {
+		return nil, -1, errors.New("no destinations passed to Motion")
+	}
+
+	// share the cores between the goals, rather than every goal starting as many IK solvers as planning to a single goal would
+	if _, ok := planningOpts["num_threads"]; !ok {
+		planningOpts = deepAtomicCopyMap(planningOpts)
+		planningOpts["num_threads"] = int(math.Max(1, float64(runtime.NumCPU()/len(goals))))
+	}
+
+	type goalResult struct {
+		steps []map[string][]frame.Input
+		err   error
+	}
+	results := make([]goalResult, len(goals))
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i, goal := range goals {
+		i, goal := i, goal
+		wg.Add(1)
+		utils.PanicCapturingGo(func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[i].err = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+			results[i].steps, results[i].err = motionPlanInternal(
+				ctx,
+				logger,
+				goal,
+				f,
+				seedMap,
+				fs,
+				worldState,
+				constraintSpec,
+				planningOpts,
+			)
+		})
+	}
+	wg.Wait()
+
+	best := -1
+	bestCost := math.Inf(1)
+	var errs error
+	for i, result := range results {
+		if result.err != nil {
+			errs = multierr.Combine(errs, errors.Wrapf(result.err, "goal %d", i))
+			continue
+		}
+		if cost := jointPathLength(result.steps); best < 0 || cost < bestCost {
+			best = i
+			bestCost = cost
+		}
+	}
+	if best < 0 {
+		return nil, -1, errors.Wrap(errs, "no goal could be reached")
+	}
+	logger.Debugf("planned to goal %d of %d with joint path length %f", best, len(goals), bestCost)
+	return results[best].steps, best, nil
+}
+
+// jointPathLength returns the total distance every frame's inputs move through over the steps of a plan.
+func jointPathLength(steps []map[string][]frame.Input) float64 {
+	length := 0.
+	for i := 1; i < len(steps); i++ {
+		for name, inputs := range steps[i] {
+			length += math.Sqrt(L2InputMetric(&Segment{StartConfiguration: steps[i-1][name], EndConfiguration: inputs}))
+		}
+	}
+	return length
+}
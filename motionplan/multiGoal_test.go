@@ -0,0 +1,78 @@
+package motionplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestPlanMotionToAny(t *testing.T) {
+	fs := makeTestFS(t)
+	positions := frame.StartPositions(fs)
+	unreachable := frame.NewPoseInFrame(
+		frame.World,
+		spatialmath.NewPose(r3.Vector{X: 257, Y: 21000, Z: -300}, &spatialmath.OrientationVectorDegrees{OZ: -1}),
+	)
+	reachable := frame.NewPoseInFrame(
+		frame.World,
+		spatialmath.NewPose(r3.Vector{X: 257, Y: 2100, Z: -300}, &spatialmath.OrientationVectorDegrees{OZ: -1}),
+	)
+
+	t.Run("the reachable goal is planned to", func(t *testing.T) {
+		plan, goal, err := PlanMotionToAny(
+			context.Background(),
+			logger.Sugar(),
+			[]*frame.PoseInFrame{unreachable, reachable},
+			fs.Frame("xArmVgripper"),
+			positions,
+			fs,
+			nil,
+			nil,
+			nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, goal, test.ShouldEqual, 1)
+		solvedPose, err := fs.Transform(
+			plan[len(plan)-1],
+			frame.NewPoseInFrame("xArmVgripper", spatialmath.NewZeroPose()),
+			frame.World,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(solvedPose.(*frame.PoseInFrame).Pose(), reachable.Pose(), 0.01), test.ShouldBeTrue)
+	})
+
+	t.Run("no reachable goal is an error", func(t *testing.T) {
+		_, goal, err := PlanMotionToAny(
+			context.Background(),
+			logger.Sugar(),
+			[]*frame.PoseInFrame{unreachable},
+			fs.Frame("xArmVgripper"),
+			positions,
+			fs,
+			nil,
+			nil,
+			nil,
+		)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, goal, test.ShouldEqual, -1)
+	})
+
+	t.Run("no goals is an error", func(t *testing.T) {
+		_, _, err := PlanMotionToAny(context.Background(), logger.Sugar(), nil, fs.Frame("xArmVgripper"), positions, fs, nil, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestJointPathLength(t *testing.T) {
+	steps := []map[string][]frame.Input{
+		{"a": frame.FloatsToInputs([]float64{0, 0}), "b": frame.FloatsToInputs([]float64{0})},
+		{"a": frame.FloatsToInputs([]float64{3, 4}), "b": frame.FloatsToInputs([]float64{1})},
+		{"a": frame.FloatsToInputs([]float64{3, 4}), "b": frame.FloatsToInputs([]float64{-1})},
+	}
+	test.That(t, jointPathLength(steps), test.ShouldAlmostEqual, 8)
+}
@@ -16,16 +16,7 @@ import (
 	"go.viam.com/rdk/referenceframe"
 )
 
-var (
-	errNoSolve     = errors.New("kinematics could not solve for position")
-	errBadBounds   = errors.New("cannot set upper or lower bounds for nlopt, slice is empty. Are you trying to move a static frame?")
-	errTooManyVals = errors.New("passed in too many joint positions")
-)
-
-const (
-	constrainedTries  = 30
-	nloptStepsPerIter = 4001
-)
+const nloptStepsPerIter = 4001
 
 // NloptIK TODO.
 type NloptIK struct {
@@ -219,24 +210,7 @@ func (ik *NloptIK) Solve(ctx context.Context,
 
 // GenerateRandomPositions generates a random set of positions within the limits of this solver.
 func (ik *NloptIK) GenerateRandomPositions(randSeed *rand.Rand) []referenceframe.Input {
-	pos := make([]referenceframe.Input, len(ik.model.DoF()))
-	for i, l := range ik.lowerBound {
-		u := ik.upperBound[i]
-
-		// Default to [-999,999] as range if limits are infinite
-		if l == math.Inf(-1) {
-			l = -999
-		}
-		if u == math.Inf(1) {
-			u = 999
-		}
-
-		jRange := math.Abs(u - l)
-		// Note that rand is unseeded and so will produce the same sequence of floats every time
-		// However, since this will presumably happen at different positions to different joints, this shouldn't matter
-		pos[i] = referenceframe.Input{randSeed.Float64()*jRange + l}
-	}
-	return pos
+	return randomInputs(ik.lowerBound, ik.upperBound, randSeed)
 }
 
 // Frame returns the associated referenceframe.
@@ -247,20 +221,7 @@ func (ik *NloptIK) Frame() referenceframe.Frame {
 // updateBounds will set the allowable maximum/minimum joint angles to disincentivise large swings before small swings
 // have been tried.
 func (ik *NloptIK) updateBounds(seed []referenceframe.Input, tries int, opt *nlopt.NLopt) error {
-	rangeStep := 0.1
-	newLower := make([]float64, len(ik.lowerBound))
-	newUpper := make([]float64, len(ik.upperBound))
-
-	for i, pos := range seed {
-		newLower[i] = math.Max(ik.lowerBound[i], pos.Value-(rangeStep*float64(tries*(i+1))))
-		newUpper[i] = math.Min(ik.upperBound[i], pos.Value+(rangeStep*float64(tries*(i+1))))
-
-		// Allow full freedom of movement for the two most distal joints
-		if i > len(seed)-2 {
-			newLower[i] = ik.lowerBound[i]
-			newUpper[i] = ik.upperBound[i]
-		}
-	}
+	newLower, newUpper := constrainedBounds(ik.lowerBound, ik.upperBound, seed, tries)
 	return multierr.Combine(
 		opt.SetLowerBounds(newLower),
 		opt.SetUpperBounds(newUpper),
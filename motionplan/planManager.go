@@ -103,10 +103,9 @@ func (pm *planManager) PlanSingleWaypoint(ctx context.Context,
 		}
 		numSteps := PathStepCount(seedPos, goalPos, pathStepSize)
 
+		waypoints := spatialmath.InterpolatePoses(seedPos, goalPos, numSteps)
 		from := seedPos
-		for i := 1; i < numSteps; i++ {
-			by := float64(i) / float64(numSteps)
-			to := spatialmath.Interpolate(seedPos, goalPos, by)
+		for _, to := range waypoints[1 : len(waypoints)-1] {
 			goals = append(goals, to)
 			opt, err := pm.plannerSetupFromMoveRequest(from, to, seedMap, worldState, constraintSpec, motionConfig)
 			if err != nil {
@@ -352,7 +351,7 @@ func (pm *planManager) planParallelRRTMotion(
 		// Start smoothing before initializing the fallback plan. This allows both to run simultaneously.
 		smoothChan := make(chan []node, 1)
 		utils.PanicCapturingGo(func() {
-			smoothChan <- pathPlanner.smoothPath(ctx, finalSteps.steps)
+			smoothChan <- smoothAndShortcut(ctx, pathPlanner, finalSteps.steps)
 		})
 		var alternateFuture *resultPromise
 
@@ -433,7 +432,15 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 	}
 
 	hasTopoConstraint := opt.addPbTopoConstraints(from, to, constraints)
-	if hasTopoConstraint {
+	requestOpts, err := RequestOptionsFromExtra(planningOpts)
+	if err != nil {
+		return nil, err
+	}
+	hasToolConstraint, err := opt.addToolConstraints(from, to, requestOpts.ToolConstraints)
+	if err != nil {
+		return nil, err
+	}
+	if hasTopoConstraint || hasToolConstraint {
 		planAlg = "cbirrt"
 	}
 
@@ -463,6 +470,9 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 		if !ok {
 			return nil, errors.New("could not interpret planning_alg field as string")
 		}
+		if hasToolConstraint && planAlg != "cbirrt" {
+			return nil, fmt.Errorf("%s can only be planned with cbirrt, not planning_alg %q", ToolConstraintsKey, planAlg)
+		}
 		switch planAlg {
 		// TODO(pl): make these consts
 		case "cbirrt":
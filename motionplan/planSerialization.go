@@ -0,0 +1,64 @@
+package motionplan
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+// PlanFormatVersion is the version of the serialized plan format written by MarshalPlan. It changes whenever the format does, so that
+// plans saved by an older version can be recognized rather than misread.
+const PlanFormatVersion = 1
+
+// SerializedPlan is the stable form of a plan made by PlanMotion, as saved by MarshalPlan. Each step maps the name of every frame in the
+// plan to its inputs, in meters or radians.
+type SerializedPlan struct {
+	Version int                    `json:"version"`
+	Steps   []map[string][]float64 `json:"steps"`
+}
+
+// NewSerializedPlan converts a plan into its serialized form.
+func NewSerializedPlan(plan []map[string][]referenceframe.Input) SerializedPlan {
+	steps := make([]map[string][]float64, 0, len(plan))
+	for _, step := range plan {
+		floats := make(map[string][]float64, len(step))
+		for name, inputs := range step {
+			floats[name] = referenceframe.InputsToFloats(inputs)
+		}
+		steps = append(steps, floats)
+	}
+	return SerializedPlan{Version: PlanFormatVersion, Steps: steps}
+}
+
+// Plan converts the serialized plan back into a plan that can be executed.
+func (sp SerializedPlan) Plan() ([]map[string][]referenceframe.Input, error) {
+	if sp.Version != PlanFormatVersion {
+		return nil, errors.Errorf("cannot read plan format version %d, only version %d is supported", sp.Version, PlanFormatVersion)
+	}
+	plan := make([]map[string][]referenceframe.Input, 0, len(sp.Steps))
+	for _, step := range sp.Steps {
+		inputs := make(map[string][]referenceframe.Input, len(step))
+		for name, floats := range step {
+			inputs[name] = referenceframe.FloatsToInputs(floats)
+		}
+		plan = append(plan, inputs)
+	}
+	return plan, nil
+}
+
+// MarshalPlan serializes a plan to indented JSON. Frames are written in sorted order, so the same plan always serializes the same way
+// and plans from different planner versions can be diffed line by line.
+func MarshalPlan(plan []map[string][]referenceframe.Input) ([]byte, error) {
+	return json.MarshalIndent(NewSerializedPlan(plan), "", "  ")
+}
+
+// UnmarshalPlan reads a plan serialized by MarshalPlan.
+func UnmarshalPlan(data []byte) ([]map[string][]referenceframe.Input, error) {
+	var sp SerializedPlan
+	if err := json.Unmarshal(data, &sp); err != nil {
+		return nil, err
+	}
+	return sp.Plan()
+}
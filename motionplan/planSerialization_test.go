@@ -0,0 +1,52 @@
+package motionplan
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+func TestPlanSerialization(t *testing.T) {
+	plan := []map[string][]referenceframe.Input{
+		{"arm": referenceframe.FloatsToInputs([]float64{0, 0.5}), "gantry": referenceframe.FloatsToInputs([]float64{10})},
+		{"arm": referenceframe.FloatsToInputs([]float64{0.25, 1}), "gantry": referenceframe.FloatsToInputs([]float64{20})},
+	}
+	golden := `{
+  "version": 1,
+  "steps": [
+    {
+      "arm": [
+        0,
+        0.5
+      ],
+      "gantry": [
+        10
+      ]
+    },
+    {
+      "arm": [
+        0.25,
+        1
+      ],
+      "gantry": [
+        20
+      ]
+    }
+  ]
+}`
+
+	data, err := MarshalPlan(plan)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(data), test.ShouldEqual, golden)
+
+	replayed, err := UnmarshalPlan(data)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, replayed, test.ShouldResemble, plan)
+
+	_, err = UnmarshalPlan([]byte(`{"version": 2, "steps": []}`))
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = UnmarshalPlan([]byte(`not json`))
+	test.That(t, err, test.ShouldNotBeNil)
+}
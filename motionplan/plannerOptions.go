@@ -39,13 +39,19 @@ const (
 	// default number of times to try to smooth the path.
 	defaultSmoothIter = 20
 
+	// default for whether to shortcut a smoothed path.
+	defaultShortcut = true
+
 	// descriptions of constraints.
-	defaultLinearConstraintDesc         = "Constraint to follow linear path"
-	defaultPseudolinearConstraintDesc   = "Constraint to follow pseudolinear path, with tolerance scaled to path length"
-	defaultOrientationConstraintDesc    = "Constraint to maintain orientation within bounds"
-	defaultObstacleConstraintDesc       = "Collision between the robot and an obstacle"
-	defaultSelfCollisionConstraintDesc  = "Collision between two robot components that are moving"
-	defaultRobotCollisionConstraintDesc = "Collision between a robot component that is moving and one that is stationary"
+	defaultLinearConstraintDesc           = "Constraint to follow linear path"
+	defaultPseudolinearConstraintDesc     = "Constraint to follow pseudolinear path, with tolerance scaled to path length"
+	defaultOrientationConstraintDesc      = "Constraint to maintain orientation within bounds"
+	defaultFixedOrientationConstraintDesc = "Constraint to hold orientation fixed"
+	defaultLineConstraintDesc             = "Constraint to stay on a line"
+	defaultPlaneConstraintDesc            = "Constraint to stay on a plane"
+	defaultObstacleConstraintDesc         = "Collision between the robot and an obstacle"
+	defaultSelfCollisionConstraintDesc    = "Collision between two robot components that are moving"
+	defaultRobotCollisionConstraintDesc   = "Collision between a robot component that is moving and one that is stationary"
 
 	// When breaking down a path into smaller waypoints, add a waypoint every this many mm of movement.
 	defaultPathStepSize = 10
@@ -85,6 +91,7 @@ func newBasicPlannerOptions() *plannerOptions {
 	opt.PlannerConstructor = newCBiRRTMotionPlanner
 
 	opt.SmoothIter = defaultSmoothIter
+	opt.Shortcut = defaultShortcut
 
 	opt.NumThreads = defaultNumThreads
 
@@ -119,6 +126,12 @@ type plannerOptions struct {
 	// Number of times to try to smooth the path
 	SmoothIter int `json:"smooth_iter"`
 
+	// Number of seconds smoothing may run for, if positive. Otherwise smoothing runs until it is done or planning times out
+	SmoothTimeBudget float64 `json:"smooth_time_budget"`
+
+	// Whether to shortcut the smoothed path by connecting each waypoint directly to the furthest later waypoint it can reach
+	Shortcut bool `json:"shortcut"`
+
 	// Number of cpu cores to use
 	NumThreads int `json:"num_threads"`
 
@@ -0,0 +1,79 @@
+package motionplan
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Keys of the extra map of a motion request for the options that the Constraints message has no fields for. They are all read
+// and validated by RequestOptionsFromExtra.
+const (
+	// MaxLinearVelocityKey limits how fast the moving frame travels, in mm/s.
+	MaxLinearVelocityKey = "max_linear_velocity_mm_per_sec"
+	// MaxAngularVelocityKey limits how fast the moving frame turns, in degs/s.
+	MaxAngularVelocityKey = "max_angular_velocity_degs_per_sec"
+	// MaxLinearAccelerationKey limits how fast the moving frame speeds up and slows down, in mm/s^2. It requires
+	// MaxLinearVelocityKey.
+	MaxLinearAccelerationKey = "max_linear_acceleration_mm_per_sec_per_sec"
+	// MaxAngularAccelerationKey limits how fast the turning of the moving frame speeds up and slows down, in degs/s^2. It
+	// requires MaxAngularVelocityKey.
+	MaxAngularAccelerationKey = "max_angular_acceleration_degs_per_sec_per_sec"
+	// ToolConstraintsKey holds ToolConstraints, encoded as they are in JSON. They can only be planned with cbirrt.
+	ToolConstraintsKey = "tool_constraints"
+)
+
+// RequestOptions are the options of a motion request that are given in its extra map.
+type RequestOptions struct {
+	PathLimits      PathLimits
+	ToolConstraints *ToolConstraints
+}
+
+// RequestOptionsFromExtra reads the options out of the extra map of a motion request and ensures they are valid. Options that
+// are not given are left at their zero values.
+func RequestOptionsFromExtra(extra map[string]interface{}) (RequestOptions, error) {
+	var opts RequestOptions
+	for key, limit := range map[string]*float64{
+		MaxLinearVelocityKey:      &opts.PathLimits.LinearVelocityMmPerSec,
+		MaxAngularVelocityKey:     &opts.PathLimits.AngularVelocityDegsPerSec,
+		MaxLinearAccelerationKey:  &opts.PathLimits.LinearAccelerationMmPerSec2,
+		MaxAngularAccelerationKey: &opts.PathLimits.AngularAccelerationDegsPerSec2,
+	} {
+		raw, ok := extra[key]
+		if !ok {
+			continue
+		}
+		switch v := raw.(type) {
+		case float64:
+			*limit = v
+		case float32:
+			*limit = float64(v)
+		case int:
+			*limit = float64(v)
+		case int64:
+			*limit = float64(v)
+		default:
+			return RequestOptions{}, fmt.Errorf("%s must be a number, got %T", key, raw)
+		}
+	}
+	if err := opts.PathLimits.Validate(); err != nil {
+		return RequestOptions{}, err
+	}
+
+	if raw, ok := extra[ToolConstraintsKey]; ok {
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return RequestOptions{}, err
+		}
+		var tc ToolConstraints
+		if err := json.Unmarshal(data, &tc); err != nil {
+			return RequestOptions{}, errors.Wrapf(err, "could not interpret %s", ToolConstraintsKey)
+		}
+		if err := tc.Validate(); err != nil {
+			return RequestOptions{}, err
+		}
+		opts.ToolConstraints = &tc
+	}
+	return opts, nil
+}
@@ -0,0 +1,84 @@
+package motionplan
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestRequestOptionsFromExtra(t *testing.T) {
+	opts, err := RequestOptionsFromExtra(nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opts.PathLimits.Limited(), test.ShouldBeFalse)
+	test.That(t, opts.ToolConstraints, test.ShouldBeNil)
+
+	opts, err = RequestOptionsFromExtra(map[string]interface{}{
+		MaxLinearVelocityKey:     100.,
+		MaxLinearAccelerationKey: 50,
+		ToolConstraintsKey: map[string]interface{}{
+			"orientation_lock": map[string]interface{}{"tolerance_degs": 5.},
+			"plane": map[string]interface{}{
+				"point":  map[string]interface{}{"x": 0., "y": 0., "z": 100.},
+				"normal": map[string]interface{}{"x": 0., "y": 0., "z": 1.},
+			},
+		},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opts.PathLimits, test.ShouldResemble, PathLimits{LinearVelocityMmPerSec: 100, LinearAccelerationMmPerSec2: 50})
+	test.That(t, opts.PathLimits.Limited(), test.ShouldBeTrue)
+	test.That(t, opts.ToolConstraints.OrientationLock.ToleranceDegs, test.ShouldEqual, 5.)
+	test.That(t, opts.ToolConstraints.Line, test.ShouldBeNil)
+	test.That(t, opts.ToolConstraints.Plane.Point, test.ShouldResemble, r3.Vector{Z: 100})
+
+	for _, extra := range []map[string]interface{}{
+		{MaxAngularVelocityKey: "fast"},
+		{MaxLinearVelocityKey: -1.},
+		{MaxAngularAccelerationKey: 10.},
+		{ToolConstraintsKey: map[string]interface{}{"line": map[string]interface{}{"point": map[string]interface{}{"x": 1.}}}},
+		{ToolConstraintsKey: "level"},
+	} {
+		_, err = RequestOptionsFromExtra(extra)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+func TestToolConstraintsPlanningAlg(t *testing.T) {
+	xarm, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm7_kinematics.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(xarm, fs.World()), test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, xarm.Name(), frame.World, frame.StartPositions(fs))
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+
+	from := spatialmath.NewPoseFromPoint(r3.Vector{X: 300})
+	to := spatialmath.NewPoseFromPoint(r3.Vector{X: 400})
+	onPlane := map[string]interface{}{"plane": map[string]interface{}{"normal": map[string]interface{}{"z": 1.}}}
+
+	opt, err := pm.plannerSetupFromMoveRequest(from, to, frame.StartPositions(fs), nil, nil, map[string]interface{}{
+		ToolConstraintsKey: onPlane,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opt.StateConstraints(), test.ShouldContain, defaultPlaneConstraintDesc)
+	test.That(t, opt.Fallback, test.ShouldBeNil)
+
+	_, err = pm.plannerSetupFromMoveRequest(from, to, frame.StartPositions(fs), nil, nil, map[string]interface{}{
+		ToolConstraintsKey: onPlane,
+		"planning_alg":     "cbirrt",
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	// the planner asked for cannot hold the constraints, so it is an error rather than being silently replaced
+	_, err = pm.plannerSetupFromMoveRequest(from, to, frame.StartPositions(fs), nil, nil, map[string]interface{}{
+		ToolConstraintsKey: onPlane,
+		"planning_alg":     "rrtstar",
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cbirrt")
+}
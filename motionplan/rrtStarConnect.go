@@ -21,12 +21,23 @@ const (
 	defaultOptimalityThreshold = 1.05
 
 	defaultOptimalityCheckIter = 10
+
+	// The number of times to try sampling the informed subset within joint limits before falling back to a uniform sample.
+	informedSampleTries = 10
 )
 
 type rrtStarConnectOptions struct {
 	// The number of nearest neighbors to consider when adding a new sample to the tree
 	NeighborhoodSize int `json:"neighborhood_size"`
 
+	// Number of seconds to spend improving the path. When set, planning does not return as soon as a sufficiently optimal path is
+	// found, but keeps refining the best path until the budget is used up, trading planning latency for path quality.
+	TimeBudget float64 `json:"time_budget_sec"`
+
+	// Once a path is found, only sample configurations that could be part of a shorter path, as in Informed RRT*, Gammell et al 2014.
+	// This assumes the planner distance function is the L2 distance between inputs, which is the default.
+	Informed bool `json:"informed_sampling"`
+
 	// Parameters common to all RRT implementations
 	*rrtOptions
 }
@@ -131,6 +142,8 @@ func (mp *rrtStarConnectMotionPlanner) rrtBackgroundRunner(ctx context.Context,
 	defer close(m2chan)
 
 	nSolved := 0
+	bestCost := math.Inf(1)
+	budget := time.Duration(mp.algOpts.TimeBudget * float64(time.Second))
 
 	for i := 0; i < mp.algOpts.PlanIter; i++ {
 		select {
@@ -165,7 +178,8 @@ func (mp *rrtStarConnectMotionPlanner) rrtBackgroundRunner(ctx context.Context,
 			if nSolved%defaultOptimalityCheckIter == 0 {
 				solution := shortestPath(rrt.maps, shared)
 				solutionCost := EvaluatePlan(solution.toInputs(), mp.planOpts.DistanceFunc)
-				if solutionCost-rrt.maps.optNode.cost < defaultOptimalityThreshold*rrt.maps.optNode.cost {
+				bestCost = math.Min(bestCost, solutionCost)
+				if budget <= 0 && solutionCost-rrt.maps.optNode.cost < defaultOptimalityThreshold*rrt.maps.optNode.cost {
 					mp.logger.Debug("RRT* progress: sufficiently optimal path found, exiting")
 					rrt.solutionChan <- solution
 					return
@@ -175,8 +189,18 @@ func (mp *rrtStarConnectMotionPlanner) rrtBackgroundRunner(ctx context.Context,
 			nSolved++
 		}
 
+		if budget > 0 && nSolved > 0 && time.Since(mp.start) > budget {
+			mp.logger.Debugf("RRT* used its time budget after %d iterations, returning best path", i)
+			rrt.solutionChan <- shortestPath(rrt.maps, shared)
+			return
+		}
+
 		// get next sample, switch map pointers
-		target = referenceframe.RandomFrameInputs(mp.frame, mp.randseed)
+		if mp.algOpts.Informed {
+			target = mp.informedSample(seed, rrt.maps.optNode.Q(), bestCost)
+		} else {
+			target = referenceframe.RandomFrameInputs(mp.frame, mp.randseed)
+		}
 	}
 	mp.logger.Debug("RRT* exceeded max iter")
 	rrt.solutionChan <- shortestPath(rrt.maps, shared)
@@ -235,3 +259,70 @@ func (mp *rrtStarConnectMotionPlanner) extend(
 	}
 	mchan <- targetNode
 }
+
+// informedSample returns a random sample from the set of inputs that could be on a path from start to goal shorter than bestCost, which is
+// a hyperellipsoid with start and goal at its foci. Until a path is found, or if no sample in the set is within the joint limits, the
+// sample is drawn uniformly from within the joint limits instead.
+func (mp *rrtStarConnectMotionPlanner) informedSample(start, goal []referenceframe.Input, bestCost float64) []referenceframe.Input {
+	minCost := referenceframe.InputsL2Distance(start, goal)
+	if math.IsInf(bestCost, 1) || bestCost <= minCost || minCost == 0 {
+		return referenceframe.RandomFrameInputs(mp.frame, mp.randseed)
+	}
+	n := len(start)
+
+	// the unit vector from start to goal, and the reflection that takes the first axis onto it
+	axis := make([]float64, n)
+	for i := range axis {
+		axis[i] = (goal[i].Value - start[i].Value) / minCost
+	}
+	reflection := make([]float64, n)
+	copy(reflection, axis)
+	reflection[0] -= 1
+	reflectionNormSq := 0.
+	for _, v := range reflection {
+		reflectionNormSq += v * v
+	}
+
+	radii := make([]float64, n)
+	radii[0] = bestCost / 2
+	for i := 1; i < n; i++ {
+		radii[i] = math.Sqrt(bestCost*bestCost-minCost*minCost) / 2
+	}
+
+	for try := 0; try < informedSampleTries; try++ {
+		// a uniform sample from the unit ball, stretched into the ellipsoid
+		ball := make([]float64, n)
+		norm := 0.
+		for i := range ball {
+			ball[i] = mp.randseed.NormFloat64()
+			norm += ball[i] * ball[i]
+		}
+		scale := math.Pow(mp.randseed.Float64(), 1/float64(n)) / math.Sqrt(norm)
+		for i := range ball {
+			ball[i] *= scale * radii[i]
+		}
+
+		// reflect the first axis onto the one between start and goal, then move to between them
+		if reflectionNormSq > defaultEpsilon {
+			dot := 0.
+			for i, v := range reflection {
+				dot += v * ball[i]
+			}
+			for i, v := range reflection {
+				ball[i] -= 2 * dot / reflectionNormSq * v
+			}
+		}
+		sample := make([]referenceframe.Input, n)
+		withinLimits := true
+		for i, limit := range mp.frame.DoF() {
+			sample[i] = referenceframe.Input{Value: (start[i].Value+goal[i].Value)/2 + ball[i]}
+			if sample[i].Value < limit.Min || sample[i].Value > limit.Max {
+				withinLimits = false
+			}
+		}
+		if withinLimits {
+			return sample
+		}
+	}
+	return referenceframe.RandomFrameInputs(mp.frame, mp.randseed)
+}
@@ -0,0 +1,48 @@
+package motionplan
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/utils"
+)
+
+func TestRRTStarConnectOptions(t *testing.T) {
+	opt := newBasicPlannerOptions()
+	opt.extra = map[string]interface{}{"time_budget_sec": 2.5, "informed_sampling": true}
+	algOpts, err := newRRTStarConnectOptions(opt)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, algOpts.TimeBudget, test.ShouldEqual, 2.5)
+	test.That(t, algOpts.Informed, test.ShouldBeTrue)
+	test.That(t, algOpts.NeighborhoodSize, test.ShouldEqual, defaultNeighborhoodSize)
+}
+
+func TestInformedSample(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	model, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm6_kinematics.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	mp, err := newRRTStarConnectMotionPlanner(model, rand.New(rand.NewSource(1)), logger, newBasicPlannerOptions())
+	test.That(t, err, test.ShouldBeNil)
+	planner := mp.(*rrtStarConnectMotionPlanner)
+
+	start := frame.FloatsToInputs([]float64{0, 0, -1, 0, 0, 0})
+	goal := frame.FloatsToInputs([]float64{1, 0.5, -1.5, 0, 0, 0})
+	minCost := frame.InputsL2Distance(start, goal)
+	bestCost := 1.2 * minCost
+
+	for i := 0; i < 100; i++ {
+		sample := planner.informedSample(start, goal, bestCost)
+		// every sample could be on a path shorter than the best one
+		cost := frame.InputsL2Distance(start, sample) + frame.InputsL2Distance(sample, goal)
+		test.That(t, cost, test.ShouldBeLessThanOrEqualTo, bestCost+1e-9)
+	}
+
+	// without a path there is nothing to inform the sample
+	sample := planner.informedSample(start, goal, math.Inf(1))
+	test.That(t, sample, test.ShouldHaveLength, len(model.DoF()))
+}
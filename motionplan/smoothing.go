@@ -0,0 +1,134 @@
+package motionplan
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/service/motion/v1"
+
+	frame "go.viam.com/rdk/referenceframe"
+)
+
+// SmoothPath smooths and shortcuts an existing path for the given frame, such as one recorded by teaching, so that it can be cleaned
+// up the same way a planned path is. Every new segment is checked against the world state and constraints, and the first and last
+// steps are kept. Inputs for frames missing from the first step are taken to be their zero positions. Smoothing is configured with
+// the same "smooth_iter", "smooth_time_budget" and "shortcut" options as planning.
+func SmoothPath(ctx context.Context,
+	logger golog.Logger,
+	path []map[string][]frame.Input,
+	f frame.Frame,
+	fs frame.FrameSystem,
+	worldState *frame.WorldState,
+	constraintSpec *pb.Constraints,
+	planningOpts map[string]interface{},
+) ([]map[string][]frame.Input, error) {
+	if len(path) < 2 {
+		return path, nil
+	}
+	seedMap := frame.StartPositions(fs)
+	for name, inputs := range path[0] {
+		seedMap[name] = inputs
+	}
+	sf, err := newSolverFrame(fs, f.Name(), frame.World, seedMap)
+	if err != nil {
+		return nil, err
+	}
+	if len(sf.DoF()) == 0 {
+		return nil, errors.New("solver frame has no degrees of freedom, cannot smooth path")
+	}
+	steps := make([][]frame.Input, 0, len(path))
+	for i, stepMap := range path {
+		step, err := sf.mapToSlice(stepMap)
+		if err != nil {
+			return nil, errors.Wrapf(err, "step %d of path", i)
+		}
+		steps = append(steps, step)
+	}
+	from, err := sf.Transform(steps[0])
+	if err != nil {
+		return nil, err
+	}
+	to, err := sf.Transform(steps[len(steps)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	rseed := defaultRandomSeed
+	if seed, ok := planningOpts["rseed"].(int); ok {
+		rseed = seed
+	}
+	pm, err := newPlanManager(sf, fs, logger, rseed)
+	if err != nil {
+		return nil, err
+	}
+	opt, err := pm.plannerSetupFromMoveRequest(from, to, seedMap, worldState, constraintSpec, planningOpts)
+	if err != nil {
+		return nil, err
+	}
+	//nolint: gosec
+	mp, err := newPlanner(sf, rand.New(rand.NewSource(int64(rseed))), logger, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	smoothed := smoothAndShortcut(ctx, mp, stepsToNodes(steps))
+	logger.Debugf("smoothed path of %d steps to %d steps", len(path), len(smoothed))
+	result := make([]map[string][]frame.Input, 0, len(smoothed))
+	for _, n := range smoothed {
+		result = append(result, sf.sliceToMap(n.Q()))
+	}
+	return result, nil
+}
+
+// pathSmoother is the part of a motionPlanner needed to smooth a path, which the base planner has on its own.
+type pathSmoother interface {
+	smoothPath(context.Context, []node) []node
+	checkPath([]frame.Input, []frame.Input) bool
+	opt() *plannerOptions
+}
+
+// smoothAndShortcut runs the smoothing stage of planning on a path as configured by the options of the planner: the planner's own
+// smoothing, limited to the smoothing time budget, followed by shortcutting if it is enabled.
+func smoothAndShortcut(ctx context.Context, mp pathSmoother, path []node) []node {
+	opt := mp.opt()
+	if opt == nil {
+		return mp.smoothPath(ctx, path)
+	}
+	if opt.SmoothTimeBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(opt.SmoothTimeBudget*float64(time.Second)))
+		defer cancel()
+	}
+	path = mp.smoothPath(ctx, path)
+	if opt.Shortcut {
+		path = shortcutPath(ctx, path, mp.checkPath)
+	}
+	return path
+}
+
+// shortcutPath greedily connects each waypoint of the path directly to the furthest later waypoint it can reach, dropping every
+// waypoint in between. Unlike random smoothing this is deterministic, and it reliably thins out densely sampled paths.
+func shortcutPath(ctx context.Context, path []node, checkPath func([]frame.Input, []frame.Input) bool) []node {
+	if len(path) <= 2 {
+		return path
+	}
+	shortcut := []node{path[0]}
+	for i := 0; i < len(path)-1; {
+		next := i + 1
+		for j := len(path) - 1; j > i+1; j-- {
+			if ctx.Err() != nil {
+				return append(shortcut, path[i+1:]...)
+			}
+			if checkPath(path[i].Q(), path[j].Q()) {
+				next = j
+				break
+			}
+		}
+		shortcut = append(shortcut, path[next])
+		i = next
+	}
+	return shortcut
+}
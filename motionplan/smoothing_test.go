@@ -0,0 +1,69 @@
+package motionplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/utils"
+)
+
+func TestSmoothPath(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+	model, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm6_kinematics.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+
+	// a densely recorded path that turns the base while detouring through a wrist rotation
+	var path []map[string][]frame.Input
+	addSegment := func(from, to []float64, n int) {
+		for i := 0; i < n; i++ {
+			step := frame.InterpolateInputs(frame.FloatsToInputs(from), frame.FloatsToInputs(to), float64(i)/float64(n))
+			path = append(path, map[string][]frame.Input{model.Name(): step})
+		}
+	}
+	addSegment([]float64{0, 0, 0, 0, 0, 0}, []float64{0, 0, 0, 0, 0, 0.5}, 10)
+	addSegment([]float64{0, 0, 0, 0, 0, 0.5}, []float64{1, 0, 0, 0, 0, 0.5}, 10)
+	addSegment([]float64{1, 0, 0, 0, 0, 0.5}, []float64{1, 0, 0, 0, 0, 0}, 10)
+	path = append(path, map[string][]frame.Input{model.Name(): frame.FloatsToInputs([]float64{1, 0, 0, 0, 0, 0})})
+
+	t.Run("a free path is shortcut to its ends", func(t *testing.T) {
+		smoothed, err := SmoothPath(ctx, logger, path, model, fs, nil, nil, map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(smoothed), test.ShouldEqual, 2)
+		test.That(t, smoothed[0][model.Name()], test.ShouldResemble, path[0][model.Name()])
+		test.That(t, smoothed[1][model.Name()], test.ShouldResemble, path[len(path)-1][model.Name()])
+	})
+
+	t.Run("smoothing can be turned off", func(t *testing.T) {
+		smoothed, err := SmoothPath(ctx, logger, path, model, fs, nil, nil, map[string]interface{}{"smooth_iter": -1, "shortcut": false})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(smoothed), test.ShouldEqual, len(path))
+	})
+
+	t.Run("missing inputs are an error", func(t *testing.T) {
+		_, err := SmoothPath(ctx, logger, append(path, map[string][]frame.Input{}), model, fs, nil, nil, map[string]interface{}{})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestShortcutPath(t *testing.T) {
+	var path []node
+	for i := 0; i < 6; i++ {
+		path = append(path, &basicNode{q: frame.FloatsToInputs([]float64{float64(i)})})
+	}
+	// waypoint 2 can only be passed through, never jumped over
+	checkPath := func(a, b []frame.Input) bool {
+		return (a[0].Value <= 2 && b[0].Value <= 2) || (a[0].Value >= 2 && b[0].Value >= 2)
+	}
+	shortcut := shortcutPath(context.Background(), path, checkPath)
+	test.That(t, len(shortcut), test.ShouldEqual, 3)
+	test.That(t, shortcut[0], test.ShouldEqual, path[0])
+	test.That(t, shortcut[1], test.ShouldEqual, path[2])
+	test.That(t, shortcut[2], test.ShouldEqual, path[5])
+}
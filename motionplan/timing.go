@@ -0,0 +1,281 @@
+package motionplan
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// PathLimits are the velocity and acceleration limits of the moving frame along a planned path. A limit left at zero is not
+// enforced, and an acceleration limit may only be set together with the velocity limit of the same kind.
+type PathLimits struct {
+	LinearVelocityMmPerSec         float64
+	AngularVelocityDegsPerSec      float64
+	LinearAccelerationMmPerSec2    float64
+	AngularAccelerationDegsPerSec2 float64
+}
+
+// Validate ensures the limits are usable.
+func (l PathLimits) Validate() error {
+	if l.LinearVelocityMmPerSec < 0 || l.AngularVelocityDegsPerSec < 0 ||
+		l.LinearAccelerationMmPerSec2 < 0 || l.AngularAccelerationDegsPerSec2 < 0 {
+		return errors.New("path limits cannot be negative")
+	}
+	if l.LinearAccelerationMmPerSec2 > 0 && l.LinearVelocityMmPerSec == 0 {
+		return errors.Errorf("%s requires %s to be set", MaxLinearAccelerationKey, MaxLinearVelocityKey)
+	}
+	if l.AngularAccelerationDegsPerSec2 > 0 && l.AngularVelocityDegsPerSec == 0 {
+		return errors.Errorf("%s requires %s to be set", MaxAngularAccelerationKey, MaxAngularVelocityKey)
+	}
+	return nil
+}
+
+// Limited returns whether any velocity limit is set.
+func (l PathLimits) Limited() bool {
+	return l.LinearVelocityMmPerSec > 0 || l.AngularVelocityDegsPerSec > 0
+}
+
+// TimeParameterize returns the time, measured from the start of the motion, at which each step of a plan should be reached so
+// that the named frame never exceeds the given limits. Speed ramps up and down with a trapezoidal profile along the whole path
+// rather than stopping at every step. Steps only need to hold the inputs that change; start holds the inputs of every frame.
+func TimeParameterize(
+	fs referenceframe.FrameSystem,
+	frameName string,
+	start map[string][]referenceframe.Input,
+	steps []map[string][]referenceframe.Input,
+	limits PathLimits,
+) ([]time.Duration, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	if !limits.Limited() {
+		return make([]time.Duration, len(steps)), nil
+	}
+
+	inputs := make(map[string][]referenceframe.Input, len(start))
+	for name, in := range start {
+		inputs[name] = in
+	}
+	framePose := func() (spatialmath.Pose, error) {
+		tf, err := fs.Transform(inputs, referenceframe.NewPoseInFrame(frameName, spatialmath.NewZeroPose()), referenceframe.World)
+		if err != nil {
+			return nil, err
+		}
+		return tf.(*referenceframe.PoseInFrame).Pose(), nil
+	}
+
+	prev, err := framePose()
+	if err != nil {
+		return nil, err
+	}
+	// nominal[i] is how long the path up to step i takes at the velocity limits
+	nominal := make([]float64, len(steps))
+	total := 0.
+	for i, step := range steps {
+		for name, in := range step {
+			inputs[name] = in
+		}
+		pose, err := framePose()
+		if err != nil {
+			return nil, err
+		}
+		total += limits.segmentTime(prev, pose)
+		nominal[i] = total
+		prev = pose
+	}
+
+	times, _ := profileTimes(nominal, limits.acceleration())
+	return times, nil
+}
+
+// JointMotionLimit is how fast a single joint may move, in the units of its inputs per second. A limit left at zero is not enforced.
+type JointMotionLimit struct {
+	Velocity     float64
+	Acceleration float64
+}
+
+// TrajectoryPoint is a waypoint of a trajectory with the time it is reached, measured from the start of the trajectory, and the
+// velocity of each joint when it is reached.
+type TrajectoryPoint struct {
+	Time       time.Duration
+	Inputs     []referenceframe.Input
+	Velocities []float64
+}
+
+// A Trajectory is a joint path with the times at which each waypoint is reached.
+type Trajectory []TrajectoryPoint
+
+// Duration returns how long the trajectory takes.
+func (t Trajectory) Duration() time.Duration {
+	if len(t) == 0 {
+		return 0
+	}
+	return t[len(t)-1].Time
+}
+
+// JointTrajectory time parameterizes a joint path, such as the steps of a single frame returned by FrameStepsFromRobotPath, so that
+// no joint exceeds its velocity and acceleration limits. Like TimeParameterize, speed ramps up and down with a trapezoidal profile
+// along the whole path, which starts and ends at rest, so the path should be as finely stepped as planned paths are.
+func JointTrajectory(path [][]referenceframe.Input, limits []JointMotionLimit) (Trajectory, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	dof := len(path[0])
+	if len(limits) != dof {
+		return nil, errors.Errorf("got %d joint limits for a path of %d joints", len(limits), dof)
+	}
+	accel := math.Inf(1)
+	for i, limit := range limits {
+		if limit.Velocity < 0 || limit.Acceleration < 0 {
+			return nil, errors.Errorf("limits of joint %d cannot be negative", i)
+		}
+		if limit.Velocity > 0 && limit.Acceleration > 0 {
+			accel = math.Min(accel, limit.Acceleration/limit.Velocity)
+		}
+	}
+	if math.IsInf(accel, 1) {
+		accel = 0
+	}
+
+	// nominal[i] is how long the path up to waypoint i takes with the slowest joint of each segment at its velocity limit
+	nominal := make([]float64, len(path))
+	for i, step := range path {
+		if len(step) != dof {
+			return nil, errors.New("every waypoint of the path must have the same number of joints")
+		}
+		if i == 0 {
+			continue
+		}
+		segment := 0.
+		for j, limit := range limits {
+			delta := math.Abs(step[j].Value - path[i-1][j].Value)
+			if delta == 0 {
+				continue
+			}
+			if limit.Velocity == 0 {
+				return nil, errors.New("a velocity limit is needed for every joint that moves")
+			}
+			segment = math.Max(segment, delta/limit.Velocity)
+		}
+		nominal[i] = nominal[i-1] + segment
+	}
+
+	times, speeds := profileTimes(nominal, accel)
+	trajectory := make(Trajectory, len(path))
+	for i, step := range path {
+		velocities := make([]float64, dof)
+		if i < len(path)-1 {
+			if segment := nominal[i+1] - nominal[i]; segment > 0 {
+				for j := range velocities {
+					velocities[j] = speeds[i] * (path[i+1][j].Value - step[j].Value) / segment
+				}
+			}
+		}
+		trajectory[i] = TrajectoryPoint{Time: times[i], Inputs: step, Velocities: velocities}
+	}
+	return trajectory, nil
+}
+
+// profileTimes returns the times at which a trapezoidal velocity profile along a path reaches each of the given distances along
+// it, which are measured in seconds at full speed, and the fraction of full speed it is moving at when it does.
+func profileTimes(nominal []float64, accel float64) ([]time.Duration, []float64) {
+	length := 0.
+	if len(nominal) > 0 {
+		length = nominal[len(nominal)-1]
+	}
+	profile := newTrapezoid(length, accel)
+	times := make([]time.Duration, len(nominal))
+	speeds := make([]float64, len(nominal))
+	for i, u := range nominal {
+		times[i] = time.Duration(profile.timeAt(u) * float64(time.Second))
+		speeds[i] = profile.speedAt(u)
+	}
+	return times, speeds
+}
+
+// segmentTime returns how long moving between the poses takes at the velocity limits.
+func (l PathLimits) segmentTime(from, to spatialmath.Pose) float64 {
+	t := 0.
+	if l.LinearVelocityMmPerSec > 0 {
+		t = from.Point().Distance(to.Point()) / l.LinearVelocityMmPerSec
+	}
+	if l.AngularVelocityDegsPerSec > 0 {
+		theta := utils.RadToDeg(spatialmath.OrientationBetween(from.Orientation(), to.Orientation()).AxisAngles().Theta)
+		t = math.Max(t, math.Abs(theta)/l.AngularVelocityDegsPerSec)
+	}
+	return t
+}
+
+// acceleration returns the acceleration limit along the path, in units of the fraction of the velocity limit gained per second.
+// Zero means the velocity limit can be reached immediately.
+func (l PathLimits) acceleration() float64 {
+	accel := math.Inf(1)
+	if l.LinearAccelerationMmPerSec2 > 0 {
+		accel = math.Min(accel, l.LinearAccelerationMmPerSec2/l.LinearVelocityMmPerSec)
+	}
+	if l.AngularAccelerationDegsPerSec2 > 0 {
+		accel = math.Min(accel, l.AngularAccelerationDegsPerSec2/l.AngularVelocityDegsPerSec)
+	}
+	if math.IsInf(accel, 1) {
+		return 0
+	}
+	return accel
+}
+
+// trapezoid is a velocity profile over a path whose length is measured in seconds at full speed, so full speed is 1.
+type trapezoid struct {
+	length    float64
+	accel     float64
+	peak      float64
+	rampLen   float64
+	totalTime float64
+}
+
+func newTrapezoid(length, accel float64) trapezoid {
+	if accel <= 0 || length <= 0 {
+		return trapezoid{length: length, peak: 1, totalTime: length}
+	}
+	// paths too short to reach full speed have a triangular profile
+	peak := math.Min(1, math.Sqrt(accel*length))
+	rampLen := peak * peak / (2 * accel)
+	return trapezoid{
+		length:    length,
+		accel:     accel,
+		peak:      peak,
+		rampLen:   rampLen,
+		totalTime: 2*peak/accel + (length-2*rampLen)/peak,
+	}
+}
+
+// timeAt returns the time at which the given distance along the path is reached.
+func (p trapezoid) timeAt(u float64) float64 {
+	switch {
+	case p.accel <= 0:
+		return u
+	case u <= p.rampLen:
+		return math.Sqrt(2 * u / p.accel)
+	case u <= p.length-p.rampLen:
+		return p.peak/p.accel + (u-p.rampLen)/p.peak
+	default:
+		return p.totalTime - math.Sqrt(2*math.Max(0, p.length-u)/p.accel)
+	}
+}
+
+// speedAt returns the fraction of full speed the profile is moving at when the given distance along the path is reached.
+func (p trapezoid) speedAt(u float64) float64 {
+	switch {
+	case p.accel <= 0:
+		return 1
+	case u <= p.rampLen:
+		return math.Sqrt(2 * p.accel * u)
+	case u <= p.length-p.rampLen:
+		return p.peak
+	default:
+		return math.Sqrt(2 * p.accel * math.Max(0, p.length-u))
+	}
+}
@@ -0,0 +1,107 @@
+package motionplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+func TestTimeParameterize(t *testing.T) {
+	fs := referenceframe.NewEmptySimpleFrameSystem("test")
+	slider, err := referenceframe.NewTranslationalFrame("slider", r3.Vector{X: 1}, referenceframe.Limit{Min: 0, Max: 10000})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(slider, fs.World()), test.ShouldBeNil)
+
+	start := map[string][]referenceframe.Input{"slider": referenceframe.FloatsToInputs([]float64{0})}
+	var steps []map[string][]referenceframe.Input
+	for i := 1; i <= 10; i++ {
+		steps = append(steps, map[string][]referenceframe.Input{"slider": referenceframe.FloatsToInputs([]float64{float64(i) * 100})})
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		times, err := TimeParameterize(fs, "slider", start, steps, PathLimits{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, times, test.ShouldHaveLength, 10)
+		test.That(t, times[9], test.ShouldEqual, time.Duration(0))
+	})
+
+	t.Run("velocity", func(t *testing.T) {
+		times, err := TimeParameterize(fs, "slider", start, steps, PathLimits{LinearVelocityMmPerSec: 100})
+		test.That(t, err, test.ShouldBeNil)
+		for i, at := range times {
+			test.That(t, at.Seconds(), test.ShouldAlmostEqual, float64(i+1))
+		}
+	})
+
+	t.Run("acceleration", func(t *testing.T) {
+		limits := PathLimits{LinearVelocityMmPerSec: 100, LinearAccelerationMmPerSec2: 100}
+		times, err := TimeParameterize(fs, "slider", start, steps, limits)
+		test.That(t, err, test.ShouldBeNil)
+		// a second to speed up and a second to slow down, each covering 50mm
+		test.That(t, times[0].Seconds(), test.ShouldAlmostEqual, 1.5)
+		test.That(t, times[4].Seconds(), test.ShouldAlmostEqual, 5.5)
+		test.That(t, times[9].Seconds(), test.ShouldAlmostEqual, 11)
+	})
+
+	t.Run("short path", func(t *testing.T) {
+		limits := PathLimits{LinearVelocityMmPerSec: 100, LinearAccelerationMmPerSec2: 100}
+		times, err := TimeParameterize(fs, "slider", start, steps[:1], limits)
+		test.That(t, err, test.ShouldBeNil)
+		// only just reaches full speed before slowing down again
+		test.That(t, times[0].Seconds(), test.ShouldAlmostEqual, 2)
+	})
+}
+
+func TestJointTrajectory(t *testing.T) {
+	path := [][]referenceframe.Input{
+		referenceframe.FloatsToInputs([]float64{0, 0}),
+		referenceframe.FloatsToInputs([]float64{1, 0}),
+		referenceframe.FloatsToInputs([]float64{2, 0}),
+	}
+
+	t.Run("velocity limits", func(t *testing.T) {
+		trajectory, err := JointTrajectory(path, []JointMotionLimit{{Velocity: 0.5}, {Velocity: 1}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, trajectory, test.ShouldHaveLength, 3)
+		test.That(t, trajectory[1].Time.Seconds(), test.ShouldAlmostEqual, 2)
+		test.That(t, trajectory.Duration().Seconds(), test.ShouldAlmostEqual, 4)
+		test.That(t, trajectory[1].Velocities, test.ShouldResemble, []float64{0.5, 0})
+		test.That(t, trajectory[2].Velocities, test.ShouldResemble, []float64{0, 0})
+	})
+
+	t.Run("acceleration limits", func(t *testing.T) {
+		trajectory, err := JointTrajectory(path, []JointMotionLimit{{Velocity: 1, Acceleration: 1}, {Velocity: 1, Acceleration: 1}})
+		test.That(t, err, test.ShouldBeNil)
+		// a second each to speed up and slow down over half a unit, with a second of cruising in between
+		test.That(t, trajectory[1].Time.Seconds(), test.ShouldAlmostEqual, 1.5)
+		test.That(t, trajectory.Duration().Seconds(), test.ShouldAlmostEqual, 3)
+		test.That(t, trajectory[0].Velocities, test.ShouldResemble, []float64{0, 0})
+		test.That(t, trajectory[1].Velocities[0], test.ShouldAlmostEqual, 1)
+	})
+
+	t.Run("slowest joint sets the pace", func(t *testing.T) {
+		diagonal := [][]referenceframe.Input{
+			referenceframe.FloatsToInputs([]float64{0, 0}),
+			referenceframe.FloatsToInputs([]float64{4, 1}),
+			referenceframe.FloatsToInputs([]float64{8, 2}),
+		}
+		trajectory, err := JointTrajectory(diagonal, []JointMotionLimit{{Velocity: 1}, {Velocity: 0.1}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, trajectory[1].Time.Seconds(), test.ShouldAlmostEqual, 10)
+		test.That(t, trajectory[1].Velocities[0], test.ShouldAlmostEqual, 0.4)
+		test.That(t, trajectory[1].Velocities[1], test.ShouldAlmostEqual, 0.1)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := JointTrajectory(path, []JointMotionLimit{{Velocity: 1}})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = JointTrajectory(path, []JointMotionLimit{{Velocity: 1}, {Velocity: -1}})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = JointTrajectory(path, []JointMotionLimit{{}, {Velocity: 1}})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,112 @@
+package motionplan
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// ToolConstraints are constraints on the pose of the moving frame that hold throughout a path. Positions and directions are in the
+// frame the goal is solved in, which is the world frame for the motion service.
+type ToolConstraints struct {
+	// OrientationLock keeps the orientation of the moving frame at its starting orientation.
+	OrientationLock *OrientationLock `json:"orientation_lock,omitempty"`
+	// Line keeps the position of the moving frame on a line.
+	Line *LineLock `json:"line,omitempty"`
+	// Plane keeps the position of the moving frame on a plane.
+	Plane *PlaneLock `json:"plane,omitempty"`
+}
+
+// OrientationLock holds the orientation of the moving frame fixed.
+type OrientationLock struct {
+	ToleranceDegs float64 `json:"tolerance_degs"`
+}
+
+// LineLock holds the position of the moving frame on the line through Point in Direction.
+type LineLock struct {
+	Point       r3.Vector `json:"point"`
+	Direction   r3.Vector `json:"direction"`
+	ToleranceMm float64   `json:"tolerance_mm"`
+}
+
+// PlaneLock holds the position of the moving frame on the plane through Point with the given Normal.
+type PlaneLock struct {
+	Point       r3.Vector `json:"point"`
+	Normal      r3.Vector `json:"normal"`
+	ToleranceMm float64   `json:"tolerance_mm"`
+}
+
+// Validate ensures the constraints are well defined.
+func (tc *ToolConstraints) Validate() error {
+	if tc.OrientationLock != nil && tc.OrientationLock.ToleranceDegs < 0 {
+		return errors.New("orientation lock tolerance cannot be negative")
+	}
+	if tc.Line != nil {
+		if tc.Line.Direction.Norm() == 0 {
+			return errors.New("line constraint needs a nonzero direction")
+		}
+		if tc.Line.ToleranceMm < 0 {
+			return errors.New("line constraint tolerance cannot be negative")
+		}
+	}
+	if tc.Plane != nil {
+		if tc.Plane.Normal.Norm() == 0 {
+			return errors.New("plane constraint needs a nonzero normal")
+		}
+		if tc.Plane.ToleranceMm < 0 {
+			return errors.New("plane constraint tolerance cannot be negative")
+		}
+	}
+	return nil
+}
+
+// addToolConstraints adds the tool constraints for a motion from one pose to another. It returns whether there were any, and an
+// error if the goal itself breaks one of them, since no path could then satisfy it.
+func (p *plannerOptions) addToolConstraints(from, to spatialmath.Pose, tc *ToolConstraints) (bool, error) {
+	if tc == nil {
+		return false, nil
+	}
+	goal := &State{Position: to}
+	added := false
+	if lock := tc.OrientationLock; lock != nil {
+		tolerance := lock.ToleranceDegs
+		if tolerance == 0 {
+			tolerance = defaultOrientationDeviation
+		}
+		constraint, pathDist := NewFixedOrientationConstraint(from.Orientation(), tolerance)
+		if pathDist(goal) > 0 {
+			return false, errors.New("goal orientation differs from the locked starting orientation")
+		}
+		p.AddStateConstraint(defaultFixedOrientationConstraintDesc, constraint)
+		p.pathMetric = CombineMetrics(p.pathMetric, pathDist)
+		added = true
+	}
+	if line := tc.Line; line != nil {
+		tolerance := line.ToleranceMm
+		if tolerance == 0 {
+			tolerance = defaultLinearDeviation
+		}
+		constraint, pathDist := NewInfiniteLineConstraint(line.Point, line.Direction, tolerance)
+		if pathDist(goal) > 0 {
+			return false, errors.New("goal position is not on the constraining line")
+		}
+		p.AddStateConstraint(defaultLineConstraintDesc, constraint)
+		p.pathMetric = CombineMetrics(p.pathMetric, pathDist)
+		added = true
+	}
+	if plane := tc.Plane; plane != nil {
+		tolerance := plane.ToleranceMm
+		if tolerance == 0 {
+			tolerance = defaultLinearDeviation
+		}
+		constraint, pathDist := NewPositionPlaneConstraint(plane.Normal, plane.Point, tolerance)
+		if pathDist(goal) > 0 {
+			return false, errors.New("goal position is not on the constraining plane")
+		}
+		p.AddStateConstraint(defaultPlaneConstraintDesc, constraint)
+		p.pathMetric = CombineMetrics(p.pathMetric, pathDist)
+		added = true
+	}
+	return added, nil
+}
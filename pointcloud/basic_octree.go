@@ -1,6 +1,8 @@
 package pointcloud
 
 import (
+	"math"
+
 	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
 
@@ -15,6 +17,9 @@ const (
 	// This value allows for high level of granularity in the octree while still allowing for fast access times
 	// even on a pi.
 	maxRecursionDepth = 1000
+	// points on the boundary between octants can be a rounding error outside of both of them, so the bounds of an
+	// octree are widened by this relative amount.
+	placementTolerance = 1e-9
 )
 
 // NodeType represents the possible types of nodes in an octree.
@@ -24,12 +29,20 @@ type NodeType uint8
 // point, side length and node data. An octree is a data structure that recursively partitions 3D space into
 // octants to represent occupancy. It is a storage format for a pointcloud that allows for better searchability
 // and serialization.
+//
+// A BasicOctree is also a spatialmath.Geometry, so a pointcloud can be used directly as an obstacle for motion planning. When checked
+// for collisions as a geometry, only points whose value is at least the confidence threshold are considered, and every point is
+// treated as a sphere whose radius is the collision buffer. By default every point is considered and is treated as a single point.
 type BasicOctree struct {
 	node       basicOctreeNode
 	center     r3.Vector
 	sideLength float64
 	size       int
 	meta       MetaData
+
+	label               string
+	confidenceThreshold int
+	collisionBuffer     float64
 }
 
 // basicOctreeNode is a struct comprised of the type of node, children nodes (should they exist) and the pointcloud's
@@ -48,16 +61,43 @@ func NewBasicOctree(center r3.Vector, sideLength float64) (*BasicOctree, error)
 	}
 
 	octree := &BasicOctree{
-		node:       newLeafNodeEmpty(),
-		center:     center,
-		sideLength: sideLength,
-		size:       0,
-		meta:       NewMetaData(),
+		node:                newLeafNodeEmpty(),
+		center:              center,
+		sideLength:          sideLength,
+		size:                0,
+		meta:                NewMetaData(),
+		confidenceThreshold: emptyProb,
 	}
 
 	return octree, nil
 }
 
+// NewBasicOctreeFromPointCloud creates a new basic octree just large enough to hold the points of the given pointcloud, such as one
+// from a depth camera, and adds them to it.
+func NewBasicOctreeFromPointCloud(pc PointCloud) (*BasicOctree, error) {
+	if pc.Size() == 0 {
+		return nil, errors.New("cannot create an octree from an empty pointcloud")
+	}
+	meta := pc.MetaData()
+	// a cloud of a single point has no extent, but the octree needs some
+	octree, err := NewBasicOctree(getCenterFromPcMetaData(meta), math.Max(getMaxSideLengthFromPcMetaData(meta), 1))
+	if err != nil {
+		return nil, err
+	}
+	pc.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		// the octree needs the data of every point, which point clouds can leave out
+		if d == nil {
+			d = NewBasicData()
+		}
+		err = octree.Set(p, d)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return octree, nil
+}
+
 // Size returns the number of points stored in the octree's metadata.
 func (octree *BasicOctree) Size() int {
 	return octree.size
@@ -169,7 +209,7 @@ func (octree *BasicOctree) CollidesWithGeometry(geom spatialmath.Geometry, thres
 	case leafNodeEmpty:
 		return false, nil
 	case leafNodeFilled:
-		ptGeom, err := spatialmath.NewSphere(spatialmath.NewPoseFromPoint(octree.node.point.P), buffer, "")
+		ptGeom, err := pointGeometry(octree.node.point.P, buffer)
 		if err != nil {
 			return false, err
 		}
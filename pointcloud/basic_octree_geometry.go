@@ -0,0 +1,190 @@
+package pointcloud
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// SetConfidenceThreshold sets the value a point needs to be considered when the octree is checked for collisions as a geometry.
+func (octree *BasicOctree) SetConfidenceThreshold(threshold int) {
+	octree.confidenceThreshold = threshold
+}
+
+// SetCollisionBuffer sets the radius in mm of the sphere each point is treated as when the octree is checked for collisions as a
+// geometry.
+func (octree *BasicOctree) SetCollisionBuffer(buffer float64) {
+	octree.collisionBuffer = buffer
+}
+
+// Pose returns the pose of the center of the octree.
+func (octree *BasicOctree) Pose() spatialmath.Pose {
+	return spatialmath.NewPoseFromPoint(octree.center)
+}
+
+// AlmostEqual compares the octree with another geometry and checks if they hold the same points.
+func (octree *BasicOctree) AlmostEqual(g spatialmath.Geometry) bool {
+	other, ok := g.(*BasicOctree)
+	if !ok || octree.Size() != other.Size() {
+		return false
+	}
+	equal := true
+	octree.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		_, equal = other.At(p.X, p.Y, p.Z)
+		return equal
+	})
+	return equal
+}
+
+// Transform premultiplies every point of the octree with a transform, allowing the pointcloud to be moved in space.
+func (octree *BasicOctree) Transform(toPremultiply spatialmath.Pose) spatialmath.Geometry {
+	// a rotated octree may extend further along each axis than the original, but never more than its diagonal
+	sideLength := octree.sideLength
+	if !spatialmath.OrientationAlmostEqual(toPremultiply.Orientation(), spatialmath.NewZeroOrientation()) {
+		sideLength *= math.Sqrt(3)
+	}
+	transformed := &BasicOctree{
+		node:                newLeafNodeEmpty(),
+		center:              spatialmath.Compose(toPremultiply, octree.Pose()).Point(),
+		sideLength:          sideLength,
+		meta:                NewMetaData(),
+		label:               octree.label,
+		confidenceThreshold: octree.confidenceThreshold,
+		collisionBuffer:     octree.collisionBuffer,
+	}
+	octree.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		// every transformed point is within the bounds of the transformed octree, so this cannot fail
+		//nolint:errcheck
+		transformed.Set(spatialmath.Compose(toPremultiply, spatialmath.NewPoseFromPoint(p)).Point(), d)
+		return true
+	})
+	return transformed
+}
+
+// ToProtobuf converts the octree to a Geometry proto message. The API has no pointcloud geometry, so the octree is sent as the box
+// that bounds it, which is conservative for collision checking.
+func (octree *BasicOctree) ToProtobuf() *commonpb.Geometry {
+	return &commonpb.Geometry{
+		Center: spatialmath.PoseToProtobuf(octree.Pose()),
+		GeometryType: &commonpb.Geometry_Box{
+			Box: &commonpb.RectangularPrism{DimsMm: &commonpb.Vector3{
+				X: octree.sideLength,
+				Y: octree.sideLength,
+				Z: octree.sideLength,
+			}},
+		},
+		Label: octree.label,
+	}
+}
+
+// CollidesWith checks if the given geometry collides with any point of the octree.
+func (octree *BasicOctree) CollidesWith(g spatialmath.Geometry) (bool, error) {
+	return octree.CollidesWithGeometry(g, octree.confidenceThreshold, octree.collisionBuffer)
+}
+
+// DistanceFrom returns the distance from the given geometry to the closest point of the octree.
+func (octree *BasicOctree) DistanceFrom(g spatialmath.Geometry) (float64, error) {
+	best := math.Inf(1)
+	if err := octree.helperDistanceFrom(g, octree.confidenceThreshold, octree.collisionBuffer, &best); err != nil {
+		return math.Inf(-1), err
+	}
+	return best, nil
+}
+
+// helperDistanceFrom is a recursive helper for DistanceFrom that skips every node whose bounds are further from the geometry than the
+// closest point found so far.
+func (octree *BasicOctree) helperDistanceFrom(g spatialmath.Geometry, threshold int, buffer float64, best *float64) error {
+	if octree.MaxVal() < threshold || *best <= spatialmath.CollisionBuffer {
+		return nil
+	}
+	switch octree.node.nodeType {
+	case internalNode:
+		side := octree.sideLength + 2*buffer
+		bounds, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(octree.center), r3.Vector{X: side, Y: side, Z: side}, "")
+		if err != nil {
+			return err
+		}
+		dist, err := g.DistanceFrom(bounds)
+		if err != nil {
+			return err
+		}
+		if dist >= *best {
+			return nil
+		}
+		for _, child := range octree.node.children {
+			if err := child.helperDistanceFrom(g, threshold, buffer, best); err != nil {
+				return err
+			}
+		}
+	case leafNodeFilled:
+		ptGeom, err := pointGeometry(octree.node.point.P, buffer)
+		if err != nil {
+			return err
+		}
+		dist, err := g.DistanceFrom(ptGeom)
+		if err != nil {
+			return err
+		}
+		*best = math.Min(*best, dist)
+	case leafNodeEmpty:
+	}
+	return nil
+}
+
+// EncompassedBy returns whether every point of the octree is inside the given geometry.
+func (octree *BasicOctree) EncompassedBy(g spatialmath.Geometry) (bool, error) {
+	inside := true
+	var err error
+	octree.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		var ptGeom spatialmath.Geometry
+		if ptGeom, err = pointGeometry(p, octree.collisionBuffer); err != nil {
+			return false
+		}
+		inside, err = ptGeom.EncompassedBy(g)
+		return err == nil && inside
+	})
+	return inside && err == nil, err
+}
+
+// SetLabel sets the label of this octree.
+func (octree *BasicOctree) SetLabel(label string) {
+	octree.label = label
+}
+
+// Label returns the label of this octree.
+func (octree *BasicOctree) Label() string {
+	return octree.label
+}
+
+// String returns a human readable string that represents the octree.
+func (octree *BasicOctree) String() string {
+	return fmt.Sprintf("Type: Octree, Points: %d, Side length: %.0f", octree.Size(), octree.sideLength)
+}
+
+// ToPoints returns the points of the octree, ignoring the resolution.
+func (octree *BasicOctree) ToPoints(resolution float64) []r3.Vector {
+	points := make([]r3.Vector, 0, octree.Size())
+	octree.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		points = append(points, p)
+		return true
+	})
+	return points
+}
+
+// MarshalJSON always fails, since there is no geometry config for an octree.
+func (octree *BasicOctree) MarshalJSON() ([]byte, error) {
+	return nil, errors.Wrap(spatialmath.ErrGeometryTypeUnsupported, "octree")
+}
+
+// pointGeometry returns the geometry a point of an octree is treated as for collision checking with the given buffer.
+func pointGeometry(p r3.Vector, buffer float64) (spatialmath.Geometry, error) {
+	if buffer > 0 {
+		return spatialmath.NewSphere(spatialmath.NewPoseFromPoint(p), buffer, "")
+	}
+	return spatialmath.NewPoint(p, ""), nil
+}
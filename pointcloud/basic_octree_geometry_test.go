@@ -0,0 +1,133 @@
+package pointcloud
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestBasicOctreeGeometry(t *testing.T) {
+	_, err := NewBasicOctreeFromPointCloud(New())
+	test.That(t, err, test.ShouldNotBeNil)
+
+	points := []r3.Vector{{X: 100}, {X: 110}, {X: 100, Y: 10}}
+	pc := New()
+	for _, p := range points {
+		test.That(t, pc.Set(p, nil), test.ShouldBeNil)
+	}
+	octree, err := NewBasicOctreeFromPointCloud(pc)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, octree.Size(), test.ShouldEqual, len(points))
+	var geometry spatialmath.Geometry = octree
+	geometry.SetLabel("cloud")
+	test.That(t, geometry.Label(), test.ShouldEqual, "cloud")
+
+	sphereAt := func(pt r3.Vector, r float64) spatialmath.Geometry {
+		s, err := spatialmath.NewSphere(spatialmath.NewPoseFromPoint(pt), r, "")
+		test.That(t, err, test.ShouldBeNil)
+		return s
+	}
+
+	t.Run("distance and collisions", func(t *testing.T) {
+		dist, err := octree.DistanceFrom(spatialmath.NewPoint(r3.Vector{X: 100, Z: 20}, ""))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dist, test.ShouldAlmostEqual, 20)
+
+		collides, err := octree.CollidesWith(sphereAt(r3.Vector{X: 100, Z: 5}, 10))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeTrue)
+		collides, err = octree.CollidesWith(sphereAt(r3.Vector{X: 100, Z: 5}, 4))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeFalse)
+
+		buffered := octree.Transform(spatialmath.NewZeroPose()).(*BasicOctree)
+		buffered.SetCollisionBuffer(2)
+		collides, err = buffered.CollidesWith(sphereAt(r3.Vector{X: 100, Z: 5}, 4))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeTrue)
+		dist, err = buffered.DistanceFrom(sphereAt(r3.Vector{X: 100, Z: 5}, 4))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dist, test.ShouldAlmostEqual, -1)
+
+		valued, err := NewBasicOctree(r3.Vector{}, 10)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valued.Set(r3.Vector{X: 1}, NewValueData(10)), test.ShouldBeNil)
+		valued.SetConfidenceThreshold(50)
+		collides, err = valued.CollidesWith(sphereAt(r3.Vector{X: 1}, 1))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeFalse)
+		dist, err = valued.DistanceFrom(sphereAt(r3.Vector{X: 1}, 1))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, math.IsInf(dist, 1), test.ShouldBeTrue)
+	})
+
+	t.Run("transform", func(t *testing.T) {
+		pose := spatialmath.NewPose(r3.Vector{Z: 1000}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90})
+		moved := octree.Transform(pose)
+		test.That(t, moved.Label(), test.ShouldEqual, "cloud")
+		test.That(t, moved.AlmostEqual(octree), test.ShouldBeFalse)
+
+		expected := New()
+		for _, p := range points {
+			test.That(t, expected.Set(spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(p)).Point(), nil), test.ShouldBeNil)
+		}
+		expectedOctree, err := NewBasicOctreeFromPointCloud(expected)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, moved.AlmostEqual(expectedOctree), test.ShouldBeTrue)
+	})
+
+	t.Run("points without data", func(t *testing.T) {
+		for _, p := range points {
+			d, ok := octree.At(p.X, p.Y, p.Z)
+			test.That(t, ok, test.ShouldBeTrue)
+			test.That(t, d, test.ShouldNotBeNil)
+		}
+	})
+
+	t.Run("transformed points on octant boundaries", func(t *testing.T) {
+		// rotated points that fall on the boundaries between octants must still find one
+		grid := New()
+		for x := 0.; x <= 4; x++ {
+			for y := 0.; y <= 4; y++ {
+				test.That(t, grid.Set(r3.Vector{X: x, Y: y, Z: x + y}, nil), test.ShouldBeNil)
+			}
+		}
+		gridOctree, err := NewBasicOctreeFromPointCloud(grid)
+		test.That(t, err, test.ShouldBeNil)
+		pose := spatialmath.NewPose(r3.Vector{X: 1.5, Y: -3}, &spatialmath.OrientationVectorDegrees{OX: 1, OY: 1, OZ: 1, Theta: 30})
+		moved := gridOctree.Transform(pose).(*BasicOctree)
+		test.That(t, moved.Size(), test.ShouldEqual, grid.Size())
+		grid.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+			movedPt := spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(p)).Point()
+			_, ok := moved.At(movedPt.X, movedPt.Y, movedPt.Z)
+			test.That(t, ok, test.ShouldBeTrue)
+			return true
+		})
+	})
+
+	t.Run("encompassed by", func(t *testing.T) {
+		bounds, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 105}), r3.Vector{X: 20, Y: 30, Z: 10}, "")
+		test.That(t, err, test.ShouldBeNil)
+		inside, err := octree.EncompassedBy(bounds)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, inside, test.ShouldBeTrue)
+		inside, err = octree.EncompassedBy(sphereAt(r3.Vector{X: 100}, 5))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, inside, test.ShouldBeFalse)
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		test.That(t, octree.ToPoints(0), test.ShouldHaveLength, len(points))
+		bounds, err := spatialmath.NewGeometryFromProto(octree.ToProtobuf())
+		test.That(t, err, test.ShouldBeNil)
+		encompassed, err := octree.EncompassedBy(bounds)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, encompassed, test.ShouldBeTrue)
+		_, err = octree.MarshalJSON()
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -99,9 +99,10 @@ func (octree *BasicOctree) splitIntoOctants() error {
 
 // Checks that a point should be inside a basic octree based on its center and defined side length.
 func (octree *BasicOctree) checkPointPlacement(p r3.Vector) bool {
-	return ((math.Abs(octree.center.X-p.X) <= octree.sideLength/2.) &&
-		(math.Abs(octree.center.Y-p.Y) <= octree.sideLength/2.) &&
-		(math.Abs(octree.center.Z-p.Z) <= octree.sideLength/2.))
+	halfSide := octree.sideLength / 2. * (1 + placementTolerance)
+	return ((math.Abs(octree.center.X-p.X) <= halfSide) &&
+		(math.Abs(octree.center.Y-p.Y) <= halfSide) &&
+		(math.Abs(octree.center.Z-p.Z) <= halfSide))
 }
 
 // helperSet is used by Set to recursive move through a basic octree while tracking recursion depth.
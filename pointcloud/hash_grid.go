@@ -0,0 +1,274 @@
+package pointcloud
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// HashGrid is a PointCloud that indexes its points by the cube of a uniform grid they fall in, which makes adding a
+// point constant time. Unlike a KDTree, which has to be rebuilt to stay balanced as points are added, it can answer
+// the same nearest neighbor and radius queries while points keep streaming in, and it is safe to query from other
+// goroutines while points are being added. Queries are fastest when the cell size is about their radius, or about the
+// distance to the k-th nearest neighbor.
+type HashGrid struct {
+	mu       sync.RWMutex
+	cellSize float64
+	cells    map[voxelKey][]r3.Vector
+	minKey   voxelKey
+	maxKey   voxelKey
+	points   *matrixStorage
+	meta     MetaData
+}
+
+// NewHashGrid returns an empty HashGrid with cubic cells of the given side length.
+func NewHashGrid(cellSize float64) (*HashGrid, error) {
+	if cellSize <= 0 || math.IsInf(cellSize, 0) || math.IsNaN(cellSize) {
+		return nil, errors.Errorf("hash grid cell size must be positive, got %v", cellSize)
+	}
+	return &HashGrid{
+		cellSize: cellSize,
+		cells:    map[voxelKey][]r3.Vector{},
+		points:   &matrixStorage{points: []PointAndData{}, indexMap: map[r3.Vector]uint{}},
+		meta:     NewMetaData(),
+	}, nil
+}
+
+// Size returns the number of points in the grid.
+func (g *HashGrid) Size() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.points.Size()
+}
+
+// MetaData returns the meta data of the points in the grid.
+func (g *HashGrid) MetaData() MetaData {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.meta
+}
+
+// Set adds a point to the grid, or replaces its data if it is already in it.
+func (g *HashGrid) Set(p r3.Vector, d Data) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, exists := g.points.At(p.X, p.Y, p.Z)
+	if err := g.points.Set(p, d); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	key := newVoxelKey(p, g.cellSize)
+	if len(g.cells) == 0 {
+		g.minKey, g.maxKey = key, key
+	}
+	g.minKey = voxelKey{minInt64(g.minKey.x, key.x), minInt64(g.minKey.y, key.y), minInt64(g.minKey.z, key.z)}
+	g.maxKey = voxelKey{maxInt64(g.maxKey.x, key.x), maxInt64(g.maxKey.y, key.y), maxInt64(g.maxKey.z, key.z)}
+	g.cells[key] = append(g.cells[key], p)
+	g.meta.Merge(p, d)
+	return nil
+}
+
+// At returns the data of the point at the given position, if there is one.
+func (g *HashGrid) At(x, y, z float64) (Data, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.points.At(x, y, z)
+}
+
+// Iterate calls fn with the points of the grid in the order they were added, stopping if it returns false. It goes
+// over the points that were in the grid when it was called, so fn may add points to the grid.
+func (g *HashGrid) Iterate(numBatches, myBatch int, fn func(p r3.Vector, d Data) bool) {
+	g.mu.RLock()
+	batch := make([]PointAndData, 0)
+	g.points.Iterate(numBatches, myBatch, func(p r3.Vector, d Data) bool {
+		batch = append(batch, PointAndData{p, d})
+		return true
+	})
+	g.mu.RUnlock()
+	for _, pd := range batch {
+		if !fn(pd.P, pd.D) {
+			return
+		}
+	}
+}
+
+// NearestNeighbor returns the nearest point and its distance from the input point.
+func (g *HashGrid) NearestNeighbor(p r3.Vector) (r3.Vector, Data, float64, bool) {
+	nearest := g.KNearestNeighbors(p, 1, true)
+	if len(nearest) == 0 {
+		return r3.Vector{}, nil, 0, false
+	}
+	return nearest[0].P, nearest[0].D, p.Distance(nearest[0].P), true
+}
+
+// KNearestNeighbors returns the k nearest points ordered by distance. If includeSelf is true and the point p is in the
+// grid, it is returned as the first element.
+func (g *HashGrid) KNearestNeighbors(p r3.Vector, k int, includeSelf bool) []*PointAndData {
+	if k <= 0 {
+		return nil
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if len(g.cells) == 0 {
+		return nil
+	}
+
+	// search shells of cells further and further around the cell of p, until the k-th nearest point found is closer
+	// than any point of the cells not searched yet can be
+	center := newVoxelKey(p, g.cellSize)
+	maxRing := maxInt64(
+		maxInt64(absInt64(center.x-g.minKey.x), absInt64(g.maxKey.x-center.x)),
+		maxInt64(
+			maxInt64(absInt64(center.y-g.minKey.y), absInt64(g.maxKey.y-center.y)),
+			maxInt64(absInt64(center.z-g.minKey.z), absInt64(g.maxKey.z-center.z)),
+		),
+	)
+	var found []hashGridNeighbor
+	searched := int64(0)
+	for ring := int64(0); ring <= maxRing; ring++ {
+		shell := (2*ring+1)*(2*ring+1)*(2*ring+1) - (2*ring-1)*(2*ring-1)*(2*ring-1)
+		if ring == 0 {
+			shell = 1
+		}
+		// once there are more cells left to search than are occupied, it is faster to go over all of them
+		if searched+shell > int64(len(g.cells)) {
+			found = g.searchAll(p, math.Inf(1), includeSelf)
+			break
+		}
+		g.searchShell(center, ring, p, includeSelf, &found)
+		searched += shell
+		if len(found) >= k {
+			sortNeighbors(found)
+			if found[k-1].dist <= g.distanceToCubeEdge(p, center, ring) {
+				break
+			}
+		}
+	}
+	sortNeighbors(found)
+	if len(found) > k {
+		found = found[:k]
+	}
+	return g.toPointAndData(found)
+}
+
+// RadiusNearestNeighbors returns the points within a radius r (inclusive) of p ordered by distance. If includeSelf is
+// true and the point p is in the grid, it is returned as the first element.
+func (g *HashGrid) RadiusNearestNeighbors(p r3.Vector, r float64, includeSelf bool) []*PointAndData {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	lo := newVoxelKey(p.Sub(r3.Vector{r, r, r}), g.cellSize)
+	hi := newVoxelKey(p.Add(r3.Vector{r, r, r}), g.cellSize)
+	cells := float64(hi.x-lo.x+1) * float64(hi.y-lo.y+1) * float64(hi.z-lo.z+1)
+
+	var found []hashGridNeighbor
+	if cells > float64(len(g.cells)) {
+		found = g.searchAll(p, r, includeSelf)
+	} else {
+		for x := lo.x; x <= hi.x; x++ {
+			for y := lo.y; y <= hi.y; y++ {
+				for z := lo.z; z <= hi.z; z++ {
+					g.searchCell(voxelKey{x, y, z}, p, r, includeSelf, &found)
+				}
+			}
+		}
+	}
+	sortNeighbors(found)
+	return g.toPointAndData(found)
+}
+
+// hashGridNeighbor is a point found by a query and its distance from the query point.
+type hashGridNeighbor struct {
+	p    r3.Vector
+	dist float64
+}
+
+func sortNeighbors(found []hashGridNeighbor) {
+	sort.Slice(found, func(i, j int) bool { return found[i].dist < found[j].dist })
+}
+
+// searchCell adds the points of a cell that are within the radius of p to found.
+func (g *HashGrid) searchCell(key voxelKey, p r3.Vector, radius float64, includeSelf bool, found *[]hashGridNeighbor) {
+	for _, q := range g.cells[key] {
+		if !includeSelf && p.ApproxEqual(q) {
+			continue
+		}
+		if dist := p.Distance(q); dist <= radius {
+			*found = append(*found, hashGridNeighbor{q, dist})
+		}
+	}
+}
+
+// searchShell adds the points of the cells ring cells away from the center cell to found.
+func (g *HashGrid) searchShell(center voxelKey, ring int64, p r3.Vector, includeSelf bool, found *[]hashGridNeighbor) {
+	for x := center.x - ring; x <= center.x+ring; x++ {
+		for y := center.y - ring; y <= center.y+ring; y++ {
+			step := int64(1)
+			if ring > 0 && absInt64(x-center.x) != ring && absInt64(y-center.y) != ring {
+				// only the two cells on the faces of the shell are in it
+				step = 2 * ring
+			}
+			for z := center.z - ring; z <= center.z+ring; z += step {
+				g.searchCell(voxelKey{x, y, z}, p, math.Inf(1), includeSelf, found)
+			}
+		}
+	}
+}
+
+// searchAll returns the points of the grid within the radius of p.
+func (g *HashGrid) searchAll(p r3.Vector, radius float64, includeSelf bool) []hashGridNeighbor {
+	var found []hashGridNeighbor
+	for key := range g.cells {
+		g.searchCell(key, p, radius, includeSelf, &found)
+	}
+	return found
+}
+
+// distanceToCubeEdge returns the distance from p to the edge of the cube of cells up to ring cells away from the
+// center cell, which no point outside of the cube can be closer than.
+func (g *HashGrid) distanceToCubeEdge(p r3.Vector, center voxelKey, ring int64) float64 {
+	dist := math.Inf(1)
+	for _, axis := range [][2]float64{{p.X, float64(center.x)}, {p.Y, float64(center.y)}, {p.Z, float64(center.z)}} {
+		lo := (axis[1] - float64(ring)) * g.cellSize
+		hi := (axis[1] + float64(ring) + 1) * g.cellSize
+		dist = math.Min(dist, math.Min(axis[0]-lo, hi-axis[0]))
+	}
+	return dist
+}
+
+func (g *HashGrid) toPointAndData(found []hashGridNeighbor) []*PointAndData {
+	points := make([]*PointAndData, 0, len(found))
+	for _, n := range found {
+		d, ok := g.points.At(n.p.X, n.p.Y, n.p.Z)
+		if !ok {
+			panic("Mismatch between grid and point storage.")
+		}
+		points = append(points, &PointAndData{P: n.p, D: d})
+	}
+	return points
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absInt64(a int64) int64 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
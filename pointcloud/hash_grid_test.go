@@ -0,0 +1,90 @@
+package pointcloud
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestHashGrid(t *testing.T) {
+	_, err := NewHashGrid(0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// the grid answers queries the same as a KD tree of the same points, whatever its cell size
+	//nolint:gosec
+	r := rand.New(rand.NewSource(1))
+	kd := NewKDTree()
+	var points []r3.Vector
+	for i := 0; i < 1000; i++ {
+		p := r3.Vector{r.Float64() * 100, r.Float64() * 100, r.Float64() * 10}
+		points = append(points, p)
+		test.That(t, kd.Set(p, NewValueData(i)), test.ShouldBeNil)
+	}
+	for _, cellSize := range []float64{0.5, 5, 1000} {
+		grid, err := NewHashGrid(cellSize)
+		test.That(t, err, test.ShouldBeNil)
+		for i, p := range points {
+			test.That(t, grid.Set(p, NewValueData(i)), test.ShouldBeNil)
+		}
+		test.That(t, grid.Size(), test.ShouldEqual, 1000)
+		d, ok := grid.At(points[7].X, points[7].Y, points[7].Z)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, d.Value(), test.ShouldEqual, 7)
+
+		for i := 0; i < 50; i++ {
+			p := r3.Vector{r.Float64()*200 - 50, r.Float64() * 100, r.Float64() * 50}
+			includeSelf := i%2 == 0
+			if i%3 == 0 {
+				p = points[r.Intn(len(points))]
+			}
+
+			nearest, _, dist, ok := grid.NearestNeighbor(p)
+			test.That(t, ok, test.ShouldBeTrue)
+			kdNearest, _, kdDist, _ := kd.NearestNeighbor(p)
+			test.That(t, nearest, test.ShouldResemble, kdNearest)
+			test.That(t, dist, test.ShouldAlmostEqual, kdDist)
+
+			assertSameDistances(t, p, grid.KNearestNeighbors(p, 10, includeSelf), kd.KNearestNeighbors(p, 10, includeSelf))
+			assertSameDistances(t, p, grid.RadiusNearestNeighbors(p, 8, includeSelf), kd.RadiusNearestNeighbors(p, 8, includeSelf))
+		}
+	}
+
+	empty, err := NewHashGrid(1)
+	test.That(t, err, test.ShouldBeNil)
+	_, _, _, ok := empty.NearestNeighbor(r3.Vector{})
+	test.That(t, ok, test.ShouldBeFalse)
+}
+
+func assertSameDistances(t *testing.T, p r3.Vector, got, want []*PointAndData) {
+	t.Helper()
+	test.That(t, got, test.ShouldHaveLength, len(want))
+	for i := range got {
+		test.That(t, got[i].P.Distance(p), test.ShouldAlmostEqual, want[i].P.Distance(p))
+	}
+}
+
+func TestHashGridConcurrentInsertion(t *testing.T) {
+	grid, err := NewHashGrid(5)
+	test.That(t, err, test.ShouldBeNil)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 2000; i++ {
+			test.That(t, grid.Set(r3.Vector{float64(i % 50), float64(i / 50), 0}, nil), test.ShouldBeNil)
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		neighbors := grid.KNearestNeighbors(r3.Vector{25, 20, 1}, 5, true)
+		test.That(t, len(neighbors), test.ShouldBeLessThanOrEqualTo, 5)
+		grid.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+			return true
+		})
+	}
+	wg.Wait()
+	test.That(t, grid.Size(), test.ShouldEqual, 2000)
+	test.That(t, grid.RadiusNearestNeighbors(r3.Vector{25, 20, 0}, 1, false), test.ShouldHaveLength, 4)
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 	"go.viam.com/utils"
 	"gonum.org/v1/gonum/diff/fd"
 	"gonum.org/v1/gonum/mat"
@@ -161,3 +162,328 @@ func RegisterPointCloudICP(pcSrc PointCloud, target *KDTree, guess spatialmath.P
 
 	return registeredPointCloud, IcpMergeResultInfo{X0: x0, OptResult: *res}, nil
 }
+
+// ICPMethod is the error that ICP minimizes between the points of the source cloud and their closest points in the target.
+type ICPMethod string
+
+const (
+	// ICPPointToPoint minimizes the distances between the points, which works on any clouds.
+	ICPPointToPoint = ICPMethod("point_to_point")
+	// ICPPointToPlane minimizes the distances from the source points to the planes through the target points, which
+	// lets flat surfaces slide along each other and converges in fewer iterations on clouds of smooth surfaces.
+	ICPPointToPlane = ICPMethod("point_to_plane")
+)
+
+// ICPOptions configure ICP. Options left at their zero values take the defaults given.
+type ICPOptions struct {
+	// Method defaults to ICPPointToPoint.
+	Method ICPMethod
+	// MaxIterations defaults to 50.
+	MaxIterations int
+	// MaxCorrespondenceDistance is how far the closest point in the target can be for a source point to be matched
+	// to it. It defaults to any distance.
+	MaxCorrespondenceDistance float64
+	// Tolerance stops the iterations once the change to the transform in one is smaller, in the units of the clouds
+	// for its translation and radians for its rotation. It defaults to 1e-6.
+	Tolerance float64
+	// NormalNeighbors is the number of points of the target used to find the normal of a target point for
+	// ICPPointToPlane. It defaults to 10.
+	NormalNeighbors int
+}
+
+// ICPResult is the transform ICP finds and how well the clouds match with it.
+type ICPResult struct {
+	// Transform takes the source cloud into the frame of the target.
+	Transform spatialmath.Pose
+	// Fitness is the fraction of the source points that were matched to a target point.
+	Fitness float64
+	// InlierRMSE is the root mean square distance between the matched points.
+	InlierRMSE float64
+	Iterations int
+	Converged  bool
+}
+
+// ICP registers a source point cloud to a target one with iterative closest point, starting from an initial guess of
+// the transform between them. Each iteration matches every source point to its closest target point and solves
+// for the rigid transform that best aligns the matches, in closed form for ICPPointToPoint and by linear least
+// squares for ICPPointToPlane.
+func ICP(source PointCloud, target *KDTree, guess spatialmath.Pose, opts ICPOptions) (ICPResult, error) {
+	if opts.Method == "" {
+		opts.Method = ICPPointToPoint
+	}
+	if opts.Method != ICPPointToPoint && opts.Method != ICPPointToPlane {
+		return ICPResult{}, errors.Errorf("unknown ICP method %q", opts.Method)
+	}
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = 50
+	}
+	if opts.MaxCorrespondenceDistance <= 0 {
+		opts.MaxCorrespondenceDistance = math.Inf(1)
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 1e-6
+	}
+	if opts.NormalNeighbors <= 0 {
+		opts.NormalNeighbors = 10
+	}
+	if source.Size() == 0 || target.Size() == 0 {
+		return ICPResult{}, errors.New("cannot register empty point clouds")
+	}
+
+	sourcePoints := make([]r3.Vector, 0, source.Size())
+	source.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		sourcePoints = append(sourcePoints, p)
+		return true
+	})
+	icp := &icpState{target: target, opts: opts, sourcePoints: sourcePoints, normals: map[r3.Vector]r3.Vector{}}
+
+	transform := rigidTransformFromPose(guess)
+	result := ICPResult{}
+	for result.Iterations < opts.MaxIterations {
+		matches := icp.match(transform)
+		var step rigidTransform
+		var err error
+		if opts.Method == ICPPointToPlane {
+			step, err = solvePointToPlane(matches)
+		} else {
+			step, err = solvePointToPoint(matches)
+		}
+		if err != nil {
+			return ICPResult{}, err
+		}
+		transform = step.compose(transform)
+		result.Iterations++
+		if step.t.Norm() < opts.Tolerance && step.angle() < opts.Tolerance {
+			result.Converged = true
+			break
+		}
+	}
+
+	matches := icp.match(transform)
+	if len(matches) > 0 {
+		sumSq := 0.
+		for _, m := range matches {
+			sumSq += m.source.Sub(m.target).Norm2()
+		}
+		result.InlierRMSE = math.Sqrt(sumSq / float64(len(matches)))
+	}
+	result.Fitness = float64(len(matches)) / float64(len(sourcePoints))
+	pose, err := transform.pose()
+	if err != nil {
+		return ICPResult{}, err
+	}
+	result.Transform = pose
+	return result, nil
+}
+
+// icpMatch is a source point, moved by the current transform, matched to its closest target point.
+type icpMatch struct {
+	source, target, normal r3.Vector
+}
+
+type icpState struct {
+	target       *KDTree
+	opts         ICPOptions
+	sourcePoints []r3.Vector
+
+	normalsMu sync.Mutex
+	normals   map[r3.Vector]r3.Vector
+}
+
+// match moves the source points by the transform and matches them to their closest target points.
+func (icp *icpState) match(transform rigidTransform) []icpMatch {
+	found := make([]icpMatch, len(icp.sourcePoints))
+	ok := make([]bool, len(icp.sourcePoints))
+	parallelBatches(len(icp.sourcePoints), func(start, end int) {
+		for i := start; i < end; i++ {
+			p := transform.apply(icp.sourcePoints[i])
+			nearest, _, dist, got := icp.target.NearestNeighbor(p)
+			if !got || dist > icp.opts.MaxCorrespondenceDistance {
+				continue
+			}
+			found[i] = icpMatch{source: p, target: nearest}
+			if icp.opts.Method == ICPPointToPlane {
+				found[i].normal = icp.normal(nearest)
+			}
+			ok[i] = true
+		}
+	})
+	matches := make([]icpMatch, 0, len(found))
+	for i, m := range found {
+		if ok[i] {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// normal returns the normal of the surface through the neighbors of a target point, which is cached as the same
+// target points are matched in every iteration.
+func (icp *icpState) normal(p r3.Vector) r3.Vector {
+	icp.normalsMu.Lock()
+	n, ok := icp.normals[p]
+	icp.normalsMu.Unlock()
+	if ok {
+		return n
+	}
+	n = surfaceNormal(icp.target, p, icp.opts.NormalNeighbors)
+	icp.normalsMu.Lock()
+	icp.normals[p] = n
+	icp.normalsMu.Unlock()
+	return n
+}
+
+// solvePointToPoint returns the rigid transform that minimizes the squared distances between the matched points,
+// with the SVD of their cross covariance.
+func solvePointToPoint(matches []icpMatch) (rigidTransform, error) {
+	if len(matches) < 3 {
+		return rigidTransform{}, errors.Errorf("ICP needs at least 3 matched points, got %d", len(matches))
+	}
+	var sourceCentroid, targetCentroid r3.Vector
+	for _, m := range matches {
+		sourceCentroid = sourceCentroid.Add(m.source)
+		targetCentroid = targetCentroid.Add(m.target)
+	}
+	sourceCentroid = sourceCentroid.Mul(1 / float64(len(matches)))
+	targetCentroid = targetCentroid.Mul(1 / float64(len(matches)))
+
+	cov := mat.NewDense(3, 3, nil)
+	for _, m := range matches {
+		s := m.source.Sub(sourceCentroid)
+		t := m.target.Sub(targetCentroid)
+		sv := [3]float64{s.X, s.Y, s.Z}
+		tv := [3]float64{t.X, t.Y, t.Z}
+		for r := 0; r < 3; r++ {
+			for c := 0; c < 3; c++ {
+				cov.Set(r, c, cov.At(r, c)+sv[r]*tv[c])
+			}
+		}
+	}
+	var svd mat.SVD
+	if !svd.Factorize(cov, mat.SVDFull) {
+		return rigidTransform{}, errors.New("ICP could not factorize the covariance of the matched points")
+	}
+	var u, v, rot mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	rot.Mul(&v, u.T())
+	if mat.Det(&rot) < 0 {
+		// a reflection, so flip the axis of least variance
+		for r := 0; r < 3; r++ {
+			v.Set(r, 2, -v.At(r, 2))
+		}
+		rot.Mul(&v, u.T())
+	}
+
+	var step rigidTransform
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			step.r[3*r+c] = rot.At(r, c)
+		}
+	}
+	step.t = targetCentroid.Sub(step.rotate(sourceCentroid))
+	return step, nil
+}
+
+// solvePointToPlane returns the rigid transform that minimizes the squared distances from the source points to the
+// planes through the matched target points, linearized for small rotations.
+func solvePointToPlane(matches []icpMatch) (rigidTransform, error) {
+	if len(matches) < 6 {
+		return rigidTransform{}, errors.Errorf("point to plane ICP needs at least 6 matched points, got %d", len(matches))
+	}
+	ata := mat.NewSymDense(6, nil)
+	atb := mat.NewVecDense(6, nil)
+	for _, m := range matches {
+		c := m.source.Cross(m.normal)
+		row := [6]float64{c.X, c.Y, c.Z, m.normal.X, m.normal.Y, m.normal.Z}
+		b := m.target.Sub(m.source).Dot(m.normal)
+		for i := 0; i < 6; i++ {
+			for j := i; j < 6; j++ {
+				ata.SetSym(i, j, ata.At(i, j)+row[i]*row[j])
+			}
+			atb.SetVec(i, atb.AtVec(i)+row[i]*b)
+		}
+	}
+	var x mat.VecDense
+	if err := x.SolveVec(ata, atb); err != nil {
+		// an ill conditioned system still has a solution, which the next iteration can improve on
+		var cond mat.Condition
+		if !errors.As(err, &cond) {
+			return rigidTransform{}, errors.Wrap(err, "point to plane ICP could not solve for the transform, the target may be a single plane")
+		}
+	}
+	step := rigidTransformFromRotationVector(r3.Vector{X: x.AtVec(0), Y: x.AtVec(1), Z: x.AtVec(2)})
+	step.t = r3.Vector{X: x.AtVec(3), Y: x.AtVec(4), Z: x.AtVec(5)}
+	return step, nil
+}
+
+// rigidTransform is a rotation, as a row major matrix, followed by a translation. It is used instead of a
+// spatialmath.Pose while iterating as it is applied to every point of the source cloud in each iteration.
+type rigidTransform struct {
+	r [9]float64
+	t r3.Vector
+}
+
+func rigidTransformFromPose(pose spatialmath.Pose) rigidTransform {
+	rm := pose.Orientation().RotationMatrix()
+	var rt rigidTransform
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			rt.r[3*r+c] = rm.At(r, c)
+		}
+	}
+	rt.t = pose.Point()
+	return rt
+}
+
+// rigidTransformFromRotationVector returns the rotation about the axis of the vector by its length in radians.
+func rigidTransformFromRotationVector(w r3.Vector) rigidTransform {
+	theta := w.Norm()
+	if theta < 1e-12 {
+		return rigidTransform{r: [9]float64{1, -w.Z, w.Y, w.Z, 1, -w.X, -w.Y, w.X, 1}}
+	}
+	k := w.Mul(1 / theta)
+	s, c := math.Sin(theta), 1-math.Cos(theta)
+	return rigidTransform{r: [9]float64{
+		1 - c*(k.Y*k.Y+k.Z*k.Z), -s*k.Z + c*k.X*k.Y, s*k.Y + c*k.X*k.Z,
+		s*k.Z + c*k.X*k.Y, 1 - c*(k.X*k.X+k.Z*k.Z), -s*k.X + c*k.Y*k.Z,
+		-s*k.Y + c*k.X*k.Z, s*k.X + c*k.Y*k.Z, 1 - c*(k.X*k.X+k.Y*k.Y),
+	}}
+}
+
+func (rt rigidTransform) rotate(p r3.Vector) r3.Vector {
+	return r3.Vector{
+		X: rt.r[0]*p.X + rt.r[1]*p.Y + rt.r[2]*p.Z,
+		Y: rt.r[3]*p.X + rt.r[4]*p.Y + rt.r[5]*p.Z,
+		Z: rt.r[6]*p.X + rt.r[7]*p.Y + rt.r[8]*p.Z,
+	}
+}
+
+func (rt rigidTransform) apply(p r3.Vector) r3.Vector {
+	return rt.rotate(p).Add(rt.t)
+}
+
+// compose returns the transform that applies other and then rt.
+func (rt rigidTransform) compose(other rigidTransform) rigidTransform {
+	var out rigidTransform
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			out.r[3*r+c] = rt.r[3*r]*other.r[c] + rt.r[3*r+1]*other.r[3+c] + rt.r[3*r+2]*other.r[6+c]
+		}
+	}
+	out.t = rt.apply(other.t)
+	return out
+}
+
+// angle returns the angle of the rotation in radians.
+func (rt rigidTransform) angle() float64 {
+	return math.Acos(math.Max(-1, math.Min(1, (rt.r[0]+rt.r[4]+rt.r[8]-1)/2)))
+}
+
+func (rt rigidTransform) pose() (spatialmath.Pose, error) {
+	rm, err := spatialmath.NewRotationMatrix(rt.r[:])
+	if err != nil {
+		return nil, err
+	}
+	return spatialmath.NewPose(rt.t, rm), nil
+}
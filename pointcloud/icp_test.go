@@ -1,6 +1,7 @@
 package pointcloud
 
 import (
+	"math"
 	"os"
 	"testing"
 
@@ -51,3 +52,45 @@ func TestICPRegistration(t *testing.T) {
 
 	test.That(t, info.OptResult.F, test.ShouldBeLessThan, 20.)
 }
+
+func TestICP(t *testing.T) {
+	// three faces of a box, so that both methods have a unique solution
+	source := New()
+	for a := -10.; a <= 10; a += 2 {
+		for b := -10.; b <= 10; b += 2 {
+			test.That(t, source.Set(r3.Vector{a, b, 0}, nil), test.ShouldBeNil)
+			test.That(t, source.Set(r3.Vector{a, 0, b}, nil), test.ShouldBeNil)
+			test.That(t, source.Set(r3.Vector{0, a, b}, nil), test.ShouldBeNil)
+		}
+	}
+	truth := spatialmath.NewPose(r3.Vector{3, -2, 1}, &spatialmath.R4AA{Theta: 8 * math.Pi / 180, RX: 0.1, RY: 0.1, RZ: 1})
+	target := NewKDTree()
+	source.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		test.That(t, target.Set(spatialmath.Compose(truth, spatialmath.NewPoseFromPoint(p)).Point(), d), test.ShouldBeNil)
+		return true
+	})
+
+	for _, method := range []ICPMethod{ICPPointToPoint, ICPPointToPlane} {
+		t.Run(string(method), func(t *testing.T) {
+			result, err := ICP(source, target, spatialmath.NewZeroPose(), ICPOptions{Method: method})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, result.Converged, test.ShouldBeTrue)
+			test.That(t, spatialmath.PoseAlmostEqualEps(result.Transform, truth, 1e-3), test.ShouldBeTrue)
+			test.That(t, result.Fitness, test.ShouldEqual, 1)
+			test.That(t, result.InlierRMSE, test.ShouldBeLessThan, 1e-3)
+		})
+	}
+
+	// only the points near the target are matched
+	result, err := ICP(source, target, truth, ICPOptions{MaxCorrespondenceDistance: 1, MaxIterations: 1})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, result.Fitness, test.ShouldEqual, 1)
+	far := spatialmath.Compose(spatialmath.NewPoseFromPoint(r3.Vector{X: 1000}), truth)
+	_, err = ICP(source, target, far, ICPOptions{MaxCorrespondenceDistance: 1})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	_, err = ICP(source, target, truth, ICPOptions{Method: "point_to_mesh"})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = ICP(New(), target, truth, ICPOptions{})
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -207,3 +207,29 @@ func TestStatisticalOutlierFilter(t *testing.T) {
 	test.That(t, CloudContains(filtered, -3.2, -3.2, -3.2), test.ShouldBeTrue)
 	test.That(t, CloudContains(filtered, 2000, 2000, 2000), test.ShouldBeFalse)
 }
+
+func TestRadiusOutlierFilter(t *testing.T) {
+	_, err := RadiusOutlierFilter(0, 1)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = RadiusOutlierFilter(2, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// the points of the cloud are about 1.8 apart, apart from the outlier
+	filter, err := RadiusOutlierFilter(2, 1)
+	test.That(t, err, test.ShouldBeNil)
+	filtered, err := filter(makePointCloud(t))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filtered.Size(), test.ShouldEqual, 7)
+	test.That(t, CloudContains(filtered, 0, 0, 0), test.ShouldBeTrue)
+	test.That(t, CloudContains(filtered, -3.2, -3.2, -3.2), test.ShouldBeTrue)
+	test.That(t, CloudContains(filtered, 2000, 2000, 2000), test.ShouldBeFalse)
+
+	// only the points in the middle of the line have two neighbors
+	filter, err = RadiusOutlierFilter(2, 2)
+	test.That(t, err, test.ShouldBeNil)
+	filtered, err = filter(makePointCloud(t))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filtered.Size(), test.ShouldEqual, 5)
+	test.That(t, CloudContains(filtered, 3, 3, 3), test.ShouldBeFalse)
+	test.That(t, CloudContains(filtered, -3.2, -3.2, -3.2), test.ShouldBeFalse)
+}
@@ -0,0 +1,212 @@
+package pointcloud
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// lodOctreeDepthDefault is the depth of an LODOctree unless another is given, at which the cubes of an octree 10 m
+// wide are about 0.15 mm wide.
+const lodOctreeDepthDefault = 16
+
+// LODOctree is a PointCloud that also sorts its points into an octree of a fixed depth, in which every cube keeps the
+// centroid, average color and intensity of the points in it. That gives the cloud levels of detail: level 0 is a
+// single point, and every level after it has a point for each occupied cube a half as wide as those of the level
+// before it. They let clouds too large to send or plan with be cut down to about as many points as can be handled,
+// while keeping points spread evenly over the cloud.
+type LODOctree struct {
+	center     r3.Vector
+	sideLength float64
+	root       *lodOctreeNode
+	levelSizes []int
+	points     *matrixStorage
+	meta       MetaData
+}
+
+// lodOctreeNode is a cube of an LODOctree.
+type lodOctreeNode struct {
+	sum      voxelSum
+	children [8]*lodOctreeNode
+}
+
+// NewLODOctree returns an empty LODOctree of cubes with the given center and side length, with levels of detail up
+// to the given depth, or to a depth of 16 if it is 0.
+func NewLODOctree(center r3.Vector, sideLength float64, depth int) (*LODOctree, error) {
+	if sideLength <= 0 {
+		return nil, errors.Errorf("invalid side length (%.2f) for octree", sideLength)
+	}
+	if depth < 0 {
+		return nil, errors.Errorf("invalid depth (%d) for octree", depth)
+	}
+	if depth == 0 {
+		depth = lodOctreeDepthDefault
+	}
+	return &LODOctree{
+		center:     center,
+		sideLength: sideLength,
+		levelSizes: make([]int, depth+1),
+		points:     &matrixStorage{points: []PointAndData{}, indexMap: map[r3.Vector]uint{}},
+		meta:       NewMetaData(),
+	}, nil
+}
+
+// NewLODOctreeFromPointCloud returns an LODOctree just large enough to hold the points of the given cloud, with
+// levels of detail up to the given depth, or to a depth of 16 if it is 0.
+func NewLODOctreeFromPointCloud(pc PointCloud, depth int) (*LODOctree, error) {
+	meta := pc.MetaData()
+	center, sideLength := r3.Vector{}, 1.
+	if pc.Size() != 0 {
+		// a cloud of a single point has no extent, but the octree needs some
+		center, sideLength = getCenterFromPcMetaData(meta), math.Max(getMaxSideLengthFromPcMetaData(meta), 1)
+	}
+	octree, err := NewLODOctree(center, sideLength, depth)
+	if err != nil {
+		return nil, err
+	}
+	pc.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		err = octree.Set(p, d)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return octree, nil
+}
+
+// Size returns the number of points in the octree.
+func (octree *LODOctree) Size() int {
+	return octree.points.Size()
+}
+
+// MetaData returns the meta data of the points in the octree.
+func (octree *LODOctree) MetaData() MetaData {
+	return octree.meta
+}
+
+// Set adds a point to the octree, or replaces its data if it is already in it. The levels of detail keep the data
+// the point was first added with.
+func (octree *LODOctree) Set(p r3.Vector, d Data) error {
+	halfSide := octree.sideLength / 2 * (1 + placementTolerance)
+	offset := p.Sub(octree.center)
+	if math.Abs(offset.X) > halfSide || math.Abs(offset.Y) > halfSide || math.Abs(offset.Z) > halfSide {
+		return errors.Errorf("point (%v, %v, %v) is outside of the octree", p.X, p.Y, p.Z)
+	}
+	_, exists := octree.points.At(p.X, p.Y, p.Z)
+	if err := octree.points.Set(p, d); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	octree.meta.Merge(p, d)
+
+	if octree.root == nil {
+		octree.root = &lodOctreeNode{}
+		octree.levelSizes[0]++
+	}
+	node, center, side := octree.root, octree.center, octree.sideLength
+	node.sum.add(p, d)
+	for level := 1; level < len(octree.levelSizes); level++ {
+		side /= 2
+		octant := 0
+		childCenter := center.Sub(r3.Vector{X: side / 2, Y: side / 2, Z: side / 2})
+		if p.X >= center.X {
+			octant |= 1
+			childCenter.X += side
+		}
+		if p.Y >= center.Y {
+			octant |= 2
+			childCenter.Y += side
+		}
+		if p.Z >= center.Z {
+			octant |= 4
+			childCenter.Z += side
+		}
+		if node.children[octant] == nil {
+			node.children[octant] = &lodOctreeNode{}
+			octree.levelSizes[level]++
+		}
+		node, center = node.children[octant], childCenter
+		node.sum.add(p, d)
+	}
+	return nil
+}
+
+// At returns the data of the point at the given position, if there is one.
+func (octree *LODOctree) At(x, y, z float64) (Data, bool) {
+	return octree.points.At(x, y, z)
+}
+
+// Iterate calls fn with the points of the octree in the order they were added, stopping if it returns false.
+func (octree *LODOctree) Iterate(numBatches, myBatch int, fn func(p r3.Vector, d Data) bool) {
+	octree.points.Iterate(numBatches, myBatch, fn)
+}
+
+// Depth returns the deepest level of detail of the octree.
+func (octree *LODOctree) Depth() int {
+	return len(octree.levelSizes) - 1
+}
+
+// LevelSize returns the number of points in the given level of detail.
+func (octree *LODOctree) LevelSize(level int) int {
+	if level < 0 || level > octree.Depth() {
+		return 0
+	}
+	return octree.levelSizes[level]
+}
+
+// Level returns the cloud at the given level of detail, with a point for each occupied cube of that level.
+func (octree *LODOctree) Level(level int) (PointCloud, error) {
+	if level < 0 || level > octree.Depth() {
+		return nil, errors.Errorf("octree has no level of detail %d, its levels go from 0 to %d", level, octree.Depth())
+	}
+	cloud := NewWithPrealloc(octree.levelSizes[level])
+	if octree.root == nil {
+		return cloud, nil
+	}
+	var err error
+	var visit func(node *lodOctreeNode, depth int)
+	visit = func(node *lodOctreeNode, depth int) {
+		if depth == level {
+			err = cloud.Set(node.sum.point())
+			return
+		}
+		for _, child := range node.children {
+			if child != nil && err == nil {
+				visit(child, depth+1)
+			}
+		}
+	}
+	visit(octree.root, 0)
+	if err != nil {
+		return nil, err
+	}
+	return cloud, nil
+}
+
+// LevelOfDetail returns the cloud at the finest level of detail with at most maxPoints points, or all of the points
+// of the octree if there are no more than that of them.
+func (octree *LODOctree) LevelOfDetail(maxPoints int) (PointCloud, error) {
+	if maxPoints < 1 {
+		return nil, errors.Errorf("a level of detail needs at least 1 point, got %d", maxPoints)
+	}
+	if octree.Size() <= maxPoints {
+		cloud := NewWithPrealloc(octree.Size())
+		var err error
+		octree.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+			err = cloud.Set(p, d)
+			return err == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return cloud, nil
+	}
+	level := 0
+	for level < octree.Depth() && octree.levelSizes[level+1] <= maxPoints {
+		level++
+	}
+	return octree.Level(level)
+}
@@ -0,0 +1,71 @@
+package pointcloud
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestLODOctree(t *testing.T) {
+	_, err := NewLODOctree(r3.Vector{}, 0, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewLODOctree(r3.Vector{}, 1, -1)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// a 16x16x16 cube of points with a spacing of 1 mm
+	cloud := New()
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 16; y++ {
+			for z := 0; z < 16; z++ {
+				d := NewColoredData(color.NRGBA{uint8(x * 16), 0, 0, 255})
+				test.That(t, cloud.Set(r3.Vector{float64(x), float64(y), float64(z)}, d), test.ShouldBeNil)
+			}
+		}
+	}
+	octree, err := NewLODOctreeFromPointCloud(cloud, 4)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, octree.Size(), test.ShouldEqual, 4096)
+	test.That(t, octree.Depth(), test.ShouldEqual, 4)
+	d, ok := octree.At(3, 4, 5)
+	test.That(t, ok, test.ShouldBeTrue)
+	r, _, _ := d.RGB255()
+	test.That(t, r, test.ShouldEqual, 48)
+	test.That(t, octree.Set(r3.Vector{X: 100}, nil), test.ShouldNotBeNil)
+
+	// every level has 8 times as many points as the one before it
+	for level, size := range []int{1, 8, 64, 512, 4096} {
+		test.That(t, octree.LevelSize(level), test.ShouldEqual, size)
+	}
+	test.That(t, octree.LevelSize(5), test.ShouldEqual, 0)
+	_, err = octree.Level(5)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	coarsest, err := octree.Level(0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, coarsest.Size(), test.ShouldEqual, 1)
+	d, ok = coarsest.At(7.5, 7.5, 7.5)
+	test.That(t, ok, test.ShouldBeTrue)
+	r, _, _ = d.RGB255()
+	test.That(t, r, test.ShouldEqual, 120)
+
+	// a level of detail is the finest one that fits
+	lod, err := octree.LevelOfDetail(1000)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lod.Size(), test.ShouldEqual, 512)
+	test.That(t, CloudContains(lod, 0.5, 0.5, 0.5), test.ShouldBeTrue)
+	lod, err = octree.LevelOfDetail(5000)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lod.Size(), test.ShouldEqual, 4096)
+	test.That(t, CloudContains(lod, 3, 4, 5), test.ShouldBeTrue)
+	_, err = octree.LevelOfDetail(0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	empty, err := NewLODOctreeFromPointCloud(New(), 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, empty.Depth(), test.ShouldEqual, 16)
+	lod, err = empty.LevelOfDetail(10)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lod.Size(), test.ShouldEqual, 0)
+}
@@ -0,0 +1,106 @@
+package pointcloud
+
+import (
+	"github.com/pkg/errors"
+)
+
+// LZF is the compression of the data of binary_compressed PCD files. Its data is a series of chunks, each starting
+// with a control byte. A control byte under 32 is followed by that many plus one literal bytes. Otherwise its top
+// 3 bits are the length of a back reference minus 2, with 7 meaning the length continues in the next byte, and its
+// bottom 5 bits and the byte after the length are how far back the reference starts, minus 1.
+// http://oldhome.schmorp.de/marc/liblzf.html
+const (
+	lzfHashBits   = 14
+	lzfMaxLiteral = 1 << 5
+	lzfMaxOffset  = 1 << 13
+	lzfMaxRef     = (1 << 8) + (1 << 3)
+)
+
+// lzfCompress compresses the data with LZF.
+func lzfCompress(in []byte) []byte {
+	out := make([]byte, 0, len(in)+len(in)/lzfMaxLiteral+1)
+	var table [1 << lzfHashBits]int
+	literals := 0
+	flushLiterals := func(end int) {
+		for start := end - literals; start < end; start += lzfMaxLiteral {
+			n := end - start
+			if n > lzfMaxLiteral {
+				n = lzfMaxLiteral
+			}
+			out = append(out, byte(n-1))
+			out = append(out, in[start:start+n]...)
+		}
+		literals = 0
+	}
+
+	i := 0
+	for i+2 < len(in) {
+		h := (uint32(in[i])<<16 | uint32(in[i+1])<<8 | uint32(in[i+2])) * 2654435761 >> (32 - lzfHashBits)
+		ref := table[h] - 1
+		table[h] = i + 1
+		offset := i - ref - 1
+		if ref < 0 || offset >= lzfMaxOffset || in[ref] != in[i] || in[ref+1] != in[i+1] || in[ref+2] != in[i+2] {
+			i++
+			literals++
+			continue
+		}
+		flushLiterals(i)
+		length := 3
+		for length < lzfMaxRef && i+length < len(in) && in[ref+length] == in[i+length] {
+			length++
+		}
+		if n := length - 2; n < 7 {
+			out = append(out, byte(n<<5|offset>>8))
+		} else {
+			out = append(out, byte(7<<5|offset>>8), byte(n-7))
+		}
+		out = append(out, byte(offset))
+		i += length
+	}
+	literals += len(in) - i
+	flushLiterals(len(in))
+	return out
+}
+
+// lzfDecompress decompresses LZF data that decompresses to the given number of bytes.
+func lzfDecompress(in []byte, size int) ([]byte, error) {
+	out := make([]byte, 0, size)
+	for i := 0; i < len(in); {
+		ctrl := int(in[i])
+		i++
+		if ctrl < lzfMaxLiteral {
+			n := ctrl + 1
+			if i+n > len(in) || len(out)+n > size {
+				return nil, errors.New("lzf literal runs past the end of the data")
+			}
+			out = append(out, in[i:i+n]...)
+			i += n
+			continue
+		}
+		length := ctrl >> 5
+		if length == 7 {
+			if i >= len(in) {
+				return nil, errors.New("lzf back reference runs past the end of the data")
+			}
+			length += int(in[i])
+			i++
+		}
+		length += 2
+		if i >= len(in) {
+			return nil, errors.New("lzf back reference runs past the end of the data")
+		}
+		ref := len(out) - ((ctrl&0x1f)<<8 | int(in[i])) - 1
+		i++
+		if ref < 0 || len(out)+length > size {
+			return nil, errors.New("lzf back reference is outside of the data")
+		}
+		// the reference can overlap the bytes it is copying to, so it is copied one byte at a time
+		for j := 0; j < length; j++ {
+			out = append(out, out[ref+j])
+		}
+	}
+	if len(out) != size {
+		return nil, errors.Errorf("lzf data decompressed to %d bytes, expected %d", len(out), size)
+	}
+	return out, nil
+}
@@ -0,0 +1,39 @@
+package pointcloud
+
+import (
+	"math/rand"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestLZF(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	repetitive := make([]byte, 100000)
+	random := make([]byte, 10000)
+	rng.Read(random)
+	mixed := make([]byte, 10000)
+	for i := range mixed {
+		if i > 8 && rng.Intn(2) == 0 {
+			mixed[i] = mixed[i-1-rng.Intn(8)]
+		} else {
+			mixed[i] = byte(rng.Intn(16))
+		}
+	}
+	for _, data := range [][]byte{{}, {1}, {1, 2, 3}, repetitive, random, mixed} {
+		compressed := lzfCompress(data)
+		decompressed, err := lzfDecompress(compressed, len(data))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, decompressed, test.ShouldResemble, data)
+	}
+	test.That(t, len(lzfCompress(repetitive)), test.ShouldBeLessThan, len(repetitive)/50)
+
+	// a literal of 3 bytes followed by a back reference to them of length 6, as liblzf writes it
+	decompressed, err := lzfDecompress([]byte{2, 'a', 'b', 'c', 4 << 5, 2}, 9)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(decompressed), test.ShouldEqual, "abcabcabc")
+	_, err = lzfDecompress([]byte{2, 'a', 'b', 'c', 4 << 5, 2}, 8)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = lzfDecompress([]byte{1 << 5, 10}, 3)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,50 @@
+package pointcloud
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// EstimateNormals estimates the normal of the surface at every point of the cloud from the plane that best fits the
+// point and its nearest neighbors. Normals are unit vectors oriented towards the viewpoint, such as the origin of the
+// camera the cloud was seen from, and are zero for points with fewer than 3 neighbors.
+func EstimateNormals(kd *KDTree, neighbors int, viewpoint r3.Vector) (map[r3.Vector]r3.Vector, error) {
+	if neighbors < 3 {
+		return nil, errors.Errorf("need at least 3 neighbors to estimate a normal, got %d", neighbors)
+	}
+	points := make([]r3.Vector, 0, kd.Size())
+	kd.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		points = append(points, p)
+		return true
+	})
+	normals := make([]r3.Vector, len(points))
+	parallelBatches(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			n := surfaceNormal(kd, points[i], neighbors)
+			if n.Dot(viewpoint.Sub(points[i])) < 0 {
+				n = n.Mul(-1)
+			}
+			normals[i] = n
+		}
+	})
+
+	byPoint := make(map[r3.Vector]r3.Vector, len(points))
+	for i, p := range points {
+		byPoint[p] = normals[i]
+	}
+	return byPoint, nil
+}
+
+// surfaceNormal returns the normal of the plane that best fits the point p of the tree and its nearest neighbors, or
+// the zero vector if there are fewer than 3 of them.
+func surfaceNormal(kd *KDTree, p r3.Vector, neighbors int) r3.Vector {
+	nearest := kd.KNearestNeighbors(p, neighbors, true)
+	if len(nearest) < 3 {
+		return r3.Vector{}
+	}
+	points := make([]r3.Vector, 0, len(nearest))
+	for _, neighbor := range nearest {
+		points = append(points, neighbor.P)
+	}
+	return estimatePlaneNormalFromPoints(points)
+}
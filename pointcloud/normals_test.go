@@ -0,0 +1,38 @@
+package pointcloud
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestEstimateNormals(t *testing.T) {
+	kd := NewKDTree()
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			test.That(t, kd.Set(r3.Vector{float64(i), float64(j), 5}, nil), test.ShouldBeNil)
+		}
+	}
+
+	_, err := EstimateNormals(kd, 2, r3.Vector{})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// the normals point towards the viewpoint
+	normals, err := EstimateNormals(kd, 8, r3.Vector{Z: 100})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, normals, test.ShouldHaveLength, 100)
+	for _, n := range normals {
+		test.That(t, n.Sub(r3.Vector{Z: 1}).Norm(), test.ShouldBeLessThan, 1e-6)
+	}
+	normals, err = EstimateNormals(kd, 8, r3.Vector{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, normals[r3.Vector{3, 4, 5}].Sub(r3.Vector{Z: -1}).Norm(), test.ShouldBeLessThan, 1e-6)
+
+	// a lone point has no neighbors to find its normal from
+	lone := NewKDTree()
+	test.That(t, lone.Set(r3.Vector{1, 2, 3}, nil), test.ShouldBeNil)
+	normals, err = EstimateNormals(lone, 8, r3.Vector{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, normals[r3.Vector{1, 2, 3}], test.ShouldResemble, r3.Vector{})
+}
@@ -0,0 +1,258 @@
+package pointcloud
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// The occupancy of a cell of an OccupancyGrid is the probability, as a percentage, that it holds an obstacle, or
+// OccupancyUnknown if nothing is known about it.
+const (
+	OccupancyUnknown  int8 = -1
+	OccupancyFree     int8 = 0
+	OccupancyOccupied int8 = 100
+	// OccupiedThreshold is the occupancy at and above which a cell is treated as holding an obstacle.
+	OccupiedThreshold int8 = 50
+)
+
+// occupancyGridMagic starts the binary encoding of an OccupancyGrid, followed by its version.
+const (
+	occupancyGridMagic   = "OGRD"
+	occupancyGridVersion = 1
+)
+
+// An OccupancyGrid is a regular grid of cubes, or of squares in the XY plane for a 2D grid, that records how likely
+// each is to hold an obstacle. It is the common gridded form of a map, such as the point cloud map of a SLAM
+// service, for navigation, motion planning and display.
+type OccupancyGrid struct {
+	resolution           float64
+	origin               r3.Vector
+	width, height, depth int
+	cells                []int8
+}
+
+// NewOccupancyGrid returns a grid of unknown cells with its minimum corner at origin and cells resolution mm wide.
+// A depth of 1 makes a 2D grid, in which every cell covers all heights.
+func NewOccupancyGrid(origin r3.Vector, resolution float64, width, height, depth int) (*OccupancyGrid, error) {
+	if resolution <= 0 {
+		return nil, errors.New("occupancy grid resolution must be positive")
+	}
+	if width <= 0 || height <= 0 || depth <= 0 {
+		return nil, fmt.Errorf("invalid occupancy grid dimensions %dx%dx%d", width, height, depth)
+	}
+	cells := make([]int8, width*height*depth)
+	for i := range cells {
+		cells[i] = OccupancyUnknown
+	}
+	return &OccupancyGrid{resolution: resolution, origin: origin, width: width, height: height, depth: depth, cells: cells}, nil
+}
+
+// NewOccupancyGridFromPointCloud returns a grid covering the point cloud. Cells holding points are occupied, or as
+// likely to be as the value of their most likely point if the points have values, as in the maps of SLAM services
+// that record probabilities. Cells without points are unknown, since a point cloud records only where obstacles are.
+// A 2D grid projects the points onto the XY plane. The grid of an empty point cloud is a single unknown cell at the
+// origin.
+func NewOccupancyGridFromPointCloud(pc PointCloud, resolution float64, is2D bool) (*OccupancyGrid, error) {
+	if pc.Size() == 0 {
+		return NewOccupancyGrid(r3.Vector{}, resolution, 1, 1, 1)
+	}
+	meta := pc.MetaData()
+	origin := r3.Vector{X: meta.MinX, Y: meta.MinY, Z: meta.MinZ}
+	depth := int(math.Floor((meta.MaxZ-meta.MinZ)/resolution)) + 1
+	if is2D {
+		origin.Z = 0
+		depth = 1
+	}
+	grid, err := NewOccupancyGrid(
+		origin,
+		resolution,
+		int(math.Floor((meta.MaxX-meta.MinX)/resolution))+1,
+		int(math.Floor((meta.MaxY-meta.MinY)/resolution))+1,
+		depth,
+	)
+	if err != nil {
+		return nil, err
+	}
+	pc.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		occupancy := OccupancyOccupied
+		if d != nil && d.HasValue() {
+			occupancy = int8(math.Max(0, math.Min(float64(OccupancyOccupied), float64(d.Value()))))
+		}
+		if i, ok := grid.index(p); ok && occupancy > grid.cells[i] {
+			grid.cells[i] = occupancy
+		}
+		return true
+	})
+	return grid, nil
+}
+
+// Resolution returns the width of a cell, in mm.
+func (g *OccupancyGrid) Resolution() float64 {
+	return g.resolution
+}
+
+// Origin returns the minimum corner of the grid.
+func (g *OccupancyGrid) Origin() r3.Vector {
+	return g.origin
+}
+
+// Dimensions returns the number of cells along X, Y and Z.
+func (g *OccupancyGrid) Dimensions() (int, int, int) {
+	return g.width, g.height, g.depth
+}
+
+// Is2D returns whether the grid is a 2D grid, whose cells cover all heights.
+func (g *OccupancyGrid) Is2D() bool {
+	return g.depth == 1
+}
+
+// At returns the occupancy of the cell with the given indexes.
+func (g *OccupancyGrid) At(x, y, z int) int8 {
+	if !g.inBounds(x, y, z) {
+		return OccupancyUnknown
+	}
+	return g.cells[(z*g.height+y)*g.width+x]
+}
+
+// Set sets the occupancy of the cell with the given indexes.
+func (g *OccupancyGrid) Set(x, y, z int, occupancy int8) error {
+	if !g.inBounds(x, y, z) {
+		return fmt.Errorf("cell (%d, %d, %d) is outside the occupancy grid", x, y, z)
+	}
+	g.cells[(z*g.height+y)*g.width+x] = occupancy
+	return nil
+}
+
+// Occupancy returns the occupancy of the cell holding the point. Points off the grid are unknown.
+func (g *OccupancyGrid) Occupancy(p r3.Vector) int8 {
+	i, ok := g.index(p)
+	if !ok {
+		return OccupancyUnknown
+	}
+	return g.cells[i]
+}
+
+// Occupied returns whether the cell holding the point is at least OccupiedThreshold likely to hold an obstacle.
+func (g *OccupancyGrid) Occupied(p r3.Vector) bool {
+	return g.Occupancy(p) >= OccupiedThreshold
+}
+
+// CellCenter returns the center of the cell with the given indexes. The center of a cell of a 2D grid is at Z=0.
+func (g *OccupancyGrid) CellCenter(x, y, z int) r3.Vector {
+	center := g.origin.Add(r3.Vector{X: float64(x) + 0.5, Y: float64(y) + 0.5, Z: float64(z) + 0.5}.Mul(g.resolution))
+	if g.Is2D() {
+		center.Z = 0
+	}
+	return center
+}
+
+// Iterate calls fn with the indexes and occupancy of every cell until it returns false.
+func (g *OccupancyGrid) Iterate(fn func(x, y, z int, occupancy int8) bool) {
+	for i, occupancy := range g.cells {
+		x, y, z := i%g.width, (i/g.width)%g.height, i/(g.width*g.height)
+		if !fn(x, y, z, occupancy) {
+			return
+		}
+	}
+}
+
+// ToGeometries returns a box for every occupied cell, so that the grid can be used as obstacles for motion planning.
+// The boxes of a 2D grid are given the height of the given number of mm, centered at Z=0.
+func (g *OccupancyGrid) ToGeometries(height2D float64) ([]spatialmath.Geometry, error) {
+	dims := r3.Vector{X: g.resolution, Y: g.resolution, Z: g.resolution}
+	if g.Is2D() {
+		dims.Z = height2D
+	}
+	var geometries []spatialmath.Geometry
+	var err error
+	g.Iterate(func(x, y, z int, occupancy int8) bool {
+		if occupancy < OccupiedThreshold {
+			return true
+		}
+		var box spatialmath.Geometry
+		box, err = spatialmath.NewBox(spatialmath.NewPoseFromPoint(g.CellCenter(x, y, z)), dims, "")
+		if err != nil {
+			return false
+		}
+		geometries = append(geometries, box)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return geometries, nil
+}
+
+// MarshalBinary encodes the grid as a versioned header, giving its resolution, origin and dimensions, followed by its
+// cells, X fastest and then Y, as one signed byte each. It is the form in which grids are sent over the network.
+func (g *OccupancyGrid) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(occupancyGridMagic)
+	fields := []interface{}{
+		uint32(occupancyGridVersion),
+		g.resolution, g.origin.X, g.origin.Y, g.origin.Z,
+		uint32(g.width), uint32(g.height), uint32(g.depth),
+		g.cells,
+	}
+	for _, v := range fields {
+		if err := binary.Write(&buf, binary.LittleEndian, v); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a grid encoded by MarshalBinary.
+func (g *OccupancyGrid) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(occupancyGridMagic)) {
+		return errors.New("not an occupancy grid")
+	}
+	r := bytes.NewReader(data[len(occupancyGridMagic):])
+	var version, width, height, depth uint32
+	var resolution float64
+	var origin r3.Vector
+	for _, v := range []interface{}{&version, &resolution, &origin.X, &origin.Y, &origin.Z, &width, &height, &depth} {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+	if version != occupancyGridVersion {
+		return fmt.Errorf("unsupported occupancy grid version %d", version)
+	}
+	if r.Len() != int(width*height*depth) {
+		return fmt.Errorf("occupancy grid has %d cells but should have %d", r.Len(), width*height*depth)
+	}
+	decoded, err := NewOccupancyGrid(origin, resolution, int(width), int(height), int(depth))
+	if err != nil {
+		return err
+	}
+	if err := binary.Read(r, binary.LittleEndian, decoded.cells); err != nil {
+		return err
+	}
+	*g = *decoded
+	return nil
+}
+
+func (g *OccupancyGrid) index(p r3.Vector) (int, bool) {
+	x := int(math.Floor((p.X - g.origin.X) / g.resolution))
+	y := int(math.Floor((p.Y - g.origin.Y) / g.resolution))
+	z := 0
+	if !g.Is2D() {
+		z = int(math.Floor((p.Z - g.origin.Z) / g.resolution))
+	}
+	if !g.inBounds(x, y, z) {
+		return 0, false
+	}
+	return (z*g.height+y)*g.width + x, true
+}
+
+func (g *OccupancyGrid) inBounds(x, y, z int) bool {
+	return x >= 0 && y >= 0 && z >= 0 && x < g.width && y < g.height && z < g.depth
+}
@@ -0,0 +1,81 @@
+package pointcloud
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func occupancyGridTestCloud(t *testing.T) PointCloud {
+	t.Helper()
+	pc := New()
+	test.That(t, pc.Set(NewVector(0, 0, 0), nil), test.ShouldBeNil)
+	test.That(t, pc.Set(NewVector(25, 5, 30), NewValueData(30)), test.ShouldBeNil)
+	test.That(t, pc.Set(NewVector(25, 5, 0), NewValueData(80)), test.ShouldBeNil)
+	return pc
+}
+
+func TestOccupancyGridFromPointCloud(t *testing.T) {
+	t.Run("3D", func(t *testing.T) {
+		grid, err := NewOccupancyGridFromPointCloud(occupancyGridTestCloud(t), 10, false)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, grid.Is2D(), test.ShouldBeFalse)
+		width, height, depth := grid.Dimensions()
+		test.That(t, []int{width, height, depth}, test.ShouldResemble, []int{3, 1, 4})
+		test.That(t, grid.At(0, 0, 0), test.ShouldEqual, OccupancyOccupied)
+		test.That(t, grid.At(2, 0, 3), test.ShouldEqual, int8(30))
+		test.That(t, grid.At(2, 0, 0), test.ShouldEqual, int8(80))
+		test.That(t, grid.At(1, 0, 0), test.ShouldEqual, OccupancyUnknown)
+		test.That(t, grid.Occupied(r3.Vector{X: 25, Y: 5, Z: 35}), test.ShouldBeFalse)
+		test.That(t, grid.Occupied(r3.Vector{X: 25, Y: 5, Z: 5}), test.ShouldBeTrue)
+		test.That(t, grid.Occupancy(r3.Vector{X: -5}), test.ShouldEqual, OccupancyUnknown)
+	})
+
+	t.Run("2D keeps the most likely point of each column", func(t *testing.T) {
+		grid, err := NewOccupancyGridFromPointCloud(occupancyGridTestCloud(t), 10, true)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, grid.Is2D(), test.ShouldBeTrue)
+		test.That(t, grid.At(2, 0, 0), test.ShouldEqual, int8(80))
+		test.That(t, grid.Occupied(r3.Vector{X: 25, Y: 5, Z: 1000}), test.ShouldBeTrue)
+		test.That(t, grid.CellCenter(2, 0, 0), test.ShouldResemble, r3.Vector{X: 25, Y: 5})
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		grid, err := NewOccupancyGridFromPointCloud(New(), 10, false)
+		test.That(t, err, test.ShouldBeNil)
+		width, height, depth := grid.Dimensions()
+		test.That(t, []int{width, height, depth}, test.ShouldResemble, []int{1, 1, 1})
+		test.That(t, grid.At(0, 0, 0), test.ShouldEqual, OccupancyUnknown)
+	})
+
+	_, err := NewOccupancyGridFromPointCloud(occupancyGridTestCloud(t), 0, false)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestOccupancyGridBinary(t *testing.T) {
+	grid, err := NewOccupancyGridFromPointCloud(occupancyGridTestCloud(t), 10, false)
+	test.That(t, err, test.ShouldBeNil)
+	data, err := grid.MarshalBinary()
+	test.That(t, err, test.ShouldBeNil)
+
+	var decoded OccupancyGrid
+	test.That(t, decoded.UnmarshalBinary(data), test.ShouldBeNil)
+	test.That(t, &decoded, test.ShouldResemble, grid)
+
+	test.That(t, decoded.UnmarshalBinary(data[:len(data)-1]), test.ShouldNotBeNil)
+	test.That(t, decoded.UnmarshalBinary([]byte("nope")), test.ShouldNotBeNil)
+}
+
+func TestOccupancyGridToGeometries(t *testing.T) {
+	grid, err := NewOccupancyGrid(r3.Vector{}, 10, 2, 2, 1)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grid.Set(1, 1, 0, OccupancyOccupied), test.ShouldBeNil)
+	test.That(t, grid.Set(0, 1, 0, OccupancyFree), test.ShouldBeNil)
+	test.That(t, grid.Set(2, 0, 0, OccupancyOccupied), test.ShouldNotBeNil)
+
+	geometries, err := grid.ToGeometries(500)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(geometries), test.ShouldEqual, 1)
+	test.That(t, geometries[0].Pose().Point(), test.ShouldResemble, r3.Vector{X: 15, Y: 15})
+}
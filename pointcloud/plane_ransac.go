@@ -0,0 +1,194 @@
+package pointcloud
+
+import (
+	"context"
+	"math"
+	"math/rand"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// PlaneRANSACOptions configure the search for planes in a point cloud with RANSAC.
+type PlaneRANSACOptions struct {
+	// Threshold is how far from a plane a point can be and still be a part of it, in mm. It must be positive.
+	Threshold float64
+	// Iterations is the number of candidate planes tried, 1000 if unset.
+	Iterations int
+	// MinPoints is the fewest points a plane can have, 3 if unset.
+	MinPoints int
+	// Normal, if set, limits the search to planes whose normal is within MaxAngleDegs of it, such as the up direction of
+	// the cloud when looking for the floor. The normals of the planes found then point the same way as it.
+	Normal       r3.Vector
+	MaxAngleDegs float64
+}
+
+func (opts *PlaneRANSACOptions) setDefaults() error {
+	if opts.Threshold <= 0 {
+		return errors.Errorf("plane threshold must be positive, got %v", opts.Threshold)
+	}
+	if opts.Iterations <= 0 {
+		opts.Iterations = 1000
+	}
+	if opts.MinPoints < 3 {
+		opts.MinPoints = 3
+	}
+	if opts.MaxAngleDegs < 0 || opts.MaxAngleDegs > 90 {
+		return errors.Errorf("plane normal max angle must be between 0 and 90 degrees, got %v", opts.MaxAngleDegs)
+	}
+	return nil
+}
+
+// planeCandidate is a plane with a unit normal and the number of points within the threshold of it.
+type planeCandidate struct {
+	normal  r3.Vector
+	offset  float64
+	inliers int
+}
+
+func (c planeCandidate) distance(p r3.Vector) float64 {
+	return math.Abs(c.normal.Dot(p) + c.offset)
+}
+
+// SegmentPlaneRANSAC finds the plane with the most points of the cloud with RANSAC, refits it to all of its points with
+// least squares, and returns it along with the points that are not a part of it. If no plane has at least
+// opts.MinPoints points, it returns an empty plane and the whole cloud.
+func SegmentPlaneRANSAC(ctx context.Context, cloud PointCloud, opts PlaneRANSACOptions) (Plane, PointCloud, error) {
+	if err := opts.setDefaults(); err != nil {
+		return nil, nil, err
+	}
+	points := make([]r3.Vector, 0, cloud.Size())
+	cloud.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		points = append(points, p)
+		return true
+	})
+	if len(points) < opts.MinPoints {
+		return NewEmptyPlane(), cloud, nil
+	}
+
+	up := opts.Normal.Normalize()
+	minCos := math.Cos(opts.MaxAngleDegs * math.Pi / 180)
+	// candidates are sampled up front so that the planes found do not depend on how the work is split between threads
+	//nolint:gosec
+	r := rand.New(rand.NewSource(1))
+	candidates := make([]planeCandidate, 0, opts.Iterations)
+	for i := 0; i < opts.Iterations; i++ {
+		p1, p2, p3 := points[r.Intn(len(points))], points[r.Intn(len(points))], points[r.Intn(len(points))]
+		normal := p2.Sub(p1).Cross(p3.Sub(p1))
+		if normal.Norm2() == 0 {
+			continue
+		}
+		normal = normal.Normalize()
+		if up.Norm2() != 0 {
+			if normal.Dot(up) < 0 {
+				normal = normal.Mul(-1)
+			}
+			if normal.Dot(up) < minCos {
+				continue
+			}
+		}
+		candidates = append(candidates, planeCandidate{normal: normal, offset: -normal.Dot(p1)})
+	}
+
+	parallelBatches(len(candidates), func(start, end int) {
+		for i := start; i < end && ctx.Err() == nil; i++ {
+			for _, p := range points {
+				if candidates[i].distance(p) < opts.Threshold {
+					candidates[i].inliers++
+				}
+			}
+		}
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	var best planeCandidate
+	for _, c := range candidates {
+		if c.inliers > best.inliers {
+			best = c
+		}
+	}
+	if best.inliers < opts.MinPoints {
+		return NewEmptyPlane(), cloud, nil
+	}
+
+	// refit the plane to all of its points, keeping it if the refit lost any of them
+	inliers := make([]r3.Vector, 0, best.inliers)
+	for _, p := range points {
+		if best.distance(p) < opts.Threshold {
+			inliers = append(inliers, p)
+		}
+	}
+	if refit := fitPlane(inliers, best.normal); refit.normal.Norm2() != 0 {
+		for _, p := range points {
+			if refit.distance(p) < opts.Threshold {
+				refit.inliers++
+			}
+		}
+		if refit.inliers >= best.inliers {
+			best = refit
+		}
+	}
+	return splitByPlane(cloud, best, opts.Threshold)
+}
+
+// SegmentPlanesRANSAC repeatedly finds the plane with the most points with SegmentPlaneRANSAC and removes it from the
+// cloud, until maxPlanes planes are found or there are no more planes of at least opts.MinPoints points. It returns
+// the planes, largest first, and the points that are in none of them.
+func SegmentPlanesRANSAC(ctx context.Context, cloud PointCloud, opts PlaneRANSACOptions, maxPlanes int) ([]Plane, PointCloud, error) {
+	var planes []Plane
+	for len(planes) < maxPlanes {
+		plane, rest, err := SegmentPlaneRANSAC(ctx, cloud, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		planeCloud, err := plane.PointCloud()
+		if err != nil {
+			return nil, nil, err
+		}
+		if planeCloud.Size() == 0 {
+			break
+		}
+		planes = append(planes, plane)
+		cloud = rest
+	}
+	return planes, cloud, nil
+}
+
+// fitPlane returns the least squares plane through the points, with its normal pointing the same way as towards.
+func fitPlane(points []r3.Vector, towards r3.Vector) planeCandidate {
+	if len(points) < 3 {
+		return planeCandidate{}
+	}
+	normal := estimatePlaneNormalFromPoints(points)
+	if normal.Dot(towards) < 0 {
+		normal = normal.Mul(-1)
+	}
+	return planeCandidate{normal: normal, offset: -normal.Dot(GetVoxelCenter(points))}
+}
+
+// splitByPlane returns the plane made of the points of the cloud within the threshold of the candidate, and the rest
+// of the cloud.
+func splitByPlane(cloud PointCloud, c planeCandidate, threshold float64) (Plane, PointCloud, error) {
+	planeCloud := NewWithPrealloc(c.inliers)
+	rest := NewWithPrealloc(cloud.Size() - c.inliers)
+	var center r3.Vector
+	var err error
+	cloud.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		if c.distance(p) < threshold {
+			center = center.Add(p)
+			err = planeCloud.Set(p, d)
+		} else {
+			err = rest.Set(p, d)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if planeCloud.Size() != 0 {
+		center = center.Mul(1 / float64(planeCloud.Size()))
+	}
+	equation := [4]float64{c.normal.X, c.normal.Y, c.normal.Z, c.offset}
+	return NewPlaneWithCenter(planeCloud, equation, center), rest, nil
+}
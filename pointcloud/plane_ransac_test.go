@@ -0,0 +1,83 @@
+package pointcloud
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+// floorAndWall returns a cloud of a floor at z = 0, a bigger wall at x = -50, and points floating between them.
+func floorAndWall(t *testing.T) PointCloud {
+	t.Helper()
+	cloud := New()
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 20; j++ {
+			test.That(t, cloud.Set(r3.Vector{float64(i * 10), float64(j * 10), 0}, nil), test.ShouldBeNil)
+		}
+	}
+	for i := 0; i < 25; i++ {
+		for j := 0; j < 25; j++ {
+			test.That(t, cloud.Set(r3.Vector{-50, float64(i * 10), float64(10 + j*10)}, nil), test.ShouldBeNil)
+		}
+	}
+	//nolint:gosec
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 30; i++ {
+		test.That(t, cloud.Set(r3.Vector{20 + 180*r.Float64(), 200 * r.Float64(), 50 + 100*r.Float64()}, nil), test.ShouldBeNil)
+	}
+	return cloud
+}
+
+func TestSegmentPlaneRANSAC(t *testing.T) {
+	ctx := context.Background()
+	cloud := floorAndWall(t)
+
+	_, _, err := SegmentPlaneRANSAC(ctx, cloud, PlaneRANSACOptions{})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	opts := PlaneRANSACOptions{Threshold: 1, MinPoints: 50}
+	wall, rest, err := SegmentPlaneRANSAC(ctx, cloud, opts)
+	test.That(t, err, test.ShouldBeNil)
+	wallCloud, err := wall.PointCloud()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, wallCloud.Size(), test.ShouldEqual, 625)
+	test.That(t, rest.Size(), test.ShouldEqual, 430)
+	test.That(t, wall.Normal().Cross(r3.Vector{X: 1}).Norm(), test.ShouldBeLessThan, 1e-6)
+	test.That(t, wall.Center().X, test.ShouldAlmostEqual, -50)
+
+	// the floor is found by the direction of its normal, which is then up
+	opts.Normal = r3.Vector{Z: 2}
+	opts.MaxAngleDegs = 10
+	floor, rest, err := SegmentPlaneRANSAC(ctx, cloud, opts)
+	test.That(t, err, test.ShouldBeNil)
+	floorCloud, err := floor.PointCloud()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, floorCloud.Size(), test.ShouldEqual, 400)
+	test.That(t, rest.Size(), test.ShouldEqual, 655)
+	test.That(t, floor.Normal().Sub(r3.Vector{Z: 1}).Norm(), test.ShouldBeLessThan, 1e-6)
+	test.That(t, floor.Offset(), test.ShouldAlmostEqual, 0)
+
+	opts.MaxAngleDegs = 100
+	_, _, err = SegmentPlaneRANSAC(ctx, cloud, opts)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// no plane is left among the floating points
+	opts = PlaneRANSACOptions{Threshold: 1, MinPoints: 50}
+	planes, rest, err := SegmentPlanesRANSAC(ctx, cloud, opts, 5)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, planes, test.ShouldHaveLength, 2)
+	test.That(t, rest.Size(), test.ShouldEqual, 30)
+
+	planes, rest, err = SegmentPlanesRANSAC(ctx, cloud, opts, 1)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, planes, test.ShouldHaveLength, 1)
+	test.That(t, rest.Size(), test.ShouldEqual, 430)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, err = SegmentPlaneRANSAC(cancelled, cloud, opts)
+	test.That(t, err, test.ShouldBeError, context.Canceled)
+}
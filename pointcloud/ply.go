@@ -0,0 +1,339 @@
+package pointcloud
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// PLYType is the format of a ply file.
+type PLYType int
+
+const (
+	// PLYAscii ascii format for ply.
+	PLYAscii PLYType = 0
+	// PLYBinary little endian binary format for ply.
+	PLYBinary PLYType = 1
+)
+
+// ToPLY writes out a point cloud as the vertices of a PLY file of the specified type, with their colors if the cloud
+// has any. Like PCD files, PLY files are written in meters.
+func ToPLY(cloud PointCloud, out io.Writer, outputType PLYType) error {
+	format := "ascii"
+	switch outputType {
+	case PLYAscii:
+	case PLYBinary:
+		format = "binary_little_endian"
+	default:
+		return errors.Errorf("unsupported ply type %d", outputType)
+	}
+	hasColor := cloud.MetaData().HasColor
+	w := bufio.NewWriter(out)
+	header := fmt.Sprintf("ply\nformat %s 1.0\nelement vertex %d\nproperty float x\nproperty float y\nproperty float z\n",
+		format, cloud.Size())
+	if hasColor {
+		header += "property uchar red\nproperty uchar green\nproperty uchar blue\n"
+	}
+	header += "end_header\n"
+	if _, err := w.WriteString(header); err != nil {
+		return err
+	}
+
+	var err error
+	buf := make([]byte, 15)
+	cloud.Iterate(0, 0, func(pos r3.Vector, d Data) bool {
+		// Converts RDK units (millimeters) to meters for PLY
+		x, y, z := pos.X/1000., pos.Y/1000., pos.Z/1000.
+		var r, g, b uint8
+		if hasColor && d != nil {
+			r, g, b = d.RGB255()
+		}
+		if outputType == PLYAscii {
+			if hasColor {
+				_, err = fmt.Fprintf(w, "%f %f %f %d %d %d\n", x, y, z, r, g, b)
+			} else {
+				_, err = fmt.Fprintf(w, "%f %f %f\n", x, y, z)
+			}
+			return err == nil
+		}
+		binary.LittleEndian.PutUint32(buf, math.Float32bits(float32(x)))
+		binary.LittleEndian.PutUint32(buf[4:], math.Float32bits(float32(y)))
+		binary.LittleEndian.PutUint32(buf[8:], math.Float32bits(float32(z)))
+		n := 12
+		if hasColor {
+			buf[12], buf[13], buf[14] = r, g, b
+			n = 15
+		}
+		_, err = w.Write(buf[:n])
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+type plyProperty struct {
+	name string
+	// valType is the type of the property, or of the items of a list property.
+	valType string
+	// countType is the type of the length of a list property, and empty for other properties.
+	countType string
+}
+
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+type plyHeader struct {
+	// order is the byte order of binary files, and nil for ascii ones.
+	order    binary.ByteOrder
+	elements []plyElement
+}
+
+// plyTypeSizes are the sizes in bytes of the types of ply properties, by both of their names.
+var plyTypeSizes = map[string]int{
+	"char": 1, "uchar": 1, "short": 2, "ushort": 2, "int": 4, "uint": 4, "float": 4, "double": 8,
+	"int8": 1, "uint8": 1, "int16": 2, "uint16": 2, "int32": 4, "uint32": 4, "float32": 4, "float64": 8,
+}
+
+func parsePLYHeader(in *bufio.Reader) (*plyHeader, error) {
+	line, err := in.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("error reading ply header: %w", err)
+	}
+	if strings.TrimSpace(line) != "ply" {
+		return nil, errors.New("file does not start with ply")
+	}
+	header := &plyHeader{}
+	formatSeen := false
+	for {
+		line, err := in.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("error reading ply header: %w", err)
+		}
+		tokens := strings.Fields(line)
+		if len(tokens) == 0 {
+			continue
+		}
+		switch tokens[0] {
+		case "comment", "obj_info":
+		case "format":
+			if len(tokens) != 3 {
+				return nil, fmt.Errorf("invalid ply format line %q", strings.TrimSpace(line))
+			}
+			switch tokens[1] {
+			case "ascii":
+			case "binary_little_endian":
+				header.order = binary.LittleEndian
+			case "binary_big_endian":
+				header.order = binary.BigEndian
+			default:
+				return nil, fmt.Errorf("unsupported ply format %s", tokens[1])
+			}
+			formatSeen = true
+		case "element":
+			if len(tokens) != 3 {
+				return nil, fmt.Errorf("invalid ply element line %q", strings.TrimSpace(line))
+			}
+			count, err := strconv.Atoi(tokens[2])
+			if err != nil || count < 0 {
+				return nil, fmt.Errorf("invalid count of ply element %s: %s", tokens[1], tokens[2])
+			}
+			header.elements = append(header.elements, plyElement{name: tokens[1], count: count})
+		case "property":
+			if len(header.elements) == 0 {
+				return nil, errors.New("ply property is not part of an element")
+			}
+			var prop plyProperty
+			switch {
+			case len(tokens) == 3:
+				prop = plyProperty{name: tokens[2], valType: tokens[1]}
+			case len(tokens) == 5 && tokens[1] == "list":
+				prop = plyProperty{name: tokens[4], valType: tokens[3], countType: tokens[2]}
+				if _, ok := plyTypeSizes[prop.countType]; !ok {
+					return nil, fmt.Errorf("unsupported ply property type %s", prop.countType)
+				}
+			default:
+				return nil, fmt.Errorf("invalid ply property line %q", strings.TrimSpace(line))
+			}
+			if _, ok := plyTypeSizes[prop.valType]; !ok {
+				return nil, fmt.Errorf("unsupported ply property type %s", prop.valType)
+			}
+			element := &header.elements[len(header.elements)-1]
+			element.properties = append(element.properties, prop)
+		case "end_header":
+			if !formatSeen {
+				return nil, errors.New("ply header has no format")
+			}
+			return header, nil
+		default:
+			return nil, fmt.Errorf("unexpected ply header line %q", strings.TrimSpace(line))
+		}
+	}
+}
+
+// ReadPLY reads the vertices of a PLY file into a pointcloud, with their colors if they have any. Like PCD files,
+// PLY files are read in meters. Other elements, such as the faces of meshes, are skipped.
+func ReadPLY(inRaw io.Reader) (PointCloud, error) {
+	in := bufio.NewReader(inRaw)
+	header, err := parsePLYHeader(in)
+	if err != nil {
+		return nil, err
+	}
+	for _, element := range header.elements {
+		if element.name != "vertex" {
+			if err := skipPLYElement(in, header, element); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return readPLYVertices(in, header, element)
+	}
+	return nil, errors.New("ply file has no vertex element")
+}
+
+func readPLYVertices(in *bufio.Reader, header *plyHeader, element plyElement) (PointCloud, error) {
+	indices := map[string]int{}
+	for i, prop := range element.properties {
+		if prop.countType == "" {
+			indices[prop.name] = i
+		}
+	}
+	for _, name := range []string{"x", "y", "z"} {
+		if _, ok := indices[name]; !ok {
+			return nil, fmt.Errorf("ply vertices have no %s property", name)
+		}
+	}
+	_, hasRed := indices["red"]
+	_, hasGreen := indices["green"]
+	_, hasBlue := indices["blue"]
+	hasColor := hasRed && hasGreen && hasBlue
+	colorChannel := func(values []float64, name string) uint8 {
+		v := values[indices[name]]
+		if t := element.properties[indices[name]].valType; t == "float" || t == "float32" || t == "double" || t == "float64" {
+			v *= 255
+		}
+		return uint8(math.Max(0, math.Min(255, math.Round(v))))
+	}
+
+	pc := NewWithPrealloc(element.count)
+	for i := 0; i < element.count; i++ {
+		values, err := readPLYElementValues(in, header, element)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ply vertex %d: %w", i, err)
+		}
+		// Converts PLY units (meters) to millimeters for RDK
+		point := r3.Vector{X: 1000. * values[indices["x"]], Y: 1000. * values[indices["y"]], Z: 1000. * values[indices["z"]]}
+		d := NewBasicData()
+		if hasColor {
+			d = NewColoredData(color.NRGBA{colorChannel(values, "red"), colorChannel(values, "green"), colorChannel(values, "blue"), 255})
+		}
+		if err := pc.Set(point, d); err != nil {
+			return nil, err
+		}
+	}
+	return pc, nil
+}
+
+func skipPLYElement(in *bufio.Reader, header *plyHeader, element plyElement) error {
+	for i := 0; i < element.count; i++ {
+		if _, err := readPLYElementValues(in, header, element); err != nil {
+			return fmt.Errorf("error reading ply %s %d: %w", element.name, i, err)
+		}
+	}
+	return nil
+}
+
+// readPLYElementValues reads one instance of an element, returning the values of its properties in order. List
+// properties are read past and left as 0.
+func readPLYElementValues(in *bufio.Reader, header *plyHeader, element plyElement) ([]float64, error) {
+	values := make([]float64, len(element.properties))
+	if header.order == nil {
+		line, err := in.ReadString('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+			return nil, err
+		}
+		tokens := strings.Fields(line)
+		next := func() (float64, error) {
+			if len(tokens) == 0 {
+				return 0, errors.New("too few values")
+			}
+			v, err := strconv.ParseFloat(tokens[0], 64)
+			tokens = tokens[1:]
+			return v, err
+		}
+		for i, prop := range element.properties {
+			v, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if prop.countType == "" {
+				values[i] = v
+				continue
+			}
+			for j := 0; j < int(v); j++ {
+				if _, err := next(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return values, nil
+	}
+
+	for i, prop := range element.properties {
+		if prop.countType == "" {
+			v, err := readPLYBinaryValue(in, header.order, prop.valType)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+			continue
+		}
+		count, err := readPLYBinaryValue(in, header.order, prop.countType)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := in.Discard(int(count) * plyTypeSizes[prop.valType]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func readPLYBinaryValue(in *bufio.Reader, order binary.ByteOrder, valType string) (float64, error) {
+	buf := make([]byte, plyTypeSizes[valType])
+	if _, err := io.ReadFull(in, buf); err != nil {
+		return 0, err
+	}
+	switch valType {
+	case "char", "int8":
+		return float64(int8(buf[0])), nil
+	case "uchar", "uint8":
+		return float64(buf[0]), nil
+	case "short", "int16":
+		return float64(int16(order.Uint16(buf))), nil
+	case "ushort", "uint16":
+		return float64(order.Uint16(buf)), nil
+	case "int", "int32":
+		return float64(int32(order.Uint32(buf))), nil
+	case "uint", "uint32":
+		return float64(order.Uint32(buf)), nil
+	case "float", "float32":
+		return readFloat(order.Uint32(buf)), nil
+	case "double", "float64":
+		return math.Float64frombits(order.Uint64(buf)), nil
+	default:
+		return 0, fmt.Errorf("unsupported ply property type %s", valType)
+	}
+}
@@ -0,0 +1,105 @@
+package pointcloud
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+)
+
+func TestPLYRoundTrip(t *testing.T) {
+	colored := New()
+	test.That(t, colored.Set(NewVector(-1, -2, 5), NewColoredData(color.NRGBA{255, 1, 2, 255})), test.ShouldBeNil)
+	test.That(t, colored.Set(NewVector(582, 12, 0), NewColoredData(color.NRGBA{0, 10, 20, 255})), test.ShouldBeNil)
+	plain := New()
+	test.That(t, plain.Set(NewVector(-1, -2, 5), NewBasicData()), test.ShouldBeNil)
+	test.That(t, plain.Set(NewVector(582, 12, 0), NewBasicData()), test.ShouldBeNil)
+
+	for _, plyType := range []PLYType{PLYAscii, PLYBinary} {
+		for _, cloud := range []PointCloud{colored, plain} {
+			var buf bytes.Buffer
+			test.That(t, ToPLY(cloud, &buf, plyType), test.ShouldBeNil)
+			test.That(t, buf.String(), test.ShouldContainSubstring, "element vertex 2\n")
+
+			cloud2, err := ReadPLY(&buf)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, cloud2.Size(), test.ShouldEqual, 2)
+			data, ok := cloud2.At(-1, -2, 5)
+			test.That(t, ok, test.ShouldBeTrue)
+			test.That(t, data.HasColor(), test.ShouldEqual, cloud.MetaData().HasColor)
+			if cloud.MetaData().HasColor {
+				r, g, b := data.RGB255()
+				test.That(t, []uint8{r, g, b}, test.ShouldResemble, []uint8{255, 1, 2})
+			}
+		}
+	}
+
+	// clouds can be read by their extension
+	fn := filepath.Join(t.TempDir(), "cloud.ply")
+	var buf bytes.Buffer
+	test.That(t, ToPLY(colored, &buf, PLYBinary), test.ShouldBeNil)
+	test.That(t, os.WriteFile(fn, buf.Bytes(), 0o600), test.ShouldBeNil)
+	cloud, err := NewFromFile(fn, golog.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cloud.Size(), test.ShouldEqual, 2)
+}
+
+func TestReadPLYMesh(t *testing.T) {
+	// a mesh with its faces before its vertices, as some tools write them, and float colors
+	ascii := "ply\n" +
+		"format ascii 1.0\n" +
+		"comment made by hand\n" +
+		"element face 1\n" +
+		"property list uchar int vertex_indices\n" +
+		"element vertex 3\n" +
+		"property double x\n" +
+		"property double y\n" +
+		"property double z\n" +
+		"property float red\n" +
+		"property float green\n" +
+		"property float blue\n" +
+		"end_header\n" +
+		"3 0 1 2\n" +
+		"0 0 0 1 0 0\n" +
+		"0.001 0 0 0 1 0\n" +
+		"0 0.002 0 0 0 1\n"
+	cloud, err := ReadPLY(strings.NewReader(ascii))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cloud.Size(), test.ShouldEqual, 3)
+	data, ok := cloud.At(0, 2, 0)
+	test.That(t, ok, test.ShouldBeTrue)
+	r, g, b := data.RGB255()
+	test.That(t, []uint8{r, g, b}, test.ShouldResemble, []uint8{0, 0, 255})
+
+	var bin bytes.Buffer
+	bin.WriteString("ply\nformat binary_big_endian 1.0\n" +
+		"element face 1\nproperty list uchar int vertex_indices\n" +
+		"element vertex 1\nproperty float x\nproperty float y\nproperty float z\nproperty int confidence\n" +
+		"end_header\n")
+	bin.Write([]byte{2, 0, 0, 0, 0, 0, 0, 0, 1})
+	for _, v := range []float32{0.5, -0.25, 1} {
+		test.That(t, binary.Write(&bin, binary.BigEndian, math.Float32bits(v)), test.ShouldBeNil)
+	}
+	test.That(t, binary.Write(&bin, binary.BigEndian, int32(-7)), test.ShouldBeNil)
+	cloud, err = ReadPLY(&bin)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, CloudContains(cloud, 500, -250, 1000), test.ShouldBeTrue)
+
+	for _, bad := range []string{
+		"pcd\n",
+		"ply\nformat binary_middle_endian 1.0\nend_header\n",
+		"ply\nformat ascii 1.0\nelement face 0\nend_header\n",
+		"ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\nproperty float y\nend_header\n0 0\n",
+		"ply\nformat ascii 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nend_header\n0 0 0\n",
+	} {
+		_, err := ReadPLY(strings.NewReader(bad))
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"image/color"
 	"io"
 	"math"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -40,11 +41,24 @@ func NewFromFile(fn string, logger golog.Logger) (PointCloud, error) {
 	switch filepath.Ext(fn) {
 	case ".las":
 		return NewFromLASFile(fn, logger)
+	case ".pcd":
+		return readFile(fn, ReadPCD)
+	case ".ply":
+		return readFile(fn, ReadPLY)
 	default:
 		return nil, errors.Errorf("do not know how to read file %q", fn)
 	}
 }
 
+func readFile(fn string, read func(io.Reader) (PointCloud, error)) (PointCloud, error) {
+	f, err := os.Open(fn) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer utils.UncheckedErrorFunc(f.Close)
+	return read(f)
+}
+
 // pointValueDataTag encodes if the point has value data.
 const pointValueDataTag = "rc|pv"
 
@@ -285,11 +299,11 @@ func ToPCD(cloud PointCloud, out io.Writer, outputType PCDType) error {
 			return err
 		}
 	case PCDCompressed:
-		// _, err = fmt.Fprintf(out, "DATA binary_compressed\n")
-		// if err != nil {
-		// 	return err
-		// }
-		return errors.New("compressed PCD not yet implemented")
+		_, err = fmt.Fprintf(out, "DATA binary_compressed\n")
+		if err != nil {
+			return err
+		}
+		return writePCDCompressedData(cloud, out)
 	}
 	err = writePCDData(cloud, out, outputType)
 	if err != nil {
@@ -317,8 +331,6 @@ func writePCDData(cloud PointCloud, out io.Writer, pcdtype PCDType) error {
 				_, err = out.Write(buf)
 			case PCDAscii:
 				_, err = fmt.Fprintf(out, "%f %f %f %d\n", x, y, z, c)
-			case PCDCompressed:
-				return false // TODO(aidanglickman): Implement compressed PCD
 			default:
 				return false
 			}
@@ -332,8 +344,6 @@ func writePCDData(cloud PointCloud, out io.Writer, pcdtype PCDType) error {
 				_, err = out.Write(buf)
 			case PCDAscii:
 				_, err = fmt.Fprintf(out, "%f %f %f\n", x, y, z)
-			case PCDCompressed:
-				return false // TODO(aidanglickman): Implement compressed PCD
 			default:
 				return false
 			}
@@ -343,6 +353,39 @@ func writePCDData(cloud PointCloud, out io.Writer, pcdtype PCDType) error {
 	return nil
 }
 
+// writePCDCompressedData writes the points of the cloud in the layout of binary_compressed PCD files, which is the
+// sizes of the compressed and uncompressed data followed by the LZF compressed values of each field of all the
+// points, one field after another.
+func writePCDCompressedData(cloud PointCloud, out io.Writer) error {
+	numFields := 3
+	if cloud.MetaData().HasColor {
+		numFields = 4
+	}
+	n := cloud.Size()
+	data := make([]byte, 4*numFields*n)
+	i := 0
+	cloud.Iterate(0, 0, func(pos r3.Vector, d Data) bool {
+		// Converts RDK units (millimeters) to meters for PCD
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(float32(pos.X/1000.)))
+		binary.LittleEndian.PutUint32(data[4*(n+i):], math.Float32bits(float32(pos.Y/1000.)))
+		binary.LittleEndian.PutUint32(data[4*(2*n+i):], math.Float32bits(float32(pos.Z/1000.)))
+		if numFields == 4 {
+			binary.LittleEndian.PutUint32(data[4*(3*n+i):], uint32(_colorToPCDInt(d)))
+		}
+		i++
+		return i < n
+	})
+	compressed := lzfCompress(data)
+	sizes := make([]byte, 8)
+	binary.LittleEndian.PutUint32(sizes, uint32(len(compressed)))
+	binary.LittleEndian.PutUint32(sizes[4:], uint32(len(data)))
+	if _, err := out.Write(sizes); err != nil {
+		return err
+	}
+	_, err := out.Write(compressed)
+	return err
+}
+
 func readFloat(n uint32) float64 {
 	f := float64(math.Float32frombits(n))
 	return math.Round(f*10000) / 10000
@@ -577,8 +620,7 @@ func readPCDHelper(inRaw io.Reader, pctype PCType) (PointCloud, error) {
 	case PCDBinary:
 		return readPCDBinary(in, *header, pc)
 	case PCDCompressed:
-		// return readPCDCompressed(in, header)
-		return nil, errors.New("compressed pcd not yet supported")
+		return readPCDCompressed(in, *header, pc)
 	default:
 		return nil, fmt.Errorf("unsupported pcd data type %v", header.data)
 	}
@@ -670,6 +712,61 @@ func readPCDBinary(in *bufio.Reader, header pcdHeader, pc PointCloud) (PointClou
 	return pc, nil
 }
 
+func extractPCDPointsCompressed(in *bufio.Reader, header pcdHeader) ([]PointAndData, error) {
+	for _, size := range header.size {
+		if size != 4 {
+			return nil, fmt.Errorf("unsupported binary_compressed field size %d", size)
+		}
+	}
+	sizes := make([]byte, 8)
+	if _, err := io.ReadFull(in, sizes); err != nil {
+		return nil, err
+	}
+	compressedSize := binary.LittleEndian.Uint32(sizes)
+	uncompressedSize := binary.LittleEndian.Uint32(sizes[4:])
+	if uint64(uncompressedSize) != 4*uint64(header.fields)*header.points {
+		return nil, fmt.Errorf("binary_compressed data of %d bytes does not hold %d points", uncompressedSize, header.points)
+	}
+	compressed := make([]byte, compressedSize)
+	if _, err := io.ReadFull(in, compressed); err != nil {
+		return nil, err
+	}
+	data, err := lzfDecompress(compressed, int(uncompressedSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// the values of each field are stored one after another
+	n := int(header.points)
+	field := func(j, i int) uint32 {
+		return binary.LittleEndian.Uint32(data[4*(j*n+i):])
+	}
+	points := make([]PointAndData, 0, n)
+	for i := 0; i < n; i++ {
+		// Converts PCD units (meters) to millimeters for RDK
+		point := r3.Vector{X: 1000. * readFloat(field(0, i)), Y: 1000. * readFloat(field(1, i)), Z: 1000. * readFloat(field(2, i))}
+		d := NewBasicData()
+		if header.fields == pcdPointColor {
+			d = NewColoredData(_pcdIntToColor(int(field(3, i))))
+		}
+		points = append(points, PointAndData{P: point, D: d})
+	}
+	return points, nil
+}
+
+func readPCDCompressed(in *bufio.Reader, header pcdHeader, pc PointCloud) (PointCloud, error) {
+	points, err := extractPCDPointsCompressed(in, header)
+	if err != nil {
+		return nil, err
+	}
+	for _, pd := range points {
+		if err := pc.Set(pd.P, pd.D); err != nil {
+			return nil, err
+		}
+	}
+	return pc, nil
+}
+
 func parsePCDMetaData(in bufio.Reader, header pcdHeader) (MetaData, error) {
 	meta := NewMetaData()
 	switch header.data {
@@ -691,8 +788,13 @@ func parsePCDMetaData(in bufio.Reader, header pcdHeader) (MetaData, error) {
 			meta.Merge(pd.P, pd.D)
 		}
 	case PCDCompressed:
-		// return readPCDCompressed(in, header)
-		return MetaData{}, errors.New("compressed pcd not yet supported")
+		points, err := extractPCDPointsCompressed(&in, header)
+		if err != nil {
+			return MetaData{}, err
+		}
+		for _, pd := range points {
+			meta.Merge(pd.P, pd.D)
+		}
 	default:
 		return MetaData{}, fmt.Errorf("unsupported pcd data type %v", header.data)
 	}
@@ -66,6 +66,7 @@ func TestPCD(t *testing.T) {
 	testPCDHeaders(t)
 	testASCIIRoundTrip(t, cloud)
 	testBinaryRoundTrip(t, cloud)
+	testCompressedRoundTrip(t, cloud)
 }
 
 func testPCDHeaders(t *testing.T) {
@@ -158,6 +159,7 @@ func TestPCDNoColor(t *testing.T) {
 
 	testNoColorASCIIRoundTrip(t, cloud)
 	testNoColorBinaryRoundTrip(t, cloud)
+	testCompressedRoundTrip(t, cloud)
 	testLargeBinaryNoError(t)
 }
 
@@ -254,6 +256,34 @@ func testBinaryRoundTrip(t *testing.T, cloud PointCloud) {
 	test.That(t, b, test.ShouldEqual, 2)
 }
 
+func testCompressedRoundTrip(t *testing.T, cloud PointCloud) {
+	t.Helper()
+	var buf bytes.Buffer
+	err := ToPCD(cloud, &buf, PCDCompressed)
+	test.That(t, err, test.ShouldBeNil)
+	gotPCD := buf.String()
+	test.That(t, gotPCD, test.ShouldContainSubstring, "POINTS 3\n")
+	test.That(t, gotPCD, test.ShouldContainSubstring, "DATA binary_compressed\n")
+
+	cloud2, err := ReadPCD(strings.NewReader(gotPCD))
+	test.That(t, err, test.ShouldBeNil)
+	testPCDOutput(t, cloud2)
+	data, dataFlag := cloud2.At(-1, -2, 5)
+	test.That(t, dataFlag, test.ShouldBeTrue)
+	test.That(t, data.HasColor(), test.ShouldEqual, cloud.MetaData().HasColor)
+	if cloud.MetaData().HasColor {
+		r, g, b := data.RGB255()
+		test.That(t, []uint8{r, g, b}, test.ShouldResemble, []uint8{255, 1, 2})
+	}
+
+	meta, err := GetPCDMetaData(strings.NewReader(gotPCD))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, meta.MaxX, test.ShouldAlmostEqual, 582)
+
+	_, err = ReadPCD(strings.NewReader(gotPCD[:len(gotPCD)-2]))
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 func testLargeBinaryNoError(t *testing.T) {
 	// This tests whether large pointclouds that exceed the usual buffered page size for a file error on reads
 	t.Helper()
@@ -2,9 +2,11 @@ package pointcloud
 
 import (
 	"math"
+	"sync"
 
 	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
+	"go.viam.com/utils"
 	"gonum.org/v1/gonum/stat"
 
 	"go.viam.com/rdk/spatialmath"
@@ -64,22 +66,15 @@ func StatisticalOutlierFilter(meanK int, stdDevThresh float64) (func(PointCloud)
 	}
 	filterFunc := func(pc PointCloud) (PointCloud, error) {
 		// create data type that can do nearest neighbors
-		kd, ok := pc.(*KDTree)
-		if !ok {
-			kd = ToKDTree(pc)
-		}
+		kd := ToKDTree(pc)
 		// get the statistical information
-		avgDistances := make([]float64, 0, kd.Size())
-		points := make([]PointAndData, 0, kd.Size())
-		kd.Iterate(0, 0, func(v r3.Vector, d Data) bool {
+		points, avgDistances := mapPoints(kd, func(v r3.Vector) float64 {
 			neighbors := kd.KNearestNeighbors(v, meanK, false)
 			sumDist := 0.0
 			for _, p := range neighbors {
 				sumDist += v.Distance(p.P)
 			}
-			avgDistances = append(avgDistances, sumDist/float64(len(neighbors)))
-			points = append(points, PointAndData{v, d})
-			return true
+			return sumDist / float64(len(neighbors))
 		})
 
 		mean, stddev := stat.MeanStdDev(avgDistances, nil)
@@ -98,3 +93,67 @@ func StatisticalOutlierFilter(meanK int, stdDevThresh float64) (func(PointCloud)
 	}
 	return filterFunc, nil
 }
+
+// RadiusOutlierFilter returns a filter that removes the points of a point cloud that have fewer than minNeighbors
+// other points within the given radius of them.
+// https://pcl.readthedocs.io/projects/tutorials/en/latest/remove_outliers.html
+func RadiusOutlierFilter(radius float64, minNeighbors int) (func(PointCloud) (PointCloud, error), error) {
+	if radius <= 0.0 {
+		return nil, errors.Errorf("argument radius must be a positive float, got %.2f", radius)
+	}
+	if minNeighbors <= 0 {
+		return nil, errors.Errorf("argument minNeighbors must be a positive int, got %d", minNeighbors)
+	}
+	filterFunc := func(pc PointCloud) (PointCloud, error) {
+		kd := ToKDTree(pc)
+		points, neighborCounts := mapPoints(kd, func(v r3.Vector) float64 {
+			return float64(len(kd.RadiusNearestNeighbors(v, radius, false)))
+		})
+		filteredCloud := New()
+		for i, count := range neighborCounts {
+			if int(count) >= minNeighbors {
+				if err := filteredCloud.Set(points[i].P, points[i].D); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return filteredCloud, nil
+	}
+	return filterFunc, nil
+}
+
+// mapPoints calls f on every point of the tree, spread over numThreadsPointCloud goroutines as nearest neighbor
+// queries do not change the tree, and returns the points along with the result for each of them.
+func mapPoints(kd *KDTree, f func(v r3.Vector) float64) ([]PointAndData, []float64) {
+	points := make([]PointAndData, 0, kd.Size())
+	kd.Iterate(0, 0, func(v r3.Vector, d Data) bool {
+		points = append(points, PointAndData{v, d})
+		return true
+	})
+	results := make([]float64, len(points))
+	parallelBatches(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			results[i] = f(points[i].P)
+		}
+	})
+	return points, results
+}
+
+// parallelBatches splits the indices [0, n) into numThreadsPointCloud batches and calls f on each of them
+// concurrently, returning once all of them have.
+func parallelBatches(n int, f func(start, end int)) {
+	batchSize := (n + numThreadsPointCloud - 1) / numThreadsPointCloud
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += batchSize {
+		start, end := start, start+batchSize
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		utils.PanicCapturingGo(func() {
+			defer wg.Done()
+			f(start, end)
+		})
+	}
+	wg.Wait()
+}
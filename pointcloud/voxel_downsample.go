@@ -0,0 +1,169 @@
+package pointcloud
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// voxelKey is the integer coordinates of a cube of a voxel grid.
+type voxelKey struct {
+	x, y, z int64
+}
+
+// newVoxelKey returns the key of the voxel of the given size that the point falls in.
+func newVoxelKey(p r3.Vector, size float64) voxelKey {
+	return voxelKey{int64(math.Floor(p.X / size)), int64(math.Floor(p.Y / size)), int64(math.Floor(p.Z / size))}
+}
+
+// voxelSum accumulates the points that fall in one voxel.
+type voxelSum struct {
+	n         int
+	position  r3.Vector
+	colored   int
+	r, g, b   int
+	intensity int
+	value     Data
+}
+
+// add adds a point to the voxel.
+func (sum *voxelSum) add(p r3.Vector, d Data) {
+	sum.n++
+	sum.position = sum.position.Add(p)
+	if d == nil {
+		return
+	}
+	if d.HasColor() {
+		r, g, b := d.RGB255()
+		sum.r += int(r)
+		sum.g += int(g)
+		sum.b += int(b)
+		sum.colored++
+	}
+	sum.intensity += int(d.Intensity())
+	if sum.value == nil && d.HasValue() {
+		sum.value = d
+	}
+}
+
+// point returns the centroid of the points added to the voxel, with their average color and intensity.
+func (sum *voxelSum) point() (r3.Vector, Data) {
+	d := NewBasicData()
+	if sum.colored > 0 {
+		d.SetColor(color.NRGBA{
+			R: uint8(sum.r / sum.colored),
+			G: uint8(sum.g / sum.colored),
+			B: uint8(sum.b / sum.colored),
+			A: 255,
+		})
+	}
+	if sum.value != nil {
+		d.SetValue(sum.value.Value())
+	}
+	d.SetIntensity(uint16(sum.intensity / sum.n))
+	n := float64(sum.n)
+	return r3.Vector{X: sum.position.X / n, Y: sum.position.Y / n, Z: sum.position.Z / n}, d
+}
+
+// VoxelDownsampler reduces a cloud to one point per occupied cube of a voxel grid, at the centroid of the points
+// in the cube and with their average color and intensity. The value of the first point with one is kept. Points
+// are added one at a time and only a running sum per occupied cube is kept, so clouds can be reduced as they are
+// read without holding all of their points.
+type VoxelDownsampler struct {
+	voxelSize float64
+	voxels    map[voxelKey]*voxelSum
+}
+
+// NewVoxelDownsampler returns a VoxelDownsampler for cubes with sides of the given length.
+func NewVoxelDownsampler(voxelSize float64) (*VoxelDownsampler, error) {
+	if voxelSize <= 0 || math.IsInf(voxelSize, 0) || math.IsNaN(voxelSize) {
+		return nil, errors.Errorf("argument voxelSize must be a positive float, got %.2f", voxelSize)
+	}
+	return &VoxelDownsampler{voxelSize: voxelSize, voxels: map[voxelKey]*voxelSum{}}, nil
+}
+
+// Add adds a point to the voxel it falls in.
+func (vd *VoxelDownsampler) Add(p r3.Vector, d Data) {
+	key := newVoxelKey(p, vd.voxelSize)
+	sum, ok := vd.voxels[key]
+	if !ok {
+		sum = &voxelSum{}
+		vd.voxels[key] = sum
+	}
+	sum.add(p, d)
+}
+
+// Size returns the number of occupied voxels, which is the size of the downsampled cloud.
+func (vd *VoxelDownsampler) Size() int {
+	return len(vd.voxels)
+}
+
+// PointCloud returns the downsampled cloud of the points added so far.
+func (vd *VoxelDownsampler) PointCloud() (PointCloud, error) {
+	cloud := NewWithPrealloc(len(vd.voxels))
+	for _, sum := range vd.voxels {
+		if err := cloud.Set(sum.point()); err != nil {
+			return nil, err
+		}
+	}
+	return cloud, nil
+}
+
+// VoxelGridDownsample returns a filter that reduces a point cloud to one point per occupied cube of a voxel grid
+// with cubes of the given size, as VoxelDownsampler does.
+func VoxelGridDownsample(voxelSize float64) (func(PointCloud) (PointCloud, error), error) {
+	if _, err := NewVoxelDownsampler(voxelSize); err != nil {
+		return nil, err
+	}
+	filterFunc := func(pc PointCloud) (PointCloud, error) {
+		vd, err := NewVoxelDownsampler(voxelSize)
+		if err != nil {
+			return nil, err
+		}
+		pc.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+			vd.Add(p, d)
+			return true
+		})
+		return vd.PointCloud()
+	}
+	return filterFunc, nil
+}
+
+// coarseToFineLevels is the number of ever finer voxel grids CoarseToFine picks points from before adding the rest.
+const coarseToFineLevels = 10
+
+// CoarseToFine returns the points of a cloud ordered so that the points of any prefix of them are spread evenly over
+// the cloud, which lets the first points of a cloud that is sent in parts be shown as a coarse preview of it. It
+// picks one point from each occupied voxel of a grid of 8 voxels across the cloud, then one from each voxel of a grid
+// twice as fine of the points left, and so on, before adding the points left at the end.
+func CoarseToFine(pc PointCloud) []PointAndData {
+	remaining := make([]PointAndData, 0, pc.Size())
+	pc.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		remaining = append(remaining, PointAndData{P: p, D: d})
+		return true
+	})
+	if len(remaining) == 0 {
+		return remaining
+	}
+	meta := pc.MetaData()
+	voxelSize := math.Max(meta.MaxX-meta.MinX, math.Max(meta.MaxY-meta.MinY, meta.MaxZ-meta.MinZ)) / 8
+	ordered := make([]PointAndData, 0, len(remaining))
+	for level := 0; level < coarseToFineLevels && voxelSize > 0 && len(remaining) > 0; level++ {
+		occupied := map[voxelKey]bool{}
+		rest := make([]PointAndData, 0, len(remaining))
+		for _, pd := range remaining {
+			key := newVoxelKey(pd.P, voxelSize)
+			if occupied[key] {
+				rest = append(rest, pd)
+				continue
+			}
+			occupied[key] = true
+			ordered = append(ordered, pd)
+		}
+		remaining = rest
+		voxelSize /= 2
+	}
+	return append(ordered, remaining...)
+}
@@ -0,0 +1,73 @@
+package pointcloud
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestVoxelGridDownsample(t *testing.T) {
+	_, err := VoxelGridDownsample(0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cloud := New()
+	test.That(t, cloud.Set(r3.Vector{1, 1, 1}, NewColoredData(color.NRGBA{100, 0, 0, 255})), test.ShouldBeNil)
+	test.That(t, cloud.Set(r3.Vector{3, 3, 3}, NewColoredData(color.NRGBA{200, 50, 0, 255})), test.ShouldBeNil)
+	test.That(t, cloud.Set(r3.Vector{9, 9, 9}, NewValueData(5)), test.ShouldBeNil)
+	test.That(t, cloud.Set(r3.Vector{-1, 1, 1}, nil), test.ShouldBeNil)
+
+	filter, err := VoxelGridDownsample(4)
+	test.That(t, err, test.ShouldBeNil)
+	downsampled, err := filter(cloud)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, downsampled.Size(), test.ShouldEqual, 3)
+
+	d, ok := downsampled.At(2, 2, 2)
+	test.That(t, ok, test.ShouldBeTrue)
+	r, g, b := d.RGB255()
+	test.That(t, []uint8{r, g, b}, test.ShouldResemble, []uint8{150, 25, 0})
+	d, ok = downsampled.At(9, 9, 9)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, d.Value(), test.ShouldEqual, 5)
+	test.That(t, d.HasColor(), test.ShouldBeFalse)
+	test.That(t, CloudContains(downsampled, -1, 1, 1), test.ShouldBeTrue)
+
+	// points can be added as they are read
+	vd, err := NewVoxelDownsampler(4)
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 1000; i++ {
+		vd.Add(r3.Vector{float64(i % 10), 0, 0}, nil)
+	}
+	test.That(t, vd.Size(), test.ShouldEqual, 3)
+	downsampled, err = vd.PointCloud()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, CloudContains(downsampled, 1.5, 0, 0), test.ShouldBeTrue)
+	test.That(t, CloudContains(downsampled, 8.5, 0, 0), test.ShouldBeTrue)
+}
+
+func TestCoarseToFine(t *testing.T) {
+	cloud := New()
+	for x := 0.; x < 64; x++ {
+		for y := 0.; y < 64; y++ {
+			test.That(t, cloud.Set(r3.Vector{x, y, 0}, nil), test.ShouldBeNil)
+		}
+	}
+	ordered := CoarseToFine(cloud)
+	test.That(t, ordered, test.ShouldHaveLength, cloud.Size())
+	seen := map[r3.Vector]bool{}
+	for _, pd := range ordered {
+		seen[pd.P] = true
+	}
+	test.That(t, seen, test.ShouldHaveLength, cloud.Size())
+
+	// the first points are one from each of the voxels an eighth of the size of the cloud, of which the far edges
+	// of the cloud take up a 9th row and column
+	voxels := map[[2]float64]bool{}
+	for _, pd := range ordered[:81] {
+		voxels[[2]float64{math.Floor(pd.P.X / (63. / 8)), math.Floor(pd.P.Y / (63. / 8))}] = true
+	}
+	test.That(t, voxels, test.ShouldHaveLength, 81)
+}
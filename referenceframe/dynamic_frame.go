@@ -0,0 +1,138 @@
+package referenceframe
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/component/arm/v1"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+// PoseFunc returns the pose of a dynamic frame relative to its parent at the given time.
+type PoseFunc func(t time.Time) (spatial.Pose, error)
+
+// DynamicFrame is a frame whose pose relative to its parent changes over time without being driven by inputs, such as a
+// pan-tilt mount whose orientation is read from a movement sensor. Its Transform is its pose at the current time, and
+// FrameSystem.TransformAt uses its pose at the time asked for.
+type DynamicFrame interface {
+	Frame
+
+	// PoseAt returns the pose of the frame relative to its parent at the given time.
+	PoseAt(t time.Time) (spatial.Pose, error)
+}
+
+// a dynamicFrame is a frame with no inputs whose pose is supplied by a PoseFunc.
+type dynamicFrame struct {
+	*baseFrame
+	poseAt PoseFunc
+}
+
+// NewDynamicFrame creates a frame whose pose relative to its parent at any time is given by poseAt.
+// poseAt is not allowed to be nil.
+func NewDynamicFrame(name string, poseAt PoseFunc) (DynamicFrame, error) {
+	if poseAt == nil {
+		return nil, errors.New("pose function is not allowed to be nil")
+	}
+	return &dynamicFrame{&baseFrame{name, []Limit{}}, poseAt}, nil
+}
+
+// PoseAt returns the pose of the frame relative to its parent at the given time.
+func (df *dynamicFrame) PoseAt(t time.Time) (spatial.Pose, error) {
+	return df.poseAt(t)
+}
+
+// Transform returns the pose of the frame relative to its parent at the current time.
+func (df *dynamicFrame) Transform(input []Input) (spatial.Pose, error) {
+	if len(input) != 0 {
+		return nil, NewIncorrectInputLengthError(len(input), 0)
+	}
+	return df.poseAt(time.Now())
+}
+
+// InputFromProtobuf converts pb.JointPosition to inputs.
+func (df *dynamicFrame) InputFromProtobuf(jp *pb.JointPositions) []Input {
+	return []Input{}
+}
+
+// ProtobufFromInput converts inputs to pb.JointPosition.
+func (df *dynamicFrame) ProtobufFromInput(input []Input) *pb.JointPositions {
+	return &pb.JointPositions{}
+}
+
+// Geometries returns no geometries, as a dynamic frame does not occupy any space itself.
+func (df *dynamicFrame) Geometries(input []Input) (*GeometriesInFrame, error) {
+	return NewGeometriesInFrame(df.Name(), nil), nil
+}
+
+// MarshalJSON returns an error, as the source of the pose of a dynamic frame cannot be serialized.
+func (df dynamicFrame) MarshalJSON() ([]byte, error) {
+	return nil, errors.Errorf("cannot marshal dynamic frame %q", df.name)
+}
+
+// AlmostEquals returns whether the other frame is the same dynamic frame, as the sources of their poses cannot be compared.
+func (df *dynamicFrame) AlmostEquals(otherFrame Frame) bool {
+	other, ok := otherFrame.(*dynamicFrame)
+	return ok && df == other
+}
+
+// timedPose is a pose and the time at which it was read.
+type timedPose struct {
+	time time.Time
+	pose spatial.Pose
+}
+
+// PoseHistory keeps the most recent timestamped readings of a pose, such as from a movement sensor, and gives the pose at
+// any time between them by interpolating the readings around it. Its PoseAt method can be used as the PoseFunc of a
+// dynamic frame.
+type PoseHistory struct {
+	mu       sync.Mutex
+	size     int
+	readings []timedPose
+}
+
+// NewPoseHistory creates a PoseHistory that keeps at most size readings.
+func NewPoseHistory(size int) *PoseHistory {
+	if size < 1 {
+		size = 1
+	}
+	return &PoseHistory{size: size}
+}
+
+// Add records the pose read at the given time, dropping the oldest reading if the history is full.
+func (ph *PoseHistory) Add(t time.Time, pose spatial.Pose) {
+	ph.mu.Lock()
+	defer ph.mu.Unlock()
+	// readings usually arrive in order, but insert in place in case they do not
+	i := sort.Search(len(ph.readings), func(i int) bool { return ph.readings[i].time.After(t) })
+	ph.readings = append(ph.readings, timedPose{})
+	copy(ph.readings[i+1:], ph.readings[i:])
+	ph.readings[i] = timedPose{time: t, pose: pose}
+	if len(ph.readings) > ph.size {
+		ph.readings = ph.readings[len(ph.readings)-ph.size:]
+	}
+}
+
+// PoseAt returns the pose at the given time, interpolated between the readings before and after it. Times after the
+// latest reading give the latest reading. Times before the oldest reading kept are an error, as are all times if
+// there are no readings.
+func (ph *PoseHistory) PoseAt(t time.Time) (spatial.Pose, error) {
+	ph.mu.Lock()
+	defer ph.mu.Unlock()
+	if len(ph.readings) == 0 {
+		return nil, errors.New("no poses have been recorded")
+	}
+	if t.Before(ph.readings[0].time) {
+		return nil, errors.Errorf("no pose recorded at or before %v, oldest is at %v", t, ph.readings[0].time)
+	}
+	// i is the first reading after t, which is never the first reading
+	i := sort.Search(len(ph.readings), func(i int) bool { return ph.readings[i].time.After(t) })
+	if i == len(ph.readings) {
+		return ph.readings[i-1].pose, nil
+	}
+	before, after := ph.readings[i-1], ph.readings[i]
+	by := float64(t.Sub(before.time)) / float64(after.time.Sub(before.time))
+	return spatial.Interpolate(before.pose, after.pose, by), nil
+}
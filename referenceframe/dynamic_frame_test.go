@@ -0,0 +1,85 @@
+package referenceframe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func TestPoseHistory(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := NewPoseHistory(3)
+	_, err := history.PoseAt(start)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// readings out of order are kept in order
+	history.Add(start.Add(2*time.Second), spatial.NewPose(r3.Vector{X: 20}, &spatial.OrientationVectorDegrees{OZ: 1, Theta: 90}))
+	history.Add(start, spatial.NewPose(r3.Vector{}, &spatial.OrientationVectorDegrees{OZ: 1}))
+
+	pose, err := history.PoseAt(start)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.PoseAlmostEqual(pose, spatial.NewZeroPose()), test.ShouldBeTrue)
+	pose, err = history.PoseAt(start.Add(time.Second))
+	test.That(t, err, test.ShouldBeNil)
+	expected := spatial.NewPose(r3.Vector{X: 10}, &spatial.OrientationVectorDegrees{OZ: 1, Theta: 45})
+	test.That(t, spatial.PoseAlmostEqual(pose, expected), test.ShouldBeTrue)
+	pose, err = history.PoseAt(start.Add(time.Minute))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pose.Point().X, test.ShouldAlmostEqual, 20)
+	_, err = history.PoseAt(start.Add(-time.Second))
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// the oldest readings are dropped once the history is full
+	history.Add(start.Add(3*time.Second), spatial.NewPoseFromPoint(r3.Vector{X: 30}))
+	history.Add(start.Add(4*time.Second), spatial.NewPoseFromPoint(r3.Vector{X: 40}))
+	_, err = history.PoseAt(start.Add(time.Second))
+	test.That(t, err, test.ShouldNotBeNil)
+	pose, err = history.PoseAt(start.Add(3500 * time.Millisecond))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pose.Point().X, test.ShouldAlmostEqual, 35)
+}
+
+func TestDynamicFrameTransformAt(t *testing.T) {
+	// a camera on a pan mount 100 mm above a base, which turns to the left over a second
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := NewPoseHistory(10)
+	history.Add(start, spatial.NewPoseFromPoint(r3.Vector{Z: 100}))
+	history.Add(start.Add(time.Second), spatial.NewPose(r3.Vector{Z: 100}, &spatial.OrientationVectorDegrees{OZ: 1, Theta: 90}))
+
+	fs := NewEmptySimpleFrameSystem("test")
+	base, err := FrameFromPoint("base", r3.Vector{X: 10})
+	test.That(t, err, test.ShouldBeNil)
+	err = fs.AddFrame(base, fs.World())
+	test.That(t, err, test.ShouldBeNil)
+	camera, err := NewDynamicFrame("camera", history.PoseAt)
+	test.That(t, err, test.ShouldBeNil)
+	err = fs.AddFrame(camera, base)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, StartPositions(fs)["camera"], test.ShouldHaveLength, 0)
+
+	// a point seen 50 mm in front of the camera
+	seen := NewPoseInFrame("camera", spatial.NewPoseFromPoint(r3.Vector{X: 50}))
+
+	tf, err := fs.TransformAt(StartPositions(fs), seen, World, start)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(tf.(*PoseInFrame).Pose().Point(), r3.Vector{X: 60, Z: 100}, 1e-9), test.ShouldBeTrue)
+
+	tf, err = fs.TransformAt(StartPositions(fs), seen, World, start.Add(time.Second))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(tf.(*PoseInFrame).Pose().Point(), r3.Vector{X: 10, Y: 50, Z: 100}, 1e-9), test.ShouldBeTrue)
+
+	// without a time the latest pose is used
+	tf, err = fs.Transform(StartPositions(fs), seen, World)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(tf.(*PoseInFrame).Pose().Point(), r3.Vector{X: 10, Y: 50, Z: 100}, 1e-9), test.ShouldBeTrue)
+
+	_, err = fs.TransformAt(StartPositions(fs), seen, World, start.Add(-time.Second))
+	test.That(t, err, test.ShouldNotBeNil)
+
+	_, err = NewDynamicFrame("camera", nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -3,6 +3,8 @@ package referenceframe
 import (
 	"encoding/json"
 	"errors"
+	"sync"
+	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/golang/geo/r3"
@@ -50,6 +52,15 @@ type FrameSystem interface {
 	// is a map of inputs for any frames with non-zero DOF, with slices of inputs keyed to the frame name.
 	Transform(positions map[string][]Input, object Transformable, dst string) (Transformable, error)
 
+	// TransformAt is Transform with any DynamicFrames in the frame system at their poses at the given time, such as the
+	// time an image was taken, rather than at their current poses.
+	TransformAt(positions map[string][]Input, object Transformable, dst string, t time.Time) (Transformable, error)
+
+	// TransformTwist takes in the twist of a rigid body observed in one frame and returns the same motion observed in the
+	// destination frame, as if the destination frame were fixed to the body. Positions is as for Transform; the velocities
+	// of any joints between the two frames are not included.
+	TransformTwist(positions map[string][]Input, twist *TwistInFrame, dst string) (*TwistInFrame, error)
+
 	// FrameSystemSubset will take a frame system and a frame in that system, and return a new frame system rooted
 	// at the given frame and containing all descendents of it. The original frame system is unchanged.
 	FrameSystemSubset(newRoot Frame) (FrameSystem, error)
@@ -77,12 +88,23 @@ type simpleFrameSystem struct {
 	world   Frame // separate from the map of frames so it can be detached easily
 	frames  map[string]Frame
 	parents map[Frame]Frame
+
+	// staticPoses caches the poses in the world frame of frames that are fixed to it, and nil for frames that are not,
+	// so that the static part of a chain is not recomputed on every Transform.
+	staticMu    sync.RWMutex
+	staticPoses map[Frame]spatial.Pose
 }
 
 // NewEmptySimpleFrameSystem creates a graph of Frames that have.
 func NewEmptySimpleFrameSystem(name string) FrameSystem {
 	worldFrame := NewZeroStaticFrame(World)
-	return &simpleFrameSystem{name, worldFrame, map[string]Frame{}, map[Frame]Frame{}}
+	return &simpleFrameSystem{
+		name:        name,
+		world:       worldFrame,
+		frames:      map[string]Frame{},
+		parents:     map[Frame]Frame{},
+		staticPoses: map[Frame]spatial.Pose{},
+	}
 }
 
 // World returns the base world referenceframe.
@@ -116,6 +138,9 @@ func (sfs *simpleFrameSystem) frameExists(name string) bool {
 func (sfs *simpleFrameSystem) RemoveFrame(frame Frame) {
 	delete(sfs.frames, frame.Name())
 	delete(sfs.parents, frame)
+	sfs.staticMu.Lock()
+	sfs.staticPoses = map[Frame]spatial.Pose{}
+	sfs.staticMu.Unlock()
 
 	// Remove all descendents
 	for f, parent := range sfs.parents {
@@ -186,6 +211,27 @@ func (sfs *simpleFrameSystem) AddFrame(frame, parent Frame) error {
 // Transform takes in a Transformable object and destination frame, and returns the pose from the first to the second. Positions
 // is a map of inputs for any frames with non-zero DOF, with slices of inputs keyed to the frame name.
 func (sfs *simpleFrameSystem) Transform(positions map[string][]Input, object Transformable, dst string) (Transformable, error) {
+	return sfs.transform(positions, object, dst, time.Time{})
+}
+
+// TransformAt is Transform with any DynamicFrames in the frame system at their poses at the given time, such as the
+// time an image was taken, rather than at their current poses.
+func (sfs *simpleFrameSystem) TransformAt(
+	positions map[string][]Input,
+	object Transformable,
+	dst string,
+	t time.Time,
+) (Transformable, error) {
+	return sfs.transform(positions, object, dst, t)
+}
+
+// transform is Transform at the given time, where the zero time is the current time.
+func (sfs *simpleFrameSystem) transform(
+	positions map[string][]Input,
+	object Transformable,
+	dst string,
+	t time.Time,
+) (Transformable, error) {
 	src := object.Parent()
 	if src == dst {
 		return object, nil
@@ -206,9 +252,9 @@ func (sfs *simpleFrameSystem) Transform(positions map[string][]Input, object Tra
 		// A frame is assigned a pose and a geometry and the two are not coupled together. This way you do can define everything relative
 		// to the parent frame. So geometries are tied to the frame they are assigned to but we do not want to actually transform them
 		// along the final transformation.
-		tfParent, err = sfs.transformFromParent(positions, sfs.parents[srcFrame], sfs.Frame(dst))
+		tfParent, err = sfs.transformFromParent(positions, sfs.parents[srcFrame], sfs.Frame(dst), t)
 	} else {
-		tfParent, err = sfs.transformFromParent(positions, srcFrame, sfs.Frame(dst))
+		tfParent, err = sfs.transformFromParent(positions, srcFrame, sfs.Frame(dst), t)
 	}
 	if err != nil {
 		return nil, err
@@ -216,6 +262,21 @@ func (sfs *simpleFrameSystem) Transform(positions map[string][]Input, object Tra
 	return object.Transform(tfParent), nil
 }
 
+// TransformTwist takes in the twist of a rigid body observed in one frame and returns the same motion observed in the
+// destination frame, as if the destination frame were fixed to the body. Positions is as for Transform; the velocities
+// of any joints between the two frames are not included.
+func (sfs *simpleFrameSystem) TransformTwist(
+	positions map[string][]Input,
+	twist *TwistInFrame,
+	dst string,
+) (*TwistInFrame, error) {
+	tf, err := sfs.Transform(positions, twist, dst)
+	if err != nil {
+		return nil, err
+	}
+	return tf.(*TwistInFrame), nil
+}
+
 // Name returns the name of the simpleFrameSystem.
 func (sfs *simpleFrameSystem) Name() string {
 	return sfs.name
@@ -274,7 +335,13 @@ func (sfs *simpleFrameSystem) MergeFrameSystem(systemToMerge FrameSystem, attach
 // at the given frame and containing all descendents of it. The original frame system is unchanged.
 func (sfs *simpleFrameSystem) FrameSystemSubset(newRoot Frame) (FrameSystem, error) {
 	newWorld := NewZeroStaticFrame(World)
-	newFS := &simpleFrameSystem{newRoot.Name() + "_FS", newWorld, map[string]Frame{}, map[Frame]Frame{}}
+	newFS := &simpleFrameSystem{
+		name:        newRoot.Name() + "_FS",
+		world:       newWorld,
+		frames:      map[string]Frame{},
+		parents:     map[Frame]Frame{},
+		staticPoses: map[Frame]spatial.Pose{},
+	}
 
 	rootFrame := sfs.Frame(newRoot.Name())
 	if rootFrame == nil {
@@ -325,7 +392,11 @@ func (sfs *simpleFrameSystem) DivideFrameSystem(newRoot Frame) (FrameSystem, err
 	return newFS, nil
 }
 
-func (sfs *simpleFrameSystem) getFrameToWorldTransform(inputMap map[string][]Input, src Frame) (spatial.Pose, error) {
+func (sfs *simpleFrameSystem) getFrameToWorldTransform(
+	inputMap map[string][]Input,
+	src Frame,
+	t time.Time,
+) (spatial.Pose, error) {
 	if !sfs.frameExists(src.Name()) {
 		return nil, NewFrameMissingError(src.Name())
 	}
@@ -334,7 +405,7 @@ func (sfs *simpleFrameSystem) getFrameToWorldTransform(inputMap map[string][]Inp
 	var err error
 	srcToWorld := spatial.NewZeroPose()
 	if src != nil {
-		srcToWorld, err = sfs.composeTransforms(src, inputMap)
+		srcToWorld, err = sfs.composeTransforms(src, inputMap, t)
 		if err != nil && srcToWorld == nil {
 			return nil, err
 		}
@@ -343,12 +414,12 @@ func (sfs *simpleFrameSystem) getFrameToWorldTransform(inputMap map[string][]Inp
 }
 
 // Returns the relative pose between the parent and the destination frame.
-func (sfs *simpleFrameSystem) transformFromParent(inputMap map[string][]Input, src, dst Frame) (*PoseInFrame, error) {
+func (sfs *simpleFrameSystem) transformFromParent(inputMap map[string][]Input, src, dst Frame, t time.Time) (*PoseInFrame, error) {
 	// catch all errors together to allow for hypothetical calculations that result in errors
 	var errAll error
-	dstToWorld, err := sfs.getFrameToWorldTransform(inputMap, dst)
+	dstToWorld, err := sfs.getFrameToWorldTransform(inputMap, dst, t)
 	multierr.AppendInto(&errAll, err)
-	srcToWorld, err := sfs.getFrameToWorldTransform(inputMap, src)
+	srcToWorld, err := sfs.getFrameToWorldTransform(inputMap, src, t)
 	multierr.AppendInto(&errAll, err)
 	if errAll != nil && (dstToWorld == nil || srcToWorld == nil) {
 		return nil, errAll
@@ -359,12 +430,16 @@ func (sfs *simpleFrameSystem) transformFromParent(inputMap map[string][]Input, s
 }
 
 // compose the quaternions from the input frame to the world referenceframe.
-func (sfs *simpleFrameSystem) composeTransforms(frame Frame, inputMap map[string][]Input) (spatial.Pose, error) {
+func (sfs *simpleFrameSystem) composeTransforms(frame Frame, inputMap map[string][]Input, t time.Time) (spatial.Pose, error) {
 	q := spatial.NewZeroPose() // empty initial dualquat
 	var errAll error
 	for sfs.parents[frame] != nil { // stop once you reach world node
+		// the rest of the chain is fixed to the world, so its pose is already known
+		if toWorld, ok := sfs.staticPoseToWorld(frame); ok {
+			return spatial.Compose(toWorld, q), errAll
+		}
 		// Transform() gives FROM q TO parent. Add new transforms to the left.
-		pose, err := poseFromPositions(frame, inputMap)
+		pose, err := poseFromPositions(frame, inputMap, t)
 		if err != nil && pose == nil {
 			return nil, err
 		}
@@ -375,6 +450,35 @@ func (sfs *simpleFrameSystem) composeTransforms(frame Frame, inputMap map[string
 	return q, errAll
 }
 
+// staticPoseToWorld returns the pose of the frame in the world frame if it and all of its ancestors are static, caching
+// the answer either way until a frame is removed.
+func (sfs *simpleFrameSystem) staticPoseToWorld(frame Frame) (spatial.Pose, bool) {
+	if frame == sfs.world {
+		return spatial.NewZeroPose(), true
+	}
+	sfs.staticMu.RLock()
+	pose, ok := sfs.staticPoses[frame]
+	sfs.staticMu.RUnlock()
+	if ok {
+		return pose, pose != nil
+	}
+
+	pose = nil
+	if _, dynamic := frame.(DynamicFrame); !dynamic && len(frame.DoF()) == 0 {
+		if parent, ok := sfs.parents[frame]; ok {
+			if parentToWorld, ok := sfs.staticPoseToWorld(parent); ok {
+				if tf, err := frame.Transform([]Input{}); err == nil {
+					pose = spatial.Compose(parentToWorld, tf)
+				}
+			}
+		}
+	}
+	sfs.staticMu.Lock()
+	sfs.staticPoses[frame] = pose
+	sfs.staticMu.Unlock()
+	return pose, pose != nil
+}
+
 // StartPositions returns a zeroed input map ensuring all frames have inputs.
 func StartPositions(fs FrameSystem) map[string][]Input {
 	positions := make(map[string][]Input)
@@ -541,7 +645,11 @@ func CreateFramesFromPart(part *FrameSystemPart) (Frame, Frame, error) {
 	return modelFrame, &tailGeometryStaticFrame{staticOriginFrame}, nil
 }
 
-func poseFromPositions(frame Frame, positions map[string][]Input) (spatial.Pose, error) {
+// poseFromPositions returns the pose of the frame at the given time, where the zero time is the current time.
+func poseFromPositions(frame Frame, positions map[string][]Input, t time.Time) (spatial.Pose, error) {
+	if df, ok := frame.(DynamicFrame); ok && !t.IsZero() {
+		return df.PoseAt(t)
+	}
 	inputs, err := GetFrameInputs(frame, positions)
 	if err != nil {
 		return nil, err
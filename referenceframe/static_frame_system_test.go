@@ -268,6 +268,85 @@ func TestGeomtriesTransform(t *testing.T) {
 	test.That(t, spatial.PoseAlmostCoincident(framedGeometries.GeometryByName("object").Pose(), objectFromFrame3), test.ShouldBeTrue)
 }
 
+func TestStaticPoseCache(t *testing.T) {
+	fs := NewEmptySimpleFrameSystem("test")
+	mount, err := FrameFromPoint("mount", r3.Vector{0, 0, 100})
+	test.That(t, err, test.ShouldBeNil)
+	err = fs.AddFrame(mount, fs.World())
+	test.That(t, err, test.ShouldBeNil)
+	joint, err := NewRotationalFrame("joint", spatial.R4AA{RZ: 1}, Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	err = fs.AddFrame(joint, mount)
+	test.That(t, err, test.ShouldBeNil)
+	tip, err := FrameFromPoint("tip", r3.Vector{10, 0, 0})
+	test.That(t, err, test.ShouldBeNil)
+	err = fs.AddFrame(tip, joint)
+	test.That(t, err, test.ShouldBeNil)
+
+	// the static mount is cached while the frames after the joint still follow its input
+	origin := NewPoseInFrame("tip", spatial.NewZeroPose())
+	for _, angle := range []float64{0, math.Pi / 2, 0} {
+		tf, err := fs.Transform(map[string][]Input{"joint": {{angle}}}, origin, World)
+		test.That(t, err, test.ShouldBeNil)
+		expected := r3.Vector{10 * math.Cos(angle), 10 * math.Sin(angle), 100}
+		test.That(t, spatial.R3VectorAlmostEqual(tf.(*PoseInFrame).Pose().Point(), expected, 1e-9), test.ShouldBeTrue)
+	}
+
+	// replacing a static frame replaces its cached pose
+	fs.RemoveFrame(mount)
+	mount, err = FrameFromPoint("mount", r3.Vector{0, 0, 200})
+	test.That(t, err, test.ShouldBeNil)
+	err = fs.AddFrame(mount, fs.World())
+	test.That(t, err, test.ShouldBeNil)
+	tf, err := fs.Transform(map[string][]Input{}, NewPoseInFrame("mount", spatial.NewZeroPose()), World)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tf.(*PoseInFrame).Pose().Point(), test.ShouldResemble, r3.Vector{0, 0, 200})
+}
+
+func TestTwistTransform(t *testing.T) {
+	// build the system: a camera 100 mm out on a pan joint mounted on a base
+	fs := NewEmptySimpleFrameSystem("test")
+	base, err := FrameFromPoint("base", r3.Vector{10, 20, 0})
+	test.That(t, err, test.ShouldBeNil)
+	err = fs.AddFrame(base, fs.World())
+	test.That(t, err, test.ShouldBeNil)
+	pan, err := NewRotationalFrame("pan", spatial.R4AA{RZ: 1}, Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	err = fs.AddFrame(pan, base)
+	test.That(t, err, test.ShouldBeNil)
+	camera, err := FrameFromPoint("camera", r3.Vector{100, 0, 0})
+	test.That(t, err, test.ShouldBeNil)
+	err = fs.AddFrame(camera, pan)
+	test.That(t, err, test.ShouldBeNil)
+
+	// the base drives forward at 10 mm/sec while turning left at 90 deg/sec
+	baseTwist := NewTwistInFrame("base", r3.Vector{X: 10}, spatial.AngularVelocity{Z: 90})
+
+	// the camera is ahead of the base, so it also moves to the left
+	positions := map[string][]Input{"pan": FloatsToInputs([]float64{0})}
+	cameraTwist, err := fs.TransformTwist(positions, baseTwist, "camera")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cameraTwist.Parent(), test.ShouldEqual, "camera")
+	test.That(t, spatial.R3VectorAlmostEqual(cameraTwist.Linear(), r3.Vector{X: 10, Y: 50 * math.Pi}, 1e-9), test.ShouldBeTrue)
+	test.That(t, spatial.R3VectorAlmostEqual(r3.Vector(cameraTwist.Angular()), r3.Vector{Z: 90}, 1e-9), test.ShouldBeTrue)
+
+	// panned to the left, the camera is beside the base and moves backwards, which is to its right
+	positions = map[string][]Input{"pan": FloatsToInputs([]float64{math.Pi / 2})}
+	cameraTwist, err = fs.TransformTwist(positions, baseTwist, "camera")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(cameraTwist.Linear(), r3.Vector{Y: 50*math.Pi - 10}, 1e-9), test.ShouldBeTrue)
+	test.That(t, spatial.R3VectorAlmostEqual(r3.Vector(cameraTwist.Angular()), r3.Vector{Z: 90}, 1e-9), test.ShouldBeTrue)
+
+	// transforming back recovers the twist of the base
+	tf, err := fs.TransformTwist(positions, cameraTwist, "base")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(tf.Linear(), baseTwist.Linear(), 1e-9), test.ShouldBeTrue)
+	test.That(t, spatial.R3VectorAlmostEqual(r3.Vector(tf.Angular()), r3.Vector(baseTwist.Angular()), 1e-9), test.ShouldBeTrue)
+
+	_, err = fs.TransformTwist(positions, baseTwist, "foo")
+	test.That(t, err, test.ShouldBeError, NewFrameMissingError("foo"))
+}
+
 func TestComplicatedFrameTransform(t *testing.T) {
 	// build the system
 	fs := NewEmptySimpleFrameSystem("test")
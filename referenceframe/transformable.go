@@ -1,6 +1,7 @@
 package referenceframe
 
 import (
+	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
 	commonpb "go.viam.com/api/common/v1"
 
@@ -102,6 +103,42 @@ func NewLinkInFrame(frame string, pose spatialmath.Pose, name string, geometry s
 	}
 }
 
+// TwistInFrame is the velocity of a rigid body, with the linear velocity in mm/sec of the origin of the frame it is
+// observed in and the angular velocity in deg/sec of the body, both expressed in that frame.
+type TwistInFrame struct {
+	parent  string
+	linear  r3.Vector
+	angular spatialmath.AngularVelocity
+}
+
+// NewTwistInFrame generates a new TwistInFrame.
+func NewTwistInFrame(frame string, linear r3.Vector, angular spatialmath.AngularVelocity) *TwistInFrame {
+	return &TwistInFrame{parent: frame, linear: linear, angular: angular}
+}
+
+// Parent returns the name of the frame in which the twist was observed. Needed for Transformable interface.
+func (tF *TwistInFrame) Parent() string {
+	return tF.parent
+}
+
+// Linear returns the linear velocity of the origin of the frame the twist was observed in.
+func (tF *TwistInFrame) Linear() r3.Vector {
+	return tF.linear
+}
+
+// Angular returns the angular velocity of the body.
+func (tF *TwistInFrame) Angular() spatialmath.AngularVelocity {
+	return tF.angular
+}
+
+// Transform changes the TwistInFrame tF into the reference frame specified by the tf argument, treating the two frames
+// as fixed to each other. The tf PoseInFrame represents the pose of the tF reference frame with respect to the
+// destination reference frame.
+func (tF *TwistInFrame) Transform(tf *PoseInFrame) Transformable {
+	linear, angular := spatialmath.TransformTwist(tf.pose, tF.linear, tF.angular)
+	return NewTwistInFrame(tf.parent, linear, angular)
+}
+
 // PoseInFrameToProtobuf converts a PoseInFrame struct to a PoseInFrame protobuf message.
 func PoseInFrameToProtobuf(framedPose *PoseInFrame) *commonpb.PoseInFrame {
 	poseProto := &commonpb.Pose{}
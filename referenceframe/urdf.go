@@ -2,6 +2,7 @@ package referenceframe
 
 import (
 	"encoding/xml"
+	"fmt"
 	"math"
 	"os"
 	"strconv"
@@ -24,57 +25,78 @@ type URDFConfig struct {
 
 // URDFLink is a struct which details the XML used in a URDF link element.
 type URDFLink struct {
-	XMLName   xml.Name `xml:"link"`
-	Name      string   `xml:"name,attr"`
-	Collision []struct {
-		XMLName xml.Name `xml:"collision"`
-		Name    string   `xml:"name,attr"`
-		Origin  struct {
-			XMLName xml.Name `xml:"origin"`
-			RPY     string   `xml:"rpy,attr"` // Fixed frame angle "r p y" format, in radians
-			XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
-		} `xml:"origin"`
-		Geometry struct {
-			XMLName xml.Name `xml:"geometry"`
-			Box     struct {
-				XMLName xml.Name `xml:"box"`
-				Size    string   `xml:"size,attr"` // "x y z" format, in meters
-			} `xml:"box"`
-			Sphere struct {
-				XMLName xml.Name `xml:"sphere"`
-				Radius  float64  `xml:"radius,attr"` // in meters
-			} `xml:"sphere"`
-		} `xml:"geometry"`
-	} `xml:"collision"`
+	XMLName   xml.Name        `xml:"link"`
+	Name      string          `xml:"name,attr"`
+	Collision []URDFCollision `xml:"collision"`
 }
 
-// URDFJoint is a struct which details the XML used in a URDF joint element.
+// URDFCollision is a struct which details the XML used in a URDF collision element.
+type URDFCollision struct {
+	XMLName  xml.Name     `xml:"collision"`
+	Name     string       `xml:"name,attr,omitempty"`
+	Origin   *URDFOrigin  `xml:"origin"`
+	Geometry URDFGeometry `xml:"geometry"`
+}
+
+// URDFGeometry is a struct which details the XML used in a URDF geometry element. Exactly one of its shapes is set.
+type URDFGeometry struct {
+	XMLName  xml.Name      `xml:"geometry"`
+	Box      *URDFBox      `xml:"box"`
+	Sphere   *URDFSphere   `xml:"sphere"`
+	Cylinder *URDFCylinder `xml:"cylinder"`
+}
+
+// URDFBox is a struct which details the XML used in a URDF box element.
+type URDFBox struct {
+	Size string `xml:"size,attr"` // "x y z" format, in meters
+}
+
+// URDFSphere is a struct which details the XML used in a URDF sphere element.
+type URDFSphere struct {
+	Radius float64 `xml:"radius,attr"` // in meters
+}
+
+// URDFCylinder is a struct which details the XML used in a URDF cylinder element, which extends along its Z axis.
+type URDFCylinder struct {
+	Radius float64 `xml:"radius,attr"` // in meters
+	Length float64 `xml:"length,attr"` // in meters
+}
+
+// URDFOrigin is a struct which details the XML used in a URDF origin element, the pose of a joint or collision
+// relative to the link it belongs to.
+type URDFOrigin struct {
+	XMLName xml.Name `xml:"origin"`
+	RPY     string   `xml:"rpy,attr,omitempty"` // Fixed frame angle "r p y" format, in radians
+	XYZ     string   `xml:"xyz,attr,omitempty"` // "x y z" format, in meters
+}
+
+// URDFJoint is a struct which details the XML used in a URDF joint element. The child link of a joint is placed at
+// its origin relative to its parent link, and then moved along or about its axis.
 type URDFJoint struct {
-	XMLName xml.Name `xml:"joint"`
-	Name    string   `xml:"name,attr"`
-	Type    string   `xml:"type,attr"`
-	Origin  struct {
-		XMLName xml.Name `xml:"origin"`
-		RPY     string   `xml:"rpy,attr"` // Fixed frame angle "r p y" format, in radians
-		XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
-	} `xml:"origin"`
-	Parent struct {
-		XMLName xml.Name `xml:"parent"`
-		Link    string   `xml:"link,attr"`
-	} `xml:"parent"`
-	Child struct {
-		XMLName xml.Name `xml:"child"`
-		Link    string   `xml:"link,attr"`
-	} `xml:"child"`
-	Axis struct {
-		XMLName xml.Name `xml:"axis"`
-		XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
-	} `xml:"axis"`
-	Limit struct {
-		XMLName xml.Name `xml:"limit"`
-		Lower   float64  `xml:"lower,attr"` // translation limits are in meters, revolute limits are in radians
-		Upper   float64  `xml:"upper,attr"` // translation limits are in meters, revolute limits are in radians
-	} `xml:"limit"`
+	XMLName xml.Name    `xml:"joint"`
+	Name    string      `xml:"name,attr"`
+	Type    string      `xml:"type,attr"`
+	Origin  *URDFOrigin `xml:"origin"`
+	Parent  URDFLinkRef `xml:"parent"`
+	Child   URDFLinkRef `xml:"child"`
+	Axis    *URDFAxis   `xml:"axis"`
+	Limit   *URDFLimit  `xml:"limit"`
+}
+
+// URDFLinkRef is a struct which details the XML used in the parent and child elements of a URDF joint.
+type URDFLinkRef struct {
+	Link string `xml:"link,attr"`
+}
+
+// URDFAxis is a struct which details the XML used in a URDF axis element.
+type URDFAxis struct {
+	XYZ string `xml:"xyz,attr"` // "x y z" format, unitless
+}
+
+// URDFLimit is a struct which details the XML used in a URDF limit element.
+type URDFLimit struct {
+	Lower float64 `xml:"lower,attr"` // translation limits are in meters, revolute limits are in radians
+	Upper float64 `xml:"upper,attr"` // translation limits are in meters, revolute limits are in radians
 }
 
 // ParseURDFFile will read a given file and parse the contained URDF XML data into an equivalent ModelConfig struct.
@@ -96,13 +118,16 @@ func ParseURDFFile(filename, modelName string) (Model, error) {
 // ConvertURDFToConfig will transfer the given URDF XML data into an equivalent ModelConfig. Direct unmarshaling in the
 // same fashion as ModelJSON is not possible, as URDF data will need to be evaluated to accommodate differences
 // between the two kinematics encoding schemes.
+//
+// Every URDF link other than the world becomes a link of the same name, translated by the origin of the joint that
+// follows it, and every movable URDF joint becomes a joint of the same name. Only serial chains are supported, and
+// cylinder collision geometries become the smallest capsules that contain them.
 func ConvertURDFToConfig(xmlData []byte, modelName string) (*ModelConfig, error) {
 	// empty data probably means that the read URDF has no actionable information
 	if len(xmlData) == 0 {
 		return nil, ErrNoModelInformation
 	}
 
-	mc := &ModelConfig{}
 	urdf := &URDFConfig{}
 	err := xml.Unmarshal(xmlData, urdf)
 	if err != nil {
@@ -113,138 +138,212 @@ func ConvertURDFToConfig(xmlData []byte, modelName string) (*ModelConfig, error)
 		modelName = urdf.Name
 	}
 
-	parentMap := map[string]string{}
-
 	// Migrate URDF elements into an equivalent ModelConfig representation
-	mc.Name = modelName
-	mc.KinParamType = "SVA"
-
-	// Handle joints
+	mc := &ModelConfig{Name: modelName, KinParamType: "SVA"}
+
+	// Relationship tracking, links can be referred to by joints before they are declared
+	linkNames := []string{}
+	links := map[string]URDFLink{}
+	addLink := func(name string) {
+		if _, ok := links[name]; !ok {
+			links[name] = URDFLink{Name: name}
+			linkNames = append(linkNames, name)
+		}
+	}
+	for _, linkElem := range urdf.Links {
+		addLink(linkElem.Name)
+		links[linkElem.Name] = linkElem
+	}
+	incoming := map[string]URDFJoint{}
+	outgoing := map[string]URDFJoint{}
 	for _, jointElem := range urdf.Joints {
 		// Checking for reserved names in this or adjacent elements
 		if jointElem.Name == World {
 			return nil, errors.New("Joints with the name 'world' are not supported by config parsers")
 		}
-
-		// Relationship tracking
-		parentMap[jointElem.Name] = jointElem.Parent.Link
-		parentMap[jointElem.Child.Link] = jointElem.Name
-
-		// Set up the child link mentioned in this joint; fill out the details in the link parsing section later
-
-		childLink := LinkConfig{ID: jointElem.Child.Link, Parent: jointElem.Name}
-
 		switch jointElem.Type {
-		case ContinuousJoint, RevoluteJoint, PrismaticJoint:
-			// Parse important details about each joint, including axes and limits
-			jointAxes := convStringAttrToFloats(jointElem.Axis.XYZ)
-			thisJoint := JointConfig{
-				ID:     jointElem.Name,
-				Type:   jointElem.Type,
-				Parent: jointElem.Parent.Link,
-				Axis:   spatial.AxisConfig{jointAxes[0], jointAxes[1], jointAxes[2]},
-			}
-
-			// Slightly different limits handling for continuous, revolute, and prismatic joints
-			switch jointElem.Type {
-			case ContinuousJoint:
-				thisJoint.Type = RevoluteJoint // Currently, we treate a continuous joint as a special case of a revolute joint
-				thisJoint.Min, thisJoint.Max = math.Inf(-1), math.Inf(1)
-			case PrismaticJoint:
-				thisJoint.Min, thisJoint.Max = metersToMM(jointElem.Limit.Lower), metersToMM(jointElem.Limit.Upper)
-			case RevoluteJoint:
-				thisJoint.Min, thisJoint.Max = utils.RadToDeg(jointElem.Limit.Lower), utils.RadToDeg(jointElem.Limit.Upper)
-			default:
-				return nil, err
-			}
-
-			mc.Joints = append(mc.Joints, thisJoint)
+		case FixedJoint, ContinuousJoint, RevoluteJoint, PrismaticJoint:
+		default:
+			return nil, NewUnsupportedJointTypeError(jointElem.Type)
+		}
+		if _, ok := outgoing[jointElem.Parent.Link]; ok {
+			return nil, errors.Errorf("URDF link %q has more than one child joint, only serial chains are supported", jointElem.Parent.Link)
+		}
+		if _, ok := incoming[jointElem.Child.Link]; ok {
+			return nil, errors.Errorf("URDF link %q has more than one parent joint", jointElem.Child.Link)
+		}
+		outgoing[jointElem.Parent.Link] = jointElem
+		incoming[jointElem.Child.Link] = jointElem
+		addLink(jointElem.Parent.Link)
+		addLink(jointElem.Child.Link)
+	}
 
-			// Generate child link translation and orientation data, which is held by this joint per the URDF design
-			childXYZ := convStringAttrToFloats(jointElem.Origin.XYZ)
-			childRPY := convStringAttrToFloats(jointElem.Origin.RPY)
-			childEA := spatial.EulerAngles{Roll: childRPY[0], Pitch: childRPY[1], Yaw: childRPY[2]}
-			childOrient, err := spatial.NewOrientationConfig(childEA.AxisAngles())
+	// The world cannot be translated, so the origin of a joint attached to it needs a link of its own
+	worldEnd := World
+	if jointElem, ok := outgoing[World]; ok {
+		originLink, err := newURDFLinkConfig(jointElem.Name+"_origin", World, jointElem.Origin)
+		if err != nil {
+			return nil, err
+		}
+		if originLink.Translation.Norm() != 0 || originLink.Orientation != nil {
+			mc.Links = append(mc.Links, *originLink)
+			worldEnd = originLink.ID
+		}
+	}
+	// endOf returns the frame which ends at the origin of the joint following the given link
+	endOf := func(link string) string {
+		if link == World {
+			return worldEnd
+		}
+		return link
+	}
 
-			// Note the conversion from meters to mm
-			childLink.Translation = r3.Vector{
-				metersToMM(childXYZ[0]),
-				metersToMM(childXYZ[1]),
-				metersToMM(childXYZ[2]),
+	for _, name := range linkNames {
+		// Skip any world links
+		if name == World {
+			continue
+		}
+		parent := World
+		if jointElem, ok := incoming[name]; ok {
+			if jointElem.Type == FixedJoint {
+				parent = endOf(jointElem.Parent.Link)
+			} else {
+				parent = jointElem.Name
 			}
-			childLink.Orientation = childOrient
-
-			if err != nil {
+		}
+		var origin *URDFOrigin
+		if jointElem, ok := outgoing[name]; ok {
+			origin = jointElem.Origin
+		}
+		thisLink, err := newURDFLinkConfig(name, parent, origin)
+		if err != nil {
+			return nil, err
+		}
+		if len(links[name].Collision) > 0 {
+			if thisLink.Geometry, err = createConfigFromCollision(links[name]); err != nil {
 				return nil, err
 			}
-		case FixedJoint:
-			// Handle fixed joint -> static link conversion instead of adding to Joints[]
-			thisLink := LinkConfig{ID: jointElem.Name, Parent: jointElem.Parent.Link}
-
-			linkXYZ := convStringAttrToFloats(jointElem.Origin.XYZ)
-			linkRPY := convStringAttrToFloats(jointElem.Origin.RPY)
-			linkEA := spatial.EulerAngles{Roll: linkRPY[0], Pitch: linkRPY[1], Yaw: linkRPY[2]}
-			linkOrient, err := spatial.NewOrientationConfig(linkEA.AxisAngles())
-
-			// Note the conversion from meters to mm
-			thisLink.Translation = r3.Vector{
-				metersToMM(linkXYZ[0]),
-				metersToMM(linkXYZ[1]),
-				metersToMM(linkXYZ[2]),
-			}
-			thisLink.Orientation = linkOrient
+		}
+		mc.Links = append(mc.Links, *thisLink)
+	}
 
-			if err != nil {
-				return nil, err
+	for _, jointElem := range urdf.Joints {
+		if jointElem.Type == FixedJoint {
+			continue
+		}
+		// Parse important details about each joint, including axes and limits. URDF joints rotate about or translate
+		// along the X axis unless told otherwise.
+		axis := r3.Vector{X: 1}
+		if jointElem.Axis != nil {
+			if axis, err = parseURDFVector(jointElem.Axis.XYZ, "axis"); err != nil {
+				return nil, errors.Wrapf(err, "joint %q", jointElem.Name)
 			}
-
-			mc.Links = append(mc.Links, thisLink)
-		default:
-			return nil, NewUnsupportedJointTypeError(jointElem.Type)
+		}
+		thisJoint := JointConfig{
+			ID:     jointElem.Name,
+			Type:   jointElem.Type,
+			Parent: endOf(jointElem.Parent.Link),
+			Axis:   spatial.AxisConfig(axis),
 		}
 
-		mc.Links = append(mc.Links, childLink)
+		// Slightly different limits handling for continuous, revolute, and prismatic joints
+		var lower, upper float64
+		if jointElem.Limit != nil {
+			lower, upper = jointElem.Limit.Lower, jointElem.Limit.Upper
+		}
+		switch jointElem.Type {
+		case ContinuousJoint:
+			thisJoint.Type = RevoluteJoint // Currently, we treat a continuous joint as a special case of a revolute joint
+			thisJoint.Min, thisJoint.Max = math.Inf(-1), math.Inf(1)
+		case PrismaticJoint:
+			thisJoint.Min, thisJoint.Max = metersToMM(lower), metersToMM(upper)
+		case RevoluteJoint:
+			thisJoint.Min, thisJoint.Max = utils.RadToDeg(lower), utils.RadToDeg(upper)
+		}
+		mc.Joints = append(mc.Joints, thisJoint)
 	}
+	return mc, nil
+}
 
-	// Handle links
-	for _, linkElem := range urdf.Links {
-		// Skip any world links
-		if linkElem.Name == World {
-			continue
+// newURDFLinkConfig creates the config of a link translated by the given URDF origin, which may be nil.
+func newURDFLinkConfig(name, parent string, origin *URDFOrigin) (*LinkConfig, error) {
+	pose, err := origin.pose()
+	if err != nil {
+		return nil, errors.Wrapf(err, "origin of link %q", name)
+	}
+	link := &LinkConfig{ID: name, Parent: parent, Translation: pose.Point()}
+	if !spatial.OrientationAlmostEqual(pose.Orientation(), spatial.NewZeroOrientation()) {
+		if link.Orientation, err = spatial.NewOrientationConfig(pose.Orientation().AxisAngles()); err != nil {
+			return nil, err
 		}
+	}
+	return link, nil
+}
 
-		// Find matching links which already exist, take care of geometry if collision elements are detected
-		hasCollision := len(linkElem.Collision) > 0
-		for idx, prefabLink := range mc.Links {
-			if prefabLink.ID == linkElem.Name && hasCollision {
-				geoCfg, err := createConfigFromCollision(linkElem)
-				if err != nil {
-					return nil, err
-				}
-				mc.Links[idx].Geometry = &geoCfg
-				break
-			}
-		}
+// pose returns the pose described by a URDF origin, with its translation in mm. A missing origin is the zero pose.
+func (o *URDFOrigin) pose() (spatial.Pose, error) {
+	if o == nil {
+		return spatial.NewZeroPose(), nil
+	}
+	xyz, err := parseURDFVector(o.XYZ, "xyz")
+	if err != nil {
+		return nil, err
+	}
+	rpy, err := parseURDFVector(o.RPY, "rpy")
+	if err != nil {
+		return nil, err
+	}
+	// Note the conversion from meters to mm
+	return spatial.NewPose(xyz.Mul(1000), &spatial.EulerAngles{Roll: rpy.X, Pitch: rpy.Y, Yaw: rpy.Z}), nil
+}
 
-		// In the event the link does not already exist in the ModelConfig, we will have to generate it now
-		// Most likely, this is a link normally whose parent is the World
-		if _, ok := parentMap[linkElem.Name]; !ok {
-			thisLink := LinkConfig{ID: linkElem.Name, Parent: World}
-			thisLink.Translation = r3.Vector{0.0, 0.0, 0.0}
-			thisLink.Orientation = &spatial.OrientationConfig{} // Orientation is guaranteed to be zero for this
+// newURDFOrigin returns the URDF origin describing a pose with its translation in mm, or nil for the zero pose.
+func newURDFOrigin(pose spatial.Pose) *URDFOrigin {
+	if spatial.PoseAlmostEqual(pose, spatial.NewZeroPose()) {
+		return nil
+	}
+	return &URDFOrigin{
+		XYZ: formatURDFVector(pose.Point().Mul(0.001)),
+		RPY: formatURDFVector(urdfRPY(pose.Orientation())),
+	}
+}
 
-			if hasCollision {
-				geoCfg, err := createConfigFromCollision(linkElem)
-				if err != nil {
-					return nil, err
-				}
-				thisLink.Geometry = &geoCfg
-			}
+// urdfRPY returns the fixed axis roll, pitch and yaw of an orientation, which rotate it about X, then Y, then Z. When
+// the pitch is a quarter turn only the difference of the roll and yaw matters, and the yaw is left at 0.
+func urdfRPY(o spatial.Orientation) r3.Vector {
+	q := spatial.Normalize(o.Quaternion())
+	w, x, y, z := q.Real, q.Imag, q.Jmag, q.Kmag
+	r00, r10, r20 := 1-2*(y*y+z*z), 2*(x*y+w*z), 2*(x*z-w*y)
+	pitch := math.Atan2(-r20, math.Hypot(r00, r10))
+	if math.Hypot(r00, r10) < 1e-9 {
+		return r3.Vector{X: math.Atan2(-2*(y*z-w*x), 1-2*(x*x+z*z)), Y: pitch}
+	}
+	return r3.Vector{X: math.Atan2(2*(y*z+w*x), 1-2*(x*x+y*y)), Y: pitch, Z: math.Atan2(r10, r00)}
+}
 
-			mc.Links = append(mc.Links, thisLink)
+// parseURDFVector parses a space-delimited vector in a URDF, such as an xyz or rpy attribute. Missing attributes are
+// zero vectors.
+func parseURDFVector(attr, name string) (r3.Vector, error) {
+	values := convStringAttrToFloats(attr)
+	if len(values) == 0 {
+		return r3.Vector{}, nil
+	}
+	if len(values) != 3 {
+		return r3.Vector{}, errors.Errorf("%s attribute %q must have 3 values", name, attr)
+	}
+	for _, value := range values {
+		if math.IsNaN(value) {
+			return r3.Vector{}, errors.Errorf("%s attribute %q must be numeric", name, attr)
 		}
 	}
-	return mc, nil
+	return r3.Vector{X: values[0], Y: values[1], Z: values[2]}, nil
+}
+
+func formatURDFVector(v r3.Vector) string {
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return fmt.Sprintf("%s %s %s", format(v.X), format(v.Y), format(v.Z))
 }
 
 // Convenience method to split up space-delimited fields in URDFs, such as xyz or rpy attributes.
@@ -265,49 +364,39 @@ func convStringAttrToFloats(attr string) []float64 {
 }
 
 // Convenience method to simplify creating geometry configs from URDF XML that has a collision element specified.
-func createConfigFromCollision(link URDFLink) (spatial.GeometryConfig, error) {
-	var geoCfg spatial.GeometryConfig
-	boxGeometry := link.Collision[0].Geometry.Box
-	sphereGeometry := link.Collision[0].Geometry.Sphere
+func createConfigFromCollision(link URDFLink) (*spatial.GeometryConfig, error) {
+	collision := link.Collision[0]
 
 	// Offset for the geometry origin from the reference link origin
-	geomXYZ := convStringAttrToFloats(link.Collision[0].Origin.XYZ)
-	geomTx := r3.Vector{geomXYZ[0], geomXYZ[1], geomXYZ[2]}
-	geomRPY := convStringAttrToFloats(link.Collision[0].Origin.RPY)
-	geomEA := spatial.EulerAngles{
-		Roll:  utils.RadToDeg(geomRPY[0]),
-		Pitch: utils.RadToDeg(geomRPY[1]),
-		Yaw:   utils.RadToDeg(geomRPY[2]),
-	}
-	geomOx, err := spatial.NewOrientationConfig(geomEA.AxisAngles())
+	offset, err := collision.Origin.pose()
 	if err != nil {
-		return spatial.GeometryConfig{}, err
+		return nil, errors.Wrapf(err, "collision origin of link %q", link.Name)
 	}
+	geomOx, err := spatial.NewOrientationConfig(offset.Orientation().AxisAngles())
+	if err != nil {
+		return nil, err
+	}
+	geoCfg := &spatial.GeometryConfig{TranslationOffset: offset.Point(), OrientationOffset: *geomOx}
 
 	// Logic specific to the geometry type
+	geometry := collision.Geometry
 	switch {
-	case len(boxGeometry.Size) > 0:
-		boxDims := convStringAttrToFloats(boxGeometry.Size)
-		geoCfg = spatial.GeometryConfig{
-			Type:              "box",
-			X:                 metersToMM(boxDims[0]),
-			Y:                 metersToMM(boxDims[1]),
-			Z:                 metersToMM(boxDims[2]),
-			TranslationOffset: geomTx,
-			OrientationOffset: *geomOx,
-			Label:             "box",
-		}
-	case sphereGeometry.Radius > 0:
-		sphereRadius := metersToMM(sphereGeometry.Radius)
-		geoCfg = spatial.GeometryConfig{
-			Type:              "sphere",
-			R:                 sphereRadius,
-			TranslationOffset: geomTx,
-			OrientationOffset: *geomOx,
-			Label:             "sphere",
+	case geometry.Box != nil:
+		boxDims, err := parseURDFVector(geometry.Box.Size, "size")
+		if err != nil {
+			return nil, errors.Wrapf(err, "box of link %q", link.Name)
 		}
+		geoCfg.Type = spatial.BoxType
+		geoCfg.X, geoCfg.Y, geoCfg.Z = metersToMM(boxDims.X), metersToMM(boxDims.Y), metersToMM(boxDims.Z)
+	case geometry.Sphere != nil:
+		geoCfg.Type = spatial.SphereType
+		geoCfg.R = metersToMM(geometry.Sphere.Radius)
+	case geometry.Cylinder != nil:
+		geoCfg.Type = spatial.CylinderType
+		geoCfg.R = metersToMM(geometry.Cylinder.Radius)
+		geoCfg.L = metersToMM(geometry.Cylinder.Length)
 	default:
-		return spatial.GeometryConfig{}, errors.Errorf("Unsupported collision geometry type detected for [ %v ] link", link.Collision[0].Name)
+		return nil, errors.Errorf("Unsupported collision geometry type detected for [ %v ] link", link.Name)
 	}
 
 	return geoCfg, nil
@@ -317,3 +406,152 @@ func createConfigFromCollision(link URDFLink) (spatial.GeometryConfig, error) {
 func metersToMM(valMeters float64) float64 {
 	return valMeters * 1000
 }
+
+// MarshalURDF returns the URDF XML describing a model, which can be read back with ConvertURDFToConfig.
+func MarshalURDF(m Model) ([]byte, error) {
+	urdf, err := ConvertModelToURDF(m)
+	if err != nil {
+		return nil, err
+	}
+	xmlData, err := xml.MarshalIndent(urdf, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), xmlData...), nil
+}
+
+// ConvertModelToURDF will transfer the frames of a model into an equivalent URDFConfig. Static frames become links
+// joined to the link before them by fixed joints, and rotational and translational frames become revolute, continuous
+// and prismatic joints. Names are kept where URDF allows it, and the links it needs that the model does not have are
+// named after the frames they follow. Capsule geometries become the cylinders containing them.
+func ConvertModelToURDF(m Model) (*URDFConfig, error) {
+	simple, ok := m.(*SimpleModel)
+	if !ok {
+		return nil, errors.Errorf("cannot convert model of type %T to URDF", m)
+	}
+	urdf := &URDFConfig{Name: simple.Name(), Links: []URDFLink{{Name: World}}}
+
+	// The link whose frame the next frame of the model starts at, once translated by origin
+	link := World
+	origin := spatial.NewZeroPose()
+	// A movable joint waits for the link after it
+	var pending *URDFJoint
+	finishPending := func(child string) {
+		if pending == nil {
+			return
+		}
+		pending.Child.Link = child
+		urdf.Joints = append(urdf.Joints, *pending)
+		pending = nil
+	}
+	addLink := func(name string, geometry spatial.Geometry, geometryOffset spatial.Pose) error {
+		newLink := URDFLink{Name: name}
+		if geometry != nil {
+			collision, err := newURDFCollision(geometry, geometryOffset)
+			if err != nil {
+				return errors.Wrapf(err, "geometry of frame %q", name)
+			}
+			newLink.Collision = append(newLink.Collision, *collision)
+		}
+		if pending != nil {
+			finishPending(name)
+		} else {
+			urdf.Joints = append(urdf.Joints, URDFJoint{
+				Name:   link + "_to_" + name,
+				Type:   FixedJoint,
+				Origin: newURDFOrigin(origin),
+				Parent: URDFLinkRef{Link: link},
+				Child:  URDFLinkRef{Link: name},
+			})
+		}
+		urdf.Links = append(urdf.Links, newLink)
+		link, origin = name, spatial.NewZeroPose()
+		return nil
+	}
+	addJoint := func(name, typ string, axis r3.Vector, limit Limit, scale float64) {
+		if pending != nil {
+			// a link is needed between two joints
+			linkName := pending.Name + "_link"
+			finishPending(linkName)
+			urdf.Links = append(urdf.Links, URDFLink{Name: linkName})
+			link, origin = linkName, spatial.NewZeroPose()
+		}
+		pending = &URDFJoint{
+			Name:   name,
+			Type:   typ,
+			Origin: newURDFOrigin(origin),
+			Parent: URDFLinkRef{Link: link},
+			Axis:   &URDFAxis{XYZ: formatURDFVector(axis)},
+		}
+		if typ != ContinuousJoint {
+			pending.Limit = &URDFLimit{Lower: limit.Min * scale, Upper: limit.Max * scale}
+		}
+	}
+
+	for _, transform := range simple.OrdTransforms {
+		switch frame := transform.(type) {
+		case *tailGeometryStaticFrame:
+			if err := addLink(frame.name, frame.geometry, frame.transform); err != nil {
+				return nil, err
+			}
+			origin = frame.transform
+		case *staticFrame:
+			if err := addLink(frame.name, frame.geometry, spatial.NewZeroPose()); err != nil {
+				return nil, err
+			}
+			origin = frame.transform
+		case *rotationalFrame:
+			typ := RevoluteJoint
+			limit := frame.limits[0]
+			if math.IsInf(limit.Min, -1) && math.IsInf(limit.Max, 1) {
+				typ = ContinuousJoint
+			}
+			addJoint(frame.name, typ, frame.rotAxis, limit, 1)
+		case *translationalFrame:
+			if frame.geometry != nil {
+				// the geometry of a translational frame does not move with it, so it is held by a link before it
+				if err := addLink(frame.name+"_geometry", frame.geometry, spatial.NewZeroPose()); err != nil {
+					return nil, err
+				}
+			}
+			addJoint(frame.name, PrismaticJoint, frame.transAxis, frame.limits[0], 0.001)
+		default:
+			return nil, errors.Errorf("frame %q of type %T cannot be converted to URDF", transform.Name(), transform)
+		}
+	}
+	switch {
+	case pending != nil:
+		linkName := pending.Name + "_link"
+		finishPending(linkName)
+		urdf.Links = append(urdf.Links, URDFLink{Name: linkName})
+	case !spatial.PoseAlmostEqual(origin, spatial.NewZeroPose()):
+		// the end of the model needs a link of its own for the translation of its last frame
+		if err := addLink(link+"_end", nil, nil); err != nil {
+			return nil, err
+		}
+	}
+	return urdf, nil
+}
+
+// newURDFCollision returns the URDF collision element of a geometry placed at the given pose in its link.
+func newURDFCollision(geometry spatial.Geometry, offset spatial.Pose) (*URDFCollision, error) {
+	cfg, err := spatial.NewGeometryConfig(geometry)
+	if err != nil {
+		return nil, err
+	}
+	collision := &URDFCollision{Origin: newURDFOrigin(spatial.Compose(offset, geometry.Pose()))}
+	switch cfg.Type {
+	case spatial.BoxType:
+		collision.Geometry.Box = &URDFBox{Size: formatURDFVector(r3.Vector{X: cfg.X, Y: cfg.Y, Z: cfg.Z}.Mul(0.001))}
+	case spatial.SphereType:
+		collision.Geometry.Sphere = &URDFSphere{Radius: cfg.R / 1000}
+	case spatial.CylinderType:
+		collision.Geometry.Cylinder = &URDFCylinder{Radius: cfg.R / 1000, Length: cfg.L / 1000}
+	case spatial.CapsuleType:
+		// URDF has no capsules, so use the cylinder of the same length and radius, which contains the capsule
+		collision.Geometry.Cylinder = &URDFCylinder{Radius: cfg.R / 1000, Length: cfg.L / 1000}
+	default:
+		return nil, errors.Errorf("URDF cannot describe %s geometries", cfg.Type)
+	}
+	return collision, nil
+}
@@ -1,9 +1,11 @@
 package referenceframe
 
 import (
+	"math"
 	"math/rand"
 	"testing"
 
+	"github.com/golang/geo/r3"
 	"go.viam.com/test"
 
 	spatial "go.viam.com/rdk/spatialmath"
@@ -86,3 +88,59 @@ func TestURDFGeometries(t *testing.T) {
 	modelGeo, _ = ur5ViamModel.Geometries(inputs)
 	test.That(t, len(modelGeo.geometries), test.ShouldEqual, 5)
 }
+
+func TestURDFForwardKinematics(t *testing.T) {
+	// the origin of each joint of a URDF places it relative to the joint before it, before the joint moves
+	u, err := ParseURDFFile(utils.ResolveFile("referenceframe/testurdf/ur5_minimal.urdf"), "")
+	test.That(t, err, test.ShouldBeNil)
+	pose, err := u.Transform(make([]Input, len(u.DoF())))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(pose.Point(), r3.Vector{X: 817.25, Y: 191.45, Z: -5.491}, 1e-3), test.ShouldBeTrue)
+
+	pose, err = u.Transform(FloatsToInputs([]float64{math.Pi / 2, 0, 0, 0, 0, 0}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(pose.Point(), r3.Vector{X: -191.45, Y: 817.25, Z: -5.491}, 1e-3), test.ShouldBeTrue)
+}
+
+func TestURDFExport(t *testing.T) {
+	for _, file := range []string{
+		"components/arm/universalrobots/ur5e.json",
+		"referenceframe/testurdf/ur5_viam.urdf",
+		"referenceframe/testurdf/example_gantry.urdf",
+	} {
+		t.Run(file, func(t *testing.T) {
+			model, err := ModelFromPath(utils.ResolveFile(file), "")
+			test.That(t, err, test.ShouldBeNil)
+
+			xmlData, err := MarshalURDF(model)
+			test.That(t, err, test.ShouldBeNil)
+			cfg, err := ConvertURDFToConfig(xmlData, "")
+			test.That(t, err, test.ShouldBeNil)
+			exported, err := cfg.ParseConfig("")
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, exported.Name(), test.ShouldEqual, model.Name())
+			test.That(t, limitsAlmostEqual(exported.DoF(), model.DoF()), test.ShouldBeTrue)
+
+			seed := rand.New(rand.NewSource(1))
+			for i := 0; i < 20; i++ {
+				inputs := FloatsToInputs(GenerateRandomConfiguration(model, seed))
+				pose, err := model.Transform(inputs)
+				test.That(t, err, test.ShouldBeNil)
+				exportedPose, err := exported.Transform(inputs)
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, spatial.PoseAlmostEqualEps(pose, exportedPose, 1e-6), test.ShouldBeTrue)
+
+				geometries, err := model.Geometries(inputs)
+				test.That(t, err, test.ShouldBeNil)
+				exportedGeometries, err := exported.Geometries(inputs)
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, exportedGeometries.Geometries(), test.ShouldHaveLength, len(geometries.Geometries()))
+				for j, g := range geometries.Geometries() {
+					exportedPose := exportedGeometries.Geometries()[j].Pose()
+					test.That(t, spatial.R3VectorAlmostEqual(g.Pose().Point(), exportedPose.Point(), 1e-6), test.ShouldBeTrue)
+					test.That(t, spatial.OrientationAlmostEqualEps(g.Pose().Orientation(), exportedPose.Orientation(), 1e-6), test.ShouldBeTrue)
+				}
+			}
+		})
+	}
+}
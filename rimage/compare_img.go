@@ -5,6 +5,7 @@ import (
 	"image/color"
 	"image/draw"
 	"math"
+	"math/bits"
 
 	"github.com/pkg/errors"
 )
@@ -67,3 +68,46 @@ func sqDiffUInt32(x, y uint32) uint64 {
 	d := uint64(x) - uint64(y)
 	return d * d
 }
+
+// DifferenceHash returns a 64 bit perceptual hash of the image, which is the same for images that look the same even if
+// their pixels differ slightly, such as from sensor noise or compression. Each bit is whether a cell of a 9x8 grid
+// over the image is brighter than the cell to its right.
+func DifferenceHash(img image.Image) uint64 {
+	const cols, rows = 9, 8
+	var cells [rows][cols]float64
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return 0
+	}
+	// each cell is the mean of a few samples of it, which is enough for the hash and much cheaper than every pixel
+	const samples = 4
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			sum := 0.
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					x := bounds.Min.X + (c*samples+sx)*bounds.Dx()/(cols*samples)
+					y := bounds.Min.Y + (r*samples+sy)*bounds.Dy()/(rows*samples)
+					sum += float64(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+				}
+			}
+			cells[r][c] = sum
+		}
+	}
+	var hash uint64
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols-1; c++ {
+			hash <<= 1
+			if cells[r][c] > cells[r][c+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// HashDistance returns the number of bits two hashes from DifferenceHash differ by, from 0 for images that look the
+// same to 64.
+func HashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
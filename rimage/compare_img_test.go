@@ -0,0 +1,37 @@
+package rimage
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestDifferenceHash(t *testing.T) {
+	gradient := func(offset uint8) *image.Gray {
+		img := image.NewGray(image.Rect(0, 0, 160, 120))
+		for y := 0; y < 120; y++ {
+			for x := 0; x < 160; x++ {
+				img.SetGray(x, y, color.Gray{Y: uint8((x+y)%200) + offset})
+			}
+		}
+		return img
+	}
+	hash := DifferenceHash(gradient(0))
+	test.That(t, HashDistance(hash, hash), test.ShouldEqual, 0)
+	// a uniformly brighter image looks the same
+	test.That(t, HashDistance(hash, DifferenceHash(gradient(20))), test.ShouldEqual, 0)
+
+	// a bright object in the scene changes the hash
+	withObject := gradient(0)
+	for y := 30; y < 90; y++ {
+		for x := 40; x < 100; x++ {
+			withObject.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	test.That(t, HashDistance(hash, DifferenceHash(withObject)), test.ShouldBeGreaterThan, 4)
+
+	test.That(t, DifferenceHash(image.NewGray(image.Rectangle{})), test.ShouldEqual, 0)
+	test.That(t, HashDistance(0, ^uint64(0)), test.ShouldEqual, 64)
+}
@@ -0,0 +1,140 @@
+package rimage
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// DepthColormap is the name of a way to color a depth map.
+type DepthColormap string
+
+// The colormaps depth maps can be colored with. Each runs from the color of the nearest depth to that of the farthest.
+const (
+	// DepthColormapHue is the one ToPrettyPicture uses, going around the hue circle from orange to blue.
+	DepthColormapHue = DepthColormap("hue")
+	// DepthColormapGrayscale goes from black to white.
+	DepthColormapGrayscale = DepthColormap("grayscale")
+	// DepthColormapJet goes from dark blue through cyan, yellow and red to dark red.
+	DepthColormapJet = DepthColormap("jet")
+	// DepthColormapTurbo is like jet, with smoother changes in brightness.
+	DepthColormapTurbo = DepthColormap("turbo")
+	// DepthColormapViridis goes from dark purple through teal to yellow, and reads the same to color blind viewers.
+	DepthColormapViridis = DepthColormap("viridis")
+)
+
+// depthColormapSteps is the number of colors each colormap is sampled at.
+const depthColormapSteps = 256
+
+// viridisStops are evenly spaced colors of viridis, which is interpolated between them.
+var viridisStops = [][3]float64{
+	{0x44, 0x01, 0x54}, {0x48, 0x28, 0x78}, {0x3e, 0x49, 0x89}, {0x31, 0x68, 0x8e}, {0x26, 0x82, 0x8e},
+	{0x1f, 0x9e, 0x89}, {0x35, 0xb7, 0x79}, {0x6e, 0xce, 0x58}, {0xfd, 0xe7, 0x25},
+}
+
+// colorAt returns the color of the colormap at the given position, from 0 at the start to 1 at the end.
+func (cm DepthColormap) colorAt(pos float64) (Color, error) {
+	switch cm {
+	case DepthColormapHue, "":
+		return NewColorFromHSV(30+200*pos, 1, 1), nil
+	case DepthColormapGrayscale:
+		v := unitTo255(pos)
+		return NewColor(v, v, v), nil
+	case DepthColormapJet:
+		return NewColor(
+			unitTo255(1.5-math.Abs(4*pos-3)),
+			unitTo255(1.5-math.Abs(4*pos-2)),
+			unitTo255(1.5-math.Abs(4*pos-1)),
+		), nil
+	case DepthColormapTurbo:
+		// polynomial approximation of turbo from https://gist.github.com/mikhailov-work/0d177465a8151eb6ede1768d51d476c7
+		return NewColor(
+			unitTo255(0.13572138+pos*(4.61539260+pos*(-42.66032258+pos*(132.13108234+pos*(-152.94239396+pos*59.28637943))))),
+			unitTo255(0.09140261+pos*(2.19418839+pos*(4.84296658+pos*(-14.18503333+pos*(4.27729857+pos*2.82956604))))),
+			unitTo255(0.10667330+pos*(12.64194608+pos*(-60.58204836+pos*(110.36276771+pos*(-89.90310912+pos*27.34824973))))),
+		), nil
+	case DepthColormapViridis:
+		scaled := pos * float64(len(viridisStops)-1)
+		i := int(math.Min(scaled, float64(len(viridisStops)-2)))
+		frac := scaled - float64(i)
+		var rgb [3]uint8
+		for c := range rgb {
+			rgb[c] = uint8(math.Round(viridisStops[i][c] + frac*(viridisStops[i+1][c]-viridisStops[i][c])))
+		}
+		return NewColor(rgb[0], rgb[1], rgb[2]), nil
+	default:
+		return Color(0), errors.Errorf("unknown depth colormap %q", cm)
+	}
+}
+
+// unitTo255 scales a value from [0, 1] to a color channel, clamping it to the range.
+func unitTo255(v float64) uint8 {
+	return uint8(math.Round(255 * math.Max(0, math.Min(1, v))))
+}
+
+// DepthColorOptions configure how a depth map is turned into a picture by ToColormapPicture.
+type DepthColorOptions struct {
+	// Colormap defaults to DepthColormapHue.
+	Colormap DepthColormap
+	// MinDepth and MaxDepth are the depths at the start and end of the colormap, with depths outside of them given the
+	// color at the nearest end. Either left at 0 is taken from the depths of each depth map instead.
+	MinDepth Depth
+	MaxDepth Depth
+	// InvalidColor is the color of the pixels that have no depth.
+	InvalidColor Color
+}
+
+// Validate ensures the options can be used to color depth maps.
+func (opts DepthColorOptions) Validate() error {
+	if _, err := opts.Colormap.colorAt(0); err != nil {
+		return err
+	}
+	if opts.MinDepth > 0 && opts.MaxDepth > 0 && opts.MinDepth >= opts.MaxDepth {
+		return errors.Errorf("min depth %d must be less than max depth %d", opts.MinDepth, opts.MaxDepth)
+	}
+	return nil
+}
+
+// ToColormapPicture colors the depth map with the colormap of the options, to make it easier to see the depth
+// gradients. The colorful picture will have no useful depth information, though.
+func (dm *DepthMap) ToColormapPicture(opts DepthColorOptions) (*Image, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	var colors [depthColormapSteps]Color
+	for i := range colors {
+		c, err := opts.Colormap.colorAt(float64(i) / (depthColormapSteps - 1))
+		if err != nil {
+			return nil, err
+		}
+		colors[i] = c
+	}
+
+	min, max := opts.MinDepth, opts.MaxDepth
+	if min == 0 || max == 0 {
+		frameMin, frameMax := dm.MinMax()
+		if min == 0 {
+			min = frameMin
+		}
+		if max == 0 {
+			max = frameMax
+		}
+	}
+	span := math.Max(float64(max)-float64(min), 1)
+
+	img := NewImage(dm.Width(), dm.Height())
+	parallelRows(dm.Width(), dm.Height(), func(yStart, yEnd int) {
+		for y := yStart; y < yEnd; y++ {
+			for x := 0; x < dm.Width(); x++ {
+				z := dm.GetDepth(x, y)
+				if z == 0 {
+					img.SetXY(x, y, opts.InvalidColor)
+					continue
+				}
+				pos := math.Max(0, math.Min(1, (float64(z)-float64(min))/span))
+				img.SetXY(x, y, colors[int(math.Round(pos*(depthColormapSteps-1)))])
+			}
+		}
+	})
+	return img, nil
+}
@@ -0,0 +1,43 @@
+package rimage
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestToColormapPicture(t *testing.T) {
+	dm := NewEmptyDepthMap(3, 1)
+	dm.Set(1, 0, 1000)
+	dm.Set(2, 0, 3000)
+	invalid := NewColor(1, 2, 3)
+
+	// scaled to the depths of the map
+	img, err := dm.ToColormapPicture(DepthColorOptions{Colormap: DepthColormapGrayscale, InvalidColor: invalid})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, img.GetXY(0, 0), test.ShouldEqual, invalid)
+	test.That(t, img.GetXY(1, 0), test.ShouldEqual, NewColor(0, 0, 0))
+	test.That(t, img.GetXY(2, 0), test.ShouldEqual, NewColor(255, 255, 255))
+
+	// fixed depths, with the ones outside of them clamped
+	img, err = dm.ToColormapPicture(DepthColorOptions{Colormap: DepthColormapGrayscale, MinDepth: 2000, MaxDepth: 4000})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, img.GetXY(0, 0), test.ShouldEqual, NewColor(0, 0, 0))
+	test.That(t, img.GetXY(1, 0), test.ShouldEqual, NewColor(0, 0, 0))
+	test.That(t, img.GetXY(2, 0), test.ShouldEqual, NewColor(128, 128, 128))
+
+	for _, cm := range []DepthColormap{"", DepthColormapHue, DepthColormapJet, DepthColormapTurbo, DepthColormapViridis} {
+		img, err = dm.ToColormapPicture(DepthColorOptions{Colormap: cm})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, img.GetXY(1, 0), test.ShouldNotEqual, img.GetXY(2, 0))
+	}
+	img, err = dm.ToColormapPicture(DepthColorOptions{Colormap: DepthColormapViridis})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, img.GetXY(1, 0), test.ShouldEqual, NewColorFromHexOrPanic("#440154"))
+	test.That(t, img.GetXY(2, 0), test.ShouldEqual, NewColorFromHexOrPanic("#fde725"))
+
+	_, err = dm.ToColormapPicture(DepthColorOptions{Colormap: "rainbow"})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = dm.ToColormapPicture(DepthColorOptions{MinDepth: 3000, MaxDepth: 1000})
+	test.That(t, err, test.ShouldNotBeNil)
+}
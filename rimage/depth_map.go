@@ -225,27 +225,28 @@ func (dm *DepthMap) ToPrettyPicture(hardMin, hardMax Depth) *Image {
 
 	span := float64(max) - float64(min)
 
-	for x := 0; x < dm.Width(); x++ {
-		for y := 0; y < dm.Height(); y++ {
-			p := image.Point{x, y}
-			z := dm.Get(p)
-			if z == 0 {
-				continue
-			}
+	parallelRows(dm.Width(), dm.Height(), func(yStart, yEnd int) {
+		for y := yStart; y < yEnd; y++ {
+			for x := 0; x < dm.Width(); x++ {
+				z := dm.GetDepth(x, y)
+				if z == 0 {
+					continue
+				}
 
-			if z < min {
-				z = min
-			}
-			if z > max {
-				z = max
-			}
+				if z < min {
+					z = min
+				}
+				if z > max {
+					z = max
+				}
 
-			ratio := float64(z-min) / span
+				ratio := float64(z-min) / span
 
-			hue := 30 + (200.0 * ratio)
-			img.SetXY(x, y, NewColorFromHSV(hue, 1.0, 1.0))
+				hue := 30 + (200.0 * ratio)
+				img.SetXY(x, y, NewColorFromHSV(hue, 1.0, 1.0))
+			}
 		}
-	}
+	})
 
 	return img
 }
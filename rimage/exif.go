@@ -0,0 +1,217 @@
+package rimage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The EXIF tags ImageMetadata is read from and written to.
+const (
+	exifTagMake               = 0x010f
+	exifTagModel              = 0x0110
+	exifTagOrientation        = 0x0112
+	exifTagDateTime           = 0x0132
+	exifTagExifIFD            = 0x8769
+	exifTagDateTimeOriginal   = 0x9003
+	exifTagOffsetTime         = 0x9010
+	exifTagOffsetTimeOriginal = 0x9011
+	exifTagSubSecTimeOriginal = 0x9291
+)
+
+// The EXIF types of the tags above.
+const (
+	exifTypeASCII = 2
+	exifTypeShort = 3
+	exifTypeLong  = 4
+)
+
+const (
+	exifDateTimeLayout = "2006:01:02 15:04:05"
+	exifOffsetLayout   = "-07:00"
+)
+
+type exifEntry struct {
+	tag, typ uint16
+	count    uint32
+	value    []byte
+}
+
+func exifASCII(tag uint16, s string) exifEntry {
+	return exifEntry{tag: tag, typ: exifTypeASCII, count: uint32(len(s) + 1), value: append([]byte(s), 0)}
+}
+
+// encodeEXIF returns the EXIF fields of the metadata as a little endian TIFF structure, or nil if it has none.
+func encodeEXIF(md ImageMetadata) []byte {
+	var ifd0, exifIFD []exifEntry
+	if md.CameraMake != "" {
+		ifd0 = append(ifd0, exifASCII(exifTagMake, md.CameraMake))
+	}
+	if md.CameraModel != "" {
+		ifd0 = append(ifd0, exifASCII(exifTagModel, md.CameraModel))
+	}
+	if md.Orientation != 0 {
+		value := make([]byte, 2)
+		binary.LittleEndian.PutUint16(value, uint16(md.Orientation))
+		ifd0 = append(ifd0, exifEntry{tag: exifTagOrientation, typ: exifTypeShort, count: 1, value: value})
+	}
+	if !md.Time.IsZero() {
+		dateTime := md.Time.Format(exifDateTimeLayout)
+		offset := md.Time.Format(exifOffsetLayout)
+		ifd0 = append(ifd0, exifASCII(exifTagDateTime, dateTime))
+		exifIFD = append(exifIFD, exifASCII(exifTagDateTimeOriginal, dateTime))
+		exifIFD = append(exifIFD, exifASCII(exifTagOffsetTime, offset), exifASCII(exifTagOffsetTimeOriginal, offset))
+		if nanos := md.Time.Nanosecond(); nanos != 0 {
+			exifIFD = append(exifIFD, exifASCII(exifTagSubSecTimeOriginal, strings.TrimRight(fmt.Sprintf("%09d", nanos), "0")))
+		}
+	}
+	if len(ifd0) == 0 && len(exifIFD) == 0 {
+		return nil
+	}
+
+	const ifd0Offset = 8
+	if len(exifIFD) != 0 {
+		// the pointer to the Exif IFD fits in its entry, so the size of IFD0 does not depend on it
+		ifd0 = append(ifd0, exifEntry{tag: exifTagExifIFD, typ: exifTypeLong, count: 1, value: make([]byte, 4)})
+		exifOffset := ifd0Offset + len(encodeIFD(ifd0, ifd0Offset))
+		binary.LittleEndian.PutUint32(ifd0[len(ifd0)-1].value, uint32(exifOffset))
+	}
+	tiff := []byte{'I', 'I', 42, 0, ifd0Offset, 0, 0, 0}
+	tiff = append(tiff, encodeIFD(ifd0, ifd0Offset)...)
+	if len(exifIFD) != 0 {
+		tiff = append(tiff, encodeIFD(exifIFD, uint32(len(tiff)))...)
+	}
+	return tiff
+}
+
+// encodeIFD returns an IFD of the entries, which must be sorted by tag, followed by the values that do not fit in
+// their entries, for an IFD at the given offset from the start of the TIFF structure.
+func encodeIFD(entries []exifEntry, offset uint32) []byte {
+	size := 2 + 12*len(entries) + 4
+	ifd := make([]byte, size)
+	binary.LittleEndian.PutUint16(ifd, uint16(len(entries)))
+	var values []byte
+	for i, entry := range entries {
+		e := ifd[2+12*i:]
+		binary.LittleEndian.PutUint16(e, entry.tag)
+		binary.LittleEndian.PutUint16(e[2:], entry.typ)
+		binary.LittleEndian.PutUint32(e[4:], entry.count)
+		if len(entry.value) <= 4 {
+			copy(e[8:12], entry.value)
+			continue
+		}
+		binary.LittleEndian.PutUint32(e[8:], offset+uint32(size+len(values)))
+		values = append(values, entry.value...)
+		// values start on word boundaries
+		if len(values)%2 == 1 {
+			values = append(values, 0)
+		}
+	}
+	return append(ifd, values...)
+}
+
+// decodeEXIF reads the metadata out of the fields of an EXIF TIFF structure, in either byte order.
+func decodeEXIF(tiff []byte) (ImageMetadata, error) {
+	var order binary.ByteOrder
+	switch {
+	case len(tiff) >= 8 && string(tiff[:4]) == "II*\x00":
+		order = binary.LittleEndian
+	case len(tiff) >= 8 && string(tiff[:4]) == "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return ImageMetadata{}, errors.New("EXIF data does not start with a TIFF header")
+	}
+	fields := map[uint16]exifEntry{}
+	if err := decodeIFD(tiff, order, order.Uint32(tiff[4:8]), fields); err != nil {
+		return ImageMetadata{}, err
+	}
+	if exifIFD, ok := fields[exifTagExifIFD]; ok && exifIFD.typ == exifTypeLong {
+		if err := decodeIFD(tiff, order, order.Uint32(exifIFD.value), fields); err != nil {
+			return ImageMetadata{}, err
+		}
+	}
+
+	ascii := func(tag uint16) string {
+		entry, ok := fields[tag]
+		if !ok || entry.typ != exifTypeASCII {
+			return ""
+		}
+		return strings.TrimSpace(strings.TrimRight(string(entry.value), "\x00"))
+	}
+	md := ImageMetadata{CameraMake: ascii(exifTagMake), CameraModel: ascii(exifTagModel)}
+	if orientation, ok := fields[exifTagOrientation]; ok && orientation.typ == exifTypeShort {
+		md.Orientation = int(order.Uint16(orientation.value))
+	}
+	dateTime, offset := ascii(exifTagDateTimeOriginal), ascii(exifTagOffsetTimeOriginal)
+	if dateTime == "" {
+		dateTime, offset = ascii(exifTagDateTime), ascii(exifTagOffsetTime)
+	}
+	if dateTime != "" {
+		md.Time = parseEXIFTime(dateTime, offset, ascii(exifTagSubSecTimeOriginal))
+	}
+	return md, nil
+}
+
+// decodeIFD adds the entries of the IFD at the given offset to fields, with their values read from wherever they are.
+func decodeIFD(tiff []byte, order binary.ByteOrder, offset uint32, fields map[uint16]exifEntry) error {
+	if uint64(offset)+2 > uint64(len(tiff)) {
+		return errors.New("EXIF IFD is out of bounds")
+	}
+	n := int(order.Uint16(tiff[offset:]))
+	if uint64(offset)+2+12*uint64(n) > uint64(len(tiff)) {
+		return errors.New("EXIF IFD is truncated")
+	}
+	for i := 0; i < n; i++ {
+		e := tiff[int(offset)+2+12*i:]
+		entry := exifEntry{tag: order.Uint16(e), typ: order.Uint16(e[2:]), count: order.Uint32(e[4:])}
+		var size uint64
+		switch entry.typ {
+		case exifTypeASCII:
+			size = uint64(entry.count)
+		case exifTypeShort:
+			size = 2 * uint64(entry.count)
+		case exifTypeLong:
+			size = 4 * uint64(entry.count)
+		default:
+			// other types are not needed for the metadata
+			continue
+		}
+		if size <= 4 {
+			entry.value = e[8 : 8+size]
+		} else {
+			start := uint64(order.Uint32(e[8:]))
+			if start+size > uint64(len(tiff)) {
+				return errors.Errorf("value of EXIF tag %#x is out of bounds", entry.tag)
+			}
+			entry.value = tiff[start : start+size]
+		}
+		fields[entry.tag] = entry
+	}
+	return nil
+}
+
+// parseEXIFTime parses an EXIF date and time, which is read as UTC if there is no offset for it. Times that cannot be
+// parsed are left out.
+func parseEXIFTime(dateTime, offset, subSec string) time.Time {
+	loc := time.UTC
+	if offset != "" {
+		if t, err := time.Parse(exifOffsetLayout, offset); err == nil {
+			_, seconds := t.Zone()
+			loc = time.FixedZone("", seconds)
+		}
+	}
+	t, err := time.ParseInLocation(exifDateTimeLayout, dateTime, loc)
+	if err != nil {
+		return time.Time{}
+	}
+	if subSec != "" && len(subSec) <= 9 {
+		if nanos, err := strconv.Atoi(subSec + strings.Repeat("0", 9-len(subSec))); err == nil {
+			t = t.Add(time.Duration(nanos))
+		}
+	}
+	return t
+}
@@ -3,7 +3,6 @@ package rimage
 import (
 	"image"
 	"image/color"
-	"image/draw"
 
 	"github.com/pkg/errors"
 )
@@ -18,10 +17,18 @@ func SameImgSize(g1, g2 image.Image) bool {
 
 // MakeGray takes a rimage.Image and well... makes it gray (image.Gray).
 func MakeGray(pic *Image) *image.Gray {
-	// Converting image to grayscale
+	// Converting image to grayscale with the same weights as color.GrayModel
 	result := image.NewGray(pic.Bounds())
-	draw.Draw(result, result.Bounds(), pic, pic.Bounds().Min, draw.Src)
-
+	parallelRows(pic.width, pic.height, func(yStart, yEnd int) {
+		for y := yStart; y < yEnd; y++ {
+			row := result.Pix[y*result.Stride : y*result.Stride+pic.width]
+			for x := range row {
+				r, g, b := pic.GetXY(x, y).RGB255()
+				// 0x101 scales the 8 bit values to the 16 bit ones the weights are for
+				row[x] = uint8((19595*uint32(r)*0x101 + 38470*uint32(g)*0x101 + 7471*uint32(b)*0x101 + 1<<15) >> 24)
+			}
+		}
+	})
 	return result
 }
 
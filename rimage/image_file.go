@@ -20,10 +20,16 @@ import (
 	"go.opencensus.io/trace"
 	"go.uber.org/multierr"
 	"go.viam.com/utils"
+	// registers the WebP format with image.Decode.
+	_ "golang.org/x/image/webp"
 
 	ut "go.viam.com/rdk/utils"
 )
 
+// ErrEncodingUnsupported is returned by EncodeImage for MIME types that images can be decoded
+// from but not encoded to, such as WebP.
+var ErrEncodingUnsupported = errors.New("encoding is not supported")
+
 // RGBABitmapMagicNumber represents the magic number for our custom header
 // for raw RGBA data. The header is composed of this magic number followed by
 // a 4-byte line of the width as a uint32 number and another for the height. Credit to
@@ -286,24 +292,52 @@ func DecodeImage(ctx context.Context, imgBytes []byte, mimeType string) (image.I
 	}
 }
 
+// DecodeImageWithMetadata decodes an image as DecodeImage does, and also reads the metadata of JPEGs and PNGs.
+func DecodeImageWithMetadata(ctx context.Context, imgBytes []byte, mimeType string) (image.Image, ImageMetadata, error) {
+	img, err := DecodeImage(ctx, imgBytes, mimeType)
+	if err != nil {
+		return nil, ImageMetadata{}, err
+	}
+	var md ImageMetadata
+	if bytes.HasPrefix(imgBytes, jpegMagicNumber) || bytes.HasPrefix(imgBytes, pngMagicNumber) {
+		if md, err = ReadImageMetadata(imgBytes); err != nil {
+			return nil, ImageMetadata{}, err
+		}
+	}
+	return img, md, nil
+}
+
 // EncodeImage takes an image and mimeType as input and encodes it into a
-// slice of bytes (buffer) and returns the bytes.
+// slice of bytes (buffer) and returns the bytes. The metadata of images from
+// WithMetadata is written into the JPEGs and PNGs they are encoded to.
 func EncodeImage(ctx context.Context, img image.Image, mimeType string) ([]byte, error) {
 	_, span := trace.StartSpan(ctx, "rimage::EncodeImage::"+mimeType)
 	defer span.End()
 
 	actualOutMIME, _ := ut.CheckLazyMIMEType(mimeType)
 
+	if withMetadata, ok := img.(*imageWithMetadata); ok {
+		imgBytes, err := EncodeImage(ctx, withMetadata.Image, actualOutMIME)
+		if err != nil || (actualOutMIME != ut.MimeTypeJPEG && actualOutMIME != ut.MimeTypePNG) {
+			return imgBytes, err
+		}
+		return WriteImageMetadata(imgBytes, withMetadata.metadata)
+	}
+
 	if lazy, ok := img.(*LazyEncodedImage); ok {
 		if lazy.MIMEType() == actualOutMIME {
 			return lazy.imgBytes, nil
 		}
 		// LazyImage holds bytes different from requested mime type: decode and re-encode
-		lazy.decode()
-		if lazy.decodeErr != nil {
-			return nil, errors.Errorf("could not decode LazyEncodedImage: %v", lazy.decodeErr)
+		decoded, err := lazy.DecodedImage()
+		if err != nil {
+			return nil, errors.Errorf("could not decode LazyEncodedImage: %v", err)
 		}
-		return EncodeImage(ctx, lazy.decodedImage, actualOutMIME)
+		// keep the metadata of the bytes
+		if md := MetadataOf(lazy); !md.IsZero() {
+			decoded = WithMetadata(decoded, md)
+		}
+		return EncodeImage(ctx, decoded, actualOutMIME)
 	}
 	var buf bytes.Buffer
 	bounds := img.Bounds()
@@ -338,6 +372,8 @@ func EncodeImage(ctx context.Context, img image.Image, mimeType string) ([]byte,
 		if err := qoi.Encode(&buf, img); err != nil {
 			return nil, err
 		}
+	case ut.MimeTypeWebP:
+		return nil, errors.Wrapf(ErrEncodingUnsupported, "cannot encode %q", actualOutMIME)
 	default:
 		return nil, errors.Errorf("do not know how to encode %q", actualOutMIME)
 	}
@@ -346,61 +382,61 @@ func EncodeImage(ctx context.Context, img image.Image, mimeType string) ([]byte,
 }
 
 func fastConvertNRGBA(dst *Image, src *image.NRGBA) {
-	for y := 0; y < dst.height; y++ {
-		for x := 0; x < dst.width; x++ {
-			i := src.PixOffset(x, y)
-			s := src.Pix[i : i+3 : i+3] // Small cap improves performance, see https://golang.org/issue/27857
-			r, g, b := s[0], s[1], s[2]
-			dst.SetXY(x, y, NewColor(r, g, b))
+	parallelRows(dst.width, dst.height, func(yStart, yEnd int) {
+		for y := yStart; y < yEnd; y++ {
+			for x := 0; x < dst.width; x++ {
+				i := src.PixOffset(x, y)
+				s := src.Pix[i : i+3 : i+3] // Small cap improves performance, see https://golang.org/issue/27857
+				r, g, b := s[0], s[1], s[2]
+				dst.SetXY(x, y, NewColor(r, g, b))
+			}
 		}
-	}
+	})
 }
 
 func fastConvertRGBA(dst *Image, src *image.RGBA) {
-	for y := 0; y < dst.height; y++ {
-		for x := 0; x < dst.width; x++ {
-			i := src.PixOffset(x, y)
-			s := src.Pix[i : i+4 : i+4]
-			r, g, b, a := s[0], s[1], s[2], s[3]
-
-			if a == 255 {
-				dst.SetXY(x, y, NewColor(r, g, b))
-			} else {
-				dst.SetXY(x, y, NewColorFromColor(color.RGBA{r, g, b, a}))
+	parallelRows(dst.width, dst.height, func(yStart, yEnd int) {
+		for y := yStart; y < yEnd; y++ {
+			for x := 0; x < dst.width; x++ {
+				i := src.PixOffset(x, y)
+				s := src.Pix[i : i+4 : i+4]
+				r, g, b, a := s[0], s[1], s[2], s[3]
+
+				if a == 255 {
+					dst.SetXY(x, y, NewColor(r, g, b))
+				} else {
+					dst.SetXY(x, y, NewColorFromColor(color.RGBA{r, g, b, a}))
+				}
 			}
 		}
-	}
+	})
 }
 
 // ConvertToRGBA converts an rimage.Image type image to image.RGBA.
 func ConvertToRGBA(dst *image.RGBA, src *Image) {
-	for y := 0; y < src.height; y++ {
-		for x := 0; x < src.width; x++ {
-			c := src.At(x, y)
-			r, g, b, a := c.RGBA()
-			cRGBA := color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: uint8(a)}
-			dst.SetRGBA(x, y, cRGBA)
+	parallelRows(src.width, src.height, func(yStart, yEnd int) {
+		for y := yStart; y < yEnd; y++ {
+			for x := 0; x < src.width; x++ {
+				r, g, b := src.GetXY(x, y).RGB255()
+				i := dst.PixOffset(x, y)
+				s := dst.Pix[i : i+4 : i+4]
+				s[0], s[1], s[2], s[3] = r, g, b, 255
+			}
 		}
-	}
+	})
 }
 
 func fastConvertYcbcr(dst *Image, src *image.YCbCr) {
-	c := color.YCbCr{}
-
-	for y := 0; y < dst.height; y++ {
-		for x := 0; x < dst.width; x++ {
-			yi := src.YOffset(x, y)
-			ci := src.COffset(x, y)
-
-			c.Y = src.Y[yi]
-			c.Cb = src.Cb[ci]
-			c.Cr = src.Cr[ci]
-
-			r, g, b := color.YCbCrToRGB(c.Y, c.Cb, c.Cr)
-
-			dst.SetXY(x, y, NewColor(r, g, b))
+	parallelRows(dst.width, dst.height, func(yStart, yEnd int) {
+		for y := yStart; y < yEnd; y++ {
+			for x := 0; x < dst.width; x++ {
+				yi := src.YOffset(x, y)
+				ci := src.COffset(x, y)
+				r, g, b := color.YCbCrToRGB(src.Y[yi], src.Cb[ci], src.Cr[ci])
+				dst.SetXY(x, y, NewColor(r, g, b))
+			}
 		}
-	}
+	})
 }
 
 // IsImageFile returns if the given file is an image file based on what
@@ -3,6 +3,7 @@ package rimage
 import (
 	"bytes"
 	"context"
+	"errors"
 	"image"
 	"image/png"
 	"os"
@@ -79,6 +80,31 @@ func TestEncodeImage(t *testing.T) {
 	})
 }
 
+func TestWebP(t *testing.T) {
+	// a 1x1 transparent lossless WebP
+	webpBytes := []byte{
+		0x52, 0x49, 0x46, 0x46, 0x1a, 0x0, 0x0, 0x0, 0x57, 0x45, 0x42, 0x50, 0x56, 0x50, 0x38, 0x4c, 0xd, 0x0,
+		0x0, 0x0, 0x2f, 0x0, 0x0, 0x0, 0x10, 0x7, 0x10, 0x11, 0x11, 0x88, 0x88, 0xfe, 0x7, 0x0,
+	}
+	img, err := DecodeImage(context.Background(), webpBytes, utils.MimeTypeWebP)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, img.Bounds(), test.ShouldResemble, image.Rect(0, 0, 1, 1))
+
+	_, err = EncodeImage(context.Background(), img, utils.MimeTypeWebP)
+	test.That(t, errors.Is(err, ErrEncodingUnsupported), test.ShouldBeTrue)
+
+	// WebP images that are never decoded are passed through as they are
+	lazyImg := NewLazyEncodedImage(webpBytes, utils.MimeTypeWebP)
+	encoded, err := EncodeImage(context.Background(), lazyImg, utils.MimeTypeWebP)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encoded, test.ShouldResemble, webpBytes)
+	encoded, err = EncodeImage(context.Background(), lazyImg, utils.MimeTypePNG)
+	test.That(t, err, test.ShouldBeNil)
+	decoded, err := DecodeImage(context.Background(), encoded, utils.MimeTypePNG)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, decoded.Bounds(), test.ShouldResemble, image.Rect(0, 0, 1, 1))
+}
+
 func TestRawRGBAEncodingDecoding(t *testing.T) {
 	img := image.NewNRGBA(image.Rect(0, 0, 4, 8))
 	img.Set(3, 3, Red)
@@ -0,0 +1,134 @@
+package rimage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	pngMagicNumber  = []byte("\x89PNG\r\n\x1a\n")
+	jpegMagicNumber = []byte{0xff, 0xd8}
+	qoiMagicNumber  = []byte("qoif")
+)
+
+// errUnknownImageHeader is returned by DecodeImageHeader for images it cannot read the header of.
+var errUnknownImageHeader = errors.New("not a JPEG, PNG or QOI image")
+
+// DecodeImageHeader returns the dimensions and color model of a JPEG, PNG or QOI image by reading the
+// fields of its header in place, without allocating a decoder or reading the image data. The format
+// is recognized by its magic number rather than a MIME type. Paletted PNGs, whose color model is only
+// known once their palette is read, and other formats are not supported and return an error.
+func DecodeImageHeader(imgBytes []byte) (image.Config, error) {
+	switch {
+	case bytes.HasPrefix(imgBytes, pngMagicNumber):
+		return decodePNGHeader(imgBytes)
+	case bytes.HasPrefix(imgBytes, jpegMagicNumber):
+		return decodeJPEGHeader(imgBytes)
+	case bytes.HasPrefix(imgBytes, qoiMagicNumber):
+		return decodeQOIHeader(imgBytes)
+	default:
+		return image.Config{}, errUnknownImageHeader
+	}
+}
+
+// decodePNGHeader reads the IHDR chunk, which must come first.
+func decodePNGHeader(imgBytes []byte) (image.Config, error) {
+	// magic number, chunk length, chunk type, width, height, bit depth and color type
+	const ihdrEnd = 8 + 4 + 4 + 4 + 4 + 1 + 1
+	if len(imgBytes) < ihdrEnd || string(imgBytes[12:16]) != "IHDR" {
+		return image.Config{}, errors.New("PNG header is missing its IHDR chunk")
+	}
+	width := binary.BigEndian.Uint32(imgBytes[16:20])
+	height := binary.BigEndian.Uint32(imgBytes[20:24])
+	bitDepth, colorType := imgBytes[24], imgBytes[25]
+
+	var model color.Model
+	switch colorType {
+	case 0: // grayscale
+		model = color.GrayModel
+		if bitDepth == 16 {
+			model = color.Gray16Model
+		}
+	case 2: // truecolor
+		model = color.RGBAModel
+		if bitDepth == 16 {
+			model = color.RGBA64Model
+		}
+	case 4, 6: // grayscale or truecolor with alpha
+		model = color.NRGBAModel
+		if bitDepth == 16 {
+			model = color.NRGBA64Model
+		}
+	default:
+		return image.Config{}, errors.Errorf("unsupported PNG color type %d", colorType)
+	}
+	return image.Config{ColorModel: model, Width: int(width), Height: int(height)}, nil
+}
+
+// decodeJPEGHeader skips over the segments of a JPEG until its start of frame segment.
+func decodeJPEGHeader(imgBytes []byte) (image.Config, error) {
+	i := len(jpegMagicNumber)
+	for {
+		// markers may be preceded by any number of fill bytes
+		start := i
+		for i < len(imgBytes) && imgBytes[i] == 0xff {
+			i++
+		}
+		if i == start || i >= len(imgBytes) {
+			return image.Config{}, errors.New("JPEG header ended before its start of frame")
+		}
+		marker := imgBytes[i]
+		i++
+		// markers without a segment
+		if marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7) {
+			continue
+		}
+		if i+2 > len(imgBytes) {
+			return image.Config{}, errors.New("JPEG header ended before its start of frame")
+		}
+		length := int(binary.BigEndian.Uint16(imgBytes[i : i+2]))
+		// start of frame markers, other than those for huffman tables, arithmetic coding and extensions
+		if marker >= 0xc0 && marker <= 0xcf && marker != 0xc4 && marker != 0xc8 && marker != 0xcc {
+			// length, precision, height, width and number of components
+			if length < 8 || i+8 > len(imgBytes) {
+				return image.Config{}, errors.New("JPEG start of frame is too short")
+			}
+			height := int(binary.BigEndian.Uint16(imgBytes[i+3 : i+5]))
+			width := int(binary.BigEndian.Uint16(imgBytes[i+5 : i+7]))
+			if height == 0 {
+				return image.Config{}, errors.New("JPEG height is only given after the image data")
+			}
+			var model color.Model
+			switch components := imgBytes[i+7]; components {
+			case 1:
+				model = color.GrayModel
+			case 3:
+				model = color.YCbCrModel
+			case 4:
+				model = color.CMYKModel
+			default:
+				return image.Config{}, errors.Errorf("unsupported number of JPEG components %d", components)
+			}
+			return image.Config{ColorModel: model, Width: width, Height: height}, nil
+		}
+		i += length
+	}
+}
+
+// decodeQOIHeader reads the fixed size QOI header.
+func decodeQOIHeader(imgBytes []byte) (image.Config, error) {
+	// magic number, width, height, channels and colorspace
+	const headerLength = 4 + 4 + 4 + 1 + 1
+	if len(imgBytes) < headerLength {
+		return image.Config{}, errors.New("QOI header is too short")
+	}
+	return image.Config{
+		ColorModel: color.NRGBAModel,
+		Width:      int(binary.BigEndian.Uint32(imgBytes[4:8])),
+		Height:     int(binary.BigEndian.Uint32(imgBytes[8:12])),
+	}, nil
+}
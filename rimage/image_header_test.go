@@ -0,0 +1,64 @@
+package rimage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/xfmoulet/qoi"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/utils"
+)
+
+func TestDecodeImageHeader(t *testing.T) {
+	rect := image.Rect(0, 0, 33, 17)
+	encode := func(encoder func(*bytes.Buffer) error) []byte {
+		var buf bytes.Buffer
+		test.That(t, encoder(&buf), test.ShouldBeNil)
+		return buf.Bytes()
+	}
+	images := map[string][]byte{
+		"png nrgba": encode(func(buf *bytes.Buffer) error { return png.Encode(buf, image.NewNRGBA(rect)) }),
+		"png gray":  encode(func(buf *bytes.Buffer) error { return png.Encode(buf, image.NewGray16(rect)) }),
+		"jpeg color": encode(func(buf *bytes.Buffer) error {
+			return jpeg.Encode(buf, image.NewRGBA(rect), nil)
+		}),
+		"jpeg gray": encode(func(buf *bytes.Buffer) error { return jpeg.Encode(buf, image.NewGray(rect), nil) }),
+		"qoi":       encode(func(buf *bytes.Buffer) error { return qoi.Encode(buf, image.NewNRGBA(rect)) }),
+	}
+	for name, imgBytes := range images {
+		t.Run(name, func(t *testing.T) {
+			expected, _, err := image.DecodeConfig(bytes.NewReader(imgBytes))
+			test.That(t, err, test.ShouldBeNil)
+			cfg, err := DecodeImageHeader(imgBytes)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, cfg.Width, test.ShouldEqual, 33)
+			test.That(t, cfg.Height, test.ShouldEqual, 17)
+			test.That(t, cfg.ColorModel, test.ShouldEqual, expected.ColorModel)
+
+			_, err = DecodeImageHeader(imgBytes[:10])
+			test.That(t, err, test.ShouldNotBeNil)
+		})
+	}
+
+	paletted := encode(func(buf *bytes.Buffer) error { return png.Encode(buf, image.NewPaletted(rect, palette.Plan9)) })
+	_, err := DecodeImageHeader(paletted)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = DecodeImageHeader([]byte{1, 2, 3})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// lazy images get their bounds without decoding, and fall back to the standard decoders for other formats
+	lazy := NewLazyEncodedImage(images["jpeg color"], utils.MimeTypeJPEG).(*LazyEncodedImage)
+	test.That(t, lazy.Bounds(), test.ShouldResemble, rect)
+	test.That(t, lazy.decodedImage, test.ShouldBeNil)
+	lazy = NewLazyEncodedImage(paletted, utils.MimeTypePNG).(*LazyEncodedImage)
+	cfg, err := lazy.DecodeConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.ColorModel, test.ShouldHaveSameTypeAs, color.Palette{})
+	test.That(t, lazy.Bounds(), test.ShouldResemble, rect)
+}
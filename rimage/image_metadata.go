@@ -0,0 +1,298 @@
+package rimage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"image"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ImageMetadata describes where an image came from. The standard fields are stored as EXIF, and Extra, which can
+// record anything else such as the component that captured the image, is stored alongside it. JPEG and PNG images
+// can hold metadata.
+type ImageMetadata struct {
+	// Orientation is the EXIF orientation of the image from 1 to 8, where 1 is upright, or 0 if it is unknown.
+	Orientation int
+	// Time is when the image was captured.
+	Time        time.Time
+	CameraMake  string
+	CameraModel string
+	Extra       map[string]string
+}
+
+// IsZero returns whether the metadata has no fields set.
+func (md ImageMetadata) IsZero() bool {
+	return md.Orientation == 0 && md.Time.IsZero() && md.CameraMake == "" && md.CameraModel == "" && len(md.Extra) == 0
+}
+
+// imageWithMetadata is an image with metadata to encode with it.
+type imageWithMetadata struct {
+	image.Image
+	metadata ImageMetadata
+}
+
+// WithMetadata returns the image with metadata that EncodeImage writes into the JPEGs and PNGs it encodes the image
+// to. Other formats leave the metadata out.
+func WithMetadata(img image.Image, md ImageMetadata) image.Image {
+	if withMetadata, ok := img.(*imageWithMetadata); ok {
+		img = withMetadata.Image
+	}
+	return &imageWithMetadata{Image: img, metadata: md}
+}
+
+// MetadataOf returns the metadata of an image, which is either given to it by WithMetadata or, for a
+// LazyEncodedImage, read from its bytes.
+func MetadataOf(img image.Image) ImageMetadata {
+	switch v := img.(type) {
+	case *imageWithMetadata:
+		return v.metadata
+	case *LazyEncodedImage:
+		md, err := ReadImageMetadata(v.imgBytes)
+		if err != nil {
+			return ImageMetadata{}
+		}
+		return md
+	default:
+		return ImageMetadata{}
+	}
+}
+
+// metadataCommentPrefix starts the JPEG comment and is the keyword of the PNG text chunk that hold the Extra field of
+// ImageMetadata, encoded as JSON.
+const metadataCommentPrefix = "viam-metadata"
+
+var (
+	exifHeader         = []byte("Exif\x00\x00")
+	jpegCommentPrefix  = []byte(metadataCommentPrefix + "\x00")
+	pngTextChunkPrefix = []byte(metadataCommentPrefix + "\x00\x00\x00\x00\x00")
+)
+
+const (
+	jpegMarkerAPP0 = 0xe0
+	jpegMarkerAPP1 = 0xe1
+	jpegMarkerCOM  = 0xfe
+	jpegMarkerSOS  = 0xda
+	// jpegMaxSegmentData is the most data a JPEG segment can hold after its length.
+	jpegMaxSegmentData = 0xffff - 2
+)
+
+// ReadImageMetadata reads the metadata of an encoded JPEG or PNG image. Images without metadata have empty metadata.
+func ReadImageMetadata(imgBytes []byte) (ImageMetadata, error) {
+	var exif, extra []byte
+	switch {
+	case bytes.HasPrefix(imgBytes, jpegMagicNumber):
+		segments, _, err := jpegSegments(imgBytes)
+		if err != nil {
+			return ImageMetadata{}, err
+		}
+		for _, s := range segments {
+			switch {
+			case s.marker == jpegMarkerAPP1 && bytes.HasPrefix(s.data, exifHeader):
+				exif = s.data[len(exifHeader):]
+			case s.marker == jpegMarkerCOM && bytes.HasPrefix(s.data, jpegCommentPrefix):
+				extra = s.data[len(jpegCommentPrefix):]
+			}
+		}
+	case bytes.HasPrefix(imgBytes, pngMagicNumber):
+		chunks, err := pngChunks(imgBytes)
+		if err != nil {
+			return ImageMetadata{}, err
+		}
+		for _, c := range chunks {
+			switch {
+			case c.typ == "eXIf":
+				exif = c.data
+			case c.typ == "iTXt" && bytes.HasPrefix(c.data, pngTextChunkPrefix):
+				extra = c.data[len(pngTextChunkPrefix):]
+			}
+		}
+	default:
+		return ImageMetadata{}, errors.New("only JPEG and PNG images can hold metadata")
+	}
+
+	var md ImageMetadata
+	if exif != nil {
+		var err error
+		if md, err = decodeEXIF(exif); err != nil {
+			return ImageMetadata{}, err
+		}
+	}
+	if extra != nil {
+		if err := json.Unmarshal(extra, &md.Extra); err != nil {
+			return ImageMetadata{}, errors.Wrap(err, "could not read extra image metadata")
+		}
+	}
+	return md, nil
+}
+
+// WriteImageMetadata returns an encoded JPEG or PNG image with the given metadata in place of any it had.
+func WriteImageMetadata(imgBytes []byte, md ImageMetadata) ([]byte, error) {
+	exif := encodeEXIF(md)
+	var extra []byte
+	if len(md.Extra) != 0 {
+		var err error
+		if extra, err = json.Marshal(md.Extra); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case bytes.HasPrefix(imgBytes, jpegMagicNumber):
+		return writeJPEGMetadata(imgBytes, exif, extra)
+	case bytes.HasPrefix(imgBytes, pngMagicNumber):
+		return writePNGMetadata(imgBytes, exif, extra)
+	default:
+		return nil, errors.New("only JPEG and PNG images can hold metadata")
+	}
+}
+
+// jpegSegment is a segment of the header of a JPEG, from its marker to the end of its data.
+type jpegSegment struct {
+	marker     byte
+	start, end int
+	data       []byte
+}
+
+// jpegSegments returns the segments of a JPEG before its image data, and the offset its image data starts at.
+func jpegSegments(imgBytes []byte) ([]jpegSegment, int, error) {
+	var segments []jpegSegment
+	i := len(jpegMagicNumber)
+	for {
+		start := i
+		// markers may be preceded by any number of fill bytes
+		for i < len(imgBytes) && imgBytes[i] == 0xff {
+			i++
+		}
+		if i == start || i+3 > len(imgBytes) {
+			return nil, 0, errors.New("JPEG ends before its image data")
+		}
+		marker := imgBytes[i]
+		if marker == jpegMarkerSOS {
+			return segments, start, nil
+		}
+		length := int(binary.BigEndian.Uint16(imgBytes[i+1:]))
+		end := i + 1 + length
+		if length < 2 || end > len(imgBytes) {
+			return nil, 0, errors.New("JPEG segment is truncated")
+		}
+		segments = append(segments, jpegSegment{marker: marker, start: start, end: end, data: imgBytes[i+3 : end]})
+		i = end
+	}
+}
+
+func writeJPEGMetadata(imgBytes, exif, extra []byte) ([]byte, error) {
+	segments, imageData, err := jpegSegments(imgBytes)
+	if err != nil {
+		return nil, err
+	}
+	var metadata []byte
+	if exif != nil {
+		if metadata, err = appendJPEGSegment(metadata, jpegMarkerAPP1, exifHeader, exif); err != nil {
+			return nil, err
+		}
+	}
+	if extra != nil {
+		if metadata, err = appendJPEGSegment(metadata, jpegMarkerCOM, jpegCommentPrefix, extra); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]byte, 0, len(imgBytes)+len(metadata))
+	out = append(out, jpegMagicNumber...)
+	written := false
+	for _, s := range segments {
+		// the metadata goes after the JFIF header, which must come first
+		if !written && s.marker != jpegMarkerAPP0 {
+			out = append(out, metadata...)
+			written = true
+		}
+		if (s.marker == jpegMarkerAPP1 && bytes.HasPrefix(s.data, exifHeader)) ||
+			(s.marker == jpegMarkerCOM && bytes.HasPrefix(s.data, jpegCommentPrefix)) {
+			continue
+		}
+		out = append(out, imgBytes[s.start:s.end]...)
+	}
+	if !written {
+		out = append(out, metadata...)
+	}
+	return append(out, imgBytes[imageData:]...), nil
+}
+
+func appendJPEGSegment(out []byte, marker byte, prefix, data []byte) ([]byte, error) {
+	length := len(prefix) + len(data)
+	if length > jpegMaxSegmentData {
+		return nil, errors.Errorf("image metadata of %d bytes does not fit in a JPEG segment", length)
+	}
+	out = append(out, 0xff, marker, byte((length+2)>>8), byte(length+2))
+	out = append(out, prefix...)
+	return append(out, data...), nil
+}
+
+// pngChunk is a chunk of a PNG, from its length to its CRC.
+type pngChunk struct {
+	typ        string
+	start, end int
+	data       []byte
+}
+
+func pngChunks(imgBytes []byte) ([]pngChunk, error) {
+	var chunks []pngChunk
+	for i := len(pngMagicNumber); i < len(imgBytes); {
+		if i+8 > len(imgBytes) {
+			return nil, errors.New("PNG chunk is truncated")
+		}
+		length := uint64(binary.BigEndian.Uint32(imgBytes[i:]))
+		end := uint64(i) + 12 + length
+		if end > uint64(len(imgBytes)) {
+			return nil, errors.New("PNG chunk is truncated")
+		}
+		chunks = append(chunks, pngChunk{
+			typ:   string(imgBytes[i+4 : i+8]),
+			start: i,
+			end:   int(end),
+			data:  imgBytes[i+8 : int(end)-4],
+		})
+		i = int(end)
+	}
+	if len(chunks) == 0 || chunks[0].typ != "IHDR" {
+		return nil, errors.New("PNG header is missing its IHDR chunk")
+	}
+	return chunks, nil
+}
+
+func writePNGMetadata(imgBytes, exif, extra []byte) ([]byte, error) {
+	chunks, err := pngChunks(imgBytes)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(imgBytes)+len(exif)+len(extra)+64)
+	out = append(out, pngMagicNumber...)
+	for i, c := range chunks {
+		if c.typ == "eXIf" || (c.typ == "iTXt" && bytes.HasPrefix(c.data, pngTextChunkPrefix)) {
+			continue
+		}
+		out = append(out, imgBytes[c.start:c.end]...)
+		// the metadata goes right after the IHDR chunk, before the image data
+		if i == 0 {
+			if exif != nil {
+				out = appendPNGChunk(out, "eXIf", exif)
+			}
+			if extra != nil {
+				out = appendPNGChunk(out, "iTXt", append(append([]byte{}, pngTextChunkPrefix...), extra...))
+			}
+		}
+	}
+	return out, nil
+}
+
+func appendPNGChunk(out []byte, typ string, data []byte) []byte {
+	out = binary.BigEndian.AppendUint32(out, uint32(len(data)))
+	start := len(out)
+	out = append(out, typ...)
+	out = append(out, data...)
+	return binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(out[start:]))
+}
@@ -0,0 +1,86 @@
+package rimage
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/utils"
+)
+
+func TestImageMetadata(t *testing.T) {
+	ctx := context.Background()
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 8))
+	img.Set(3, 3, Red)
+	md := ImageMetadata{
+		Orientation: 6,
+		Time:        time.Date(2023, 5, 6, 7, 8, 9, 123000000, time.FixedZone("", 2*60*60)),
+		CameraMake:  "Viam",
+		CameraModel: "webcam",
+		Extra:       map[string]string{"component_name": "camera1", "note": "déjà vu"},
+	}
+
+	for _, mimeType := range []string{utils.MimeTypeJPEG, utils.MimeTypePNG} {
+		imgBytes, err := EncodeImage(ctx, WithMetadata(img, md), mimeType)
+		test.That(t, err, test.ShouldBeNil)
+		decoded, got, err := DecodeImageWithMetadata(ctx, imgBytes, mimeType)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, decoded.Bounds(), test.ShouldResemble, img.Bounds())
+		test.That(t, got.Time.Equal(md.Time), test.ShouldBeTrue)
+		got.Time = md.Time
+		test.That(t, got, test.ShouldResemble, md)
+
+		// metadata is replaced rather than added to
+		rewritten, err := WriteImageMetadata(imgBytes, ImageMetadata{Orientation: 1})
+		test.That(t, err, test.ShouldBeNil)
+		got, err = ReadImageMetadata(rewritten)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, got, test.ShouldResemble, ImageMetadata{Orientation: 1})
+		_, err = DecodeImage(ctx, rewritten, mimeType)
+		test.That(t, err, test.ShouldBeNil)
+
+		// lazy images keep their metadata when they are encoded to another format
+		lazy := NewLazyEncodedImage(imgBytes, mimeType)
+		test.That(t, MetadataOf(lazy).CameraModel, test.ShouldEqual, "webcam")
+		otherType := utils.MimeTypePNG
+		if mimeType == utils.MimeTypePNG {
+			otherType = utils.MimeTypeJPEG
+		}
+		reencoded, err := EncodeImage(ctx, lazy, otherType)
+		test.That(t, err, test.ShouldBeNil)
+		got, err = ReadImageMetadata(reencoded)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, got.Extra, test.ShouldResemble, md.Extra)
+	}
+
+	// images without metadata have none, and formats that cannot hold it leave it out
+	plain, err := EncodeImage(ctx, img, utils.MimeTypePNG)
+	test.That(t, err, test.ShouldBeNil)
+	got, err := ReadImageMetadata(plain)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, got.IsZero(), test.ShouldBeTrue)
+	qoiBytes, err := EncodeImage(ctx, WithMetadata(img, md), utils.MimeTypeQOI)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = ReadImageMetadata(qoiBytes)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestDecodeBigEndianEXIF(t *testing.T) {
+	tiff := []byte{
+		'M', 'M', 0, 42, 0, 0, 0, 8,
+		0, 1, // one entry
+		0x01, 0x12, 0, exifTypeShort, 0, 0, 0, 1, 0, 3, 0, 0, // orientation 3
+		0, 0, 0, 0,
+	}
+	md, err := decodeEXIF(tiff)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, md.Orientation, test.ShouldEqual, 3)
+
+	_, err = decodeEXIF(tiff[:12])
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = decodeEXIF([]byte("not exif"))
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -1,7 +1,9 @@
 package rimage
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"image"
 	"image/color"
 	"sync"
@@ -13,15 +15,19 @@ type LazyEncodedImage struct {
 	mimeType string
 
 	decodeOnce   sync.Once
-	decodeErr    interface{}
+	decodeErr    error
 	decodedImage image.Image
+
+	decodeConfigOnce sync.Once
+	decodeConfigErr  error
+	decodedConfig    image.Config
 }
 
 // NewLazyEncodedImage returns a new image that will only get decoded once actual data is needed
-// from it. This is helpful for zero copy scenarios. If a width or height of the image is unknown,
-// pass 0 or -1; when done a decode will happen on Bounds. In the future this can probably go
-// away with reading all metadata from the header of the image bytes.
-// NOTE: Usage of an image that would fail to decode causes a lazy panic.
+// from it. This is helpful for zero copy scenarios.
+// NOTE: Usage of an image that would fail to decode through the image.Image methods causes a lazy
+// panic. Callers that may be handed corrupt bytes should use DecodedImage, DecodeConfig,
+// DecodedBounds or DecodedAt, which report the error instead.
 func NewLazyEncodedImage(imgBytes []byte, mimeType string) image.Image {
 	return &LazyEncodedImage{
 		imgBytes: imgBytes,
@@ -32,8 +38,12 @@ func NewLazyEncodedImage(imgBytes []byte, mimeType string) image.Image {
 func (lei *LazyEncodedImage) decode() {
 	lei.decodeOnce.Do(func() {
 		defer func() {
-			if err := recover(); err != nil {
-				lei.decodeErr = err
+			if r := recover(); r != nil {
+				if err, ok := r.(error); ok {
+					lei.decodeErr = err
+				} else {
+					lei.decodeErr = fmt.Errorf("%v", r)
+				}
 			}
 		}()
 		lei.decodedImage, lei.decodeErr = DecodeImage(
@@ -42,9 +52,56 @@ func (lei *LazyEncodedImage) decode() {
 			lei.mimeType,
 		)
 	})
-	if lei.decodeErr != nil {
-		panic(lei.decodeErr)
+}
+
+// mustDecode decodes the image and panics if it cannot be, since the image.Image methods have
+// no way to return an error.
+func (lei *LazyEncodedImage) mustDecode() {
+	if _, err := lei.DecodedImage(); err != nil {
+		panic(err)
+	}
+}
+
+// DecodedImage returns the decoded image, decoding it the first time it is called, or the error
+// the bytes failed to decode with.
+func (lei *LazyEncodedImage) DecodedImage() (image.Image, error) {
+	lei.decode()
+	return lei.decodedImage, lei.decodeErr
+}
+
+// DecodeConfig returns the color model and dimensions of the image from its header, without
+// decoding the rest of it. The headers of JPEG, PNG and QOI images are read in place.
+func (lei *LazyEncodedImage) DecodeConfig() (image.Config, error) {
+	lei.decodeConfigOnce.Do(func() {
+		lei.decodedConfig, lei.decodeConfigErr = DecodeImageHeader(lei.imgBytes)
+		if lei.decodeConfigErr != nil {
+			lei.decodedConfig, _, lei.decodeConfigErr = image.DecodeConfig(bytes.NewReader(lei.imgBytes))
+		}
+	})
+	return lei.decodedConfig, lei.decodeConfigErr
+}
+
+// DecodedBounds is like Bounds but returns an error rather than panicking if the image cannot
+// be decoded. The bounds are read from the header of the image where possible.
+func (lei *LazyEncodedImage) DecodedBounds() (image.Rectangle, error) {
+	if cfg, err := lei.DecodeConfig(); err == nil {
+		return image.Rect(0, 0, cfg.Width, cfg.Height), nil
 	}
+	img, err := lei.DecodedImage()
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	return img.Bounds(), nil
+}
+
+// DecodedAt is like At but returns an error rather than panicking if the image cannot be
+// decoded.
+func (lei *LazyEncodedImage) DecodedAt(x, y int) (color.Color, error) {
+	img, err := lei.DecodedImage()
+	if err != nil {
+		return nil, err
+	}
+	return img.At(x, y), nil
 }
 
 // MIMEType returns the encoded Image's MIME type.
@@ -60,23 +117,26 @@ func (lei *LazyEncodedImage) RawData() []byte {
 
 // ColorModel returns the Image's color model.
 func (lei *LazyEncodedImage) ColorModel() color.Model {
-	lei.decode()
+	lei.mustDecode()
 	return lei.decodedImage.ColorModel()
 }
 
 // Bounds returns the domain for which At can return non-zero color.
 // The bounds do not necessarily contain the point (0, 0).
+// The bounds are read from the header of the image where possible, so getting them does not
+// decode the image.
 func (lei *LazyEncodedImage) Bounds() image.Rectangle {
-	if lei.decodedImage == nil {
-		lei.decode()
+	bounds, err := lei.DecodedBounds()
+	if err != nil {
+		panic(err)
 	}
-	return lei.decodedImage.Bounds()
+	return bounds
 }
 
 // At returns the color of the pixel at (x, y).
 // At(Bounds().Min.X, Bounds().Min.Y) returns the upper-left pixel of the grid.
 // At(Bounds().Max.X-1, Bounds().Max.Y-1) returns the lower-right one.
 func (lei *LazyEncodedImage) At(x, y int) color.Color {
-	lei.decode()
+	lei.mustDecode()
 	return lei.decodedImage.At(x, y)
 }
@@ -2,6 +2,7 @@ package rimage
 
 import (
 	"bytes"
+	"context"
 	"image"
 	"image/png"
 	"testing"
@@ -30,9 +31,31 @@ func TestLazyEncodedImage(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, img2, test.ShouldResemble, img)
 
+	bounds, err := imgLazy.(*LazyEncodedImage).DecodedBounds()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, bounds, test.ShouldResemble, img.Bounds())
+	cfg, err := imgLazy.(*LazyEncodedImage).DecodeConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Width, test.ShouldEqual, 4)
+	test.That(t, cfg.Height, test.ShouldEqual, 8)
+	c, err := imgLazy.(*LazyEncodedImage).DecodedAt(3, 3)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, NewColorFromColor(c), test.ShouldEqual, Red)
+
 	// a bad image though :(
 	imgLazy = NewLazyEncodedImage([]byte{1, 2, 3}, utils.MimeTypePNG)
 
+	_, err = imgLazy.(*LazyEncodedImage).DecodedImage()
+	test.That(t, err, test.ShouldBeError, image.ErrFormat)
+	_, err = imgLazy.(*LazyEncodedImage).DecodeConfig()
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = imgLazy.(*LazyEncodedImage).DecodedBounds()
+	test.That(t, err, test.ShouldBeError, image.ErrFormat)
+	_, err = imgLazy.(*LazyEncodedImage).DecodedAt(0, 0)
+	test.That(t, err, test.ShouldBeError, image.ErrFormat)
+	_, err = EncodeImage(context.Background(), imgLazy, utils.MimeTypeJPEG)
+	test.That(t, err, test.ShouldNotBeNil)
+
 	test.That(t, imgLazy.(*LazyEncodedImage).MIMEType(), test.ShouldEqual, utils.MimeTypePNG)
 	test.That(t, func() { imgLazy.Bounds() }, test.ShouldPanic)
 	test.That(t, func() { imgLazy.ColorModel() }, test.ShouldPanicWith, image.ErrFormat)
@@ -0,0 +1,41 @@
+package rimage
+
+import (
+	"sync"
+
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/utils"
+)
+
+// minParallelPixels is the size of image below which conversions run on a single goroutine, as
+// splitting up smaller ones costs more than it saves.
+const minParallelPixels = 160 * 120
+
+// parallelRows calls f concurrently over bands of the rows of an image of the given size, so
+// that per pixel conversions use every core. Each call is given the rows [yStart, yEnd) and is
+// the only one to get them, so it can write to them without synchronizing.
+func parallelRows(width, height int, f func(yStart, yEnd int)) {
+	bands := utils.ParallelFactor
+	if bands > height {
+		bands = height
+	}
+	if bands < 2 || width*height < minParallelPixels {
+		f(0, height)
+		return
+	}
+	rowsPerBand := (height + bands - 1) / bands
+	var wg sync.WaitGroup
+	for yStart := 0; yStart < height; yStart += rowsPerBand {
+		yStart, yEnd := yStart, yStart+rowsPerBand
+		if yEnd > height {
+			yEnd = height
+		}
+		wg.Add(1)
+		goutils.PanicCapturingGo(func() {
+			defer wg.Done()
+			f(yStart, yEnd)
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,67 @@
+package rimage
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestParallelRows(t *testing.T) {
+	for _, size := range []image.Point{{1, 1}, {10, 7}, {640, 1}, {640, 481}} {
+		var mu sync.Mutex
+		visits := make([]int, size.Y)
+		parallelRows(size.X, size.Y, func(yStart, yEnd int) {
+			mu.Lock()
+			defer mu.Unlock()
+			for y := yStart; y < yEnd; y++ {
+				visits[y]++
+			}
+		})
+		for y := range visits {
+			test.That(t, visits[y], test.ShouldEqual, 1)
+		}
+	}
+}
+
+func TestParallelConversions(t *testing.T) {
+	// large enough to be split between goroutines
+	bounds := image.Rect(0, 0, 321, 243)
+	src := image.NewRGBA(bounds)
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			src.SetRGBA(x, y, color.RGBA{uint8(x), uint8(y), uint8(x * y), 255})
+		}
+	}
+	img := ConvertImage(src)
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			test.That(t, img.GetXY(x, y), test.ShouldEqual, NewColor(uint8(x), uint8(y), uint8(x*y)))
+		}
+	}
+
+	rgba := image.NewRGBA(bounds)
+	ConvertToRGBA(rgba, img)
+	test.That(t, rgba.Pix, test.ShouldResemble, src.Pix)
+
+	gray := image.NewGray(bounds)
+	draw.Draw(gray, bounds, img, bounds.Min, draw.Src)
+	test.That(t, MakeGray(img).Pix, test.ShouldResemble, gray.Pix)
+
+	ycbcr := image.NewYCbCr(bounds, image.YCbCrSubsampleRatio420)
+	for i := range ycbcr.Y {
+		ycbcr.Y[i] = uint8(i)
+	}
+	for i := range ycbcr.Cb {
+		ycbcr.Cb[i], ycbcr.Cr[i] = uint8(i*3), uint8(i*7)
+	}
+	img = ConvertImage(ycbcr)
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			test.That(t, img.GetXY(x, y), test.ShouldEqual, NewColorFromColor(ycbcr.At(x, y)))
+		}
+	}
+}
@@ -46,9 +46,10 @@ type Service interface {
 // New returns a new frame system service for the given robot.
 func New(ctx context.Context, r robot.Robot, logger golog.Logger) Service {
 	return &frameSystemService{
-		Named:  InternalServiceName.AsNamed(),
-		r:      r,
-		logger: logger,
+		Named:    InternalServiceName.AsNamed(),
+		r:        r,
+		logger:   logger,
+		watchers: map[chan framesystemparts.Parts]struct{}{},
 	}
 }
 
@@ -77,6 +78,16 @@ type frameSystemService struct {
 	partsMu     sync.RWMutex
 	localParts  framesystemparts.Parts                     // gotten from the local robot's config.Config
 	offsetParts map[string]*referenceframe.FrameSystemPart // gotten from local robot's config.Remote
+
+	// the frame system used by TransformPose is kept, along with the poses of static frames it caches, until the
+	// robot is reconfigured or the parts of its remotes change.
+	cacheMu         sync.Mutex
+	cachedFS        referenceframe.FrameSystem
+	cachedRemoteKey string
+
+	topologyMu  sync.Mutex
+	topologyKey string // the parts last sent to watchers
+	watchers    map[chan framesystemparts.Parts]struct{}
 }
 
 // Reconfigure will rebuild the frame system from the newly updated robot.
@@ -107,6 +118,11 @@ func (svc *frameSystemService) Reconfigure(ctx context.Context, _ resource.Depen
 		return err
 	}
 	svc.logger.Debugf("updated robot frame system:\n%v", sortedParts.String())
+
+	svc.cacheMu.Lock()
+	svc.cachedFS = nil
+	svc.cacheMu.Unlock()
+	svc.publishTopology(sortedParts)
 	return nil
 }
 
@@ -142,6 +158,9 @@ func (svc *frameSystemService) Config(
 	if err != nil {
 		return nil, err
 	}
+	if len(additionalTransforms) == 0 {
+		svc.publishTopology(sortedParts)
+	}
 	return sortedParts, nil
 }
 
@@ -156,11 +175,18 @@ func (svc *frameSystemService) TransformPose(
 	defer span.End()
 
 	// get the frame system and initial inputs
-	allParts, err := svc.Config(ctx, additionalTransforms)
-	if err != nil {
-		return nil, err
+	var fs referenceframe.FrameSystem
+	var err error
+	if len(additionalTransforms) == 0 {
+		fs, err = svc.cachedFrameSystem(ctx)
+	} else {
+		var allParts framesystemparts.Parts
+		allParts, err = svc.Config(ctx, additionalTransforms)
+		if err != nil {
+			return nil, err
+		}
+		fs, err = NewFrameSystemFromParts(LocalFrameSystemName, "", allParts, svc.logger)
 	}
-	fs, err := NewFrameSystemFromParts(LocalFrameSystemName, "", allParts, svc.logger)
 	if err != nil {
 		return nil, err
 	}
@@ -202,6 +228,41 @@ func (svc *frameSystemService) TransformPose(
 	return pose, nil
 }
 
+// cachedFrameSystem returns the frame system of the robot and its remotes, building it again only if the robot has been
+// reconfigured or the parts of its remotes have changed since it was last built. The frame system returned must not be
+// changed, as it is shared between calls.
+func (svc *frameSystemService) cachedFrameSystem(ctx context.Context) (referenceframe.FrameSystem, error) {
+	svc.partsMu.RLock()
+	defer svc.partsMu.RUnlock()
+
+	remoteParts, err := svc.updateRemoteParts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remoteKey, err := remotePartsKey(remoteParts)
+	if err != nil {
+		return nil, err
+	}
+
+	svc.cacheMu.Lock()
+	defer svc.cacheMu.Unlock()
+	if svc.cachedFS != nil && remoteKey == svc.cachedRemoteKey {
+		return svc.cachedFS, nil
+	}
+	sortedParts, err := framesystemparts.TopologicallySort(combineParts(svc.localParts, svc.offsetParts, remoteParts))
+	if err != nil {
+		return nil, err
+	}
+	fs, err := NewFrameSystemFromParts(LocalFrameSystemName, "", sortedParts, svc.logger)
+	if err != nil {
+		return nil, err
+	}
+	svc.cachedFS = fs
+	svc.cachedRemoteKey = remoteKey
+	svc.publishTopology(sortedParts)
+	return fs, nil
+}
+
 // AllCurrentInputs will get present inputs for a framesystem from a robot and return a map of those inputs, as well as a map of the
 // InputEnabled resources that those inputs came from.
 func (svc *frameSystemService) AllCurrentInputs(
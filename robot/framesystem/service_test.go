@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/golang/geo/r3"
@@ -339,3 +340,105 @@ func pointAlmostEqual(t *testing.T, from, to r3.Vector) {
 	test.That(t, from.Y, test.ShouldAlmostEqual, to.Y)
 	test.That(t, from.Z, test.ShouldAlmostEqual, to.Z)
 }
+
+func TestTopologyChanges(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	cfg, err := config.Read(context.Background(), rdkutils.ResolveFile("robot/impl/data/fake.json"), logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	injectRobot := &inject.Robot{}
+	injectRobot.ConfigFunc = func(ctx context.Context) (*config.Config, error) {
+		return cfg, nil
+	}
+	injectRobot.ResourceByNameFunc = func(name resource.Name) (resource.Resource, error) {
+		return testutils.NewUnimplementedResource(name), nil
+	}
+	injectRobot.RemoteNamesFunc = func() []string {
+		return []string{}
+	}
+
+	ctx := context.Background()
+	service := framesystem.New(ctx, injectRobot, logger)
+	err = service.Reconfigure(ctx, nil, resource.Config{})
+	test.That(t, err, test.ShouldBeNil)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	changes, err := service.(framesystem.TopologyWatcher).TopologyChanges(watchCtx)
+	test.That(t, err, test.ShouldBeNil)
+	parts := <-changes
+	test.That(t, framesystemparts.Names(parts), test.ShouldContain, "cameraOver")
+
+	camera := referenceframe.NewPoseInFrame("cameraOver", spatialmath.NewZeroPose())
+	pose, err := service.TransformPose(ctx, camera, referenceframe.World, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.R3VectorAlmostEqual(pose.Pose().Point(), r3.Vector{X: 2000, Y: 500, Z: 1300}, 1e-9), test.ShouldBeTrue)
+
+	// reconfiguring without changing any frames does not notify watchers
+	err = service.Reconfigure(ctx, nil, resource.Config{})
+	test.That(t, err, test.ShouldBeNil)
+	select {
+	case <-changes:
+		t.Fatal("frame system did not change")
+	default:
+	}
+
+	// moving a frame notifies watchers and is seen by TransformPose
+	for _, c := range cfg.Components {
+		if c.Name == "cameraOver" {
+			c.Frame.Translation = r3.Vector{X: 1000, Y: 500, Z: 1300}
+		}
+	}
+	err = service.Reconfigure(ctx, nil, resource.Config{})
+	test.That(t, err, test.ShouldBeNil)
+	parts = <-changes
+	for _, part := range parts {
+		if part.FrameConfig.Name() == "cameraOver" {
+			test.That(t, part.FrameConfig.Pose().Point().X, test.ShouldAlmostEqual, 1000)
+		}
+	}
+	pose, err = service.TransformPose(ctx, camera, referenceframe.World, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.R3VectorAlmostEqual(pose.Pose().Point(), r3.Vector{X: 1000, Y: 500, Z: 1300}, 1e-9), test.ShouldBeTrue)
+
+	cancel()
+	_, ok := <-changes
+	test.That(t, ok, test.ShouldBeFalse)
+}
+
+func TestWatchTopologyPolling(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	lif, err := (&referenceframe.LinkConfig{ID: "frame1", Parent: referenceframe.World}).ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	parts := framesystemparts.Parts{{FrameConfig: lif}}
+
+	// a robot without a frame system service that can notify of changes, such as a robot client
+	injectRobot := &inject.Robot{}
+	injectRobot.ResourceByNameFunc = func(name resource.Name) (resource.Resource, error) {
+		return nil, resource.NewNotFoundError(name)
+	}
+	injectRobot.LoggerFunc = func() golog.Logger {
+		return logger
+	}
+	injectRobot.FrameSystemConfigFunc = func(
+		ctx context.Context,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) (framesystemparts.Parts, error) {
+		return parts, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := framesystem.WatchTopology(ctx, injectRobot, time.Millisecond)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, framesystemparts.Names(<-changes), test.ShouldResemble, []string{"frame1"})
+
+	lif2, err := (&referenceframe.LinkConfig{ID: "frame2", Parent: "frame1"}).ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	injectRobot.Mu.Lock()
+	parts = framesystemparts.Parts{{FrameConfig: lif}, {FrameConfig: lif2}}
+	injectRobot.Mu.Unlock()
+	test.That(t, framesystemparts.Names(<-changes), test.ShouldResemble, []string{"frame1", "frame2"})
+
+	cancel()
+	for range changes {
+	}
+}
@@ -0,0 +1,165 @@
+package framesystem
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	goutils "go.viam.com/utils"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/robot"
+	framesystemparts "go.viam.com/rdk/robot/framesystem/parts"
+)
+
+// DefaultTopologyPollPeriod is how often WatchTopology fetches the frame system config of a robot whose frame system
+// service cannot notify it of changes.
+const DefaultTopologyPollPeriod = time.Second
+
+// A TopologyWatcher is a frame system service that can notify callers when the parts of the frame system change, so
+// that callers holding a frame system built from them know when to build it again.
+type TopologyWatcher interface {
+	// TopologyChanges returns a channel that first receives the parts of the frame system and then receives them
+	// again each time they change. A receiver that falls behind only gets the latest parts. The channel is closed
+	// once ctx is done.
+	TopologyChanges(ctx context.Context) (<-chan framesystemparts.Parts, error)
+}
+
+// WatchTopology returns a channel that first receives the frame system parts of the robot and then receives them again
+// each time they change, and is closed once ctx is done. Robots whose frame system service is a TopologyWatcher notify
+// the channel themselves. Other robots, such as robot clients, have their frame system config fetched every period
+// until the robot API has a streaming RPC for frame system changes; errors fetching it are logged and it is fetched
+// again the next period.
+func WatchTopology(ctx context.Context, r robot.Robot, period time.Duration) (<-chan framesystemparts.Parts, error) {
+	if svc, err := FromRobot(r); err == nil {
+		if watcher, ok := svc.(TopologyWatcher); ok {
+			return watcher.TopologyChanges(ctx)
+		}
+	}
+	changes := make(chan framesystemparts.Parts)
+	goutils.PanicCapturingGo(func() {
+		defer close(changes)
+		var lastKey string
+		for {
+			parts, err := r.FrameSystemConfig(ctx, nil)
+			var key string
+			if err == nil {
+				key, err = partsKey(parts)
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					r.Logger().Debugw("failed to get frame system config", "error", err)
+				}
+			} else if key != lastKey {
+				select {
+				case changes <- parts:
+					lastKey = key
+				case <-ctx.Done():
+					return
+				}
+			}
+			if !goutils.SelectContextOrWait(ctx, period) {
+				return
+			}
+		}
+	})
+	return changes, nil
+}
+
+// partsKey returns the serialized parts, which are the same for two sets of parts only if they make the same frame system.
+func partsKey(parts framesystemparts.Parts) (string, error) {
+	var key bytes.Buffer
+	for _, part := range parts {
+		partProto, err := part.ToProtobuf()
+		if err != nil {
+			return "", err
+		}
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(partProto)
+		if err != nil {
+			return "", err
+		}
+		key.Write(data)
+	}
+	return key.String(), nil
+}
+
+// remotePartsKey is partsKey for the parts of every remote, in order of remote name.
+func remotePartsKey(remoteParts map[string]framesystemparts.Parts) (string, error) {
+	names := make([]string, 0, len(remoteParts))
+	for name := range remoteParts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var key bytes.Buffer
+	for _, name := range names {
+		k, err := partsKey(remoteParts[name])
+		if err != nil {
+			return "", err
+		}
+		key.WriteString(name)
+		key.WriteString(k)
+	}
+	return key.String(), nil
+}
+
+// TopologyChanges returns a channel that first receives the parts of the frame system and then receives them again each
+// time they change, such as when the robot is reconfigured or the frame system of a remote changes. A receiver that falls
+// behind only gets the latest parts. The channel is closed once ctx is done.
+func (svc *frameSystemService) TopologyChanges(ctx context.Context) (<-chan framesystemparts.Parts, error) {
+	parts, err := svc.Config(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	key, err := partsKey(parts)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(chan framesystemparts.Parts, 1)
+	changes <- parts
+
+	svc.topologyMu.Lock()
+	// the parts may have changed since they were last sent to other watchers
+	svc.sendTopology(key, parts)
+	svc.watchers[changes] = struct{}{}
+	svc.topologyMu.Unlock()
+	goutils.PanicCapturingGo(func() {
+		<-ctx.Done()
+		svc.topologyMu.Lock()
+		defer svc.topologyMu.Unlock()
+		delete(svc.watchers, changes)
+		close(changes)
+	})
+	return changes, nil
+}
+
+// publishTopology sends the parts of the frame system to every watcher if they are different from the last parts sent.
+func (svc *frameSystemService) publishTopology(parts framesystemparts.Parts) {
+	svc.topologyMu.Lock()
+	defer svc.topologyMu.Unlock()
+	if len(svc.watchers) == 0 {
+		return
+	}
+	key, err := partsKey(parts)
+	if err != nil {
+		svc.logger.Debugw("failed to compare frame system parts", "error", err)
+		return
+	}
+	svc.sendTopology(key, parts)
+}
+
+// sendTopology sends the parts with the given key to every watcher if the key is different from the last one sent,
+// replacing any parts a watcher has not received yet. topologyMu must be held.
+func (svc *frameSystemService) sendTopology(key string, parts framesystemparts.Parts) {
+	if key == svc.topologyKey {
+		return
+	}
+	svc.topologyKey = key
+	for changes := range svc.watchers {
+		select {
+		case <-changes:
+		default:
+		}
+		changes <- parts
+	}
+}
@@ -0,0 +1,238 @@
+package builtin
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	v1 "go.viam.com/api/app/datasync/v1"
+
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/datamanager/datacapture"
+	"go.viam.com/rdk/services/datamanager/datasync"
+)
+
+// exportManifestName is the name of the manifest at the root of an export archive.
+const exportManifestName = "manifest.json"
+
+// exportManifest describes the captured data in an export archive, so that it can be understood without the robot that
+// captured it.
+type exportManifest struct {
+	CreatedAt time.Time       `json:"created_at"`
+	Filter    datasync.Filter `json:"filter"`
+	// Resources are the resources and methods the exported data was captured from, with the form of their data.
+	Resources []exportedResource `json:"resources"`
+	// CaptureMethods is the capture configuration of the data manager when the archive was made.
+	CaptureMethods []datamanager.DataCaptureConfig `json:"capture_methods"`
+	// Files are the paths of the exported files within the archive.
+	Files []string `json:"files"`
+}
+
+// exportedResource is a resource method data in an export archive was captured from.
+type exportedResource struct {
+	ComponentType    string   `json:"component_type"`
+	ComponentName    string   `json:"component_name"`
+	MethodName       string   `json:"method_name"`
+	DataType         string   `json:"data_type"`
+	FileExtension    string   `json:"file_extension,omitempty"`
+	MethodParameters []string `json:"method_parameters,omitempty"`
+	Files            int      `json:"files"`
+}
+
+// exportFile is a file to export and the path it is given within the archive.
+type exportFile struct {
+	path        string
+	archivePath string
+}
+
+// exportData writes the captured data and the files of the additional sync paths that match the filter to a gzipped tar
+// archive at path, with a manifest of what it holds, and returns how many files it exported. Data captured to the capture
+// directory is under "capture" in the archive and the files of each additional sync path are under "additional/<index>".
+func (svc *builtIn) exportData(path string, filter datasync.Filter) (int, error) {
+	if path == "" {
+		return 0, errors.New("a path to export to is required")
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return 0, err
+	}
+
+	svc.lock.Lock()
+	roots := map[string]string{"capture": svc.captureDir}
+	for i, additional := range svc.additionalSyncPaths {
+		roots[filepath.ToSlash(filepath.Join("additional", strconv.Itoa(i)))] = additional
+	}
+	waitAfterLastModifiedMillis := svc.waitAfterLastModifiedMillis
+	captureMethods := make([]datamanager.DataCaptureConfig, 0, len(svc.collectors))
+	for _, collector := range svc.collectors {
+		captureMethods = append(captureMethods, collector.Config)
+	}
+	svc.lock.Unlock()
+	sort.Slice(captureMethods, func(i, j int) bool {
+		if captureMethods[i].Name.String() != captureMethods[j].Name.String() {
+			return captureMethods[i].Name.String() < captureMethods[j].Name.String()
+		}
+		return captureMethods[i].Method < captureMethods[j].Method
+	})
+
+	var files []exportFile
+	for prefix, root := range roots {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return 0, err
+		}
+		if strings.HasPrefix(path, absRoot+string(filepath.Separator)) {
+			return 0, errors.Errorf("cannot export to %s, which is within the exported directory %s", path, root)
+		}
+		for _, file := range getAllFilesToSync(root, waitAfterLastModifiedMillis) {
+			// files still being captured are left out, as they are not synced either
+			if filepath.Ext(file) == datacapture.InProgressFileExt {
+				continue
+			}
+			if match, err := filter.Matches(file); err != nil || !match {
+				continue
+			}
+			rel, err := filepath.Rel(root, file)
+			if err != nil {
+				return 0, err
+			}
+			files = append(files, exportFile{path: file, archivePath: filepath.ToSlash(filepath.Join(prefix, rel))})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].archivePath < files[j].archivePath })
+
+	manifest := exportManifest{
+		CreatedAt:      time.Now().UTC(),
+		Filter:         filter,
+		CaptureMethods: captureMethods,
+	}
+	resources := map[string]*exportedResource{}
+	for _, file := range files {
+		manifest.Files = append(manifest.Files, file.archivePath)
+		if filepath.Ext(file.path) != datacapture.FileExt {
+			continue
+		}
+		md, err := readCaptureMetadata(file.path)
+		if err != nil {
+			return 0, err
+		}
+		key := md.ComponentType + "/" + md.ComponentName + "/" + md.MethodName
+		if _, ok := resources[key]; !ok {
+			var params []string
+			for name := range md.GetMethodParameters() {
+				params = append(params, name)
+			}
+			sort.Strings(params)
+			resources[key] = &exportedResource{
+				ComponentType:    md.GetComponentType(),
+				ComponentName:    md.GetComponentName(),
+				MethodName:       md.GetMethodName(),
+				DataType:         md.GetType().String(),
+				FileExtension:    md.GetFileExtension(),
+				MethodParameters: params,
+			}
+		}
+		resources[key].Files++
+	}
+	for _, res := range resources {
+		manifest.Resources = append(manifest.Resources, *res)
+	}
+	sort.Slice(manifest.Resources, func(i, j int) bool {
+		a, b := manifest.Resources[i], manifest.Resources[j]
+		return a.ComponentType+"/"+a.ComponentName+"/"+a.MethodName < b.ComponentType+"/"+b.ComponentName+"/"+b.MethodName
+	})
+
+	// the archive is written next to its destination and renamed once it is complete, so a partial one is never left behind
+	tmpPath := path + ".tmp"
+	if err := writeExportArchive(tmpPath, manifest, files); err != nil {
+		return 0, multierr.Combine(err, os.Remove(tmpPath))
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, err
+	}
+	return len(files), nil
+}
+
+func readCaptureMetadata(path string) (*v1.DataCaptureMetadata, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck
+	defer f.Close()
+	captureFile, err := datacapture.ReadFile(f)
+	if err != nil {
+		return nil, err
+	}
+	return captureFile.ReadMetadata(), nil
+}
+
+func writeExportArchive(path string, manifest exportManifest, files []exportFile) (err error) {
+	//nolint:gosec
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	defer func() {
+		err = multierr.Combine(err, tw.Close(), gz.Close(), out.Close())
+	}()
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    exportManifestName,
+		Mode:    0o644,
+		Size:    int64(len(manifestBytes)),
+		ModTime: manifest.CreatedAt,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifestBytes); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := addFileToArchive(tw, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addFileToArchive(tw *tar.Writer, file exportFile) error {
+	//nolint:gosec
+	f, err := os.Open(file.path)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = file.archivePath
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
@@ -0,0 +1,104 @@
+package builtin
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// readArchive returns the contents of the files in a gzipped tar archive by name, in the order they were written.
+func readArchive(t *testing.T, path string) ([]string, map[string][]byte) {
+	t.Helper()
+	f, err := os.Open(path)
+	test.That(t, err, test.ShouldBeNil)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	test.That(t, err, test.ShouldBeNil)
+	tr := tar.NewReader(gz)
+	var names []string
+	contents := map[string][]byte{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		test.That(t, err, test.ShouldBeNil)
+		data, err := io.ReadAll(tr)
+		test.That(t, err, test.ShouldBeNil)
+		names = append(names, header.Name)
+		contents[header.Name] = data
+	}
+	return names, contents
+}
+
+func TestExportData(t *testing.T) {
+	captureDir := t.TempDir()
+	md, err := datacapture.BuildCaptureMetadata(sensor.API, "sensor1", "Readings", nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	captureFile, err := datacapture.NewFile(captureDir, md)
+	test.That(t, err, test.ShouldBeNil)
+	reading, err := structpb.NewStruct(map[string]interface{}{"temperature": 20.5})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, captureFile.WriteNext(&v1.SensorData{Data: &v1.SensorData_Struct{Struct: reading}}), test.ShouldBeNil)
+	test.That(t, captureFile.Close(), test.ShouldBeNil)
+	// a file that is still being captured to is not exported
+	inProgress, err := datacapture.NewFile(captureDir, md)
+	test.That(t, err, test.ShouldBeNil)
+	defer inProgress.Close()
+
+	additionalDir := t.TempDir()
+	test.That(t, os.WriteFile(filepath.Join(additionalDir, "notes.txt"), []byte("notes"), 0o600), test.ShouldBeNil)
+
+	svc := &builtIn{captureDir: captureDir, additionalSyncPaths: []string{additionalDir}}
+	exportPath := filepath.Join(t.TempDir(), "export.tar.gz")
+	resp, err := svc.DoCommand(context.Background(), map[string]interface{}{
+		datamanager.ExportDataCommand: map[string]interface{}{"path": exportPath},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["exported_files"], test.ShouldEqual, 2)
+
+	names, contents := readArchive(t, exportPath)
+	test.That(t, names, test.ShouldHaveLength, 3)
+	test.That(t, names[0], test.ShouldEqual, exportManifestName)
+	var manifest exportManifest
+	test.That(t, json.Unmarshal(contents[exportManifestName], &manifest), test.ShouldBeNil)
+	test.That(t, manifest.Files, test.ShouldResemble, names[1:])
+	test.That(t, manifest.Files[0], test.ShouldEqual, "additional/0/notes.txt")
+	test.That(t, contents["additional/0/notes.txt"], test.ShouldResemble, []byte("notes"))
+	test.That(t, manifest.Resources, test.ShouldHaveLength, 1)
+	test.That(t, manifest.Resources[0].ComponentName, test.ShouldEqual, "sensor1")
+	test.That(t, manifest.Resources[0].MethodName, test.ShouldEqual, "Readings")
+	test.That(t, manifest.Resources[0].DataType, test.ShouldEqual, v1.DataType_DATA_TYPE_TABULAR_SENSOR.String())
+	test.That(t, manifest.Resources[0].Files, test.ShouldEqual, 1)
+
+	// filtering by resource leaves out the files that were not captured
+	resp, err = svc.DoCommand(context.Background(), map[string]interface{}{
+		datamanager.ExportDataCommand: map[string]interface{}{
+			"path":   exportPath,
+			"filter": map[string]interface{}{"resources": []interface{}{"sensor1"}},
+		},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["exported_files"], test.ShouldEqual, 1)
+
+	// the archive cannot be written into the data it exports
+	_, err = svc.DoCommand(context.Background(), map[string]interface{}{
+		datamanager.ExportDataCommand: map[string]interface{}{"path": filepath.Join(captureDir, "export.tar.gz")},
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = svc.DoCommand(context.Background(), map[string]interface{}{datamanager.ExportDataCommand: map[string]interface{}{}})
+	test.That(t, err, test.ShouldNotBeNil)
+}
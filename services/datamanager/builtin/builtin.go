@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sync"
 	"time"
 
@@ -60,6 +61,10 @@ const defaultCaptureQueueSize = 250
 // Default bufio.Writer buffer size in bytes.
 const defaultCaptureBufferSize = 4096
 
+// How often the disk usage of the capture directory is measured when it has a quota. Writes in between count
+// towards the usage as they happen.
+var quotaCheckInterval = 10 * time.Second
+
 var clock = clk.New()
 
 var errCaptureDirectoryConfigurationDisabled = errors.New("changing the capture directory is prohibited in this environment")
@@ -72,11 +77,46 @@ type Config struct {
 	CaptureDisabled       bool                             `json:"capture_disabled"`
 	ScheduledSyncDisabled bool                             `json:"sync_disabled"`
 	ResourceConfigs       []*datamanager.DataCaptureConfig `json:"resource_configs"`
+	// MaxCaptureDirSizeMB is the most disk the capture directory may use, or no limit if 0. What happens once it
+	// uses more is up to CaptureDirQuotaPolicy, which drops the oldest captured data by default.
+	MaxCaptureDirSizeMB   float64 `json:"max_capture_dir_size_mb"`
+	CaptureDirQuotaPolicy string  `json:"capture_dir_quota_policy"`
+	// MaxSyncBandwidthKbps caps the upload rate of sync in kilobits per second, or leaves it uncapped if 0.
+	MaxSyncBandwidthKbps float64 `json:"max_sync_bandwidth_kbps"`
+	// SyncWindows are the times of day scheduled syncs may run at, which is any time if there are none. Manual
+	// syncs run regardless.
+	SyncWindows []datasync.SyncWindow `json:"sync_windows"`
+	// SyncFilter limits scheduled syncs to the captured data that matches it. The rest is kept until it is synced
+	// manually or dropped by the quota.
+	SyncFilter datasync.Filter `json:"sync_filter"`
+	// CaptureTriggers arm the capture of resources so that their data is only stored around events.
+	CaptureTriggers []CaptureTrigger `json:"capture_triggers"`
 }
 
 // components will be depended upon weakly due to the above matcher.
 func (c *Config) Validate(path string) ([]string, error) {
-	return []string{cloud.InternalServiceName.String()}, nil
+	if c.MaxCaptureDirSizeMB < 0 {
+		return nil, goutils.NewConfigValidationError(path, errors.New("max_capture_dir_size_mb cannot be negative"))
+	}
+	if err := datacapture.ValidateQuotaPolicy(datacapture.QuotaPolicy(c.CaptureDirQuotaPolicy)); err != nil {
+		return nil, goutils.NewConfigValidationError(path, err)
+	}
+	if c.MaxSyncBandwidthKbps < 0 {
+		return nil, goutils.NewConfigValidationError(path, errors.New("max_sync_bandwidth_kbps cannot be negative"))
+	}
+	for _, w := range c.SyncWindows {
+		if err := w.Validate(); err != nil {
+			return nil, goutils.NewConfigValidationError(path, err)
+		}
+	}
+	if err := c.SyncFilter.Validate(); err != nil {
+		return nil, goutils.NewConfigValidationError(path, err)
+	}
+	triggerDeps, err := validateCaptureTriggers(c.CaptureTriggers)
+	if err != nil {
+		return nil, goutils.NewConfigValidationError(path, err)
+	}
+	return append([]string{cloud.InternalServiceName.String()}, triggerDeps...), nil
 }
 
 // builtIn initializes and orchestrates data capture collectors for registered component/methods.
@@ -90,9 +130,24 @@ type builtIn struct {
 	backgroundWorkers           sync.WaitGroup
 	waitAfterLastModifiedMillis int
 
+	quota              *datacapture.Quota
+	quotaCheckCancelFn context.CancelFunc
+	quotaWorkers       sync.WaitGroup
+
+	triggersConfig          []CaptureTrigger
+	triggerWatchersCancelFn context.CancelFunc
+	triggerWorkers          sync.WaitGroup
+	// triggerLock guards the triggers and the buffers they write out, which are fired outside of lock.
+	triggerLock      sync.Mutex
+	captureTriggers  []CaptureTrigger
+	triggeredBuffers map[string][]*datacapture.TriggeredBuffer
+
 	additionalSyncPaths []string
 	syncDisabled        bool
 	syncIntervalMins    float64
+	syncWindows         []datasync.SyncWindow
+	syncFilter          datasync.Filter
+	maxSyncBandwidth    float64
 	syncRoutineCancelFn context.CancelFunc
 	syncer              datasync.Manager
 	syncerConstructor   datasync.ManagerConstructor
@@ -119,6 +174,7 @@ func NewBuiltIn(
 		additionalSyncPaths:         []string{},
 		waitAfterLastModifiedMillis: 10000,
 		syncerConstructor:           datasync.NewManager,
+		quota:                       datacapture.NewQuota(viamCaptureDotDir, 0, "", logger),
 	}
 
 	if err := svc.Reconfigure(ctx, deps, conf); err != nil {
@@ -133,12 +189,37 @@ func (svc *builtIn) Close(_ context.Context) error {
 	svc.closeCollectors()
 	svc.closeSyncer()
 	svc.cancelSyncScheduler()
+	svc.cancelQuotaChecks()
+	svc.cancelTriggerWatchers()
 
 	svc.lock.Unlock()
 	svc.backgroundWorkers.Wait()
 	return nil
 }
 
+// Status returns the disk usage of the capture directory and what its quota, if any, has done about it.
+func (svc *builtIn) Status(ctx context.Context) (map[string]interface{}, error) {
+	svc.lock.Lock()
+	defer svc.lock.Unlock()
+	usage := svc.quota.Usage()
+	if usage.MaxBytes == 0 {
+		// without a quota the usage is not checked in the background
+		if err := svc.quota.Check(); err != nil {
+			return nil, err
+		}
+		usage = svc.quota.Usage()
+	}
+	return map[string]interface{}{
+		"capture_dir":                svc.captureDir,
+		"capture_dir_size_bytes":     usage.UsedBytes,
+		"max_capture_dir_size_bytes": usage.MaxBytes,
+		"capture_dir_quota_policy":   string(usage.Policy),
+		"capture_dir_quota_exceeded": usage.Exceeded,
+		"dropped_capture_files":      usage.DroppedFiles,
+		"skipped_readings":           usage.SkippedReadings,
+	}, nil
+}
+
 func (svc *builtIn) closeCollectors() {
 	var wg sync.WaitGroup
 	for md, collector := range svc.collectors {
@@ -170,6 +251,8 @@ func (svc *builtIn) flushCollectors() {
 type collectorAndConfig struct {
 	Collector data.Collector
 	Config    datamanager.DataCaptureConfig
+	// Triggered is the buffer of the collector if its capture is armed by triggers.
+	Triggered *datacapture.TriggeredBuffer
 }
 
 // Identifier for a particular collector: component name, component model, component type,
@@ -252,11 +335,19 @@ func (svc *builtIn) initializeOrUpdateCollector(
 	if err := os.MkdirAll(targetDir, 0o700); err != nil {
 		return nil, err
 	}
+	buffer := datacapture.NewBuffer(targetDir, captureMetadata)
+	buffer.Quota = svc.quota
+	var target datacapture.BufferedWriter = buffer
+	var triggered *datacapture.TriggeredBuffer
+	if pre, _, armed := triggerTimes(svc.triggersConfig, config.Name.ShortName()); armed {
+		triggered = datacapture.NewTriggeredBuffer(buffer, pre, clock)
+		target = triggered
+	}
 	params := data.CollectorParams{
 		ComponentName: config.Name.ShortName(),
 		Interval:      interval,
 		MethodParams:  methodParams,
-		Target:        datacapture.NewBuffer(targetDir, captureMetadata),
+		Target:        target,
 		QueueSize:     captureQueueSize,
 		BufferSize:    captureBufferSize,
 		Logger:        svc.logger,
@@ -268,7 +359,7 @@ func (svc *builtIn) initializeOrUpdateCollector(
 	}
 	collector.Collect()
 
-	return &collectorAndConfig{collector, *config}, nil
+	return &collectorAndConfig{collector, *config, triggered}, nil
 }
 
 func (svc *builtIn) closeSyncer() {
@@ -303,6 +394,7 @@ func (svc *builtIn) initSyncer(ctx context.Context) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize new syncer")
 	}
+	syncer.SetMaxBandwidth(svc.maxSyncBandwidth)
 	svc.syncer = syncer
 	svc.cloudConn = conn
 	return nil
@@ -333,8 +425,17 @@ func (svc *builtIn) getCollectorFromConfig(attributes datamanager.DataCaptureCon
 // TODO: Determine desired behavior if sync is disabled. Do we wan to allow manual syncs, then?
 //       If so, how could a user cancel it?
 
-// Sync performs a non-scheduled sync of the data in the capture directory.
-func (svc *builtIn) Sync(ctx context.Context, _ map[string]interface{}) error {
+// Sync performs a non-scheduled sync of the data in the capture directory, or only of the data that matches the
+// filter in extra if there is one.
+func (svc *builtIn) Sync(ctx context.Context, extra map[string]interface{}) error {
+	var filter datasync.Filter
+	if filterMap, ok := extra[datamanager.SyncFilterExtraKey].(map[string]interface{}); ok {
+		var err error
+		if filter, err = datasync.FilterFromMap(filterMap); err != nil {
+			return err
+		}
+	}
+
 	svc.lock.Lock()
 	defer svc.lock.Unlock()
 	if svc.syncer == nil {
@@ -344,10 +445,75 @@ func (svc *builtIn) Sync(ctx context.Context, _ map[string]interface{}) error {
 		}
 	}
 
-	svc.sync()
+	svc.sync(filter)
 	return nil
 }
 
+// DoCommand supports "tag_files", which adds tags to the captured data matching a filter and returns the number of
+// files it changed, "trigger_capture", which fires the named capture trigger, and "export_data", which archives the
+// captured data matching a filter and returns the number of files it exported.
+func (svc *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if name, ok := cmd[datamanager.TriggerCaptureCommand].(string); ok {
+		return map[string]interface{}{}, svc.fireTrigger(name)
+	}
+	if args, ok := cmd[datamanager.ExportDataCommand].(map[string]interface{}); ok {
+		path, _ := args["path"].(string)
+		filterMap, _ := args["filter"].(map[string]interface{})
+		filter, err := datasync.FilterFromMap(filterMap)
+		if err != nil {
+			return nil, err
+		}
+		exported, err := svc.exportData(path, filter)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"exported_files": exported}, nil
+	}
+	args, ok := cmd[datamanager.TagFilesCommand].(map[string]interface{})
+	if !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+	var tags []string
+	rawTags, _ := args["tags"].([]interface{})
+	for _, tag := range rawTags {
+		tagStr, ok := tag.(string)
+		if !ok {
+			return nil, errors.Errorf("tags must be strings, got %v", tag)
+		}
+		tags = append(tags, tagStr)
+	}
+	if len(tags) == 0 {
+		return nil, errors.New("no tags to add")
+	}
+	filterMap, _ := args["filter"].(map[string]interface{})
+	filter, err := datasync.FilterFromMap(filterMap)
+	if err != nil {
+		return nil, err
+	}
+
+	svc.lock.Lock()
+	captureDir := svc.captureDir
+	svc.lock.Unlock()
+	tagged := 0
+	for _, path := range getAllFilesToSync(captureDir, 0) {
+		// files still being captured to get their tags from the config
+		if filepath.Ext(path) != datacapture.FileExt {
+			continue
+		}
+		if match, err := filter.Matches(path); err != nil || !match {
+			continue
+		}
+		added, err := datacapture.AddTags(path, tags)
+		if err != nil {
+			return nil, err
+		}
+		if added {
+			tagged++
+		}
+	}
+	return map[string]interface{}{"tagged_files": tagged}, nil
+}
+
 // Reconfigure updates the data manager service when the config has changed.
 func (svc *builtIn) Reconfigure(
 	ctx context.Context,
@@ -369,6 +535,15 @@ func (svc *builtIn) Reconfigure(
 	reinitSyncer := cloudConnSvc != svc.cloudConnSvc
 	svc.cloudConnSvc = cloudConnSvc
 
+	// The collectors of resources are rebuilt when the triggers that arm them change, and the watchers of triggers
+	// are always restarted since their sensors and vision services may have changed.
+	svc.cancelTriggerWatchers()
+	if !reflect.DeepEqual(svc.triggersConfig, svcConfig.CaptureTriggers) {
+		svc.closeCollectors()
+		svc.collectors = make(map[componentMethodMetadata]*collectorAndConfig)
+		svc.triggersConfig = svcConfig.CaptureTriggers
+	}
+
 	svc.updateDataCaptureConfigs(deps, svcConfig.ResourceConfigs, svcConfig.CaptureDir)
 
 	if !utils.IsTrustedEnvironment(ctx) && svcConfig.CaptureDir != "" && svcConfig.CaptureDir != viamCaptureDotDir {
@@ -382,6 +557,14 @@ func (svc *builtIn) Reconfigure(
 		svc.captureDir = viamCaptureDotDir
 	}
 	svc.captureDisabled = svcConfig.CaptureDisabled
+
+	maxCaptureDirSizeBytes := int64(svcConfig.MaxCaptureDirSizeMB * 1024 * 1024)
+	svc.quota.SetLimit(svc.captureDir, maxCaptureDirSizeBytes, datacapture.QuotaPolicy(svcConfig.CaptureDirQuotaPolicy))
+	if maxCaptureDirSizeBytes == 0 {
+		svc.cancelQuotaChecks()
+	} else if svc.quotaCheckCancelFn == nil {
+		svc.startQuotaChecks()
+	}
 	// Service is disabled, so close all collectors and clear the map so we can instantiate new ones if we enable this service.
 	if svc.captureDisabled {
 		svc.closeCollectors()
@@ -426,7 +609,18 @@ func (svc *builtIn) Reconfigure(
 		}
 	}
 	svc.collectors = newCollectors
+	svc.setTriggeredBuffers(svc.triggersConfig)
+	if err := svc.startTriggerWatchers(deps, svc.triggersConfig); err != nil {
+		return err
+	}
 	svc.additionalSyncPaths = svcConfig.AdditionalSyncPaths
+	svc.syncWindows = svcConfig.SyncWindows
+	svc.syncFilter = svcConfig.SyncFilter
+	// kilobits per second to bytes per second
+	svc.maxSyncBandwidth = svcConfig.MaxSyncBandwidthKbps * 1000 / 8
+	if svc.syncer != nil {
+		svc.syncer.SetMaxBandwidth(svc.maxSyncBandwidth)
+	}
 
 	if svc.syncDisabled != svcConfig.ScheduledSyncDisabled || svc.syncIntervalMins != svcConfig.SyncIntervalMins {
 		svc.syncDisabled = svcConfig.ScheduledSyncDisabled
@@ -474,6 +668,38 @@ func (svc *builtIn) cancelSyncScheduler() {
 	}
 }
 
+// startQuotaChecks starts the goroutine that keeps the capture directory under its quota.
+func (svc *builtIn) startQuotaChecks() {
+	cancelCtx, fn := context.WithCancel(context.Background())
+	svc.quotaCheckCancelFn = fn
+	// The ticker is created before returning for the same reason as the sync ticker.
+	ticker := clock.Ticker(quotaCheckInterval)
+	svc.quotaWorkers.Add(1)
+	goutils.PanicCapturingGo(func() {
+		defer svc.quotaWorkers.Done()
+		defer ticker.Stop()
+		for {
+			if err := svc.quota.Check(); err != nil {
+				svc.logger.Errorw("failed to check the disk usage of the capture directory", "error", err)
+			}
+			select {
+			case <-cancelCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// cancelQuotaChecks stops the goroutine that keeps the capture directory under its quota.
+func (svc *builtIn) cancelQuotaChecks() {
+	if svc.quotaCheckCancelFn != nil {
+		svc.quotaCheckCancelFn()
+		svc.quotaWorkers.Wait()
+		svc.quotaCheckCancelFn = nil
+	}
+}
+
 func (svc *builtIn) uploadData(cancelCtx context.Context, intervalMins float64) {
 	// time.Duration loses precision at low floating point values, so turn intervalMins to milliseconds.
 	intervalMillis := 60000.0 * intervalMins
@@ -498,8 +724,8 @@ func (svc *builtIn) uploadData(cancelCtx context.Context, intervalMins float64)
 				return
 			case <-svc.syncTicker.C:
 				svc.lock.Lock()
-				if svc.syncer != nil {
-					svc.sync()
+				if svc.syncer != nil && datasync.InSyncWindow(svc.syncWindows, clock.Now()) {
+					svc.sync(svc.syncFilter)
 				}
 				svc.lock.Unlock()
 			}
@@ -507,14 +733,24 @@ func (svc *builtIn) uploadData(cancelCtx context.Context, intervalMins float64)
 	})
 }
 
-func (svc *builtIn) sync() {
+func (svc *builtIn) sync(filter datasync.Filter) {
 	svc.flushCollectors()
 	toSync := getAllFilesToSync(svc.captureDir, svc.waitAfterLastModifiedMillis)
 	for _, ap := range svc.additionalSyncPaths {
 		toSync = append(toSync, getAllFilesToSync(ap, svc.waitAfterLastModifiedMillis)...)
 	}
 	for _, p := range toSync {
-		svc.syncer.SyncFile(p)
+		match, err := filter.Matches(p)
+		if err != nil {
+			// Files may be synced and deleted in between paths being built and this executing.
+			if !errors.Is(err, os.ErrNotExist) {
+				svc.logger.Errorw("failed to check if file matches the sync filter", "file", p, "error", err)
+			}
+			continue
+		}
+		if match {
+			svc.syncer.SyncFile(p)
+		}
 	}
 }
 
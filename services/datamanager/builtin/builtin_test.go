@@ -14,6 +14,7 @@ import (
 	"github.com/edaniels/gostream"
 	"github.com/golang/geo/r3"
 	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
 
 	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/components/camera"
@@ -22,6 +23,7 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/datamanager/datacapture"
 	"go.viam.com/rdk/services/datamanager/internal"
 	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/testutils/inject"
@@ -159,3 +161,45 @@ func getAllFileInfos(dir string) []os.FileInfo {
 	})
 	return files
 }
+
+func TestCaptureDirQuota(t *testing.T) {
+	dmsvc, r := newTestDataManager(t)
+	defer dmsvc.Close(context.Background())
+
+	captureDir := t.TempDir()
+	test.That(t, os.WriteFile(filepath.Join(captureDir, "old"+datacapture.FileExt), make([]byte, 2048), 0o600), test.ShouldBeNil)
+
+	resources := resourcesFromDeps(t, r, []string{cloud.InternalServiceName.String()})
+	err := dmsvc.Reconfigure(context.Background(), resources, resource.Config{
+		ConvertedAttributes: &Config{
+			CaptureDir:            captureDir,
+			MaxCaptureDirSizeMB:   0.001,
+			CaptureDirQuotaPolicy: string(datacapture.QuotaPolicyPauseCapture),
+		},
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	reporter, ok := dmsvc.(datamanager.StatusReporter)
+	test.That(t, ok, test.ShouldBeTrue)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		status, err := reporter.Status(context.Background())
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, status["capture_dir_size_bytes"], test.ShouldEqual, int64(2048))
+		test.That(tb, status["max_capture_dir_size_bytes"], test.ShouldEqual, int64(1048))
+		test.That(tb, status["capture_dir_quota_exceeded"], test.ShouldBeTrue)
+	})
+
+	// without a quota the usage is still reported
+	err = dmsvc.Reconfigure(context.Background(), resources, resource.Config{
+		ConvertedAttributes: &Config{CaptureDir: captureDir},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	status, err := reporter.Status(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status["capture_dir_size_bytes"], test.ShouldEqual, int64(2048))
+	test.That(t, status["capture_dir_quota_exceeded"], test.ShouldBeFalse)
+
+	_, err = (&Config{CaptureDirQuotaPolicy: "delete_everything"}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
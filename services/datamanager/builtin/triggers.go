@@ -0,0 +1,264 @@
+package builtin
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+	"golang.org/x/exp/slices"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager/datacapture"
+	"go.viam.com/rdk/services/vision"
+)
+
+// How often triggers check their sensor or vision service if they do not say.
+const defaultTriggerCheckInterval = time.Second
+
+// CaptureTrigger arms the capture of resources so that their data is only stored around events, such as a person
+// being detected, rather than all the time. The collectors of the resources keep capturing at their rate, and hold
+// the readings of the last PreTriggerSecs in memory until the trigger fires. A trigger fires when its sensor or
+// vision condition is met, and whenever the "trigger_capture" DoCommand names it.
+type CaptureTrigger struct {
+	Name string `json:"name"`
+	// Resources are the names of the resources whose capture the trigger arms.
+	Resources       []string `json:"resources"`
+	PreTriggerSecs  float64  `json:"pre_trigger_secs"`
+	PostTriggerSecs float64  `json:"post_trigger_secs"`
+	// CheckIntervalMs is how often the sensor or vision condition is checked, once a second by default.
+	CheckIntervalMs int            `json:"check_interval_ms,omitempty"`
+	Sensor          *SensorTrigger `json:"sensor,omitempty"`
+	Vision          *VisionTrigger `json:"vision,omitempty"`
+}
+
+// SensorTrigger fires when a reading of a sensor goes above or below a threshold.
+type SensorTrigger struct {
+	Name string `json:"name"`
+	// Key is the key of the numeric reading to compare to the thresholds.
+	Key   string   `json:"key"`
+	Above *float64 `json:"above,omitempty"`
+	Below *float64 `json:"below,omitempty"`
+}
+
+// VisionTrigger fires when a vision service detects something in the images of a camera.
+type VisionTrigger struct {
+	Service string `json:"service"`
+	Camera  string `json:"camera"`
+	// Labels are the labels of the detections that fire the trigger, which is any detection if there are none.
+	Labels        []string `json:"labels,omitempty"`
+	MinConfidence float64  `json:"min_confidence,omitempty"`
+}
+
+// validateCaptureTriggers returns the names of the resources the triggers depend on, or an error if one of the
+// triggers is invalid.
+func validateCaptureTriggers(triggers []CaptureTrigger) ([]string, error) {
+	var deps []string
+	names := map[string]bool{}
+	for i, t := range triggers {
+		if t.Name == "" {
+			return nil, errors.Errorf("capture trigger %d must have a name", i)
+		}
+		if names[t.Name] {
+			return nil, errors.Errorf("capture trigger %q is defined more than once", t.Name)
+		}
+		names[t.Name] = true
+		if len(t.Resources) == 0 {
+			return nil, errors.Errorf("capture trigger %q must arm at least one resource", t.Name)
+		}
+		if t.PreTriggerSecs < 0 || t.PostTriggerSecs < 0 || t.CheckIntervalMs < 0 {
+			return nil, errors.Errorf("the durations of capture trigger %q cannot be negative", t.Name)
+		}
+		if t.Sensor != nil {
+			if t.Sensor.Name == "" || t.Sensor.Key == "" {
+				return nil, errors.Errorf("the sensor of capture trigger %q must have a name and a key", t.Name)
+			}
+			if t.Sensor.Above == nil && t.Sensor.Below == nil {
+				return nil, errors.Errorf("the sensor of capture trigger %q must have a threshold to be above or below", t.Name)
+			}
+			deps = append(deps, t.Sensor.Name)
+		}
+		if t.Vision != nil {
+			if t.Vision.Service == "" || t.Vision.Camera == "" {
+				return nil, errors.Errorf("the vision of capture trigger %q must have a service and a camera", t.Name)
+			}
+			deps = append(deps, t.Vision.Service)
+		}
+	}
+	return deps, nil
+}
+
+// triggerTimes returns the longest pre-trigger and post-trigger durations of the triggers that arm the resource, and
+// whether any do.
+func triggerTimes(triggers []CaptureTrigger, resourceName string) (pre, post time.Duration, armed bool) {
+	for _, t := range triggers {
+		if !slices.Contains(t.Resources, resourceName) {
+			continue
+		}
+		armed = true
+		if d := secsToDuration(t.PreTriggerSecs); d > pre {
+			pre = d
+		}
+		if d := secsToDuration(t.PostTriggerSecs); d > post {
+			post = d
+		}
+	}
+	return pre, post, armed
+}
+
+func secsToDuration(secs float64) time.Duration {
+	return time.Duration(secs * float64(time.Second))
+}
+
+// fireTrigger writes out the data that the resources armed by the trigger captured in the time before it, and has
+// them store what they capture until its post-trigger time has passed.
+func (svc *builtIn) fireTrigger(name string) error {
+	svc.triggerLock.Lock()
+	defer svc.triggerLock.Unlock()
+	idx := slices.IndexFunc(svc.captureTriggers, func(t CaptureTrigger) bool { return t.Name == name })
+	if idx < 0 {
+		return errors.Errorf("no capture trigger named %q", name)
+	}
+	trigger := svc.captureTriggers[idx]
+	for _, resourceName := range trigger.Resources {
+		for _, buf := range svc.triggeredBuffers[resourceName] {
+			if err := buf.Trigger(secsToDuration(trigger.PostTriggerSecs)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setTriggeredBuffers replaces the buffers that triggers write out with those of the current collectors.
+func (svc *builtIn) setTriggeredBuffers(triggers []CaptureTrigger) {
+	buffers := map[string][]*datacapture.TriggeredBuffer{}
+	for _, c := range svc.collectors {
+		if c.Triggered != nil {
+			name := c.Config.Name.ShortName()
+			buffers[name] = append(buffers[name], c.Triggered)
+		}
+	}
+	svc.triggerLock.Lock()
+	defer svc.triggerLock.Unlock()
+	svc.captureTriggers = triggers
+	svc.triggeredBuffers = buffers
+}
+
+// startTriggerWatchers starts a goroutine for every trigger with a sensor or vision condition, which fires the trigger
+// whenever the condition is met.
+func (svc *builtIn) startTriggerWatchers(deps resource.Dependencies, triggers []CaptureTrigger) error {
+	type watcher struct {
+		name     string
+		interval time.Duration
+		check    func(ctx context.Context) (bool, error)
+	}
+	var watchers []watcher
+	for _, t := range triggers {
+		interval := defaultTriggerCheckInterval
+		if t.CheckIntervalMs > 0 {
+			interval = time.Duration(t.CheckIntervalMs) * time.Millisecond
+		}
+		if t.Sensor != nil {
+			s, err := resource.FromDependencies[sensor.Sensor](deps, sensor.Named(t.Sensor.Name))
+			if err != nil {
+				return err
+			}
+			cond := *t.Sensor
+			watchers = append(watchers, watcher{t.Name, interval, func(ctx context.Context) (bool, error) {
+				return sensorTriggered(ctx, s, cond)
+			}})
+		}
+		if t.Vision != nil {
+			vis, err := resource.FromDependencies[vision.Service](deps, vision.Named(t.Vision.Service))
+			if err != nil {
+				return err
+			}
+			cond := *t.Vision
+			watchers = append(watchers, watcher{t.Name, interval, func(ctx context.Context) (bool, error) {
+				return visionTriggered(ctx, vis, cond)
+			}})
+		}
+	}
+	if len(watchers) == 0 {
+		return nil
+	}
+
+	cancelCtx, fn := context.WithCancel(context.Background())
+	svc.triggerWatchersCancelFn = fn
+	for _, w := range watchers {
+		w := w
+		// The ticker is created before returning for the same reason as the sync ticker.
+		ticker := clock.Ticker(w.interval)
+		svc.triggerWorkers.Add(1)
+		goutils.PanicCapturingGo(func() {
+			defer svc.triggerWorkers.Done()
+			defer ticker.Stop()
+			for {
+				select {
+				case <-cancelCtx.Done():
+					return
+				case <-ticker.C:
+				}
+				triggered, err := w.check(cancelCtx)
+				if err != nil {
+					if cancelCtx.Err() == nil {
+						svc.logger.Errorw("failed to check capture trigger", "trigger", w.name, "error", err)
+					}
+					continue
+				}
+				if !triggered {
+					continue
+				}
+				if err := svc.fireTrigger(w.name); err != nil {
+					svc.logger.Errorw("failed to fire capture trigger", "trigger", w.name, "error", err)
+				}
+			}
+		})
+	}
+	return nil
+}
+
+// cancelTriggerWatchers stops the goroutines that check the conditions of triggers.
+func (svc *builtIn) cancelTriggerWatchers() {
+	if svc.triggerWatchersCancelFn != nil {
+		svc.triggerWatchersCancelFn()
+		svc.triggerWorkers.Wait()
+		svc.triggerWatchersCancelFn = nil
+	}
+}
+
+func sensorTriggered(ctx context.Context, s sensor.Sensor, cond SensorTrigger) (bool, error) {
+	readings, err := s.Readings(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	var value float64
+	switch v := readings[cond.Key].(type) {
+	case float64:
+		value = v
+	case float32:
+		value = float64(v)
+	case int:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	default:
+		return false, errors.Errorf("reading %q of sensor %q is not a number: %v", cond.Key, cond.Name, readings[cond.Key])
+	}
+	return (cond.Above != nil && value > *cond.Above) || (cond.Below != nil && value < *cond.Below), nil
+}
+
+func visionTriggered(ctx context.Context, vis vision.Service, cond VisionTrigger) (bool, error) {
+	detections, err := vis.DetectionsFromCamera(ctx, cond.Camera, nil)
+	if err != nil {
+		return false, err
+	}
+	for _, d := range detections {
+		if d.Score() >= cond.MinConfidence && (len(cond.Labels) == 0 || slices.Contains(cond.Labels, d.Label())) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
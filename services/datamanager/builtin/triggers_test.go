@@ -0,0 +1,85 @@
+package builtin
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestValidateCaptureTriggers(t *testing.T) {
+	above := 30.
+	triggers := []CaptureTrigger{
+		{Name: "hot", Resources: []string{"camera1"}, Sensor: &SensorTrigger{Name: "thermometer", Key: "temp", Above: &above}},
+		{Name: "person", Resources: []string{"camera1", "camera2"}, Vision: &VisionTrigger{Service: "people", Camera: "camera1"}},
+		{Name: "manual", Resources: []string{"camera2"}},
+	}
+	deps, err := validateCaptureTriggers(triggers)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"thermometer", "people"})
+
+	_, err = validateCaptureTriggers(append(triggers, CaptureTrigger{Name: "hot", Resources: []string{"camera1"}}))
+	test.That(t, err, test.ShouldBeError, `capture trigger "hot" is defined more than once`)
+	_, err = validateCaptureTriggers([]CaptureTrigger{{Name: "empty"}})
+	test.That(t, err, test.ShouldBeError, `capture trigger "empty" must arm at least one resource`)
+	_, err = validateCaptureTriggers([]CaptureTrigger{
+		{Name: "hot", Resources: []string{"camera1"}, Sensor: &SensorTrigger{Name: "thermometer", Key: "temp"}},
+	})
+	test.That(t, err, test.ShouldBeError, `the sensor of capture trigger "hot" must have a threshold to be above or below`)
+}
+
+func TestTriggerTimes(t *testing.T) {
+	triggers := []CaptureTrigger{
+		{Name: "a", Resources: []string{"camera1"}, PreTriggerSecs: 5, PostTriggerSecs: 1},
+		{Name: "b", Resources: []string{"camera1", "camera2"}, PreTriggerSecs: 2, PostTriggerSecs: 10},
+	}
+	pre, post, armed := triggerTimes(triggers, "camera1")
+	test.That(t, armed, test.ShouldBeTrue)
+	test.That(t, pre, test.ShouldEqual, 5*time.Second)
+	test.That(t, post, test.ShouldEqual, 10*time.Second)
+	_, _, armed = triggerTimes(triggers, "arm1")
+	test.That(t, armed, test.ShouldBeFalse)
+}
+
+func TestTriggerConditions(t *testing.T) {
+	ctx := context.Background()
+	s := inject.NewSensor("thermometer")
+	temp := 25.
+	s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"temp": temp, "status": "ok"}, nil
+	}
+	above := 30.
+	cond := SensorTrigger{Name: "thermometer", Key: "temp", Above: &above}
+	triggered, err := sensorTriggered(ctx, s, cond)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, triggered, test.ShouldBeFalse)
+	temp = 31
+	triggered, err = sensorTriggered(ctx, s, cond)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, triggered, test.ShouldBeTrue)
+	_, err = sensorTriggered(ctx, s, SensorTrigger{Name: "thermometer", Key: "status", Above: &above})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	vis := inject.NewVisionService("people")
+	vis.DetectionsFromCameraFunc = func(
+		ctx context.Context,
+		cameraName string,
+		extra map[string]interface{},
+	) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{objectdetection.NewDetection(image.Rect(0, 0, 10, 10), 0.6, "person")}, nil
+	}
+	triggered, err = visionTriggered(ctx, vis, VisionTrigger{Service: "people", Camera: "camera1", Labels: []string{"person"}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, triggered, test.ShouldBeTrue)
+	triggered, err = visionTriggered(ctx, vis, VisionTrigger{Service: "people", Camera: "camera1", MinConfidence: 0.8})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, triggered, test.ShouldBeFalse)
+	triggered, err = visionTriggered(ctx, vis, VisionTrigger{Service: "people", Camera: "camera1", Labels: []string{"dog"}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, triggered, test.ShouldBeFalse)
+}
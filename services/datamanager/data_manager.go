@@ -22,6 +22,9 @@ func init() {
 			RPCServiceDesc:              &servicepb.DataManagerService_ServiceDesc,
 			RPCClient:                   NewClientFromConn,
 			MaxInstance:                 resource.DefaultMaxInstance,
+			Status: func(ctx context.Context, s Service) (interface{}, error) {
+				return CreateStatus(ctx, s)
+			},
 		},
 		resource.AssociatedConfigRegistration[*DataCaptureConfigs]{
 			AttributeMapConverter: func(attributes utils.AttributeMap) (*DataCaptureConfigs, error) {
@@ -45,6 +48,40 @@ type Service interface {
 	Sync(ctx context.Context, extra map[string]interface{}) error
 }
 
+// A StatusReporter is a Service with state worth surfacing through the robot's
+// status API, such as the disk usage of its capture directory.
+type StatusReporter interface {
+	Status(ctx context.Context) (map[string]interface{}, error)
+}
+
+// CreateStatus creates a status from the service. Services that are not
+// StatusReporters have an empty status.
+func CreateStatus(ctx context.Context, s Service) (map[string]interface{}, error) {
+	reporter, ok := s.(StatusReporter)
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+	return reporter.Status(ctx)
+}
+
+const (
+	// SyncFilterExtraKey is the key of the extra parameter of Sync that syncs only the captured data matching a
+	// filter of tags, resource names and a time range, such as
+	// {"filter": {"tags": ["a"], "resources": ["camera1"], "start": "2023-06-01T00:00:00Z"}}.
+	SyncFilterExtraKey = "filter"
+	// TagFilesCommand is the DoCommand that adds tags to the captured data matching a filter, such as
+	// {"tag_files": {"tags": ["keep"], "filter": {"resources": ["camera1"]}}}, so that it can be synced selectively.
+	TagFilesCommand = "tag_files"
+	// TriggerCaptureCommand is the DoCommand that fires a capture trigger of the data manager by name, such as
+	// {"trigger_capture": "doorbell"}, to store the data captured around now.
+	TriggerCaptureCommand = "trigger_capture"
+	// ExportDataCommand is the DoCommand that writes the captured data matching a filter to a gzipped tar archive on the
+	// robot, with a manifest of the resources it was captured from and the capture configuration, such as
+	// {"export_data": {"path": "/media/usb/capture.tar.gz", "filter": {"start": "2023-06-01T00:00:00Z"}}}. It is for
+	// robots that cannot sync to the cloud.
+	ExportDataCommand = "export_data"
+)
+
 // SubtypeName is the name of the type of service.
 const SubtypeName = "data_manager"
 
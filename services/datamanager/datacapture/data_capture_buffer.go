@@ -20,8 +20,10 @@ type BufferedWriter interface {
 type Buffer struct {
 	Directory string
 	MetaData  *v1.DataCaptureMetadata
-	nextFile  *File
-	lock      sync.Mutex
+	// Quota, if set, limits the disk usage of the capture directory that the buffer writes to.
+	Quota    *Quota
+	nextFile *File
+	lock     sync.Mutex
 }
 
 // NewBuffer returns a new Buffer.
@@ -35,10 +37,16 @@ func NewBuffer(dir string, md *v1.DataCaptureMetadata) *Buffer {
 // Write writes item onto b. Binary sensor data is written to its own file.
 // Tabular data is written to disk in MaxFileSize sized files. Files that are still being written to are indicated
 // with the extension InProgressFileExt. Files that have finished being written to are indicated by FileExt.
+// Items that the buffer's quota does not allow are dropped without an error, so that capture carries on once the
+// directory is back under its quota.
 func (b *Buffer) Write(item *v1.SensorData) error {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
+	if b.Quota != nil && !b.Quota.Allow(item) {
+		return nil
+	}
+
 	if item.GetBinary() != nil {
 		binFile, err := NewFile(b.Directory, b.MetaData)
 		if err != nil {
@@ -14,6 +14,8 @@ import (
 	"github.com/matttproud/golang_protobuf_extensions/pbutil"
 	"github.com/pkg/errors"
 	v1 "go.viam.com/api/app/datasync/v1"
+	goutils "go.viam.com/utils"
+	"golang.org/x/exp/slices"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -266,6 +268,8 @@ func GetFileExt(dataType v1.DataType, methodName string, parameters map[string]s
 				return ".jpeg"
 			case utils.MimeTypePNG:
 				return ".png"
+			case utils.MimeTypeWebP:
+				return ".webp"
 			case utils.MimeTypePCD:
 				return ".pcd"
 			default:
@@ -295,6 +299,57 @@ func SensorDataFromFilePath(filePath string) ([]*v1.SensorData, error) {
 	return SensorDataFromFile(dcFile)
 }
 
+// AddTags adds the tags that the completed data capture file at path does not have yet to its metadata, and returns
+// whether there were any.
+func AddTags(path string, tags []string) (bool, error) {
+	if filepath.Ext(path) != FileExt {
+		return false, errors.Errorf("%s is not a completed data capture file", path)
+	}
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	//nolint:errcheck
+	defer f.Close()
+	md := &v1.DataCaptureMetadata{}
+	if _, err := pbutil.ReadDelimited(f, md); err != nil {
+		return false, errors.Wrapf(err, "failed to read DataCaptureMetadata from %s", path)
+	}
+	added := false
+	for _, tag := range tags {
+		if !slices.Contains(md.Tags, tag) {
+			md.Tags = append(md.Tags, tag)
+			added = true
+		}
+	}
+	if !added {
+		return false, nil
+	}
+
+	// The file is rewritten next to the original, as one in progress so that it is still synced if it is left over,
+	// and then replaces it.
+	tmpPath := strings.TrimSuffix(path, FileExt) + InProgressFileExt
+	//nolint:gosec
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return false, err
+	}
+	if _, err := pbutil.WriteDelimited(tmp, md); err != nil {
+		goutils.UncheckedError(tmp.Close())
+		return false, err
+	}
+	// the rest of the file is the readings, which are copied as they are
+	if _, err := io.Copy(tmp, f); err != nil {
+		goutils.UncheckedError(tmp.Close())
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmpPath, path)
+}
+
 // SensorDataFromFile returns all readings in f.
 func SensorDataFromFile(f *File) ([]*v1.SensorData, error) {
 	f.Reset()
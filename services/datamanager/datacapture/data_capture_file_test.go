@@ -1,6 +1,8 @@
 package datacapture
 
 import (
+	"os"
+	"strings"
 	"testing"
 
 	v1 "go.viam.com/api/app/datasync/v1"
@@ -115,3 +117,45 @@ func TestReadCorruptedFile(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, len(sd), test.ShouldEqual, numReadings)
 }
+
+func TestAddTags(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewFile(dir, &v1.DataCaptureMetadata{Tags: []string{"a"}})
+	test.That(t, err, test.ShouldBeNil)
+	numReadings := 10
+	for i := 0; i < numReadings; i++ {
+		err := f.WriteNext(&v1.SensorData{
+			Metadata: &v1.SensorMetadata{},
+			Data:     &v1.SensorData_Struct{Struct: &structpb.Struct{}},
+		})
+		test.That(t, err, test.ShouldBeNil)
+	}
+	// files still being captured to cannot be tagged
+	_, err = AddTags(f.GetPath(), []string{"b"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, f.Close(), test.ShouldBeNil)
+	path := strings.TrimSuffix(f.GetPath(), InProgressFileExt) + FileExt
+
+	added, err := AddTags(path, []string{"a", "b"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, added, test.ShouldBeTrue)
+	added, err = AddTags(path, []string{"b"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, added, test.ShouldBeFalse)
+
+	//nolint:gosec
+	osFile, err := os.Open(path)
+	test.That(t, err, test.ShouldBeNil)
+	defer osFile.Close()
+	tagged, err := ReadFile(osFile)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tagged.ReadMetadata().GetTags(), test.ShouldResemble, []string{"a", "b"})
+	sd, err := SensorDataFromFile(tagged)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(sd), test.ShouldEqual, numReadings)
+
+	// the rewritten file is the only one left
+	entries, err := os.ReadDir(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, entries, test.ShouldHaveLength, 1)
+}
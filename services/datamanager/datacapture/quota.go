@@ -0,0 +1,202 @@
+package datacapture
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// QuotaPolicy is what data capture does once its directory uses more disk than its quota.
+type QuotaPolicy string
+
+const (
+	// QuotaPolicyDropOldest deletes the oldest completed capture files until the directory is under its quota.
+	QuotaPolicyDropOldest QuotaPolicy = "drop_oldest"
+	// QuotaPolicyPauseCapture stops writing captured data until the directory is under its quota, such as once
+	// it has been synced.
+	QuotaPolicyPauseCapture QuotaPolicy = "pause_capture"
+	// QuotaPolicyAlert only logs an error when the directory goes over its quota.
+	QuotaPolicyAlert QuotaPolicy = "alert"
+)
+
+// ValidateQuotaPolicy returns an error if the policy is not one of the known ones. The empty policy is
+// QuotaPolicyDropOldest.
+func ValidateQuotaPolicy(policy QuotaPolicy) error {
+	switch policy {
+	case "", QuotaPolicyDropOldest, QuotaPolicyPauseCapture, QuotaPolicyAlert:
+		return nil
+	default:
+		return errors.Errorf("unknown quota policy %q, expected one of %q, %q or %q",
+			policy, QuotaPolicyDropOldest, QuotaPolicyPauseCapture, QuotaPolicyAlert)
+	}
+}
+
+// QuotaUsage is the disk usage of a capture directory, and what its quota has done about it.
+type QuotaUsage struct {
+	UsedBytes int64
+	// MaxBytes is 0 when there is no quota.
+	MaxBytes        int64
+	Policy          QuotaPolicy
+	Exceeded        bool
+	DroppedFiles    int
+	SkippedReadings int
+}
+
+// Quota keeps the disk usage of a capture directory under a maximum. Usage is measured by Check, and estimated from
+// the data written by the Buffers that use the quota in between, so that capture pauses as soon as the directory is
+// full rather than at the next check.
+type Quota struct {
+	logger golog.Logger
+
+	mu              sync.Mutex
+	dir             string
+	maxBytes        int64
+	policy          QuotaPolicy
+	usedBytes       int64
+	exceeded        bool
+	droppedFiles    int
+	skippedReadings int
+}
+
+// NewQuota returns a quota of maxBytes, or no quota if 0, on the directory.
+func NewQuota(dir string, maxBytes int64, policy QuotaPolicy, logger golog.Logger) *Quota {
+	q := &Quota{logger: logger}
+	q.SetLimit(dir, maxBytes, policy)
+	return q
+}
+
+// SetLimit changes the directory, maximum and policy of the quota, which take effect at the next Check.
+func (q *Quota) SetLimit(dir string, maxBytes int64, policy QuotaPolicy) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if policy == "" {
+		policy = QuotaPolicyDropOldest
+	}
+	q.dir = dir
+	q.maxBytes = maxBytes
+	q.policy = policy
+}
+
+// Allow reports whether a reading may be written to the directory, and counts it towards its usage if so. Only the
+// QuotaPolicyPauseCapture policy refuses readings, while the directory is over its quota.
+func (q *Quota) Allow(item *v1.SensorData) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.maxBytes <= 0 {
+		return true
+	}
+	if q.exceeded && q.policy == QuotaPolicyPauseCapture {
+		q.skippedReadings++
+		return false
+	}
+	q.usedBytes += int64(proto.Size(item))
+	if q.usedBytes > q.maxBytes && q.policy == QuotaPolicyPauseCapture {
+		q.exceeded = true
+	}
+	return true
+}
+
+// Check measures the disk usage of the directory and applies the policy if it is over the quota.
+func (q *Quota) Check() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	files, used, err := captureFiles(q.dir)
+	if err != nil {
+		return err
+	}
+	q.usedBytes = used
+	if q.maxBytes <= 0 || used <= q.maxBytes {
+		if q.exceeded {
+			q.logger.Infow("capture directory is back under its quota", "dir", q.dir, "used_bytes", used)
+		}
+		q.exceeded = false
+		return nil
+	}
+
+	switch q.policy {
+	case QuotaPolicyDropOldest:
+		for _, f := range files {
+			if q.usedBytes <= q.maxBytes {
+				break
+			}
+			// files still being written to are left alone
+			if filepath.Ext(f.path) == InProgressFileExt {
+				continue
+			}
+			if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			q.usedBytes -= f.size
+			q.droppedFiles++
+		}
+		q.exceeded = q.usedBytes > q.maxBytes
+		if q.exceeded {
+			q.logger.Warnw("capture directory is over its quota with only files in progress left to drop",
+				"dir", q.dir, "used_bytes", q.usedBytes, "max_bytes", q.maxBytes)
+		}
+	case QuotaPolicyPauseCapture, QuotaPolicyAlert:
+		if !q.exceeded {
+			q.logger.Errorw("capture directory is over its quota", "dir", q.dir, "used_bytes", used,
+				"max_bytes", q.maxBytes, "policy", q.policy)
+		}
+		q.exceeded = true
+	}
+	return nil
+}
+
+// Usage returns the disk usage of the directory as of the last Check, and the writes since.
+func (q *Quota) Usage() QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return QuotaUsage{
+		UsedBytes:       q.usedBytes,
+		MaxBytes:        q.maxBytes,
+		Policy:          q.policy,
+		Exceeded:        q.exceeded,
+		DroppedFiles:    q.droppedFiles,
+		SkippedReadings: q.skippedReadings,
+	}
+}
+
+type captureFileInfo struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// captureFiles returns the data capture files in dir from oldest to newest, and the total size of all of its files.
+func captureFiles(dir string) ([]captureFileInfo, int64, error) {
+	var files []captureFileInfo
+	var used int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// files may be removed by sync while walking
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		used += info.Size()
+		if ext := filepath.Ext(path); ext == FileExt || ext == InProgressFileExt {
+			files = append(files, captureFileInfo{path: path, size: info.Size(), modTime: info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	return files, used, nil
+}
@@ -0,0 +1,99 @@
+package datacapture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+)
+
+// writeTestFile writes a file of the given size that was last modified age ago.
+func writeTestFile(t *testing.T, path string, size int, age time.Duration) {
+	t.Helper()
+	test.That(t, os.WriteFile(path, make([]byte, size), 0o600), test.ShouldBeNil)
+	modTime := time.Now().Add(-age)
+	test.That(t, os.Chtimes(path, modTime, modTime), test.ShouldBeNil)
+}
+
+func TestValidateQuotaPolicy(t *testing.T) {
+	test.That(t, ValidateQuotaPolicy(""), test.ShouldBeNil)
+	test.That(t, ValidateQuotaPolicy(QuotaPolicyAlert), test.ShouldBeNil)
+	test.That(t, ValidateQuotaPolicy("delete_everything"), test.ShouldNotBeNil)
+}
+
+func TestQuotaDropOldest(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "old"+FileExt), 100, 3*time.Hour)
+	writeTestFile(t, filepath.Join(dir, "older"+InProgressFileExt), 100, 4*time.Hour)
+	writeTestFile(t, filepath.Join(dir, "new"+FileExt), 100, time.Hour)
+
+	q := NewQuota(dir, 250, "", golog.NewTestLogger(t))
+	test.That(t, q.Check(), test.ShouldBeNil)
+	usage := q.Usage()
+	test.That(t, usage.Policy, test.ShouldEqual, QuotaPolicyDropOldest)
+	test.That(t, usage.UsedBytes, test.ShouldEqual, 200)
+	test.That(t, usage.DroppedFiles, test.ShouldEqual, 1)
+	test.That(t, usage.Exceeded, test.ShouldBeFalse)
+
+	// the oldest completed file is dropped, and the file in progress is kept
+	_, err := os.Stat(filepath.Join(dir, "old"+FileExt))
+	test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+	_, err = os.Stat(filepath.Join(dir, "older"+InProgressFileExt))
+	test.That(t, err, test.ShouldBeNil)
+	_, err = os.Stat(filepath.Join(dir, "new"+FileExt))
+	test.That(t, err, test.ShouldBeNil)
+}
+
+func TestQuotaPauseCapture(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "old"+FileExt), 100, time.Hour)
+
+	q := NewQuota(dir, 50, QuotaPolicyPauseCapture, golog.NewTestLogger(t))
+	test.That(t, q.Check(), test.ShouldBeNil)
+	test.That(t, q.Usage().Exceeded, test.ShouldBeTrue)
+
+	// nothing is written while the directory is over its quota
+	b := NewBuffer(dir, &v1.DataCaptureMetadata{})
+	b.Quota = q
+	test.That(t, b.Write(binarySensorData), test.ShouldBeNil)
+	test.That(t, b.Flush(), test.ShouldBeNil)
+	entries, err := os.ReadDir(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, entries, test.ShouldHaveLength, 1)
+	test.That(t, q.Usage().SkippedReadings, test.ShouldEqual, 1)
+
+	// capture carries on once the directory has been synced
+	test.That(t, os.Remove(filepath.Join(dir, "old"+FileExt)), test.ShouldBeNil)
+	test.That(t, q.Check(), test.ShouldBeNil)
+	test.That(t, q.Usage().Exceeded, test.ShouldBeFalse)
+	test.That(t, b.Write(binarySensorData), test.ShouldBeNil)
+	test.That(t, b.Write(binarySensorData), test.ShouldBeNil)
+	entries, err = os.ReadDir(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, entries, test.ShouldHaveLength, 2)
+
+	// the estimated usage pauses capture before the next check
+	test.That(t, q.Usage().Exceeded, test.ShouldBeTrue)
+}
+
+func TestQuotaAlert(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "old"+FileExt), 100, time.Hour)
+
+	q := NewQuota(dir, 50, QuotaPolicyAlert, golog.NewTestLogger(t))
+	test.That(t, q.Check(), test.ShouldBeNil)
+	usage := q.Usage()
+	test.That(t, usage.Exceeded, test.ShouldBeTrue)
+	test.That(t, usage.UsedBytes, test.ShouldEqual, 100)
+	test.That(t, q.Allow(binarySensorData), test.ShouldBeTrue)
+
+	// without a maximum the usage is only measured
+	q.SetLimit(dir, 0, QuotaPolicyAlert)
+	test.That(t, q.Check(), test.ShouldBeNil)
+	test.That(t, q.Usage().Exceeded, test.ShouldBeFalse)
+	test.That(t, q.Usage().UsedBytes, test.ShouldEqual, 100)
+}
@@ -0,0 +1,81 @@
+package datacapture
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	v1 "go.viam.com/api/app/datasync/v1"
+)
+
+// TriggeredBuffer is a BufferedWriter that only writes the readings captured around triggers, such as a detection
+// by a vision service. It keeps the readings of the last preTrigger in memory, writes them out when it is triggered,
+// and then writes readings through until the time given to the last trigger has passed.
+type TriggeredBuffer struct {
+	target     BufferedWriter
+	preTrigger time.Duration
+	clock      clock.Clock
+
+	mu      sync.Mutex
+	pending []pendingReading
+	until   time.Time
+}
+
+type pendingReading struct {
+	at   time.Time
+	item *v1.SensorData
+}
+
+// NewTriggeredBuffer returns a TriggeredBuffer that writes to target.
+func NewTriggeredBuffer(target BufferedWriter, preTrigger time.Duration, clk clock.Clock) *TriggeredBuffer {
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &TriggeredBuffer{target: target, preTrigger: preTrigger, clock: clk}
+}
+
+// Write writes the reading if the buffer has been triggered recently enough, and otherwise keeps it in memory until it
+// is older than the pre-trigger duration.
+func (b *TriggeredBuffer) Write(item *v1.SensorData) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.clock.Now()
+	if now.Before(b.until) {
+		return b.target.Write(item)
+	}
+	b.pending = append(b.pending, pendingReading{at: now, item: item})
+	dropped := 0
+	for dropped < len(b.pending) && now.Sub(b.pending[dropped].at) > b.preTrigger {
+		dropped++
+	}
+	b.pending = b.pending[dropped:]
+	return nil
+}
+
+// Trigger writes out the readings of the pre-trigger duration, and has readings written through for postTrigger from
+// now on, or until the end of an earlier trigger if that is later.
+func (b *TriggeredBuffer) Trigger(postTrigger time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if until := b.clock.Now().Add(postTrigger); until.After(b.until) {
+		b.until = until
+	}
+	pending := b.pending
+	b.pending = nil
+	for _, r := range pending {
+		if err := b.target.Write(r.item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush flushes the readings written so far to disk. Readings still waiting for a trigger are kept in memory.
+func (b *TriggeredBuffer) Flush() error {
+	return b.target.Flush()
+}
+
+// Path returns the path of the directory the readings are written to.
+func (b *TriggeredBuffer) Path() string {
+	return b.target.Path()
+}
@@ -0,0 +1,71 @@
+package datacapture
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type recordingWriter struct {
+	written []*v1.SensorData
+}
+
+func (w *recordingWriter) Write(item *v1.SensorData) error {
+	w.written = append(w.written, item)
+	return nil
+}
+
+func (w *recordingWriter) Flush() error {
+	return nil
+}
+
+func (w *recordingWriter) Path() string {
+	return "dir"
+}
+
+func TestTriggeredBuffer(t *testing.T) {
+	mockClock := clock.NewMock()
+	target := &recordingWriter{}
+	b := NewTriggeredBuffer(target, 2*time.Second, mockClock)
+	reading := func(i int) *v1.SensorData {
+		return &v1.SensorData{Data: &v1.SensorData_Struct{Struct: &structpb.Struct{
+			Fields: map[string]*structpb.Value{"i": structpb.NewNumberValue(float64(i))},
+		}}}
+	}
+	index := func(item *v1.SensorData) int {
+		return int(item.GetStruct().GetFields()["i"].GetNumberValue())
+	}
+
+	// a reading a second that is held in memory until triggered
+	for i := 0; i < 5; i++ {
+		test.That(t, b.Write(reading(i)), test.ShouldBeNil)
+		mockClock.Add(time.Second)
+	}
+	test.That(t, target.written, test.ShouldBeEmpty)
+	test.That(t, b.Path(), test.ShouldEqual, "dir")
+
+	// the readings of the last two seconds are written out
+	test.That(t, b.Trigger(3*time.Second), test.ShouldBeNil)
+	test.That(t, target.written, test.ShouldHaveLength, 3)
+	test.That(t, index(target.written[0]), test.ShouldEqual, 2)
+	test.That(t, index(target.written[2]), test.ShouldEqual, 4)
+
+	// and the readings after until the post-trigger time has passed
+	for i := 5; i < 10; i++ {
+		test.That(t, b.Write(reading(i)), test.ShouldBeNil)
+		mockClock.Add(time.Second)
+	}
+	test.That(t, target.written, test.ShouldHaveLength, 6)
+	test.That(t, index(target.written[5]), test.ShouldEqual, 7)
+
+	// an earlier trigger that ends later is not cut short
+	test.That(t, b.Trigger(10*time.Second), test.ShouldBeNil)
+	test.That(t, b.Trigger(time.Second), test.ShouldBeNil)
+	mockClock.Add(5 * time.Second)
+	test.That(t, b.Write(reading(10)), test.ShouldBeNil)
+	test.That(t, index(target.written[len(target.written)-1]), test.ShouldEqual, 10)
+}
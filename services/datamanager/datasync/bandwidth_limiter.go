@@ -0,0 +1,58 @@
+package datasync
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// bandwidthLimiter limits the rate at which bytes are uploaded, shared across all uploads of a syncer. Up to a
+// second's worth of unused bandwidth can be saved up, and a send larger than that is let through and paid back by
+// the sends after it.
+type bandwidthLimiter struct {
+	mu             sync.Mutex
+	bytesPerSecond float64
+	available      float64
+	last           time.Time
+}
+
+// setLimit sets the maximum upload rate, or no maximum if bytesPerSecond is 0.
+func (l *bandwidthLimiter) setLimit(bytesPerSecond float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.bytesPerSecond = bytesPerSecond
+	l.available = math.Min(l.available, bytesPerSecond)
+}
+
+// wait blocks until n bytes can be sent without going over the limit, or until ctx is done.
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	if l.bytesPerSecond <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.available = math.Min(l.bytesPerSecond, l.available+now.Sub(l.last).Seconds()*l.bytesPerSecond)
+	}
+	l.last = now
+	l.available -= float64(n)
+	var delay time.Duration
+	if l.available < 0 {
+		delay = time.Duration(-l.available / l.bytesPerSecond * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package datasync
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// Filter selects the files to sync. A file matches if it has any of the tags, was captured from any of the resources
+// and was captured within the time range, and any of those left empty matches everything. Files that were not
+// captured by the data manager, such as those in additional sync paths, have no tags or resource.
+type Filter struct {
+	Tags []string `json:"tags,omitempty"`
+	// Resources are the names of the resources data was captured from.
+	Resources []string   `json:"resources,omitempty"`
+	Start     *time.Time `json:"start,omitempty"`
+	End       *time.Time `json:"end,omitempty"`
+}
+
+// FilterFromMap returns the filter in the map, such as from the extra parameters of a sync, which has the same fields
+// as the JSON of a Filter with times formatted as RFC 3339.
+func FilterFromMap(m map[string]interface{}) (Filter, error) {
+	var f Filter
+	b, err := json.Marshal(m)
+	if err != nil {
+		return Filter{}, err
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return Filter{}, errors.Wrap(err, "invalid sync filter")
+	}
+	if err := f.Validate(); err != nil {
+		return Filter{}, err
+	}
+	return f, nil
+}
+
+// Validate returns an error if the time range of the filter is empty.
+func (f Filter) Validate() error {
+	if f.Start != nil && f.End != nil && f.End.Before(*f.Start) {
+		return errors.New("the end of a sync filter cannot be before its start")
+	}
+	return nil
+}
+
+// IsEmpty reports whether the filter matches every file.
+func (f Filter) IsEmpty() bool {
+	return len(f.Tags) == 0 && len(f.Resources) == 0 && f.Start == nil && f.End == nil
+}
+
+// Matches reports whether the file at path matches the filter. The capture time of a data capture file spans from
+// its first reading to when it was last written to, and that of any other file is when it was last written to.
+func (f Filter) Matches(path string) (bool, error) {
+	if f.IsEmpty() {
+		return true, nil
+	}
+	//nolint:gosec
+	osFile, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	//nolint:errcheck
+	defer osFile.Close()
+	info, err := osFile.Stat()
+	if err != nil {
+		return false, err
+	}
+	first, last := info.ModTime(), info.ModTime()
+
+	if !datacapture.IsDataCaptureFile(osFile) {
+		if len(f.Tags) > 0 || len(f.Resources) > 0 {
+			return false, nil
+		}
+		return f.inTimeRange(first, last), nil
+	}
+
+	captureFile, err := datacapture.ReadFile(osFile)
+	if err != nil {
+		return false, err
+	}
+	md := captureFile.ReadMetadata()
+	if len(f.Tags) > 0 && !slices.ContainsFunc(md.GetTags(), func(tag string) bool { return slices.Contains(f.Tags, tag) }) {
+		return false, nil
+	}
+	if len(f.Resources) > 0 && !slices.Contains(f.Resources, md.GetComponentName()) {
+		return false, nil
+	}
+	if f.Start == nil && f.End == nil {
+		return true, nil
+	}
+	if reading, err := captureFile.ReadNext(); err == nil && reading.GetMetadata().GetTimeRequested() != nil {
+		first = reading.GetMetadata().GetTimeRequested().AsTime()
+	}
+	return f.inTimeRange(first, last), nil
+}
+
+// inTimeRange reports whether the time span from first to last overlaps the time range of the filter.
+func (f Filter) inTimeRange(first, last time.Time) bool {
+	if f.Start != nil && last.Before(*f.Start) {
+		return false
+	}
+	if f.End != nil && first.After(*f.End) {
+		return false
+	}
+	return true
+}
@@ -11,4 +11,6 @@ func NewNoopManager() Manager {
 
 func (m *noopManager) SyncFile(path string) {}
 
+func (m *noopManager) SetMaxBandwidth(bytesPerSecond float64) {}
+
 func (m *noopManager) Close() {}
@@ -31,6 +31,8 @@ var (
 // Manager is responsible for enqueuing files in captureDir and uploading them to the cloud.
 type Manager interface {
 	SyncFile(path string)
+	// SetMaxBandwidth limits the rate of all uploads to bytesPerSecond, or removes the limit if 0.
+	SetMaxBandwidth(bytesPerSecond float64)
 	Close()
 }
 
@@ -45,6 +47,11 @@ type syncer struct {
 
 	progressLock sync.Mutex
 	inProgress   map[string]bool
+	// uploadedReadings is the number of readings of each data capture file that have been uploaded, so that a
+	// failed upload resumes after them.
+	uploadedReadings map[string]int
+
+	limiter bandwidthLimiter
 
 	syncErrs   chan error
 	closed     atomic.Bool
@@ -63,8 +70,9 @@ func NewManager(identity string, client v1.DataSyncServiceClient, logger golog.L
 		logger:     logger,
 		cancelCtx:  cancelCtx,
 		cancelFunc: cancelFunc,
-		inProgress: make(map[string]bool),
-		syncErrs:   make(chan error, 10),
+		inProgress:       make(map[string]bool),
+		uploadedReadings: make(map[string]int),
+		syncErrs:         make(chan error, 10),
 	}
 	ret.logRoutine.Add(1)
 	goutils.PanicCapturingGo(func() {
@@ -85,6 +93,10 @@ func (s *syncer) Close() {
 	_ = s.logger.Sync()
 }
 
+func (s *syncer) SetMaxBandwidth(bytesPerSecond float64) {
+	s.limiter.setLimit(bytesPerSecond)
+}
+
 func (s *syncer) SyncFile(path string) {
 	s.backgroundWorkers.Add(1)
 	goutils.PanicCapturingGo(func() {
@@ -130,7 +142,10 @@ func (s *syncer) syncDataCaptureFile(f *datacapture.File) {
 	uploadErr := exponentialRetry(
 		s.cancelCtx,
 		func(ctx context.Context) error {
-			err := uploadDataCaptureFile(ctx, s.client, f, s.partID)
+			err := uploadDataCaptureFile(ctx, s.client, f, s.partID, s.getUploadedReadings(f.GetPath()), &s.limiter,
+				func(uploaded int) {
+					s.setUploadedReadings(f.GetPath(), uploaded)
+				})
 			if err != nil {
 				s.syncErrs <- errors.Wrap(err, fmt.Sprintf("error uploading file %s", f.GetPath()))
 			}
@@ -144,6 +159,7 @@ func (s *syncer) syncDataCaptureFile(f *datacapture.File) {
 		}
 		return
 	}
+	s.setUploadedReadings(f.GetPath(), 0)
 	if err := f.Delete(); err != nil {
 		s.syncErrs <- errors.Wrap(err, "error deleting data capture file")
 		return
@@ -154,7 +170,7 @@ func (s *syncer) syncArbitraryFile(f *os.File) {
 	uploadErr := exponentialRetry(
 		s.cancelCtx,
 		func(ctx context.Context) error {
-			err := uploadArbitraryFile(ctx, s.client, f, s.partID)
+			err := uploadArbitraryFile(ctx, s.client, f, s.partID, &s.limiter)
 			if err != nil {
 				s.syncErrs <- errors.Wrap(err, fmt.Sprintf("error uploading file %s", f.Name()))
 			}
@@ -191,6 +207,24 @@ func (s *syncer) unmarkInProgress(path string) {
 	delete(s.inProgress, path)
 }
 
+func (s *syncer) getUploadedReadings(path string) int {
+	s.progressLock.Lock()
+	defer s.progressLock.Unlock()
+	return s.uploadedReadings[path]
+}
+
+// setUploadedReadings records that the first uploaded readings of the file at path have been uploaded, or forgets the
+// file if 0.
+func (s *syncer) setUploadedReadings(path string, uploaded int) {
+	s.progressLock.Lock()
+	defer s.progressLock.Unlock()
+	if uploaded == 0 {
+		delete(s.uploadedReadings, path)
+		return
+	}
+	s.uploadedReadings[path] = uploaded
+}
+
 func (s *syncer) logSyncErrs() {
 	for err := range s.syncErrs {
 		if s.closed.Load() {
@@ -0,0 +1,179 @@
+package datasync
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+func TestSyncWindows(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2023, 1, 1, hour, min, 0, 0, time.Local)
+	}
+	day := SyncWindow{Start: "09:00", End: "17:30"}
+	test.That(t, day.Validate(), test.ShouldBeNil)
+	test.That(t, day.Contains(at(9, 0)), test.ShouldBeTrue)
+	test.That(t, day.Contains(at(17, 29)), test.ShouldBeTrue)
+	test.That(t, day.Contains(at(17, 30)), test.ShouldBeFalse)
+	test.That(t, day.Contains(at(3, 0)), test.ShouldBeFalse)
+
+	night := SyncWindow{Start: "22:00", End: "06:00"}
+	test.That(t, night.Contains(at(23, 0)), test.ShouldBeTrue)
+	test.That(t, night.Contains(at(5, 59)), test.ShouldBeTrue)
+	test.That(t, night.Contains(at(12, 0)), test.ShouldBeFalse)
+
+	test.That(t, InSyncWindow(nil, at(12, 0)), test.ShouldBeTrue)
+	test.That(t, InSyncWindow([]SyncWindow{day, night}, at(12, 0)), test.ShouldBeTrue)
+	test.That(t, InSyncWindow([]SyncWindow{night}, at(12, 0)), test.ShouldBeFalse)
+
+	test.That(t, SyncWindow{Start: "25:00", End: "06:00"}.Validate(), test.ShouldBeError,
+		`sync window start "25:00" must be formatted as HH:MM`)
+	test.That(t, SyncWindow{Start: "22:00"}.Validate(), test.ShouldBeError, `sync window end "" must be formatted as HH:MM`)
+}
+
+func TestBandwidthLimiter(t *testing.T) {
+	var limiter bandwidthLimiter
+	ctx := context.Background()
+
+	// unlimited by default
+	start := time.Now()
+	test.That(t, limiter.wait(ctx, 1e9), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeLessThan, 50*time.Millisecond)
+
+	limiter.setLimit(10000)
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		test.That(t, limiter.wait(ctx, 1000), test.ShouldBeNil)
+	}
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 250*time.Millisecond)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	test.That(t, limiter.wait(cancelCtx, 1e6), test.ShouldBeError, context.Canceled)
+}
+
+type flakyDataSyncClient struct {
+	v1.DataSyncServiceClient
+	failAfter int
+	requests  []*v1.DataCaptureUploadRequest
+}
+
+func (c *flakyDataSyncClient) DataCaptureUpload(
+	ctx context.Context,
+	in *v1.DataCaptureUploadRequest,
+	opts ...grpc.CallOption,
+) (*v1.DataCaptureUploadResponse, error) {
+	if len(c.requests) == c.failAfter {
+		c.failAfter = -1
+		return nil, errors.New("connection lost")
+	}
+	c.requests = append(c.requests, in)
+	return &v1.DataCaptureUploadResponse{}, nil
+}
+
+func TestUploadDataCaptureFileResumes(t *testing.T) {
+	f, err := datacapture.NewFile(t.TempDir(), &v1.DataCaptureMetadata{Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR})
+	test.That(t, err, test.ShouldBeNil)
+	defer f.Close()
+	reading, err := structpb.NewStruct(map[string]interface{}{"reading": 1})
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 5; i++ {
+		test.That(t, f.WriteNext(&v1.SensorData{Data: &v1.SensorData_Struct{Struct: reading}}), test.ShouldBeNil)
+	}
+	test.That(t, f.Flush(), test.ShouldBeNil)
+
+	// send every reading in its own request
+	prevBatchSize := MaxCaptureUploadBatchSize
+	MaxCaptureUploadBatchSize = 1
+	defer func() {
+		MaxCaptureUploadBatchSize = prevBatchSize
+	}()
+
+	client := &flakyDataSyncClient{failAfter: 3}
+	var limiter bandwidthLimiter
+	uploaded := 0
+	progress := func(n int) {
+		uploaded = n
+	}
+	err = uploadDataCaptureFile(context.Background(), client, f, "part", uploaded, &limiter, progress)
+	test.That(t, err, test.ShouldBeError, "connection lost")
+	test.That(t, uploaded, test.ShouldEqual, 3)
+
+	// the retry only sends the readings that were not uploaded yet
+	err = uploadDataCaptureFile(context.Background(), client, f, "part", uploaded, &limiter, progress)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, uploaded, test.ShouldEqual, 5)
+	test.That(t, client.requests, test.ShouldHaveLength, 5)
+	for _, req := range client.requests {
+		test.That(t, req.GetSensorContents(), test.ShouldHaveLength, 1)
+		test.That(t, req.GetMetadata().GetPartId(), test.ShouldEqual, "part")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	dir := t.TempDir()
+	captured := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	writeCaptureFile := func(component string, tags []string) string {
+		f, err := datacapture.NewFile(dir, &v1.DataCaptureMetadata{ComponentName: component, Tags: tags})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, f.WriteNext(&v1.SensorData{
+			Metadata: &v1.SensorMetadata{TimeRequested: timestamppb.New(captured)},
+			Data:     &v1.SensorData_Struct{Struct: &structpb.Struct{}},
+		}), test.ShouldBeNil)
+		test.That(t, f.Close(), test.ShouldBeNil)
+		path := strings.TrimSuffix(f.GetPath(), datacapture.InProgressFileExt) + datacapture.FileExt
+		test.That(t, os.Chtimes(path, captured, captured.Add(time.Minute)), test.ShouldBeNil)
+		return path
+	}
+	camera := writeCaptureFile("camera1", []string{"keep"})
+	arm := writeCaptureFile("arm1", nil)
+	other := filepath.Join(dir, "notes.txt")
+	test.That(t, os.WriteFile(other, []byte("notes"), 0o600), test.ShouldBeNil)
+	test.That(t, os.Chtimes(other, captured, captured), test.ShouldBeNil)
+
+	matches := func(f Filter) []string {
+		var ret []string
+		for _, path := range []string{camera, arm, other} {
+			match, err := f.Matches(path)
+			test.That(t, err, test.ShouldBeNil)
+			if match {
+				ret = append(ret, path)
+			}
+		}
+		return ret
+	}
+	test.That(t, matches(Filter{}), test.ShouldResemble, []string{camera, arm, other})
+	test.That(t, matches(Filter{Tags: []string{"keep", "other"}}), test.ShouldResemble, []string{camera})
+	test.That(t, matches(Filter{Resources: []string{"arm1"}}), test.ShouldResemble, []string{arm})
+
+	before, after := captured.Add(-time.Hour), captured.Add(time.Hour)
+	test.That(t, matches(Filter{Start: &before, End: &after}), test.ShouldResemble, []string{camera, arm, other})
+	test.That(t, matches(Filter{Start: &after}), test.ShouldBeEmpty)
+	test.That(t, matches(Filter{End: &before}), test.ShouldBeEmpty)
+	// the span of a capture file runs until it was last written to
+	justAfter := captured.Add(30 * time.Second)
+	test.That(t, matches(Filter{Start: &justAfter}), test.ShouldResemble, []string{camera, arm})
+
+	f, err := FilterFromMap(map[string]interface{}{"resources": []interface{}{"camera1"}, "start": "2023-06-01T00:00:00Z"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, f.Resources, test.ShouldResemble, []string{"camera1"})
+	test.That(t, f.Start.Equal(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)), test.ShouldBeTrue)
+	test.That(t, matches(f), test.ShouldResemble, []string{camera})
+
+	_, err = FilterFromMap(map[string]interface{}{"start": "2023-06-02T00:00:00Z", "end": "2023-06-01T00:00:00Z"})
+	test.That(t, err, test.ShouldBeError, "the end of a sync filter cannot be before its start")
+	_, err = FilterFromMap(map[string]interface{}{"start": "yesterday"})
+	test.That(t, err, test.ShouldNotBeNil)
+}
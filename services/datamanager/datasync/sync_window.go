@@ -0,0 +1,64 @@
+package datasync
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const syncWindowTimeLayout = "15:04"
+
+// SyncWindow is a daily window of local time, such as overnight, during which scheduled syncs may run. Start and
+// End are formatted as "HH:MM", and a window that ends before it starts wraps past midnight.
+type SyncWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Validate returns an error if the start or end of the window is not a time of day.
+func (w SyncWindow) Validate() error {
+	if _, err := time.Parse(syncWindowTimeLayout, w.Start); err != nil {
+		return errors.Errorf("sync window start %q must be formatted as HH:MM", w.Start)
+	}
+	if _, err := time.Parse(syncWindowTimeLayout, w.End); err != nil {
+		return errors.Errorf("sync window end %q must be formatted as HH:MM", w.End)
+	}
+	return nil
+}
+
+// Contains reports whether the time of day of t is within the window. A window that starts and ends at the same
+// time contains the whole day.
+func (w SyncWindow) Contains(t time.Time) bool {
+	start, err := time.Parse(syncWindowTimeLayout, w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(syncWindowTimeLayout, w.End)
+	if err != nil {
+		return false
+	}
+	startMins := start.Hour()*60 + start.Minute()
+	endMins := end.Hour()*60 + end.Minute()
+	mins := t.Hour()*60 + t.Minute()
+	switch {
+	case startMins == endMins:
+		return true
+	case startMins < endMins:
+		return mins >= startMins && mins < endMins
+	default:
+		return mins >= startMins || mins < endMins
+	}
+}
+
+// InSyncWindow reports whether t is within any of the windows. Any time is if there are no windows.
+func InSyncWindow(windows []SyncWindow, t time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	for _, w := range windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
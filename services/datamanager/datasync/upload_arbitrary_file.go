@@ -13,7 +13,18 @@ import (
 // UploadChunkSize defines the size of the data included in each message of a FileUpload stream.
 var UploadChunkSize = 64 * 1024
 
-func uploadArbitraryFile(ctx context.Context, client v1.DataSyncServiceClient, f *os.File, partID string) error {
+// uploadArbitraryFile uploads the whole of f. FileUpload streams cannot be resumed, so a retried upload starts over from
+// the beginning of the file.
+func uploadArbitraryFile(
+	ctx context.Context,
+	client v1.DataSyncServiceClient,
+	f *os.File,
+	partID string,
+	limiter *bandwidthLimiter,
+) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
 	stream, err := client.FileUpload(ctx)
 	if err != nil {
 		return err
@@ -36,7 +47,7 @@ func uploadArbitraryFile(ctx context.Context, client v1.DataSyncServiceClient, f
 		return err
 	}
 
-	if err := sendFileUploadRequests(ctx, stream, f); err != nil {
+	if err := sendFileUploadRequests(ctx, stream, f, limiter); err != nil {
 		return errors.Wrapf(err, "error syncing %s", f.Name())
 	}
 
@@ -48,7 +59,12 @@ func uploadArbitraryFile(ctx context.Context, client v1.DataSyncServiceClient, f
 	return nil
 }
 
-func sendFileUploadRequests(ctx context.Context, stream v1.DataSyncService_FileUploadClient, f *os.File) error {
+func sendFileUploadRequests(
+	ctx context.Context,
+	stream v1.DataSyncService_FileUploadClient,
+	f *os.File,
+	limiter *bandwidthLimiter,
+) error {
 	//nolint:errcheck
 	defer stream.CloseSend()
 	// Loop until there is no more content to be read from file.
@@ -69,6 +85,9 @@ func sendFileUploadRequests(ctx context.Context, stream v1.DataSyncService_FileU
 				return err
 			}
 
+			if err := limiter.wait(ctx, len(uploadReq.GetFileContents().GetData())); err != nil {
+				return err
+			}
 			if err = stream.Send(uploadReq); err != nil {
 				return err
 			}
@@ -4,11 +4,27 @@ import (
 	"context"
 
 	v1 "go.viam.com/api/app/datasync/v1"
+	"google.golang.org/protobuf/proto"
 
 	"go.viam.com/rdk/services/datamanager/datacapture"
 )
 
-func uploadDataCaptureFile(ctx context.Context, client v1.DataSyncServiceClient, f *datacapture.File, partID string) error {
+// MaxCaptureUploadBatchSize is the most bytes of sensor readings sent in each DataCaptureUploadRequest. Readings
+// larger than it are sent on their own.
+var MaxCaptureUploadBatchSize = 2 * 1024 * 1024
+
+// uploadDataCaptureFile uploads the readings of f after the first uploaded ones, in batches of at most
+// MaxCaptureUploadBatchSize bytes. progress is called with the number of readings uploaded after every batch, so
+// that an interrupted upload can be resumed from there.
+func uploadDataCaptureFile(
+	ctx context.Context,
+	client v1.DataSyncServiceClient,
+	f *datacapture.File,
+	partID string,
+	uploaded int,
+	limiter *bandwidthLimiter,
+	progress func(uploaded int),
+) error {
 	md := f.ReadMetadata()
 	sensorData, err := datacapture.SensorDataFromFile(f)
 	if err != nil {
@@ -20,24 +36,41 @@ func uploadDataCaptureFile(ctx context.Context, client v1.DataSyncServiceClient,
 		return nil
 	}
 
-	ur := &v1.DataCaptureUploadRequest{
-		Metadata: &v1.UploadMetadata{
-			PartId:           partID,
-			ComponentType:    md.GetComponentType(),
-			ComponentName:    md.GetComponentName(),
-			ComponentModel:   md.GetComponentModel(),
-			MethodName:       md.GetMethodName(),
-			Type:             md.GetType(),
-			MethodParameters: md.GetMethodParameters(),
-			FileExtension:    md.GetFileExtension(),
-			Tags:             md.GetTags(),
-			SessionId:        md.GetSessionId(),
-		},
-		SensorContents: sensorData,
+	uploadMD := &v1.UploadMetadata{
+		PartId:           partID,
+		ComponentType:    md.GetComponentType(),
+		ComponentName:    md.GetComponentName(),
+		ComponentModel:   md.GetComponentModel(),
+		MethodName:       md.GetMethodName(),
+		Type:             md.GetType(),
+		MethodParameters: md.GetMethodParameters(),
+		FileExtension:    md.GetFileExtension(),
+		Tags:             md.GetTags(),
+		SessionId:        md.GetSessionId(),
 	}
-	_, err = client.DataCaptureUpload(ctx, ur)
-	if err != nil {
-		return err
+	for uploaded < len(sensorData) {
+		end, batchSize := uploaded, 0
+		for end < len(sensorData) {
+			size := proto.Size(sensorData[end])
+			if end > uploaded && batchSize+size > MaxCaptureUploadBatchSize {
+				break
+			}
+			batchSize += size
+			end++
+		}
+
+		ur := &v1.DataCaptureUploadRequest{
+			Metadata:       uploadMD,
+			SensorContents: sensorData[uploaded:end],
+		}
+		if err := limiter.wait(ctx, proto.Size(ur)); err != nil {
+			return err
+		}
+		if _, err := client.DataCaptureUpload(ctx, ur); err != nil {
+			return err
+		}
+		uploaded = end
+		progress(uploaded)
 	}
 
 	return nil
@@ -0,0 +1,196 @@
+package mlmodel
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+)
+
+// DefaultMaxBatchSize is the most inputs an InferQueue runs inference on in one batch.
+const DefaultMaxBatchSize = 8
+
+// maxPendingInferJobs is the most inputs an InferQueue holds while they wait for inference.
+const maxPendingInferJobs = 64
+
+// A BatchInferrer is an ML model service that can run inference on several inputs in one call, such as by stacking
+// them along the batch dimension of the model, so that the cost of invoking the model is paid once for all of them.
+type BatchInferrer interface {
+	// InferBatch returns the outputs of every input, in the same order.
+	InferBatch(ctx context.Context, inputs []map[string]interface{}) ([]map[string]interface{}, error)
+}
+
+// InferBatch runs inference on every input, in one call if the service is a BatchInferrer and one at a time if not.
+func InferBatch(ctx context.Context, svc Service, inputs []map[string]interface{}) ([]map[string]interface{}, error) {
+	if bi, ok := svc.(BatchInferrer); ok {
+		return bi.InferBatch(ctx, inputs)
+	}
+	outputs := make([]map[string]interface{}, 0, len(inputs))
+	for _, input := range inputs {
+		output, err := svc.Infer(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs, nil
+}
+
+// An InferJob is an input submitted for inference in the background.
+type InferJob struct {
+	ID   string
+	Done bool
+	// Output and Err are the result of inference once it is done.
+	Output map[string]interface{}
+	Err    error
+}
+
+// An AsyncInferrer is an ML model service that can run inference in the background, so that the caller can go on
+// with other work, such as capturing the next camera frame, while it waits for the outputs.
+type AsyncInferrer interface {
+	// SubmitInfer queues the input for inference and returns the ID of its job.
+	SubmitInfer(ctx context.Context, input map[string]interface{}) (string, error)
+	// PollInfer returns the job with the given ID. Once it is returned done, it is forgotten.
+	PollInfer(ctx context.Context, id string) (InferJob, error)
+}
+
+type queuedInput struct {
+	id    string
+	input map[string]interface{}
+}
+
+// An InferQueue runs inference in the background on the inputs submitted to it, in batches of the inputs that are
+// waiting whenever the service is free, so that services can implement AsyncInferrer with it.
+type InferQueue struct {
+	svc          Service
+	maxBatchSize int
+
+	mu      sync.Mutex
+	pending []queuedInput
+	jobs    map[string]*InferJob
+	wake    chan struct{}
+	started bool
+
+	cancelCtx               context.Context
+	cancelFunc              func()
+	activeBackgroundWorkers sync.WaitGroup
+}
+
+// NewInferQueue returns a queue running inference with the service on up to maxBatchSize inputs at a time, or
+// DefaultMaxBatchSize if it is not positive. It starts running once the first input is submitted.
+func NewInferQueue(svc Service, maxBatchSize int) *InferQueue {
+	if maxBatchSize <= 0 {
+		maxBatchSize = DefaultMaxBatchSize
+	}
+	cancelCtx, cancelFunc := context.WithCancel(context.Background())
+	return &InferQueue{
+		svc:          svc,
+		maxBatchSize: maxBatchSize,
+		jobs:         map[string]*InferJob{},
+		wake:         make(chan struct{}, 1),
+		cancelCtx:    cancelCtx,
+		cancelFunc:   cancelFunc,
+	}
+}
+
+// Submit queues the input for inference and returns the ID of its job.
+func (q *InferQueue) Submit(input map[string]interface{}) (string, error) {
+	q.mu.Lock()
+	if q.cancelCtx.Err() != nil {
+		q.mu.Unlock()
+		return "", errors.New("inference queue is closed")
+	}
+	if len(q.pending) >= maxPendingInferJobs {
+		q.mu.Unlock()
+		return "", errors.Errorf("too many inputs waiting for inference, the limit is %d", maxPendingInferJobs)
+	}
+	if !q.started {
+		q.started = true
+		q.activeBackgroundWorkers.Add(1)
+		goutils.PanicCapturingGo(func() {
+			defer q.activeBackgroundWorkers.Done()
+			q.run(q.cancelCtx)
+		})
+	}
+	id := uuid.NewString()
+	q.jobs[id] = &InferJob{ID: id}
+	q.pending = append(q.pending, queuedInput{id: id, input: input})
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return id, nil
+}
+
+// Poll returns the job with the given ID, and forgets it if it is done.
+func (q *InferQueue) Poll(id string) (InferJob, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return InferJob{}, errors.Errorf("no inference job with ID %q", id)
+	}
+	if job.Done {
+		delete(q.jobs, id)
+	}
+	return *job, nil
+}
+
+// Close stops running inference. Jobs that are not done by then never will be.
+func (q *InferQueue) Close() {
+	q.mu.Lock()
+	q.cancelFunc()
+	q.mu.Unlock()
+	q.activeBackgroundWorkers.Wait()
+}
+
+func (q *InferQueue) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		}
+		for ctx.Err() == nil {
+			q.mu.Lock()
+			n := len(q.pending)
+			if n > q.maxBatchSize {
+				n = q.maxBatchSize
+			}
+			batch := q.pending[:n]
+			q.pending = q.pending[n:]
+			q.mu.Unlock()
+			if n == 0 {
+				break
+			}
+
+			inputs := make([]map[string]interface{}, 0, n)
+			for _, queued := range batch {
+				inputs = append(inputs, queued.input)
+			}
+			outputs, err := InferBatch(ctx, q.svc, inputs)
+			if err == nil && len(outputs) != n {
+				err = errors.Errorf("got %d outputs for %d inputs", len(outputs), n)
+			}
+
+			q.mu.Lock()
+			for i, queued := range batch {
+				job, ok := q.jobs[queued.id]
+				if !ok {
+					continue
+				}
+				job.Done = true
+				if err != nil {
+					job.Err = err
+				} else {
+					job.Output = outputs[i]
+				}
+			}
+			q.mu.Unlock()
+		}
+	}
+}
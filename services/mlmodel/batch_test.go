@@ -0,0 +1,108 @@
+package mlmodel_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/services/mlmodel"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// batchModel doubles its inputs, and records the size of every batch it is given.
+type batchModel struct {
+	*inject.MLModelService
+	mu      sync.Mutex
+	batches []int
+}
+
+func (m *batchModel) InferBatch(ctx context.Context, inputs []map[string]interface{}) ([]map[string]interface{}, error) {
+	m.mu.Lock()
+	m.batches = append(m.batches, len(inputs))
+	m.mu.Unlock()
+	outputs := make([]map[string]interface{}, 0, len(inputs))
+	for _, input := range inputs {
+		x, ok := input["x"].(int)
+		if !ok {
+			return nil, errors.New("no x")
+		}
+		outputs = append(outputs, map[string]interface{}{"y": 2 * x})
+	}
+	return outputs, nil
+}
+
+func TestInferBatch(t *testing.T) {
+	ctx := context.Background()
+	inputs := []map[string]interface{}{{"x": 1}, {"x": 2}}
+
+	t.Run("one at a time", func(t *testing.T) {
+		svc := inject.NewMLModelService(testMLModelServiceName)
+		calls := 0
+		svc.InferFunc = func(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+			calls++
+			return map[string]interface{}{"y": 3 * input["x"].(int)}, nil
+		}
+		outputs, err := mlmodel.InferBatch(ctx, svc, inputs)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, outputs, test.ShouldResemble, []map[string]interface{}{{"y": 3}, {"y": 6}})
+		test.That(t, calls, test.ShouldEqual, 2)
+	})
+
+	t.Run("batch inferrer", func(t *testing.T) {
+		svc := &batchModel{MLModelService: inject.NewMLModelService(testMLModelServiceName)}
+		outputs, err := mlmodel.InferBatch(ctx, svc, inputs)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, outputs, test.ShouldResemble, []map[string]interface{}{{"y": 2}, {"y": 4}})
+		test.That(t, svc.batches, test.ShouldResemble, []int{2})
+	})
+}
+
+func TestInferQueue(t *testing.T) {
+	svc := &batchModel{MLModelService: inject.NewMLModelService(testMLModelServiceName)}
+	queue := mlmodel.NewInferQueue(svc, 2)
+	defer queue.Close()
+
+	var ids []string
+	for x := 1; x <= 5; x++ {
+		id, err := queue.Submit(map[string]interface{}{"x": x})
+		test.That(t, err, test.ShouldBeNil)
+		ids = append(ids, id)
+	}
+	for i, id := range ids {
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			job, err := queue.Poll(id)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, job.Done, test.ShouldBeTrue)
+			test.That(tb, job.Err, test.ShouldBeNil)
+			test.That(tb, job.Output, test.ShouldResemble, map[string]interface{}{"y": 2 * (i + 1)})
+		})
+		_, err := queue.Poll(id)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+
+	// a job whose inference fails is done with the error
+	badID, err := queue.Submit(map[string]interface{}{})
+	test.That(t, err, test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		job, err := queue.Poll(badID)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, job.Done, test.ShouldBeTrue)
+		test.That(tb, job.Err, test.ShouldNotBeNil)
+	})
+
+	svc.mu.Lock()
+	for _, n := range svc.batches {
+		test.That(t, n, test.ShouldBeBetweenOrEqual, 1, 2)
+	}
+	svc.mu.Unlock()
+
+	queue.Close()
+	_, err = queue.Submit(map[string]interface{}{"x": 1})
+	test.That(t, err, test.ShouldNotBeNil)
+}
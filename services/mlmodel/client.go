@@ -2,8 +2,10 @@ package mlmodel
 
 import (
 	"context"
+	"sync"
 
 	"github.com/edaniels/golog"
+	"go.uber.org/multierr"
 	pb "go.viam.com/api/service/mlmodel/v1"
 	"go.viam.com/utils/rpc"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -15,11 +17,11 @@ import (
 type client struct {
 	resource.Named
 	resource.TriviallyReconfigurable
-	resource.TriviallyCloseable
 	name   string
 	conn   rpc.ClientConn
 	client pb.MLModelServiceClient
 	logger golog.Logger
+	queue  *InferQueue
 }
 
 // NewClientFromConn constructs a new Client from connection passed in.
@@ -38,6 +40,7 @@ func NewClientFromConn(
 		client: grpcClient,
 		logger: logger,
 	}
+	c.queue = NewInferQueue(c, 0)
 	return c, nil
 }
 
@@ -57,6 +60,41 @@ func (c *client) Infer(ctx context.Context, input map[string]interface{}) (map[s
 	return resp.OutputData.AsMap(), nil
 }
 
+// InferBatch sends every input at once, in concurrent Infer calls, until the ML model API has an RPC for batches.
+func (c *client) InferBatch(ctx context.Context, inputs []map[string]interface{}) ([]map[string]interface{}, error) {
+	outputs := make([]map[string]interface{}, len(inputs))
+	errs := make([]error, len(inputs))
+	var wg sync.WaitGroup
+	for i, input := range inputs {
+		i, input := i, input
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outputs[i], errs[i] = c.Infer(ctx, input)
+		}()
+	}
+	wg.Wait()
+	if err := multierr.Combine(errs...); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+// SubmitInfer queues the input for inference on this side of the connection, until the ML model API has an RPC for
+// it, so that callers can submit frames without waiting on the network.
+func (c *client) SubmitInfer(ctx context.Context, input map[string]interface{}) (string, error) {
+	return c.queue.Submit(input)
+}
+
+func (c *client) PollInfer(ctx context.Context, id string) (InferJob, error) {
+	return c.queue.Poll(id)
+}
+
+func (c *client) Close(ctx context.Context) error {
+	c.queue.Close()
+	return nil
+}
+
 func (c *client) Metadata(ctx context.Context) (MLMetadata, error) {
 	resp, err := c.client.Metadata(ctx, &pb.MetadataRequest{
 		Name: c.name,
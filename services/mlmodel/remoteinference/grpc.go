@@ -0,0 +1,58 @@
+package remoteinference
+
+import (
+	"context"
+
+	"github.com/edaniels/golog"
+	"go.uber.org/multierr"
+	"go.viam.com/utils/rpc"
+
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/services/mlmodel"
+)
+
+// grpcBackend forwards to an ML model service served over gRPC, by way of its client.
+type grpcBackend struct {
+	conn   rpc.ClientConn
+	client mlmodel.Service
+}
+
+func newGRPCBackend(ctx context.Context, params *Config, logger golog.Logger) (*grpcBackend, error) {
+	var opts []rpc.DialOption
+	if params.Insecure {
+		opts = append(opts, rpc.WithInsecure())
+	}
+	conn, err := viamgrpc.Dial(ctx, params.Address, logger, opts...)
+	if err != nil {
+		return nil, err
+	}
+	client, err := mlmodel.NewClientFromConn(ctx, conn, "", mlmodel.Named(params.ModelName), logger)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcBackend{conn: conn, client: client}, nil
+}
+
+func (b *grpcBackend) metadata(ctx context.Context) (mlmodel.MLMetadata, error) {
+	return b.client.Metadata(ctx)
+}
+
+func (b *grpcBackend) infer(
+	ctx context.Context,
+	input map[string]interface{},
+	md *mlmodel.MLMetadata,
+) (map[string]interface{}, error) {
+	return b.client.Infer(ctx, input)
+}
+
+func (b *grpcBackend) inferBatch(
+	ctx context.Context,
+	inputs []map[string]interface{},
+	md *mlmodel.MLMetadata,
+) ([]map[string]interface{}, error) {
+	return mlmodel.InferBatch(ctx, b.client, inputs)
+}
+
+func (b *grpcBackend) close(ctx context.Context) error {
+	return multierr.Combine(b.client.Close(ctx), b.conn.Close())
+}
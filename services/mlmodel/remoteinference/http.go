@@ -0,0 +1,407 @@
+package remoteinference
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/services/mlmodel"
+)
+
+// httpBackend speaks the HTTP/REST form of the open inference protocol, as described at
+// https://kserve.github.io/website/latest/modelserving/data_plane/v2_protocol/.
+type httpBackend struct {
+	client   *http.Client
+	modelURL string
+	headers  map[string]string
+}
+
+func newHTTPBackend(params *Config, timeout time.Duration) *httpBackend {
+	address := strings.TrimSuffix(params.Address, "/")
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	modelURL := address + "/v2/models/" + url.PathEscape(params.ModelName)
+	if params.ModelVersion != "" {
+		modelURL += "/versions/" + url.PathEscape(params.ModelVersion)
+	}
+	return &httpBackend{client: &http.Client{Timeout: timeout}, modelURL: modelURL, headers: params.Headers}
+}
+
+// tensorMetadata describes an input or output of a model in its metadata. Dimensions of unknown size, such as the
+// batch size, are -1.
+type tensorMetadata struct {
+	Name     string `json:"name"`
+	Datatype string `json:"datatype"`
+	Shape    []int  `json:"shape"`
+}
+
+type modelMetadataResponse struct {
+	Name     string           `json:"name"`
+	Versions []string         `json:"versions,omitempty"`
+	Platform string           `json:"platform"`
+	Inputs   []tensorMetadata `json:"inputs"`
+	Outputs  []tensorMetadata `json:"outputs"`
+}
+
+// inferTensor is an input or output of an inference request, whose data is in row-major order.
+type inferTensor struct {
+	Name     string      `json:"name"`
+	Shape    []int       `json:"shape"`
+	Datatype string      `json:"datatype"`
+	Data     interface{} `json:"data"`
+}
+
+type inferRequest struct {
+	Inputs []inferTensor `json:"inputs"`
+}
+
+type inferResponse struct {
+	ModelName string        `json:"model_name"`
+	Outputs   []inferTensor `json:"outputs"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (b *httpBackend) metadata(ctx context.Context) (mlmodel.MLMetadata, error) {
+	var resp modelMetadataResponse
+	if err := b.do(ctx, http.MethodGet, b.modelURL, nil, &resp); err != nil {
+		return mlmodel.MLMetadata{}, err
+	}
+	md := mlmodel.MLMetadata{ModelName: resp.Name, ModelType: resp.Platform}
+	for _, in := range resp.Inputs {
+		md.Inputs = append(md.Inputs, mlmodel.TensorInfo{Name: in.Name, DataType: fromV2Datatype(in.Datatype), Shape: in.Shape})
+	}
+	for _, out := range resp.Outputs {
+		md.Outputs = append(md.Outputs, mlmodel.TensorInfo{Name: out.Name, DataType: fromV2Datatype(out.Datatype), Shape: out.Shape})
+	}
+	return md, nil
+}
+
+func (b *httpBackend) infer(
+	ctx context.Context,
+	input map[string]interface{},
+	md *mlmodel.MLMetadata,
+) (map[string]interface{}, error) {
+	req := inferRequest{Inputs: make([]inferTensor, 0, len(input))}
+	for name, value := range input {
+		// a single input is the model's single input, whatever it is called, as for the other implementations
+		if len(input) == 1 && md != nil && len(md.Inputs) == 1 {
+			name = md.Inputs[0].Name
+		}
+		tensor, err := toInferTensor(name, value, inputInfo(md, name))
+		if err != nil {
+			return nil, err
+		}
+		req.Inputs = append(req.Inputs, tensor)
+	}
+
+	var resp inferResponse
+	if err := b.do(ctx, http.MethodPost, b.modelURL+"/infer", req, &resp); err != nil {
+		return nil, err
+	}
+	out := make(map[string]interface{}, len(resp.Outputs))
+	for _, tensor := range resp.Outputs {
+		data, err := fromInferTensor(tensor)
+		if err != nil {
+			return nil, err
+		}
+		out[tensor.Name] = data
+	}
+	return out, nil
+}
+
+// inferBatch stacks the inputs along the batch dimension of the model, if it has one, so that they take one request,
+// and splits the outputs evenly between them.
+func (b *httpBackend) inferBatch(
+	ctx context.Context,
+	inputs []map[string]interface{},
+	md *mlmodel.MLMetadata,
+) ([]map[string]interface{}, error) {
+	stacked, ok := stackInputs(inputs, md)
+	if !ok {
+		outputs := make([]map[string]interface{}, 0, len(inputs))
+		for _, input := range inputs {
+			output, err := b.infer(ctx, input, md)
+			if err != nil {
+				return nil, err
+			}
+			outputs = append(outputs, output)
+		}
+		return outputs, nil
+	}
+
+	out, err := b.infer(ctx, stacked, md)
+	if err != nil {
+		return nil, err
+	}
+	outputs := make([]map[string]interface{}, len(inputs))
+	for i := range outputs {
+		outputs[i] = make(map[string]interface{}, len(out))
+	}
+	for name, data := range out {
+		v := reflect.ValueOf(data)
+		if v.Len()%len(inputs) != 0 {
+			return nil, errors.Errorf("output %q of %d values cannot be split between %d inputs", name, v.Len(), len(inputs))
+		}
+		size := v.Len() / len(inputs)
+		for i := range outputs {
+			outputs[i][name] = v.Slice(i*size, (i+1)*size).Interface()
+		}
+	}
+	return outputs, nil
+}
+
+// stackInputs concatenates each input of every input map, if the model's inputs all have a batch dimension and the
+// input maps all have the same inputs of the same types.
+func stackInputs(inputs []map[string]interface{}, md *mlmodel.MLMetadata) (map[string]interface{}, bool) {
+	if len(inputs) < 2 || md == nil || len(md.Inputs) == 0 {
+		return nil, false
+	}
+	for _, info := range md.Inputs {
+		if len(info.Shape) < 2 || info.Shape[0] >= 0 {
+			return nil, false
+		}
+	}
+	stacked := make(map[string]interface{}, len(inputs[0]))
+	for name, value := range inputs[0] {
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice {
+			return nil, false
+		}
+		for _, input := range inputs[1:] {
+			other := reflect.ValueOf(input[name])
+			if len(input) != len(inputs[0]) || !other.IsValid() || other.Type() != v.Type() {
+				return nil, false
+			}
+			v = reflect.AppendSlice(v, other)
+		}
+		stacked[name] = v.Interface()
+	}
+	return stacked, true
+}
+
+func (b *httpBackend) close(ctx context.Context) error {
+	b.client.CloseIdleConnections()
+	return nil
+}
+
+// do sends a request, with the JSON of body if it is not nil, and decodes the JSON of the response into out.
+func (b *httpBackend) do(ctx context.Context, method, target string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range b.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		goutils.UncheckedError(resp.Body.Close())
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Error == "" {
+			return errors.Errorf("inference server returned %s", resp.Status)
+		}
+		return errors.Errorf("inference server returned %s: %s", resp.Status, errResp.Error)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// inputInfo returns the metadata of the named input, if there is any.
+func inputInfo(md *mlmodel.MLMetadata, name string) *mlmodel.TensorInfo {
+	if md == nil {
+		return nil
+	}
+	for i := range md.Inputs {
+		if md.Inputs[i].Name == name {
+			return &md.Inputs[i]
+		}
+	}
+	return nil
+}
+
+// toInferTensor converts a flat slice of input into a tensor, whose shape is the shape of the input in the model's
+// metadata with the unknown dimensions filled in.
+func toInferTensor(name string, value interface{}, info *mlmodel.TensorInfo) (inferTensor, error) {
+	tensor := inferTensor{Name: name, Data: value}
+	var n int
+	switch v := value.(type) {
+	case []uint8:
+		// the JSON of a []uint8 is a base64 string, rather than an array
+		data := make([]int, 0, len(v))
+		for _, x := range v {
+			data = append(data, int(x))
+		}
+		tensor.Data, tensor.Datatype, n = data, "UINT8", len(v)
+	case []int8:
+		tensor.Datatype, n = "INT8", len(v)
+	case []int32:
+		tensor.Datatype, n = "INT32", len(v)
+	case []int64:
+		tensor.Datatype, n = "INT64", len(v)
+	case []int:
+		tensor.Datatype, n = "INT64", len(v)
+	case []float32:
+		tensor.Datatype, n = "FP32", len(v)
+	case []float64:
+		tensor.Datatype, n = "FP64", len(v)
+	case []bool:
+		tensor.Datatype, n = "BOOL", len(v)
+	case []interface{}:
+		// the inputs of a remote ML model service client are not typed
+		tensor.Datatype, n = "FP32", len(v)
+		if info != nil && info.DataType != "" {
+			tensor.Datatype = toV2Datatype(info.DataType)
+		}
+	default:
+		return inferTensor{}, errors.Errorf("input %q has unsupported type %T", name, value)
+	}
+	tensor.Shape = tensorShape(info, n)
+	return tensor, nil
+}
+
+// tensorShape returns the shape of a tensor of n values. The shape in the metadata, if any, is used, with its first
+// unknown dimensions, such as the batch size, taken to be 1 and its last sized to fit the values.
+func tensorShape(info *mlmodel.TensorInfo, n int) []int {
+	if info == nil || len(info.Shape) == 0 {
+		return []int{n}
+	}
+	shape := make([]int, len(info.Shape))
+	copy(shape, info.Shape)
+	known, last := 1, -1
+	for i, dim := range shape {
+		if dim < 0 {
+			if last >= 0 {
+				shape[last] = 1
+			}
+			last = i
+		} else {
+			known *= dim
+		}
+	}
+	if last >= 0 && known > 0 {
+		shape[last] = n / known
+	}
+	return shape
+}
+
+// fromInferTensor converts the data of an output, which may be nested, into a flat slice of the output's type.
+func fromInferTensor(tensor inferTensor) (interface{}, error) {
+	var values []interface{}
+	var flatten func(v interface{})
+	flatten = func(v interface{}) {
+		if vs, ok := v.([]interface{}); ok {
+			for _, x := range vs {
+				flatten(x)
+			}
+			return
+		}
+		values = append(values, v)
+	}
+	flatten(tensor.Data)
+
+	if tensor.Datatype == "BOOL" {
+		out := make([]bool, 0, len(values))
+		for _, v := range values {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, errors.Errorf("output %q has non-boolean value %v", tensor.Name, v)
+			}
+			out = append(out, b)
+		}
+		return out, nil
+	}
+	if tensor.Datatype == "BYTES" {
+		out := make([]string, 0, len(values))
+		for _, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				return nil, errors.Errorf("output %q has non-string value %v", tensor.Name, v)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	}
+
+	floats := make([]float64, 0, len(values))
+	for _, v := range values {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, errors.Errorf("output %q has non-numeric value %v", tensor.Name, v)
+		}
+		floats = append(floats, f)
+	}
+	switch tensor.Datatype {
+	case "UINT8":
+		out := make([]uint8, 0, len(floats))
+		for _, f := range floats {
+			out = append(out, uint8(f))
+		}
+		return out, nil
+	case "INT32":
+		out := make([]int32, 0, len(floats))
+		for _, f := range floats {
+			out = append(out, int32(f))
+		}
+		return out, nil
+	case "INT64":
+		out := make([]int64, 0, len(floats))
+		for _, f := range floats {
+			out = append(out, int64(f))
+		}
+		return out, nil
+	case "FP16", "FP32":
+		out := make([]float32, 0, len(floats))
+		for _, f := range floats {
+			out = append(out, float32(f))
+		}
+		return out, nil
+	default:
+		return floats, nil
+	}
+}
+
+// fromV2Datatype converts a datatype of the open inference protocol, such as FP32, into an ML model service data type,
+// such as float32.
+func fromV2Datatype(datatype string) string {
+	if strings.HasPrefix(datatype, "FP") {
+		return "float" + strings.TrimPrefix(datatype, "FP")
+	}
+	return strings.ToLower(datatype)
+}
+
+// toV2Datatype is the inverse of fromV2Datatype.
+func toV2Datatype(dataType string) string {
+	if strings.HasPrefix(dataType, "float") {
+		return "FP" + strings.TrimPrefix(dataType, "float")
+	}
+	return strings.ToUpper(dataType)
+}
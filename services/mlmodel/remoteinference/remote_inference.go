@@ -0,0 +1,235 @@
+// Package remoteinference forwards inference to a remote inference server, as an implementation of the ML model
+// service, so that heavyweight models can run on a nearby GPU machine while the robot keeps using them through the
+// ML model and vision services as it would a local model.
+package remoteinference
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/mlmodel"
+	"go.viam.com/rdk/utils"
+)
+
+var sModel = resource.DefaultModelFamily.WithModel("remote_inference")
+
+// The protocols a remote inference server can speak.
+const (
+	// ProtocolHTTP is the HTTP/REST form of the open inference protocol, also known as the KServe V2 protocol, as
+	// served by NVIDIA Triton, KServe, Seldon MLServer and others.
+	ProtocolHTTP = "http"
+	// ProtocolGRPC is the gRPC API of the ML model service, as served by another robot or by any server implementing it.
+	ProtocolGRPC = "grpc"
+)
+
+const defaultTimeout = 10 * time.Second
+
+func init() {
+	resource.RegisterService(mlmodel.API, sModel, resource.Registration[mlmodel.Service, *Config]{
+		Constructor: func(
+			ctx context.Context,
+			_ resource.Dependencies,
+			conf resource.Config,
+			logger golog.Logger,
+		) (mlmodel.Service, error) {
+			svcConf, err := resource.NativeConfig[*Config](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewRemoteInferenceModel(ctx, svcConf, conf.ResourceName(), logger)
+		},
+	})
+}
+
+// Config contains the parameters specific to a remote_inference implementation of the MLMS (machine learning model
+// service).
+type Config struct {
+	// Protocol is ProtocolHTTP, the default, or ProtocolGRPC.
+	Protocol string `json:"protocol"`
+	// Address is the base URL of an HTTP server, such as http://gpu-box:8000, or the address of a gRPC server.
+	Address string `json:"address"`
+	// ModelName is the name of the model on the server. For the gRPC protocol, it is the name of the ML model service.
+	ModelName    string `json:"model_name"`
+	ModelVersion string `json:"model_version"`
+	TimeoutMs    int    `json:"timeout_ms"`
+	// Headers are added to every HTTP request, such as to authenticate with the server.
+	Headers map[string]string `json:"headers"`
+	// Insecure allows dialing a gRPC server without TLS.
+	Insecure bool `json:"insecure"`
+	// MaxBatchSize is the most inputs submitted for inference in the background that are sent at once.
+	MaxBatchSize int     `json:"max_batch_size"`
+	LabelPath    *string `json:"label_path"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	switch cfg.Protocol {
+	case "", ProtocolHTTP, ProtocolGRPC:
+	default:
+		return nil, goutils.NewConfigValidationError(path, errors.Errorf("unknown protocol %q", cfg.Protocol))
+	}
+	if cfg.Address == "" {
+		return nil, goutils.NewConfigValidationFieldRequiredError(path, "address")
+	}
+	if cfg.ModelName == "" {
+		return nil, goutils.NewConfigValidationFieldRequiredError(path, "model_name")
+	}
+	if cfg.TimeoutMs < 0 {
+		return nil, goutils.NewConfigValidationError(path, errors.New("timeout_ms cannot be negative"))
+	}
+	if cfg.MaxBatchSize < 0 {
+		return nil, goutils.NewConfigValidationError(path, errors.New("max_batch_size cannot be negative"))
+	}
+	return nil, nil
+}
+
+// Walk implements the Walker interface and correctly replaces the label path.
+func (cfg *Config) Walk(visitor utils.Visitor) (interface{}, error) {
+	labelPath, err := visitor.Visit(cfg.LabelPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.LabelPath = labelPath.(*string)
+	return cfg, nil
+}
+
+// backend is the client of a remote inference server for one protocol.
+type backend interface {
+	infer(ctx context.Context, input map[string]interface{}, md *mlmodel.MLMetadata) (map[string]interface{}, error)
+	inferBatch(ctx context.Context, inputs []map[string]interface{}, md *mlmodel.MLMetadata) ([]map[string]interface{}, error)
+	metadata(ctx context.Context) (mlmodel.MLMetadata, error)
+	close(ctx context.Context) error
+}
+
+// Model is a struct that implements the remote inference implementation of the MLMS.
+// It includes the configured parameters, the client of the server, and the metadata of the model once known.
+type Model struct {
+	resource.Named
+	resource.AlwaysRebuild
+	conf    Config
+	backend backend
+	queue   *mlmodel.InferQueue
+	logger  golog.Logger
+
+	mu       sync.Mutex
+	metadata *mlmodel.MLMetadata
+}
+
+// NewRemoteInferenceModel is a constructor that builds a remote inference implementation of the MLMS.
+func NewRemoteInferenceModel(
+	ctx context.Context,
+	params *Config,
+	name resource.Name,
+	logger golog.Logger,
+) (mlmodel.Service, error) {
+	_, span := trace.StartSpan(ctx, "service::mlmodel::NewRemoteInferenceModel")
+	defer span.End()
+	if params == nil {
+		return nil, errors.New("could not find parameters")
+	}
+
+	timeout := defaultTimeout
+	if params.TimeoutMs > 0 {
+		timeout = time.Duration(params.TimeoutMs) * time.Millisecond
+	}
+	var b backend
+	switch params.Protocol {
+	case "", ProtocolHTTP:
+		b = newHTTPBackend(params, timeout)
+	case ProtocolGRPC:
+		var err error
+		b, err = newGRPCBackend(ctx, params, logger)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not connect to inference server at %s", params.Address)
+		}
+	default:
+		return nil, errors.Errorf("unknown protocol %q", params.Protocol)
+	}
+	m := &Model{Named: name.AsNamed(), conf: *params, backend: b, logger: logger}
+	m.queue = mlmodel.NewInferQueue(m, params.MaxBatchSize)
+	return m, nil
+}
+
+// Infer sends the input map to the inference server and returns its outputs. Inputs and outputs are flat slices, as
+// for the other implementations of the MLMS, shaped according to the metadata of the model.
+func (m *Model) Infer(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+	ctx, span := trace.StartSpan(ctx, "service::mlmodel::remote_inference::Infer")
+	defer span.End()
+
+	out, err := m.backend.infer(ctx, input, m.knownMetadata(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't infer from model %q", m.Name())
+	}
+	return out, nil
+}
+
+// InferBatch sends the inputs to the inference server in one request, if the model has a batch dimension, and
+// returns the outputs of each.
+func (m *Model) InferBatch(ctx context.Context, inputs []map[string]interface{}) ([]map[string]interface{}, error) {
+	ctx, span := trace.StartSpan(ctx, "service::mlmodel::remote_inference::InferBatch")
+	defer span.End()
+
+	outputs, err := m.backend.inferBatch(ctx, inputs, m.knownMetadata(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't infer from model %q", m.Name())
+	}
+	return outputs, nil
+}
+
+// SubmitInfer queues the input for inference in the background, in batches of the inputs waiting for it.
+func (m *Model) SubmitInfer(ctx context.Context, input map[string]interface{}) (string, error) {
+	return m.queue.Submit(input)
+}
+
+// PollInfer returns the inference job with the given ID.
+func (m *Model) PollInfer(ctx context.Context, id string) (mlmodel.InferJob, error) {
+	return m.queue.Poll(id)
+}
+
+// knownMetadata returns the metadata of the model, or nil if the server cannot give it, since the server may still
+// be able to run the model without it.
+func (m *Model) knownMetadata(ctx context.Context) *mlmodel.MLMetadata {
+	md, err := m.Metadata(ctx)
+	if err != nil {
+		m.logger.Debugw("error getting metadata of remote model", "error", err)
+		return nil
+	}
+	return &md
+}
+
+// Metadata asks the inference server for the metadata of the model, once, and adds the configured labels to it.
+func (m *Model) Metadata(ctx context.Context) (mlmodel.MLMetadata, error) {
+	ctx, span := trace.StartSpan(ctx, "service::mlmodel::remote_inference::Metadata")
+	defer span.End()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.metadata != nil {
+		return *m.metadata, nil
+	}
+	md, err := m.backend.metadata(ctx)
+	if err != nil {
+		return mlmodel.MLMetadata{}, errors.Wrap(err, "could not get metadata from inference server")
+	}
+	if len(md.Outputs) > 0 && m.conf.LabelPath != nil {
+		if md.Outputs[0].Extra == nil {
+			md.Outputs[0].Extra = map[string]interface{}{}
+		}
+		md.Outputs[0].Extra["labels"] = *m.conf.LabelPath
+	}
+	m.metadata = &md
+	return md, nil
+}
+
+// Close stops running inference in the background and closes the connection to the inference server.
+func (m *Model) Close(ctx context.Context) error {
+	m.queue.Close()
+	return m.backend.close(ctx)
+}
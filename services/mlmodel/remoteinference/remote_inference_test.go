@@ -0,0 +1,192 @@
+package remoteinference
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/mlmodel"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// newTritonServer serves a detector in the way of an open inference protocol server, such as Triton.
+func newTritonServer(t *testing.T) (*httptest.Server, *inferRequest) {
+	t.Helper()
+	var lastRequest inferRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/models/detector/versions/2", func(w http.ResponseWriter, r *http.Request) {
+		test.That(t, r.Header.Get("Authorization"), test.ShouldEqual, "Bearer token")
+		test.That(t, json.NewEncoder(w).Encode(modelMetadataResponse{
+			Name:     "detector",
+			Platform: "onnxruntime_onnx",
+			Inputs:   []tensorMetadata{{Name: "images", Datatype: "UINT8", Shape: []int{-1, 2, 2, 3}}},
+			Outputs: []tensorMetadata{
+				{Name: "location", Datatype: "FP32", Shape: []int{-1, 1, 4}},
+				{Name: "category", Datatype: "FP32", Shape: []int{-1, 1}},
+				{Name: "score", Datatype: "FP32", Shape: []int{-1, 1}},
+			},
+		}), test.ShouldBeNil)
+	})
+	mux.HandleFunc("/v2/models/detector/versions/2/infer", func(w http.ResponseWriter, r *http.Request) {
+		test.That(t, r.Method, test.ShouldEqual, http.MethodPost)
+		test.That(t, json.NewDecoder(r.Body).Decode(&lastRequest), test.ShouldBeNil)
+		if len(lastRequest.Inputs) != 1 || lastRequest.Inputs[0].Name != "images" {
+			w.WriteHeader(http.StatusBadRequest)
+			test.That(t, json.NewEncoder(w).Encode(errorResponse{Error: "unexpected inputs"}), test.ShouldBeNil)
+			return
+		}
+		// every image of the batch is a dog in the same place
+		batch := lastRequest.Inputs[0].Shape[0]
+		var locations [][]float64
+		var categories, scores []float64
+		for i := 0; i < batch; i++ {
+			locations = append(locations, []float64{0.1, 0.2, 0.3, 0.4})
+			categories = append(categories, 17)
+			scores = append(scores, 0.9)
+		}
+		test.That(t, json.NewEncoder(w).Encode(inferResponse{
+			ModelName: "detector",
+			Outputs: []inferTensor{
+				{Name: "location", Datatype: "FP32", Shape: []int{batch, 1, 4}, Data: locations},
+				{Name: "category", Datatype: "FP32", Shape: []int{batch, 1}, Data: categories},
+				{Name: "score", Datatype: "FP32", Shape: []int{batch, 1}, Data: scores},
+			},
+		}), test.ShouldBeNil)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &lastRequest
+}
+
+func TestRemoteInferenceHTTP(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+	server, lastRequest := newTritonServer(t)
+	labels := "labels.txt"
+	cfg := Config{
+		Address:      server.URL,
+		ModelName:    "detector",
+		ModelVersion: "2",
+		Headers:      map[string]string{"Authorization": "Bearer token"},
+		LabelPath:    &labels,
+	}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	svc, err := NewRemoteInferenceModel(ctx, &cfg, mlmodel.Named("remote"), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, svc.Close(ctx), test.ShouldBeNil)
+	}()
+
+	md, err := svc.Metadata(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, md.ModelName, test.ShouldEqual, "detector")
+	test.That(t, md.Inputs[0].DataType, test.ShouldEqual, "uint8")
+	test.That(t, md.Outputs[0].DataType, test.ShouldEqual, "float32")
+	test.That(t, md.Outputs[0].Extra["labels"], test.ShouldEqual, labels)
+
+	image := make([]uint8, 12)
+	out, err := svc.Infer(ctx, map[string]interface{}{"image": image})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lastRequest.Inputs[0].Datatype, test.ShouldEqual, "UINT8")
+	test.That(t, lastRequest.Inputs[0].Shape, test.ShouldResemble, []int{1, 2, 2, 3})
+	test.That(t, out["location"], test.ShouldResemble, []float32{0.1, 0.2, 0.3, 0.4})
+	test.That(t, out["category"], test.ShouldResemble, []float32{17})
+	test.That(t, out["score"], test.ShouldResemble, []float32{0.9})
+
+	_, err = svc.Infer(ctx, map[string]interface{}{"image": image, "other": image})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unexpected inputs")
+
+	t.Run("batches are stacked into one request", func(t *testing.T) {
+		outputs, err := mlmodel.InferBatch(ctx, svc, []map[string]interface{}{{"image": image}, {"image": image}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, lastRequest.Inputs[0].Shape, test.ShouldResemble, []int{2, 2, 2, 3})
+		test.That(t, len(outputs), test.ShouldEqual, 2)
+		for _, out := range outputs {
+			test.That(t, out["location"], test.ShouldResemble, []float32{0.1, 0.2, 0.3, 0.4})
+			test.That(t, out["category"], test.ShouldResemble, []float32{17})
+		}
+	})
+
+	t.Run("submitted inputs are inferred in the background", func(t *testing.T) {
+		async := svc.(mlmodel.AsyncInferrer)
+		id, err := async.SubmitInfer(ctx, map[string]interface{}{"image": image})
+		test.That(t, err, test.ShouldBeNil)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			job, err := async.PollInfer(ctx, id)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, job.Done, test.ShouldBeTrue)
+			test.That(tb, job.Err, test.ShouldBeNil)
+			test.That(tb, job.Output["score"], test.ShouldResemble, []float32{0.9})
+		})
+		_, err = async.PollInfer(ctx, id)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestRemoteInferenceGRPC(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	gpuModel := inject.NewMLModelService("gpu")
+	gpuModel.MetadataFunc = func(ctx context.Context) (mlmodel.MLMetadata, error) {
+		return mlmodel.MLMetadata{ModelName: "gpu", Inputs: []mlmodel.TensorInfo{{Name: "image", DataType: "uint8"}}}, nil
+	}
+	gpuModel.InferFunc = func(ctx context.Context, input map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"score": []interface{}{0.5}}, nil
+	}
+	svc, err := resource.NewAPIResourceCollection(mlmodel.API, map[resource.Name]mlmodel.Service{mlmodel.Named("gpu"): gpuModel})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[mlmodel.Service](mlmodel.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(ctx, rpcServer, svc), test.ShouldBeNil)
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	cfg := Config{Protocol: ProtocolGRPC, Address: listener.Addr().String(), ModelName: "gpu", Insecure: true}
+	remote, err := NewRemoteInferenceModel(ctx, &cfg, mlmodel.Named("remote"), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, remote.Close(ctx), test.ShouldBeNil)
+	}()
+
+	md, err := remote.Metadata(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, md.ModelName, test.ShouldEqual, "gpu")
+	out, err := remote.Infer(ctx, map[string]interface{}{"image": []uint8{1, 2, 3}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, out["score"], test.ShouldResemble, []interface{}{0.5})
+}
+
+func TestRemoteInferenceConfig(t *testing.T) {
+	cfg := Config{ModelName: "detector"}
+	_, err := cfg.Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "address")
+
+	cfg = Config{Address: "localhost:8000", ModelName: "detector", Protocol: "carrier_pigeon"}
+	_, err = cfg.Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "carrier_pigeon")
+}
+
+func TestTensorShape(t *testing.T) {
+	test.That(t, tensorShape(nil, 6), test.ShouldResemble, []int{6})
+	test.That(t, tensorShape(&mlmodel.TensorInfo{Shape: []int{-1, 2, 3}}, 6), test.ShouldResemble, []int{1, 2, 3})
+	test.That(t, tensorShape(&mlmodel.TensorInfo{Shape: []int{-1, -1, 3}}, 12), test.ShouldResemble, []int{1, 4, 3})
+	test.That(t, tensorShape(&mlmodel.TensorInfo{Shape: []int{2, 3}}, 6), test.ShouldResemble, []int{2, 3})
+}
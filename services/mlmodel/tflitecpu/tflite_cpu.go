@@ -3,14 +3,17 @@ package tflitecpu
 
 import (
 	"context"
+	"fmt"
 	"math"
 	fp "path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/edaniels/golog"
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
+	goutils "go.viam.com/utils"
 
 	inf "go.viam.com/rdk/ml/inference"
 	"go.viam.com/rdk/ml/inference/tflite_metadata"
@@ -41,11 +44,23 @@ func init() {
 // TFLiteConfig contains the parameters specific to a tflite_cpu implementation
 // of the MLMS (machine learning model service).
 type TFLiteConfig struct {
-	resource.TriviallyValidateConfig
 	// this should come from the attributes of the tflite_cpu instance of the MLMS
 	ModelPath  string  `json:"model_path"`
 	NumThreads int     `json:"num_threads"`
 	LabelPath  *string `json:"label_path"`
+	// Delegates run the model on accelerators, such as a Coral Edge TPU, in order of preference, with the CPU
+	// running whatever they cannot.
+	Delegates []inf.TFLiteDelegateConfig `json:"delegates"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *TFLiteConfig) Validate(path string) ([]string, error) {
+	for i, d := range cfg.Delegates {
+		if err := d.Validate(); err != nil {
+			return nil, goutils.NewConfigValidationError(fmt.Sprintf("%s.delegates.%d", path, i), err)
+		}
+	}
+	return nil, nil
 }
 
 // Walk implements the Walker interface and correctly replaces model and label paths.
@@ -69,59 +84,157 @@ func (cfg *TFLiteConfig) Walk(visitor utils.Visitor) (interface{}, error) {
 // It includes the configured parameters, model struct, and associated metadata.
 type Model struct {
 	resource.Named
-	resource.AlwaysRebuild
-	resource.TriviallyCloseable
+	// mu guards the model, which may be swapped for a new one, and its config and metadata. Infer holds it for
+	// reading while it uses the model.
+	mu       sync.RWMutex
 	conf     TFLiteConfig
 	model    *inf.TFLiteStruct
 	metadata *mlmodel.MLMetadata
 	logger   golog.Logger
+	queue    *mlmodel.InferQueue
+
+	// loaded is a new model, loaded in the background since the last reconfiguration, that the next Infer swaps in.
+	// loads counts the reconfigurations, so that only the latest one's model is swapped in.
+	loadMu                  sync.Mutex
+	loaded                  *Model
+	loads                   int
+	activeBackgroundWorkers sync.WaitGroup
 }
 
 // NewTFLiteCPUModel is a constructor that builds a tflite cpu implementation of the MLMS.
 func NewTFLiteCPUModel(ctx context.Context, params *TFLiteConfig, name resource.Name) (mlmodel.Service, error) {
 	_, span := trace.StartSpan(ctx, "service::mlmodel::NewTFLiteCPUModel")
 	defer span.End()
-	var model *inf.TFLiteStruct
+	if params == nil {
+		return nil, errors.New("could not find parameters")
+	}
+	logger := golog.NewLogger("tflite_cpu")
+	model, err := loadModel(params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not add model from location %s", params.ModelPath)
+	}
+	m := &Model{Named: name.AsNamed(), conf: *params, model: model, logger: logger}
+	m.queue = mlmodel.NewInferQueue(m, 0)
+	return m, nil
+}
+
+// loadModel loads the configured model file.
+func loadModel(params *TFLiteConfig) (*inf.TFLiteStruct, error) {
 	var loader *inf.TFLiteModelLoader
 	var err error
-	logger := golog.NewLogger("tflite_cpu")
+	if len(params.Delegates) > 0 {
+		loader, err = inf.NewTFLiteModelLoaderWithDelegates(params.NumThreads, params.Delegates)
+	} else if params.NumThreads <= 0 {
+		loader, err = inf.NewDefaultTFLiteModelLoader()
+	} else {
+		loader, err = inf.NewTFLiteModelLoader(params.NumThreads)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get loader")
+	}
 
-	addModel := func() (*inf.TFLiteStruct, error) {
-		if params == nil {
-			return nil, errors.New("could not find parameters")
-		}
-		if params.NumThreads <= 0 {
-			loader, err = inf.NewDefaultTFLiteModelLoader()
-		} else {
-			loader, err = inf.NewTFLiteModelLoader(params.NumThreads)
+	var model *inf.TFLiteStruct
+	fullpath, err2 := fp.Abs(params.ModelPath)
+	if err2 != nil {
+		model, err = loader.Load(params.ModelPath)
+	} else {
+		model, err = loader.Load(fullpath)
+	}
+
+	if err != nil {
+		if strings.Contains(err.Error(), "failed to load") {
+			if err2 != nil {
+				return nil, errors.Wrapf(err, "file not found at %s", params.ModelPath)
+			}
+			return nil, errors.Wrapf(err, "file not found at %s", fullpath)
 		}
+		return nil, errors.Wrap(err, "loader could not load model")
+	}
+	return model, nil
+}
+
+// Reconfigure loads the newly configured model in the background, such as a new version of it delivered over the
+// air, and the next Infer swaps it in for the old one. Inference goes on with the old model while the new one loads,
+// and if it fails to load.
+func (m *Model) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	newConf, err := resource.NativeConfig[*TFLiteConfig](conf)
+	if err != nil {
+		return err
+	}
+
+	m.loadMu.Lock()
+	defer m.loadMu.Unlock()
+	m.loads++
+	load := m.loads
+	if m.loaded != nil {
+		goutils.UncheckedError(m.loaded.model.Close())
+		m.loaded = nil
+	}
+
+	m.activeBackgroundWorkers.Add(1)
+	goutils.PanicCapturingGo(func() {
+		defer m.activeBackgroundWorkers.Done()
+		model, err := loadModel(newConf)
 		if err != nil {
-			return nil, errors.Wrap(err, "could not get loader")
+			m.logger.Errorw("could not load new model, still using the old one", "model_path", newConf.ModelPath, "error", err)
+			return
 		}
-
-		fullpath, err2 := fp.Abs(params.ModelPath)
-		if err2 != nil {
-			model, err = loader.Load(params.ModelPath)
-		} else {
-			model, err = loader.Load(fullpath)
+		loaded := &Model{Named: m.Named, conf: *newConf, model: model, logger: m.logger}
+		// an old model's metadata is no use once the new one is swapped in
+		if _, err := loaded.Metadata(context.Background()); err != nil {
+			m.logger.Errorw("could not read metadata of new model", "error", err)
 		}
 
-		if err != nil {
-			if strings.Contains(err.Error(), "failed to load") {
-				if err2 != nil {
-					return nil, errors.Wrapf(err, "file not found at %s", params.ModelPath)
-				}
-				return nil, errors.Wrapf(err, "file not found at %s", fullpath)
-			}
-			return nil, errors.Wrap(err, "loader could not load model")
+		m.loadMu.Lock()
+		defer m.loadMu.Unlock()
+		if load != m.loads {
+			goutils.UncheckedError(model.Close())
+			return
 		}
-		return model, nil
+		m.loaded = loaded
+		m.logger.Infow("loaded new model, swapping it in on the next inference", "model_path", newConf.ModelPath)
+	})
+	return nil
+}
+
+// swapInLoadedModel replaces the model with the one loaded since the last reconfiguration, if there is one.
+func (m *Model) swapInLoadedModel() {
+	m.loadMu.Lock()
+	loaded := m.loaded
+	m.loaded = nil
+	m.loadMu.Unlock()
+	if loaded == nil {
+		return
 	}
-	model, err = addModel()
-	if err != nil {
-		return nil, errors.Wrapf(err, "could not add model from location %s", params.ModelPath)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old := m.model
+	m.model, m.conf, m.metadata = loaded.model, loaded.conf, loaded.metadata
+	goutils.UncheckedError(old.Close())
+}
+
+// SubmitInfer queues the input for inference in the background.
+func (m *Model) SubmitInfer(ctx context.Context, input map[string]interface{}) (string, error) {
+	return m.queue.Submit(input)
+}
+
+// PollInfer returns the inference job with the given ID.
+func (m *Model) PollInfer(ctx context.Context, id string) (mlmodel.InferJob, error) {
+	return m.queue.Poll(id)
+}
+
+// Close stops running inference in the background, and any model loading.
+func (m *Model) Close(ctx context.Context) error {
+	m.queue.Close()
+	m.activeBackgroundWorkers.Wait()
+	m.loadMu.Lock()
+	defer m.loadMu.Unlock()
+	if m.loaded != nil {
+		goutils.UncheckedError(m.loaded.model.Close())
+		m.loaded = nil
 	}
-	return &Model{Named: name.AsNamed(), conf: *params, model: model, logger: logger}, nil
+	return nil
 }
 
 // Infer takes the input map and uses the inference package to
@@ -130,6 +243,13 @@ func (m *Model) Infer(ctx context.Context, input map[string]interface{}) (map[st
 	_, span := trace.StartSpan(ctx, "service::mlmodel::tflite_cpu::Infer")
 	defer span.End()
 
+	m.swapInLoadedModel()
+	if _, err := m.Metadata(ctx); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	outMap := make(map[string]interface{})
 	doInfer := func(input interface{}) (map[string]interface{}, error) {
 		outTensors, err := m.model.Infer(input)
@@ -175,6 +295,9 @@ func (m *Model) Metadata(ctx context.Context) (mlmodel.MLMetadata, error) {
 	_, span := trace.StartSpan(ctx, "service::mlmodel::tflite_cpu::Metadata")
 	defer span.End()
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if m.metadata != nil {
 		return *m.metadata, nil
 	}
@@ -14,6 +14,7 @@ import (
 	"go.viam.com/utils/rpc"
 
 	viamgrpc "go.viam.com/rdk/grpc"
+	inf "go.viam.com/rdk/ml/inference"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/rimage"
 	"go.viam.com/rdk/robot/packages"
@@ -30,6 +31,20 @@ func TestEmptyTFLiteConfig(t *testing.T) {
 	test.That(t, err.Error(), test.ShouldContainSubstring, "could not add model")
 }
 
+func TestTFLiteConfigValidate(t *testing.T) {
+	cfg := TFLiteConfig{
+		ModelPath: "model.tflite",
+		Delegates: []inf.TFLiteDelegateConfig{{Type: inf.DelegateEdgeTPU, Device: "usb"}, {Type: inf.DelegateXNNPACK}},
+	}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	cfg.Delegates = append(cfg.Delegates, inf.TFLiteDelegateConfig{Type: "abacus"})
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "path.delegates.2")
+}
+
 func TestTFLiteCPUDetector(t *testing.T) {
 	ctx := context.Background()
 	modelLoc := artifact.MustPath("vision/tflite/effdet0.tflite")
@@ -127,6 +142,59 @@ func TestTFLiteCPUClassifier(t *testing.T) {
 	test.That(t, gotOutput["probability"].([]uint8)[292], test.ShouldEqual, 0)
 }
 
+func TestTFLiteCPUModelSwap(t *testing.T) {
+	ctx := context.Background()
+	cfg := TFLiteConfig{ModelPath: artifact.MustPath("vision/tflite/effdet0.tflite"), NumThreads: 2}
+	out, err := NewTFLiteCPUModel(ctx, &cfg, mlmodel.Named("mySwap"))
+	test.That(t, err, test.ShouldBeNil)
+	got := out.(*Model)
+	defer func() {
+		test.That(t, got.Close(ctx), test.ShouldBeNil)
+	}()
+	gotMD, err := got.Metadata(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gotMD.Outputs[0].Name, test.ShouldResemble, "location")
+
+	reconfigure := func(modelPath string) {
+		t.Helper()
+		conf := resource.Config{
+			Name:                "mySwap",
+			API:                 mlmodel.API,
+			Model:               sModel,
+			ConvertedAttributes: &TFLiteConfig{ModelPath: modelPath, NumThreads: 2},
+		}
+		test.That(t, got.Reconfigure(ctx, nil, conf), test.ShouldBeNil)
+	}
+
+	// a model that fails to load leaves the old one in use
+	reconfigure("/not/a/model.tflite")
+	got.activeBackgroundWorkers.Wait()
+	test.That(t, got.loaded, test.ShouldBeNil)
+
+	reconfigure(artifact.MustPath("vision/tflite/effnet0.tflite"))
+	got.activeBackgroundWorkers.Wait()
+	test.That(t, got.loaded, test.ShouldNotBeNil)
+	loadedMD := got.loaded.metadata
+	test.That(t, loadedMD, test.ShouldNotBeNil)
+
+	// the new model is only swapped in by the next inference
+	gotMD, err = got.Metadata(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gotMD.Outputs[0].Name, test.ShouldResemble, "location")
+
+	pic, err := rimage.NewImageFromFile(artifact.MustPath("vision/tflite/lion.jpeg"))
+	test.That(t, err, test.ShouldBeNil)
+	resized := resize.Resize(uint(loadedMD.Inputs[0].Shape[1]), uint(loadedMD.Inputs[0].Shape[2]), pic, resize.Bilinear)
+	gotOutput, err := got.Infer(ctx, map[string]interface{}{"image": rimage.ImageToUInt8Buffer(resized)})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gotOutput["probability"].([]uint8)[291], test.ShouldBeGreaterThan, 200) // 291 is lion
+	test.That(t, got.loaded, test.ShouldBeNil)
+
+	gotMD, err = got.Metadata(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gotMD.Outputs[0].Name, test.ShouldResemble, "probability")
+}
+
 func TestTFLiteCPUTextModel(t *testing.T) {
 	// Setup
 	ctx := context.Background()
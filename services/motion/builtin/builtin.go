@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -12,7 +13,12 @@ import (
 	"github.com/golang/geo/r3"
 
 	servicepb "go.viam.com/api/service/motion/v1"
+	"go.viam.com/utils"
+
 	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/internal"
 	"go.viam.com/rdk/motionplan"
 	"go.viam.com/rdk/operation"
@@ -21,6 +27,7 @@ import (
 	"go.viam.com/rdk/robot/framesystem"
 	"go.viam.com/rdk/services/motion"
 	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/services/vision"
 	"go.viam.com/rdk/spatialmath"
 )
 
@@ -41,13 +48,57 @@ func init() {
 		})
 }
 
-// Config describes how to configure the service; currently only used for specifying dependency on framesystem service
+// Config describes how to configure the service.
 type Config struct {
+	// ReplanPolicy decides when to plan again while executing a plan; it defaults to never.
+	ReplanPolicy motion.ReplanPolicy `json:"replan_policy,omitempty"`
+	// ReplanPeriodSec is how often the periodic replan policy plans again.
+	ReplanPeriodSec float64 `json:"replan_period_sec,omitempty"`
+	// MaxReplans is how many times a move is planned again before it fails; it defaults to defaultMaxReplans.
+	MaxReplans int `json:"max_replans,omitempty"`
+	// ObstacleDetectors are added to the world state of every plan.
+	ObstacleDetectors []ObstacleDetectorConfig `json:"obstacle_detectors,omitempty"`
+	// PointCloudObstacles are cameras whose point clouds are added to the world state of every plan as they are.
+	PointCloudObstacles []PointCloudObstacleConfig `json:"pointcloud_obstacles,omitempty"`
+	// Base and MovementSensor are the base MoveOnGlobe drives and the movement sensor it locates the base with.
+	Base           string `json:"base,omitempty"`
+	MovementSensor string `json:"movement_sensor,omitempty"`
 }
 
 // Validate here adds a dependency on the internal framesystem service
 func (c *Config) Validate(path string) ([]string, error) {
-	return []string{framesystem.InternalServiceName.String()}, nil
+	switch c.ReplanPolicy {
+	case "", motion.ReplanNever, motion.ReplanOnObstacle:
+	case motion.ReplanPeriodic:
+		if c.ReplanPeriodSec <= 0 {
+			return nil, utils.NewConfigValidationError(path, errors.New("replan_period_sec must be positive for the periodic replan policy"))
+		}
+	default:
+		return nil, utils.NewConfigValidationError(path, errors.Errorf("unknown replan_policy %q", c.ReplanPolicy))
+	}
+	if c.MaxReplans < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("max_replans cannot be negative"))
+	}
+	deps := []string{framesystem.InternalServiceName.String()}
+	for i, detector := range c.ObstacleDetectors {
+		if err := detector.Validate(fmt.Sprintf("%s.obstacle_detectors.%d", path, i)); err != nil {
+			return nil, err
+		}
+		deps = append(deps, vision.Named(detector.VisionService).String())
+	}
+	for i, obstacle := range c.PointCloudObstacles {
+		if err := obstacle.Validate(fmt.Sprintf("%s.pointcloud_obstacles.%d", path, i)); err != nil {
+			return nil, err
+		}
+		deps = append(deps, camera.Named(obstacle.Camera).String())
+	}
+	if (c.Base == "") != (c.MovementSensor == "") {
+		return nil, utils.NewConfigValidationError(path, errors.New("base and movement_sensor must be configured together"))
+	}
+	if c.Base != "" {
+		deps = append(deps, base.Named(c.Base).String(), movementsensor.Named(c.MovementSensor).String())
+	}
+	return deps, nil
 }
 
 // NewBuiltIn returns a new move and grab service for the given robot.
@@ -55,6 +106,7 @@ func NewBuiltIn(ctx context.Context, deps resource.Dependencies, conf resource.C
 	ms := &builtIn{
 		Named:  conf.ResourceName().AsNamed(),
 		logger: logger,
+		plans:  newPlanTracker(),
 	}
 
 	if err := ms.Reconfigure(ctx, deps, conf); err != nil {
@@ -73,25 +125,70 @@ func (ms *builtIn) Reconfigure(
 	defer ms.lock.Unlock()
 
 	slamServices := make(map[resource.Name]slam.Service)
+	components := make(map[resource.Name]resource.Resource)
+	var obstacleSources []motion.ObstacleSource
 	for name, dep := range deps {
 		switch dep := dep.(type) {
 		case framesystem.Service:
 			ms.fsService = dep
 		case slam.Service:
 			slamServices[name] = dep
+		default:
+			if name.API.IsComponent() {
+				components[name] = dep
+			}
+		}
+		if source, ok := dep.(motion.ObstacleSource); ok {
+			obstacleSources = append(obstacleSources, source)
 		}
 	}
 	ms.slamServices = slamServices
+	ms.components = components
+	ms.obstacleSources = obstacleSources
+
+	ms.replanPolicy = motion.ReplanNever
+	ms.replanPeriod = 0
+	ms.maxReplans = defaultMaxReplans
+	if newConf, ok := conf.ConvertedAttributes.(*Config); ok {
+		if newConf.ReplanPolicy != "" {
+			ms.replanPolicy = newConf.ReplanPolicy
+		}
+		ms.replanPeriod = time.Duration(newConf.ReplanPeriodSec * float64(time.Second))
+		if newConf.MaxReplans != 0 {
+			ms.maxReplans = newConf.MaxReplans
+		}
+		for _, detector := range newConf.ObstacleDetectors {
+			source, err := newVisionObstacleSource(deps, detector)
+			if err != nil {
+				return err
+			}
+			ms.obstacleSources = append(ms.obstacleSources, source)
+		}
+		for _, obstacle := range newConf.PointCloudObstacles {
+			source, err := newPointCloudObstacleSource(deps, obstacle)
+			if err != nil {
+				return err
+			}
+			ms.obstacleSources = append(ms.obstacleSources, source)
+		}
+	}
 	return nil
 }
 
 type builtIn struct {
 	resource.Named
-	resource.TriviallyCloseable
 	fsService    framesystem.Service
 	slamServices map[resource.Name]slam.Service
+	components   map[resource.Name]resource.Resource
 	logger       golog.Logger
 	lock         sync.Mutex
+
+	obstacleSources []motion.ObstacleSource
+	replanPolicy    motion.ReplanPolicy
+	replanPeriod    time.Duration
+	maxReplans      int
+
+	plans *planTracker
 }
 
 // Move takes a goal location and will plan and execute a movement to move a component specified by its name to that destination.
@@ -104,42 +201,160 @@ func (ms *builtIn) Move(
 	extra map[string]interface{},
 ) (bool, error) {
 	operation.CancelOtherWithLabel(ctx, "motion-service")
+
+	opts, err := motionplan.RequestOptionsFromExtra(extra)
+	if err != nil {
+		return false, err
+	}
+	steps, resources, plannedWorldState, err := ms.planMove(ctx, componentName, destination, worldState, constraints, extra)
+	if err != nil {
+		return false, err
+	}
+	req := moveRequest{componentName, destination, worldState, constraints, extra, opts.PathLimits}
+
+	// track the move like a background plan so it shows up in the plan history and can be stopped
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	id := ms.plans.add(req.record(steps, plannedWorldState), resources, cancel)
+	if err := ms.plans.run(ctx, id, func(ctx context.Context, id motion.PlanID) error {
+		return ms.execute(ctx, id, req, steps, resources)
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// MoveToAny plans a movement of the component to every destination concurrently and executes the one that moves its joints
+// the least, returning the index of the destination it reaches.
+func (ms *builtIn) MoveToAny(
+	ctx context.Context,
+	componentName resource.Name,
+	destinations []*referenceframe.PoseInFrame,
+	worldState *referenceframe.WorldState,
+	constraints *servicepb.Constraints,
+	extra map[string]interface{},
+) (int, error) {
+	operation.CancelOtherWithLabel(ctx, "motion-service")
+
+	opts, err := motionplan.RequestOptionsFromExtra(extra)
+	if err != nil {
+		return -1, err
+	}
+	steps, resources, plannedWorldState, goal, err := ms.planMoveToAny(ctx, componentName, destinations, worldState, constraints, extra)
+	if err != nil {
+		return -1, err
+	}
+	req := moveRequest{componentName, destinations[goal], worldState, constraints, extra, opts.PathLimits}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	id := ms.plans.add(req.record(steps, plannedWorldState), resources, cancel)
+	if err := ms.plans.run(ctx, id, func(ctx context.Context, id motion.PlanID) error {
+		return ms.execute(ctx, id, req, steps, resources)
+	}); err != nil {
+		return -1, err
+	}
+	return goal, nil
+}
+
+// planMove plans a movement of the named component to the destination, returning the steps of the plan, the resources
+// needed to execute it and the world state it was planned in.
+func (ms *builtIn) planMove(
+	ctx context.Context,
+	componentName resource.Name,
+	destination *referenceframe.PoseInFrame,
+	worldState *referenceframe.WorldState,
+	constraints *servicepb.Constraints,
+	extra map[string]interface{},
+) ([]map[string][]referenceframe.Input, map[string]referenceframe.InputEnabled, *referenceframe.WorldState, error) {
+	steps, resources, plannedWorldState, _, err := ms.planMoveToAny(
+		ctx,
+		componentName,
+		[]*referenceframe.PoseInFrame{destination},
+		worldState,
+		constraints,
+		extra,
+	)
+	return steps, resources, plannedWorldState, err
+}
+
+// planMoveToAny plans a movement of the named component to whichever of the destinations it can best reach, returning the
+// steps of the plan, the resources needed to execute it, the world state it was planned in and the index of the destination
+// it reaches.
+func (ms *builtIn) planMoveToAny(
+	ctx context.Context,
+	componentName resource.Name,
+	destinations []*referenceframe.PoseInFrame,
+	worldState *referenceframe.WorldState,
+	constraints *servicepb.Constraints,
+	extra map[string]interface{},
+) ([]map[string][]referenceframe.Input, map[string]referenceframe.InputEnabled, *referenceframe.WorldState, int, error) {
 	logger := ms.logger
 
-	// get goal frame
-	goalFrameName := destination.Parent()
-	logger.Debugf("goal given in frame of %q", goalFrameName)
+	// add whatever the obstacle sources can see to the world state
+	ms.lock.Lock()
+	sources := ms.obstacleSources
+	ms.lock.Unlock()
+	if len(sources) > 0 {
+		obstacles, err := observeObstacles(ctx, sources)
+		if err != nil {
+			return nil, nil, nil, -1, err
+		}
+		if worldState, err = ms.withObstacles(ctx, worldState, obstacles); err != nil {
+			return nil, nil, nil, -1, err
+		}
+	}
 
 	frameSys, err := ms.fsService.FrameSystem(ctx, worldState.Transforms())
 	if err != nil {
-		return false, err
+		return nil, nil, nil, -1, err
 	}
 
 	// build maps of relevant components and inputs from initial inputs
 	fsInputs, resources, err := ms.fsService.AllCurrentInputs(ctx)
 	if err != nil {
-		return false, err
+		return nil, nil, nil, -1, err
 	}
 
 	movingFrame := frameSys.Frame(componentName.ShortName())
 
 	logger.Debugf("frame system inputs: %v", fsInputs)
 	if movingFrame == nil {
-		return false, fmt.Errorf("component named %s not found in robot frame system", componentName.ShortName())
+		return nil, nil, nil, -1, fmt.Errorf("component named %s not found in robot frame system", componentName.ShortName())
 	}
 
-	// re-evaluate goalPose to be in the frame of World
+	// re-evaluate goal poses to be in the frame of World
 	solvingFrame := referenceframe.World // TODO(erh): this should really be the parent of rootName
-	tf, err := frameSys.Transform(fsInputs, destination, solvingFrame)
-	if err != nil {
-		return false, err
+	goalPoses := make([]*referenceframe.PoseInFrame, 0, len(destinations))
+	for _, destination := range destinations {
+		logger.Debugf("goal given in frame of %q", destination.Parent())
+		tf, err := frameSys.Transform(fsInputs, destination, solvingFrame)
+		if err != nil {
+			return nil, nil, nil, -1, err
+		}
+		goalPoses = append(goalPoses, tf.(*referenceframe.PoseInFrame))
 	}
-	goalPose, _ := tf.(*referenceframe.PoseInFrame)
 
-	// the goal is to move the component to goalPose which is specified in coordinates of goalFrameName
-	output, err := motionplan.PlanMotion(ctx,
+	// a single goal is planned to directly, so that planning errors are reported as they are
+	if len(goalPoses) == 1 {
+		steps, err := motionplan.PlanMotion(ctx,
+			logger,
+			goalPoses[0],
+			movingFrame,
+			fsInputs,
+			frameSys,
+			worldState,
+			constraints,
+			extra,
+		)
+		if err != nil {
+			return nil, nil, nil, -1, err
+		}
+		return steps, resources, worldState, 0, nil
+	}
+	steps, goal, err := motionplan.PlanMotionToAny(ctx,
 		logger,
-		goalPose,
+		goalPoses,
 		movingFrame,
 		fsInputs,
 		frameSys,
@@ -148,23 +363,23 @@ func (ms *builtIn) Move(
 		extra,
 	)
 	if err != nil {
-		return false, err
+		return nil, nil, nil, -1, err
 	}
+	return steps, resources, worldState, goal, nil
+}
 
-	// move all the components
-	for _, step := range output {
-		// TODO(erh): what order? parallel?
-		for name, inputs := range step {
-			if len(inputs) == 0 {
-				continue
-			}
-			err := resources[name].GoToInputs(ctx, inputs)
-			if err != nil {
-				return false, err
-			}
+// executeStep moves all the components to their inputs in one step of a plan.
+func executeStep(ctx context.Context, step map[string][]referenceframe.Input, resources map[string]referenceframe.InputEnabled) error {
+	// TODO(erh): what order? parallel?
+	for name, inputs := range step {
+		if len(inputs) == 0 {
+			continue
+		}
+		if err := resources[name].GoToInputs(ctx, inputs); err != nil {
+			return err
 		}
 	}
-	return true, nil
+	return nil
 }
 
 // MoveOnMap will move the given component
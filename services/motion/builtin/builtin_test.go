@@ -2,6 +2,7 @@ package builtin_test
 
 import (
 	"context"
+	"encoding/json"
 	"math"
 	"testing"
 
@@ -18,10 +19,13 @@ import (
 	commonpb "go.viam.com/api/common/v1"
 	_ "go.viam.com/rdk/components/register"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/motionplan"
 	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
 	framesystemparts "go.viam.com/rdk/robot/framesystem/parts"
 	robotimpl "go.viam.com/rdk/robot/impl"
 	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/motion/builtin"
 	_ "go.viam.com/rdk/services/register"
 	"go.viam.com/rdk/services/slam"
 	"go.viam.com/rdk/spatialmath"
@@ -118,6 +122,188 @@ func TestMove(t *testing.T) {
 	})
 }
 
+func TestMoveAsync(t *testing.T) {
+	ctx := context.Background()
+	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+	defer teardown()
+	executor, ok := ms.(motion.PlanExecutor)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	t.Run("fails to plan", func(t *testing.T) {
+		grabPose := referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{0, -30, -50}))
+		_, err := executor.MoveAsync(ctx, camera.Named("fake"), grabPose, nil, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("reports progress until the plan succeeds", func(t *testing.T) {
+		grabPose := referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{0, -30, -50}))
+		id, err := executor.MoveAsync(ctx, gripper.Named("pieceGripper"), grabPose, nil, nil, nil)
+		test.That(t, err, test.ShouldBeNil)
+		progress, err := executor.PlanProgress(ctx, id)
+		test.That(t, err, test.ShouldBeNil)
+
+		var last motion.PlanStatus
+		for status := range progress {
+			test.That(t, status.ID, test.ShouldEqual, id)
+			last = status
+		}
+		test.That(t, last.State, test.ShouldEqual, motion.PlanStateSucceeded)
+		test.That(t, last.PercentComplete(), test.ShouldEqual, 100.0)
+
+		status, err := executor.PlanStatus(ctx, id)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, status, test.ShouldResemble, last)
+
+		// stopping a finished plan does nothing
+		test.That(t, executor.StopPlan(ctx, id), test.ShouldBeNil)
+		resp, err := ms.DoCommand(ctx, map[string]interface{}{motion.GetPlanStatusCommand: string(id)})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp["state"], test.ShouldEqual, "succeeded")
+	})
+
+	t.Run("unknown plan", func(t *testing.T) {
+		_, err := executor.PlanStatus(ctx, "nope")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, executor.StopPlan(ctx, "nope"), test.ShouldNotBeNil)
+	})
+}
+
+func TestPlanHistory(t *testing.T) {
+	ctx := context.Background()
+	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+	defer teardown()
+	history, ok := ms.(motion.PlanHistory)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	records, err := history.ListPlans(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, records, test.ShouldBeEmpty)
+
+	grabPose := referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{0, -30, -50}))
+	_, err = ms.Move(ctx, gripper.Named("pieceGripper"), grabPose, nil, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	id, err := ms.(motion.PlanExecutor).MoveAsync(ctx, gripper.Named("pieceGripper"), grabPose, nil, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	progress, err := ms.(motion.PlanExecutor).PlanProgress(ctx, id)
+	test.That(t, err, test.ShouldBeNil)
+	for status := range progress {
+		test.That(t, status.ID, test.ShouldEqual, id)
+	}
+
+	records, err = history.ListPlans(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, records, test.ShouldHaveLength, 2)
+	test.That(t, records[0].Status.ID, test.ShouldEqual, id)
+	for _, record := range records {
+		test.That(t, record.ComponentName, test.ShouldResemble, gripper.Named("pieceGripper"))
+		test.That(t, record.Destination, test.ShouldResemble, grabPose)
+		test.That(t, record.Trajectory, test.ShouldNotBeEmpty)
+		test.That(t, record.Status.State, test.ShouldEqual, motion.PlanStateSucceeded)
+		test.That(t, record.EndTime.Before(record.StartTime), test.ShouldBeFalse)
+	}
+
+	record, err := history.GetPlan(ctx, records[1].Status.ID)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, record, test.ShouldResemble, records[1])
+	_, err = history.GetPlan(ctx, "nope")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	resp, err := ms.DoCommand(ctx, map[string]interface{}{motion.ListPlansCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["plans"], test.ShouldHaveLength, 2)
+	resp, err = ms.DoCommand(ctx, map[string]interface{}{motion.GetPlanCommand: string(id)})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["state"], test.ShouldEqual, "succeeded")
+	test.That(t, resp["destination"], test.ShouldNotBeNil)
+	test.That(t, resp["trajectory"], test.ShouldHaveLength, len(records[0].Trajectory))
+}
+
+func TestPlanStepTimes(t *testing.T) {
+	ctx := context.Background()
+	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+	defer teardown()
+
+	grabPose := referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{0, -30, -50}))
+	extra := map[string]interface{}{motionplan.MaxLinearVelocityKey: 1e4}
+	_, err := ms.Move(ctx, gripper.Named("pieceGripper"), grabPose, nil, nil, extra)
+	test.That(t, err, test.ShouldBeNil)
+
+	records, err := ms.(motion.PlanHistory).ListPlans(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, records, test.ShouldHaveLength, 1)
+	stepTimes := records[0].StepTimes
+	test.That(t, stepTimes, test.ShouldHaveLength, len(records[0].Trajectory))
+	for i := 1; i < len(stepTimes); i++ {
+		test.That(t, stepTimes[i], test.ShouldBeGreaterThanOrEqualTo, stepTimes[i-1])
+	}
+
+	resp, err := ms.DoCommand(ctx, map[string]interface{}{motion.GetPlanCommand: string(records[0].Status.ID)})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["step_times_sec"], test.ShouldHaveLength, len(stepTimes))
+}
+
+func TestExecutePlan(t *testing.T) {
+	ctx := context.Background()
+	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+	defer teardown()
+
+	grabPose := referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{0, -30, -50}))
+	_, err := ms.Move(ctx, gripper.Named("pieceGripper"), grabPose, nil, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	records, err := ms.(motion.PlanHistory).ListPlans(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, records, test.ShouldHaveLength, 1)
+
+	data, err := motionplan.MarshalPlan(records[0].Trajectory)
+	test.That(t, err, test.ShouldBeNil)
+	plan, err := motionplan.UnmarshalPlan(data)
+	test.That(t, err, test.ShouldBeNil)
+	id, err := ms.(motion.PlanReplayer).ExecutePlan(ctx, plan)
+	test.That(t, err, test.ShouldBeNil)
+	record, err := ms.(motion.PlanHistory).GetPlan(ctx, id)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, record.Status.State, test.ShouldEqual, motion.PlanStateSucceeded)
+	test.That(t, record.Trajectory, test.ShouldResemble, plan)
+
+	var args map[string]interface{}
+	test.That(t, json.Unmarshal(data, &args), test.ShouldBeNil)
+	resp, err := ms.DoCommand(ctx, map[string]interface{}{motion.ExecutePlanCommand: args})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["id"], test.ShouldNotBeEmpty)
+
+	_, err = ms.(motion.PlanReplayer).ExecutePlan(ctx, []map[string][]referenceframe.Input{{"nope": {{Value: 1}}}})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestMoveToAny(t *testing.T) {
+	ctx := context.Background()
+	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+	defer teardown()
+
+	unreachable := referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{0, -30000, -50}))
+	reachable := referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{0, -30, -50}))
+	destinations := []*referenceframe.PoseInFrame{unreachable, reachable}
+	goal, err := ms.(motion.MultiGoalMover).MoveToAny(ctx, gripper.Named("pieceGripper"), destinations, nil, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, goal, test.ShouldEqual, 1)
+	records, err := ms.(motion.PlanHistory).ListPlans(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, records, test.ShouldHaveLength, 1)
+	test.That(t, records[0].Destination, test.ShouldEqual, reachable)
+
+	resp, err := ms.DoCommand(ctx, map[string]interface{}{motion.MoveToAnyCommand: map[string]interface{}{
+		"component_name": gripper.Named("pieceGripper").String(),
+		"destinations": []interface{}{
+			map[string]interface{}{"referenceFrame": "c", "pose": map[string]interface{}{"x": 0, "y": -30, "z": -50, "oZ": 1}},
+		},
+	}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["destination_index"], test.ShouldEqual, 0)
+
+	_, err = ms.(motion.MultiGoalMover).MoveToAny(ctx, gripper.Named("pieceGripper"), destinations[:1], nil, nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 func TestMoveWithObstacles(t *testing.T) {
 	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
 	defer teardown()
@@ -309,3 +495,56 @@ func TestGetPose(t *testing.T) {
 	test.That(t, err, test.ShouldBeError, framesystemparts.NewMissingParentError("testFrame", "noParent"))
 	test.That(t, pose, test.ShouldBeNil)
 }
+
+func TestValidateReplanPolicy(t *testing.T) {
+	for _, policy := range []motion.ReplanPolicy{"", motion.ReplanNever, motion.ReplanOnObstacle} {
+		cfg := &builtin.Config{ReplanPolicy: policy}
+		_, err := cfg.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+	}
+
+	cfg := &builtin.Config{ReplanPolicy: motion.ReplanPeriodic}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "replan_period_sec")
+
+	cfg.ReplanPeriodSec = 0.5
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	cfg = &builtin.Config{ReplanPolicy: "sometimes"}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cfg = &builtin.Config{MaxReplans: -1}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestMaxReplans(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+	cfg, err := config.Read(ctx, "../data/moving_arm.json", logger)
+	test.That(t, err, test.ShouldBeNil)
+	// a period this short plans again before every step, so the move never gets past its first step
+	cfg.Services = append(cfg.Services, resource.Config{
+		Name:  "builtin",
+		API:   motion.API,
+		Model: resource.DefaultServiceModel,
+		ConvertedAttributes: &builtin.Config{
+			ReplanPolicy:    motion.ReplanPeriodic,
+			ReplanPeriodSec: 1e-9,
+			MaxReplans:      2,
+		},
+	})
+	myRobot, err := robotimpl.New(ctx, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer myRobot.Close(context.Background())
+	ms, err := motion.FromRobot(myRobot, "builtin")
+	test.That(t, err, test.ShouldBeNil)
+
+	grabPose := referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{0, -30, -50}))
+	_, err = ms.Move(ctx, gripper.Named("pieceGripper"), grabPose, nil, nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "after planning again 2 times")
+}
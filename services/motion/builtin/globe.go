@@ -0,0 +1,237 @@
+package builtin
+
+import (
+	"context"
+	"math"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	defaultLinearMmPerSec    = 300
+	defaultAngularDegsPerSec = 60
+	// The radius used for collision checking of bases that do not report their width.
+	defaultBaseRadiusMm = 300
+	// How close to the destination the base has to get, given the accuracy of most GPS receivers.
+	globeArrivalToleranceMm = 1000
+	// How many times the base will try to correct its position once it has driven the plan.
+	maxGlobeCorrections = 3
+)
+
+// MoveOnGlobe plans a path for the base from its current location to the destination around the obstacles, in a local frame
+// centered on where it starts, and then drives each leg of the path, using the movement sensor to steer. The base and movement
+// sensor must be those in the config of the service.
+func (ms *builtIn) MoveOnGlobe(
+	ctx context.Context,
+	componentName resource.Name,
+	destination *geo.Point,
+	heading float64,
+	movementSensorName resource.Name,
+	obstacles []*motion.GeoObstacle,
+	linearMmPerSec float64,
+	angularDegsPerSec float64,
+	extra map[string]interface{},
+) (bool, error) {
+	operation.CancelOtherWithLabel(ctx, "motion-service")
+
+	ms.lock.Lock()
+	baseRes, baseOK := ms.components[componentName]
+	sensorRes, sensorOK := ms.components[movementSensorName]
+	ms.lock.Unlock()
+	if !baseOK {
+		return false, resource.DependencyNotFoundError(componentName)
+	}
+	b, ok := baseRes.(base.Base)
+	if !ok {
+		return false, resource.TypeError[base.Base](baseRes)
+	}
+	if !sensorOK {
+		return false, resource.DependencyNotFoundError(movementSensorName)
+	}
+	movementSensor, ok := sensorRes.(movementsensor.MovementSensor)
+	if !ok {
+		return false, resource.TypeError[movementsensor.MovementSensor](sensorRes)
+	}
+	props, err := movementSensor.Properties(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	if !props.PositionSupported || !props.CompassHeadingSupported {
+		return false, errors.Errorf("movement sensor %q must support position and compass heading", movementSensorName.ShortName())
+	}
+	if linearMmPerSec == 0 {
+		linearMmPerSec = defaultLinearMmPerSec
+	}
+	if angularDegsPerSec == 0 {
+		angularDegsPerSec = defaultAngularDegsPerSec
+	}
+
+	origin, _, err := movementSensor.Position(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	goal := motion.GeoPointToLocal(origin, destination)
+	waypoints, err := ms.planOnGlobe(ctx, b, origin, goal, obstacles, extra)
+	if err != nil {
+		return false, err
+	}
+
+	nav := globeNavigator{
+		base:              b,
+		sensor:            movementSensor,
+		origin:            origin,
+		linearMmPerSec:    linearMmPerSec,
+		angularDegsPerSec: angularDegsPerSec,
+	}
+	for _, waypoint := range waypoints {
+		if _, err := nav.driveTo(ctx, waypoint); err != nil {
+			return false, err
+		}
+	}
+	arrived := false
+	for i := 0; i <= maxGlobeCorrections && !arrived; i++ {
+		remaining, err := nav.driveTo(ctx, goal)
+		if err != nil {
+			return false, err
+		}
+		arrived = remaining <= globeArrivalToleranceMm
+	}
+	if !arrived {
+		return false, errors.New("could not get within tolerance of the destination")
+	}
+
+	if !math.IsNaN(heading) {
+		current, err := movementSensor.CompassHeading(ctx, nil)
+		if err != nil {
+			return false, err
+		}
+		if err := b.Spin(ctx, normalizeDegrees(current-heading), angularDegsPerSec, nil); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// planOnGlobe plans a collision free path for the base from the origin of the local frame to the goal, returning the waypoints
+// along it.
+func (ms *builtIn) planOnGlobe(
+	ctx context.Context,
+	b base.Base,
+	origin *geo.Point,
+	goal r3.Vector,
+	obstacles []*motion.GeoObstacle,
+	extra map[string]interface{},
+) ([]r3.Vector, error) {
+	worldState, err := motion.GeoObstaclesToWorldState(origin, obstacles)
+	if err != nil {
+		return nil, err
+	}
+	if worldState == nil {
+		// nothing to avoid, so drive straight there
+		return []r3.Vector{goal}, nil
+	}
+
+	radius := float64(defaultBaseRadiusMm)
+	if lb, ok := b.(base.LocalBase); ok {
+		width, err := lb.Width(ctx)
+		if err != nil {
+			return nil, err
+		}
+		radius = float64(width) / 2
+	}
+	geometry, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), radius, b.Name().ShortName())
+	if err != nil {
+		return nil, err
+	}
+	// leave room to drive around obstacles on the way
+	extent := 2*goal.Norm() + 10*radius
+	limits := []referenceframe.Limit{{Min: -extent, Max: extent}, {Min: -extent, Max: extent}}
+	baseFrame, err := referenceframe.NewMobile2DFrame(b.Name().ShortName(), limits, geometry)
+	if err != nil {
+		return nil, err
+	}
+	fs := referenceframe.NewEmptySimpleFrameSystem("globe")
+	if err := fs.AddFrame(baseFrame, fs.World()); err != nil {
+		return nil, err
+	}
+
+	steps, err := motionplan.PlanMotion(
+		ctx,
+		ms.logger,
+		referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(goal)),
+		baseFrame,
+		map[string][]referenceframe.Input{baseFrame.Name(): referenceframe.FloatsToInputs([]float64{0, 0})},
+		fs,
+		worldState,
+		nil,
+		extra,
+	)
+	if err != nil {
+		return nil, err
+	}
+	frameSteps, err := motionplan.FrameStepsFromRobotPath(baseFrame.Name(), steps)
+	if err != nil {
+		return nil, err
+	}
+	waypoints := make([]r3.Vector, 0, len(frameSteps))
+	for _, step := range frameSteps {
+		waypoints = append(waypoints, r3.Vector{X: step[0].Value, Y: step[1].Value})
+	}
+	return waypoints, nil
+}
+
+// globeNavigator drives a base to points in a local frame centered on origin, with +X pointing east and +Y pointing north.
+type globeNavigator struct {
+	base              base.Base
+	sensor            movementsensor.MovementSensor
+	origin            *geo.Point
+	linearMmPerSec    float64
+	angularDegsPerSec float64
+}
+
+// driveTo turns the base towards the target and drives straight to it, returning how far from the target it was before moving.
+func (n *globeNavigator) driveTo(ctx context.Context, target r3.Vector) (float64, error) {
+	position, _, err := n.sensor.Position(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	heading, err := n.sensor.CompassHeading(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	delta := target.Sub(motion.GeoPointToLocal(n.origin, position))
+	distance := delta.Norm()
+	if distance <= globeArrivalToleranceMm {
+		return distance, nil
+	}
+
+	// compass bearings are clockwise from north, while spins are counterclockwise
+	bearing := math.Atan2(delta.X, delta.Y) * 180 / math.Pi
+	if err := n.base.Spin(ctx, normalizeDegrees(heading-bearing), n.angularDegsPerSec, nil); err != nil {
+		return 0, err
+	}
+	if err := n.base.MoveStraight(ctx, int(distance), n.linearMmPerSec, nil); err != nil {
+		return 0, err
+	}
+	return distance, nil
+}
+
+// normalizeDegrees wraps an angle into [-180, 180).
+func normalizeDegrees(deg float64) float64 {
+	deg = math.Mod(deg+180, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg - 180
+}
@@ -0,0 +1,98 @@
+package builtin
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/edaniels/golog"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// newSimulatedRover returns a base that moves the position and compass heading reported by the movement sensor.
+func newSimulatedRover(start *geo.Point) (*inject.Base, *inject.MovementSensor) {
+	position := start
+	heading := 0.
+	injectBase := inject.NewBase("rover")
+	injectBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		// spins are counterclockwise while compass headings are clockwise
+		heading = math.Mod(heading-angleDeg+360, 360)
+		return nil
+	}
+	injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+		position = position.PointAtDistanceAndBearing(float64(distanceMm)/1e6, heading)
+		return nil
+	}
+	injectSensor := inject.NewMovementSensor("gps")
+	injectSensor.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+		return &movementsensor.Properties{PositionSupported: true, CompassHeadingSupported: true}, nil
+	}
+	injectSensor.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		return position, 0, nil
+	}
+	injectSensor.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		return heading, nil
+	}
+	return injectBase, injectSensor
+}
+
+func TestMoveOnGlobe(t *testing.T) {
+	ctx := context.Background()
+	start := geo.NewPoint(40.7, -73.98)
+	injectBase, injectSensor := newSimulatedRover(start)
+	ms := &builtIn{
+		logger: golog.NewTestLogger(t),
+		plans:  newPlanTracker(),
+		components: map[resource.Name]resource.Resource{
+			base.Named("rover"):         injectBase,
+			movementsensor.Named("gps"): injectSensor,
+		},
+	}
+
+	destination := start.PointAtDistanceAndBearing(0.02, 45)
+	success, err := ms.MoveOnGlobe(ctx, base.Named("rover"), destination, 90, movementsensor.Named("gps"), nil, 0, 0, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, success, test.ShouldBeTrue)
+	position, _, err := injectSensor.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, motion.GeoPointToLocal(destination, position).Norm(), test.ShouldBeLessThanOrEqualTo, globeArrivalToleranceMm)
+	heading, err := injectSensor.CompassHeading(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, heading, test.ShouldAlmostEqual, 90, 1e-6)
+
+	// only the configured base and movement sensor can be used
+	_, err = ms.MoveOnGlobe(ctx, base.Named("other"), destination, math.NaN(), movementsensor.Named("gps"), nil, 0, 0, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	injectSensor.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+		return &movementsensor.Properties{PositionSupported: true}, nil
+	}
+	_, err = ms.MoveOnGlobe(ctx, base.Named("rover"), destination, math.NaN(), movementsensor.Named("gps"), nil, 0, 0, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "compass heading")
+}
+
+func TestValidateGlobeConfig(t *testing.T) {
+	cfg := Config{Base: "rover"}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cfg.MovementSensor = "gps"
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldContain, base.Named("rover").String())
+	test.That(t, deps, test.ShouldContain, movementsensor.Named("gps").String())
+}
+
+func TestNormalizeDegrees(t *testing.T) {
+	test.That(t, normalizeDegrees(190), test.ShouldEqual, -170)
+	test.That(t, normalizeDegrees(-190), test.ShouldEqual, 170)
+	test.That(t, normalizeDegrees(45), test.ShouldEqual, 45)
+}
@@ -0,0 +1,402 @@
+package builtin
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	servicepb "go.viam.com/api/service/motion/v1"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+)
+
+// maxFinishedPlans is how many finished plans are remembered so their final status and record can still be queried.
+const maxFinishedPlans = 100
+
+type planExecution struct {
+	cancel func()
+	// resources are those the plan can move, which are stopped when the plan is stopped.
+	resources map[string]referenceframe.InputEnabled
+	// done is closed once the plan is no longer being executed.
+	done      chan struct{}
+	record    motion.PlanRecord
+	listeners []chan motion.PlanStatus
+}
+
+// planTracker keeps track of the progress of plans, whether they run in the background or not, and remembers recent plans.
+type planTracker struct {
+	mu       sync.Mutex
+	plans    map[motion.PlanID]*planExecution
+	order    []motion.PlanID
+	finished []motion.PlanID
+
+	activeBackgroundWorkers sync.WaitGroup
+}
+
+func newPlanTracker() *planTracker {
+	return &planTracker{plans: map[motion.PlanID]*planExecution{}}
+}
+
+// add starts tracking a plan that is about to be executed with the given resources. Stopping the plan calls cancel and then stops
+// the resources it moves.
+func (pt *planTracker) add(
+	record motion.PlanRecord,
+	resources map[string]referenceframe.InputEnabled,
+	cancel func(),
+) motion.PlanID {
+	id := motion.PlanID(uuid.NewString())
+	record.StartTime = time.Now()
+	record.Status = motion.PlanStatus{ID: id, State: motion.PlanStateInProgress, TotalWaypoints: len(record.Trajectory)}
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.plans[id] = &planExecution{cancel: cancel, resources: resources, done: make(chan struct{}), record: record}
+	pt.order = append(pt.order, id)
+	return id
+}
+
+// run executes a tracked plan and records how it ended.
+func (pt *planTracker) run(ctx context.Context, id motion.PlanID, run func(ctx context.Context, id motion.PlanID) error) error {
+	pt.mu.Lock()
+	plan := pt.plans[id]
+	pt.mu.Unlock()
+	defer close(plan.done)
+
+	err := run(ctx, id)
+	pt.update(id, func(r *motion.PlanRecord) {
+		switch {
+		case err == nil:
+			r.Status.State = motion.PlanStateSucceeded
+			r.Status.Waypoint = r.Status.TotalWaypoints
+		case ctx.Err() != nil:
+			r.Status.State = motion.PlanStateStopped
+		default:
+			r.Status.State = motion.PlanStateFailed
+			r.Status.Err = err
+		}
+	})
+	return err
+}
+
+// start executes a plan in the background, once any plan already in progress has been stopped.
+func (pt *planTracker) start(
+	ctx context.Context,
+	record motion.PlanRecord,
+	resources map[string]referenceframe.InputEnabled,
+	run func(ctx context.Context, id motion.PlanID) error,
+) (motion.PlanID, error) {
+	if err := pt.stopAll(ctx); err != nil {
+		return "", err
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	id := pt.add(record, resources, cancel)
+	pt.activeBackgroundWorkers.Add(1)
+	utils.ManagedGo(func() {
+		//nolint:errcheck
+		pt.run(runCtx, id, run)
+	}, pt.activeBackgroundWorkers.Done)
+	return id, nil
+}
+
+// reached records that a plan is moving to the given waypoint.
+func (pt *planTracker) reached(id motion.PlanID, waypoint int) {
+	pt.update(id, func(r *motion.PlanRecord) {
+		r.Status.Waypoint = waypoint
+	})
+}
+
+// replanned records that a plan was made again partway through, with done waypoints already reached.
+func (pt *planTracker) replanned(
+	id motion.PlanID,
+	done int,
+	steps []map[string][]referenceframe.Input,
+	resources map[string]referenceframe.InputEnabled,
+	worldState *referenceframe.WorldState,
+) {
+	pt.mu.Lock()
+	if plan, ok := pt.plans[id]; ok {
+		plan.resources = resources
+	}
+	pt.mu.Unlock()
+	pt.update(id, func(r *motion.PlanRecord) {
+		r.Replans++
+		r.Trajectory = steps
+		r.WorldState = worldState
+		r.Status.TotalWaypoints = done + len(steps)
+	})
+}
+
+// scheduled records when each step of the trajectory a plan is executing is scheduled to be reached.
+func (pt *planTracker) scheduled(id motion.PlanID, times []time.Duration) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if plan, ok := pt.plans[id]; ok {
+		plan.record.StepTimes = times
+	}
+}
+
+// update changes the record of a plan that is still in progress and notifies its listeners.
+func (pt *planTracker) update(id motion.PlanID, f func(*motion.PlanRecord)) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	plan, ok := pt.plans[id]
+	if !ok || plan.record.Status.State.Done() {
+		return
+	}
+	f(&plan.record)
+	for _, listener := range plan.listeners {
+		select {
+		case listener <- plan.record.Status:
+		default:
+		}
+	}
+	if !plan.record.Status.State.Done() {
+		return
+	}
+
+	plan.record.EndTime = time.Now()
+	plan.cancel()
+	for _, listener := range plan.listeners {
+		close(listener)
+	}
+	plan.listeners = nil
+	pt.finished = append(pt.finished, id)
+	if len(pt.finished) > maxFinishedPlans {
+		oldest := pt.finished[0]
+		delete(pt.plans, oldest)
+		pt.finished = pt.finished[1:]
+		for i, other := range pt.order {
+			if other == oldest {
+				pt.order = append(pt.order[:i], pt.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// stop stops executing a plan, waits for its execution to end and then stops the resources it was moving, so that none keep
+// moving towards the last inputs they were sent.
+func (pt *planTracker) stop(ctx context.Context, id motion.PlanID) error {
+	pt.mu.Lock()
+	plan, ok := pt.plans[id]
+	pt.mu.Unlock()
+	if !ok {
+		return errors.Errorf("no plan with id %q", id)
+	}
+	return pt.stopExecution(ctx, id, plan)
+}
+
+func (pt *planTracker) stopAll(ctx context.Context) error {
+	pt.mu.Lock()
+	plans := make(map[motion.PlanID]*planExecution, len(pt.plans))
+	for id, plan := range pt.plans {
+		if !plan.record.Status.State.Done() {
+			plans[id] = plan
+		}
+	}
+	pt.mu.Unlock()
+	var err error
+	for id, plan := range plans {
+		err = multierr.Combine(err, pt.stopExecution(ctx, id, plan))
+	}
+	return err
+}
+
+func (pt *planTracker) stopExecution(ctx context.Context, id motion.PlanID, plan *planExecution) error {
+	pt.mu.Lock()
+	inProgress := !plan.record.Status.State.Done()
+	pt.mu.Unlock()
+	if !inProgress {
+		return nil
+	}
+	pt.update(id, func(r *motion.PlanRecord) { r.Status.State = motion.PlanStateStopped })
+	select {
+	case <-plan.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	pt.mu.Lock()
+	moving := map[string]bool{}
+	for _, step := range plan.record.Trajectory {
+		for name, inputs := range step {
+			if len(inputs) > 0 {
+				moving[name] = true
+			}
+		}
+	}
+	resources := plan.resources
+	pt.mu.Unlock()
+	var err error
+	for name := range moving {
+		if actuator, ok := resources[name].(resource.Actuator); ok {
+			err = multierr.Combine(err, actuator.Stop(ctx, nil))
+		}
+	}
+	return err
+}
+
+func (pt *planTracker) get(id motion.PlanID) (motion.PlanRecord, error) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	plan, ok := pt.plans[id]
+	if !ok {
+		return motion.PlanRecord{}, errors.Errorf("no plan with id %q", id)
+	}
+	return plan.record, nil
+}
+
+// list returns the records of the tracked plans, newest first.
+func (pt *planTracker) list() []motion.PlanRecord {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	records := make([]motion.PlanRecord, 0, len(pt.order))
+	for i := len(pt.order) - 1; i >= 0; i-- {
+		records = append(records, pt.plans[pt.order[i]].record)
+	}
+	return records
+}
+
+func (pt *planTracker) progress(id motion.PlanID) (<-chan motion.PlanStatus, error) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	plan, ok := pt.plans[id]
+	if !ok {
+		return nil, errors.Errorf("no plan with id %q", id)
+	}
+	// Every waypoint and the final state fit in the buffer, so no update is ever dropped.
+	listener := make(chan motion.PlanStatus, plan.record.Status.TotalWaypoints+2)
+	listener <- plan.record.Status
+	if plan.record.Status.State.Done() {
+		close(listener)
+	} else {
+		plan.listeners = append(plan.listeners, listener)
+	}
+	return listener, nil
+}
+
+// close stops every plan and waits for the ones in the background to finish.
+func (pt *planTracker) close(ctx context.Context) error {
+	err := pt.stopAll(ctx)
+	pt.activeBackgroundWorkers.Wait()
+	return err
+}
+
+// MoveAsync plans a move and executes it in the background.
+func (ms *builtIn) MoveAsync(
+	ctx context.Context,
+	componentName resource.Name,
+	destination *referenceframe.PoseInFrame,
+	worldState *referenceframe.WorldState,
+	constraints *servicepb.Constraints,
+	extra map[string]interface{},
+) (motion.PlanID, error) {
+	opts, err := motionplan.RequestOptionsFromExtra(extra)
+	if err != nil {
+		return "", err
+	}
+	steps, resources, plannedWorldState, err := ms.planMove(ctx, componentName, destination, worldState, constraints, extra)
+	if err != nil {
+		return "", err
+	}
+	req := moveRequest{componentName, destination, worldState, constraints, extra, opts.PathLimits}
+	return ms.plans.start(ctx, req.record(steps, plannedWorldState), resources, func(ctx context.Context, id motion.PlanID) error {
+		return ms.execute(ctx, id, req, steps, resources)
+	})
+}
+
+// StopPlan stops executing the given plan and stops the components it was moving.
+func (ms *builtIn) StopPlan(ctx context.Context, id motion.PlanID) error {
+	return ms.plans.stop(ctx, id)
+}
+
+// PlanStatus returns the current status of the given plan.
+func (ms *builtIn) PlanStatus(ctx context.Context, id motion.PlanID) (motion.PlanStatus, error) {
+	record, err := ms.plans.get(id)
+	if err != nil {
+		return motion.PlanStatus{}, err
+	}
+	return record.Status, nil
+}
+
+// PlanProgress returns a channel that receives the status of the given plan every time it changes.
+func (ms *builtIn) PlanProgress(ctx context.Context, id motion.PlanID) (<-chan motion.PlanStatus, error) {
+	return ms.plans.progress(id)
+}
+
+// ListPlans returns the records of recent plans, newest first.
+func (ms *builtIn) ListPlans(ctx context.Context) ([]motion.PlanRecord, error) {
+	return ms.plans.list(), nil
+}
+
+// GetPlan returns the record of the given plan.
+func (ms *builtIn) GetPlan(ctx context.Context, id motion.PlanID) (motion.PlanRecord, error) {
+	return ms.plans.get(id)
+}
+
+// DoCommand supports "move_async", "stop_plan", "get_plan_status", "list_plans", "get_plan" and "execute_plan" so that plans can
+// be started, managed, inspected and replayed through a remote motion service, and "move_on_globe" and "move_to_any" which the
+// motion service client uses for MoveOnGlobe and MoveToAny.
+func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if args, ok := cmd[motion.MoveOnGlobeCommand]; ok {
+		return motion.DoMoveOnGlobeCommand(ctx, ms, args)
+	}
+	if args, ok := cmd[motion.MoveAsyncCommand]; ok {
+		return motion.DoMoveAsyncCommand(ctx, ms, args)
+	}
+	if id, ok := cmd[motion.StopPlanCommand].(string); ok {
+		return map[string]interface{}{}, ms.StopPlan(ctx, motion.PlanID(id))
+	}
+	if id, ok := cmd[motion.GetPlanStatusCommand].(string); ok {
+		status, err := ms.PlanStatus(ctx, motion.PlanID(id))
+		if err != nil {
+			return nil, err
+		}
+		return status.ToMap(), nil
+	}
+	if _, ok := cmd[motion.ListPlansCommand]; ok {
+		records, err := ms.ListPlans(ctx)
+		if err != nil {
+			return nil, err
+		}
+		plans := make([]interface{}, 0, len(records))
+		for _, record := range records {
+			plans = append(plans, record.Summary())
+		}
+		return map[string]interface{}{"plans": plans}, nil
+	}
+	if id, ok := cmd[motion.GetPlanCommand].(string); ok {
+		record, err := ms.GetPlan(ctx, motion.PlanID(id))
+		if err != nil {
+			return nil, err
+		}
+		return record.ToMap()
+	}
+	if args, ok := cmd[motion.MoveToAnyCommand]; ok {
+		return motion.DoMoveToAnyCommand(ctx, ms, args)
+	}
+	if args, ok := cmd[motion.ExecutePlanCommand]; ok {
+		plan, err := decodePlan(args)
+		if err != nil {
+			return nil, err
+		}
+		id, err := ms.ExecutePlan(ctx, plan)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"id": string(id)}, nil
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// Close stops any plans that are still executing.
+func (ms *builtIn) Close(ctx context.Context) error {
+	return ms.plans.close(ctx)
+}
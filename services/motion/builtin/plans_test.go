@@ -0,0 +1,65 @@
+package builtin
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestPlanTrackerStop(t *testing.T) {
+	ctx := context.Background()
+	pt := newPlanTracker()
+
+	var armStops, otherStops int32
+	injectArm := inject.NewArm("arm")
+	injectArm.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		atomic.AddInt32(&armStops, 1)
+		return nil
+	}
+	injectOther := inject.NewArm("other")
+	injectOther.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		atomic.AddInt32(&otherStops, 1)
+		return nil
+	}
+	resources := map[string]referenceframe.InputEnabled{"arm": injectArm, "other": injectOther}
+	record := motion.PlanRecord{Trajectory: []map[string][]referenceframe.Input{{"arm": {{Value: 1}}, "other": {}}}}
+
+	// a plan that runs until it is stopped, and records whether it was still running when the next one started
+	var running, overlapped int32
+	runUntilStopped := func(ctx context.Context, id motion.PlanID) error {
+		if !atomic.CompareAndSwapInt32(&running, 0, 1) {
+			atomic.StoreInt32(&overlapped, 1)
+		}
+		<-ctx.Done()
+		atomic.StoreInt32(&running, 0)
+		return ctx.Err()
+	}
+
+	first, err := pt.start(ctx, record, resources, runUntilStopped)
+	test.That(t, err, test.ShouldBeNil)
+	second, err := pt.start(ctx, record, resources, runUntilStopped)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, atomic.LoadInt32(&overlapped), test.ShouldEqual, 0)
+
+	// only the components the plan moves are stopped
+	test.That(t, atomic.LoadInt32(&armStops), test.ShouldEqual, 1)
+	test.That(t, atomic.LoadInt32(&otherStops), test.ShouldEqual, 0)
+	status, err := pt.get(first)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status.Status.State, test.ShouldEqual, motion.PlanStateStopped)
+
+	test.That(t, pt.stop(ctx, second), test.ShouldBeNil)
+	test.That(t, atomic.LoadInt32(&running), test.ShouldEqual, 0)
+	test.That(t, atomic.LoadInt32(&armStops), test.ShouldEqual, 2)
+
+	// stopping a plan that is done does not stop its components again
+	test.That(t, pt.stop(ctx, second), test.ShouldBeNil)
+	test.That(t, atomic.LoadInt32(&armStops), test.ShouldEqual, 2)
+	test.That(t, pt.close(ctx), test.ShouldBeNil)
+}
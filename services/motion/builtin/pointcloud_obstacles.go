@@ -0,0 +1,65 @@
+package builtin
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// PointCloudObstacleConfig names a depth camera whose whole point cloud should be avoided, without first segmenting it into objects.
+type PointCloudObstacleConfig struct {
+	Camera string `json:"camera"`
+	// CollisionBufferMm is how close to any point of the cloud counts as a collision; it defaults to touching the point.
+	CollisionBufferMm float64 `json:"collision_buffer_mm,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (c *PointCloudObstacleConfig) Validate(path string) error {
+	if c.Camera == "" {
+		return utils.NewConfigValidationFieldRequiredError(path, "camera")
+	}
+	if c.CollisionBufferMm < 0 {
+		return utils.NewConfigValidationError(path, errors.New("collision_buffer_mm cannot be negative"))
+	}
+	return nil
+}
+
+// pointCloudObstacleSource reports a camera's latest point cloud as a single octree obstacle in the camera's frame, which the frame
+// system places in the world.
+type pointCloudObstacleSource struct {
+	camera camera.Camera
+	buffer float64
+}
+
+func newPointCloudObstacleSource(deps resource.Dependencies, cfg PointCloudObstacleConfig) (*pointCloudObstacleSource, error) {
+	cam, err := camera.FromDependencies(deps, cfg.Camera)
+	if err != nil {
+		return nil, err
+	}
+	return &pointCloudObstacleSource{camera: cam, buffer: cfg.CollisionBufferMm}, nil
+}
+
+func (s *pointCloudObstacleSource) Obstacles(ctx context.Context) ([]*referenceframe.GeometriesInFrame, error) {
+	name := s.camera.Name().ShortName()
+	pc, err := s.camera.NextPointCloud(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get point cloud from camera %q", name)
+	}
+	if pc.Size() == 0 {
+		return nil, nil
+	}
+	octree, err := pointcloud.NewBasicOctreeFromPointCloud(pc)
+	if err != nil {
+		return nil, err
+	}
+	octree.SetLabel(name + "_pointcloud")
+	octree.SetCollisionBuffer(s.buffer)
+	return []*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(name, []spatialmath.Geometry{octree})}, nil
+}
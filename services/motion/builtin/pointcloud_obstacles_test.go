@@ -0,0 +1,62 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestPointCloudObstacleSource(t *testing.T) {
+	pc := pointcloud.New()
+	test.That(t, pc.Set(r3.Vector{Z: 500}, nil), test.ShouldBeNil)
+	test.That(t, pc.Set(r3.Vector{X: 10, Z: 500}, nil), test.ShouldBeNil)
+	injectCamera := inject.NewCamera("cam")
+	injectCamera.NextPointCloudFunc = func(ctx context.Context) (pointcloud.PointCloud, error) {
+		return pc, nil
+	}
+	deps := resource.Dependencies{camera.Named("cam"): injectCamera}
+
+	_, err := newPointCloudObstacleSource(deps, PointCloudObstacleConfig{Camera: "missing"})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	source, err := newPointCloudObstacleSource(deps, PointCloudObstacleConfig{Camera: "cam", CollisionBufferMm: 5})
+	test.That(t, err, test.ShouldBeNil)
+	obstacles, err := source.Obstacles(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(obstacles), test.ShouldEqual, 1)
+	test.That(t, obstacles[0].Parent(), test.ShouldEqual, "cam")
+	test.That(t, obstacles[0].Geometries(), test.ShouldHaveLength, 1)
+	octree := obstacles[0].Geometries()[0]
+	test.That(t, octree.Label(), test.ShouldEqual, "cam_pointcloud")
+	test.That(t, octree.ToPoints(0), test.ShouldHaveLength, 2)
+
+	// the collision buffer is applied to every point
+	near, err := spatialmath.NewSphere(spatialmath.NewPoseFromPoint(r3.Vector{Z: 508}), 4, "")
+	test.That(t, err, test.ShouldBeNil)
+	collides, err := octree.CollidesWith(near)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeTrue)
+}
+
+func TestValidatePointCloudObstacles(t *testing.T) {
+	cfg := &Config{PointCloudObstacles: []PointCloudObstacleConfig{{}}}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cfg.PointCloudObstacles[0] = PointCloudObstacleConfig{Camera: "cam", CollisionBufferMm: -1}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cfg.PointCloudObstacles[0].CollisionBufferMm = 1
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldContain, camera.Named("cam").String())
+}
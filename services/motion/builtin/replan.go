@@ -0,0 +1,315 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	servicepb "go.viam.com/api/service/motion/v1"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// moveRequest holds the arguments of a move so it can be planned again while it is executing.
+type moveRequest struct {
+	componentName resource.Name
+	destination   *referenceframe.PoseInFrame
+	worldState    *referenceframe.WorldState
+	constraints   *servicepb.Constraints
+	extra         map[string]interface{}
+	limits        motionplan.PathLimits
+}
+
+// record returns the record of a plan for the request, before it is executed.
+func (req moveRequest) record(steps []map[string][]referenceframe.Input, worldState *referenceframe.WorldState) motion.PlanRecord {
+	return motion.PlanRecord{
+		ComponentName: req.componentName,
+		Destination:   req.destination,
+		WorldState:    worldState,
+		Constraints:   req.constraints,
+		Extra:         req.extra,
+		Trajectory:    steps,
+	}
+}
+
+// defaultMaxReplans is how many times a move is planned again before it fails, unless configured otherwise.
+const defaultMaxReplans = 20
+
+// execute moves all the components through each step of the tracked plan with the given ID, recording its progress.
+// Depending on the replan policy, it plans again from the current inputs before a step when an obstacle source reports an
+// obstacle in the way of the rest of the plan, or when the replan period has passed, and fails once it would plan again more
+// than the configured number of times. When the request has path limits, each step is paced to reach its inputs at the time
+// given by the time parameterization of the plan.
+func (ms *builtIn) execute(
+	ctx context.Context,
+	id motion.PlanID,
+	req moveRequest,
+	steps []map[string][]referenceframe.Input,
+	resources map[string]referenceframe.InputEnabled,
+) error {
+	ms.lock.Lock()
+	policy := ms.replanPolicy
+	period := ms.replanPeriod
+	maxReplans := ms.maxReplans
+	sources := ms.obstacleSources
+	ms.lock.Unlock()
+	if len(sources) == 0 && policy == motion.ReplanOnObstacle {
+		policy = motion.ReplanNever
+	}
+
+	times, err := ms.schedule(ctx, req, steps)
+	if err != nil {
+		return err
+	}
+	ms.plans.scheduled(id, times)
+	lastPlanned := time.Now()
+	replans := 0
+	// waypoints already reached in earlier plans, so progress keeps counting up across replans
+	done := 0
+	for i := 0; i < len(steps); i++ {
+		replan := false
+		var obstacles []*referenceframe.GeometriesInFrame
+		switch policy {
+		case motion.ReplanOnObstacle:
+			if obstacles, err = observeObstacles(ctx, sources); err != nil {
+				return err
+			}
+			if replan, err = ms.pathCollides(ctx, req, steps[i:], obstacles); err != nil {
+				return err
+			}
+		case motion.ReplanPeriodic:
+			replan = time.Since(lastPlanned) >= period
+		case motion.ReplanNever:
+		}
+
+		if replan {
+			if replans == maxReplans {
+				return errors.Errorf("gave up on moving %q after planning again %d times", req.componentName.ShortName(), replans)
+			}
+			replans++
+			// planning takes the obstacles reported by every source into account
+			ms.logger.Debugf("replanning move of %q at waypoint %d of %d", req.componentName.ShortName(), i, len(steps))
+			newSteps, newResources, worldState, err := ms.planMove(
+				ctx, req.componentName, req.destination, req.worldState, req.constraints, req.extra,
+			)
+			if err != nil {
+				return err
+			}
+			done += i
+			ms.plans.replanned(id, done, newSteps, newResources, worldState)
+			steps, resources, i = newSteps, newResources, 0
+			if times, err = ms.schedule(ctx, req, steps); err != nil {
+				return err
+			}
+			ms.plans.scheduled(id, times)
+			lastPlanned = time.Now()
+			if len(steps) == 0 {
+				break
+			}
+		}
+
+		ms.plans.reached(id, done+i)
+		if !req.limits.Limited() {
+			if err := executeStep(ctx, steps[i], resources); err != nil {
+				return err
+			}
+			continue
+		}
+		duration := times[i]
+		if i > 0 {
+			duration -= times[i-1]
+		}
+		if err := executeTimedStep(ctx, steps[i], resources, duration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schedule returns the times at which each step of a plan should be reached from the current inputs. Without path limits there
+// is nothing to schedule.
+func (ms *builtIn) schedule(
+	ctx context.Context,
+	req moveRequest,
+	steps []map[string][]referenceframe.Input,
+) ([]time.Duration, error) {
+	if !req.limits.Limited() {
+		return nil, nil
+	}
+	frameSys, err := ms.fsService.FrameSystem(ctx, req.worldState.Transforms())
+	if err != nil {
+		return nil, err
+	}
+	current, _, err := ms.fsService.AllCurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return motionplan.TimeParameterize(frameSys, req.componentName.ShortName(), current, steps, req.limits)
+}
+
+// executeTimedStep sends the inputs of the step to the components once and then waits until the step is scheduled to be
+// reached, so that the plan does not get ahead of its time parameterization.
+func executeTimedStep(
+	ctx context.Context,
+	step map[string][]referenceframe.Input,
+	resources map[string]referenceframe.InputEnabled,
+	duration time.Duration,
+) error {
+	start := time.Now()
+	if err := executeStep(ctx, step, resources); err != nil {
+		return err
+	}
+	if !utils.SelectContextOrWait(ctx, time.Until(start.Add(duration))) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// observeObstacles collects the obstacles currently reported by every source.
+func observeObstacles(ctx context.Context, sources []motion.ObstacleSource) ([]*referenceframe.GeometriesInFrame, error) {
+	var obstacles []*referenceframe.GeometriesInFrame
+	var errs error
+	for _, source := range sources {
+		found, err := source.Obstacles(ctx)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		obstacles = append(obstacles, found...)
+	}
+	return obstacles, errs
+}
+
+// withObstacles returns the world state with the given obstacles added to it.
+func (ms *builtIn) withObstacles(
+	ctx context.Context,
+	worldState *referenceframe.WorldState,
+	obstacles []*referenceframe.GeometriesInFrame,
+) (*referenceframe.WorldState, error) {
+	if len(obstacles) == 0 {
+		return worldState, nil
+	}
+	obstacles = nameObservedObstacles(obstacles)
+	if worldState == nil {
+		return referenceframe.NewWorldState(obstacles, nil)
+	}
+	frameSys, err := ms.fsService.FrameSystem(ctx, worldState.Transforms())
+	if err != nil {
+		return nil, err
+	}
+	inputs, _, err := ms.fsService.AllCurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := worldState.ObstaclesInWorldFrame(frameSys, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return referenceframe.NewWorldState(append([]*referenceframe.GeometriesInFrame{existing}, obstacles...), worldState.Transforms())
+}
+
+// nameObservedObstacles returns copies of the obstacles with every geometry given a name of its own, so that none clash with each
+// other or with the names already in the world state. Sources often report several obstacles with the same label, such as the
+// class of a detection, which is kept at the end of the name.
+func nameObservedObstacles(obstacles []*referenceframe.GeometriesInFrame) []*referenceframe.GeometriesInFrame {
+	named := make([]*referenceframe.GeometriesInFrame, 0, len(obstacles))
+	count := 0
+	for _, gif := range obstacles {
+		geometries := make([]spatialmath.Geometry, 0, len(gif.Geometries()))
+		for _, g := range gif.Geometries() {
+			name := fmt.Sprintf("observed_obstacle_%d", count)
+			if g.Label() != "" {
+				name += "_" + g.Label()
+			}
+			count++
+			g = g.Transform(spatialmath.NewZeroPose())
+			g.SetLabel(name)
+			geometries = append(geometries, g)
+		}
+		named = append(named, referenceframe.NewGeometriesInFrame(gif.Parent(), geometries))
+	}
+	return named
+}
+
+// pathCollides returns whether any of the geometries that move during the remaining steps of a plan collide with the obstacles.
+func (ms *builtIn) pathCollides(
+	ctx context.Context,
+	req moveRequest,
+	steps []map[string][]referenceframe.Input,
+	obstacles []*referenceframe.GeometriesInFrame,
+) (bool, error) {
+	if len(obstacles) == 0 {
+		return false, nil
+	}
+	frameSys, err := ms.fsService.FrameSystem(ctx, req.worldState.Transforms())
+	if err != nil {
+		return false, err
+	}
+	current, _, err := ms.fsService.AllCurrentInputs(ctx)
+	if err != nil {
+		return false, err
+	}
+	obstacleState, err := referenceframe.NewWorldState(obstacles, nil)
+	if err != nil {
+		return false, err
+	}
+	worldObstacles, err := obstacleState.ObstaclesInWorldFrame(frameSys, current)
+	if err != nil {
+		return false, err
+	}
+	before, err := referenceframe.FrameSystemGeometries(frameSys, current)
+	if err != nil {
+		return false, err
+	}
+
+	inputs := make(map[string][]referenceframe.Input, len(current))
+	for name, in := range current {
+		inputs[name] = in
+	}
+	for _, step := range steps {
+		for name, in := range step {
+			inputs[name] = in
+		}
+		after, err := referenceframe.FrameSystemGeometries(frameSys, inputs)
+		if err != nil {
+			return false, err
+		}
+		for name, geometries := range after {
+			// geometries that do not move cannot run into anything new
+			if unmoved(before[name], geometries) {
+				continue
+			}
+			for _, g := range geometries.Geometries() {
+				for _, obstacle := range worldObstacles.Geometries() {
+					collides, err := g.CollidesWith(obstacle)
+					if err != nil {
+						return false, err
+					}
+					if collides {
+						return true, nil
+					}
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+func unmoved(before, after *referenceframe.GeometriesInFrame) bool {
+	if before == nil || len(before.Geometries()) != len(after.Geometries()) {
+		return false
+	}
+	for i, g := range after.Geometries() {
+		if !spatialmath.PoseAlmostEqual(before.Geometries()[i].Pose(), g.Pose()) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// recordingInputs records the inputs it is sent.
+type recordingInputs struct {
+	sent [][]referenceframe.Input
+}
+
+func (r *recordingInputs) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
+	if len(r.sent) == 0 {
+		return nil, nil
+	}
+	return r.sent[len(r.sent)-1], nil
+}
+
+func (r *recordingInputs) GoToInputs(ctx context.Context, goal []referenceframe.Input) error {
+	r.sent = append(r.sent, goal)
+	return nil
+}
+
+func TestExecuteTimedStep(t *testing.T) {
+	component := &recordingInputs{}
+	resources := map[string]referenceframe.InputEnabled{"arm": component}
+	step := map[string][]referenceframe.Input{"arm": referenceframe.FloatsToInputs([]float64{1, 2})}
+
+	start := time.Now()
+	test.That(t, executeTimedStep(context.Background(), step, resources, 100*time.Millisecond), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 100*time.Millisecond)
+	test.That(t, component.sent, test.ShouldResemble, [][]referenceframe.Input{step["arm"]})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	test.That(t, executeTimedStep(ctx, step, resources, time.Second), test.ShouldBeError, context.Canceled)
+}
+
+func TestWithObstaclesDuplicateLabels(t *testing.T) {
+	newBox := func(x float64, label string) spatialmath.Geometry {
+		box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: x}), r3.Vector{X: 10, Y: 10, Z: 10}, label)
+		test.That(t, err, test.ShouldBeNil)
+		return box
+	}
+	// two detectors that both report people, and one obstacle without a label
+	obstacles := []*referenceframe.GeometriesInFrame{
+		referenceframe.NewGeometriesInFrame("cam1", []spatialmath.Geometry{newBox(0, "person"), newBox(100, "person")}),
+		referenceframe.NewGeometriesInFrame("cam2", []spatialmath.Geometry{newBox(0, "person"), newBox(200, "")}),
+	}
+
+	ms := &builtIn{}
+	worldState, err := ms.withObstacles(context.Background(), nil, obstacles)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, worldState.ObstacleNames(), test.ShouldHaveLength, 4)
+	for name := range worldState.ObstacleNames() {
+		test.That(t, name, test.ShouldContainSubstring, "observed_obstacle_")
+	}
+
+	// the geometries reported by the sources are left as they were
+	test.That(t, obstacles[0].Geometries()[0].Label(), test.ShouldEqual, "person")
+	test.That(t, obstacles[1].Geometries()[1].Label(), test.ShouldEqual, "")
+}
@@ -0,0 +1,60 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+)
+
+// ExecutePlan moves the components through each step of a plan made earlier, without planning again. Obstacles are not checked, so the
+// plan should only be replayed in the world it was made in.
+func (ms *builtIn) ExecutePlan(ctx context.Context, plan []map[string][]referenceframe.Input) (motion.PlanID, error) {
+	operation.CancelOtherWithLabel(ctx, "motion-service")
+
+	current, resources, err := ms.fsService.AllCurrentInputs(ctx)
+	if err != nil {
+		return "", err
+	}
+	for i, step := range plan {
+		for name, inputs := range step {
+			frameInputs, ok := current[name]
+			if !ok {
+				return "", errors.Errorf("step %d of the plan moves %q, which is not in the frame system", i, name)
+			}
+			if len(inputs) != len(frameInputs) {
+				return "", errors.Errorf("step %d of the plan has %d inputs for %q, which takes %d", i, len(inputs), name, len(frameInputs))
+			}
+			if len(inputs) > 0 && resources[name] == nil {
+				return "", errors.Errorf("step %d of the plan moves %q, which cannot be moved", i, name)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	id := ms.plans.add(motion.PlanRecord{Trajectory: plan}, resources, cancel)
+	return id, ms.plans.run(ctx, id, func(ctx context.Context, id motion.PlanID) error {
+		for i, step := range plan {
+			ms.plans.reached(id, i)
+			if err := executeStep(ctx, step, resources); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// decodePlan reads a plan given to DoCommand in the form written by motionplan.MarshalPlan.
+func decodePlan(args interface{}) ([]map[string][]referenceframe.Input, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	return motionplan.UnmarshalPlan(data)
+}
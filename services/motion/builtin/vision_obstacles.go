@@ -0,0 +1,62 @@
+package builtin
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// ObstacleDetectorConfig pairs a vision service with the camera it should segment obstacles from.
+type ObstacleDetectorConfig struct {
+	VisionService string `json:"vision_service"`
+	Camera        string `json:"camera"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (c *ObstacleDetectorConfig) Validate(path string) error {
+	if c.VisionService == "" {
+		return utils.NewConfigValidationFieldRequiredError(path, "vision_service")
+	}
+	if c.Camera == "" {
+		return utils.NewConfigValidationFieldRequiredError(path, "camera")
+	}
+	return nil
+}
+
+// visionObstacleSource reports the objects a vision service segments from a camera's point clouds as obstacles in the camera's
+// frame, which the frame system places in the world.
+type visionObstacleSource struct {
+	vision vision.Service
+	camera string
+}
+
+func newVisionObstacleSource(deps resource.Dependencies, cfg ObstacleDetectorConfig) (*visionObstacleSource, error) {
+	svc, err := resource.FromDependencies[vision.Service](deps, vision.Named(cfg.VisionService))
+	if err != nil {
+		return nil, err
+	}
+	return &visionObstacleSource{vision: svc, camera: cfg.Camera}, nil
+}
+
+func (s *visionObstacleSource) Obstacles(ctx context.Context) ([]*referenceframe.GeometriesInFrame, error) {
+	objects, err := s.vision.GetObjectPointClouds(ctx, s.camera, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get obstacles from vision service %q", s.vision.Name().ShortName())
+	}
+	geometries := make([]spatialmath.Geometry, 0, len(objects))
+	for _, object := range objects {
+		if object.Geometry != nil {
+			geometries = append(geometries, object.Geometry)
+		}
+	}
+	if len(geometries) == 0 {
+		return nil, nil
+	}
+	return []*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(s.camera, geometries)}, nil
+}
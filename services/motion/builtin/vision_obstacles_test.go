@@ -0,0 +1,51 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+	viz "go.viam.com/rdk/vision"
+)
+
+func TestVisionObstacleSource(t *testing.T) {
+	box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Z: 500}), r3.Vector{X: 100, Y: 100, Z: 100}, "")
+	test.That(t, err, test.ShouldBeNil)
+
+	injectVision := inject.NewVisionService("detector")
+	var gotCamera string
+	injectVision.GetObjectPointCloudsFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]*viz.Object, error) {
+		gotCamera = cameraName
+		return []*viz.Object{{Geometry: box}, viz.NewEmptyObject()}, nil
+	}
+	deps := resource.Dependencies{vision.Named("detector"): injectVision}
+
+	_, err = newVisionObstacleSource(deps, ObstacleDetectorConfig{VisionService: "missing", Camera: "cam"})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	source, err := newVisionObstacleSource(deps, ObstacleDetectorConfig{VisionService: "detector", Camera: "cam"})
+	test.That(t, err, test.ShouldBeNil)
+	obstacles, err := source.Obstacles(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gotCamera, test.ShouldEqual, "cam")
+	test.That(t, len(obstacles), test.ShouldEqual, 1)
+	test.That(t, obstacles[0].Parent(), test.ShouldEqual, "cam")
+	test.That(t, obstacles[0].Geometries(), test.ShouldResemble, []spatialmath.Geometry{box})
+}
+
+func TestValidateObstacleDetectors(t *testing.T) {
+	cfg := &Config{ObstacleDetectors: []ObstacleDetectorConfig{{VisionService: "detector"}}}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cfg.ObstacleDetectors[0].Camera = "cam"
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldContain, vision.Named("detector").String())
+}
@@ -5,7 +5,10 @@ import (
 	"context"
 
 	"github.com/edaniels/golog"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
 	pb "go.viam.com/api/service/motion/v1"
+	"go.viam.com/utils"
 	vprotoutils "go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
@@ -153,6 +156,136 @@ func (c *client) GetPose(
 	return referenceframe.ProtobufToPoseInFrame(resp.Pose), nil
 }
 
+// MoveOnGlobe is sent through DoCommand until the motion service API has an RPC for it.
+func (c *client) MoveOnGlobe(
+	ctx context.Context,
+	componentName resource.Name,
+	destination *geo.Point,
+	heading float64,
+	movementSensorName resource.Name,
+	obstacles []*GeoObstacle,
+	linearMmPerSec float64,
+	angularDegsPerSec float64,
+	extra map[string]interface{},
+) (bool, error) {
+	cmd, err := moveOnGlobeToDoCommand(
+		componentName, destination, heading, movementSensorName, obstacles, linearMmPerSec, angularDegsPerSec, extra,
+	)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.DoCommand(ctx, cmd)
+	if err != nil {
+		return false, err
+	}
+	success, _ := resp["success"].(bool)
+	return success, nil
+}
+
+// MoveToAny is sent through DoCommand until the motion service API has an RPC for it.
+func (c *client) MoveToAny(
+	ctx context.Context,
+	componentName resource.Name,
+	destinations []*referenceframe.PoseInFrame,
+	worldState *referenceframe.WorldState,
+	constraints *pb.Constraints,
+	extra map[string]interface{},
+) (int, error) {
+	cmd, err := moveToDoCommand(MoveToAnyCommand, componentName, destinations, worldState, constraints, extra)
+	if err != nil {
+		return -1, err
+	}
+	resp, err := c.DoCommand(ctx, cmd)
+	if err != nil {
+		return -1, err
+	}
+	index, ok := resp["destination_index"].(float64)
+	if !ok {
+		return -1, errors.New("move_to_any response has no destination index")
+	}
+	return int(index), nil
+}
+
+// MoveAsync is sent through DoCommand until the motion service API has an RPC for it.
+func (c *client) MoveAsync(
+	ctx context.Context,
+	componentName resource.Name,
+	destination *referenceframe.PoseInFrame,
+	worldState *referenceframe.WorldState,
+	constraints *pb.Constraints,
+	extra map[string]interface{},
+) (PlanID, error) {
+	cmd, err := moveToDoCommand(
+		MoveAsyncCommand, componentName, []*referenceframe.PoseInFrame{destination}, worldState, constraints, extra,
+	)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.DoCommand(ctx, cmd)
+	if err != nil {
+		return "", err
+	}
+	id, ok := resp["id"].(string)
+	if !ok {
+		return "", errors.New("move_async response has no plan id")
+	}
+	return PlanID(id), nil
+}
+
+// StopPlan is sent through DoCommand until the motion service API has an RPC for it.
+func (c *client) StopPlan(ctx context.Context, id PlanID) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{StopPlanCommand: string(id)})
+	return err
+}
+
+// PlanStatus is sent through DoCommand until the motion service API has an RPC for it.
+func (c *client) PlanStatus(ctx context.Context, id PlanID) (PlanStatus, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{GetPlanStatusCommand: string(id)})
+	if err != nil {
+		return PlanStatus{}, err
+	}
+	return planStatusFromMap(resp)
+}
+
+// PlanProgress polls the status of the plan, since the motion service API has no streaming RPC for it. The channel is also
+// closed if ctx is done or the status can no longer be read.
+func (c *client) PlanProgress(ctx context.Context, id PlanID) (<-chan PlanStatus, error) {
+	status, err := c.PlanStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	progress := make(chan PlanStatus, 1)
+	progress <- status
+	if status.State.Done() {
+		close(progress)
+		return progress, nil
+	}
+	utils.PanicCapturingGo(func() {
+		defer close(progress)
+		last := status
+		for utils.SelectContextOrWait(ctx, planProgressPollInterval) {
+			status, err := c.PlanStatus(ctx, id)
+			if err != nil {
+				c.logger.Debugw("stopped reporting progress of plan", "id", id, "error", err)
+				return
+			}
+			if status.State == last.State && status.Waypoint == last.Waypoint && status.TotalWaypoints == last.TotalWaypoints {
+				continue
+			}
+			select {
+			case progress <- status:
+			case <-ctx.Done():
+				return
+			}
+			if status.State.Done() {
+				return
+			}
+			last = status
+		}
+	})
+	return progress, nil
+}
+
 func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	return protoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
 }
@@ -0,0 +1,207 @@
+package motion
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// MoveOnGlobeCommand is the DoCommand key the motion service client uses to call
+// MoveOnGlobe on a remote motion service, since it has no RPC of its own yet.
+const MoveOnGlobeCommand = "move_on_globe"
+
+// A GlobeMover is a motion service that can drive a base to a point on the globe.
+type GlobeMover interface {
+	// MoveOnGlobe moves a base to a destination on the globe, using the movement sensor to locate it.
+	// The heading is the compass heading, in degrees, the base should end up facing; pass NaN if it does not matter.
+	// Obstacles are avoided and a velocity of zero means the default is used.
+	MoveOnGlobe(
+		ctx context.Context,
+		componentName resource.Name,
+		destination *geo.Point,
+		heading float64,
+		movementSensorName resource.Name,
+		obstacles []*GeoObstacle,
+		linearMmPerSec float64,
+		angularDegsPerSec float64,
+		extra map[string]interface{},
+	) (bool, error)
+}
+
+// A GeoObstacle is a set of geometries located at a point on the globe. The
+// geometries are in a frame centered on the location with +X pointing east,
+// +Y pointing north and units of millimeters.
+type GeoObstacle struct {
+	location   *geo.Point
+	geometries []spatialmath.Geometry
+}
+
+// NewGeoObstacle constructs a GeoObstacle from a location and the geometries around it.
+func NewGeoObstacle(location *geo.Point, geometries []spatialmath.Geometry) *GeoObstacle {
+	return &GeoObstacle{location: location, geometries: geometries}
+}
+
+// Location returns the location of the obstacle.
+func (o *GeoObstacle) Location() *geo.Point {
+	return o.location
+}
+
+// Geometries returns the geometries of the obstacle.
+func (o *GeoObstacle) Geometries() []spatialmath.Geometry {
+	return o.geometries
+}
+
+// GeoPointToLocal converts a point on the globe into a local frame centered on the
+// origin, with +X pointing east, +Y pointing north and units of millimeters. The
+// conversion treats the globe as flat around the origin, which is accurate for
+// the distances a rover travels in a single move.
+func GeoPointToLocal(origin, p *geo.Point) r3.Vector {
+	distanceMm := origin.GreatCircleDistance(p) * 1e6
+	bearing := origin.BearingTo(p) * math.Pi / 180
+	return r3.Vector{X: distanceMm * math.Sin(bearing), Y: distanceMm * math.Cos(bearing)}
+}
+
+// GeoObstaclesToWorldState converts obstacles into a WorldState in the local frame
+// centered on the origin, as described by GeoPointToLocal.
+func GeoObstaclesToWorldState(origin *geo.Point, obstacles []*GeoObstacle) (*referenceframe.WorldState, error) {
+	var geometries []spatialmath.Geometry
+	for _, obstacle := range obstacles {
+		offset := spatialmath.NewPoseFromPoint(GeoPointToLocal(origin, obstacle.Location()))
+		for _, g := range obstacle.Geometries() {
+			geometries = append(geometries, g.Transform(offset))
+		}
+	}
+	if len(geometries) == 0 {
+		return nil, nil
+	}
+	return referenceframe.NewWorldState(
+		[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, geometries)},
+		nil,
+	)
+}
+
+type geoObstacleConfig struct {
+	Latitude   float64                       `json:"latitude"`
+	Longitude  float64                       `json:"longitude"`
+	Geometries []*spatialmath.GeometryConfig `json:"geometries"`
+}
+
+// moveOnGlobeRequest is how MoveOnGlobe arguments are sent through DoCommand.
+type moveOnGlobeRequest struct {
+	ComponentName      string                 `json:"component_name"`
+	Latitude           float64                `json:"latitude"`
+	Longitude          float64                `json:"longitude"`
+	Heading            *float64               `json:"heading,omitempty"`
+	MovementSensorName string                 `json:"movement_sensor_name"`
+	Obstacles          []geoObstacleConfig    `json:"obstacles,omitempty"`
+	LinearMmPerSec     float64                `json:"linear_mm_per_sec,omitempty"`
+	AngularDegsPerSec  float64                `json:"angular_degs_per_sec,omitempty"`
+	Extra              map[string]interface{} `json:"extra,omitempty"`
+}
+
+func moveOnGlobeToDoCommand(
+	componentName resource.Name,
+	destination *geo.Point,
+	heading float64,
+	movementSensorName resource.Name,
+	obstacles []*GeoObstacle,
+	linearMmPerSec float64,
+	angularDegsPerSec float64,
+	extra map[string]interface{},
+) (map[string]interface{}, error) {
+	req := moveOnGlobeRequest{
+		ComponentName:      componentName.String(),
+		Latitude:           destination.Lat(),
+		Longitude:          destination.Lng(),
+		MovementSensorName: movementSensorName.String(),
+		LinearMmPerSec:     linearMmPerSec,
+		AngularDegsPerSec:  angularDegsPerSec,
+		Extra:              extra,
+	}
+	// JSON has no NaN, so an unspecified heading is left out.
+	if !math.IsNaN(heading) {
+		req.Heading = &heading
+	}
+	for _, obstacle := range obstacles {
+		cfg := geoObstacleConfig{Latitude: obstacle.Location().Lat(), Longitude: obstacle.Location().Lng()}
+		for _, g := range obstacle.Geometries() {
+			gc, err := spatialmath.NewGeometryConfig(g)
+			if err != nil {
+				return nil, err
+			}
+			cfg.Geometries = append(cfg.Geometries, gc)
+		}
+		req.Obstacles = append(req.Obstacles, cfg)
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var args map[string]interface{}
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{MoveOnGlobeCommand: args}, nil
+}
+
+// DoMoveOnGlobeCommand calls MoveOnGlobe on the service with the arguments of a
+// "move_on_globe" DoCommand, as sent by the motion service client.
+func DoMoveOnGlobeCommand(ctx context.Context, svc GlobeMover, args interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	var req moveOnGlobeRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s arguments", MoveOnGlobeCommand)
+	}
+	componentName, err := resource.NewFromString(req.ComponentName)
+	if err != nil {
+		return nil, err
+	}
+	movementSensorName, err := resource.NewFromString(req.MovementSensorName)
+	if err != nil {
+		return nil, err
+	}
+	heading := math.NaN()
+	if req.Heading != nil {
+		heading = *req.Heading
+	}
+	obstacles := make([]*GeoObstacle, 0, len(req.Obstacles))
+	for _, cfg := range req.Obstacles {
+		geometries := make([]spatialmath.Geometry, 0, len(cfg.Geometries))
+		for _, gc := range cfg.Geometries {
+			g, err := gc.ParseConfig()
+			if err != nil {
+				return nil, err
+			}
+			geometries = append(geometries, g)
+		}
+		obstacles = append(obstacles, NewGeoObstacle(geo.NewPoint(cfg.Latitude, cfg.Longitude), geometries))
+	}
+
+	success, err := svc.MoveOnGlobe(
+		ctx,
+		componentName,
+		geo.NewPoint(req.Latitude, req.Longitude),
+		heading,
+		movementSensorName,
+		obstacles,
+		req.LinearMmPerSec,
+		req.AngularDegsPerSec,
+		req.Extra,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"success": success}, nil
+}
@@ -0,0 +1,120 @@
+package motion_test
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestGeoPointToLocal(t *testing.T) {
+	origin := geo.NewPoint(40.7, -73.98)
+
+	north := motion.GeoPointToLocal(origin, origin.PointAtDistanceAndBearing(0.1, 0))
+	test.That(t, north.X, test.ShouldAlmostEqual, 0, 1e-3)
+	test.That(t, north.Y, test.ShouldAlmostEqual, 1e5, 1)
+
+	east := motion.GeoPointToLocal(origin, origin.PointAtDistanceAndBearing(0.05, 90))
+	test.That(t, east.X, test.ShouldAlmostEqual, 5e4, 1)
+	test.That(t, east.Y, test.ShouldAlmostEqual, 0, 1)
+
+	sphere, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), 10, "rock")
+	test.That(t, err, test.ShouldBeNil)
+	obstacle := motion.NewGeoObstacle(origin.PointAtDistanceAndBearing(0.05, 90), []spatialmath.Geometry{sphere})
+	worldState, err := motion.GeoObstaclesToWorldState(origin, []*motion.GeoObstacle{obstacle})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, worldState, test.ShouldNotBeNil)
+
+	worldState, err = motion.GeoObstaclesToWorldState(origin, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, worldState, test.ShouldBeNil)
+}
+
+func TestClientMoveOnGlobe(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	injectMS := &inject.MotionService{}
+	svc, err := resource.NewAPIResourceCollection(motion.API, map[resource.Name]motion.Service{testMotionServiceName: injectMS})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[motion.Service](motion.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, svc), test.ShouldBeNil)
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	var (
+		gotDestination *geo.Point
+		gotHeading     float64
+		gotObstacles   []*motion.GeoObstacle
+		gotSensor      resource.Name
+	)
+	injectMS.DoCommandFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		return motion.DoMoveOnGlobeCommand(ctx, injectMS, cmd[motion.MoveOnGlobeCommand])
+	}
+	injectMS.MoveOnGlobeFunc = func(
+		ctx context.Context,
+		componentName resource.Name,
+		destination *geo.Point,
+		heading float64,
+		movementSensorName resource.Name,
+		obstacles []*motion.GeoObstacle,
+		linearMmPerSec float64,
+		angularDegsPerSec float64,
+		extra map[string]interface{},
+	) (bool, error) {
+		gotDestination = destination
+		gotHeading = heading
+		gotObstacles = obstacles
+		gotSensor = movementSensorName
+		return true, nil
+	}
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	svcClient, err := motion.NewClientFromConn(context.Background(), conn, "", testMotionServiceName, logger)
+	test.That(t, err, test.ShouldBeNil)
+	client, ok := svcClient.(motion.GlobeMover)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 1}), r3.Vector{X: 2, Y: 2, Z: 2}, "box")
+	test.That(t, err, test.ShouldBeNil)
+	obstacles := []*motion.GeoObstacle{motion.NewGeoObstacle(geo.NewPoint(1, 2), []spatialmath.Geometry{box})}
+
+	success, err := client.MoveOnGlobe(
+		context.Background(), base.Named("rover"), geo.NewPoint(3, 4), math.NaN(), movementsensor.Named("gps"), obstacles, 0, 0, nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, success, test.ShouldBeTrue)
+	test.That(t, gotDestination, test.ShouldResemble, geo.NewPoint(3, 4))
+	test.That(t, math.IsNaN(gotHeading), test.ShouldBeTrue)
+	test.That(t, gotSensor, test.ShouldResemble, movementsensor.Named("gps"))
+	test.That(t, len(gotObstacles), test.ShouldEqual, 1)
+	test.That(t, gotObstacles[0].Location(), test.ShouldResemble, geo.NewPoint(1, 2))
+	test.That(t, gotObstacles[0].Geometries()[0].AlmostEqual(box), test.ShouldBeTrue)
+
+	_, err = client.MoveOnGlobe(
+		context.Background(), base.Named("rover"), geo.NewPoint(3, 4), 90, movementsensor.Named("gps"), nil, 0, 0, nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gotHeading, test.ShouldEqual, 90.0)
+}
@@ -0,0 +1,153 @@
+package motion
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	servicepb "go.viam.com/api/service/motion/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+)
+
+// MoveToAnyCommand is the DoCommand key the motion service client uses to call
+// MoveToAny. It returns the index of the destination that was reached as
+// "destination_index".
+const MoveToAnyCommand = "move_to_any"
+
+// A MultiGoalMover is a motion service that can move a component to whichever
+// of several destinations it can best reach, such as the candidate poses of a
+// grasp.
+type MultiGoalMover interface {
+	// MoveToAny plans to every destination concurrently and moves the component
+	// to the one it can reach with the least joint motion, blocking until done.
+	// It returns the index of the destination that was reached.
+	MoveToAny(
+		ctx context.Context,
+		componentName resource.Name,
+		destinations []*referenceframe.PoseInFrame,
+		worldState *referenceframe.WorldState,
+		constraints *servicepb.Constraints,
+		extra map[string]interface{},
+	) (int, error)
+}
+
+// moveCommandRequest is how the arguments of MoveToAny and MoveAsync are sent
+// through DoCommand. The destinations, world state and constraints are their API
+// messages in JSON form.
+type moveCommandRequest struct {
+	ComponentName string                 `json:"component_name"`
+	Destinations  []json.RawMessage      `json:"destinations"`
+	WorldState    json.RawMessage        `json:"world_state,omitempty"`
+	Constraints   json.RawMessage        `json:"constraints,omitempty"`
+	Extra         map[string]interface{} `json:"extra,omitempty"`
+}
+
+// moveCommandArgs are the decoded arguments of a moveCommandRequest.
+type moveCommandArgs struct {
+	componentName resource.Name
+	destinations  []*referenceframe.PoseInFrame
+	worldState    *referenceframe.WorldState
+	constraints   *servicepb.Constraints
+	extra         map[string]interface{}
+}
+
+// moveToDoCommand returns the DoCommand under the given key that sends the arguments of a move.
+func moveToDoCommand(
+	key string,
+	componentName resource.Name,
+	destinations []*referenceframe.PoseInFrame,
+	worldState *referenceframe.WorldState,
+	constraints *servicepb.Constraints,
+	extra map[string]interface{},
+) (map[string]interface{}, error) {
+	req := moveCommandRequest{ComponentName: componentName.String(), Extra: extra}
+	for _, destination := range destinations {
+		data, err := protojson.Marshal(referenceframe.PoseInFrameToProtobuf(destination))
+		if err != nil {
+			return nil, err
+		}
+		req.Destinations = append(req.Destinations, data)
+	}
+	if worldState != nil {
+		worldStatePb, err := worldState.ToProtobuf()
+		if err != nil {
+			return nil, err
+		}
+		if req.WorldState, err = protojson.Marshal(worldStatePb); err != nil {
+			return nil, err
+		}
+	}
+	if constraints != nil {
+		var err error
+		if req.Constraints, err = protojson.Marshal(constraints); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var args map[string]interface{}
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{key: args}, nil
+}
+
+// decodeMoveCommand reads the arguments of a move sent by moveToDoCommand under the given key.
+func decodeMoveCommand(key string, args interface{}) (moveCommandArgs, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return moveCommandArgs{}, err
+	}
+	var req moveCommandRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return moveCommandArgs{}, errors.Wrapf(err, "invalid %s arguments", key)
+	}
+	decoded := moveCommandArgs{extra: req.Extra}
+	if decoded.componentName, err = resource.NewFromString(req.ComponentName); err != nil {
+		return moveCommandArgs{}, err
+	}
+	for _, data := range req.Destinations {
+		var destination commonpb.PoseInFrame
+		if err := protojson.Unmarshal(data, &destination); err != nil {
+			return moveCommandArgs{}, errors.Wrapf(err, "invalid %s destination", key)
+		}
+		decoded.destinations = append(decoded.destinations, referenceframe.ProtobufToPoseInFrame(&destination))
+	}
+	if len(req.WorldState) != 0 {
+		var worldStatePb commonpb.WorldState
+		if err := protojson.Unmarshal(req.WorldState, &worldStatePb); err != nil {
+			return moveCommandArgs{}, errors.Wrapf(err, "invalid %s world state", key)
+		}
+		if decoded.worldState, err = referenceframe.WorldStateFromProtobuf(&worldStatePb); err != nil {
+			return moveCommandArgs{}, err
+		}
+	}
+	if len(req.Constraints) != 0 {
+		decoded.constraints = &servicepb.Constraints{}
+		if err := protojson.Unmarshal(req.Constraints, decoded.constraints); err != nil {
+			return moveCommandArgs{}, errors.Wrapf(err, "invalid %s constraints", key)
+		}
+	}
+	return decoded, nil
+}
+
+// DoMoveToAnyCommand calls MoveToAny on the service with the arguments of a
+// "move_to_any" DoCommand, as sent by the motion service client.
+func DoMoveToAnyCommand(ctx context.Context, svc MultiGoalMover, args interface{}) (map[string]interface{}, error) {
+	req, err := decodeMoveCommand(MoveToAnyCommand, args)
+	if err != nil {
+		return nil, err
+	}
+	index, err := svc.MoveToAny(ctx, req.componentName, req.destinations, req.worldState, req.constraints, req.extra)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"destination_index": index}, nil
+}
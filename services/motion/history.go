@@ -0,0 +1,130 @@
+package motion
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	servicepb "go.viam.com/api/service/motion/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+)
+
+// The DoCommand keys used to inspect the plans a remote motion service has made.
+// ListPlansCommand takes no argument and GetPlanCommand takes the plan ID.
+const (
+	ListPlansCommand = "list_plans"
+	GetPlanCommand   = "get_plan"
+)
+
+// A PlanRecord describes a plan the motion service made and how executing it
+// went, so that an unexpected path can be understood after the fact.
+type PlanRecord struct {
+	// ComponentName and Destination are unset for plans that were replayed
+	// rather than planned.
+	ComponentName resource.Name
+	Destination   *referenceframe.PoseInFrame
+	// WorldState is the world state the plan was last made in, including any
+	// obstacles that were observed while planning.
+	WorldState  *referenceframe.WorldState
+	Constraints *servicepb.Constraints
+	Extra       map[string]interface{}
+	// Trajectory holds the inputs of the moving components at each step of the
+	// plan, as it was last made.
+	Trajectory []map[string][]referenceframe.Input
+	// StepTimes holds when each step of the trajectory is scheduled to be
+	// reached, measured from when executing the trajectory began, so that it
+	// can be streamed to components that follow timed trajectories. It is
+	// unset unless the request limited the speed along the path.
+	StepTimes []time.Duration
+	// Replans counts how many times the plan was made again while executing.
+	Replans   int
+	StartTime time.Time
+	// EndTime is zero while the plan is still executing.
+	EndTime time.Time
+	Status  PlanStatus
+}
+
+// Summary converts the record into the short form listed by DoCommand, which
+// leaves out the world state and trajectory.
+func (r PlanRecord) Summary() map[string]interface{} {
+	out := r.Status.ToMap()
+	if r.ComponentName.Name != "" {
+		out["component_name"] = r.ComponentName.String()
+	}
+	out["replans"] = r.Replans
+	out["start_time"] = r.StartTime.Format(time.RFC3339Nano)
+	if !r.EndTime.IsZero() {
+		out["end_time"] = r.EndTime.Format(time.RFC3339Nano)
+	}
+	return out
+}
+
+// ToMap converts the full record into the form returned by DoCommand.
+func (r PlanRecord) ToMap() (map[string]interface{}, error) {
+	out := r.Summary()
+	var err error
+	if r.Destination != nil {
+		if out["destination"], err = protoToMap(referenceframe.PoseInFrameToProtobuf(r.Destination)); err != nil {
+			return nil, err
+		}
+	}
+	if r.WorldState != nil {
+		worldState, err := r.WorldState.ToProtobuf()
+		if err != nil {
+			return nil, err
+		}
+		if out["world_state"], err = protoToMap(worldState); err != nil {
+			return nil, err
+		}
+	}
+	if r.Constraints != nil {
+		if out["constraints"], err = protoToMap(r.Constraints); err != nil {
+			return nil, err
+		}
+	}
+	if r.Extra != nil {
+		out["extra"] = r.Extra
+	}
+	trajectory := make([]interface{}, 0, len(r.Trajectory))
+	for _, step := range r.Trajectory {
+		inputs := make(map[string]interface{}, len(step))
+		for name, in := range step {
+			inputs[name] = referenceframe.InputsToFloats(in)
+		}
+		trajectory = append(trajectory, inputs)
+	}
+	out["trajectory"] = trajectory
+	if r.StepTimes != nil {
+		stepTimes := make([]interface{}, 0, len(r.StepTimes))
+		for _, t := range r.StepTimes {
+			stepTimes = append(stepTimes, t.Seconds())
+		}
+		out["step_times_sec"] = stepTimes
+	}
+	return out, nil
+}
+
+func protoToMap(msg proto.Message) (map[string]interface{}, error) {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// A PlanHistory is a motion service that remembers the plans it has recently
+// made, whether they were executed with Move or MoveAsync.
+type PlanHistory interface {
+	// ListPlans returns the recent plans, newest first.
+	ListPlans(ctx context.Context) ([]PlanRecord, error)
+	// GetPlan returns the record of the given plan.
+	GetPlan(ctx context.Context, id PlanID) (PlanRecord, error)
+}
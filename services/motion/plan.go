@@ -0,0 +1,193 @@
+package motion
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	servicepb "go.viam.com/api/service/motion/v1"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+)
+
+// The DoCommand keys the motion service client uses to manage plans on a remote
+// motion service. MoveAsyncCommand takes the arguments of MoveAsync and returns
+// the ID of the plan as "id"; the others take the plan ID as their argument.
+const (
+	MoveAsyncCommand     = "move_async"
+	StopPlanCommand      = "stop_plan"
+	GetPlanStatusCommand = "get_plan_status"
+)
+
+// How often the motion service client asks a remote motion service for the status of a plan it reports the progress of.
+const planProgressPollInterval = 100 * time.Millisecond
+
+// A PlanID identifies a plan started with MoveAsync.
+type PlanID string
+
+// PlanState describes where a plan is in its execution.
+type PlanState int
+
+// The states a plan can be in.
+const (
+	PlanStateInProgress PlanState = iota
+	PlanStateSucceeded
+	PlanStateStopped
+	PlanStateFailed
+)
+
+func (s PlanState) String() string {
+	switch s {
+	case PlanStateInProgress:
+		return "in_progress"
+	case PlanStateSucceeded:
+		return "succeeded"
+	case PlanStateStopped:
+		return "stopped"
+	case PlanStateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Done returns whether the plan has stopped executing.
+func (s PlanState) Done() bool {
+	return s != PlanStateInProgress
+}
+
+// PlanStatus describes the progress of a plan.
+type PlanStatus struct {
+	ID    PlanID
+	State PlanState
+	// Waypoint is the index of the waypoint currently being moved to, or the
+	// number of waypoints once the plan has succeeded.
+	Waypoint       int
+	TotalWaypoints int
+	// Err is set if the plan failed.
+	Err error
+}
+
+// PercentComplete returns how much of the plan has been executed, by waypoint.
+func (s PlanStatus) PercentComplete() float64 {
+	if s.TotalWaypoints == 0 {
+		if s.State == PlanStateSucceeded {
+			return 100
+		}
+		return 0
+	}
+	return 100 * float64(s.Waypoint) / float64(s.TotalWaypoints)
+}
+
+// ToMap converts the status into the form returned by DoCommand.
+func (s PlanStatus) ToMap() map[string]interface{} {
+	out := map[string]interface{}{
+		"id":               string(s.ID),
+		"state":            s.State.String(),
+		"waypoint":         s.Waypoint,
+		"total_waypoints":  s.TotalWaypoints,
+		"percent_complete": s.PercentComplete(),
+	}
+	if s.Err != nil {
+		out["error"] = s.Err.Error()
+	}
+	return out
+}
+
+// planStatusFromMap reads a status in the form written by ToMap.
+func planStatusFromMap(m map[string]interface{}) (PlanStatus, error) {
+	id, ok := m["id"].(string)
+	if !ok {
+		return PlanStatus{}, errors.New("plan status has no id")
+	}
+	status := PlanStatus{ID: PlanID(id), State: -1}
+	state, _ := m["state"].(string)
+	for s := PlanStateInProgress; s <= PlanStateFailed; s++ {
+		if s.String() == state {
+			status.State = s
+		}
+	}
+	if status.State < 0 {
+		return PlanStatus{}, errors.Errorf("unknown plan state %q", state)
+	}
+	waypoint, _ := m["waypoint"].(float64)
+	total, _ := m["total_waypoints"].(float64)
+	status.Waypoint, status.TotalWaypoints = int(waypoint), int(total)
+	if msg, ok := m["error"].(string); ok {
+		status.Err = errors.New(msg)
+	}
+	return status, nil
+}
+
+// A PlanExecutor is a motion service that can execute plans in the background,
+// rather than blocking until the move completes.
+type PlanExecutor interface {
+	// MoveAsync plans a move like Move does, returning any planning error, and
+	// then executes the plan in the background. Starting a plan stops any other
+	// plan that is still in progress.
+	MoveAsync(
+		ctx context.Context,
+		componentName resource.Name,
+		destination *referenceframe.PoseInFrame,
+		worldState *referenceframe.WorldState,
+		constraints *servicepb.Constraints,
+		extra map[string]interface{},
+	) (PlanID, error)
+	// StopPlan stops executing the given plan. Stopping a finished plan does nothing.
+	StopPlan(ctx context.Context, id PlanID) error
+	// PlanStatus returns the current status of the given plan.
+	PlanStatus(ctx context.Context, id PlanID) (PlanStatus, error)
+	// PlanProgress returns a channel that receives the plan's status every time
+	// it changes and is closed once the plan is done.
+	PlanProgress(ctx context.Context, id PlanID) (<-chan PlanStatus, error)
+}
+
+// DoMoveAsyncCommand calls MoveAsync on the service with the arguments of a
+// "move_async" DoCommand, as sent by the motion service client.
+func DoMoveAsyncCommand(ctx context.Context, svc PlanExecutor, args interface{}) (map[string]interface{}, error) {
+	req, err := decodeMoveCommand(MoveAsyncCommand, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.destinations) != 1 {
+		return nil, errors.Errorf("%s needs exactly one destination", MoveAsyncCommand)
+	}
+	id, err := svc.MoveAsync(ctx, req.componentName, req.destinations[0], req.worldState, req.constraints, req.extra)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"id": string(id)}, nil
+}
+
+// ExecutePlanCommand is the DoCommand key used to execute a plan on a remote
+// motion service without planning again. Its argument is the plan in the form
+// written by motionplan.MarshalPlan.
+const ExecutePlanCommand = "execute_plan"
+
+// A PlanReplayer is a motion service that can execute a plan made earlier,
+// such as one saved with motionplan.MarshalPlan, without planning again.
+type PlanReplayer interface {
+	// ExecutePlan moves the components through each step of the plan, blocking
+	// until done. The plan is recorded in the plan history under the returned ID.
+	ExecutePlan(ctx context.Context, plan []map[string][]referenceframe.Input) (PlanID, error)
+}
+
+// An ObstacleSource reports obstacles the motion service should avoid, such as those seen by a camera. Obstacles may be given in
+// any frame of the robot's frame system.
+type ObstacleSource interface {
+	Obstacles(ctx context.Context) ([]*referenceframe.GeometriesInFrame, error)
+}
+
+// A ReplanPolicy decides when the motion service plans again while executing a plan.
+type ReplanPolicy string
+
+// The supported replan policies.
+const (
+	// ReplanNever executes a plan as it was made.
+	ReplanNever ReplanPolicy = "never"
+	// ReplanOnObstacle plans again whenever an obstacle source reports an obstacle in the way of the rest of the plan.
+	ReplanOnObstacle ReplanPolicy = "on_obstacle"
+	// ReplanPeriodic plans again at a fixed period, taking every obstacle source into account.
+	ReplanPeriodic ReplanPolicy = "periodic"
+)
@@ -0,0 +1,121 @@
+package motion_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	servicepb "go.viam.com/api/service/motion/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/components/gripper"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// planExecutor moves through its waypoints every time its status is read.
+type planExecutor struct {
+	destination *referenceframe.PoseInFrame
+	status      motion.PlanStatus
+}
+
+func (e *planExecutor) MoveAsync(
+	ctx context.Context,
+	componentName resource.Name,
+	destination *referenceframe.PoseInFrame,
+	worldState *referenceframe.WorldState,
+	constraints *servicepb.Constraints,
+	extra map[string]interface{},
+) (motion.PlanID, error) {
+	e.destination = destination
+	e.status = motion.PlanStatus{ID: "plan", State: motion.PlanStateInProgress, TotalWaypoints: 3}
+	return e.status.ID, nil
+}
+
+func (e *planExecutor) StopPlan(ctx context.Context, id motion.PlanID) error {
+	e.status.State = motion.PlanStateStopped
+	return nil
+}
+
+func (e *planExecutor) PlanStatus(ctx context.Context, id motion.PlanID) (motion.PlanStatus, error) {
+	status := e.status
+	if e.status.State == motion.PlanStateInProgress {
+		e.status.Waypoint++
+		if e.status.Waypoint == e.status.TotalWaypoints {
+			e.status.State = motion.PlanStateSucceeded
+		}
+	}
+	return status, nil
+}
+
+func (e *planExecutor) PlanProgress(ctx context.Context, id motion.PlanID) (<-chan motion.PlanStatus, error) {
+	return nil, nil
+}
+
+func TestClientPlans(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	injectMS := &inject.MotionService{}
+	svc, err := resource.NewAPIResourceCollection(motion.API, map[resource.Name]motion.Service{testMotionServiceName: injectMS})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[motion.Service](motion.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, svc), test.ShouldBeNil)
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	executor := &planExecutor{}
+	injectMS.DoCommandFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		if args, ok := cmd[motion.MoveAsyncCommand]; ok {
+			return motion.DoMoveAsyncCommand(ctx, executor, args)
+		}
+		if id, ok := cmd[motion.StopPlanCommand].(string); ok {
+			return map[string]interface{}{}, executor.StopPlan(ctx, motion.PlanID(id))
+		}
+		status, err := executor.PlanStatus(ctx, motion.PlanID(cmd[motion.GetPlanStatusCommand].(string)))
+		return status.ToMap(), err
+	}
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	client, err := motion.NewClientFromConn(context.Background(), conn, "", testMotionServiceName, logger)
+	test.That(t, err, test.ShouldBeNil)
+	plans, ok := client.(motion.PlanExecutor)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	destination := referenceframe.NewPoseInFrame("world", spatialmath.NewPoseFromPoint(r3.Vector{X: 100}))
+	id, err := plans.MoveAsync(context.Background(), gripper.Named("pieceGripper"), destination, nil, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, id, test.ShouldEqual, motion.PlanID("plan"))
+	test.That(t, spatialmath.PoseAlmostEqual(executor.destination.Pose(), destination.Pose()), test.ShouldBeTrue)
+
+	progress, err := plans.PlanProgress(context.Background(), id)
+	test.That(t, err, test.ShouldBeNil)
+	var waypoints []int
+	var last motion.PlanStatus
+	for status := range progress {
+		waypoints = append(waypoints, status.Waypoint)
+		last = status
+	}
+	test.That(t, waypoints, test.ShouldResemble, []int{0, 1, 2, 3})
+	test.That(t, last.State, test.ShouldEqual, motion.PlanStateSucceeded)
+	test.That(t, last.PercentComplete(), test.ShouldEqual, 100)
+
+	test.That(t, plans.StopPlan(context.Background(), id), test.ShouldBeNil)
+	status, err := plans.PlanStatus(context.Background(), id)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status.State, test.ShouldEqual, motion.PlanStateStopped)
+}
@@ -9,20 +9,29 @@ import (
 	"time"
 
 	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
 	geo "github.com/kellydunn/golang-geo"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.viam.com/utils"
 
 	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/components/sensor"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
 	"go.viam.com/rdk/services/navigation"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
 )
 
 const (
 	mmPerSecDefault  = 500
 	degPerSecDefault = 45
+
+	costmapResolutionMmDefault = 50
 )
 
 func init() {
@@ -45,6 +54,54 @@ type Config struct {
 	MovementSensorName string                 `json:"movement_sensor"`
 	DegPerSecDefault   float64                `json:"degs_per_sec"`
 	MMPerSecDefault    float64                `json:"mm_per_sec"`
+	Costmap            *CostmapConfig         `json:"costmap,omitempty"`
+	Zones              []navigation.Zone      `json:"zones,omitempty"`
+	Dock               *DockConfig            `json:"dock,omitempty"`
+}
+
+// CostmapConfig describes how to plan paths to waypoints over a costmap of the map built by a SLAM service, instead
+// of driving straight at them. The SLAM map's origin is placed at the given latitude and longitude, with +X pointing
+// east and +Y pointing north.
+type CostmapConfig struct {
+	SLAMName           string                    `json:"slam"`
+	MapOriginLatitude  float64                   `json:"map_origin_latitude"`
+	MapOriginLongitude float64                   `json:"map_origin_longitude"`
+	ResolutionMm       float64                   `json:"resolution_mm"`
+	RobotRadiusMm      float64                   `json:"robot_radius_mm"`
+	InflationRadiusMm  float64                   `json:"inflation_radius_mm"`
+	ObstacleDetectors  []*ObstacleDetectorConfig `json:"obstacle_detectors"`
+}
+
+// ObstacleDetectorConfig names a vision service whose detections in the images of a camera are added to the costmap
+// as obstacles. The camera is assumed to be mounted where the SLAM service tracks the robot's pose.
+type ObstacleDetectorConfig struct {
+	VisionServiceName string `json:"vision_service"`
+	CameraName        string `json:"camera"`
+}
+
+// Validate ensures all parts of the config are valid and returns the services it depends on.
+func (conf *CostmapConfig) Validate(path string) ([]string, error) {
+	if conf.SLAMName == "" {
+		return nil, utils.NewConfigValidationFieldRequiredError(path, "slam")
+	}
+	if conf.RobotRadiusMm <= 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("robot_radius_mm must be positive"))
+	}
+	if conf.ResolutionMm < 0 || conf.InflationRadiusMm < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("resolution_mm and inflation_radius_mm cannot be negative"))
+	}
+	deps := []string{conf.SLAMName}
+	for i, detector := range conf.ObstacleDetectors {
+		detectorPath := fmt.Sprintf("%s.obstacle_detectors.%d", path, i)
+		if detector.VisionServiceName == "" {
+			return nil, utils.NewConfigValidationFieldRequiredError(detectorPath, "vision_service")
+		}
+		if detector.CameraName == "" {
+			return nil, utils.NewConfigValidationFieldRequiredError(detectorPath, "camera")
+		}
+		deps = append(deps, detector.VisionServiceName)
+	}
+	return deps, nil
 }
 
 // Validate creates the list of implicit dependencies.
@@ -61,6 +118,37 @@ func (conf *Config) Validate(path string) ([]string, error) {
 	}
 	deps = append(deps, conf.MovementSensorName)
 
+	if conf.Costmap != nil {
+		costmapDeps, err := conf.Costmap.Validate(path + ".costmap")
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, costmapDeps...)
+	}
+
+	if conf.Dock != nil {
+		dockDeps, err := conf.Dock.Validate(path + ".dock")
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, dockDeps...)
+	}
+
+	names := map[string]bool{}
+	for i, zone := range conf.Zones {
+		zonePath := fmt.Sprintf("%s.zones.%d", path, i)
+		if err := zone.Validate(zonePath); err != nil {
+			return nil, err
+		}
+		if names[zone.Name] {
+			return nil, utils.NewConfigValidationError(zonePath, errors.Errorf("duplicate zone name %q", zone.Name))
+		}
+		names[zone.Name] = true
+		if len(zone.Polygon) > 0 && conf.Costmap == nil {
+			return nil, utils.NewConfigValidationError(zonePath, errors.New("zones in the map frame need a costmap to place the map"))
+		}
+	}
+
 	return deps, nil
 }
 
@@ -89,6 +177,28 @@ type builtIn struct {
 	base           base.Base
 	movementSensor movementsensor.MovementSensor
 
+	costmapConfig  *CostmapConfig
+	slam           slam.Service
+	visionServices []vision.Service
+	mapOrigin      *geo.Point
+
+	// zones has its own lock since it is read while navigating, which SetMode waits on with mu held
+	zonesMu sync.Mutex
+	zones   []navigation.Zone
+
+	// mission is the mission being run instead of the store's waypoints, if any. It has its own lock for the same
+	// reason as zones.
+	missionMu sync.Mutex
+	mission   *navigation.Mission
+	progress  *navigation.MissionProgress
+
+	dockConfig   *DockConfig
+	dockCamera   camera.Camera
+	dockVision   vision.Service
+	chargeSensor sensor.Sensor
+	dockMu       sync.Mutex
+	dockStatus   navigation.DockStatus
+
 	mmPerSecDefault         float64
 	degPerSecDefault        float64
 	logger                  golog.Logger
@@ -114,6 +224,39 @@ func (svc *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies,
 		return err
 	}
 
+	var slamSvc slam.Service
+	var visionServices []vision.Service
+	if svcConfig.Costmap != nil {
+		slamSvc, err = resource.FromDependencies[slam.Service](deps, slam.Named(svcConfig.Costmap.SLAMName))
+		if err != nil {
+			return err
+		}
+		for _, detector := range svcConfig.Costmap.ObstacleDetectors {
+			visionSvc, err := resource.FromDependencies[vision.Service](deps, vision.Named(detector.VisionServiceName))
+			if err != nil {
+				return err
+			}
+			visionServices = append(visionServices, visionSvc)
+		}
+	}
+
+	var dockCamera camera.Camera
+	var dockVision vision.Service
+	var chargeSensor sensor.Sensor
+	if svcConfig.Dock != nil {
+		if dockCamera, err = camera.FromDependencies(deps, svcConfig.Dock.CameraName); err != nil {
+			return err
+		}
+		dockVision, err = resource.FromDependencies[vision.Service](deps, vision.Named(svcConfig.Dock.VisionServiceName))
+		if err != nil {
+			return err
+		}
+		chargeSensor, err = resource.FromDependencies[sensor.Sensor](deps, sensor.Named(svcConfig.Dock.ChargeSensorName))
+		if err != nil {
+			return err
+		}
+	}
+
 	var newStore navigation.NavStore
 	if svc.storeType != string(svcConfig.Store.Type) {
 		switch svcConfig.Store.Type {
@@ -125,6 +268,12 @@ func (svc *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies,
 			if err != nil {
 				return err
 			}
+		case navigation.StoreTypeFile:
+			var err error
+			newStore, err = navigation.NewFileNavigationStore(svcConfig.Store.Config)
+			if err != nil {
+				return err
+			}
 		default:
 			return errors.Errorf("unknown store type %q", svcConfig.Store.Type)
 		}
@@ -148,6 +297,19 @@ func (svc *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies,
 	svc.movementSensor = movementSensor
 	svc.mmPerSecDefault = straightSpeed
 	svc.degPerSecDefault = spinSpeed
+	svc.costmapConfig = svcConfig.Costmap
+	svc.dockConfig = svcConfig.Dock
+	svc.dockCamera = dockCamera
+	svc.dockVision = dockVision
+	svc.chargeSensor = chargeSensor
+	svc.zonesMu.Lock()
+	svc.zones = append([]navigation.Zone(nil), svcConfig.Zones...)
+	svc.zonesMu.Unlock()
+	svc.slam = slamSvc
+	svc.visionServices = visionServices
+	if svcConfig.Costmap != nil {
+		svc.mapOrigin = geo.NewPoint(svcConfig.Costmap.MapOriginLatitude, svcConfig.Costmap.MapOriginLongitude)
+	}
 
 	return nil
 }
@@ -204,60 +366,20 @@ func (svc *builtIn) startWaypoint(extra map[string]interface{}) error {
 			if !utils.SelectContextOrWait(svc.cancelCtx, 500*time.Millisecond) {
 				return
 			}
-			currentLoc, _, err := svc.movementSensor.Position(svc.cancelCtx, extra)
-			if err != nil {
-				svc.logger.Errorw("failed to get gps location", "error", err)
-				continue
-			}
-
-			if len(path) <= 1 || currentLoc.GreatCircleDistance(path[len(path)-1]) > .0001 {
-				// gps often updates less frequently
-				path = append(path, currentLoc)
-				if len(path) > 2 {
-					path = path[len(path)-2:]
-				}
-			}
 
 			navOnce := func(ctx context.Context) error {
-				if len(path) <= 1 {
-					return errors.New("not enough gps data")
-				}
-
-				currentBearing, err := svc.computeCurrentBearing(ctx, path)
+				wp, err := svc.nextWaypoint(ctx)
 				if err != nil {
 					return err
 				}
-
-				bearingToGoal, distanceToGoal, err := svc.waypointDirectionAndDistanceToGo(ctx, currentLoc)
+				reached, err := svc.driveTowards(ctx, wp.ToPoint(), &path, extra)
 				if err != nil {
 					return err
 				}
-
-				if distanceToGoal < .005 {
+				if reached {
 					svc.logger.Debug("i made it")
 					return svc.waypointReached(ctx)
 				}
-
-				bearingDelta := computeBearing(bearingToGoal, currentBearing)
-				steeringDir := -bearingDelta / 180.0
-
-				svc.logger.Debugf("currentBearing: %0.0f bearingToGoal: %0.0f distanceToGoal: %0.3f bearingDelta: %0.1f steeringDir: %0.2f",
-					currentBearing, bearingToGoal, distanceToGoal, bearingDelta, steeringDir)
-
-				// TODO(erh->erd): maybe need an arc/stroke abstraction?
-				// - Remember that we added -1*bearingDelta instead of steeringDir
-				// - Test both naval/land to prove it works
-				if err := svc.base.Spin(ctx, -1*bearingDelta, svc.degPerSecDefault, nil); err != nil {
-					return fmt.Errorf("error turning: %w", err)
-				}
-
-				distanceMm := distanceToGoal * 1000 * 1000
-				distanceMm = math.Min(distanceMm, 10*1000)
-
-				if err := svc.base.MoveStraight(ctx, int(distanceMm), svc.mmPerSecDefault, nil); err != nil {
-					return fmt.Errorf("error moving %w", err)
-				}
-
 				return nil
 			}
 
@@ -269,15 +391,177 @@ func (svc *builtIn) startWaypoint(extra map[string]interface{}) error {
 	return nil
 }
 
-func (svc *builtIn) waypointDirectionAndDistanceToGo(ctx context.Context, currentLoc *geo.Point) (float64, float64, error) {
-	wp, err := svc.nextWaypoint(ctx)
+// driveTowards drives the base one leg of the way to the target, over a costmap if one is configured and straight
+// at the target by GPS otherwise, and reports whether the target had already been reached instead. path holds the
+// last GPS locations, used to work out the current bearing when the movement sensor has no compass.
+func (svc *builtIn) driveTowards(
+	ctx context.Context,
+	target *geo.Point,
+	path *[]*geo.Point,
+	extra map[string]interface{},
+) (bool, error) {
+	if svc.costmapConfig != nil {
+		return svc.driveOverCostmap(ctx, target)
+	}
+
+	currentLoc, _, err := svc.movementSensor.Position(ctx, extra)
+	if err != nil {
+		return false, fmt.Errorf("failed to get gps location: %w", err)
+	}
+	if len(*path) <= 1 || currentLoc.GreatCircleDistance((*path)[len(*path)-1]) > .0001 {
+		// gps often updates less frequently
+		*path = append(*path, currentLoc)
+		if len(*path) > 2 {
+			*path = (*path)[len(*path)-2:]
+		}
+	}
+	if len(*path) <= 1 {
+		return false, errors.New("not enough gps data")
+	}
+
+	currentBearing, err := svc.computeCurrentBearing(ctx, *path)
+	if err != nil {
+		return false, err
+	}
+
+	bearingToGoal := fixAngle(currentLoc.BearingTo(target))
+	distanceToGoal := currentLoc.GreatCircleDistance(target)
+	if distanceToGoal < .005 {
+		return true, nil
+	}
+
+	// without a map to plan around zones, legs that violate them are refused
+	if err := svc.geofence(currentLoc).CheckSegment(r3.Vector{}, motion.GeoPointToLocal(currentLoc, target)); err != nil {
+		return false, err
+	}
+
+	bearingDelta := computeBearing(bearingToGoal, currentBearing)
+	steeringDir := -bearingDelta / 180.0
+
+	svc.logger.Debugf("currentBearing: %0.0f bearingToGoal: %0.0f distanceToGoal: %0.3f bearingDelta: %0.1f steeringDir: %0.2f",
+		currentBearing, bearingToGoal, distanceToGoal, bearingDelta, steeringDir)
+
+	// TODO(erh->erd): maybe need an arc/stroke abstraction?
+	// - Remember that we added -1*bearingDelta instead of steeringDir
+	// - Test both naval/land to prove it works
+	if err := svc.base.Spin(ctx, -1*bearingDelta, svc.degPerSecDefault, nil); err != nil {
+		return false, fmt.Errorf("error turning: %w", err)
+	}
+
+	distanceMm := distanceToGoal * 1000 * 1000
+	distanceMm = math.Min(distanceMm, 10*1000)
+
+	if err := svc.base.MoveStraight(ctx, int(distanceMm), svc.mmPerSecDefault, nil); err != nil {
+		return false, fmt.Errorf("error moving %w", err)
+	}
+	return false, nil
+}
+
+// driveOverCostmap plans a path to the target over a costmap of the SLAM map and the obstacles detected around the
+// robot, and drives the first leg of it. The costmap is rebuilt every time, so that the path follows the map as it
+// grows and obstacles as they move.
+func (svc *builtIn) driveOverCostmap(ctx context.Context, target *geo.Point) (bool, error) {
+	pose, _, err := svc.slam.GetPosition(ctx)
 	if err != nil {
-		return 0, 0, err
+		return false, err
+	}
+	current := r3.Vector{X: pose.Point().X, Y: pose.Point().Y}
+	goal := motion.GeoPointToLocal(svc.mapOrigin, target)
+	geofence := svc.geofence(svc.mapOrigin)
+	if err := geofence.CheckPoint(goal); err != nil {
+		return false, fmt.Errorf("waypoint cannot be reached: %w", err)
 	}
 
-	goal := wp.ToPoint()
+	// the same 5m tolerance as when navigating by GPS alone
+	if current.Sub(goal).Norm() < 5*1000 {
+		return true, nil
+	}
 
-	return fixAngle(currentLoc.BearingTo(goal)), currentLoc.GreatCircleDistance(goal), nil
+	costmap, err := svc.buildCostmap(ctx, pose, current, goal, geofence)
+	if err != nil {
+		return false, err
+	}
+	path, err := costmap.PlanPath(current, goal)
+	if err != nil {
+		return false, err
+	}
+	leg := path[1].Sub(current)
+	bearingToLeg := math.Atan2(leg.Y, leg.X) * 180 / math.Pi
+	heading := pose.Orientation().EulerAngles().Yaw * 180 / math.Pi
+	// Spin is counterclockwise, like the angles of the map frame
+	spin := computeBearing(heading, bearingToLeg)
+
+	svc.logger.Debugf("path: %v heading: %0.0f bearingToLeg: %0.0f spin: %0.1f", path, heading, bearingToLeg, spin)
+
+	if err := svc.base.Spin(ctx, spin, svc.degPerSecDefault, nil); err != nil {
+		return false, fmt.Errorf("error turning: %w", err)
+	}
+	distanceMm := math.Min(leg.Norm(), 10*1000)
+	if err := svc.base.MoveStraight(ctx, int(distanceMm), svc.mmPerSecDefault, nil); err != nil {
+		return false, fmt.Errorf("error moving %w", err)
+	}
+	return false, nil
+}
+
+// buildCostmap builds an inflated costmap of the occupancy grid of the SLAM map and the obstacles currently detected by the vision
+// services, with the cells the geofence keeps the robot out of as obstacles, large enough to plan between the robot
+// and the goal.
+func (svc *builtIn) buildCostmap(
+	ctx context.Context,
+	pose spatialmath.Pose,
+	current, goal r3.Vector,
+	geofence *navigation.Geofence,
+) (*navigation.Costmap, error) {
+	resolution := svc.costmapConfig.ResolutionMm
+	if resolution == 0 {
+		resolution = costmapResolutionMmDefault
+	}
+	grid, err := slam.GetOccupancyGrid(ctx, svc.slam, resolution, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var obstacles []spatialmath.Geometry
+	for i, visionSvc := range svc.visionServices {
+		objects, err := visionSvc.GetObjectPointClouds(ctx, svc.costmapConfig.ObstacleDetectors[i].CameraName, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range objects {
+			if object.Geometry != nil {
+				obstacles = append(obstacles, object.Geometry.Transform(pose))
+			}
+		}
+	}
+
+	min := r3.Vector{X: math.Min(current.X, goal.X), Y: math.Min(current.Y, goal.Y)}
+	max := r3.Vector{X: math.Max(current.X, goal.X), Y: math.Max(current.Y, goal.Y)}
+	width, height, _ := grid.Dimensions()
+	gridMin := grid.Origin()
+	gridMax := gridMin.Add(r3.Vector{X: float64(width), Y: float64(height)}.Mul(grid.Resolution()))
+	min = r3.Vector{X: math.Min(min.X, gridMin.X), Y: math.Min(min.Y, gridMin.Y)}
+	max = r3.Vector{X: math.Max(max.X, gridMax.X), Y: math.Max(max.Y, gridMax.Y)}
+	// leave room to plan around obstacles at the edges
+	margin := 2 * math.Max(svc.costmapConfig.RobotRadiusMm, svc.costmapConfig.InflationRadiusMm)
+	min = min.Sub(r3.Vector{X: margin, Y: margin})
+	max = max.Add(r3.Vector{X: margin, Y: margin})
+
+	costmap, err := navigation.NewCostmap(min, max, resolution)
+	if err != nil {
+		return nil, err
+	}
+	costmap.AddOccupancyGrid(grid)
+	costmap.AddObstacles(obstacles)
+	costmap.AddGeofence(geofence)
+	costmap.Inflate(svc.costmapConfig.RobotRadiusMm, svc.costmapConfig.InflationRadiusMm)
+	return costmap, nil
+}
+
+// geofence returns a geofence of the current zones around the given origin.
+func (svc *builtIn) geofence(origin *geo.Point) *navigation.Geofence {
+	svc.zonesMu.Lock()
+	defer svc.zonesMu.Unlock()
+	return navigation.NewGeofence(svc.zones, origin)
 }
 
 func (svc *builtIn) Location(ctx context.Context, extra map[string]interface{}) (*geo.Point, error) {
@@ -299,6 +583,13 @@ func (svc *builtIn) Waypoints(ctx context.Context, extra map[string]interface{})
 }
 
 func (svc *builtIn) AddWaypoint(ctx context.Context, point *geo.Point, extra map[string]interface{}) error {
+	origin := svc.mapOrigin
+	if origin == nil {
+		origin = point
+	}
+	if err := svc.geofence(origin).CheckPoint(motion.GeoPointToLocal(origin, point)); err != nil {
+		return fmt.Errorf("waypoint not added: %w", err)
+	}
 	_, err := svc.store.AddWaypoint(ctx, point)
 	return err
 }
@@ -307,11 +598,132 @@ func (svc *builtIn) RemoveWaypoint(ctx context.Context, id primitive.ObjectID, e
 	return svc.store.RemoveWaypoint(ctx, id)
 }
 
+func (svc *builtIn) Zones(ctx context.Context) ([]navigation.Zone, error) {
+	svc.zonesMu.Lock()
+	defer svc.zonesMu.Unlock()
+	return append([]navigation.Zone(nil), svc.zones...), nil
+}
+
+func (svc *builtIn) AddZone(ctx context.Context, zone navigation.Zone) error {
+	if err := zone.Validate("zone"); err != nil {
+		return err
+	}
+	if len(zone.Polygon) > 0 && svc.mapOrigin == nil {
+		return errors.New("zones in the map frame need a costmap to place the map")
+	}
+	svc.zonesMu.Lock()
+	defer svc.zonesMu.Unlock()
+	for i, z := range svc.zones {
+		if z.Name == zone.Name {
+			svc.zones[i] = zone
+			return nil
+		}
+	}
+	svc.zones = append(svc.zones, zone)
+	return nil
+}
+
+func (svc *builtIn) RemoveZone(ctx context.Context, name string) error {
+	svc.zonesMu.Lock()
+	defer svc.zonesMu.Unlock()
+	for i, z := range svc.zones {
+		if z.Name == name {
+			svc.zones = append(svc.zones[:i], svc.zones[i+1:]...)
+			return nil
+		}
+	}
+	return errors.Errorf("no zone named %q", name)
+}
+
+func (svc *builtIn) Missions(ctx context.Context) ([]navigation.Mission, error) {
+	return svc.store.Missions(ctx)
+}
+
+func (svc *builtIn) AddMission(ctx context.Context, mission navigation.Mission) error {
+	if err := mission.Validate(); err != nil {
+		return err
+	}
+	for i, v := range mission.Waypoints {
+		point := geo.NewPoint(v.Latitude, v.Longitude)
+		origin := svc.mapOrigin
+		if origin == nil {
+			origin = point
+		}
+		if err := svc.geofence(origin).CheckPoint(motion.GeoPointToLocal(origin, point)); err != nil {
+			return fmt.Errorf("mission waypoint %d: %w", i, err)
+		}
+	}
+	return svc.store.SaveMission(ctx, mission)
+}
+
+func (svc *builtIn) RemoveMission(ctx context.Context, name string) error {
+	return svc.store.RemoveMission(ctx, name)
+}
+
+// StartMission runs the mission from its first waypoint, switching to waypoint mode if needed.
+func (svc *builtIn) StartMission(ctx context.Context, name string) error {
+	mission, err := svc.store.Mission(ctx, name)
+	if err != nil {
+		return err
+	}
+	progress := navigation.NewMissionProgress(mission)
+	svc.missionMu.Lock()
+	svc.mission = &mission
+	svc.progress = &progress
+	svc.missionMu.Unlock()
+	return svc.SetMode(ctx, navigation.ModeWaypoint, nil)
+}
+
+func (svc *builtIn) StopMission(ctx context.Context) error {
+	svc.missionMu.Lock()
+	defer svc.missionMu.Unlock()
+	svc.mission = nil
+	return nil
+}
+
+func (svc *builtIn) MissionProgress(ctx context.Context) (navigation.MissionProgress, error) {
+	svc.missionMu.Lock()
+	defer svc.missionMu.Unlock()
+	if svc.progress == nil {
+		return navigation.MissionProgress{}, errors.New("no mission has been started")
+	}
+	return *svc.progress, nil
+}
+
+func (svc *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	resp, err := navigation.DoZoneCommand(ctx, svc, cmd)
+	if !errors.Is(err, resource.ErrDoUnimplemented) {
+		return resp, err
+	}
+	resp, err = navigation.DoMissionCommand(ctx, svc, cmd)
+	if !errors.Is(err, resource.ErrDoUnimplemented) {
+		return resp, err
+	}
+	return navigation.DoDockCommand(ctx, svc, cmd)
+}
+
+// nextWaypoint returns the waypoint to navigate to, from the running mission if there is one and otherwise from the
+// store.
 func (svc *builtIn) nextWaypoint(ctx context.Context) (navigation.Waypoint, error) {
+	svc.missionMu.Lock()
+	if svc.mission != nil {
+		defer svc.missionMu.Unlock()
+		return svc.progress.Target(*svc.mission)
+	}
+	svc.missionMu.Unlock()
 	return svc.store.NextWaypoint(ctx)
 }
 
 func (svc *builtIn) waypointReached(ctx context.Context) error {
+	svc.missionMu.Lock()
+	if svc.mission != nil {
+		defer svc.missionMu.Unlock()
+		svc.progress.Advance(*svc.mission)
+		svc.logger.Debugf("mission %q progress: %+v", svc.mission.Name, *svc.progress)
+		return nil
+	}
+	svc.missionMu.Unlock()
+
 	wp, err := svc.nextWaypoint(ctx)
 	if err != nil {
 		return fmt.Errorf("can't mark waypoint reached: %w", err)
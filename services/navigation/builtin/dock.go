@@ -0,0 +1,273 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/services/navigation"
+)
+
+const (
+	dockApproachStepMmDefault   = 50
+	dockMaxApproachMmDefault    = 2000
+	dockApproachMMPerSecDefault = 100
+	dockMarkerLabelDefault      = "dock"
+	dockChargeReadingDefault    = "is_charging"
+
+	// how far off center the marker may be, as a fraction of half the image width, before the base turns to it
+	dockAlignTolerance = 0.05
+	// how far the base turns for a marker at the edge of the image
+	dockAlignGainDegs = 15
+	// how far the base turns at a time while looking for a marker it cannot see
+	dockSearchStepDegs = 20
+	// how many times in a row the base may turn to the marker without centering it
+	dockMaxAlignTurns = 10
+)
+
+// DockConfig describes how to dock the robot: it navigates to the approach point as it would to a waypoint, and then
+// turns to face the marker on the dock, as detected by the vision service in the images of the camera, and creeps
+// towards it until the charge sensor reports that it is charging.
+type DockConfig struct {
+	ApproachLatitude  float64 `json:"approach_latitude"`
+	ApproachLongitude float64 `json:"approach_longitude"`
+
+	CameraName        string `json:"camera"`
+	VisionServiceName string `json:"vision_service"`
+	// MarkerLabel is the label of the detections of the marker, such as an AprilTag or IR beacon, on the dock.
+	MarkerLabel string `json:"marker_label"`
+
+	ChargeSensorName string `json:"charge_sensor"`
+	// ChargeReading is the reading of the charge sensor that is true, or positive, while the robot is charging.
+	ChargeReading string `json:"charge_reading"`
+
+	ApproachStepMm   float64 `json:"approach_step_mm"`
+	MaxApproachMm    float64 `json:"max_approach_mm"`
+	ApproachMMPerSec float64 `json:"approach_mm_per_sec"`
+}
+
+// Validate ensures all parts of the config are valid and returns the resources it depends on.
+func (conf *DockConfig) Validate(path string) ([]string, error) {
+	if conf.CameraName == "" {
+		return nil, utils.NewConfigValidationFieldRequiredError(path, "camera")
+	}
+	if conf.VisionServiceName == "" {
+		return nil, utils.NewConfigValidationFieldRequiredError(path, "vision_service")
+	}
+	if conf.ChargeSensorName == "" {
+		return nil, utils.NewConfigValidationFieldRequiredError(path, "charge_sensor")
+	}
+	if conf.ApproachStepMm < 0 || conf.MaxApproachMm < 0 || conf.ApproachMMPerSec < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("approach distances and speed cannot be negative"))
+	}
+	return []string{conf.CameraName, conf.VisionServiceName, conf.ChargeSensorName}, nil
+}
+
+func (svc *builtIn) Dock(ctx context.Context) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if svc.dockConfig == nil {
+		return errors.New("no dock is configured")
+	}
+
+	// docking takes over the base from any other navigation
+	svc.cancelFunc()
+	svc.activeBackgroundWorkers.Wait()
+	cancelCtx, cancelFunc := context.WithCancel(context.Background())
+	svc.cancelCtx = cancelCtx
+	svc.cancelFunc = cancelFunc
+	svc.mode = navigation.ModeManual
+
+	svc.setDockStatus(navigation.DockStateApproaching, nil)
+	svc.activeBackgroundWorkers.Add(1)
+	utils.PanicCapturingGo(func() {
+		defer svc.activeBackgroundWorkers.Done()
+		if err := svc.dock(cancelCtx); err != nil {
+			svc.logger.Infof("error docking: %s", err)
+			svc.setDockStatus(navigation.DockStateFailed, err)
+			return
+		}
+		svc.setDockStatus(navigation.DockStateDocked, nil)
+	})
+	return nil
+}
+
+func (svc *builtIn) DockStatus(ctx context.Context) (navigation.DockStatus, error) {
+	svc.dockMu.Lock()
+	defer svc.dockMu.Unlock()
+	if svc.dockStatus.State == "" {
+		return navigation.DockStatus{State: navigation.DockStateIdle}, nil
+	}
+	return svc.dockStatus, nil
+}
+
+func (svc *builtIn) setDockStatus(state navigation.DockState, err error) {
+	svc.dockMu.Lock()
+	defer svc.dockMu.Unlock()
+	svc.dockStatus = navigation.DockStatus{State: state}
+	if err != nil {
+		svc.dockStatus.Error = err.Error()
+	}
+}
+
+// dock navigates to the approach point and then makes the precision approach to the dock.
+func (svc *builtIn) dock(ctx context.Context) error {
+	approach := geo.NewPoint(svc.dockConfig.ApproachLatitude, svc.dockConfig.ApproachLongitude)
+	path := []*geo.Point{}
+	for {
+		reached, err := svc.driveTowards(ctx, approach, &path, nil)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			svc.logger.Infof("error navigating to dock: %s", err)
+		}
+		if reached {
+			break
+		}
+		if !utils.SelectContextOrWait(ctx, 500*time.Millisecond) {
+			return ctx.Err()
+		}
+	}
+
+	svc.logger.Debug("reached dock approach point")
+	svc.setDockStatus(navigation.DockStateAligning, nil)
+	return svc.approachDock(ctx)
+}
+
+// approachDock creeps towards the dock's marker, turning to keep it centered, until the robot is charging. Once the
+// marker has been seen, the robot keeps going straight if it loses sight of it, since the marker is likely too close
+// to be seen whole.
+func (svc *builtIn) approachDock(ctx context.Context) error {
+	step := svc.dockConfig.ApproachStepMm
+	if step == 0 {
+		step = dockApproachStepMmDefault
+	}
+	maxApproach := svc.dockConfig.MaxApproachMm
+	if maxApproach == 0 {
+		maxApproach = dockMaxApproachMmDefault
+	}
+	speed := svc.dockConfig.ApproachMMPerSec
+	if speed == 0 {
+		speed = dockApproachMMPerSecDefault
+	}
+
+	var driven, searched float64
+	seen := false
+	alignTurns := 0
+	for driven < maxApproach {
+		charging, err := svc.charging(ctx)
+		if err != nil {
+			return err
+		}
+		if charging {
+			return nil
+		}
+
+		offset, found, err := svc.findDockMarker(ctx)
+		if err != nil {
+			return err
+		}
+		switch {
+		case found && math.Abs(offset) > dockAlignTolerance:
+			if alignTurns >= dockMaxAlignTurns {
+				return errors.New("could not turn to face the dock marker")
+			}
+			alignTurns++
+			// Spin is counterclockwise, so a marker right of center needs a negative spin
+			if err := svc.base.Spin(ctx, -offset*dockAlignGainDegs, svc.degPerSecDefault, nil); err != nil {
+				return fmt.Errorf("error turning: %w", err)
+			}
+			continue
+		case found:
+			seen = true
+			searched = 0
+		case !seen:
+			if searched >= 360 {
+				return errors.New("could not find the dock marker")
+			}
+			if err := svc.base.Spin(ctx, dockSearchStepDegs, svc.degPerSecDefault, nil); err != nil {
+				return fmt.Errorf("error turning: %w", err)
+			}
+			searched += dockSearchStepDegs
+			continue
+		}
+
+		if err := svc.base.MoveStraight(ctx, int(step), speed, nil); err != nil {
+			return fmt.Errorf("error moving %w", err)
+		}
+		driven += step
+		alignTurns = 0
+	}
+
+	charging, err := svc.charging(ctx)
+	if err != nil {
+		return err
+	}
+	if !charging {
+		return errors.Errorf("not charging after approaching the dock for %0.fmm", driven)
+	}
+	return nil
+}
+
+// findDockMarker returns how far off center the best detection of the dock's marker is, from -1 at the left edge of
+// the image to 1 at the right, and whether the marker was detected at all.
+func (svc *builtIn) findDockMarker(ctx context.Context) (float64, bool, error) {
+	img, release, err := camera.ReadImage(ctx, svc.dockCamera)
+	if err != nil {
+		return 0, false, err
+	}
+	defer release()
+	detections, err := svc.dockVision.Detections(ctx, img, nil)
+	if err != nil {
+		return 0, false, err
+	}
+
+	label := svc.dockConfig.MarkerLabel
+	if label == "" {
+		label = dockMarkerLabelDefault
+	}
+	found := false
+	var bestScore, offset float64
+	halfWidth := float64(img.Bounds().Dx()) / 2
+	for _, d := range detections {
+		if !strings.EqualFold(d.Label(), label) || (found && d.Score() <= bestScore) {
+			continue
+		}
+		box := d.BoundingBox()
+		center := float64(box.Min.X+box.Max.X)/2 - float64(img.Bounds().Min.X)
+		offset = (center - halfWidth) / halfWidth
+		bestScore = d.Score()
+		found = true
+	}
+	return offset, found, nil
+}
+
+// charging returns whether the charge sensor reports that the robot is charging.
+func (svc *builtIn) charging(ctx context.Context) (bool, error) {
+	readings, err := svc.chargeSensor.Readings(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	key := svc.dockConfig.ChargeReading
+	if key == "" {
+		key = dockChargeReadingDefault
+	}
+	switch v := readings[key].(type) {
+	case bool:
+		return v, nil
+	case float64:
+		return v > 0, nil
+	case int:
+		return v > 0, nil
+	default:
+		return false, errors.Errorf("charge sensor has no %q reading", key)
+	}
+}
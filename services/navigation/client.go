@@ -164,3 +164,123 @@ func (c *client) RemoveWaypoint(ctx context.Context, id primitive.ObjectID, extr
 func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	return rprotoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
 }
+
+// Zones is sent through DoCommand until the navigation service API has an RPC for it.
+func (c *client) Zones(ctx context.Context) ([]Zone, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{GetZonesCommand: true})
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := resp["zones"].([]interface{})
+	zones := make([]Zone, 0, len(raw))
+	for _, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("invalid zone in %s response", GetZonesCommand)
+		}
+		zone, err := ZoneFromMap(m)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
+
+// AddZone is sent through DoCommand until the navigation service API has an RPC for it.
+func (c *client) AddZone(ctx context.Context, zone Zone) error {
+	m, err := zone.ToMap()
+	if err != nil {
+		return err
+	}
+	_, err = c.DoCommand(ctx, map[string]interface{}{AddZoneCommand: m})
+	return err
+}
+
+// RemoveZone is sent through DoCommand until the navigation service API has an RPC for it.
+func (c *client) RemoveZone(ctx context.Context, name string) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{RemoveZoneCommand: name})
+	return err
+}
+
+// Missions is sent through DoCommand until the navigation service API has an RPC for it.
+func (c *client) Missions(ctx context.Context) ([]Mission, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{GetMissionsCommand: true})
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := resp["missions"].([]interface{})
+	missions := make([]Mission, 0, len(raw))
+	for _, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("invalid mission in %s response", GetMissionsCommand)
+		}
+		var mission Mission
+		if err := fromMap(m, &mission); err != nil {
+			return nil, err
+		}
+		missions = append(missions, mission)
+	}
+	return missions, nil
+}
+
+// AddMission is sent through DoCommand until the navigation service API has an RPC for it.
+func (c *client) AddMission(ctx context.Context, mission Mission) error {
+	m, err := toMap(mission)
+	if err != nil {
+		return err
+	}
+	_, err = c.DoCommand(ctx, map[string]interface{}{AddMissionCommand: m})
+	return err
+}
+
+// RemoveMission is sent through DoCommand until the navigation service API has an RPC for it.
+func (c *client) RemoveMission(ctx context.Context, name string) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{RemoveMissionCommand: name})
+	return err
+}
+
+// StartMission is sent through DoCommand until the navigation service API has an RPC for it.
+func (c *client) StartMission(ctx context.Context, name string) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{StartMissionCommand: name})
+	return err
+}
+
+// StopMission is sent through DoCommand until the navigation service API has an RPC for it.
+func (c *client) StopMission(ctx context.Context) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{StopMissionCommand: true})
+	return err
+}
+
+// MissionProgress is sent through DoCommand until the navigation service API has an RPC for it.
+func (c *client) MissionProgress(ctx context.Context) (MissionProgress, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{GetMissionProgressCommand: true})
+	if err != nil {
+		return MissionProgress{}, err
+	}
+	var progress MissionProgress
+	if err := fromMap(resp, &progress); err != nil {
+		return MissionProgress{}, err
+	}
+	return progress, nil
+}
+
+// Dock is sent through DoCommand until the navigation service API has an RPC for it.
+func (c *client) Dock(ctx context.Context) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{DockCommand: true})
+	return err
+}
+
+// DockStatus is sent through DoCommand until the navigation service API has an RPC for it.
+func (c *client) DockStatus(ctx context.Context) (DockStatus, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{GetDockStatusCommand: true})
+	if err != nil {
+		return DockStatus{}, err
+	}
+	var status DockStatus
+	if err := fromMap(resp, &status); err != nil {
+		return DockStatus{}, err
+	}
+	return status, nil
+}
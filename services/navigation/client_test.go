@@ -176,6 +176,25 @@ func TestClient(t *testing.T) {
 		test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
 		test.That(t, resp["data"], test.ShouldEqual, testutils.TestCommand["data"])
 
+		// test zones
+		zones := &zoneList{}
+		workingNavigationService.DoCommandFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			return navigation.DoZoneCommand(ctx, zones, cmd)
+		}
+		zone := navigation.Zone{
+			Name:       "pond",
+			Type:       navigation.ZoneTypeKeepOut,
+			GeoPolygon: []navigation.GeoVertex{{Latitude: 40, Longitude: 20}, {Latitude: 40.1, Longitude: 20}, {Latitude: 40, Longitude: 20.1}},
+		}
+		zoneEditor, ok := workingNavClient.(navigation.ZoneEditor)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, zoneEditor.AddZone(context.Background(), zone), test.ShouldBeNil)
+		gotZones, err := zoneEditor.Zones(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, gotZones, test.ShouldResemble, []navigation.Zone{zone})
+		test.That(t, zoneEditor.RemoveZone(context.Background(), "pond"), test.ShouldBeNil)
+		test.That(t, zones.zones, test.ShouldBeEmpty)
+
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
 
@@ -0,0 +1,246 @@
+package navigation
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// The costs of special cells of a Costmap. Cells that are neither free nor too close to an obstacle cost between
+// CostFree and CostInscribed, the closer to an obstacle the more.
+const (
+	CostFree uint8 = 0
+	// CostInscribed cells are so close to an obstacle that the robot would hit it with its center in the cell.
+	CostInscribed uint8 = 253
+	// CostLethal cells hold an obstacle.
+	CostLethal uint8 = 254
+)
+
+// costWeight is how many cells of distance a path trades for passing through a cell of cost 1 less, so that paths keep
+// their distance from obstacles when it does not make them much longer.
+const costWeight = 50.
+
+// A Costmap is a grid over the XY plane of a map, in mm, that gives the cost of the robot's center being in each cell.
+// Obstacles are projected onto the plane, and then inflated by the robot's radius and an extra margin that paths are
+// kept out of when they can be.
+type Costmap struct {
+	resolution    float64
+	origin        r3.Vector
+	width, height int
+	cells         []uint8
+}
+
+// NewCostmap returns a costmap of free cells covering the rectangle between min and max, with cells resolution mm wide.
+func NewCostmap(min, max r3.Vector, resolution float64) (*Costmap, error) {
+	if resolution <= 0 {
+		return nil, errors.New("costmap resolution must be positive")
+	}
+	if max.X < min.X || max.Y < min.Y {
+		return nil, errors.New("costmap bounds are empty")
+	}
+	width := int(math.Ceil((max.X-min.X)/resolution)) + 1
+	height := int(math.Ceil((max.Y-min.Y)/resolution)) + 1
+	return &Costmap{
+		resolution: resolution,
+		origin:     r3.Vector{X: min.X, Y: min.Y},
+		width:      width,
+		height:     height,
+		cells:      make([]uint8, width*height),
+	}, nil
+}
+
+// AddPointCloud marks every cell holding a point of the cloud, such as a SLAM map, as an obstacle.
+func (c *Costmap) AddPointCloud(pc pointcloud.PointCloud) {
+	pc.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		c.setLethal(p)
+		return true
+	})
+}
+
+// AddOccupancyGrid marks every cell holding the center of an occupied cell of the grid, such as one of a SLAM map, as
+// an obstacle. Cells of the grid that are unknown are left free.
+func (c *Costmap) AddOccupancyGrid(grid *pointcloud.OccupancyGrid) {
+	grid.Iterate(func(x, y, z int, occupancy int8) bool {
+		if occupancy >= pointcloud.OccupiedThreshold {
+			c.setLethal(grid.CellCenter(x, y, z))
+		}
+		return true
+	})
+}
+
+// AddObstacles marks every cell the outline of a geometry passes through as an obstacle.
+func (c *Costmap) AddObstacles(geometries []spatialmath.Geometry) {
+	for _, g := range geometries {
+		for _, p := range g.ToPoints(c.resolution / 2) {
+			c.setLethal(p)
+		}
+	}
+}
+
+// AddGeofence marks every cell the robot may not be in because of the geofence as an obstacle, so that inflation
+// keeps the robot clear of its zones as well.
+func (c *Costmap) AddGeofence(g *Geofence) {
+	for i := range c.cells {
+		if g.CheckPoint(c.center(i)) != nil {
+			c.cells[i] = CostLethal
+		}
+	}
+}
+
+// Inflate raises the cost of the cells around every obstacle: cells within robotRadius of an obstacle become
+// CostInscribed, and the cost of cells beyond that falls to CostFree at inflationRadius.
+func (c *Costmap) Inflate(robotRadius, inflationRadius float64) {
+	inflationRadius = math.Max(robotRadius, inflationRadius)
+	reach := int(math.Ceil(inflationRadius / c.resolution))
+	costs := make([]uint8, len(c.cells))
+	copy(costs, c.cells)
+	for i, cost := range c.cells {
+		if cost != CostLethal {
+			continue
+		}
+		x, y := i%c.width, i/c.width
+		for dy := -reach; dy <= reach; dy++ {
+			for dx := -reach; dx <= reach; dx++ {
+				if !c.inBounds(x+dx, y+dy) {
+					continue
+				}
+				dist := math.Hypot(float64(dx), float64(dy)) * c.resolution
+				if dist > inflationRadius {
+					continue
+				}
+				var inflated uint8
+				if dist <= robotRadius {
+					inflated = CostInscribed
+				} else {
+					inflated = uint8(float64(CostInscribed-1) * (inflationRadius - dist) / (inflationRadius - robotRadius))
+				}
+				if j := (y+dy)*c.width + x + dx; inflated > costs[j] {
+					costs[j] = inflated
+				}
+			}
+		}
+	}
+	c.cells = costs
+}
+
+// Cost returns the cost of the cell holding the point. Points off the costmap are free.
+func (c *Costmap) Cost(p r3.Vector) uint8 {
+	x, y := c.cell(p)
+	if !c.inBounds(x, y) {
+		return CostFree
+	}
+	return c.cells[y*c.width+x]
+}
+
+// PlanPath plans the cheapest path from start to goal with A*, moving between neighbouring cells and never through a
+// cell of CostInscribed or more. The path starts at start and ends at goal, with a waypoint wherever it turns.
+func (c *Costmap) PlanPath(start, goal r3.Vector) ([]r3.Vector, error) {
+	startX, startY := c.cell(start)
+	goalX, goalY := c.cell(goal)
+	if !c.inBounds(startX, startY) || !c.inBounds(goalX, goalY) {
+		return nil, errors.New("start and goal must be on the costmap")
+	}
+	startCell, goalCell := startY*c.width+startX, goalY*c.width+goalX
+	if c.cells[goalCell] >= CostInscribed {
+		return nil, errors.New("goal is too close to an obstacle")
+	}
+
+	heuristic := func(cell int) float64 {
+		return math.Hypot(float64(cell%c.width-goalX), float64(cell/c.width-goalY))
+	}
+	costSoFar := map[int]float64{startCell: 0}
+	cameFrom := map[int]int{}
+	open := &cellQueue{{cell: startCell, priority: heuristic(startCell)}}
+	for open.Len() > 0 {
+		current := heap.Pop(open).(cellPriority)
+		if current.cell == goalCell {
+			return c.tracePath(cameFrom, startCell, goalCell, start, goal), nil
+		}
+		x, y := current.cell%c.width, current.cell/c.width
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if (dx == 0 && dy == 0) || !c.inBounds(x+dx, y+dy) {
+					continue
+				}
+				next := (y+dy)*c.width + x + dx
+				if c.cells[next] >= CostInscribed {
+					continue
+				}
+				cost := costSoFar[current.cell] + math.Hypot(float64(dx), float64(dy))*(1+float64(c.cells[next])/costWeight)
+				if prev, ok := costSoFar[next]; ok && prev <= cost {
+					continue
+				}
+				costSoFar[next] = cost
+				cameFrom[next] = current.cell
+				heap.Push(open, cellPriority{cell: next, priority: cost + heuristic(next)})
+			}
+		}
+	}
+	return nil, errors.New("no path to goal")
+}
+
+// tracePath follows the cells back from the goal to the start, keeping only the cells where the path turns.
+func (c *Costmap) tracePath(cameFrom map[int]int, startCell, goalCell int, start, goal r3.Vector) []r3.Vector {
+	cells := []int{goalCell}
+	for cell := goalCell; cell != startCell; {
+		cell = cameFrom[cell]
+		cells = append(cells, cell)
+	}
+	path := []r3.Vector{start}
+	for i := len(cells) - 2; i > 0; i-- {
+		prev, cell, next := cells[i+1], cells[i], cells[i-1]
+		if cell-prev != next-cell {
+			path = append(path, c.center(cell))
+		}
+	}
+	return append(path, goal)
+}
+
+func (c *Costmap) setLethal(p r3.Vector) {
+	if x, y := c.cell(p); c.inBounds(x, y) {
+		c.cells[y*c.width+x] = CostLethal
+	}
+}
+
+func (c *Costmap) cell(p r3.Vector) (int, int) {
+	return int(math.Floor((p.X - c.origin.X) / c.resolution)), int(math.Floor((p.Y - c.origin.Y) / c.resolution))
+}
+
+func (c *Costmap) center(cell int) r3.Vector {
+	return r3.Vector{
+		X: c.origin.X + (float64(cell%c.width)+0.5)*c.resolution,
+		Y: c.origin.Y + (float64(cell/c.width)+0.5)*c.resolution,
+	}
+}
+
+func (c *Costmap) inBounds(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.width && y < c.height
+}
+
+type cellPriority struct {
+	cell     int
+	priority float64
+}
+
+// cellQueue is a min-heap of cells by priority.
+type cellQueue []cellPriority
+
+func (q cellQueue) Len() int           { return len(q) }
+func (q cellQueue) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q cellQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *cellQueue) Push(x interface{}) {
+	*q = append(*q, x.(cellPriority))
+}
+
+func (q *cellQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
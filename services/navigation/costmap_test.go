@@ -0,0 +1,79 @@
+package navigation_test
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/services/navigation"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestCostmap(t *testing.T) {
+	_, err := navigation.NewCostmap(r3.Vector{}, r3.Vector{X: 1000, Y: 1000}, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	costmap, err := navigation.NewCostmap(r3.Vector{}, r3.Vector{X: 1000, Y: 1000}, 10)
+	test.That(t, err, test.ShouldBeNil)
+
+	// a wall across the middle of the map with a gap at its top
+	pc := pointcloud.New()
+	for y := 0.; y < 800; y += 5 {
+		test.That(t, pc.Set(r3.Vector{X: 500, Y: y}, nil), test.ShouldBeNil)
+	}
+	costmap.AddPointCloud(pc)
+	costmap.Inflate(50, 150)
+
+	test.That(t, costmap.Cost(r3.Vector{X: 505, Y: 100}), test.ShouldEqual, navigation.CostLethal)
+	test.That(t, costmap.Cost(r3.Vector{X: 545, Y: 100}), test.ShouldEqual, navigation.CostInscribed)
+	test.That(t, costmap.Cost(r3.Vector{X: 605, Y: 100}), test.ShouldBeBetween, navigation.CostFree, navigation.CostInscribed)
+	test.That(t, costmap.Cost(r3.Vector{X: 905, Y: 100}), test.ShouldEqual, navigation.CostFree)
+
+	t.Run("paths go around obstacles", func(t *testing.T) {
+		start, goal := r3.Vector{X: 100, Y: 100}, r3.Vector{X: 900, Y: 100}
+		path, err := costmap.PlanPath(start, goal)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, path[0], test.ShouldResemble, start)
+		test.That(t, path[len(path)-1], test.ShouldResemble, goal)
+		test.That(t, len(path), test.ShouldBeGreaterThan, 2)
+		for i := 1; i < len(path); i++ {
+			for _, p := range pointsBetween(path[i-1], path[i], 5) {
+				test.That(t, costmap.Cost(p), test.ShouldBeLessThan, navigation.CostInscribed)
+			}
+		}
+	})
+
+	t.Run("straight paths have no waypoints", func(t *testing.T) {
+		start, goal := r3.Vector{X: 100, Y: 100}, r3.Vector{X: 100, Y: 900}
+		path, err := costmap.PlanPath(start, goal)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, path, test.ShouldResemble, []r3.Vector{start, goal})
+	})
+
+	t.Run("live obstacles block paths", func(t *testing.T) {
+		box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 500, Y: 900}), r3.Vector{X: 20, Y: 200, Z: 20}, "")
+		test.That(t, err, test.ShouldBeNil)
+		costmap.AddObstacles([]spatialmath.Geometry{box})
+		costmap.Inflate(50, 150)
+		_, err = costmap.PlanPath(r3.Vector{X: 100, Y: 100}, r3.Vector{X: 900, Y: 100})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("goals must be reachable", func(t *testing.T) {
+		_, err := costmap.PlanPath(r3.Vector{X: 100, Y: 100}, r3.Vector{X: 500, Y: 100})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = costmap.PlanPath(r3.Vector{X: 100, Y: 100}, r3.Vector{X: 5000, Y: 100})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func pointsBetween(a, b r3.Vector, step float64) []r3.Vector {
+	n := int(b.Sub(a).Norm()/step) + 1
+	points := make([]r3.Vector, 0, n+1)
+	for i := 0; i <= n; i++ {
+		points = append(points, a.Add(b.Sub(a).Mul(float64(i)/float64(n))))
+	}
+	return points
+}
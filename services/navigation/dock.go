@@ -0,0 +1,59 @@
+package navigation
+
+import (
+	"context"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The DoCommand keys used to dock a remote navigation service. Neither takes an argument.
+const (
+	DockCommand          = "dock"
+	GetDockStatusCommand = "get_dock_status"
+)
+
+// DockState describes how far along docking is.
+type DockState string
+
+// The known dock states.
+const (
+	DockStateIdle = DockState("idle")
+	// DockStateApproaching is navigating to the dock's approach point.
+	DockStateApproaching = DockState("approaching")
+	// DockStateAligning is the precision approach from the approach point, guided by the dock's marker.
+	DockStateAligning = DockState("aligning")
+	// DockStateDocked is reached once the charge sensor confirms contact with the dock.
+	DockStateDocked = DockState("docked")
+	DockStateFailed = DockState("failed")
+)
+
+// DockStatus reports the state of docking, and why it failed if it did.
+type DockStatus struct {
+	State DockState `json:"state"`
+	Error string    `json:"error,omitempty"`
+}
+
+// A Docker is a navigation service that can take its robot to a charging dock.
+type Docker interface {
+	// Dock starts docking, which stops any other navigation, and returns without waiting for it to finish.
+	Dock(ctx context.Context) error
+	// DockStatus returns the state of the latest docking.
+	DockStatus(ctx context.Context) (DockStatus, error)
+}
+
+// DoDockCommand calls the Docker method for a "dock" or "get_dock_status" DoCommand,
+// and returns resource.ErrDoUnimplemented for any other command so that it can be
+// tried before a service's own commands.
+func DoDockCommand(ctx context.Context, d Docker, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := cmd[DockCommand]; ok {
+		return map[string]interface{}{}, d.Dock(ctx)
+	}
+	if _, ok := cmd[GetDockStatusCommand]; ok {
+		status, err := d.DockStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return toMap(status)
+	}
+	return nil, resource.ErrDoUnimplemented
+}
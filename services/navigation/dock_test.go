@@ -0,0 +1,43 @@
+package navigation_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/navigation"
+)
+
+type fakeDocker struct {
+	status navigation.DockStatus
+}
+
+func (d *fakeDocker) Dock(ctx context.Context) error {
+	d.status = navigation.DockStatus{State: navigation.DockStateApproaching}
+	return nil
+}
+
+func (d *fakeDocker) DockStatus(ctx context.Context) (navigation.DockStatus, error) {
+	return d.status, nil
+}
+
+func TestDoDockCommand(t *testing.T) {
+	ctx := context.Background()
+	d := &fakeDocker{status: navigation.DockStatus{State: navigation.DockStateIdle}}
+
+	_, err := navigation.DoDockCommand(ctx, d, map[string]interface{}{navigation.DockCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	resp, err := navigation.DoDockCommand(ctx, d, map[string]interface{}{navigation.GetDockStatusCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp, test.ShouldResemble, map[string]interface{}{"state": "approaching"})
+
+	d.status = navigation.DockStatus{State: navigation.DockStateFailed, Error: "could not find the dock marker"}
+	resp, err = navigation.DoDockCommand(ctx, d, map[string]interface{}{navigation.GetDockStatusCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["error"], test.ShouldEqual, "could not find the dock marker")
+
+	_, err = navigation.DoDockCommand(ctx, d, map[string]interface{}{"nope": true})
+	test.That(t, err, test.ShouldBeError, resource.ErrDoUnimplemented)
+}
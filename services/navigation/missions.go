@@ -0,0 +1,205 @@
+package navigation
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The DoCommand keys used to manage the missions of a remote navigation service.
+// AddMissionCommand takes the mission, in the form of its JSON, as its argument,
+// and RemoveMissionCommand and StartMissionCommand take the mission name. The
+// other commands take no argument.
+const (
+	GetMissionsCommand        = "get_missions"
+	AddMissionCommand         = "add_mission"
+	RemoveMissionCommand      = "remove_mission"
+	StartMissionCommand       = "start_mission"
+	StopMissionCommand        = "stop_mission"
+	GetMissionProgressCommand = "get_mission_progress"
+)
+
+// MissionMode describes what a mission does once it reaches its last waypoint.
+type MissionMode string
+
+// The known mission modes.
+const (
+	// MissionModeOnce missions are done at their last waypoint.
+	MissionModeOnce = MissionMode("once")
+	// MissionModeLoop missions go from their last waypoint back to their first, and around again.
+	MissionModeLoop = MissionMode("loop")
+	// MissionModePatrol missions go back through their waypoints in reverse order, and then forward again.
+	MissionModePatrol = MissionMode("patrol")
+)
+
+// A Mission is a named, ordered list of waypoints that can be saved and run again later.
+type Mission struct {
+	Name      string      `json:"name" bson:"_id"`
+	Mode      MissionMode `json:"mode" bson:"mode"`
+	Waypoints []GeoVertex `json:"waypoints" bson:"waypoints"`
+}
+
+// Validate ensures all parts of the mission are valid.
+func (m *Mission) Validate() error {
+	if m.Name == "" {
+		return errors.New("mission must have a name")
+	}
+	switch m.Mode {
+	case MissionModeOnce, MissionModeLoop, MissionModePatrol:
+	default:
+		return errors.Errorf("unknown mission mode %q", m.Mode)
+	}
+	if len(m.Waypoints) == 0 {
+		return errors.Errorf("mission %q has no waypoints", m.Name)
+	}
+	return nil
+}
+
+// MissionProgress reports how far through a mission the robot is.
+type MissionProgress struct {
+	Mission string      `json:"mission"`
+	Mode    MissionMode `json:"mode"`
+	// WaypointIndex is the index in the mission of the waypoint being navigated to.
+	WaypointIndex int `json:"waypoint_index"`
+	// WaypointsReached counts every waypoint reached since the mission started.
+	WaypointsReached int `json:"waypoints_reached"`
+	// Laps counts how many times a loop mission has come back to its first waypoint, or a patrol mission has come
+	// back along its waypoints.
+	Laps int  `json:"laps"`
+	Done bool `json:"done"`
+	// Reverse is set while a patrol mission is going back through its waypoints.
+	Reverse bool `json:"reverse"`
+}
+
+// NewMissionProgress returns the progress of a mission that has just started.
+func NewMissionProgress(m Mission) MissionProgress {
+	return MissionProgress{Mission: m.Name, Mode: m.Mode}
+}
+
+// Target returns the waypoint being navigated to.
+func (p *MissionProgress) Target(m Mission) (Waypoint, error) {
+	if p.Done {
+		return Waypoint{}, errNoMoreWaypoints
+	}
+	if p.WaypointIndex >= len(m.Waypoints) {
+		return Waypoint{}, errors.Errorf("mission %q has no waypoint %d", m.Name, p.WaypointIndex)
+	}
+	v := m.Waypoints[p.WaypointIndex]
+	return Waypoint{Order: p.WaypointIndex, Lat: v.Latitude, Long: v.Longitude}, nil
+}
+
+// Advance moves on from the waypoint just reached to the next one of the mission, according to its mode.
+func (p *MissionProgress) Advance(m Mission) {
+	p.WaypointsReached++
+	last := len(m.Waypoints) - 1
+	switch {
+	case m.Mode == MissionModePatrol && p.Reverse:
+		if p.WaypointIndex == 0 {
+			p.Reverse = false
+			p.Laps++
+			if last > 0 {
+				p.WaypointIndex = 1
+			}
+		} else {
+			p.WaypointIndex--
+		}
+	case p.WaypointIndex < last:
+		p.WaypointIndex++
+	case m.Mode == MissionModeLoop:
+		p.WaypointIndex = 0
+		p.Laps++
+	case m.Mode == MissionModePatrol:
+		p.Reverse = true
+		if last > 0 {
+			p.WaypointIndex = last - 1
+		}
+	default:
+		p.Done = true
+	}
+}
+
+// A MissionManager is a navigation service that can save missions and run them.
+type MissionManager interface {
+	// Missions returns every saved mission, sorted by name.
+	Missions(ctx context.Context) ([]Mission, error)
+	// AddMission saves a mission, replacing any mission with the same name.
+	AddMission(ctx context.Context, mission Mission) error
+	// RemoveMission removes the named mission.
+	RemoveMission(ctx context.Context, name string) error
+	// StartMission starts navigating the waypoints of the named mission, instead of the service's own waypoints.
+	StartMission(ctx context.Context, name string) error
+	// StopMission stops the running mission, if any, and goes back to the service's own waypoints.
+	StopMission(ctx context.Context) error
+	// MissionProgress returns the progress of the running mission, or of the last mission run if it is done.
+	MissionProgress(ctx context.Context) (MissionProgress, error)
+}
+
+// DoMissionCommand calls the MissionManager method for any of the mission DoCommands,
+// and returns resource.ErrDoUnimplemented for any other command so that it can be
+// tried before a service's own commands.
+func DoMissionCommand(ctx context.Context, mm MissionManager, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := cmd[GetMissionsCommand]; ok {
+		missions, err := mm.Missions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, 0, len(missions))
+		for _, mission := range missions {
+			m, err := toMap(mission)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, m)
+		}
+		return map[string]interface{}{"missions": out}, nil
+	}
+	if m, ok := cmd[AddMissionCommand].(map[string]interface{}); ok {
+		var mission Mission
+		if err := fromMap(m, &mission); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{}, mm.AddMission(ctx, mission)
+	}
+	if name, ok := cmd[RemoveMissionCommand].(string); ok {
+		return map[string]interface{}{}, mm.RemoveMission(ctx, name)
+	}
+	if name, ok := cmd[StartMissionCommand].(string); ok {
+		return map[string]interface{}{}, mm.StartMission(ctx, name)
+	}
+	if _, ok := cmd[StopMissionCommand]; ok {
+		return map[string]interface{}{}, mm.StopMission(ctx)
+	}
+	if _, ok := cmd[GetMissionProgressCommand]; ok {
+		progress, err := mm.MissionProgress(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return toMap(progress)
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// toMap converts a value into the form used by DoCommand by way of its JSON.
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// fromMap converts the form used by DoCommand back into the value by way of its JSON.
+func fromMap(m map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,198 @@
+package navigation_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/navigation"
+)
+
+func testMission(mode navigation.MissionMode) navigation.Mission {
+	return navigation.Mission{
+		Name:      "rounds",
+		Mode:      mode,
+		Waypoints: []navigation.GeoVertex{{Latitude: 1, Longitude: 1}, {Latitude: 2, Longitude: 2}, {Latitude: 3, Longitude: 3}},
+	}
+}
+
+func visit(t *testing.T, mission navigation.Mission, steps int) ([]int, navigation.MissionProgress) {
+	t.Helper()
+	progress := navigation.NewMissionProgress(mission)
+	var indexes []int
+	for i := 0; i < steps; i++ {
+		wp, err := progress.Target(mission)
+		if err != nil {
+			break
+		}
+		indexes = append(indexes, wp.Order)
+		progress.Advance(mission)
+	}
+	return indexes, progress
+}
+
+func TestMissionProgress(t *testing.T) {
+	t.Run("once", func(t *testing.T) {
+		indexes, progress := visit(t, testMission(navigation.MissionModeOnce), 10)
+		test.That(t, indexes, test.ShouldResemble, []int{0, 1, 2})
+		test.That(t, progress.Done, test.ShouldBeTrue)
+		test.That(t, progress.WaypointsReached, test.ShouldEqual, 3)
+	})
+
+	t.Run("loop", func(t *testing.T) {
+		indexes, progress := visit(t, testMission(navigation.MissionModeLoop), 7)
+		test.That(t, indexes, test.ShouldResemble, []int{0, 1, 2, 0, 1, 2, 0})
+		test.That(t, progress.Done, test.ShouldBeFalse)
+		test.That(t, progress.Laps, test.ShouldEqual, 2)
+	})
+
+	t.Run("patrol", func(t *testing.T) {
+		indexes, progress := visit(t, testMission(navigation.MissionModePatrol), 7)
+		test.That(t, indexes, test.ShouldResemble, []int{0, 1, 2, 1, 0, 1, 2})
+		test.That(t, progress.Done, test.ShouldBeFalse)
+		test.That(t, progress.Laps, test.ShouldEqual, 1)
+	})
+
+	t.Run("patrol of one waypoint", func(t *testing.T) {
+		mission := testMission(navigation.MissionModePatrol)
+		mission.Waypoints = mission.Waypoints[:1]
+		indexes, _ := visit(t, mission, 3)
+		test.That(t, indexes, test.ShouldResemble, []int{0, 0, 0})
+	})
+}
+
+func TestMissionValidate(t *testing.T) {
+	mission := testMission(navigation.MissionModeLoop)
+	test.That(t, mission.Validate(), test.ShouldBeNil)
+	mission.Mode = "forever"
+	test.That(t, mission.Validate(), test.ShouldNotBeNil)
+	mission.Mode = navigation.MissionModeOnce
+	mission.Waypoints = nil
+	test.That(t, mission.Validate(), test.ShouldNotBeNil)
+}
+
+type storeMissionManager struct {
+	store    navigation.NavStore
+	running  string
+	progress navigation.MissionProgress
+}
+
+func (mm *storeMissionManager) Missions(ctx context.Context) ([]navigation.Mission, error) {
+	return mm.store.Missions(ctx)
+}
+
+func (mm *storeMissionManager) AddMission(ctx context.Context, mission navigation.Mission) error {
+	return mm.store.SaveMission(ctx, mission)
+}
+
+func (mm *storeMissionManager) RemoveMission(ctx context.Context, name string) error {
+	return mm.store.RemoveMission(ctx, name)
+}
+
+func (mm *storeMissionManager) StartMission(ctx context.Context, name string) error {
+	mission, err := mm.store.Mission(ctx, name)
+	if err != nil {
+		return err
+	}
+	mm.running = name
+	mm.progress = navigation.NewMissionProgress(mission)
+	return nil
+}
+
+func (mm *storeMissionManager) StopMission(ctx context.Context) error {
+	mm.running = ""
+	return nil
+}
+
+func (mm *storeMissionManager) MissionProgress(ctx context.Context) (navigation.MissionProgress, error) {
+	return mm.progress, nil
+}
+
+func TestDoMissionCommand(t *testing.T) {
+	ctx := context.Background()
+	mm := &storeMissionManager{store: navigation.NewMemoryNavigationStore()}
+	mission := testMission(navigation.MissionModePatrol)
+	missionMap := map[string]interface{}{
+		"name": mission.Name,
+		"mode": string(mission.Mode),
+		"waypoints": []interface{}{
+			map[string]interface{}{"latitude": 1.0, "longitude": 1.0},
+			map[string]interface{}{"latitude": 2.0, "longitude": 2.0},
+			map[string]interface{}{"latitude": 3.0, "longitude": 3.0},
+		},
+	}
+
+	_, err := navigation.DoMissionCommand(ctx, mm, map[string]interface{}{navigation.AddMissionCommand: missionMap})
+	test.That(t, err, test.ShouldBeNil)
+	resp, err := navigation.DoMissionCommand(ctx, mm, map[string]interface{}{navigation.GetMissionsCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["missions"], test.ShouldResemble, []interface{}{missionMap})
+
+	_, err = navigation.DoMissionCommand(ctx, mm, map[string]interface{}{navigation.StartMissionCommand: "rounds"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mm.running, test.ShouldEqual, "rounds")
+	resp, err = navigation.DoMissionCommand(ctx, mm, map[string]interface{}{navigation.GetMissionProgressCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["mission"], test.ShouldEqual, "rounds")
+	test.That(t, resp["mode"], test.ShouldEqual, "patrol")
+	test.That(t, resp["waypoint_index"], test.ShouldEqual, 0.0)
+
+	_, err = navigation.DoMissionCommand(ctx, mm, map[string]interface{}{navigation.StopMissionCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mm.running, test.ShouldBeEmpty)
+
+	_, err = navigation.DoMissionCommand(ctx, mm, map[string]interface{}{navigation.RemoveMissionCommand: "rounds"})
+	test.That(t, err, test.ShouldBeNil)
+	_, err = navigation.DoMissionCommand(ctx, mm, map[string]interface{}{navigation.StartMissionCommand: "rounds"})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	_, err = navigation.DoMissionCommand(ctx, mm, map[string]interface{}{"nope": true})
+	test.That(t, err, test.ShouldBeError, resource.ErrDoUnimplemented)
+}
+
+func TestFileNavigationStore(t *testing.T) {
+	ctx := context.Background()
+	config := map[string]interface{}{"path": filepath.Join(t.TempDir(), "navigation", "store.json")}
+
+	store, err := navigation.NewFileNavigationStore(config)
+	test.That(t, err, test.ShouldBeNil)
+	wp1, err := store.AddWaypoint(ctx, geo.NewPoint(1, 2))
+	test.That(t, err, test.ShouldBeNil)
+	wp2, err := store.AddWaypoint(ctx, geo.NewPoint(3, 4))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, store.WaypointVisited(ctx, wp1.ID), test.ShouldBeNil)
+	test.That(t, store.SaveMission(ctx, testMission(navigation.MissionModeLoop)), test.ShouldBeNil)
+	test.That(t, store.Close(ctx), test.ShouldBeNil)
+
+	t.Run("waypoints and missions survive a restart", func(t *testing.T) {
+		store, err := navigation.NewFileNavigationStore(config)
+		test.That(t, err, test.ShouldBeNil)
+		wps, err := store.Waypoints(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, wps, test.ShouldResemble, []navigation.Waypoint{wp2})
+		next, err := store.NextWaypoint(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, next.ID, test.ShouldEqual, wp2.ID)
+		mission, err := store.Mission(ctx, "rounds")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, mission, test.ShouldResemble, testMission(navigation.MissionModeLoop))
+
+		test.That(t, store.RemoveMission(ctx, "rounds"), test.ShouldBeNil)
+		test.That(t, store.RemoveWaypoint(ctx, wp2.ID), test.ShouldBeNil)
+	})
+
+	t.Run("removals survive a restart", func(t *testing.T) {
+		store, err := navigation.NewFileNavigationStore(config)
+		test.That(t, err, test.ShouldBeNil)
+		wps, err := store.Waypoints(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, wps, test.ShouldBeEmpty)
+		missions, err := store.Missions(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, missions, test.ShouldBeEmpty)
+	})
+}
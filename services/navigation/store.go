@@ -3,6 +3,10 @@ package navigation
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -26,6 +30,15 @@ type NavStore interface {
 	RemoveWaypoint(ctx context.Context, id primitive.ObjectID) error
 	NextWaypoint(ctx context.Context) (Waypoint, error)
 	WaypointVisited(ctx context.Context, id primitive.ObjectID) error
+
+	// Missions are sorted by name.
+	Missions(ctx context.Context) ([]Mission, error)
+	// Mission returns the named mission.
+	Mission(ctx context.Context, name string) (Mission, error)
+	// SaveMission adds the mission, or replaces the mission with the same name.
+	SaveMission(ctx context.Context, mission Mission) error
+	RemoveMission(ctx context.Context, name string) error
+
 	Close(ctx context.Context) error
 }
 
@@ -36,6 +49,8 @@ const (
 	StoreTypeMemory = "memory"
 	// StoreTypeMongoDB is the constant for the mongodb store type.
 	StoreTypeMongoDB = "mongodb"
+	// StoreTypeFile is the constant for the file store type.
+	StoreTypeFile = "file"
 )
 
 // StoreConfig describes how to configure data storage.
@@ -47,7 +62,7 @@ type StoreConfig struct {
 // Validate ensures all parts of the config are valid.
 func (config *StoreConfig) Validate(path string) error {
 	switch config.Type {
-	case StoreTypeMemory, StoreTypeMongoDB:
+	case StoreTypeMemory, StoreTypeMongoDB, StoreTypeFile:
 	default:
 		return errors.Errorf("unknown store type %q", config.Type)
 	}
@@ -56,11 +71,11 @@ func (config *StoreConfig) Validate(path string) error {
 
 // A Waypoint designates a location within a path to navigate to.
 type Waypoint struct {
-	ID      primitive.ObjectID `bson:"_id"`
-	Visited bool               `bson:"visited"`
-	Order   int                `bson:"order"`
-	Lat     float64            `bson:"latitude"`
-	Long    float64            `bson:"longitude"`
+	ID      primitive.ObjectID `bson:"_id" json:"id"`
+	Visited bool               `bson:"visited" json:"visited"`
+	Order   int                `bson:"order" json:"order"`
+	Lat     float64            `bson:"latitude" json:"latitude"`
+	Long    float64            `bson:"longitude" json:"longitude"`
 }
 
 // ToPoint converts the waypoint to a geo.Point.
@@ -77,6 +92,7 @@ func NewMemoryNavigationStore() *MemoryNavigationStore {
 type MemoryNavigationStore struct {
 	mu        sync.RWMutex
 	waypoints []*Waypoint
+	missions  map[string]Mission
 }
 
 // Waypoints returns a copy of all of the waypoints in the MemoryNavigationStore.
@@ -147,16 +163,174 @@ func (store *MemoryNavigationStore) WaypointVisited(ctx context.Context, id prim
 	return nil
 }
 
+// Missions returns all of the missions in the MemoryNavigationStore.
+func (store *MemoryNavigationStore) Missions(ctx context.Context) ([]Mission, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	missions := make([]Mission, 0, len(store.missions))
+	for _, mission := range store.missions {
+		missions = append(missions, mission)
+	}
+	sort.Slice(missions, func(i, j int) bool {
+		return missions[i].Name < missions[j].Name
+	})
+	return missions, nil
+}
+
+// Mission returns the named mission in the MemoryNavigationStore.
+func (store *MemoryNavigationStore) Mission(ctx context.Context, name string) (Mission, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	mission, ok := store.missions[name]
+	if !ok {
+		return Mission{}, errors.Errorf("no mission named %q", name)
+	}
+	return mission, nil
+}
+
+// SaveMission adds a mission to the MemoryNavigationStore, replacing the mission with the same name.
+func (store *MemoryNavigationStore) SaveMission(ctx context.Context, mission Mission) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.missions == nil {
+		store.missions = map[string]Mission{}
+	}
+	store.missions[mission.Name] = mission
+	return nil
+}
+
+// RemoveMission removes a mission from the MemoryNavigationStore.
+func (store *MemoryNavigationStore) RemoveMission(ctx context.Context, name string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.missions, name)
+	return nil
+}
+
 // Close does nothing.
 func (store *MemoryNavigationStore) Close(ctx context.Context) error {
 	return nil
 }
 
+// defaultFileNavStorePath is where a FileNavigationStore is saved unless its config gives a "path".
+var defaultFileNavStorePath = filepath.Join(os.Getenv("HOME"), ".viam", "navigation", "store.json")
+
+// fileNavStoreContents is the form a FileNavigationStore is saved in.
+type fileNavStoreContents struct {
+	Waypoints []*Waypoint `json:"waypoints"`
+	Missions  []Mission   `json:"missions"`
+}
+
+// NewFileNavigationStore returns a store that keeps its waypoints and missions in memory, and saves them to a JSON
+// file after every change so that they survive restarts. The file is loaded if it already exists.
+func NewFileNavigationStore(config map[string]interface{}) (*FileNavigationStore, error) {
+	path, ok := config["path"].(string)
+	if !ok {
+		path = defaultFileNavStorePath
+	}
+	store := &FileNavigationStore{path: path}
+
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var contents fileNavStoreContents
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return nil, errors.Wrapf(err, "failed to read navigation store %q", path)
+	}
+	store.waypoints = contents.Waypoints
+	store.missions = map[string]Mission{}
+	for _, mission := range contents.Missions {
+		store.missions[mission.Name] = mission
+	}
+	return store, nil
+}
+
+// FileNavigationStore is a MemoryNavigationStore that is saved to a file.
+type FileNavigationStore struct {
+	MemoryNavigationStore
+	path   string
+	saveMu sync.Mutex
+}
+
+// AddWaypoint adds a waypoint to the FileNavigationStore.
+func (store *FileNavigationStore) AddWaypoint(ctx context.Context, point *geo.Point) (Waypoint, error) {
+	wp, err := store.MemoryNavigationStore.AddWaypoint(ctx, point)
+	if err != nil {
+		return Waypoint{}, err
+	}
+	return wp, store.save()
+}
+
+// RemoveWaypoint removes a waypoint from the FileNavigationStore.
+func (store *FileNavigationStore) RemoveWaypoint(ctx context.Context, id primitive.ObjectID) error {
+	if err := store.MemoryNavigationStore.RemoveWaypoint(ctx, id); err != nil {
+		return err
+	}
+	return store.save()
+}
+
+// WaypointVisited sets that a waypoint has been visited.
+func (store *FileNavigationStore) WaypointVisited(ctx context.Context, id primitive.ObjectID) error {
+	if err := store.MemoryNavigationStore.WaypointVisited(ctx, id); err != nil {
+		return err
+	}
+	return store.save()
+}
+
+// SaveMission adds a mission to the FileNavigationStore, replacing the mission with the same name.
+func (store *FileNavigationStore) SaveMission(ctx context.Context, mission Mission) error {
+	if err := store.MemoryNavigationStore.SaveMission(ctx, mission); err != nil {
+		return err
+	}
+	return store.save()
+}
+
+// RemoveMission removes a mission from the FileNavigationStore.
+func (store *FileNavigationStore) RemoveMission(ctx context.Context, name string) error {
+	if err := store.MemoryNavigationStore.RemoveMission(ctx, name); err != nil {
+		return err
+	}
+	return store.save()
+}
+
+// save writes the store to a temporary file and then moves it into place, so that the file is never left half
+// written.
+func (store *FileNavigationStore) save() error {
+	store.saveMu.Lock()
+	defer store.saveMu.Unlock()
+
+	missions, err := store.Missions(context.Background())
+	if err != nil {
+		return err
+	}
+	store.mu.RLock()
+	data, err := json.Marshal(fileNavStoreContents{Waypoints: store.waypoints, Missions: missions})
+	store.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(store.path), 0o700); err != nil {
+		return err
+	}
+	tmp := store.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, store.path)
+}
+
 // Database and collection names used by the MongoDBNavigationStore.
 var (
 	defaultMongoDBURI                = "mongodb://127.0.0.1:27017"
 	MongoDBNavStoreDBName            = "navigation"
 	MongoDBNavStoreWaypointsCollName = "waypoints"
+	MongoDBNavStoreMissionsCollName  = "missions"
 	mongoDBNavStoreIndexes           = []mongo.IndexModel{
 		{
 			Keys: bson.D{
@@ -193,13 +367,15 @@ func NewMongoDBNavigationStore(ctx context.Context, config map[string]interface{
 	return &MongoDBNavigationStore{
 		mongoClient:   mongoClient,
 		waypointsColl: waypoints,
+		missionsColl:  mongoClient.Database(MongoDBNavStoreDBName).Collection(MongoDBNavStoreMissionsCollName),
 	}, nil
 }
 
-// MongoDBNavigationStore holds the mongodb client and waypoints collection.
+// MongoDBNavigationStore holds the mongodb client and the waypoints and missions collections.
 type MongoDBNavigationStore struct {
 	mongoClient   *mongo.Client
 	waypointsColl *mongo.Collection
+	missionsColl  *mongo.Collection
 }
 
 // Close closes the connection with the mongodb client.
@@ -269,3 +445,40 @@ func (store *MongoDBNavigationStore) WaypointVisited(ctx context.Context, id pri
 	_, err := store.waypointsColl.UpdateOne(ctx, bson.D{{"_id", id}}, bson.D{{"$set", bson.D{{"visited", true}}}})
 	return err
 }
+
+// Missions returns all the missions in the MongoDBNavigationStore.
+func (store *MongoDBNavigationStore) Missions(ctx context.Context) ([]Mission, error) {
+	cursor, err := store.missionsColl.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var all []Mission
+	if err := cursor.All(ctx, &all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// Mission returns the named mission in the MongoDBNavigationStore.
+func (store *MongoDBNavigationStore) Mission(ctx context.Context, name string) (Mission, error) {
+	var mission Mission
+	if err := store.missionsColl.FindOne(ctx, bson.D{{"_id", name}}).Decode(&mission); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return Mission{}, errors.Errorf("no mission named %q", name)
+		}
+		return Mission{}, err
+	}
+	return mission, nil
+}
+
+// SaveMission adds a mission to the MongoDBNavigationStore, replacing the mission with the same name.
+func (store *MongoDBNavigationStore) SaveMission(ctx context.Context, mission Mission) error {
+	_, err := store.missionsColl.ReplaceOne(ctx, bson.D{{"_id", mission.Name}}, mission, options.Replace().SetUpsert(true))
+	return err
+}
+
+// RemoveMission removes a mission from the MongoDBNavigationStore.
+func (store *MongoDBNavigationStore) RemoveMission(ctx context.Context, name string) error {
+	_, err := store.missionsColl.DeleteOne(ctx, bson.D{{"_id", name}})
+	return err
+}
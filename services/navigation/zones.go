@@ -0,0 +1,223 @@
+package navigation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+)
+
+// The DoCommand keys used to edit the zones of a remote navigation service.
+// AddZoneCommand takes the zone, in the form of its JSON config, as its argument
+// and RemoveZoneCommand takes the zone name. GetZonesCommand takes no argument.
+const (
+	GetZonesCommand   = "get_zones"
+	AddZoneCommand    = "add_zone"
+	RemoveZoneCommand = "remove_zone"
+)
+
+// ZoneType describes whether the robot must stay out of or inside a zone.
+type ZoneType string
+
+// The known zone types.
+const (
+	ZoneTypeKeepOut = ZoneType("keep_out")
+	// While there are keep in zones, the robot must stay inside at least one of them.
+	ZoneTypeKeepIn = ZoneType("keep_in")
+)
+
+// A MapVertex is a point in the map frame of the navigation service, in mm.
+type MapVertex struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// A GeoVertex is a point on the globe.
+type GeoVertex struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// A Zone is a polygon the robot must keep out of or inside, given either in the map frame or as GPS coordinates.
+type Zone struct {
+	Name       string      `json:"name"`
+	Type       ZoneType    `json:"type"`
+	Polygon    []MapVertex `json:"polygon,omitempty"`
+	GeoPolygon []GeoVertex `json:"geo_polygon,omitempty"`
+}
+
+// Validate ensures all parts of the zone are valid.
+func (z *Zone) Validate(path string) error {
+	if z.Name == "" {
+		return utils.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	if z.Type != ZoneTypeKeepOut && z.Type != ZoneTypeKeepIn {
+		return utils.NewConfigValidationError(path, errors.Errorf("unknown zone type %q", z.Type))
+	}
+	if (len(z.Polygon) == 0) == (len(z.GeoPolygon) == 0) {
+		return utils.NewConfigValidationError(path, errors.New("exactly one of polygon and geo_polygon must be set"))
+	}
+	if len(z.Polygon) > 0 && len(z.Polygon) < 3 || len(z.GeoPolygon) > 0 && len(z.GeoPolygon) < 3 {
+		return utils.NewConfigValidationError(path, errors.New("a zone polygon needs at least 3 vertices"))
+	}
+	return nil
+}
+
+// ToMap converts the zone into the form used by DoCommand.
+func (z Zone) ToMap() (map[string]interface{}, error) {
+	return toMap(z)
+}
+
+// ZoneFromMap converts the form used by DoCommand back into the zone, and validates it.
+func ZoneFromMap(m map[string]interface{}) (Zone, error) {
+	var z Zone
+	if err := fromMap(m, &z); err != nil {
+		return Zone{}, err
+	}
+	if err := z.Validate("zone"); err != nil {
+		return Zone{}, err
+	}
+	return z, nil
+}
+
+// A ZoneEditor is a navigation service whose zones can be changed while it runs.
+type ZoneEditor interface {
+	// Zones returns the zones the service currently keeps to.
+	Zones(ctx context.Context) ([]Zone, error)
+	// AddZone adds a zone, replacing any zone with the same name.
+	AddZone(ctx context.Context, zone Zone) error
+	// RemoveZone removes the named zone.
+	RemoveZone(ctx context.Context, name string) error
+}
+
+// DoZoneCommand calls the ZoneEditor method for a "get_zones", "add_zone" or
+// "remove_zone" DoCommand, and returns resource.ErrDoUnimplemented for any other
+// command so that it can be tried before a service's own commands.
+func DoZoneCommand(ctx context.Context, ze ZoneEditor, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := cmd[GetZonesCommand]; ok {
+		zones, err := ze.Zones(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, 0, len(zones))
+		for _, zone := range zones {
+			m, err := zone.ToMap()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, m)
+		}
+		return map[string]interface{}{"zones": out}, nil
+	}
+	if m, ok := cmd[AddZoneCommand].(map[string]interface{}); ok {
+		zone, err := ZoneFromMap(m)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{}, ze.AddZone(ctx, zone)
+	}
+	if name, ok := cmd[RemoveZoneCommand].(string); ok {
+		return map[string]interface{}{}, ze.RemoveZone(ctx, name)
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// A Geofence checks points and paths against a set of zones, in the map frame. Zones given as GPS coordinates are
+// placed in the map frame around its origin, as described by motion.GeoPointToLocal.
+type Geofence struct {
+	keepOut map[string][]r3.Vector
+	keepIn  map[string][]r3.Vector
+}
+
+// NewGeofence returns a geofence of the given zones, with the map frame's origin at the given point on the globe.
+func NewGeofence(zones []Zone, origin *geo.Point) *Geofence {
+	g := &Geofence{keepOut: map[string][]r3.Vector{}, keepIn: map[string][]r3.Vector{}}
+	for _, zone := range zones {
+		var polygon []r3.Vector
+		for _, v := range zone.Polygon {
+			polygon = append(polygon, r3.Vector{X: v.X, Y: v.Y})
+		}
+		for _, v := range zone.GeoPolygon {
+			polygon = append(polygon, motion.GeoPointToLocal(origin, geo.NewPoint(v.Latitude, v.Longitude)))
+		}
+		if zone.Type == ZoneTypeKeepIn {
+			g.keepIn[zone.Name] = polygon
+		} else {
+			g.keepOut[zone.Name] = polygon
+		}
+	}
+	return g
+}
+
+// CheckPoint returns an error if the point is in a keep out zone, or outside every keep in zone.
+func (g *Geofence) CheckPoint(p r3.Vector) error {
+	for name, polygon := range g.keepOut {
+		if polygonContains(polygon, p) {
+			return fmt.Errorf("%v is in keep out zone %q", p, name)
+		}
+	}
+	if len(g.keepIn) == 0 {
+		return nil
+	}
+	for _, polygon := range g.keepIn {
+		if polygonContains(polygon, p) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%v is outside every keep in zone", p)
+}
+
+// CheckSegment returns an error if the straight line between the points enters a keep out zone, or does not stay
+// inside a single keep in zone.
+func (g *Geofence) CheckSegment(a, b r3.Vector) error {
+	for name, polygon := range g.keepOut {
+		if polygonContains(polygon, a) || polygonContains(polygon, b) || polygonCrosses(polygon, a, b) {
+			return fmt.Errorf("path from %v to %v enters keep out zone %q", a, b, name)
+		}
+	}
+	if len(g.keepIn) == 0 {
+		return nil
+	}
+	for _, polygon := range g.keepIn {
+		if polygonContains(polygon, a) && polygonContains(polygon, b) && !polygonCrosses(polygon, a, b) {
+			return nil
+		}
+	}
+	return fmt.Errorf("path from %v to %v leaves the keep in zones", a, b)
+}
+
+// polygonContains reports whether the point is inside the polygon, by counting how many of its edges a ray from the
+// point crosses.
+func polygonContains(polygon []r3.Vector, p r3.Vector) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// polygonCrosses reports whether the segment between a and b properly crosses an edge of the polygon.
+func polygonCrosses(polygon []r3.Vector, a, b r3.Vector) bool {
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		if segmentsCross(a, b, polygon[i], polygon[j]) {
+			return true
+		}
+	}
+	return false
+}
+
+func segmentsCross(a, b, c, d r3.Vector) bool {
+	side := func(p, q, r r3.Vector) float64 {
+		return (q.X-p.X)*(r.Y-p.Y) - (q.Y-p.Y)*(r.X-p.X)
+	}
+	return side(a, b, c)*side(a, b, d) < 0 && side(c, d, a)*side(c, d, b) < 0
+}
@@ -0,0 +1,134 @@
+package navigation_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/navigation"
+)
+
+type zoneList struct {
+	zones []navigation.Zone
+}
+
+func (zl *zoneList) Zones(ctx context.Context) ([]navigation.Zone, error) {
+	return zl.zones, nil
+}
+
+func (zl *zoneList) AddZone(ctx context.Context, zone navigation.Zone) error {
+	zl.zones = append(zl.zones, zone)
+	return nil
+}
+
+func (zl *zoneList) RemoveZone(ctx context.Context, name string) error {
+	for i, z := range zl.zones {
+		if z.Name == name {
+			zl.zones = append(zl.zones[:i], zl.zones[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("no such zone")
+}
+
+func square(min, max float64) []navigation.MapVertex {
+	return []navigation.MapVertex{{X: min, Y: min}, {X: max, Y: min}, {X: max, Y: max}, {X: min, Y: max}}
+}
+
+func TestZoneValidate(t *testing.T) {
+	zone := navigation.Zone{Name: "pond", Type: navigation.ZoneTypeKeepOut, Polygon: square(0, 10)}
+	test.That(t, zone.Validate("path"), test.ShouldBeNil)
+
+	zone.Type = "keep_near"
+	test.That(t, zone.Validate("path"), test.ShouldNotBeNil)
+	zone.Type = navigation.ZoneTypeKeepIn
+
+	zone.GeoPolygon = []navigation.GeoVertex{{Latitude: 1, Longitude: 2}, {Latitude: 3, Longitude: 4}, {Latitude: 5, Longitude: 6}}
+	test.That(t, zone.Validate("path"), test.ShouldNotBeNil)
+	zone.Polygon = nil
+	test.That(t, zone.Validate("path"), test.ShouldBeNil)
+
+	zone.GeoPolygon = zone.GeoPolygon[:2]
+	test.That(t, zone.Validate("path"), test.ShouldNotBeNil)
+}
+
+func TestGeofence(t *testing.T) {
+	origin := geo.NewPoint(40, -74)
+	corner := origin.PointAtDistanceAndBearing(0.001, 45)
+	geofence := navigation.NewGeofence([]navigation.Zone{
+		{Name: "pond", Type: navigation.ZoneTypeKeepOut, Polygon: square(400, 600)},
+		{Name: "yard", Type: navigation.ZoneTypeKeepIn, GeoPolygon: []navigation.GeoVertex{
+			{Latitude: origin.Lat(), Longitude: origin.Lng()},
+			{Latitude: origin.Lat(), Longitude: corner.Lng()},
+			{Latitude: corner.Lat(), Longitude: corner.Lng()},
+			{Latitude: corner.Lat(), Longitude: origin.Lng()},
+		}},
+	}, origin)
+	yardCorner := motion.GeoPointToLocal(origin, corner)
+
+	test.That(t, geofence.CheckPoint(r3.Vector{X: 100, Y: 100}), test.ShouldBeNil)
+	test.That(t, geofence.CheckPoint(r3.Vector{X: 500, Y: 500}), test.ShouldNotBeNil)
+	test.That(t, geofence.CheckPoint(r3.Vector{X: -100, Y: 100}), test.ShouldNotBeNil)
+	test.That(t, geofence.CheckPoint(yardCorner.Add(r3.Vector{X: 10, Y: 10})), test.ShouldNotBeNil)
+
+	t.Run("segments through keep out zones are violations", func(t *testing.T) {
+		test.That(t, geofence.CheckSegment(r3.Vector{X: 100, Y: 100}, r3.Vector{X: 100, Y: 600}), test.ShouldBeNil)
+		test.That(t, geofence.CheckSegment(r3.Vector{X: 100, Y: 100}, r3.Vector{X: 650, Y: 650}), test.ShouldNotBeNil)
+		test.That(t, geofence.CheckSegment(r3.Vector{X: 100, Y: 500}, r3.Vector{X: 650, Y: 500}), test.ShouldNotBeNil)
+	})
+
+	t.Run("segments leaving keep in zones are violations", func(t *testing.T) {
+		test.That(t, geofence.CheckSegment(r3.Vector{X: 100, Y: 100}, r3.Vector{X: -100, Y: 100}), test.ShouldNotBeNil)
+	})
+
+	t.Run("costmaps route around zones", func(t *testing.T) {
+		costmap, err := navigation.NewCostmap(r3.Vector{X: -200, Y: -200}, r3.Vector{X: 1000, Y: 1000}, 10)
+		test.That(t, err, test.ShouldBeNil)
+		costmap.AddGeofence(geofence)
+		costmap.Inflate(20, 20)
+		test.That(t, costmap.Cost(r3.Vector{X: 505, Y: 505}), test.ShouldEqual, navigation.CostLethal)
+		test.That(t, costmap.Cost(r3.Vector{X: -105, Y: 105}), test.ShouldEqual, navigation.CostLethal)
+
+		start, goal := r3.Vector{X: 300, Y: 300}, r3.Vector{X: 650, Y: 650}
+		path, err := costmap.PlanPath(start, goal)
+		test.That(t, err, test.ShouldBeNil)
+		for i := 1; i < len(path); i++ {
+			test.That(t, geofence.CheckSegment(path[i-1], path[i]), test.ShouldBeNil)
+		}
+	})
+}
+
+func TestDoZoneCommand(t *testing.T) {
+	ctx := context.Background()
+	zones := &zoneList{}
+	zone := navigation.Zone{Name: "pond", Type: navigation.ZoneTypeKeepOut, Polygon: square(0, 10)}
+	m, err := zone.ToMap()
+	test.That(t, err, test.ShouldBeNil)
+
+	_, err = navigation.DoZoneCommand(ctx, zones, map[string]interface{}{navigation.AddZoneCommand: m})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, zones.zones, test.ShouldResemble, []navigation.Zone{zone})
+
+	resp, err := navigation.DoZoneCommand(ctx, zones, map[string]interface{}{navigation.GetZonesCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["zones"], test.ShouldHaveLength, 1)
+	got, err := navigation.ZoneFromMap(resp["zones"].([]interface{})[0].(map[string]interface{}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, got, test.ShouldResemble, zone)
+
+	_, err = navigation.DoZoneCommand(ctx, zones, map[string]interface{}{navigation.RemoveZoneCommand: "pond"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, zones.zones, test.ShouldBeEmpty)
+
+	_, err = navigation.DoZoneCommand(ctx, zones, map[string]interface{}{navigation.AddZoneCommand: map[string]interface{}{"name": "pond"}})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	_, err = navigation.DoZoneCommand(ctx, zones, map[string]interface{}{"nope": true})
+	test.That(t, err, test.ShouldBeError, resource.ErrDoUnimplemented)
+}
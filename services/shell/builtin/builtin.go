@@ -13,6 +13,7 @@ import (
 	"github.com/creack/pty"
 	"github.com/edaniels/golog"
 	"go.viam.com/utils"
+	"golang.org/x/term"
 
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/shell"
@@ -43,6 +44,15 @@ type builtIn struct {
 }
 
 func (svc *builtIn) Shell(ctx context.Context, extra map[string]interface{}) (chan<- string, <-chan shell.Output, error) {
+	input, output, err := svc.Terminal(ctx, extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	return shell.DataInput(ctx, input), output, nil
+}
+
+// Terminal starts an interactive shell in a pseudo-terminal, resizing it and switching its mode as the input asks.
+func (svc *builtIn) Terminal(ctx context.Context, extra map[string]interface{}) (chan<- shell.Input, <-chan shell.Output, error) {
 	if runtime.GOOS == "windows" {
 		return nil, nil, errors.New("shell not supported on windows yet; sorry")
 	}
@@ -76,7 +86,8 @@ func (svc *builtIn) Shell(ctx context.Context, extra map[string]interface{}) (ch
 		}
 	})
 
-	input := make(chan string)
+	var modes terminalModes
+	input := make(chan shell.Input)
 	output := make(chan shell.Output)
 
 	utils.PanicCapturingGo(func() {
@@ -114,9 +125,22 @@ func (svc *builtIn) Shell(ctx context.Context, extra map[string]interface{}) (ch
 	utils.PanicCapturingGo(func() {
 		for {
 			select {
-			case inputData, ok := <-input:
+			case in, ok := <-input:
 				if ok {
-					if _, err := f.Write([]byte(inputData)); err != nil {
+					if in.Resize != nil {
+						if err := pty.Setsize(f, &pty.Winsize{Rows: in.Resize.Rows, Cols: in.Resize.Cols}); err != nil {
+							svc.logger.Errorw("error resizing terminal", "error", err)
+						}
+					}
+					if in.Mode != "" {
+						if err := modes.set(f, in.Mode); err != nil {
+							svc.logger.Errorw("error setting terminal mode", "error", err, "mode", in.Mode)
+						}
+					}
+					if in.Data == "" {
+						continue
+					}
+					if _, err := f.Write([]byte(in.Data)); err != nil {
 						svc.logger.Errorw("error writing data", "error", err)
 						return
 					}
@@ -130,8 +154,8 @@ func (svc *builtIn) Shell(ctx context.Context, extra map[string]interface{}) (ch
 			case <-ctx.Done():
 				if _, err := f.Write([]byte{4}); err != nil {
 					svc.logger.Errorw("error writing EOT", "error", err)
-					return
 				}
+				return
 			}
 		}
 	})
@@ -139,6 +163,36 @@ func (svc *builtIn) Shell(ctx context.Context, extra map[string]interface{}) (ch
 	return input, output, nil
 }
 
+// terminalModes switches a pseudo-terminal between raw and cooked modes, remembering the state it started in so that cooked mode can
+// restore it.
+type terminalModes struct {
+	cooked *term.State
+}
+
+func (m *terminalModes) set(f *os.File, mode shell.TerminalMode) error {
+	fd := int(f.Fd())
+	if m.cooked == nil {
+		state, err := term.GetState(fd)
+		if err != nil {
+			return err
+		}
+		m.cooked = state
+	}
+	if mode == shell.TerminalModeRaw {
+		_, err := term.MakeRaw(fd)
+		return err
+	}
+	return term.Restore(fd, m.cooked)
+}
+
+// DoCommand handles the file transfer commands of the shell package, which read and write files on the robot.
+func (svc *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, ok, err := shell.DoFileCommand(cmd); ok {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
 func (svc *builtIn) Close(ctx context.Context) error {
 	svc.activeBackgroundWorkers.Wait()
 	return nil
@@ -46,6 +46,15 @@ func NewClientFromConn(
 }
 
 func (c *client) Shell(ctx context.Context, extra map[string]interface{}) (chan<- string, <-chan Output, error) {
+	input, output, err := c.Terminal(ctx, extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	return DataInput(ctx, input), output, nil
+}
+
+// Terminal starts a shell like Shell does, sending the window size and mode changes of its input in the extra of the requests.
+func (c *client) Terminal(ctx context.Context, extra map[string]interface{}) (chan<- Input, <-chan Output, error) {
 	ext, err := protoutils.StructToStructPb(extra)
 	if err != nil {
 		return nil, nil, err
@@ -63,7 +72,7 @@ func (c *client) Shell(ctx context.Context, extra map[string]interface{}) (chan<
 		return nil, nil, err
 	}
 
-	input := make(chan string)
+	input := make(chan Input)
 	output := make(chan Output)
 
 	utils.PanicCapturingGo(func() {
@@ -71,12 +80,17 @@ func (c *client) Shell(ctx context.Context, extra map[string]interface{}) (chan<
 
 		for {
 			select {
-			case dataIn, ok := <-input:
+			case in, ok := <-input:
 				if ok {
+					inExt, err := protoutils.StructToStructPb(in.extra())
+					if err != nil {
+						c.logger.Errorw("error encoding input", "error", err)
+						return
+					}
 					if err := client.Send(&pb.ShellRequest{
 						Name:   c.name,
-						DataIn: dataIn,
-						Extra:  ext,
+						DataIn: in.Data,
+						Extra:  inExt,
 					}); err != nil {
 						c.logger.Errorw("error sending data", "error", err)
 						return
@@ -1,8 +1,12 @@
 package shell_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"net"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/edaniels/golog"
@@ -16,7 +20,20 @@ import (
 	"go.viam.com/rdk/testutils/inject"
 )
 
-var testSvcName1 = shell.Named("shell1")
+var (
+	testSvcName1 = shell.Named("shell1")
+	testSvcName2 = shell.Named("shell2")
+)
+
+// terminalShell is a shell service that also runs terminals, recording the input sent to them.
+type terminalShell struct {
+	*inject.ShellService
+	inputs chan shell.Input
+}
+
+func (s *terminalShell) Terminal(ctx context.Context, extra map[string]interface{}) (chan<- shell.Input, <-chan shell.Output, error) {
+	return s.inputs, make(chan shell.Output), nil
+}
 
 func TestClient(t *testing.T) {
 	logger := golog.NewTestLogger(t)
@@ -26,8 +43,10 @@ func TestClient(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 
 	injectShell := &inject.ShellService{}
+	terminal := &terminalShell{ShellService: &inject.ShellService{}, inputs: make(chan shell.Input)}
 	ssMap := map[resource.Name]shell.Service{
 		testSvcName1: injectShell,
+		testSvcName2: terminal,
 	}
 	svc, err := resource.NewAPIResourceCollection(shell.API, ssMap)
 	test.That(t, err, test.ShouldBeNil)
@@ -57,4 +76,102 @@ func TestClient(t *testing.T) {
 		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
+
+	t.Run("file transfer", func(t *testing.T) {
+		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		client, err := shell.NewClientFromConn(context.Background(), conn, "", testSvcName1, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		injectShell.DoCommandFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			resp, ok, err := shell.DoFileCommand(cmd)
+			test.That(t, ok, test.ShouldBeTrue)
+			return resp, err
+		}
+		dir := t.TempDir()
+		contents := bytes.Repeat([]byte("a log line\n"), 1000)
+		test.That(t, os.WriteFile(filepath.Join(dir, "robot.log"), contents, 0o600), test.ShouldBeNil)
+
+		var progress []shell.FileTransferProgress
+		err = shell.DownloadFile(context.Background(), client, filepath.Join(dir, "robot.log"), filepath.Join(dir, "downloaded.log"), 4096,
+			func(p shell.FileTransferProgress) { progress = append(progress, p) })
+		test.That(t, err, test.ShouldBeNil)
+		downloaded, err := os.ReadFile(filepath.Join(dir, "downloaded.log"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, downloaded, test.ShouldResemble, contents)
+		test.That(t, progress, test.ShouldHaveLength, 3)
+		test.That(t, progress[2].Transferred, test.ShouldEqual, len(contents))
+		test.That(t, progress[2].Size, test.ShouldEqual, len(contents))
+
+		progress = nil
+		err = shell.UploadFile(context.Background(), client, filepath.Join(dir, "robot.log"), filepath.Join(dir, "uploaded.log"), 4096,
+			func(p shell.FileTransferProgress) { progress = append(progress, p) })
+		test.That(t, err, test.ShouldBeNil)
+		uploaded, err := os.ReadFile(filepath.Join(dir, "uploaded.log"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, uploaded, test.ShouldResemble, contents)
+		test.That(t, progress, test.ShouldHaveLength, 3)
+		_, err = os.Stat(filepath.Join(dir, "uploaded.log.part"))
+		test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+
+		// empty files are transferred too
+		test.That(t, os.WriteFile(filepath.Join(dir, "empty"), nil, 0o600), test.ShouldBeNil)
+		err = shell.UploadFile(context.Background(), client, filepath.Join(dir, "empty"), filepath.Join(dir, "uploaded-empty"), 0, nil)
+		test.That(t, err, test.ShouldBeNil)
+		uploaded, err = os.ReadFile(filepath.Join(dir, "uploaded-empty"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, uploaded, test.ShouldHaveLength, 0)
+
+		// a chunk that does not match its checksum fails the transfer and leaves nothing behind
+		injectShell.DoCommandFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			resp, _, err := shell.DoFileCommand(cmd)
+			if err == nil {
+				resp["data"] = base64.StdEncoding.EncodeToString([]byte("corrupted"))
+			}
+			return resp, err
+		}
+		err = shell.DownloadFile(context.Background(), client, filepath.Join(dir, "robot.log"), filepath.Join(dir, "corrupted.log"), 0, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "checksum")
+		_, err = os.Stat(filepath.Join(dir, "corrupted.log"))
+		test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+		_, err = os.Stat(filepath.Join(dir, "corrupted.log.part"))
+		test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+
+		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	})
+
+	t.Run("terminal", func(t *testing.T) {
+		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		client, err := shell.NewClientFromConn(context.Background(), conn, "", testSvcName2, logger)
+		test.That(t, err, test.ShouldBeNil)
+		terminalClient, ok := client.(shell.TerminalService)
+		test.That(t, ok, test.ShouldBeTrue)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		input, _, err := terminalClient.Terminal(ctx, map[string]interface{}{
+			"window_size": map[string]interface{}{"rows": 24, "cols": 80},
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		// the starting size comes from the extra of the first request
+		test.That(t, <-terminal.inputs, test.ShouldResemble, shell.Input{Resize: &shell.WindowSize{Rows: 24, Cols: 80}})
+
+		for _, in := range []shell.Input{
+			{Resize: &shell.WindowSize{Rows: 50, Cols: 132}},
+			{Mode: shell.TerminalModeRaw},
+			{Data: "vi\n"},
+			{Data: ":q\n", Mode: shell.TerminalModeCooked},
+		} {
+			input <- in
+			test.That(t, <-terminal.inputs, test.ShouldResemble, in)
+		}
+
+		cancel()
+		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	})
 }
@@ -0,0 +1,329 @@
+package shell
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+const (
+	// DownloadFileCommand is the DoCommand key used to read a chunk of a file on the robot. Its argument is a map with the "path" of
+	// the file, the "offset" to read from and the most "bytes" to read. It returns the "data" read, base64 encoded, along with its
+	// "sha256" checksum, the "size" of the whole file and whether the chunk reaches the end of the file as "eof". The last chunk also
+	// returns the checksum of the whole file as "file_sha256".
+	DownloadFileCommand = "download_file"
+
+	// UploadFileCommand is the DoCommand key used to write a chunk of a file to the robot. Its argument is a map with the "path" to
+	// write to, the "offset" of the chunk and its "data", base64 encoded, along with its "sha256" checksum. Chunks are written to a
+	// temporary file next to the path, which replaces any file at the path once the last chunk, marked "final" and sent with the
+	// checksum of the whole file as "file_sha256", has arrived.
+	UploadFileCommand = "upload_file"
+
+	// DefaultFileChunkSize is how many bytes of a file are sent in each DoCommand when none is given.
+	DefaultFileChunkSize = 256 * 1024
+
+	// maxFileChunkSize keeps each chunk well within the message size limit of the API.
+	maxFileChunkSize = 2 * 1024 * 1024
+
+	// partialFileSuffix is added to the path of a file while it is being transferred.
+	partialFileSuffix = ".part"
+)
+
+// FileTransferProgress reports how much of a file has been transferred.
+type FileTransferProgress struct {
+	Path        string
+	Transferred int64
+	Size        int64
+}
+
+// DownloadFile copies the file at remotePath on the robot of the shell service to localPath, chunkSize bytes at a time, or
+// DefaultFileChunkSize if chunkSize is not positive. Each chunk and the whole file are checked against their checksums, and localPath
+// is only replaced once the whole file has arrived. progress, if not nil, is called after each chunk.
+func DownloadFile(
+	ctx context.Context,
+	svc Service,
+	remotePath, localPath string,
+	chunkSize int,
+	progress func(FileTransferProgress),
+) error {
+	if chunkSize <= 0 {
+		chunkSize = DefaultFileChunkSize
+	}
+	partial := localPath + partialFileSuffix
+	//nolint:gosec
+	f, err := os.Create(partial)
+	if err != nil {
+		return err
+	}
+	err = downloadFileTo(ctx, svc, remotePath, f, chunkSize, progress)
+	if err = multierr.Combine(err, f.Close()); err != nil {
+		return multierr.Combine(err, os.Remove(partial))
+	}
+	return os.Rename(partial, localPath)
+}
+
+func downloadFileTo(
+	ctx context.Context,
+	svc Service,
+	remotePath string,
+	w io.Writer,
+	chunkSize int,
+	progress func(FileTransferProgress),
+) error {
+	hash := sha256.New()
+	var offset int64
+	for {
+		resp, err := svc.DoCommand(ctx, map[string]interface{}{
+			DownloadFileCommand: map[string]interface{}{"path": remotePath, "offset": offset, "bytes": chunkSize},
+		})
+		if err != nil {
+			return err
+		}
+		data, err := decodeFileChunk(resp)
+		if err != nil {
+			return errors.Wrapf(err, "chunk of %q at offset %d", remotePath, offset)
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		hash.Write(data)
+		offset += int64(len(data))
+		size, _ := number(resp["size"])
+		if progress != nil {
+			progress(FileTransferProgress{Path: remotePath, Transferred: offset, Size: size})
+		}
+		if eof, _ := resp["eof"].(bool); eof {
+			if fileSum, _ := resp["file_sha256"].(string); fileSum != hex.EncodeToString(hash.Sum(nil)) {
+				return errors.Errorf("checksum of %q does not match, it may have changed during the download", remotePath)
+			}
+			return nil
+		}
+		if len(data) == 0 {
+			return errors.Errorf("download of %q stopped at offset %d before the end of the file", remotePath, offset)
+		}
+	}
+}
+
+// UploadFile copies the file at localPath to remotePath on the robot of the shell service, chunkSize bytes at a time, or
+// DefaultFileChunkSize if chunkSize is not positive. Each chunk and the whole file are checked against their checksums on the robot,
+// and remotePath is only replaced once the whole file has arrived. progress, if not nil, is called after each chunk.
+func UploadFile(
+	ctx context.Context,
+	svc Service,
+	localPath, remotePath string,
+	chunkSize int,
+	progress func(FileTransferProgress),
+) (retErr error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultFileChunkSize
+	}
+	//nolint:gosec
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = multierr.Combine(retErr, f.Close())
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	buf := make([]byte, chunkSize)
+	var offset int64
+	for {
+		n, err := io.ReadFull(f, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+		chunk := buf[:n]
+		hash.Write(chunk)
+		final := offset+int64(n) >= info.Size() || n < chunkSize
+		args := encodeFileChunk(chunk)
+		args["path"] = remotePath
+		args["offset"] = offset
+		if final {
+			args["final"] = true
+			args["file_sha256"] = hex.EncodeToString(hash.Sum(nil))
+		}
+		if _, err := svc.DoCommand(ctx, map[string]interface{}{UploadFileCommand: args}); err != nil {
+			return err
+		}
+		offset += int64(n)
+		if progress != nil {
+			progress(FileTransferProgress{Path: localPath, Transferred: offset, Size: info.Size()})
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+// DoDownloadFileCommand reads the chunk of a file asked for by a "download_file" DoCommand.
+func DoDownloadFileCommand(args interface{}) (map[string]interface{}, error) {
+	m, _ := args.(map[string]interface{})
+	path, _ := m["path"].(string)
+	if path == "" {
+		return nil, errors.Errorf("%s needs a path", DownloadFileCommand)
+	}
+	offset, _ := number(m["offset"])
+	size, ok := number(m["bytes"])
+	if !ok || size <= 0 {
+		size = DefaultFileChunkSize
+	}
+	if size > maxFileChunkSize {
+		size = maxFileChunkSize
+	}
+
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 || offset > info.Size() {
+		return nil, errors.Errorf("offset %d is outside of %q, which is %d bytes", offset, path, info.Size())
+	}
+	data := make([]byte, size)
+	n, err := f.ReadAt(data, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	resp := encodeFileChunk(data[:n])
+	resp["size"] = info.Size()
+	resp["eof"] = offset+int64(n) >= info.Size()
+	if offset+int64(n) >= info.Size() {
+		sum, err := fileChecksum(path)
+		if err != nil {
+			return nil, err
+		}
+		resp["file_sha256"] = sum
+	}
+	return resp, nil
+}
+
+// DoUploadFileCommand writes the chunk of a file sent by an "upload_file" DoCommand.
+func DoUploadFileCommand(args interface{}) (map[string]interface{}, error) {
+	m, _ := args.(map[string]interface{})
+	path, _ := m["path"].(string)
+	if path == "" {
+		return nil, errors.Errorf("%s needs a path", UploadFileCommand)
+	}
+	offset, _ := number(m["offset"])
+	data, err := decodeFileChunk(m)
+	if err != nil {
+		return nil, err
+	}
+
+	partial := path + partialFileSuffix
+	flags := os.O_WRONLY | os.O_APPEND
+	if offset == 0 {
+		// the first chunk starts the file over, discarding any earlier transfer that did not finish
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	//nolint:gosec
+	f, err := os.OpenFile(partial, flags, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err == nil && info.Size() != offset {
+		err = errors.Errorf("chunk of %q is at offset %d but %d bytes have arrived", path, offset, info.Size())
+	}
+	if err == nil {
+		_, err = f.Write(data)
+	}
+	if err = multierr.Combine(err, f.Close()); err != nil {
+		return nil, err
+	}
+
+	if final, _ := m["final"].(bool); final {
+		sum, err := fileChecksum(partial)
+		if err != nil {
+			return nil, err
+		}
+		if expected, _ := m["file_sha256"].(string); sum != expected {
+			return nil, multierr.Combine(errors.Errorf("checksum of uploaded %q does not match", path), os.Remove(partial))
+		}
+		if err := os.Rename(partial, path); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]interface{}{"size": offset + int64(len(data))}, nil
+}
+
+// DoFileCommand handles the file transfer DoCommands, returning false if cmd is not one of them.
+func DoFileCommand(cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	if args, ok := cmd[DownloadFileCommand]; ok {
+		resp, err := DoDownloadFileCommand(args)
+		return resp, true, err
+	}
+	if args, ok := cmd[UploadFileCommand]; ok {
+		resp, err := DoUploadFileCommand(args)
+		return resp, true, err
+	}
+	return nil, false, nil
+}
+
+func encodeFileChunk(data []byte) map[string]interface{} {
+	sum := sha256.Sum256(data)
+	return map[string]interface{}{
+		"data":   base64.StdEncoding.EncodeToString(data),
+		"sha256": hex.EncodeToString(sum[:]),
+	}
+}
+
+// decodeFileChunk returns the data of a chunk, checking it against its checksum.
+func decodeFileChunk(m map[string]interface{}) ([]byte, error) {
+	encoded, _ := m["data"].(string)
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid file chunk data")
+	}
+	sum := sha256.Sum256(data)
+	if expected, _ := m["sha256"].(string); hex.EncodeToString(sum[:]) != expected {
+		return nil, errors.New("file chunk checksum does not match")
+	}
+	return data, nil
+}
+
+func fileChecksum(path string) (string, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	//nolint:errcheck
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func number(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int64(n), true
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
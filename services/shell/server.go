@@ -34,7 +34,19 @@ func (server *serviceServer) Shell(srv pb.ShellService_ShellServer) (retErr erro
 	if err != nil {
 		return err
 	}
-	input, output, err := svc.Shell(srv.Context(), req.Extra.AsMap())
+	var (
+		input  chan<- Input
+		output <-chan Output
+	)
+	if terminal, ok := svc.(TerminalService); ok {
+		input, output, err = terminal.Terminal(srv.Context(), req.Extra.AsMap())
+	} else {
+		var data chan<- string
+		data, output, err = svc.Shell(srv.Context(), req.Extra.AsMap())
+		if err == nil {
+			input = shellInput(srv.Context(), data)
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -64,8 +76,10 @@ func (server *serviceServer) Shell(srv pb.ShellService_ShellServer) (retErr erro
 				return
 			}
 
+			in := inputFromExtra(req.Extra.AsMap())
+			in.Data = req.DataIn
 			select {
-			case input <- req.DataIn:
+			case input <- in:
 			case <-outDone:
 				close(input)
 				return
@@ -102,6 +116,34 @@ func (server *serviceServer) Shell(srv pb.ShellService_ShellServer) (retErr erro
 	}
 }
 
+// shellInput returns an input channel for a shell that only takes data, which drops changes of window size and mode that carry no
+// data. The data channel is closed once the returned channel is closed or ctx is done.
+func shellInput(ctx context.Context, data chan<- string) chan<- Input {
+	input := make(chan Input)
+	goutils.PanicCapturingGo(func() {
+		defer close(data)
+		for {
+			select {
+			case in, ok := <-input:
+				if !ok {
+					return
+				}
+				if in.Data == "" && (in.Resize != nil || in.Mode != "") {
+					continue
+				}
+				select {
+				case data <- in.Data:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	return input
+}
+
 // DoCommand receives arbitrary commands.
 func (server *serviceServer) DoCommand(ctx context.Context,
 	req *commonpb.DoCommandRequest,
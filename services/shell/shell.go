@@ -5,6 +5,7 @@ import (
 	"context"
 
 	servicepb "go.viam.com/api/service/shell/v1"
+	"go.viam.com/utils"
 
 	"go.viam.com/rdk/resource"
 )
@@ -31,6 +32,99 @@ type Output struct {
 	EOF    bool
 }
 
+// A TerminalService is a shell service whose shells run in a terminal that follows the size of the window it is shown in and can be
+// switched between raw and cooked modes, so that full-screen programs such as editors work. Over the API, window size and mode changes
+// are carried in the extra of the shell requests, so the extra given to Terminal may also set the starting size and mode.
+type TerminalService interface {
+	Terminal(ctx context.Context, extra map[string]interface{}) (input chan<- Input, output <-chan Output, retErr error)
+}
+
+// Input is an instance of input to a terminal, which may carry data typed into it, a new size of its window, a new mode, or any of
+// these together.
+type Input struct {
+	Data   string
+	Resize *WindowSize
+	Mode   TerminalMode
+}
+
+// WindowSize is the size of a terminal window in characters.
+type WindowSize struct {
+	Rows uint16
+	Cols uint16
+}
+
+// TerminalMode is how a terminal handles input. In cooked mode input is echoed and edited a line at a time before it reaches the
+// program, while in raw mode every key goes straight to the program.
+type TerminalMode string
+
+// The terminal modes.
+const (
+	TerminalModeCooked = TerminalMode("cooked")
+	TerminalModeRaw    = TerminalMode("raw")
+)
+
+// The keys of the extra of a shell request that carry the window size and terminal mode of an Input.
+const (
+	windowSizeKey   = "window_size"
+	terminalModeKey = "terminal_mode"
+)
+
+// inputFromExtra returns the window size and terminal mode given in the extra of a shell request as an Input with no data.
+func inputFromExtra(extra map[string]interface{}) Input {
+	var in Input
+	if size, ok := extra[windowSizeKey].(map[string]interface{}); ok {
+		rows, okRows := size["rows"].(float64)
+		cols, okCols := size["cols"].(float64)
+		if okRows && okCols && rows > 0 && cols > 0 {
+			in.Resize = &WindowSize{Rows: uint16(rows), Cols: uint16(cols)}
+		}
+	}
+	if mode, ok := extra[terminalModeKey].(string); ok {
+		switch TerminalMode(mode) {
+		case TerminalModeCooked, TerminalModeRaw:
+			in.Mode = TerminalMode(mode)
+		}
+	}
+	return in
+}
+
+// extra returns the window size and terminal mode of the input in the form read by inputFromExtra.
+func (in Input) extra() map[string]interface{} {
+	extra := map[string]interface{}{}
+	if in.Resize != nil {
+		extra[windowSizeKey] = map[string]interface{}{"rows": float64(in.Resize.Rows), "cols": float64(in.Resize.Cols)}
+	}
+	if in.Mode != "" {
+		extra[terminalModeKey] = string(in.Mode)
+	}
+	return extra
+}
+
+// DataInput returns a channel whose data is sent on to a terminal's input channel, for using a terminal as a plain shell. The input
+// channel is closed once the returned channel is closed or ctx is done.
+func DataInput(ctx context.Context, input chan<- Input) chan<- string {
+	data := make(chan string)
+	utils.PanicCapturingGo(func() {
+		defer close(input)
+		for {
+			select {
+			case d, ok := <-data:
+				if !ok {
+					return
+				}
+				select {
+				case input <- Input{Data: d}:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	return data
+}
+
 // SubtypeName is the name of the type of service.
 const SubtypeName = "shell"
 
@@ -4,10 +4,12 @@ import (
 	"context"
 
 	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
 	pb "go.viam.com/api/service/slam/v1"
 	"go.viam.com/utils/rpc"
 
+	"go.viam.com/rdk/pointcloud"
 	rprotoutils "go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/slam/grpchelper"
@@ -80,6 +82,79 @@ func (c *client) GetInternalState(ctx context.Context) (func() ([]byte, error),
 	return grpchelper.GetInternalStateCallback(ctx, c.name, c.client)
 }
 
+// SaveMap is sent through DoCommand until the SLAM service API has an RPC for it.
+func (c *client) SaveMap(ctx context.Context, name string) (MapInfo, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{SaveMapCommand: name})
+	if err != nil {
+		return MapInfo{}, err
+	}
+	return MapInfoFromMap(resp)
+}
+
+// ListMaps is sent through DoCommand until the SLAM service API has an RPC for it.
+func (c *client) ListMaps(ctx context.Context) ([]MapInfo, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{ListMapsCommand: true})
+	if err != nil {
+		return nil, err
+	}
+	maps, _ := resp["maps"].([]interface{})
+	infos := make([]MapInfo, 0, len(maps))
+	for _, m := range maps {
+		infoMap, ok := m.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("invalid map info in list_maps response")
+		}
+		info, err := MapInfoFromMap(infoMap)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// LoadMap is sent through DoCommand until the SLAM service API has an RPC for it.
+func (c *client) LoadMap(ctx context.Context, name string, version int) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{LoadMapCommand: name, "version": version})
+	return err
+}
+
+// Mode is sent through DoCommand until the SLAM service API has an RPC for it.
+func (c *client) Mode(ctx context.Context) (Mode, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{GetModeCommand: true})
+	if err != nil {
+		return "", err
+	}
+	mode, ok := resp["mode"].(string)
+	if !ok {
+		return "", errors.New("get_mode response has no mode")
+	}
+	return Mode(mode), nil
+}
+
+// MapUpdates polls DoCommand for map updates until the SLAM service API has a streaming RPC for them.
+func (c *client) MapUpdates(ctx context.Context) (<-chan MapUpdate, error) {
+	return StreamMapUpdates(ctx, c.logger, DefaultMapUpdatePeriod, func(ctx context.Context, seq int64) ([]MapUpdate, error) {
+		resp, err := c.DoCommand(ctx, map[string]interface{}{MapUpdatesCommand: seq})
+		if err != nil {
+			return nil, err
+		}
+		return mapUpdatesFromResponse(resp)
+	}), nil
+}
+
+// OccupancyGrid is built by the SLAM service and sent through DoCommand until the SLAM service API has an RPC for it.
+// Any SLAM service's map can be made into a grid on the client side with GetOccupancyGrid.
+func (c *client) OccupancyGrid(ctx context.Context, resolution float64, is2D bool) (*pointcloud.OccupancyGrid, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		OccupancyGridCommand: map[string]interface{}{"resolution_mm": resolution, "3d": !is2D},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return occupancyGridFromResponse(resp)
+}
+
 func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	ctx, span := trace.StartSpan(ctx, "slam::client::DoCommand")
 	defer span.End()
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
 
@@ -84,6 +86,18 @@ func fakeGetInternalState(_ context.Context, datasetDir string, slamSvc *SLAM) (
 	return f, nil
 }
 
+// chunkedCallback returns a callback function which will return the next chunk of data when called, like a streamed response.
+func chunkedCallback(data []byte) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		if len(data) == 0 {
+			return nil, io.EOF
+		}
+		chunk := data[:int(math.Min(float64(len(data)), chunkSizeBytes))]
+		data = data[len(chunk):]
+		return chunk, nil
+	}
+}
+
 func fakeGetPosition(_ context.Context, datasetDir string, slamSvc *SLAM) (spatialmath.Pose, string, error) {
 	path := filepath.Clean(artifact.MustPath(fmt.Sprintf(positionTemplate, datasetDir, slamSvc.getCount())))
 	slamSvc.logger.Debug("Reading " + path)
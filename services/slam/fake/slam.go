@@ -3,10 +3,14 @@ package fake
 
 import (
 	"context"
+	"sync"
 
 	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
+	"go.viam.com/utils"
 
+	"go.viam.com/rdk/pointcloud"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/slam"
 	"go.viam.com/rdk/spatialmath"
@@ -20,19 +24,60 @@ func init() {
 	resource.RegisterService(
 		slam.API,
 		model,
-		resource.Registration[slam.Service, resource.NoNativeConfig]{
+		resource.Registration[slam.Service, *Config]{
 			Constructor: func(
 				ctx context.Context,
 				_ resource.Dependencies,
 				conf resource.Config,
 				logger golog.Logger,
 			) (slam.Service, error) {
-				return NewSLAM(conf.ResourceName(), logger), nil
+				svcConfig, err := resource.NativeConfig[*Config](conf)
+				if err != nil {
+					return nil, err
+				}
+				slamSvc := NewSLAM(conf.ResourceName(), logger)
+				if svcConfig.MapDirectory != "" {
+					slamSvc.maps = slam.NewMapStore(svcConfig.MapDirectory)
+				}
+				if svcConfig.Map != "" {
+					if err := slamSvc.LoadMap(ctx, svcConfig.Map, svcConfig.MapVersion); err != nil {
+						return nil, err
+					}
+				}
+				if svcConfig.LocalizationOnly {
+					slamSvc.mode = slam.LocalizingMode
+				}
+				return slamSvc, nil
 			},
 		},
 	)
 }
 
+// Config describes how to configure the fake slam service.
+type Config struct {
+	// MapDirectory is where maps are saved, slam.DefaultMapDirectory if unset.
+	MapDirectory string `json:"map_directory"`
+	// Map names a saved map to start from, at MapVersion or at its latest version if MapVersion is 0.
+	Map        string `json:"map"`
+	MapVersion int    `json:"map_version"`
+	// LocalizationOnly keeps the map fixed, so that only the pose is estimated. It needs a map to start from.
+	LocalizationOnly bool `json:"localization_only"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (c *Config) Validate(path string) ([]string, error) {
+	if c.MapVersion < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("map_version cannot be negative"))
+	}
+	if c.MapVersion > 0 && c.Map == "" {
+		return nil, utils.NewConfigValidationError(path, errors.New("map_version needs a map"))
+	}
+	if c.LocalizationOnly && c.Map == "" {
+		return nil, utils.NewConfigValidationError(path, errors.New("localization_only needs a map to localize against"))
+	}
+	return nil, nil
+}
+
 // SLAM is a fake slam that returns generic data.
 type SLAM struct {
 	resource.Named
@@ -40,15 +85,26 @@ type SLAM struct {
 	resource.TriviallyCloseable
 	dataCount int
 	logger    golog.Logger
+	mode      slam.Mode
+
+	maps    *slam.MapStore
+	updates *slam.MapUpdateTracker
+	mu      sync.Mutex
+	// loadedMap is the internal state of the map the fake was started from, if any, which it returns in place of the test data.
+	loadedMap []byte
 }
 
 // NewSLAM is a constructor for a fake slam service.
 func NewSLAM(name resource.Name, logger golog.Logger) *SLAM {
-	return &SLAM{
+	slamSvc := &SLAM{
 		Named:     name.AsNamed(),
 		logger:    logger,
 		dataCount: -1,
+		mode:      slam.MappingMode,
+		maps:      slam.NewMapStore(slam.DefaultMapDirectory),
 	}
+	slamSvc.updates = slam.NewMapUpdateTracker(slamSvc)
+	return slamSvc
 }
 
 func (slamSvc *SLAM) getCount() int {
@@ -66,11 +122,13 @@ func (slamSvc *SLAM) GetPosition(ctx context.Context) (spatialmath.Pose, string,
 }
 
 // GetPointCloudMap returns a callback function which will return the next chunk of the current pointcloud
-// map.
+// map. The map only changes between calls when mapping.
 func (slamSvc *SLAM) GetPointCloudMap(ctx context.Context) (func() ([]byte, error), error) {
 	ctx, span := trace.StartSpan(ctx, "slam::fake::GetPointCloudMap")
 	defer span.End()
-	slamSvc.incrementDataCount()
+	if slamSvc.mode != slam.LocalizingMode {
+		slamSvc.incrementDataCount()
+	}
 	return fakeGetPointCloudMap(ctx, datasetDirectory, slamSvc)
 }
 
@@ -79,9 +137,74 @@ func (slamSvc *SLAM) GetPointCloudMap(ctx context.Context) (func() ([]byte, erro
 func (slamSvc *SLAM) GetInternalState(ctx context.Context) (func() ([]byte, error), error) {
 	ctx, span := trace.StartSpan(ctx, "slam::fake::GetInternalState")
 	defer span.End()
+	slamSvc.mu.Lock()
+	loadedMap := slamSvc.loadedMap
+	slamSvc.mu.Unlock()
+	if loadedMap != nil {
+		return chunkedCallback(loadedMap), nil
+	}
 	return fakeGetInternalState(ctx, datasetDirectory, slamSvc)
 }
 
+// SaveMap saves the current internal state as a new version of the named map.
+func (slamSvc *SLAM) SaveMap(ctx context.Context, name string) (slam.MapInfo, error) {
+	ctx, span := trace.StartSpan(ctx, "slam::fake::SaveMap")
+	defer span.End()
+	internalState, err := slam.GetInternalStateFull(ctx, slamSvc)
+	if err != nil {
+		return slam.MapInfo{}, err
+	}
+	return slamSvc.maps.Save(name, internalState)
+}
+
+// ListMaps returns every saved version of every map.
+func (slamSvc *SLAM) ListMaps(ctx context.Context) ([]slam.MapInfo, error) {
+	return slamSvc.maps.List()
+}
+
+// LoadMap starts the fake again from a saved map, whose internal state it then returns.
+func (slamSvc *SLAM) LoadMap(ctx context.Context, name string, version int) error {
+	internalState, info, err := slamSvc.maps.Load(name, version)
+	if err != nil {
+		return err
+	}
+	slamSvc.logger.Infof("starting from version %d of map %q", info.Version, info.Name)
+	slamSvc.mu.Lock()
+	defer slamSvc.mu.Unlock()
+	slamSvc.loadedMap = internalState
+	return nil
+}
+
+// MapUpdates streams the changes to the map as the fake advances through its test data.
+func (slamSvc *SLAM) MapUpdates(ctx context.Context) (<-chan slam.MapUpdate, error) {
+	return slam.StreamMapUpdates(ctx, slamSvc.logger, slam.DefaultMapUpdatePeriod, slamSvc.updates.Fetch), nil
+}
+
+// Mode returns whether the fake is mapping or only localizing.
+func (slamSvc *SLAM) Mode(ctx context.Context) (slam.Mode, error) {
+	return slamSvc.mode, nil
+}
+
+// OccupancyGrid returns the current map as an occupancy grid.
+func (slamSvc *SLAM) OccupancyGrid(ctx context.Context, resolution float64, is2D bool) (*pointcloud.OccupancyGrid, error) {
+	return slam.GetOccupancyGrid(ctx, slamSvc, resolution, is2D)
+}
+
+// DoCommand supports the "save_map", "list_maps", "load_map", "get_mode", "get_map_updates" and
+// "get_occupancy_grid" commands.
+func (slamSvc *SLAM) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if args, ok := cmd[slam.OccupancyGridCommand]; ok {
+		return slam.DoOccupancyGridCommand(ctx, slamSvc, args)
+	}
+	if args, ok := cmd[slam.MapUpdatesCommand]; ok {
+		return slam.DoMapUpdatesCommand(ctx, slamSvc.updates, args)
+	}
+	if _, ok := cmd[slam.GetModeCommand]; ok {
+		return map[string]interface{}{"mode": string(slamSvc.mode)}, nil
+	}
+	return slam.DoMapCommand(ctx, slamSvc, cmd)
+}
+
 // incrementDataCount is not thread safe but that is ok as we only intend a single user to be interacting
 // with it at a time.
 func (slamSvc *SLAM) incrementDataCount() {
@@ -186,3 +186,47 @@ func helperConcatenateChunksToFull(f func() ([]byte, error)) ([]byte, error) {
 		fullBytes = append(fullBytes, chunk...)
 	}
 }
+
+func TestFakeSLAMMaps(t *testing.T) {
+	ctx := context.Background()
+	slamSvc := NewSLAM(slam.Named("test"), golog.NewTestLogger(t))
+	slamSvc.maps = slam.NewMapStore(t.TempDir())
+
+	info, err := slamSvc.SaveMap(ctx, "office")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, info.Version, test.ShouldEqual, 1)
+	maps, err := slamSvc.ListMaps(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, maps, test.ShouldResemble, []slam.MapInfo{info})
+
+	saved := getDataFromStream(t, slamSvc.GetInternalState)
+	test.That(t, slamSvc.LoadMap(ctx, "office", 0), test.ShouldBeNil)
+	test.That(t, getDataFromStream(t, slamSvc.GetInternalState), test.ShouldResemble, saved)
+	test.That(t, slamSvc.LoadMap(ctx, "lab", 0), test.ShouldNotBeNil)
+
+	resp, err := slamSvc.DoCommand(ctx, map[string]interface{}{slam.ListMapsCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["maps"], test.ShouldHaveLength, 1)
+}
+
+func TestFakeSLAMLocalizationOnly(t *testing.T) {
+	ctx := context.Background()
+	slamSvc := NewSLAM(slam.Named("test"), golog.NewTestLogger(t))
+	mode, err := slamSvc.Mode(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mode, test.ShouldEqual, slam.MappingMode)
+
+	slamSvc.mode = slam.LocalizingMode
+	resp, err := slamSvc.DoCommand(ctx, map[string]interface{}{slam.GetModeCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["mode"], test.ShouldEqual, string(slam.LocalizingMode))
+
+	// the map does not change while localizing
+	data := getDataFromStream(t, slamSvc.GetPointCloudMap)
+	test.That(t, getDataFromStream(t, slamSvc.GetPointCloudMap), test.ShouldResemble, data)
+
+	_, err = (&Config{LocalizationOnly: true}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = (&Config{LocalizationOnly: true, Map: "office"}).Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+}
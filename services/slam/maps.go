@@ -0,0 +1,274 @@
+package slam
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The DoCommand keys used to manage the saved maps of a remote SLAM service.
+// SaveMapCommand and LoadMapCommand take the map name as their argument, and a
+// LoadMapCommand may also give the map version under a "version" key.
+// ListMapsCommand takes no argument.
+const (
+	SaveMapCommand  = "save_map"
+	ListMapsCommand = "list_maps"
+	LoadMapCommand  = "load_map"
+)
+
+// DefaultMapDirectory is where SLAM services save their maps unless configured otherwise.
+var DefaultMapDirectory = filepath.Join(os.Getenv("HOME"), ".viam", "slam", "maps")
+
+const mapFileExt = ".map"
+
+// MapInfo describes a saved version of a map.
+type MapInfo struct {
+	Name string
+	// Version counts up from 1 each time a map is saved under the same name.
+	Version   int
+	SavedAt   time.Time
+	SizeBytes int64
+}
+
+// ToMap converts the info into the form returned by DoCommand.
+func (m MapInfo) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"name":       m.Name,
+		"version":    m.Version,
+		"saved_at":   m.SavedAt.Format(time.RFC3339Nano),
+		"size_bytes": m.SizeBytes,
+	}
+}
+
+// MapInfoFromMap converts the form returned by DoCommand back into the info.
+func MapInfoFromMap(m map[string]interface{}) (MapInfo, error) {
+	name, ok := m["name"].(string)
+	if !ok {
+		return MapInfo{}, errors.New("map info has no name")
+	}
+	version, ok := number(m["version"])
+	if !ok {
+		return MapInfo{}, errors.New("map info has no version")
+	}
+	savedAt, _ := m["saved_at"].(string)
+	t, err := time.Parse(time.RFC3339Nano, savedAt)
+	if err != nil {
+		return MapInfo{}, err
+	}
+	size, _ := number(m["size_bytes"])
+	return MapInfo{Name: name, Version: int(version), SavedAt: t, SizeBytes: size}, nil
+}
+
+// number reads an integer from a DoCommand map, where it is a float64 if it was sent over the network.
+func number(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int64(n), true
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// A MapManager is a SLAM service that can save its map and start again from a
+// saved map, so that a robot can localize against a map it built earlier
+// instead of mapping again every time it starts.
+type MapManager interface {
+	// SaveMap saves the current internal state of the SLAM algorithm as a new
+	// version of the named map.
+	SaveMap(ctx context.Context, name string) (MapInfo, error)
+	// ListMaps returns every saved version of every map, sorted by name and then
+	// version.
+	ListMaps(ctx context.Context) ([]MapInfo, error)
+	// LoadMap restarts the SLAM algorithm from the given version of the named
+	// map, or from its latest version if version is 0.
+	LoadMap(ctx context.Context, name string, version int) error
+}
+
+// DoMapCommand calls the MapManager method for a "save_map", "list_maps" or
+// "load_map" DoCommand, and returns resource.ErrDoUnimplemented for any other
+// command so that it can be tried before a service's own commands.
+func DoMapCommand(ctx context.Context, mm MapManager, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if name, ok := cmd[SaveMapCommand].(string); ok {
+		info, err := mm.SaveMap(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return info.ToMap(), nil
+	}
+	if _, ok := cmd[ListMapsCommand]; ok {
+		infos, err := mm.ListMaps(ctx)
+		if err != nil {
+			return nil, err
+		}
+		maps := make([]interface{}, 0, len(infos))
+		for _, info := range infos {
+			maps = append(maps, info.ToMap())
+		}
+		return map[string]interface{}{"maps": maps}, nil
+	}
+	if name, ok := cmd[LoadMapCommand].(string); ok {
+		version, _ := number(cmd["version"])
+		return map[string]interface{}{}, mm.LoadMap(ctx, name, int(version))
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// A MapStore keeps saved maps as files in a directory, with a subdirectory for
+// each map name holding a file for each version. The contents of a map are the
+// internal state of whichever SLAM algorithm saved it.
+type MapStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewMapStore returns a store of the maps in the given directory, which is
+// created when the first map is saved.
+func NewMapStore(dir string) *MapStore {
+	return &MapStore{dir: dir}
+}
+
+// Save writes the internal state as a new version of the named map.
+func (s *MapStore) Save(name string, internalState []byte) (MapInfo, error) {
+	if err := validateMapName(name); err != nil {
+		return MapInfo{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := s.versions(name)
+	if err != nil {
+		return MapInfo{}, err
+	}
+	version := 1
+	if len(versions) > 0 {
+		version = versions[len(versions)-1] + 1
+	}
+	if err := os.MkdirAll(filepath.Join(s.dir, name), 0o700); err != nil {
+		return MapInfo{}, err
+	}
+	path := s.path(name, version)
+	if err := os.WriteFile(path, internalState, 0o600); err != nil {
+		return MapInfo{}, err
+	}
+	return s.info(name, version)
+}
+
+// List returns every saved version of every map, sorted by name and then version.
+func (s *MapStore) List() ([]MapInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var infos []MapInfo
+	// ReadDir sorts entries by name
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		versions, err := s.versions(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range versions {
+			info, err := s.info(entry.Name(), version)
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
+
+// Load reads the given version of the named map, or its latest version if version is 0.
+func (s *MapStore) Load(name string, version int) ([]byte, MapInfo, error) {
+	if err := validateMapName(name); err != nil {
+		return nil, MapInfo{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if version == 0 {
+		versions, err := s.versions(name)
+		if err != nil {
+			return nil, MapInfo{}, err
+		}
+		if len(versions) == 0 {
+			return nil, MapInfo{}, errors.Errorf("no map named %q", name)
+		}
+		version = versions[len(versions)-1]
+	}
+	//nolint:gosec
+	internalState, err := os.ReadFile(s.path(name, version))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, MapInfo{}, errors.Errorf("no version %d of map %q", version, name)
+	}
+	if err != nil {
+		return nil, MapInfo{}, err
+	}
+	info, err := s.info(name, version)
+	if err != nil {
+		return nil, MapInfo{}, err
+	}
+	return internalState, info, nil
+}
+
+func (s *MapStore) path(name string, version int) string {
+	return filepath.Join(s.dir, name, fmt.Sprintf("%d%s", version, mapFileExt))
+}
+
+func (s *MapStore) info(name string, version int) (MapInfo, error) {
+	stat, err := os.Stat(s.path(name, version))
+	if err != nil {
+		return MapInfo{}, err
+	}
+	return MapInfo{Name: name, Version: version, SavedAt: stat.ModTime(), SizeBytes: stat.Size()}, nil
+}
+
+// versions returns the saved versions of the named map in ascending order. It must be called with mu held.
+func (s *MapStore) versions(name string) ([]int, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var versions []int
+	for _, entry := range entries {
+		version, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), mapFileExt))
+		if err != nil || entry.IsDir() || !strings.HasSuffix(entry.Name(), mapFileExt) {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+func validateMapName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return errors.Errorf("invalid map name %q", name)
+	}
+	return nil
+}
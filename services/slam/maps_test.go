@@ -0,0 +1,112 @@
+package slam_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/slam"
+)
+
+func TestMapStore(t *testing.T) {
+	store := slam.NewMapStore(t.TempDir())
+
+	maps, err := store.List()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, maps, test.ShouldBeEmpty)
+
+	info, err := store.Save("office", []byte{1, 2, 3})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, info.Name, test.ShouldEqual, "office")
+	test.That(t, info.Version, test.ShouldEqual, 1)
+	test.That(t, info.SizeBytes, test.ShouldEqual, 3)
+	info, err = store.Save("office", []byte{4, 5})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, info.Version, test.ShouldEqual, 2)
+	_, err = store.Save("lab", []byte{6})
+	test.That(t, err, test.ShouldBeNil)
+
+	maps, err = store.List()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, maps, test.ShouldHaveLength, 3)
+	test.That(t, maps[0].Name, test.ShouldEqual, "lab")
+	test.That(t, maps[1].Name, test.ShouldEqual, "office")
+	test.That(t, maps[1].Version, test.ShouldEqual, 1)
+	test.That(t, maps[2].Version, test.ShouldEqual, 2)
+
+	t.Run("the latest version is loaded by default", func(t *testing.T) {
+		internalState, info, err := store.Load("office", 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, internalState, test.ShouldResemble, []byte{4, 5})
+		test.That(t, info.Version, test.ShouldEqual, 2)
+	})
+
+	t.Run("an earlier version can be loaded", func(t *testing.T) {
+		internalState, _, err := store.Load("office", 1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, internalState, test.ShouldResemble, []byte{1, 2, 3})
+	})
+
+	t.Run("missing maps are an error", func(t *testing.T) {
+		_, _, err := store.Load("office", 3)
+		test.That(t, err, test.ShouldNotBeNil)
+		_, _, err = store.Load("garage", 0)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("map names cannot be paths", func(t *testing.T) {
+		_, err := store.Save("../office", []byte{1})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, _, err = store.Load("..", 0)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+type storeMapManager struct {
+	store  *slam.MapStore
+	loaded slam.MapInfo
+}
+
+func (mm *storeMapManager) SaveMap(ctx context.Context, name string) (slam.MapInfo, error) {
+	return mm.store.Save(name, []byte("state"))
+}
+
+func (mm *storeMapManager) ListMaps(ctx context.Context) ([]slam.MapInfo, error) {
+	return mm.store.List()
+}
+
+func (mm *storeMapManager) LoadMap(ctx context.Context, name string, version int) error {
+	_, info, err := mm.store.Load(name, version)
+	mm.loaded = info
+	return err
+}
+
+func TestDoMapCommand(t *testing.T) {
+	ctx := context.Background()
+	mm := &storeMapManager{store: slam.NewMapStore(t.TempDir())}
+
+	resp, err := slam.DoMapCommand(ctx, mm, map[string]interface{}{slam.SaveMapCommand: "office"})
+	test.That(t, err, test.ShouldBeNil)
+	info, err := slam.MapInfoFromMap(resp)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, info.Name, test.ShouldEqual, "office")
+	test.That(t, info.Version, test.ShouldEqual, 1)
+	_, err = slam.DoMapCommand(ctx, mm, map[string]interface{}{slam.SaveMapCommand: "office"})
+	test.That(t, err, test.ShouldBeNil)
+
+	resp, err = slam.DoMapCommand(ctx, mm, map[string]interface{}{slam.ListMapsCommand: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["maps"], test.ShouldHaveLength, 2)
+
+	_, err = slam.DoMapCommand(ctx, mm, map[string]interface{}{slam.LoadMapCommand: "office", "version": 1.0})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mm.loaded.Version, test.ShouldEqual, 1)
+	_, err = slam.DoMapCommand(ctx, mm, map[string]interface{}{slam.LoadMapCommand: "office"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mm.loaded.Version, test.ShouldEqual, 2)
+
+	_, err = slam.DoMapCommand(ctx, mm, map[string]interface{}{"nope": true})
+	test.That(t, err, test.ShouldBeError, resource.ErrDoUnimplemented)
+}
@@ -0,0 +1,80 @@
+package slam
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/pointcloud"
+)
+
+// OccupancyGridCommand is the DoCommand key used to get the map of a remote SLAM
+// service as an occupancy grid. Its argument is a map that may give the cell size
+// as "resolution_mm" and ask for a 3D grid with "3d", and it returns the grid in the
+// binary encoding of pointcloud.OccupancyGrid, base64 encoded, as "grid".
+const OccupancyGridCommand = "get_occupancy_grid"
+
+// DefaultOccupancyGridResolution is the cell size, in mm, of occupancy grids unless asked otherwise.
+const DefaultOccupancyGridResolution = 50.
+
+// An OccupancyGridder is a SLAM service that can convert its map into an occupancy grid itself.
+type OccupancyGridder interface {
+	// OccupancyGrid returns the map as a grid with cells resolution mm wide, which is 2D unless is2D is false.
+	OccupancyGrid(ctx context.Context, resolution float64, is2D bool) (*pointcloud.OccupancyGrid, error)
+}
+
+// GetOccupancyGrid gets the point cloud map of the SLAM service and converts it into an occupancy grid with cells
+// resolution mm wide, which is 2D unless is2D is false.
+func GetOccupancyGrid(ctx context.Context, slamSvc Service, resolution float64, is2D bool) (*pointcloud.OccupancyGrid, error) {
+	pcd, err := GetPointCloudMapFull(ctx, slamSvc)
+	if err != nil {
+		return nil, err
+	}
+	pc, err := pointcloud.ReadPCD(bytes.NewReader(pcd))
+	if err != nil {
+		return nil, err
+	}
+	return pointcloud.NewOccupancyGridFromPointCloud(pc, resolution, is2D)
+}
+
+// DoOccupancyGridCommand answers a "get_occupancy_grid" DoCommand for the SLAM service.
+func DoOccupancyGridCommand(ctx context.Context, slamSvc Service, args interface{}) (map[string]interface{}, error) {
+	resolution, is3D := occupancyGridArgs(args)
+	grid, err := GetOccupancyGrid(ctx, slamSvc, resolution, !is3D)
+	if err != nil {
+		return nil, err
+	}
+	data, err := grid.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"grid": base64.StdEncoding.EncodeToString(data)}, nil
+}
+
+func occupancyGridArgs(args interface{}) (float64, bool) {
+	m, _ := args.(map[string]interface{})
+	resolution, ok := m["resolution_mm"].(float64)
+	if !ok || resolution <= 0 {
+		resolution = DefaultOccupancyGridResolution
+	}
+	is3D, _ := m["3d"].(bool)
+	return resolution, is3D
+}
+
+func occupancyGridFromResponse(resp map[string]interface{}) (*pointcloud.OccupancyGrid, error) {
+	encoded, ok := resp["grid"].(string)
+	if !ok {
+		return nil, errors.Errorf("%s response has no grid", OccupancyGridCommand)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var grid pointcloud.OccupancyGrid
+	if err := grid.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return &grid, nil
+}
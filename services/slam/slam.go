@@ -49,6 +49,27 @@ type Service interface {
 	GetInternalState(ctx context.Context) (func() ([]byte, error), error)
 }
 
+// A Mode is how a SLAM service runs its algorithm.
+type Mode string
+
+// The modes a SLAM service can run in.
+const (
+	// MappingMode builds a new map, or extends the map it was started from, while estimating the pose.
+	MappingMode Mode = "mapping"
+	// LocalizingMode only estimates the pose against a fixed map loaded at startup, which is cheaper than mapping and is what
+	// most deployed robots need.
+	LocalizingMode Mode = "localizing"
+)
+
+// GetModeCommand is the DoCommand key used to ask a remote SLAM service which mode it is running in. It takes no argument and
+// returns the mode as "mode".
+const GetModeCommand = "get_mode"
+
+// A ModeReporter is a SLAM service that reports which mode it is running in.
+type ModeReporter interface {
+	Mode(ctx context.Context) (Mode, error)
+}
+
 // HelperConcatenateChunksToFull concatenates the chunks from a streamed grpc endpoint.
 func HelperConcatenateChunksToFull(f func() ([]byte, error)) ([]byte, error) {
 	var fullBytes []byte
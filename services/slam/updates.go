@@ -0,0 +1,292 @@
+package slam
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// MapUpdatesCommand is the DoCommand key used to get the map updates of a remote
+// SLAM service. Its argument is the sequence number of the last update the caller
+// has, or 0 for none, and it returns the updates since then as "updates".
+const MapUpdatesCommand = "get_map_updates"
+
+// DefaultMapUpdatePeriod is how often map updates are fetched when streaming them.
+const DefaultMapUpdatePeriod = 500 * time.Millisecond
+
+// maxMapUpdates is how many updates are kept for callers that are behind. A caller
+// further behind than this gets the whole map again.
+const maxMapUpdates = 100
+
+// A MapUpdate is an incremental change to a SLAM map along with the latest pose, so
+// that the map can be kept current without downloading all of it again.
+type MapUpdate struct {
+	// Seq counts up by one with each update.
+	Seq int64
+	// Full is set if Added holds every point of the map, which replaces any map
+	// the caller has, rather than a change to it.
+	Full    bool
+	Added   []r3.Vector
+	Removed []r3.Vector
+
+	Pose               spatialmath.Pose
+	ComponentReference string
+}
+
+// ToMap converts the update into the form returned by DoCommand, with points
+// flattened into lists of coordinates.
+func (u MapUpdate) ToMap() map[string]interface{} {
+	out := map[string]interface{}{
+		"seq":                 u.Seq,
+		"full":                u.Full,
+		"added":               flattenPoints(u.Added),
+		"removed":             flattenPoints(u.Removed),
+		"component_reference": u.ComponentReference,
+	}
+	if u.Pose != nil {
+		p := spatialmath.PoseToProtobuf(u.Pose)
+		out["pose"] = map[string]interface{}{"x": p.X, "y": p.Y, "z": p.Z, "o_x": p.OX, "o_y": p.OY, "o_z": p.OZ, "theta": p.Theta}
+	}
+	return out
+}
+
+// MapUpdateFromMap converts the form returned by DoCommand back into the update.
+func MapUpdateFromMap(m map[string]interface{}) (MapUpdate, error) {
+	seq, ok := number(m["seq"])
+	if !ok {
+		return MapUpdate{}, errors.New("map update has no sequence number")
+	}
+	u := MapUpdate{Seq: seq}
+	u.Full, _ = m["full"].(bool)
+	u.ComponentReference, _ = m["component_reference"].(string)
+	var err error
+	if u.Added, err = unflattenPoints(m["added"]); err != nil {
+		return MapUpdate{}, err
+	}
+	if u.Removed, err = unflattenPoints(m["removed"]); err != nil {
+		return MapUpdate{}, err
+	}
+	if pose, ok := m["pose"].(map[string]interface{}); ok {
+		coord := func(key string) float64 {
+			v, _ := pose[key].(float64)
+			return v
+		}
+		u.Pose = spatialmath.NewPoseFromProtobuf(&commonpb.Pose{
+			X: coord("x"), Y: coord("y"), Z: coord("z"), OX: coord("o_x"), OY: coord("o_y"), OZ: coord("o_z"), Theta: coord("theta"),
+		})
+	}
+	return u, nil
+}
+
+func flattenPoints(points []r3.Vector) []interface{} {
+	coords := make([]interface{}, 0, 3*len(points))
+	for _, p := range points {
+		coords = append(coords, p.X, p.Y, p.Z)
+	}
+	return coords
+}
+
+func unflattenPoints(v interface{}) ([]r3.Vector, error) {
+	coords, _ := v.([]interface{})
+	if len(coords)%3 != 0 {
+		return nil, errors.New("map update points must have 3 coordinates each")
+	}
+	points := make([]r3.Vector, 0, len(coords)/3)
+	for i := 0; i < len(coords); i += 3 {
+		x, okX := coords[i].(float64)
+		y, okY := coords[i+1].(float64)
+		z, okZ := coords[i+2].(float64)
+		if !okX || !okY || !okZ {
+			return nil, errors.New("map update coordinates must be numbers")
+		}
+		points = append(points, r3.Vector{X: x, Y: y, Z: z})
+	}
+	return points, nil
+}
+
+// A MapStreamer is a SLAM service that can stream incremental changes to its map
+// and pose.
+type MapStreamer interface {
+	// MapUpdates returns a channel that first receives the whole map and then every
+	// change to it. The channel is closed once ctx is done.
+	MapUpdates(ctx context.Context) (<-chan MapUpdate, error)
+}
+
+// A MapUpdateTracker works out the changes to the map of a SLAM service by comparing
+// each point cloud map it gets with the previous one, and keeps the recent changes
+// for callers to catch up on.
+type MapUpdateTracker struct {
+	svc Service
+
+	mu                 sync.Mutex
+	seq                int64
+	points             map[r3.Vector]struct{}
+	pose               spatialmath.Pose
+	componentReference string
+	// history holds the most recent updates, oldest first.
+	history []MapUpdate
+}
+
+// NewMapUpdateTracker returns a tracker of the map of the given service.
+func NewMapUpdateTracker(svc Service) *MapUpdateTracker {
+	return &MapUpdateTracker{svc: svc, points: map[r3.Vector]struct{}{}}
+}
+
+// Update gets the current map and pose of the service and records any change to them as a new update.
+func (mt *MapUpdateTracker) Update(ctx context.Context) error {
+	pcd, err := GetPointCloudMapFull(ctx, mt.svc)
+	if err != nil {
+		return err
+	}
+	pc, err := pointcloud.ReadPCD(bytes.NewReader(pcd))
+	if err != nil {
+		return err
+	}
+	pose, componentReference, err := mt.svc.GetPosition(ctx)
+	if err != nil {
+		return err
+	}
+
+	points := make(map[r3.Vector]struct{}, pc.Size())
+	pc.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		points[p] = struct{}{}
+		return true
+	})
+
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	var update MapUpdate
+	for p := range points {
+		if _, ok := mt.points[p]; !ok {
+			update.Added = append(update.Added, p)
+		}
+	}
+	for p := range mt.points {
+		if _, ok := points[p]; !ok {
+			update.Removed = append(update.Removed, p)
+		}
+	}
+	poseChanged := mt.pose == nil || !spatialmath.PoseAlmostEqual(mt.pose, pose) || mt.componentReference != componentReference
+	if len(update.Added) == 0 && len(update.Removed) == 0 && !poseChanged {
+		return nil
+	}
+	mt.seq++
+	update.Seq = mt.seq
+	update.Pose = pose
+	update.ComponentReference = componentReference
+	mt.points = points
+	mt.pose = pose
+	mt.componentReference = componentReference
+	mt.history = append(mt.history, update)
+	if len(mt.history) > maxMapUpdates {
+		mt.history = mt.history[len(mt.history)-maxMapUpdates:]
+	}
+	return nil
+}
+
+// Since returns the updates after the one with the given sequence number. A caller
+// with no updates, or too far behind to catch up, gets the whole map as one update.
+func (mt *MapUpdateTracker) Since(seq int64) []MapUpdate {
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	if seq >= mt.seq {
+		return nil
+	}
+	if seq > 0 && len(mt.history) > 0 && mt.history[0].Seq <= seq+1 {
+		return append([]MapUpdate(nil), mt.history[seq+1-mt.history[0].Seq:]...)
+	}
+	full := MapUpdate{
+		Seq:                mt.seq,
+		Full:               true,
+		Added:              make([]r3.Vector, 0, len(mt.points)),
+		Pose:               mt.pose,
+		ComponentReference: mt.componentReference,
+	}
+	for p := range mt.points {
+		full.Added = append(full.Added, p)
+	}
+	return []MapUpdate{full}
+}
+
+// Fetch updates the tracker and returns the updates since the given sequence number.
+func (mt *MapUpdateTracker) Fetch(ctx context.Context, seq int64) ([]MapUpdate, error) {
+	if err := mt.Update(ctx); err != nil {
+		return nil, err
+	}
+	return mt.Since(seq), nil
+}
+
+// DoMapUpdatesCommand fetches the updates for a "get_map_updates" DoCommand from the tracker.
+func DoMapUpdatesCommand(ctx context.Context, mt *MapUpdateTracker, args interface{}) (map[string]interface{}, error) {
+	seq, _ := number(args)
+	updates, err := mt.Fetch(ctx, seq)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]interface{}, 0, len(updates))
+	for _, update := range updates {
+		out = append(out, update.ToMap())
+	}
+	return map[string]interface{}{"updates": out}, nil
+}
+
+func mapUpdatesFromResponse(resp map[string]interface{}) ([]MapUpdate, error) {
+	raw, _ := resp["updates"].([]interface{})
+	updates := make([]MapUpdate, 0, len(raw))
+	for _, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("invalid map update in %s response", MapUpdatesCommand)
+		}
+		update, err := MapUpdateFromMap(m)
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
+
+// StreamMapUpdates calls fetch every period with the sequence number of the last update
+// received, and sends the updates it returns on the channel, which is closed once ctx is
+// done. Errors are logged and fetching is tried again the next period.
+func StreamMapUpdates(
+	ctx context.Context,
+	logger golog.Logger,
+	period time.Duration,
+	fetch func(ctx context.Context, seq int64) ([]MapUpdate, error),
+) <-chan MapUpdate {
+	updates := make(chan MapUpdate)
+	goutils.PanicCapturingGo(func() {
+		defer close(updates)
+		var seq int64
+		for {
+			fetched, err := fetch(ctx, seq)
+			if err != nil && ctx.Err() == nil {
+				logger.Debugw("failed to get map updates", "error", err)
+			}
+			for _, update := range fetched {
+				select {
+				case updates <- update:
+					seq = update.Seq
+				case <-ctx.Done():
+					return
+				}
+			}
+			if !goutils.SelectContextOrWait(ctx, period) {
+				return
+			}
+		}
+	})
+	return updates
+}
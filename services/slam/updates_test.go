@@ -0,0 +1,111 @@
+package slam_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestMapUpdateTracker(t *testing.T) {
+	ctx := context.Background()
+	points := []r3.Vector{{1, 2, 3}, {4, 5, 6}}
+	pose := spatialmath.NewPoseFromPoint(r3.Vector{})
+
+	slamSvc := &inject.SLAMService{}
+	slamSvc.GetPointCloudMapFunc = func(ctx context.Context) (func() ([]byte, error), error) {
+		pc := pointcloud.New()
+		for _, p := range points {
+			test.That(t, pc.Set(p, nil), test.ShouldBeNil)
+		}
+		var buf bytes.Buffer
+		test.That(t, pointcloud.ToPCD(pc, &buf, pointcloud.PCDBinary), test.ShouldBeNil)
+		return func() ([]byte, error) {
+			if buf.Len() == 0 {
+				return nil, io.EOF
+			}
+			return buf.Next(1024), nil
+		}, nil
+	}
+	slamSvc.GetPositionFunc = func(ctx context.Context) (spatialmath.Pose, string, error) {
+		return pose, "lidar", nil
+	}
+	tracker := slam.NewMapUpdateTracker(slamSvc)
+
+	updates, err := tracker.Fetch(ctx, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, updates, test.ShouldHaveLength, 1)
+	test.That(t, updates[0].Full, test.ShouldBeTrue)
+	test.That(t, updates[0].Added, test.ShouldHaveLength, 2)
+	test.That(t, updates[0].ComponentReference, test.ShouldEqual, "lidar")
+
+	t.Run("an unchanged map has no updates", func(t *testing.T) {
+		updates, err := tracker.Fetch(ctx, 1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, updates, test.ShouldBeEmpty)
+	})
+
+	t.Run("only the changes are sent", func(t *testing.T) {
+		points = []r3.Vector{{1, 2, 3}, {7, 8, 9}}
+		updates, err := tracker.Fetch(ctx, 1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, updates, test.ShouldHaveLength, 1)
+		test.That(t, updates[0].Seq, test.ShouldEqual, 2)
+		test.That(t, updates[0].Full, test.ShouldBeFalse)
+		test.That(t, updates[0].Added, test.ShouldResemble, []r3.Vector{{7, 8, 9}})
+		test.That(t, updates[0].Removed, test.ShouldResemble, []r3.Vector{{4, 5, 6}})
+	})
+
+	t.Run("a moved pose is an update", func(t *testing.T) {
+		pose = spatialmath.NewPoseFromPoint(r3.Vector{X: 10})
+		updates, err := tracker.Fetch(ctx, 2)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, updates, test.ShouldHaveLength, 1)
+		test.That(t, updates[0].Added, test.ShouldBeEmpty)
+		test.That(t, spatialmath.PoseAlmostEqual(updates[0].Pose, pose), test.ShouldBeTrue)
+	})
+
+	t.Run("callers can catch up on several updates", func(t *testing.T) {
+		updates := tracker.Since(1)
+		test.That(t, updates, test.ShouldHaveLength, 2)
+		test.That(t, updates[0].Seq, test.ShouldEqual, 2)
+		test.That(t, updates[1].Seq, test.ShouldEqual, 3)
+	})
+
+	t.Run("updates survive DoCommand", func(t *testing.T) {
+		resp, err := slam.DoMapUpdatesCommand(ctx, tracker, 0.0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp["updates"], test.ShouldHaveLength, 1)
+		update, err := slam.MapUpdateFromMap(resp["updates"].([]interface{})[0].(map[string]interface{}))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, update.Seq, test.ShouldEqual, 3)
+		test.That(t, update.Full, test.ShouldBeTrue)
+		test.That(t, update.Added, test.ShouldHaveLength, 2)
+		test.That(t, spatialmath.PoseAlmostEqual(update.Pose, pose), test.ShouldBeTrue)
+	})
+}
+
+func TestStreamMapUpdates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var seqs []int64
+	updates := slam.StreamMapUpdates(ctx, golog.NewTestLogger(t), time.Millisecond, func(ctx context.Context, seq int64) ([]slam.MapUpdate, error) {
+		seqs = append(seqs, seq)
+		return []slam.MapUpdate{{Seq: seq + 1}}, nil
+	})
+	test.That(t, (<-updates).Seq, test.ShouldEqual, 1)
+	test.That(t, (<-updates).Seq, test.ShouldEqual, 2)
+	cancel()
+	for range updates {
+	}
+	test.That(t, seqs[:2], test.ShouldResemble, []int64{0, 1})
+}
@@ -0,0 +1,172 @@
+// Package detectionpipeline passes the detections of another vision service through a pipeline of
+// stages, such as class, confidence and region filters and a tracker, so consumers get the detections
+// they need without filtering them themselves.
+package detectionpipeline
+
+import (
+	"context"
+	"image"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+var model = resource.DefaultModelFamily.WithModel("detection_pipeline")
+
+func init() {
+	resource.RegisterService(vision.API, model, resource.Registration[vision.Service, *Config]{
+		DeprecatedRobotConstructor: func(ctx context.Context, r any, c resource.Config, logger golog.Logger) (vision.Service, error) {
+			attrs, err := resource.NativeConfig[*Config](c)
+			if err != nil {
+				return nil, err
+			}
+			actualR, err := utils.AssertType[robot.Robot](r)
+			if err != nil {
+				return nil, err
+			}
+			return registerDetectionPipeline(ctx, c.ResourceName(), attrs, actualR)
+		},
+	})
+}
+
+// Config is the detector whose detections go through the pipeline, and the stages of the pipeline in order.
+type Config struct {
+	DetectorName string  `json:"detector_name"`
+	Pipeline     []Stage `json:"pipeline"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.DetectorName == "" {
+		return nil, goutils.NewConfigValidationFieldRequiredError(path, "detector_name")
+	}
+	for i, st := range cfg.Pipeline {
+		if _, err := buildStage(st); err != nil {
+			return nil, goutils.NewConfigValidationError(path, errors.Wrapf(err, "pipeline stage %d", i))
+		}
+	}
+	return []string{cfg.DetectorName}, nil
+}
+
+// pipelineService runs the detections of a detector through the stages of the pipeline.
+type pipelineService struct {
+	vision.Service
+	name     resource.Name
+	detector vision.Service
+	stages   []stage
+}
+
+func registerDetectionPipeline(
+	ctx context.Context,
+	name resource.Name,
+	conf *Config,
+	r robot.Robot,
+) (vision.Service, error) {
+	_, span := trace.StartSpan(ctx, "service::vision::registerDetectionPipeline")
+	defer span.End()
+	if conf == nil {
+		return nil, errors.New("config for detection pipeline cannot be nil")
+	}
+	detector, err := vision.FromRobot(r, conf.DetectorName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not find necessary dependency, detector %q", conf.DetectorName)
+	}
+	svc := &pipelineService{name: name, detector: detector}
+	for i, st := range conf.Pipeline {
+		s, err := buildStage(st)
+		if err != nil {
+			return nil, errors.Wrapf(err, "pipeline stage %d", i)
+		}
+		svc.stages = append(svc.stages, s)
+	}
+	// the wrapped service answers the classifier and segmenter methods, which the pipeline does not implement
+	detectorFunc := func(ctx context.Context, img image.Image) ([]objectdetection.Detection, error) {
+		return svc.Detections(ctx, img, nil)
+	}
+	svc.Service, err = vision.NewService(name.Name, r, nil, nil, detectorFunc, nil)
+	if err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// Name returns the name of the service.
+func (ps *pipelineService) Name() resource.Name {
+	return ps.name
+}
+
+func (ps *pipelineService) run(cameraName string, dets []objectdetection.Detection) []objectdetection.Detection {
+	now := time.Now()
+	for _, s := range ps.stages {
+		dets = s.process(cameraName, dets, now)
+	}
+	return dets
+}
+
+// Detections returns the detections of the given image that make it through the pipeline.
+func (ps *pipelineService) Detections(
+	ctx context.Context,
+	img image.Image,
+	extra map[string]interface{},
+) ([]objectdetection.Detection, error) {
+	ctx, span := trace.StartSpan(ctx, "service::vision::detectionpipeline::Detections")
+	defer span.End()
+	dets, err := ps.detector.Detections(ctx, img, extra)
+	if err != nil {
+		return nil, err
+	}
+	return ps.run("", dets), nil
+}
+
+// DetectionsFromCamera returns the detections of the next image from the given camera that make it through the
+// pipeline.
+func (ps *pipelineService) DetectionsFromCamera(
+	ctx context.Context,
+	cameraName string,
+	extra map[string]interface{},
+) ([]objectdetection.Detection, error) {
+	ctx, span := trace.StartSpan(ctx, "service::vision::detectionpipeline::DetectionsFromCamera")
+	defer span.End()
+	dets, err := ps.detector.DetectionsFromCamera(ctx, cameraName, extra)
+	if err != nil {
+		return nil, err
+	}
+	return ps.run(cameraName, dets), nil
+}
+
+// DoCommand answers the "get_tracks" command with the tracks of the last tracker of the pipeline.
+func (ps *pipelineService) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	arg, ok := cmd[vision.TracksCommand]
+	if !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+	var tracker *trackerStage
+	for _, s := range ps.stages {
+		if t, ok := s.(*trackerStage); ok {
+			tracker = t
+		}
+	}
+	if tracker == nil {
+		return nil, errors.New("detection pipeline has no tracker")
+	}
+	cameraName, _ := arg.(string)
+	t := tracker.tracker(cameraName, false)
+	if t == nil {
+		return vision.TracksResponse(nil), nil
+	}
+	return vision.TracksResponse(t.Tracks()), nil
+}
+
+// Close does nothing, as the detector is a separate resource.
+func (ps *pipelineService) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,125 @@
+package detectionpipeline
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func detect(context.Context, image.Image) ([]objectdetection.Detection, error) {
+	return []objectdetection.Detection{
+		objectdetection.NewDetection(image.Rect(10, 10, 50, 50), 0.9, "person"),
+		objectdetection.NewDetection(image.Rect(200, 200, 260, 260), 0.95, "person"),
+		objectdetection.NewDetection(image.Rect(20, 20, 40, 40), 0.3, "person"),
+		objectdetection.NewDetection(image.Rect(10, 10, 50, 50), 0.99, "dog"),
+	}, nil
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := &Config{}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "detector_name")
+
+	cfg = &Config{DetectorName: "detector", Pipeline: []Stage{{Type: "blur"}}}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `do not know detection pipeline stage of type "blur"`)
+
+	for _, st := range []Stage{
+		{Type: "class_filter"},
+		{Type: "confidence", Attributes: utils.AttributeMap{"min_confidence": 2.}},
+		{Type: "roi", Attributes: utils.AttributeMap{"x_min": 10, "x_max": 10, "y_max": 10}},
+		{Type: "area"},
+		{Type: "tracker", Attributes: utils.AttributeMap{"iou_threshold": -1.}},
+	} {
+		cfg = &Config{DetectorName: "detector", Pipeline: []Stage{st}}
+		_, err = cfg.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "pipeline stage 0")
+	}
+
+	cfg = &Config{DetectorName: "detector", Pipeline: []Stage{
+		{Type: "class_filter", Attributes: utils.AttributeMap{"labels": []interface{}{"person"}}},
+		{Type: "tracker"},
+	}}
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"detector"})
+}
+
+func TestDetectionPipeline(t *testing.T) {
+	ctx := context.Background()
+	r := &inject.Robot{}
+	detector, err := vision.NewService("detector", r, nil, nil, detect, nil)
+	test.That(t, err, test.ShouldBeNil)
+	r.ResourceNamesFunc = func() []resource.Name {
+		return []resource.Name{vision.Named("detector")}
+	}
+	r.ResourceByNameFunc = func(n resource.Name) (resource.Resource, error) {
+		if n.Name == "detector" {
+			return detector, nil
+		}
+		return nil, resource.NewNotFoundError(n)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 300, 300))
+
+	conf := &Config{DetectorName: "detector", Pipeline: []Stage{
+		{Type: "class_filter", Attributes: utils.AttributeMap{"labels": []interface{}{"person"}}},
+		{Type: "confidence", Attributes: utils.AttributeMap{"min_confidence": 0.5}},
+		{Type: "roi", Attributes: utils.AttributeMap{"x_min": 0, "y_min": 0, "x_max": 100, "y_max": 100}},
+	}}
+	svc, err := registerDetectionPipeline(ctx, vision.Named("pipeline"), conf, r)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, svc.Name(), test.ShouldResemble, vision.Named("pipeline"))
+	dets, err := svc.Detections(ctx, img, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dets, test.ShouldHaveLength, 1)
+	test.That(t, dets[0].Label(), test.ShouldEqual, "person")
+	test.That(t, dets[0].Score(), test.ShouldEqual, 0.9)
+
+	_, err = svc.DoCommand(ctx, map[string]interface{}{vision.TracksCommand: ""})
+	test.That(t, err, test.ShouldBeError, "detection pipeline has no tracker")
+
+	// with an empty pipeline, the detections go through untouched
+	svc, err = registerDetectionPipeline(ctx, vision.Named("pipeline"), &Config{DetectorName: "detector"}, r)
+	test.That(t, err, test.ShouldBeNil)
+	dets, err = svc.Detections(ctx, img, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dets, test.ShouldHaveLength, 4)
+
+	conf = &Config{DetectorName: "detector", Pipeline: []Stage{
+		{Type: "class_filter", Attributes: utils.AttributeMap{"labels": []interface{}{"dog"}, "exclude": true}},
+		{Type: "tracker", Attributes: utils.AttributeMap{"min_hits": 2}},
+		{Type: "area", Attributes: utils.AttributeMap{"min_area_px": 2000}},
+	}}
+	svc, err = registerDetectionPipeline(ctx, vision.Named("pipeline"), conf, r)
+	test.That(t, err, test.ShouldBeNil)
+	dets, err = svc.Detections(ctx, img, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dets, test.ShouldBeEmpty)
+	dets, err = svc.Detections(ctx, img, nil)
+	test.That(t, err, test.ShouldBeNil)
+	// the low confidence person starts no track, and the area filter removes the smaller person
+	test.That(t, dets, test.ShouldHaveLength, 1)
+	tracked, ok := dets[0].(objectdetection.TrackedDetection)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, *tracked.BoundingBox(), test.ShouldResemble, image.Rect(200, 200, 260, 260))
+
+	resp, err := svc.DoCommand(ctx, map[string]interface{}{vision.TracksCommand: ""})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["tracks"], test.ShouldHaveLength, 2)
+	resp, err = svc.DoCommand(ctx, map[string]interface{}{vision.TracksCommand: "cam"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["tracks"], test.ShouldBeEmpty)
+	_, err = svc.DoCommand(ctx, map[string]interface{}{"foo": "bar"})
+	test.That(t, err, test.ShouldEqual, resource.ErrDoUnimplemented)
+}
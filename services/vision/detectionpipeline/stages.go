@@ -0,0 +1,170 @@
+package detectionpipeline
+
+import (
+	"image"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// stageType is the list of allowed stages that can be used in the pipeline.
+type stageType string
+
+// the allowed stages.
+const (
+	stageTypeClassFilter = stageType("class_filter")
+	stageTypeConfidence  = stageType("confidence")
+	stageTypeRegion      = stageType("roi")
+	stageTypeArea        = stageType("area")
+	stageTypeTracker     = stageType("tracker")
+)
+
+// defaultMinOverlap is the fraction of a bounding box that must be inside the region of interest by default.
+const defaultMinOverlap = 0.5
+
+// Stage states the type of a stage of the pipeline and the attributes that are specific to the given type.
+type Stage struct {
+	Type       string             `json:"type"`
+	Attributes utils.AttributeMap `json:"attributes"`
+}
+
+type classFilterConfig struct {
+	Labels  []string `json:"labels"`
+	Exclude bool     `json:"exclude"`
+}
+
+type confidenceConfig struct {
+	MinConfidence float64 `json:"min_confidence"`
+}
+
+type regionConfig struct {
+	XMin int `json:"x_min"`
+	YMin int `json:"y_min"`
+	XMax int `json:"x_max"`
+	YMax int `json:"y_max"`
+	// MinOverlap is the fraction of the bounding box of a detection that must be inside the region.
+	MinOverlap float64 `json:"min_overlap"`
+}
+
+type areaConfig struct {
+	MinAreaPx int `json:"min_area_px"`
+}
+
+type trackerConfig struct {
+	IoUThreshold       float64 `json:"iou_threshold"`
+	MaxAge             int     `json:"max_age_frames"`
+	MinHits            int     `json:"min_hits"`
+	HighScoreThreshold float64 `json:"high_score_threshold"`
+}
+
+// stage processes the detections of a frame from the given camera, or "" for images passed in.
+type stage interface {
+	process(cameraName string, dets []objectdetection.Detection, at time.Time) []objectdetection.Detection
+}
+
+// postprocessorStage is a stage that does the same to the detections of every camera.
+type postprocessorStage objectdetection.Postprocessor
+
+func (s postprocessorStage) process(cameraName string, dets []objectdetection.Detection, at time.Time) []objectdetection.Detection {
+	return s(dets)
+}
+
+// trackerStage tracks the detections of each camera separately.
+type trackerStage struct {
+	conf objectdetection.TrackerConfig
+
+	mu       sync.Mutex
+	trackers map[string]*objectdetection.Tracker
+}
+
+func (s *trackerStage) tracker(cameraName string, create bool) *objectdetection.Tracker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.trackers[cameraName]
+	if !ok && create {
+		t = objectdetection.NewTracker(s.conf)
+		s.trackers[cameraName] = t
+	}
+	return t
+}
+
+func (s *trackerStage) process(cameraName string, dets []objectdetection.Detection, at time.Time) []objectdetection.Detection {
+	tracked := s.tracker(cameraName, true).Update(dets, at)
+	out := make([]objectdetection.Detection, 0, len(tracked))
+	for _, d := range tracked {
+		out = append(out, d)
+	}
+	return out
+}
+
+// buildStage uses the Stage config to build the desired stage.
+func buildStage(st Stage) (stage, error) {
+	switch stageType(st.Type) {
+	case stageTypeClassFilter:
+		conf, err := resource.TransformAttributeMap[*classFilterConfig](st.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		if len(conf.Labels) == 0 {
+			return nil, errors.New("class filter needs labels")
+		}
+		return postprocessorStage(objectdetection.NewLabelFilter(conf.Labels, conf.Exclude)), nil
+	case stageTypeConfidence:
+		conf, err := resource.TransformAttributeMap[*confidenceConfig](st.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		if conf.MinConfidence < 0 || conf.MinConfidence > 1 {
+			return nil, errors.Errorf("min_confidence must be between 0 and 1, got %v", conf.MinConfidence)
+		}
+		return postprocessorStage(objectdetection.NewScoreFilter(conf.MinConfidence)), nil
+	case stageTypeRegion:
+		conf, err := resource.TransformAttributeMap[*regionConfig](st.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		region := image.Rect(conf.XMin, conf.YMin, conf.XMax, conf.YMax)
+		if region.Empty() {
+			return nil, errors.New("region of interest cannot be empty")
+		}
+		if conf.MinOverlap < 0 || conf.MinOverlap > 1 {
+			return nil, errors.Errorf("min_overlap must be between 0 and 1, got %v", conf.MinOverlap)
+		}
+		minOverlap := conf.MinOverlap
+		if minOverlap == 0 {
+			minOverlap = defaultMinOverlap
+		}
+		return postprocessorStage(objectdetection.NewRegionFilter(region, minOverlap)), nil
+	case stageTypeArea:
+		conf, err := resource.TransformAttributeMap[*areaConfig](st.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		if conf.MinAreaPx <= 0 {
+			return nil, errors.New("min_area_px must be positive")
+		}
+		return postprocessorStage(objectdetection.NewAreaFilter(conf.MinAreaPx)), nil
+	case stageTypeTracker:
+		conf, err := resource.TransformAttributeMap[*trackerConfig](st.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		trackerConf := objectdetection.TrackerConfig{
+			IoUThreshold:       conf.IoUThreshold,
+			MaxAge:             conf.MaxAge,
+			MinHits:            conf.MinHits,
+			HighScoreThreshold: conf.HighScoreThreshold,
+		}
+		if err := trackerConf.Validate("tracker"); err != nil {
+			return nil, err
+		}
+		return &trackerStage{conf: trackerConf, trackers: map[string]*objectdetection.Tracker{}}, nil
+	default:
+		return nil, errors.Errorf("do not know detection pipeline stage of type %q", st.Type)
+	}
+}
@@ -0,0 +1,281 @@
+// Package fiducialdetector finds fiducial markers, such as AprilTags, in images and returns their IDs
+// as detections and their poses in the camera frame as objects, so they can serve as landmarks.
+package fiducialdetector
+
+import (
+	"context"
+	"image"
+	"strconv"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+	viz "go.viam.com/rdk/vision"
+	"go.viam.com/rdk/vision/fiducial"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+var model = resource.DefaultModelFamily.WithModel("fiducial_detector")
+
+// TagPosesCommand is the DoCommand key used to get the poses of the tags seen by a camera, since the vision API has
+// no field for them. Its argument is a map with the name of the camera as "camera" and, optionally, the frame of the
+// frame system to return the poses in as "frame", which is the camera frame by default. It returns the tags as a
+// list of maps under "tags".
+const TagPosesCommand = "get_tag_poses"
+
+// tagThicknessMM is the thickness of the geometries of the tags.
+const tagThicknessMM = 1.
+
+func init() {
+	resource.RegisterService(vision.API, model, resource.Registration[vision.Service, *Config]{
+		DeprecatedRobotConstructor: func(ctx context.Context, r any, c resource.Config, logger golog.Logger) (vision.Service, error) {
+			attrs, err := resource.NativeConfig[*Config](c)
+			if err != nil {
+				return nil, err
+			}
+			actualR, err := utils.AssertType[robot.Robot](r)
+			if err != nil {
+				return nil, err
+			}
+			return registerFiducialDetector(ctx, c.ResourceName(), attrs, actualR)
+		},
+	})
+}
+
+// Config are the parameters of the fiducial detector.
+type Config struct {
+	Family string `json:"tag_family"`
+	// TagSizeMM is the length of a side of the black square of the tags.
+	TagSizeMM float64 `json:"tag_size_mm"`
+	// TagSizesMM overrides the size of the tags with the given IDs.
+	TagSizesMM map[string]float64 `json:"tag_sizes_mm,omitempty"`
+	// Intrinsics are those of the camera, if the camera does not report them itself.
+	Intrinsics *transform.PinholeCameraIntrinsics `json:"intrinsic_parameters,omitempty"`
+	NumThreads int                                `json:"num_threads,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.Family == "" {
+		return nil, goutils.NewConfigValidationFieldRequiredError(path, "tag_family")
+	}
+	if err := fiducial.ValidateFamily(cfg.Family); err != nil {
+		return nil, goutils.NewConfigValidationError(path, err)
+	}
+	if cfg.TagSizeMM <= 0 {
+		return nil, goutils.NewConfigValidationError(path, errors.New("tag_size_mm must be positive"))
+	}
+	for id, size := range cfg.TagSizesMM {
+		if _, err := strconv.Atoi(id); err != nil {
+			return nil, goutils.NewConfigValidationError(path, errors.Errorf("tag_sizes_mm key %q is not a tag ID", id))
+		}
+		if size <= 0 {
+			return nil, goutils.NewConfigValidationError(path, errors.Errorf("size of tag %s must be positive", id))
+		}
+	}
+	if cfg.Intrinsics != nil {
+		if err := cfg.Intrinsics.CheckValid(); err != nil {
+			return nil, goutils.NewConfigValidationError(path, err)
+		}
+	}
+	if cfg.NumThreads < 0 {
+		return nil, goutils.NewConfigValidationError(path, errors.New("num_threads cannot be negative"))
+	}
+	return nil, nil
+}
+
+// sizeMM returns the size of the tag with the given ID.
+func (cfg *Config) sizeMM(id int) float64 {
+	if size, ok := cfg.TagSizesMM[strconv.Itoa(id)]; ok {
+		return size
+	}
+	return cfg.TagSizeMM
+}
+
+// fiducialService detects tags as a detector and estimates their poses as a 3D segmenter.
+type fiducialService struct {
+	vision.Service
+	name     resource.Name
+	r        robot.Robot
+	conf     *Config
+	detector fiducial.Detector
+}
+
+// tagPose is a tag and its pose in the camera frame.
+type tagPose struct {
+	fiducial.Tag
+	pose spatialmath.Pose
+}
+
+func registerFiducialDetector(
+	ctx context.Context,
+	name resource.Name,
+	conf *Config,
+	r robot.Robot,
+) (vision.Service, error) {
+	_, span := trace.StartSpan(ctx, "service::vision::registerFiducialDetector")
+	defer span.End()
+	if conf == nil {
+		return nil, errors.New("config for fiducial detector cannot be nil")
+	}
+	detector, err := fiducial.NewDetector(conf.Family, conf.NumThreads)
+	if err != nil {
+		return nil, err
+	}
+	return newFiducialService(name, conf, r, detector)
+}
+
+func newFiducialService(name resource.Name, conf *Config, r robot.Robot, detector fiducial.Detector) (vision.Service, error) {
+	svc := &fiducialService{name: name, r: r, conf: conf, detector: detector}
+	closer := func(ctx context.Context) error {
+		return detector.Close()
+	}
+	var err error
+	svc.Service, err = vision.NewService(name.Name, r, closer, nil, svc.detect, svc.segment)
+	if err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// Name returns the name of the service.
+func (fs *fiducialService) Name() resource.Name {
+	return fs.name
+}
+
+// detect returns a detection, labeled with the family and ID, for each tag in the image.
+func (fs *fiducialService) detect(ctx context.Context, img image.Image) ([]objectdetection.Detection, error) {
+	tags, err := fs.detector.Detect(img)
+	if err != nil {
+		return nil, err
+	}
+	dets := make([]objectdetection.Detection, 0, len(tags))
+	for i := range tags {
+		dets = append(dets, objectdetection.NewDetection(tags[i].BoundingBox(), tags[i].Score(), tags[i].Label()))
+	}
+	return dets, nil
+}
+
+// segment returns an object for each tag the camera sees, whose geometry is a thin box covering the tag at its pose
+// in the camera frame. The objects have no points.
+func (fs *fiducialService) segment(ctx context.Context, src camera.VideoSource) ([]*viz.Object, error) {
+	tags, err := fs.cameraTagPoses(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]*viz.Object, 0, len(tags))
+	for _, tag := range tags {
+		size := fs.conf.sizeMM(tag.ID)
+		box, err := spatialmath.NewBox(tag.pose, r3.Vector{X: size, Y: size, Z: tagThicknessMM}, tag.Label())
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, &viz.Object{PointCloud: pointcloud.New(), Geometry: box})
+	}
+	return objects, nil
+}
+
+// cameraTagPoses returns the tags in the next image of the camera and their poses in its frame.
+func (fs *fiducialService) cameraTagPoses(ctx context.Context, src camera.VideoSource) ([]tagPose, error) {
+	intrinsics := fs.conf.Intrinsics
+	if intrinsics == nil {
+		props, err := src.Properties(ctx)
+		if err != nil {
+			return nil, err
+		}
+		intrinsics = props.IntrinsicParams
+	}
+	if err := intrinsics.CheckValid(); err != nil {
+		return nil, errors.Wrap(err, "the camera intrinsics are needed to estimate tag poses")
+	}
+	img, release, err := camera.ReadImage(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return fs.tagPoses(img, intrinsics)
+}
+
+// tagPoses returns the tags in the image and their poses in the frame of the camera with the given intrinsics.
+func (fs *fiducialService) tagPoses(img image.Image, intrinsics *transform.PinholeCameraIntrinsics) ([]tagPose, error) {
+	tags, err := fs.detector.Detect(img)
+	if err != nil {
+		return nil, err
+	}
+	poses := make([]tagPose, 0, len(tags))
+	for i := range tags {
+		pose, err := fiducial.EstimatePose(&tags[i], fs.conf.sizeMM(tags[i].ID), intrinsics)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot estimate pose of tag %s", tags[i].Label())
+		}
+		poses = append(poses, tagPose{Tag: tags[i], pose: pose})
+	}
+	return poses, nil
+}
+
+// DoCommand answers the "get_tag_poses" command.
+func (fs *fiducialService) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	arg, ok := cmd[TagPosesCommand]
+	if !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+	args, _ := arg.(map[string]interface{})
+	cameraName, _ := args["camera"].(string)
+	if cameraName == "" {
+		return nil, errors.Errorf("%s needs a camera", TagPosesCommand)
+	}
+	frame, _ := args["frame"].(string)
+	if frame == "" {
+		frame = cameraName
+	}
+	cam, err := camera.FromRobot(fs.r, cameraName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not find camera named %s", cameraName)
+	}
+	poses, err := fs.cameraTagPoses(ctx, cam)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]interface{}, 0, len(poses))
+	for _, tag := range poses {
+		pif := referenceframe.NewPoseInFrame(cameraName, tag.pose)
+		if frame != cameraName {
+			pif, err = fs.r.TransformPose(ctx, pif, frame, nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+		tags = append(tags, tagToMap(&tag.Tag, pif))
+	}
+	return map[string]interface{}{"tags": tags}, nil
+}
+
+func tagToMap(tag *fiducial.Tag, pif *referenceframe.PoseInFrame) map[string]interface{} {
+	pt := pif.Pose().Point()
+	o := pif.Pose().Orientation().OrientationVectorDegrees()
+	return map[string]interface{}{
+		"id":     tag.ID,
+		"family": tag.Family,
+		"label":  tag.Label(),
+		"frame":  pif.Parent(),
+		"x":      pt.X,
+		"y":      pt.Y,
+		"z":      pt.Z,
+		"o_x":    o.OX,
+		"o_y":    o.OY,
+		"o_z":    o.OZ,
+		"theta":  o.Theta,
+	}
+}
@@ -0,0 +1,153 @@
+package fiducialdetector
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"github.com/edaniels/gostream"
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/fiducial"
+)
+
+var testIntrinsics = &transform.PinholeCameraIntrinsics{Width: 640, Height: 480, Fx: 500, Fy: 500, Ppx: 320, Ppy: 240}
+
+// fakeDetector finds the tag with ID 3 and the given size at the given pose in every image.
+type fakeDetector struct {
+	pose   spatialmath.Pose
+	sizeMM float64
+	closed bool
+}
+
+func (fd *fakeDetector) Detect(img image.Image) ([]fiducial.Tag, error) {
+	tag := fiducial.Tag{Family: fiducial.FamilyTag36h11, ID: 3}
+	half := fd.sizeMM / 2
+	for i, pt := range []r3.Vector{{-half, half, 0}, {half, half, 0}, {half, -half, 0}, {-half, -half, 0}} {
+		p := spatialmath.Compose(fd.pose, spatialmath.NewPoseFromPoint(pt)).Point()
+		tag.Corners[i] = r2.Point{X: p.X/p.Z*testIntrinsics.Fx + testIntrinsics.Ppx, Y: p.Y/p.Z*testIntrinsics.Fy + testIntrinsics.Ppy}
+	}
+	return []fiducial.Tag{tag}, nil
+}
+
+func (fd *fakeDetector) Close() error {
+	fd.closed = true
+	return nil
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := &Config{}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "tag_family")
+
+	cfg = &Config{Family: "aruco_4x4_50", TagSizeMM: 100}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unsupported tag family")
+
+	cfg = &Config{Family: fiducial.FamilyTag36h11}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "tag_size_mm")
+
+	cfg = &Config{Family: fiducial.FamilyTag36h11, TagSizeMM: 100, TagSizesMM: map[string]float64{"dock": 50}}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not a tag ID")
+
+	cfg = &Config{Family: fiducial.FamilyTag36h11, TagSizeMM: 100, TagSizesMM: map[string]float64{"3": 50}}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.sizeMM(3), test.ShouldEqual, 50)
+	test.That(t, cfg.sizeMM(4), test.ShouldEqual, 100)
+}
+
+func TestFiducialDetector(t *testing.T) {
+	ctx := context.Background()
+	tagPose := spatialmath.NewPose(r3.Vector{X: 50, Y: -20, Z: 800}, &spatialmath.EulerAngles{Roll: 0.2, Pitch: 0.1})
+	detector := &fakeDetector{pose: tagPose, sizeMM: 50}
+
+	cam := &inject.Camera{}
+	cam.StreamFunc = func(ctx context.Context, errHandlers ...gostream.ErrorHandler) (gostream.VideoStream, error) {
+		return gostream.NewEmbeddedVideoStreamFromReader(
+			gostream.VideoReaderFunc(func(ctx context.Context) (image.Image, func(), error) {
+				return image.NewGray(image.Rect(0, 0, 640, 480)), func() {}, nil
+			}),
+		), nil
+	}
+	cam.PropertiesFunc = func(ctx context.Context) (camera.Properties, error) {
+		return camera.Properties{IntrinsicParams: testIntrinsics}, nil
+	}
+	r := &inject.Robot{}
+	r.ResourceNamesFunc = func() []resource.Name {
+		return []resource.Name{camera.Named("cam")}
+	}
+	r.ResourceByNameFunc = func(n resource.Name) (resource.Resource, error) {
+		if n.Name == "cam" {
+			return cam, nil
+		}
+		return nil, resource.NewNotFoundError(n)
+	}
+	// the camera is 100mm above the world origin
+	r.TransformPoseFunc = func(
+		ctx context.Context,
+		pose *referenceframe.PoseInFrame,
+		dst string,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) (*referenceframe.PoseInFrame, error) {
+		offset := spatialmath.NewPoseFromPoint(r3.Vector{Z: 100})
+		return referenceframe.NewPoseInFrame(dst, spatialmath.Compose(offset, pose.Pose())), nil
+	}
+
+	conf := &Config{Family: fiducial.FamilyTag36h11, TagSizeMM: 100, TagSizesMM: map[string]float64{"3": 50}}
+	svc, err := newFiducialService(vision.Named("tags"), conf, r, detector)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, svc.Name(), test.ShouldResemble, vision.Named("tags"))
+
+	dets, err := svc.Detections(ctx, image.NewGray(image.Rect(0, 0, 640, 480)), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dets, test.ShouldHaveLength, 1)
+	test.That(t, dets[0].Label(), test.ShouldEqual, "tag36h11:3")
+	test.That(t, dets[0].Score(), test.ShouldEqual, 1.)
+
+	objects, err := svc.GetObjectPointClouds(ctx, "cam", nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, objects, test.ShouldHaveLength, 1)
+	test.That(t, objects[0].Geometry.Label(), test.ShouldEqual, "tag36h11:3")
+	test.That(t, spatialmath.PoseAlmostEqualEps(objects[0].Geometry.Pose(), tagPose, 1e-3), test.ShouldBeTrue)
+
+	resp, err := svc.DoCommand(ctx, map[string]interface{}{TagPosesCommand: map[string]interface{}{"camera": "cam"}})
+	test.That(t, err, test.ShouldBeNil)
+	tags, ok := resp["tags"].([]interface{})
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, tags, test.ShouldHaveLength, 1)
+	tag, ok := tags[0].(map[string]interface{})
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, tag["id"], test.ShouldEqual, 3)
+	test.That(t, tag["frame"], test.ShouldEqual, "cam")
+	test.That(t, tag["z"], test.ShouldAlmostEqual, 800, 1e-3)
+
+	resp, err = svc.DoCommand(ctx, map[string]interface{}{TagPosesCommand: map[string]interface{}{"camera": "cam", "frame": "world"}})
+	test.That(t, err, test.ShouldBeNil)
+	tag = resp["tags"].([]interface{})[0].(map[string]interface{})
+	test.That(t, tag["frame"], test.ShouldEqual, "world")
+	test.That(t, tag["z"], test.ShouldAlmostEqual, 900, 1e-3)
+
+	_, err = svc.DoCommand(ctx, map[string]interface{}{TagPosesCommand: map[string]interface{}{}})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = svc.DoCommand(ctx, map[string]interface{}{"foo": "bar"})
+	test.That(t, err, test.ShouldEqual, resource.ErrDoUnimplemented)
+
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+	test.That(t, detector.closed, test.ShouldBeTrue)
+}
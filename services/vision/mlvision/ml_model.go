@@ -14,12 +14,14 @@ import (
 	"github.com/montanaflynn/stats"
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
+	goutils "go.viam.com/utils"
 
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/services/mlmodel"
 	"go.viam.com/rdk/services/vision"
 	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/objectdetection"
 )
 
 var model = resource.DefaultModelFamily.WithModel("mlmodel")
@@ -52,8 +54,54 @@ func init() {
 
 // MLModelConfig specifies the parameters needed to turn an ML model into a vision Model.
 type MLModelConfig struct {
-	resource.TriviallyValidateConfig
 	ModelName string `json:"mlmodel_name"`
+	// The following only apply to detections, to tune an off-the-shelf model. The labels are remapped first, so the
+	// confidences and non-max suppression use the remapped labels.
+	RemapLabels       map[string]string  `json:"remap_labels,omitempty"`
+	DefaultConfidence float64            `json:"default_minimum_confidence,omitempty"`
+	LabelConfidences  map[string]float64 `json:"label_confidences,omitempty"`
+	// NMSIoUThreshold turns on non-max suppression of overlapping detections with an IoU above it.
+	NMSIoUThreshold  float64 `json:"nms_iou_threshold,omitempty"`
+	NMSClassAgnostic bool    `json:"nms_class_agnostic,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *MLModelConfig) Validate(path string) ([]string, error) {
+	if conf.DefaultConfidence < 0 || conf.DefaultConfidence > 1 {
+		return nil, goutils.NewConfigValidationError(path,
+			errors.Errorf("default_minimum_confidence must be between 0 and 1, got %v", conf.DefaultConfidence))
+	}
+	for label, c := range conf.LabelConfidences {
+		if c < 0 || c > 1 {
+			return nil, goutils.NewConfigValidationError(path,
+				errors.Errorf("label_confidences for %q must be between 0 and 1, got %v", label, c))
+		}
+	}
+	if conf.NMSIoUThreshold < 0 || conf.NMSIoUThreshold > 1 {
+		return nil, goutils.NewConfigValidationError(path,
+			errors.Errorf("nms_iou_threshold must be between 0 and 1, got %v", conf.NMSIoUThreshold))
+	}
+	return nil, nil
+}
+
+// postprocessor returns the postprocessing of the detections of the model that the config asks for.
+func (conf *MLModelConfig) postprocessor() objectdetection.Postprocessor {
+	var posts []objectdetection.Postprocessor
+	if len(conf.RemapLabels) > 0 {
+		posts = append(posts, objectdetection.NewLabelMapper(conf.RemapLabels))
+	}
+	if conf.DefaultConfidence > 0 || len(conf.LabelConfidences) > 0 {
+		posts = append(posts, objectdetection.NewLabelScoreFilter(conf.LabelConfidences, conf.DefaultConfidence))
+	}
+	if conf.NMSIoUThreshold > 0 {
+		posts = append(posts, objectdetection.NewNMSFilter(conf.NMSIoUThreshold, conf.NMSClassAgnostic))
+	}
+	return func(dets []objectdetection.Detection) []objectdetection.Detection {
+		for _, post := range posts {
+			dets = post(dets)
+		}
+		return dets
+	}
 }
 
 func registerMLModelVisionService(
@@ -85,6 +133,9 @@ func registerMLModelVisionService(
 	}
 
 	detectorFunc, err := attemptToBuildDetector(mlm)
+	if err == nil {
+		detectorFunc, err = objectdetection.Build(nil, detectorFunc, params.postprocessor())
+	}
 	if err != nil {
 		logger.Infow("error turning ml model into a detector", "model", params.ModelName, "error", err)
 	} else {
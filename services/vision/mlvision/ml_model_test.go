@@ -2,6 +2,7 @@ package mlvision
 
 import (
 	"context"
+	"image"
 	"sync"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 	"go.viam.com/rdk/services/mlmodel/tflitecpu"
 	"go.viam.com/rdk/testutils/inject"
 	"go.viam.com/rdk/vision/classification"
+	"go.viam.com/rdk/vision/objectdetection"
 )
 
 func BenchmarkAddMLVisionModel(b *testing.B) {
@@ -301,6 +303,39 @@ func TestMoreMLDetectors(t *testing.T) {
 	test.That(t, gotDetections[1].Label(), test.ShouldResemble, "Dog")
 }
 
+func TestMLModelConfigPostprocessor(t *testing.T) {
+	conf := &MLModelConfig{LabelConfidences: map[string]float64{"Dog": 1.5}}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "label_confidences for \"Dog\" must be between 0 and 1")
+
+	dets := []objectdetection.Detection{
+		objectdetection.NewDetection(image.Rect(0, 0, 100, 100), 0.9, "Dog"),
+		objectdetection.NewDetection(image.Rect(0, 0, 90, 100), 0.7, "Puppy"),
+		objectdetection.NewDetection(image.Rect(200, 0, 300, 100), 0.3, "Cat"),
+		objectdetection.NewDetection(image.Rect(0, 200, 100, 300), 0.2, "Person"),
+	}
+	// with nothing configured the detections are left alone
+	conf = &MLModelConfig{}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.postprocessor()(dets), test.ShouldResemble, dets)
+
+	conf = &MLModelConfig{
+		RemapLabels:       map[string]string{"Puppy": "Dog"},
+		DefaultConfidence: 0.5,
+		LabelConfidences:  map[string]float64{"Cat": 0.25},
+		NMSIoUThreshold:   0.5,
+	}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	got := conf.postprocessor()(dets)
+	test.That(t, got, test.ShouldHaveLength, 2)
+	test.That(t, got[0].Label(), test.ShouldEqual, "Dog")
+	test.That(t, got[0].Score(), test.ShouldEqual, 0.9)
+	test.That(t, got[1].Label(), test.ShouldEqual, "Cat")
+}
+
 func TestMoreMLClassifiers(t *testing.T) {
 	// Test that mobileNet classifier gives expected output on the redpanda image
 	ctx := context.Background()
@@ -0,0 +1,66 @@
+// Package motiondetector flags the frames of a camera with motion, by subtracting a model of the scene's background
+// learned from the previous frames.
+package motiondetector
+
+import (
+	"context"
+	"image"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/classification"
+	objdet "go.viam.com/rdk/vision/objectdetection"
+)
+
+var model = resource.DefaultModelFamily.WithModel("motion_detector")
+
+func init() {
+	resource.RegisterService(vision.API, model, resource.Registration[vision.Service, *objdet.MotionDetectorConfig]{
+		DeprecatedRobotConstructor: func(ctx context.Context, r any, c resource.Config, logger golog.Logger) (vision.Service, error) {
+			attrs, err := resource.NativeConfig[*objdet.MotionDetectorConfig](c)
+			if err != nil {
+				return nil, err
+			}
+			actualR, err := utils.AssertType[robot.Robot](r)
+			if err != nil {
+				return nil, err
+			}
+			return registerMotionDetector(ctx, c.ResourceName().Name, attrs, actualR)
+		},
+	})
+}
+
+// registerMotionDetector creates a new motion detector from the config. Its detections are the moving parts of a
+// frame, and its classification is the motion label scored with the fraction of the frame that moved, so that data
+// capture can be filtered on it. The background model learns from every frame given to either, so a single service
+// should only be used with a single camera.
+func registerMotionDetector(
+	ctx context.Context,
+	name string,
+	conf *objdet.MotionDetectorConfig,
+	r robot.Robot,
+) (vision.Service, error) {
+	_, span := trace.StartSpan(ctx, "service::vision::registerMotionDetector")
+	defer span.End()
+	if conf == nil {
+		return nil, errors.New("config for motion detector cannot be nil")
+	}
+	md, err := objdet.NewMotionDetector(conf)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error registering motion detector %q", name)
+	}
+	classifier := func(ctx context.Context, img image.Image) (classification.Classifications, error) {
+		_, fraction := md.Update(img)
+		if fraction < md.MinMotionFraction() {
+			return classification.Classifications{}, nil
+		}
+		return classification.Classifications{classification.NewClassification(fraction, md.Label())}, nil
+	}
+	return vision.NewService(name, r, nil, classifier, md.Detect, nil)
+}
@@ -0,0 +1,58 @@
+package motiondetector
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func frame(square image.Rectangle) image.Image {
+	img := image.NewGray(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetGray(x, y, color.Gray{100})
+			if image.Pt(x, y).In(square) {
+				img.SetGray(x, y, color.Gray{250})
+			}
+		}
+	}
+	return img
+}
+
+func TestMotionDetector(t *testing.T) {
+	ctx := context.Background()
+	r := &inject.Robot{}
+	srv, err := registerMotionDetector(ctx, "test_md", &objectdetection.MotionDetectorConfig{}, r)
+	test.That(t, err, test.ShouldBeNil)
+
+	// Does implement Classifications
+	for i := 0; i < 3; i++ {
+		classifications, err := srv.Classifications(ctx, frame(image.Rectangle{}), 1, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, classifications, test.ShouldHaveLength, 0)
+	}
+	classifications, err := srv.Classifications(ctx, frame(image.Rect(0, 0, 16, 16)), 1, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, classifications, test.ShouldHaveLength, 1)
+	test.That(t, classifications[0].Label(), test.ShouldEqual, "motion")
+	test.That(t, classifications[0].Score(), test.ShouldAlmostEqual, 16./192.)
+
+	// Does implement Detections
+	dets, err := srv.Detections(ctx, frame(image.Rect(32, 16, 48, 32)), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dets, test.ShouldNotBeEmpty)
+
+	// with error - bad parameters
+	_, err = registerMotionDetector(ctx, "test_md", &objectdetection.MotionDetectorConfig{LearningRate: 2}, r)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "learning_rate must be between")
+
+	// with error - nil parameters
+	_, err = registerMotionDetector(ctx, "test_md", nil, r)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot be nil")
+}
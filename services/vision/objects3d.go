@@ -0,0 +1,75 @@
+package vision
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/spatialmath"
+	viz "go.viam.com/rdk/vision"
+	"go.viam.com/rdk/vision/segmentation"
+)
+
+// DetectionObjectsFromCamera returns the detections of the vision service in the next image of the camera as objects
+// in the frame of the camera, located with the depth map aligned with the image. The camera must return point clouds,
+// which the aligned image and depth map are made from. The points of each object are those of its bounding box
+// within depthBandMM of their median depth, or segmentation.DefaultDepthBandMM if 0.
+func DetectionObjectsFromCamera(
+	ctx context.Context,
+	r robot.Robot,
+	svc Service,
+	cameraName string,
+	depthBandMM float64,
+) ([]*viz.Object, error) {
+	cam, err := camera.FromRobot(r, cameraName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not find camera named %s", cameraName)
+	}
+	proj, err := cam.Projector(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pc, err := cam.NextPointCloud(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get point cloud from %s", cameraName)
+	}
+	img, dm, err := proj.PointCloudToRGBD(pc)
+	if err != nil {
+		return nil, err
+	}
+	dets, err := svc.Detections(ctx, img, nil)
+	if err != nil {
+		return nil, err
+	}
+	return segmentation.DetectionsToObjects(dets, img, dm, proj, depthBandMM)
+}
+
+// ObjectGeometriesInFrame returns the geometries of objects seen by the camera in the given frame of the robot's frame
+// system, such as the world frame, so that they can be added to a motion world state as obstacles or pick targets.
+func ObjectGeometriesInFrame(
+	ctx context.Context,
+	r robot.Robot,
+	cameraName string,
+	objects []*viz.Object,
+	frame string,
+) (*referenceframe.GeometriesInFrame, error) {
+	cameraPose := spatialmath.NewZeroPose()
+	if frame != cameraName {
+		pif, err := r.TransformPose(ctx, referenceframe.NewPoseInFrame(cameraName, cameraPose), frame, nil)
+		if err != nil {
+			return nil, err
+		}
+		cameraPose = pif.Pose()
+	}
+	geometries := make([]spatialmath.Geometry, 0, len(objects))
+	for _, obj := range objects {
+		if obj.Geometry == nil {
+			continue
+		}
+		geometries = append(geometries, obj.Geometry.Transform(cameraPose))
+	}
+	return referenceframe.NewGeometriesInFrame(frame, geometries), nil
+}
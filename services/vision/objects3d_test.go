@@ -0,0 +1,98 @@
+package vision_test
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestDetectionObjectsFromCamera(t *testing.T) {
+	ctx := context.Background()
+	intrinsics := &transform.PinholeCameraIntrinsics{Width: 100, Height: 100, Fx: 100, Fy: 100, Ppx: 50, Ppy: 50}
+	cam := &inject.Camera{}
+	cam.ProjectorFunc = func(ctx context.Context) (transform.Projector, error) {
+		return intrinsics, nil
+	}
+	// a 20x20 pixel object 500mm away in front of a wall 2000mm away
+	cam.NextPointCloudFunc = func(ctx context.Context) (pointcloud.PointCloud, error) {
+		pc := pointcloud.New()
+		for y := 0; y < 100; y++ {
+			for x := 0; x < 100; x++ {
+				z := 2000.
+				if x >= 40 && x < 60 && y >= 40 && y < 60 {
+					z = 500
+				}
+				px, py, pz := intrinsics.PixelToPoint(float64(x), float64(y), z)
+				if err := pc.Set(pointcloud.NewVector(px, py, pz), pointcloud.NewColoredData(color.NRGBA{255, 0, 0, 255})); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return pc, nil
+	}
+	svc := &inject.VisionService{}
+	svc.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{objectdetection.NewDetection(image.Rect(30, 30, 70, 70), 0.9, "box")}, nil
+	}
+	r := &inject.Robot{}
+	r.ResourceNamesFunc = func() []resource.Name {
+		return []resource.Name{camera.Named("cam")}
+	}
+	r.ResourceByNameFunc = func(n resource.Name) (resource.Resource, error) {
+		if n.Name == "cam" {
+			return cam, nil
+		}
+		return nil, resource.NewNotFoundError(n)
+	}
+	// the camera is 100mm above the world origin
+	r.TransformPoseFunc = func(
+		ctx context.Context,
+		pose *referenceframe.PoseInFrame,
+		dst string,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) (*referenceframe.PoseInFrame, error) {
+		offset := spatialmath.NewPoseFromPoint(r3.Vector{Z: 100})
+		return referenceframe.NewPoseInFrame(dst, spatialmath.Compose(offset, pose.Pose())), nil
+	}
+
+	_, err := vision.DetectionObjectsFromCamera(ctx, r, svc, "missing", 0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	objects, err := vision.DetectionObjectsFromCamera(ctx, r, svc, "cam", 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, objects, test.ShouldHaveLength, 1)
+	// only the points of the object, and none of the wall behind it, are kept
+	test.That(t, objects[0].Size(), test.ShouldEqual, 400)
+	test.That(t, objects[0].Geometry.Label(), test.ShouldEqual, "box")
+	center := objects[0].Geometry.Pose().Point()
+	test.That(t, center.X, test.ShouldAlmostEqual, -2.5, 1e-6)
+	test.That(t, center.Y, test.ShouldAlmostEqual, -2.5, 1e-6)
+	test.That(t, center.Z, test.ShouldAlmostEqual, 500, 1e-6)
+
+	geometries, err := vision.ObjectGeometriesInFrame(ctx, r, "cam", objects, "cam")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, geometries.Parent(), test.ShouldEqual, "cam")
+	test.That(t, geometries.Geometries(), test.ShouldHaveLength, 1)
+	test.That(t, geometries.Geometries()[0].Pose().Point().Z, test.ShouldAlmostEqual, 500, 1e-6)
+
+	geometries, err = vision.ObjectGeometriesInFrame(ctx, r, "cam", objects, referenceframe.World)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, geometries.Parent(), test.ShouldEqual, referenceframe.World)
+	test.That(t, geometries.Geometries(), test.ShouldHaveLength, 1)
+	test.That(t, geometries.Geometries()[0].Pose().Point().Z, test.ShouldAlmostEqual, 600, 1e-6)
+	test.That(t, geometries.Geometries()[0].Label(), test.ShouldEqual, "box")
+}
@@ -0,0 +1,189 @@
+// Package objecttracker follows the detections of another vision service across frames,
+// giving each object a stable track ID and an estimate of its velocity.
+package objecttracker
+
+import (
+	"context"
+	"image"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+var model = resource.DefaultModelFamily.WithModel("object_tracker")
+
+func init() {
+	resource.RegisterService(vision.API, model, resource.Registration[vision.Service, *Config]{
+		DeprecatedRobotConstructor: func(ctx context.Context, r any, c resource.Config, logger golog.Logger) (vision.Service, error) {
+			attrs, err := resource.NativeConfig[*Config](c)
+			if err != nil {
+				return nil, err
+			}
+			actualR, err := utils.AssertType[robot.Robot](r)
+			if err != nil {
+				return nil, err
+			}
+			return registerObjectTracker(ctx, c.ResourceName(), attrs, actualR)
+		},
+	})
+}
+
+// Config are the parameters of the object tracker. The tracker parameters are those of
+// objectdetection.TrackerConfig, and zero values are replaced by its defaults.
+type Config struct {
+	DetectorName       string  `json:"detector_name"`
+	IoUThreshold       float64 `json:"iou_threshold,omitempty"`
+	MaxAge             int     `json:"max_age_frames,omitempty"`
+	MinHits            int     `json:"min_hits,omitempty"`
+	HighScoreThreshold float64 `json:"high_score_threshold,omitempty"`
+}
+
+func (cfg *Config) trackerConfig() objectdetection.TrackerConfig {
+	return objectdetection.TrackerConfig{
+		IoUThreshold:       cfg.IoUThreshold,
+		MaxAge:             cfg.MaxAge,
+		MinHits:            cfg.MinHits,
+		HighScoreThreshold: cfg.HighScoreThreshold,
+	}
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.DetectorName == "" {
+		return nil, goutils.NewConfigValidationFieldRequiredError(path, "detector_name")
+	}
+	trackerConf := cfg.trackerConfig()
+	if err := trackerConf.Validate(path); err != nil {
+		return nil, err
+	}
+	return []string{cfg.DetectorName}, nil
+}
+
+// trackerService wraps a detector and tracks its detections, keeping one tracker per camera.
+type trackerService struct {
+	vision.Service
+	name     resource.Name
+	detector vision.Service
+	conf     objectdetection.TrackerConfig
+
+	mu       sync.Mutex
+	trackers map[string]*objectdetection.Tracker
+}
+
+func registerObjectTracker(
+	ctx context.Context,
+	name resource.Name,
+	conf *Config,
+	r robot.Robot,
+) (vision.Service, error) {
+	_, span := trace.StartSpan(ctx, "service::vision::registerObjectTracker")
+	defer span.End()
+	if conf == nil {
+		return nil, errors.New("config for object tracker cannot be nil")
+	}
+	detector, err := vision.FromRobot(r, conf.DetectorName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not find necessary dependency, detector %q", conf.DetectorName)
+	}
+	svc := &trackerService{
+		name:     name,
+		detector: detector,
+		conf:     conf.trackerConfig(),
+		trackers: map[string]*objectdetection.Tracker{},
+	}
+	// the wrapped service answers the classifier and segmenter methods, which the tracker does not implement
+	detectorFunc := func(ctx context.Context, img image.Image) ([]objectdetection.Detection, error) {
+		return svc.Detections(ctx, img, nil)
+	}
+	svc.Service, err = vision.NewService(name.Name, r, nil, nil, detectorFunc, nil)
+	if err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// Name returns the name of the service.
+func (ts *trackerService) Name() resource.Name {
+	return ts.name
+}
+
+func (ts *trackerService) tracker(cameraName string) *objectdetection.Tracker {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	t, ok := ts.trackers[cameraName]
+	if !ok {
+		t = objectdetection.NewTracker(ts.conf)
+		ts.trackers[cameraName] = t
+	}
+	return t
+}
+
+func (ts *trackerService) track(cameraName string, dets []objectdetection.Detection, at time.Time) []objectdetection.Detection {
+	tracked := ts.tracker(cameraName).Update(dets, at)
+	out := make([]objectdetection.Detection, 0, len(tracked))
+	for _, d := range tracked {
+		out = append(out, d)
+	}
+	return out
+}
+
+// Detections returns the tracked detections of the given image. All images passed in share one set of tracks.
+func (ts *trackerService) Detections(
+	ctx context.Context,
+	img image.Image,
+	extra map[string]interface{},
+) ([]objectdetection.Detection, error) {
+	ctx, span := trace.StartSpan(ctx, "service::vision::objecttracker::Detections")
+	defer span.End()
+	dets, err := ts.detector.Detections(ctx, img, extra)
+	if err != nil {
+		return nil, err
+	}
+	return ts.track("", dets, time.Now()), nil
+}
+
+// DetectionsFromCamera returns the tracked detections of the next image from the given camera.
+func (ts *trackerService) DetectionsFromCamera(
+	ctx context.Context,
+	cameraName string,
+	extra map[string]interface{},
+) ([]objectdetection.Detection, error) {
+	ctx, span := trace.StartSpan(ctx, "service::vision::objecttracker::DetectionsFromCamera")
+	defer span.End()
+	dets, err := ts.detector.DetectionsFromCamera(ctx, cameraName, extra)
+	if err != nil {
+		return nil, err
+	}
+	return ts.track(cameraName, dets, time.Now()), nil
+}
+
+// DoCommand answers the "get_tracks" command.
+func (ts *trackerService) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	arg, ok := cmd[vision.TracksCommand]
+	if !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+	cameraName, _ := arg.(string)
+	ts.mu.Lock()
+	t, ok := ts.trackers[cameraName]
+	ts.mu.Unlock()
+	if !ok {
+		return vision.TracksResponse(nil), nil
+	}
+	return vision.TracksResponse(t.Tracks()), nil
+}
+
+// Close does nothing, as the detector is a separate resource.
+func (ts *trackerService) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,103 @@
+package objecttracker
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// movingDetector detects one object that moves right 5 pixels with every image.
+type movingDetector struct {
+	frame int
+}
+
+func (m *movingDetector) Detect(context.Context, image.Image) ([]objectdetection.Detection, error) {
+	x := 5 * m.frame
+	m.frame++
+	return []objectdetection.Detection{objectdetection.NewDetection(image.Rect(x, 10, x+40, 50), 0.9, "cat")}, nil
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := &Config{}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "detector_name")
+
+	cfg = &Config{DetectorName: "detector", IoUThreshold: 2}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cfg = &Config{DetectorName: "detector"}
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"detector"})
+}
+
+func TestObjectTracker(t *testing.T) {
+	r := &inject.Robot{}
+	m := &movingDetector{}
+	detector, err := vision.NewService("detector", r, nil, nil, m.Detect, nil)
+	test.That(t, err, test.ShouldBeNil)
+	r.ResourceNamesFunc = func() []resource.Name {
+		return []resource.Name{vision.Named("detector")}
+	}
+	r.ResourceByNameFunc = func(n resource.Name) (resource.Resource, error) {
+		if n.Name == "detector" {
+			return detector, nil
+		}
+		return nil, resource.NewNotFoundError(n)
+	}
+
+	_, err = registerObjectTracker(context.Background(), vision.Named("tracker"), &Config{DetectorName: "missing"}, r)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	svc, err := registerObjectTracker(context.Background(), vision.Named("tracker"), &Config{DetectorName: "detector", MinHits: 2}, r)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, svc.Name(), test.ShouldResemble, vision.Named("tracker"))
+
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	dets, err := svc.Detections(context.Background(), img, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dets, test.ShouldBeEmpty)
+
+	var id int
+	for i := 0; i < 3; i++ {
+		dets, err = svc.Detections(context.Background(), img, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dets, test.ShouldHaveLength, 1)
+		tracked, ok := dets[0].(objectdetection.TrackedDetection)
+		test.That(t, ok, test.ShouldBeTrue)
+		if i == 0 {
+			id = tracked.TrackID()
+		}
+		test.That(t, tracked.TrackID(), test.ShouldEqual, id)
+		test.That(t, tracked.Label(), test.ShouldEqual, "cat")
+	}
+
+	resp, err := svc.DoCommand(context.Background(), map[string]interface{}{vision.TracksCommand: ""})
+	test.That(t, err, test.ShouldBeNil)
+	tracks, ok := resp["tracks"].([]interface{})
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, tracks, test.ShouldHaveLength, 1)
+	track, ok := tracks[0].(map[string]interface{})
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, track["id"], test.ShouldEqual, id)
+	test.That(t, track["x_min"], test.ShouldEqual, 15)
+
+	resp, err = svc.DoCommand(context.Background(), map[string]interface{}{vision.TracksCommand: "other_camera"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["tracks"], test.ShouldBeEmpty)
+
+	_, err = svc.DoCommand(context.Background(), map[string]interface{}{"foo": "bar"})
+	test.That(t, err, test.ShouldEqual, resource.ErrDoUnimplemented)
+
+	_, err = svc.Classifications(context.Background(), img, 1, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -4,7 +4,11 @@ package register
 import (
 	// for vision models.
 	_ "go.viam.com/rdk/services/vision/colordetector"
+	_ "go.viam.com/rdk/services/vision/detectionpipeline"
 	_ "go.viam.com/rdk/services/vision/detectionstosegments"
+	_ "go.viam.com/rdk/services/vision/fiducialdetector"
 	_ "go.viam.com/rdk/services/vision/mlvision"
+	_ "go.viam.com/rdk/services/vision/motiondetector"
+	_ "go.viam.com/rdk/services/vision/objecttracker"
 	_ "go.viam.com/rdk/services/vision/radiusclustering"
 )
@@ -0,0 +1,106 @@
+package vision
+
+import (
+	"context"
+	"time"
+
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/vision/classification"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// DefaultStreamRate is how many frames per second are processed when streaming detections or classifications,
+// unless asked otherwise.
+const DefaultStreamRate = 5.
+
+// DetectionsResult is the detections of one frame of a stream, or the error getting them.
+type DetectionsResult struct {
+	Time       time.Time
+	Detections []objectdetection.Detection
+	Err        error
+}
+
+// ClassificationsResult is the classifications of one frame of a stream, or the error getting them.
+type ClassificationsResult struct {
+	Time            time.Time
+	Classifications classification.Classifications
+	Err             error
+}
+
+// StreamDetections gets the detections of the camera's frames from the service at the given rate, in frames per
+// second, and sends them on the channel, which is closed once ctx is done. A frame that takes longer than the period
+// delays the next one rather than piling up calls. Until the vision service API has a streaming RPC, a remote service
+// is called once per frame.
+func StreamDetections(
+	ctx context.Context,
+	svc Service,
+	cameraName string,
+	rate float64,
+	extra map[string]interface{},
+) <-chan DetectionsResult {
+	results := make(chan DetectionsResult)
+	goutils.PanicCapturingGo(func() {
+		defer close(results)
+		streamFrames(ctx, rate, func() bool {
+			dets, err := svc.DetectionsFromCamera(ctx, cameraName, extra)
+			if ctx.Err() != nil {
+				return false
+			}
+			select {
+			case results <- DetectionsResult{Time: time.Now(), Detections: dets, Err: err}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	})
+	return results
+}
+
+// StreamClassifications gets the top n classifications of the camera's frames from the service at the given rate,
+// in frames per second, and sends them on the channel, which is closed once ctx is done. Like StreamDetections, a
+// remote service is called once per frame.
+func StreamClassifications(
+	ctx context.Context,
+	svc Service,
+	cameraName string,
+	n int,
+	rate float64,
+	extra map[string]interface{},
+) <-chan ClassificationsResult {
+	results := make(chan ClassificationsResult)
+	goutils.PanicCapturingGo(func() {
+		defer close(results)
+		streamFrames(ctx, rate, func() bool {
+			classifications, err := svc.ClassificationsFromCamera(ctx, cameraName, n, extra)
+			if ctx.Err() != nil {
+				return false
+			}
+			select {
+			case results <- ClassificationsResult{Time: time.Now(), Classifications: classifications, Err: err}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	})
+	return results
+}
+
+// streamFrames calls frame at most rate times a second until it returns false or ctx is done.
+func streamFrames(ctx context.Context, rate float64, frame func() bool) {
+	if rate <= 0 {
+		rate = DefaultStreamRate
+	}
+	period := time.Duration(float64(time.Second) / rate)
+	for {
+		start := time.Now()
+		if !frame() {
+			return
+		}
+		if !goutils.SelectContextOrWait(ctx, period-time.Since(start)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package vision_test
+
+import (
+	"context"
+	"errors"
+	"image"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/classification"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestStreamDetections(t *testing.T) {
+	svc := &inject.VisionService{}
+	calls := 0
+	svc.DetectionsFromCameraFunc = func(
+		ctx context.Context,
+		cameraName string,
+		extra map[string]interface{},
+	) ([]objectdetection.Detection, error) {
+		calls++
+		if calls == 2 {
+			return nil, errors.New("no frame")
+		}
+		return []objectdetection.Detection{objectdetection.NewDetection(image.Rect(0, 0, 10, 10), 0.5, cameraName)}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	results := vision.StreamDetections(ctx, svc, "cam", 50, nil)
+	for i := 0; i < 3; i++ {
+		res := <-results
+		if i == 1 {
+			test.That(t, res.Err, test.ShouldBeError, "no frame")
+			continue
+		}
+		test.That(t, res.Err, test.ShouldBeNil)
+		test.That(t, res.Detections, test.ShouldHaveLength, 1)
+		test.That(t, res.Detections[0].Label(), test.ShouldEqual, "cam")
+	}
+	// three frames at 50 a second take at least two periods
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 40*time.Millisecond)
+
+	// the stream closes once the context is done
+	cancel()
+	_, ok := <-results
+	for ok {
+		_, ok = <-results
+	}
+}
+
+func TestStreamClassifications(t *testing.T) {
+	svc := &inject.VisionService{}
+	svc.ClassificationsFromCameraFunc = func(
+		ctx context.Context,
+		cameraName string,
+		n int,
+		extra map[string]interface{},
+	) (classification.Classifications, error) {
+		return classification.Classifications{classification.NewClassification(0.9, cameraName)}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := vision.StreamClassifications(ctx, svc, "cam", 1, 0, nil)
+	res := <-results
+	test.That(t, res.Err, test.ShouldBeNil)
+	test.That(t, res.Classifications, test.ShouldHaveLength, 1)
+	test.That(t, res.Classifications[0].Label(), test.ShouldEqual, "cam")
+
+	// the stream closes once the context is done
+	cancel()
+	_, ok := <-results
+	for ok {
+		_, ok = <-results
+	}
+}
@@ -0,0 +1,30 @@
+package vision
+
+import "go.viam.com/rdk/vision/objectdetection"
+
+// TracksCommand is the DoCommand key used to get the current tracks of a vision service that tracks objects, since
+// the vision API has no field for track IDs. Its argument is the name of the camera, or "" for the images passed to
+// Detections, and it returns the tracks as a list of maps under "tracks".
+const TracksCommand = "get_tracks"
+
+// TracksResponse returns the response to a "get_tracks" command with the given tracks.
+func TracksResponse(tracks []objectdetection.TrackedDetection) map[string]interface{} {
+	out := make([]interface{}, 0, len(tracks))
+	for _, d := range tracks {
+		box := d.BoundingBox()
+		vx, vy := d.Velocity()
+		out = append(out, map[string]interface{}{
+			"id":         d.TrackID(),
+			"label":      d.Label(),
+			"score":      d.Score(),
+			"x_min":      box.Min.X,
+			"y_min":      box.Min.Y,
+			"x_max":      box.Max.X,
+			"y_max":      box.Max.Y,
+			"velocity_x": vx,
+			"velocity_y": vy,
+			"hits":       d.Hits(),
+		})
+	}
+	return map[string]interface{}{"tracks": out}
+}
@@ -157,6 +157,12 @@ func (b *box) CollidesWith(g Geometry) (bool, error) {
 	if other, ok := g.(*point); ok {
 		return pointVsBoxCollision(other.position, b), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsBoxDistance(other, b) <= CollisionBuffer, nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return cylinderVsBoxDistance(other, b) <= CollisionBuffer, nil
+	}
 	return true, newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -173,6 +179,12 @@ func (b *box) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return pointVsBoxDistance(other.position, b), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsBoxDistance(other, b), nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return cylinderVsBoxDistance(other, b), nil
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -186,9 +198,15 @@ func (b *box) EncompassedBy(g Geometry) (bool, error) {
 	if other, ok := g.(*capsule); ok {
 		return boxInCapsule(b, other), nil
 	}
+	if other, ok := g.(*cylinder); ok {
+		return boxInCylinder(b, other), nil
+	}
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
 	return false, newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -254,6 +272,20 @@ func (b *box) toMesh() *mesh {
 	return b.mesh
 }
 
+// support returns the vertex of the box that is furthest in the given direction.
+func (b *box) support(dir r3.Vector) r3.Vector {
+	rm := b.rotationMatrix()
+	pt := b.pose.Point()
+	for i := 0; i < 3; i++ {
+		if axis := rm.Row(i); axis.Dot(dir) >= 0 {
+			pt = pt.Add(axis.Mul(b.halfSize[i]))
+		} else {
+			pt = pt.Sub(axis.Mul(b.halfSize[i]))
+		}
+	}
+	return pt
+}
+
 // rotationMatrix returns the cached matrix if it exists, and generates it if not.
 func (b *box) rotationMatrix() *RotationMatrix {
 	b.once.Do(func() { b.rotMatrix = b.pose.Orientation().RotationMatrix() })
@@ -170,6 +170,12 @@ func (c *capsule) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*sphere); ok {
 		return capsuleVsSphereDistance(c, other), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return capsuleVsMeshDistance(c, other), nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return cylinderVsCapsuleDistance(other, c), nil
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(c, g)
 }
 
@@ -183,9 +189,15 @@ func (c *capsule) EncompassedBy(g Geometry) (bool, error) {
 	if other, ok := g.(*sphere); ok {
 		return capsuleInSphere(c, other), nil
 	}
+	if other, ok := g.(*cylinder); ok {
+		return capsuleInCylinder(c, other), nil
+	}
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
 	return true, newCollisionTypeUnsupportedError(c, g)
 }
 
@@ -261,15 +273,11 @@ func capsuleVsBoxDistance(c *capsule, other *box) float64 {
 // IMPORTANT: meshes are not considered solid. A mesh is not guaranteed to represent an enclosed area. This will measure ONLY the distance
 // to the closest triangle in the mesh.
 func capsuleVsMeshDistance(c *capsule, other *mesh) float64 {
-	lowDist := math.Inf(1)
-	for _, t := range other.triangles {
-		// Measure distance to each mesh triangle
-		dist := capsuleVsTriangleDistance(c, t)
-		if dist < lowDist {
-			lowDist = dist
-		}
-	}
-	return lowDist
+	// every point of the capsule is within half its length of its center
+	return other.closestDistance(
+		func(lo, hi r3.Vector) float64 { return aabbPointDistance(lo, hi, c.center) - c.length/2 },
+		func(t *triangle) float64 { return capsuleVsTriangleDistance(c, t) },
+	)
 }
 
 func capsuleVsTriangleDistance(c *capsule, other *triangle) float64 {
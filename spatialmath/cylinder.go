@@ -0,0 +1,381 @@
+package spatialmath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
+
+	"go.viam.com/rdk/utils"
+)
+
+// cylinderRimPoints is the number of points around each rim of a cylinder used to check whether it is inside a curved geometry.
+const cylinderRimPoints = 360
+
+// cylinder is a collision geometry that represents a solid cylinder with flat ends. Its pose is the center of the cylinder, and its
+// axis runs along the Z axis of the pose.
+//
+// .....___________
+// ....|           |
+// .x..|-----O-----|..x
+// ....|___________|
+//
+// Length is the distance between the x's, the full height of the cylinder from one flat end to the other.
+type cylinder struct {
+	pose   Pose
+	radius float64
+	length float64
+	label  string
+
+	// These values are generated at geometry creation time and should not be altered by hand
+	center r3.Vector // Centerpoint of the cylinder as an r3.Vector, cached to prevent recalculation
+	axis   r3.Vector // Unit vector along the axis of the cylinder, cached to prevent recalculation
+}
+
+// NewCylinder instantiates a new cylinder Geometry.
+func NewCylinder(offset Pose, radius, length float64, label string) (Geometry, error) {
+	if radius <= 0 || length <= 0 {
+		return nil, newBadGeometryDimensionsError(&cylinder{})
+	}
+	return newCylinder(offset, radius, length, label), nil
+}
+
+func newCylinder(offset Pose, radius, length float64, label string) *cylinder {
+	return &cylinder{
+		pose:   offset,
+		radius: radius,
+		length: length,
+		label:  label,
+		center: offset.Point(),
+		axis:   Compose(offset, NewPoseFromPoint(r3.Vector{Z: 1})).Point().Sub(offset.Point()),
+	}
+}
+
+func (c *cylinder) MarshalJSON() ([]byte, error) {
+	config, err := NewGeometryConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// String returns a human readable string that represents the cylinder.
+func (c *cylinder) String() string {
+	return fmt.Sprintf("Type: Cylinder, Radius: %.0f, Length: %.0f", c.radius, c.length)
+}
+
+// Label returns the label of this cylinder.
+func (c *cylinder) Label() string {
+	return c.label
+}
+
+// SetLabel sets the label of this cylinder.
+func (c *cylinder) SetLabel(label string) {
+	c.label = label
+}
+
+// Pose returns the pose of the cylinder.
+func (c *cylinder) Pose() Pose {
+	return c.pose
+}
+
+// AlmostEqual compares the cylinder with another geometry and checks if they are equivalent.
+func (c *cylinder) AlmostEqual(g Geometry) bool {
+	other, ok := g.(*cylinder)
+	if !ok {
+		return false
+	}
+	return PoseAlmostEqual(c.pose, other.pose) &&
+		utils.Float64AlmostEqual(c.radius, other.radius, 1e-8) &&
+		utils.Float64AlmostEqual(c.length, other.length, 1e-8)
+}
+
+// Transform premultiplies the cylinder pose with a transform, allowing the cylinder to be moved in space.
+func (c *cylinder) Transform(toPremultiply Pose) Geometry {
+	return newCylinder(Compose(toPremultiply, c.pose), c.radius, c.length, c.label)
+}
+
+// ToProtobuf converts the cylinder to a Geometry proto message. The API has no cylinder geometry, so the cylinder is sent as the
+// capsule with the same axis and radius that encloses it, which is conservative for collision checking.
+func (c *cylinder) ToProtobuf() *commonpb.Geometry {
+	return &commonpb.Geometry{
+		Center: PoseToProtobuf(c.pose),
+		GeometryType: &commonpb.Geometry_Capsule{
+			Capsule: &commonpb.Capsule{
+				RadiusMm: c.radius,
+				LengthMm: c.length + 2*c.radius,
+			},
+		},
+		Label: c.label,
+	}
+}
+
+// CollidesWith checks if the given cylinder collides with the given geometry and returns true if it does.
+func (c *cylinder) CollidesWith(g Geometry) (bool, error) {
+	dist, err := c.DistanceFrom(g)
+	if err != nil {
+		return true, err
+	}
+	return dist <= CollisionBuffer, nil
+}
+
+// DistanceFrom returns the distance between the cylinder and the given geometry, or the penetration depth if they are in collision.
+// Distances to points, spheres and capsules are exact. Separation distances to boxes, cylinders and meshes are exact, while their
+// penetration depths may be conservative.
+func (c *cylinder) DistanceFrom(g Geometry) (float64, error) {
+	if other, ok := g.(*box); ok {
+		return cylinderVsBoxDistance(c, other), nil
+	}
+	if other, ok := g.(*sphere); ok {
+		return cylinderVsSphereDistance(c, other), nil
+	}
+	if other, ok := g.(*capsule); ok {
+		return cylinderVsCapsuleDistance(c, other), nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return cylinderVsCylinderDistance(c, other), nil
+	}
+	if other, ok := g.(*point); ok {
+		return cylinderVsPointDistance(c, other.position), nil
+	}
+	if other, ok := g.(*mesh); ok {
+		return cylinderVsMeshDistance(c, other), nil
+	}
+	return math.Inf(-1), newCollisionTypeUnsupportedError(c, g)
+}
+
+// EncompassedBy returns a bool describing if the cylinder is completely encompassed by the given geometry.
+func (c *cylinder) EncompassedBy(g Geometry) (bool, error) {
+	if other, ok := g.(*box); ok {
+		return cylinderInBox(c, other), nil
+	}
+	if other, ok := g.(*sphere); ok {
+		return cylinderInSphere(c, other), nil
+	}
+	if other, ok := g.(*capsule); ok {
+		return cylinderInCapsule(c, other), nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return cylinderInCylinder(c, other), nil
+	}
+	if _, ok := g.(*point); ok {
+		return false, nil
+	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
+	return false, newCollisionTypeUnsupportedError(c, g)
+}
+
+// ToPoints converts a cylinder geometry into []r3.Vector of points on its surface, spaced to give roughly resolution points per
+// square mm. If the resolution is not positive, defaultPointDensity is used.
+func (c *cylinder) ToPoints(resolution float64) []r3.Vector {
+	if resolution <= 0 {
+		resolution = defaultPointDensity
+	}
+	spacing := 1 / math.Sqrt(resolution)
+	ring := func(r, z float64) []r3.Vector {
+		count := math.Max(3, math.Ceil(2*math.Pi*r/spacing))
+		pts := make([]r3.Vector, 0, int(count))
+		for i := 0.; i < count; i++ {
+			theta := 2 * math.Pi * i / count
+			pts = append(pts, r3.Vector{X: r * math.Cos(theta), Y: r * math.Sin(theta), Z: z})
+		}
+		return pts
+	}
+
+	vecList := []r3.Vector{}
+	// rings along the curved side, including both rims
+	sideRings := math.Max(1, math.Ceil(c.length/spacing))
+	for i := 0.; i <= sideRings; i++ {
+		vecList = append(vecList, ring(c.radius, -c.length/2+c.length*i/sideRings)...)
+	}
+	// concentric rings on each flat end, inside the rims
+	capRings := math.Ceil(c.radius / spacing)
+	for _, z := range []float64{-c.length / 2, c.length / 2} {
+		vecList = append(vecList, r3.Vector{Z: z})
+		for i := 1.; i < capRings; i++ {
+			vecList = append(vecList, ring(c.radius*i/capRings, z)...)
+		}
+	}
+	return transformPointsToPose(vecList, c.pose)
+}
+
+// boundingRadius returns the radius of the smallest sphere about the center of the cylinder that contains it.
+func (c *cylinder) boundingRadius() float64 {
+	return math.Hypot(c.radius, c.length/2)
+}
+
+// support returns the point of the cylinder that is furthest in the given direction.
+func (c *cylinder) support(dir r3.Vector) r3.Vector {
+	along := dir.Dot(c.axis)
+	pt := c.center
+	if along >= 0 {
+		pt = pt.Add(c.axis.Mul(c.length / 2))
+	} else {
+		pt = pt.Sub(c.axis.Mul(c.length / 2))
+	}
+	if radial := dir.Sub(c.axis.Mul(along)); radial.Norm() > floatEpsilon*dir.Norm() {
+		pt = pt.Add(radial.Normalize().Mul(c.radius))
+	}
+	return pt
+}
+
+// rims returns evenly spaced points around both rims of the cylinder.
+func (c *cylinder) rims(count int) []r3.Vector {
+	pts := make([]r3.Vector, 0, 2*count)
+	for _, z := range []float64{-c.length / 2, c.length / 2} {
+		for i := 0; i < count; i++ {
+			theta := 2 * math.Pi * float64(i) / float64(count)
+			local := r3.Vector{X: c.radius * math.Cos(theta), Y: c.radius * math.Sin(theta), Z: z}
+			pts = append(pts, Compose(c.pose, NewPoseFromPoint(local)).Point())
+		}
+	}
+	return pts
+}
+
+// separatingAxes returns the axes along which the cylinder is most likely to be separated from a convex geometry with the given
+// face normals and edge directions, whose center is at the given point.
+func (c *cylinder) separatingAxes(center r3.Vector, normals, edges []r3.Vector) []r3.Vector {
+	toOther := center.Sub(c.center)
+	axes := append([]r3.Vector{c.axis, toOther, toOther.Sub(c.axis.Mul(toOther.Dot(c.axis)))}, normals...)
+	for _, edge := range edges {
+		axes = append(axes, c.axis.Cross(edge))
+	}
+	return axes
+}
+
+// cylinderVsPointDistance returns the signed distance from the surface of the cylinder to the point, which is negative when the point
+// is inside the cylinder.
+func cylinderVsPointDistance(c *cylinder, pt r3.Vector) float64 {
+	delta := pt.Sub(c.center)
+	along := delta.Dot(c.axis)
+	radialDist := delta.Sub(c.axis.Mul(along)).Norm() - c.radius
+	axialDist := math.Abs(along) - c.length/2
+	if radialDist <= 0 && axialDist <= 0 {
+		return math.Max(radialDist, axialDist)
+	}
+	return math.Hypot(math.Max(radialDist, 0), math.Max(axialDist, 0))
+}
+
+func cylinderVsSphereDistance(c *cylinder, s *sphere) float64 {
+	return cylinderVsPointDistance(c, s.pose.Point()) - s.radius
+}
+
+// cylinderVsCapsuleDistance finds the point on the capsule's segment with the smallest signed distance to the cylinder. The signed
+// distance to a convex geometry is convex, so it has a single minimum along the segment, which is found by golden section search.
+func cylinderVsCapsuleDistance(c *cylinder, other *capsule) float64 {
+	seg := other.segB.Sub(other.segA)
+	dist := func(t float64) float64 { return cylinderVsPointDistance(c, other.segA.Add(seg.Mul(t))) }
+	invPhi := (math.Sqrt(5) - 1) / 2
+	lo, hi := 0., 1.
+	x1, x2 := hi-invPhi*(hi-lo), lo+invPhi*(hi-lo)
+	d1, d2 := dist(x1), dist(x2)
+	for hi-lo > 1e-9 {
+		if d1 < d2 {
+			hi, x2, d2 = x2, x1, d1
+			x1 = hi - invPhi*(hi-lo)
+			d1 = dist(x1)
+		} else {
+			lo, x1, d1 = x1, x2, d2
+			x2 = lo + invPhi*(hi-lo)
+			d2 = dist(x2)
+		}
+	}
+	best := math.Min(math.Min(d1, d2), math.Min(dist(0), dist(1)))
+	return best - other.radius
+}
+
+func cylinderVsBoxDistance(c *cylinder, b *box) float64 {
+	rm := b.rotationMatrix()
+	axes := []r3.Vector{rm.Row(0), rm.Row(1), rm.Row(2)}
+	return convexDistance(c.support, b.support, c.separatingAxes(b.pose.Point(), axes, axes))
+}
+
+func cylinderVsCylinderDistance(c, other *cylinder) float64 {
+	toC := c.center.Sub(other.center)
+	axes := c.separatingAxes(other.center, []r3.Vector{other.axis, toC.Sub(other.axis.Mul(toC.Dot(other.axis)))}, []r3.Vector{other.axis})
+	return convexDistance(c.support, other.support, axes)
+}
+
+// IMPORTANT: meshes are not considered solid. A mesh is not guaranteed to represent an enclosed area. This will measure ONLY the distance
+// to the closest triangle in the mesh.
+func cylinderVsMeshDistance(c *cylinder, m *mesh) float64 {
+	return m.closestDistance(
+		func(lo, hi r3.Vector) float64 { return aabbPointDistance(lo, hi, c.center) - c.boundingRadius() },
+		func(t *triangle) float64 { return cylinderVsTriangleDistance(c, t) },
+	)
+}
+
+func cylinderVsTriangleDistance(c *cylinder, t *triangle) float64 {
+	centroid := t.p0.Add(t.p1).Add(t.p2).Mul(1. / 3)
+	edges := []r3.Vector{t.p1.Sub(t.p0), t.p2.Sub(t.p1), t.p0.Sub(t.p2)}
+	return convexDistance(c.support, t.support, c.separatingAxes(centroid, []r3.Vector{t.normal}, edges))
+}
+
+// cylinderInBox returns a bool describing if the given cylinder is fully encompassed by the given box, by checking how far the cylinder
+// extends along each of the box's face normals.
+func cylinderInBox(c *cylinder, b *box) bool {
+	rm := b.rotationMatrix()
+	for i := 0; i < 3; i++ {
+		normal := rm.Row(i)
+		center := b.pose.Point().Dot(normal)
+		if c.support(normal).Dot(normal)-center > b.halfSize[i] || center-c.support(normal.Mul(-1)).Dot(normal) > b.halfSize[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// cylinderInSphere returns a bool describing if the given cylinder is fully encompassed by the given sphere, by checking the point on
+// each rim that is furthest from the center of the sphere.
+func cylinderInSphere(c *cylinder, s *sphere) bool {
+	delta := s.pose.Point().Sub(c.center)
+	along := delta.Dot(c.axis)
+	radial := delta.Sub(c.axis.Mul(along)).Norm()
+	return math.Hypot(math.Abs(along)+c.length/2, radial+c.radius) <= s.radius
+}
+
+// cylinderInCapsule returns a bool describing if the given cylinder is encompassed by the given capsule, checked at points around its
+// rims.
+func cylinderInCapsule(c *cylinder, other *capsule) bool {
+	for _, pt := range c.rims(cylinderRimPoints) {
+		if capsuleVsPointDistance(other, pt) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// cylinderInCylinder returns a bool describing if the inner cylinder is encompassed by the outer cylinder, checked at points around its
+// rims.
+func cylinderInCylinder(inner, outer *cylinder) bool {
+	for _, pt := range inner.rims(cylinderRimPoints) {
+		if cylinderVsPointDistance(outer, pt) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// boxInCylinder returns a bool describing if the given box is fully encompassed by the given cylinder.
+func boxInCylinder(b *box, c *cylinder) bool {
+	for _, vertex := range b.vertices() {
+		if cylinderVsPointDistance(c, vertex) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// sphereInCylinder returns a bool describing if the given sphere is fully encompassed by the given cylinder.
+func sphereInCylinder(s *sphere, c *cylinder) bool {
+	return cylinderVsPointDistance(c, s.pose.Point()) <= -s.radius
+}
+
+// capsuleInCylinder returns a bool describing if the given capsule is fully encompassed by the given cylinder.
+func capsuleInCylinder(inner *capsule, c *cylinder) bool {
+	return cylinderVsPointDistance(c, inner.segA) <= -inner.radius && cylinderVsPointDistance(c, inner.segB) <= -inner.radius
+}
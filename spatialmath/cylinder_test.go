@@ -0,0 +1,78 @@
+package spatialmath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func makeTestCylinder(o Orientation, pt r3.Vector, radius, length float64) Geometry {
+	c, _ := NewCylinder(NewPose(pt, o), radius, length, "")
+	return c
+}
+
+func TestCylinderConstruction(t *testing.T) {
+	c := makeTestCylinder(&OrientationVectorDegrees{OX: 1}, r3.Vector{0, 0, 0.1}, 1, 6).(*cylinder)
+	test.That(t, R3VectorAlmostEqual(c.axis, r3.Vector{1, 0, 0}, 1e-9), test.ShouldBeTrue)
+	test.That(t, R3VectorAlmostEqual(c.support(r3.Vector{1, 1, 0}), r3.Vector{3, 1, 0.1}, 1e-9), test.ShouldBeTrue)
+
+	_, err := NewCylinder(NewZeroPose(), 0, 1, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewCylinder(NewZeroPose(), 1, -1, "")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestCylinderToProtobuf(t *testing.T) {
+	// the API has no cylinders, so they are sent as the capsule enclosing them
+	pose := NewPose(r3.Vector{1, 2, 3}, &OrientationVectorDegrees{OY: 1})
+	c, err := NewCylinder(pose, 2, 10, "cylinder")
+	test.That(t, err, test.ShouldBeNil)
+	sent, err := NewGeometryFromProto(c.ToProtobuf())
+	test.That(t, err, test.ShouldBeNil)
+	expected, err := NewCapsule(pose, 2, 14, "cylinder")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sent.AlmostEqual(expected), test.ShouldBeTrue)
+	test.That(t, sent.Label(), test.ShouldEqual, "cylinder")
+}
+
+func TestCylinderToPoints(t *testing.T) {
+	c := makeTestCylinder(&OrientationVectorDegrees{OX: 1, OY: 1, OZ: 1}, r3.Vector{10, 0, -5}, 5, 20).(*cylinder)
+	pts := c.ToPoints(1)
+	// about one point per square mm of surface
+	area := 2*math.Pi*5*20 + 2*math.Pi*25
+	test.That(t, float64(len(pts)), test.ShouldAlmostEqual, area, area/4)
+	for _, pt := range pts {
+		test.That(t, cylinderVsPointDistance(c, pt), test.ShouldAlmostEqual, 0, 1e-9)
+	}
+}
+
+func TestCylinderVsMeshDistance(t *testing.T) {
+	c := makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2)
+	m, err := NewMesh(NewZeroPose(), [][3]r3.Vector{
+		{{-5, -5, 3}, {5, -5, 3}, {0, 5, 3}},
+		{{2, -5, -5}, {2, 5, -5}, {2, 0, 5}},
+	}, "")
+	test.That(t, err, test.ShouldBeNil)
+	dist, err := c.DistanceFrom(m)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dist, test.ShouldAlmostEqual, 1, 1e-6)
+
+	m, err = NewMesh(NewZeroPose(), [][3]r3.Vector{{{-5, -5, 3}, {5, -5, 3}, {0, 5, 3}}}, "")
+	test.That(t, err, test.ShouldBeNil)
+	dist, err = m.DistanceFrom(c)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dist, test.ShouldAlmostEqual, 2, 1e-6)
+
+	// a triangle through the cylinder
+	m, err = NewMesh(NewZeroPose(), [][3]r3.Vector{{{-5, -5, 0.5}, {5, -5, 0.5}, {0, 5, 0.5}}}, "")
+	test.That(t, err, test.ShouldBeNil)
+	collides, err := c.CollidesWith(m)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeTrue)
+
+	inside, err := m.EncompassedBy(makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 10, 2))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inside, test.ShouldBeTrue)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 	commonpb "go.viam.com/api/common/v1"
 )
 
@@ -36,7 +37,9 @@ const (
 	BoxType         = GeometryType("box")
 	SphereType      = GeometryType("sphere")
 	CapsuleType     = GeometryType("capsule")
+	CylinderType    = GeometryType("cylinder")
 	PointType       = GeometryType("point")
+	MeshType        = GeometryType("mesh")
 	CollisionBuffer = 1e-8 // objects must be separated by this many mm to not be in collision
 
 	// Point density corresponding to how many points per square mm.
@@ -52,12 +55,15 @@ type GeometryConfig struct {
 	Y float64 `json:"y"`
 	Z float64 `json:"z"`
 
-	// parameter used for defining a sphere's radius'
+	// parameter used for defining a sphere's, capsule's or cylinder's radius
 	R float64 `json:"r"`
 
-	// parameter used for defining a capsule's length
+	// parameter used for defining a capsule's or cylinder's length
 	L float64 `json:"l"`
 
+	// parameter used for defining a mesh, the path of an STL or OBJ file in mm
+	MeshFile string `json:"mesh_file,omitempty"`
+
 	// define an offset to position the geometry
 	TranslationOffset r3.Vector         `json:"translation,omitempty"`
 	OrientationOffset OrientationConfig `json:"orientation,omitempty"`
@@ -84,9 +90,21 @@ func NewGeometryConfig(gc Geometry) (*GeometryConfig, error) {
 		config.R = gc.(*capsule).radius
 		config.L = gc.(*capsule).length
 		config.Label = gc.(*capsule).label
+	case *cylinder:
+		config.Type = CylinderType
+		config.R = gc.(*cylinder).radius
+		config.L = gc.(*cylinder).length
+		config.Label = gc.(*cylinder).label
 	case *point:
 		config.Type = PointType
 		config.Label = gc.(*point).label
+	case *mesh:
+		if gc.(*mesh).file == "" {
+			return nil, errors.New("only meshes loaded from a file can be converted to a config")
+		}
+		config.Type = MeshType
+		config.MeshFile = gc.(*mesh).file
+		config.Label = gc.(*mesh).label
 	default:
 		return nil, fmt.Errorf("%w %s", ErrGeometryTypeUnsupported, fmt.Sprintf("%T", gcType))
 	}
@@ -118,8 +136,12 @@ func (config *GeometryConfig) ParseConfig() (Geometry, error) {
 		return NewSphere(offset, config.R, config.Label)
 	case CapsuleType:
 		return NewCapsule(offset, config.R, config.L, config.Label)
+	case CylinderType:
+		return NewCylinder(offset, config.R, config.L, config.Label)
 	case PointType:
 		return NewPoint(offset.Point(), config.Label), nil
+	case MeshType:
+		return NewMeshFromFile(offset, config.MeshFile, config.Label)
 	case UnknownType:
 		// no type specified, iterate through supported types and try to infer intent
 		boxDims := r3.Vector{X: config.X, Y: config.Y, Z: config.Z}
@@ -39,6 +39,8 @@ func TestGeometrySerialization(t *testing.T) {
 		{"bad type", GeometryConfig{Type: "bad"}, false},
 		{"c", GeometryConfig{Type: "capsule", L: 4, R: 1, TranslationOffset: translation, OrientationOffset: orientation, Label: "c"}, true},
 		{"infer c", GeometryConfig{L: 4, R: 1, TranslationOffset: translation, OrientationOffset: orientation, Label: "infer c"}, true},
+		{"cylinder", GeometryConfig{Type: "cylinder", L: 1, R: 1, TranslationOffset: translation, OrientationOffset: orientation, Label: "cylinder"}, true},
+		{"cylinder bad dims", GeometryConfig{Type: "cylinder", L: 0, R: 1}, false},
 	}
 
 	pose := NewPoseFromPoint(r3.Vector{X: 1, Y: 1, Z: 1})
@@ -800,3 +802,317 @@ func TestCapsuleVsPointEncompassed(t *testing.T) {
 	}
 	testGeometryEncompassed(t, cases)
 }
+
+func TestCylinderVsBoxCollision(t *testing.T) {
+	sideways := &OrientationVectorDegrees{OX: 1}
+	cases := []geometryComparisonTestCase{
+		{
+			"end to face separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestBox(NewZeroOrientation(), r3.Vector{0, 0, 3}, r3.Vector{2, 2, 2}, ""),
+			},
+			1,
+		},
+		{
+			"side to edge separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestBox(NewZeroOrientation(), r3.Vector{3, 3, 0}, r3.Vector{2, 2, 2}, ""),
+			},
+			2*math.Sqrt2 - 1,
+		},
+		{
+			"side to rotated edge separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestBox(&EulerAngles{0, 0, math.Pi / 4}, r3.Vector{3, 0, 0}, r3.Vector{2, 2, 2}, ""),
+			},
+			2 - math.Sqrt2,
+		},
+		{
+			"sideways end to face separated",
+			[2]Geometry{
+				makeTestCylinder(sideways, r3.Vector{}, 1, 2),
+				makeTestBox(NewZeroOrientation(), r3.Vector{3, 0, 0}, r3.Vector{2, 2, 2}, ""),
+			},
+			1,
+		},
+		{
+			"sideways side to face separated",
+			[2]Geometry{
+				makeTestCylinder(sideways, r3.Vector{}, 1, 4),
+				makeTestBox(NewZeroOrientation(), r3.Vector{0, 0, 3}, r3.Vector{2, 2, 2}, ""),
+			},
+			1,
+		},
+		{
+			"rim to corner separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestBox(NewZeroOrientation(), r3.Vector{3, 0, 3}, r3.Vector{2, 2, 2}, ""),
+			},
+			math.Sqrt2,
+		},
+		{
+			"end to face contact",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestBox(NewZeroOrientation(), r3.Vector{0, 0, 2}, r3.Vector{2, 2, 2}, ""),
+			},
+			0,
+		},
+		{
+			"end to face collision",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestBox(NewZeroOrientation(), r3.Vector{0, 0, 1.5}, r3.Vector{4, 4, 2}, ""),
+			},
+			-0.5,
+		},
+		{
+			"side to face collision",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestBox(NewZeroOrientation(), r3.Vector{1.75, 0, 0}, r3.Vector{2, 4, 4}, ""),
+			},
+			-0.25,
+		},
+	}
+	testGeometryCollision(t, cases)
+}
+
+func TestCylinderVsCylinderCollision(t *testing.T) {
+	sideways := &OrientationVectorDegrees{OX: 1}
+	cases := []geometryComparisonTestCase{
+		{
+			"side to side separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{3, 0, 0}, 1, 2),
+			},
+			1,
+		},
+		{
+			"end to end separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{0, 0, 3}, 1, 2),
+			},
+			1,
+		},
+		{
+			"rim to rim separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{3, 0, 3}, 1, 2),
+			},
+			math.Sqrt2,
+		},
+		{
+			"end to side separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCylinder(sideways, r3.Vector{0, 0, 3}, 1, 10),
+			},
+			1,
+		},
+		{
+			"crossed separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 10),
+				makeTestCylinder(sideways, r3.Vector{0, 3, 0}, 1, 10),
+			},
+			1,
+		},
+		{
+			"side to side collision",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{1.5, 0, 0}, 1, 2),
+			},
+			-0.5,
+		},
+	}
+	testGeometryCollision(t, cases)
+}
+
+func TestCylinderVsSphereCapsulePointCollision(t *testing.T) {
+	sideways := &OrientationVectorDegrees{OX: 1}
+	cases := []geometryComparisonTestCase{
+		{
+			"sphere end separated",
+			[2]Geometry{makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2), makeTestSphere(r3.Vector{0, 0, 3}, 1, "")},
+			1,
+		},
+		{
+			"sphere rim separated",
+			[2]Geometry{makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2), makeTestSphere(r3.Vector{3, 0, 3}, 1, "")},
+			2*math.Sqrt2 - 1,
+		},
+		{
+			"sphere collision",
+			[2]Geometry{makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2), makeTestSphere(r3.Vector{0, 0, 0.5}, 1, "")},
+			-1.5,
+		},
+		{
+			"capsule side separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCapsule(NewZeroOrientation(), r3.Vector{3, 0, 0}, 0.5, 3),
+			},
+			1.5,
+		},
+		{
+			"capsule end separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCapsule(sideways, r3.Vector{0, 0, 3}, 0.5, 3),
+			},
+			1.5,
+		},
+		{
+			"capsule rim separated",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCapsule(sideways, r3.Vector{4, 0, 3}, 0.5, 3),
+			},
+			2*math.Sqrt2 - 0.5,
+		},
+		{
+			"capsule collision",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCapsule(NewZeroOrientation(), r3.Vector{1.2, 0, 0}, 0.5, 3),
+			},
+			-0.3,
+		},
+		{
+			"point separated",
+			[2]Geometry{makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2), NewPoint(r3.Vector{2, 0, 0}, "")},
+			1,
+		},
+		{
+			"point inside",
+			[2]Geometry{makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2), NewPoint(r3.Vector{0, 0, 0.5}, "")},
+			-0.5,
+		},
+	}
+	testGeometryCollision(t, cases)
+}
+
+func TestCylinderEncompassed(t *testing.T) {
+	cases := []geometryComparisonTestCase{
+		{
+			"cylinder in box",
+			[2]Geometry{
+				makeTestCylinder(&OrientationVectorDegrees{OZ: 1, Theta: 45}, r3.Vector{}, 1, 2),
+				makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{2, 2, 2}, ""),
+			},
+			0,
+		},
+		{
+			"cylinder not in box",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{2, 2, 1.9}, ""),
+			},
+			1,
+		},
+		{
+			"cylinder in sphere",
+			[2]Geometry{makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2), makeTestSphere(r3.Vector{}, 1.5, "")},
+			0,
+		},
+		{
+			"cylinder not in sphere",
+			[2]Geometry{makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2), makeTestSphere(r3.Vector{}, 1.4, "")},
+			1,
+		},
+		{
+			"cylinder in capsule",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCapsule(NewZeroOrientation(), r3.Vector{}, 1.1, 5),
+			},
+			0,
+		},
+		{
+			"cylinder not in capsule",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCapsule(NewZeroOrientation(), r3.Vector{}, 1, 3),
+			},
+			1,
+		},
+		{
+			"cylinder in cylinder",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1.1, 2.2),
+			},
+			0,
+		},
+		{
+			"cylinder not in cylinder",
+			[2]Geometry{
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 2, 1.5),
+			},
+			1,
+		},
+		{
+			"cylinder not in point",
+			[2]Geometry{makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2), NewPoint(r3.Vector{}, "")},
+			1,
+		},
+		{
+			"box in cylinder",
+			[2]Geometry{
+				makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{1, 1, 1}, ""),
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+			},
+			0,
+		},
+		{
+			"box not in cylinder",
+			[2]Geometry{
+				makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{2, 2, 1}, ""),
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+			},
+			1,
+		},
+		{
+			"sphere in cylinder",
+			[2]Geometry{makeTestSphere(r3.Vector{}, 1, ""), makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2)},
+			0,
+		},
+		{
+			"sphere not in cylinder",
+			[2]Geometry{makeTestSphere(r3.Vector{0, 0, 0.5}, 1, ""), makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2)},
+			1,
+		},
+		{
+			"capsule in cylinder",
+			[2]Geometry{
+				makeTestCapsule(NewZeroOrientation(), r3.Vector{}, 0.5, 2),
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+			},
+			0,
+		},
+		{
+			"capsule not in cylinder",
+			[2]Geometry{
+				makeTestCapsule(NewZeroOrientation(), r3.Vector{}, 0.5, 3),
+				makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2),
+			},
+			1,
+		},
+		{
+			"point in cylinder",
+			[2]Geometry{NewPoint(r3.Vector{}, ""), makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2)},
+			0,
+		},
+	}
+	testGeometryEncompassed(t, cases)
+}
@@ -1,6 +1,8 @@
 package spatialmath
 
 import (
+	"math"
+
 	"github.com/golang/geo/r3"
 )
 
@@ -49,31 +51,48 @@ func SegmentDistanceToSegment(ap1, ap2, bp1, bp2 r3.Vector) float64 {
 	return bestA.Sub(bestB).Norm()
 }
 
-// ClosestPointsSegmentSegment will return the points at which two line segments are closest to one another.
+// ClosestPointsSegmentSegment will return the points at which two line segments are closest to one another. Parallel segments have no
+// unique closest points, in which case a pair at the right distance is returned.
 func ClosestPointsSegmentSegment(ap1, ap2, bp1, bp2 r3.Vector) (r3.Vector, r3.Vector) {
-	// vectors between line endpoints:
-	v0 := bp1.Sub(ap1)
-	v1 := bp2.Sub(ap1)
-	v2 := bp1.Sub(ap2)
-	v3 := bp2.Sub(ap2)
-
-	// squared distances:
-	d0 := v0.Norm2()
-	d1 := v1.Norm2()
-	d2 := v2.Norm2()
-	d3 := v3.Norm2()
-
-	// select best potential endpoint on capsule A:
-	bestA := ap1
-	if d2 < d0 || d2 < d1 || d3 < d0 || d3 < d1 {
-		bestA = ap2
-	}
-	// select point on capsule B line segment nearest to best potential endpoint on A capsule:
-	bestB := ClosestPointSegmentPoint(bp1, bp2, bestA)
-
-	// now do the same for capsule A segment:
-	bestA = ClosestPointSegmentPoint(ap1, ap2, bestB)
-	return bestA, bestB
+	// Parametrize the segments as A(s) = ap1 + s * da and B(t) = bp1 + t * db with s and t in [0, 1], minimize the distance between
+	// them analytically, and clamp the result to the segments. See Ericson's Real-Time Collision Detection, section 5.1.9.
+	da := ap2.Sub(ap1)
+	db := bp2.Sub(bp1)
+	r := ap1.Sub(bp1)
+	a := da.Norm2()
+	e := db.Norm2()
+	f := db.Dot(r)
+
+	var s, t float64
+	switch {
+	case a <= floatEpsilon && e <= floatEpsilon:
+		// both segments are points
+		return ap1, bp1
+	case a <= floatEpsilon:
+		t = clampUnit(f / e)
+	case e <= floatEpsilon:
+		s = clampUnit(-da.Dot(r) / a)
+	default:
+		b := da.Dot(db)
+		c := da.Dot(r)
+		if denom := a*e - b*b; denom > floatEpsilon {
+			s = clampUnit((b*f - c*e) / denom)
+		}
+		t = (b*s + f) / e
+		if t < 0 {
+			t = 0
+			s = clampUnit(-c / a)
+		} else if t > 1 {
+			t = 1
+			s = clampUnit((b - c) / a)
+		}
+	}
+	return ap1.Add(da.Mul(s)), bp1.Add(db.Mul(t))
+}
+
+// clampUnit clamps a value to the interval [0, 1].
+func clampUnit(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
 }
 
 // PlaneNormal returns the plane normal of the triangle defined by the three given points.
@@ -145,3 +164,112 @@ func closestPointsSegmentPlane(ap1, ap2, planePt, planeNormal r3.Vector) (segPt,
 	}
 	return coplanarPt, coplanarPt
 }
+
+// gjkIterations bounds the number of iterations of gjkDistance. Polytopes converge in a handful, and curved geometries come within a
+// negligible distance of the answer well before this.
+const gjkIterations = 100
+
+// convexDistance returns the distance between two convex geometries given by their support functions, which return the point of a
+// geometry that is furthest in a direction. Separation distances are found with the GJK algorithm. If the geometries intersect, the
+// penetration depth is estimated as the smallest overlap of their projections onto the given axes, which is at least as deep as the
+// true penetration depth and equal to it when the axis it is measured along is among those given.
+func convexDistance(a, b func(r3.Vector) r3.Vector, axes []r3.Vector) float64 {
+	if dist, separated := gjkDistance(a, b); separated {
+		return dist
+	}
+	return separatingAxesDistance(a, b, axes)
+}
+
+// gjkDistance returns the distance between two convex geometries given by their support functions, and false if they intersect.
+// It searches for the point of their Minkowski difference closest to the origin, using simplices of points on its surface.
+// Reference: Ericson's Real-Time Collision Detection, section 9.5.
+func gjkDistance(a, b func(r3.Vector) r3.Vector) (float64, bool) {
+	support := func(dir r3.Vector) r3.Vector { return a(dir).Sub(b(dir.Mul(-1))) }
+	closest := support(r3.Vector{X: 1})
+	simplex := []r3.Vector{closest}
+	for i := 0; i < gjkIterations; i++ {
+		dist2 := closest.Norm2()
+		if dist2 <= floatEpsilon*floatEpsilon {
+			return 0, false
+		}
+		next := support(closest.Mul(-1))
+		// nothing in the difference is meaningfully closer to the origin than the closest point found so far
+		if dist2-closest.Dot(next) <= 1e-12*dist2 {
+			return math.Sqrt(dist2), true
+		}
+		closest, simplex = closestPointSimplex(append(simplex, next))
+		if simplex == nil {
+			return 0, false
+		}
+	}
+	return closest.Norm(), true
+}
+
+// closestPointSimplex returns the point of a simplex of up to four points that is closest to the origin, and the smallest set of those
+// points whose simplex contains it. If the simplex is a tetrahedron that contains the origin, the set returned is nil.
+func closestPointSimplex(simplex []r3.Vector) (r3.Vector, []r3.Vector) {
+	if len(simplex) == 4 && tetrahedronContainsOrigin(simplex) {
+		return r3.Vector{}, nil
+	}
+	var best r3.Vector
+	var bestSet []r3.Vector
+	bestDist := math.Inf(1)
+	for mask := 1; mask < 1<<len(simplex); mask++ {
+		set := make([]r3.Vector, 0, len(simplex))
+		for i, pt := range simplex {
+			if mask&(1<<i) != 0 {
+				set = append(set, pt)
+			}
+		}
+		var pt r3.Vector
+		switch len(set) {
+		case 1:
+			pt = set[0]
+		case 2:
+			pt = ClosestPointSegmentPoint(set[0], set[1], r3.Vector{})
+		case 3:
+			pt = (&triangle{p0: set[0], p1: set[1], p2: set[2]}).closestPointToPoint(r3.Vector{})
+		default:
+			continue
+		}
+		dist := pt.Norm2()
+		if math.IsNaN(dist) {
+			continue
+		}
+		// prefer smaller sets that are as close, so that points which do not contribute are dropped from the simplex
+		margin := 1e-12 * dist
+		if bestSet == nil || dist < bestDist-margin || (dist <= bestDist+margin && len(set) < len(bestSet)) {
+			best, bestSet, bestDist = pt, set, dist
+		}
+	}
+	return best, bestSet
+}
+
+// tetrahedronContainsOrigin returns whether the origin is on the same side of each face of the tetrahedron as its opposite vertex.
+func tetrahedronContainsOrigin(t []r3.Vector) bool {
+	for i := 0; i < 4; i++ {
+		a, b, c, d := t[i], t[(i+1)%4], t[(i+2)%4], t[(i+3)%4]
+		normal := b.Sub(a).Cross(c.Sub(a))
+		side := normal.Dot(d.Sub(a))
+		if side == 0 || normal.Dot(a.Mul(-1))*side < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// separatingAxesDistance returns the largest separation between the projections of two convex geometries, given by their support
+// functions, onto any of the given axes. It is negative if their projections overlap on every axis.
+func separatingAxesDistance(a, b func(r3.Vector) r3.Vector, axes []r3.Vector) float64 {
+	best := math.Inf(-1)
+	for _, axis := range axes {
+		if axis.Norm() <= floatEpsilon {
+			continue
+		}
+		n := axis.Normalize()
+		maxA, minA := a(n).Dot(n), a(n.Mul(-1)).Dot(n)
+		maxB, minB := b(n).Dot(n), b(n.Mul(-1)).Dot(n)
+		best = math.Max(best, math.Max(minB-maxA, minA-maxB))
+	}
+	return best
+}
@@ -1,17 +1,364 @@
 package spatialmath
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
 	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
 )
 
 // This file incorporates work covered by the Brax project -- https://github.com/google/brax/blob/main/LICENSE.
 // Copyright 2021 The Brax Authors, which is licensed under the Apache License Version 2.0 (the “License”).
 // You may obtain a copy of the license at http://www.apache.org/licenses/LICENSE-2.0.
 
+// meshLeafSize is the most triangles held by a leaf of a mesh's bounding volume hierarchy.
+const meshLeafSize = 4
+
 // mesh is a collision geometry that represents a set of triangles that represent a mesh.
+//
+// IMPORTANT: meshes are not considered solid. A mesh is not guaranteed to represent an enclosed area, so only its surface is checked
+// for collisions, and other geometries entirely inside of it are not in collision with it.
 type mesh struct {
-	pose      Pose
+	pose Pose
+	// triangles are in the world frame, placed at pose
 	triangles []*triangle
+	label     string
+	// local holds the triangles relative to pose, so that the mesh can be transformed. It is nil for the meshes boxes build internally.
+	local []*triangle
+	// file is the file the mesh was loaded from, if any
+	file string
+
+	bvh  *meshNode
+	once sync.Once
+}
+
+// NewMesh instantiates a new mesh Geometry from triangles given relative to pose, each as its three vertices. Triangles with no area,
+// which are common in meshes exported from CAD software, are skipped.
+func NewMesh(pose Pose, triangles [][3]r3.Vector, label string) (Geometry, error) {
+	local := make([]*triangle, 0, len(triangles))
+	for _, tri := range triangles {
+		if tri[1].Sub(tri[0]).Cross(tri[2].Sub(tri[0])).Norm2() == 0 {
+			continue
+		}
+		local = append(local, newTriangle(tri[0], tri[1], tri[2]))
+	}
+	if len(local) == 0 {
+		return nil, newBadGeometryDimensionsError(&mesh{})
+	}
+	return newMesh(pose, local, label), nil
+}
+
+func newMesh(pose Pose, local []*triangle, label string) *mesh {
+	triangles := make([]*triangle, 0, len(local))
+	for _, tri := range local {
+		verts := transformPointsToPose([]r3.Vector{tri.p0, tri.p1, tri.p2}, pose)
+		triangles = append(triangles, newTriangle(verts[0], verts[1], verts[2]))
+	}
+	return &mesh{pose: pose, triangles: triangles, label: label, local: local}
+}
+
+// String returns a human readable string that represents the mesh.
+func (m *mesh) String() string {
+	return fmt.Sprintf("Type: Mesh, Triangles: %d", len(m.triangles))
+}
+
+func (m *mesh) MarshalJSON() ([]byte, error) {
+	config, err := NewGeometryConfig(m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// Label returns the label of this mesh.
+func (m *mesh) Label() string {
+	return m.label
+}
+
+// SetLabel sets the label of this mesh.
+func (m *mesh) SetLabel(label string) {
+	m.label = label
+}
+
+// Pose returns the pose of the mesh.
+func (m *mesh) Pose() Pose {
+	return m.pose
+}
+
+// AlmostEqual compares the mesh with another geometry and checks if they are equivalent.
+func (m *mesh) AlmostEqual(g Geometry) bool {
+	other, ok := g.(*mesh)
+	if !ok || len(m.triangles) != len(other.triangles) {
+		return false
+	}
+	for i, tri := range m.triangles {
+		otherTri := other.triangles[i]
+		if !tri.p0.ApproxEqual(otherTri.p0) || !tri.p1.ApproxEqual(otherTri.p1) || !tri.p2.ApproxEqual(otherTri.p2) {
+			return false
+		}
+	}
+	return PoseAlmostEqual(m.pose, other.pose)
+}
+
+// Transform premultiplies the mesh pose with a transform, allowing the mesh to be moved in space.
+func (m *mesh) Transform(toPremultiply Pose) Geometry {
+	transformed := newMesh(Compose(toPremultiply, m.pose), m.local, m.label)
+	transformed.file = m.file
+	return transformed
+}
+
+// ToProtobuf converts the mesh to a Geometry proto message. The API has no mesh geometry, so the mesh is sent as its bounding box,
+// which is conservative for collision checking.
+func (m *mesh) ToProtobuf() *commonpb.Geometry {
+	lo, hi := triangleBounds(m.local)
+	center := Compose(m.pose, NewPoseFromPoint(lo.Add(hi).Mul(0.5)))
+	dims := hi.Sub(lo)
+	return &commonpb.Geometry{
+		Center: PoseToProtobuf(center),
+		GeometryType: &commonpb.Geometry_Box{
+			Box: &commonpb.RectangularPrism{DimsMm: &commonpb.Vector3{
+				X: dims.X,
+				Y: dims.Y,
+				Z: dims.Z,
+			}},
+		},
+		Label: m.label,
+	}
+}
+
+// CollidesWith checks if the given mesh collides with the given geometry and returns true if it does.
+func (m *mesh) CollidesWith(g Geometry) (bool, error) {
+	dist, err := m.DistanceFrom(g)
+	if err != nil {
+		return true, err
+	}
+	return dist <= CollisionBuffer, nil
+}
+
+// DistanceFrom returns the distance from the surface of the mesh to the given geometry, or the penetration depth of a geometry that
+// crosses the surface.
+func (m *mesh) DistanceFrom(g Geometry) (float64, error) {
+	if other, ok := g.(*box); ok {
+		return meshVsBoxDistance(m, other), nil
+	}
+	if other, ok := g.(*sphere); ok {
+		return meshVsPointDistance(m, other.pose.Point()) - other.radius, nil
+	}
+	if other, ok := g.(*capsule); ok {
+		return capsuleVsMeshDistance(other, m), nil
+	}
+	if other, ok := g.(*point); ok {
+		return meshVsPointDistance(m, other.position), nil
+	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsMeshDistance(m, other), nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return cylinderVsMeshDistance(other, m), nil
+	}
+	return math.Inf(-1), newCollisionTypeUnsupportedError(m, g)
+}
+
+// EncompassedBy returns whether every vertex of the mesh is inside the given geometry, which for the convex geometries means the whole
+// mesh is. Nothing is encompassed by a mesh or a point, since neither is solid.
+func (m *mesh) EncompassedBy(g Geometry) (bool, error) {
+	switch g.(type) {
+	case *box, *sphere, *capsule, *cylinder:
+	case *point, *mesh:
+		return false, nil
+	default:
+		return false, newCollisionTypeUnsupportedError(m, g)
+	}
+	for _, tri := range m.triangles {
+		for _, pt := range []r3.Vector{tri.p0, tri.p1, tri.p2} {
+			inside, err := NewPoint(pt, "").EncompassedBy(g)
+			if err != nil || !inside {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// ToPoints converts a mesh geometry into a []r3.Vector of its vertices, ignoring the resolution.
+func (m *mesh) ToPoints(resolution float64) []r3.Vector {
+	pts := make([]r3.Vector, 0, 3*len(m.triangles))
+	for _, tri := range m.triangles {
+		pts = append(pts, tri.p0, tri.p1, tri.p2)
+	}
+	return pts
+}
+
+// closestDistance returns the smallest distance to any triangle of the mesh, as measured by triDist. lowerBound gives a lower bound on
+// the distance to anything within an axis aligned bounding box, which lets whole branches of the mesh's hierarchy be skipped. The search
+// stops as soon as a collision is found, so a distance below CollisionBuffer may not be the deepest.
+func (m *mesh) closestDistance(lowerBound func(lo, hi r3.Vector) float64, triDist func(*triangle) float64) float64 {
+	m.once.Do(func() { m.bvh = newMeshNode(m.triangles) })
+	best := math.Inf(1)
+	m.bvh.closestDistance(lowerBound, triDist, &best)
+	return best
+}
+
+// meshNode is a node of an axis aligned bounding box hierarchy over the triangles of a mesh.
+type meshNode struct {
+	lo, hi      r3.Vector
+	left, right *meshNode
+	// triangles is only set for leaves
+	triangles []*triangle
+}
+
+func newMeshNode(triangles []*triangle) *meshNode {
+	lo, hi := triangleBounds(triangles)
+	node := &meshNode{lo: lo, hi: hi}
+	if len(triangles) <= meshLeafSize {
+		node.triangles = triangles
+		return node
+	}
+
+	// split at the median centroid along the longest axis of the box
+	size := hi.Sub(lo)
+	axis := func(v r3.Vector) float64 { return v.X }
+	if size.Y > size.X && size.Y >= size.Z {
+		axis = func(v r3.Vector) float64 { return v.Y }
+	} else if size.Z > size.X && size.Z > size.Y {
+		axis = func(v r3.Vector) float64 { return v.Z }
+	}
+	sorted := make([]*triangle, len(triangles))
+	copy(sorted, triangles)
+	sort.Slice(sorted, func(i, j int) bool {
+		return axis(sorted[i].p0.Add(sorted[i].p1).Add(sorted[i].p2)) < axis(sorted[j].p0.Add(sorted[j].p1).Add(sorted[j].p2))
+	})
+	node.left = newMeshNode(sorted[:len(sorted)/2])
+	node.right = newMeshNode(sorted[len(sorted)/2:])
+	return node
+}
+
+func (n *meshNode) closestDistance(lowerBound func(lo, hi r3.Vector) float64, triDist func(*triangle) float64, best *float64) {
+	if *best <= CollisionBuffer || lowerBound(n.lo, n.hi) >= *best {
+		return
+	}
+	if n.triangles != nil {
+		for _, tri := range n.triangles {
+			if dist := triDist(tri); dist < *best {
+				*best = dist
+			}
+		}
+		return
+	}
+	// visit the nearer child first so that more of the farther one can be skipped
+	first, second := n.left, n.right
+	if lowerBound(second.lo, second.hi) < lowerBound(first.lo, first.hi) {
+		first, second = second, first
+	}
+	first.closestDistance(lowerBound, triDist, best)
+	second.closestDistance(lowerBound, triDist, best)
+}
+
+// triangleBounds returns the corners of the axis aligned box bounding the triangles.
+func triangleBounds(triangles []*triangle) (lo, hi r3.Vector) {
+	lo = r3.Vector{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	hi = r3.Vector{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, tri := range triangles {
+		for _, pt := range []r3.Vector{tri.p0, tri.p1, tri.p2} {
+			lo = r3.Vector{X: math.Min(lo.X, pt.X), Y: math.Min(lo.Y, pt.Y), Z: math.Min(lo.Z, pt.Z)}
+			hi = r3.Vector{X: math.Max(hi.X, pt.X), Y: math.Max(hi.Y, pt.Y), Z: math.Max(hi.Z, pt.Z)}
+		}
+	}
+	return lo, hi
+}
+
+// aabbPointDistance returns the distance from a point to an axis aligned box, which is zero if the point is inside it.
+func aabbPointDistance(lo, hi, pt r3.Vector) float64 {
+	closest := r3.Vector{
+		X: math.Min(math.Max(pt.X, lo.X), hi.X),
+		Y: math.Min(math.Max(pt.Y, lo.Y), hi.Y),
+		Z: math.Min(math.Max(pt.Z, lo.Z), hi.Z),
+	}
+	return pt.Sub(closest).Norm()
+}
+
+// meshVsPointDistance returns the distance from a point to the closest triangle of a mesh.
+func meshVsPointDistance(m *mesh, pt r3.Vector) float64 {
+	return m.closestDistance(
+		func(lo, hi r3.Vector) float64 { return aabbPointDistance(lo, hi, pt) },
+		func(tri *triangle) float64 { return tri.closestPointToPoint(pt).Sub(pt).Norm() },
+	)
+}
+
+// meshVsBoxDistance returns the distance between a mesh and a box. Since the box is solid, a mesh with vertices inside of it is in
+// collision by as much as the deepest of them.
+func meshVsBoxDistance(m *mesh, b *box) float64 {
+	depth := 0.
+	for _, tri := range m.triangles {
+		for _, pt := range []r3.Vector{tri.p0, tri.p1, tri.p2} {
+			if dist := pointVsBoxDistance(pt, b); dist < depth {
+				depth = dist
+			}
+		}
+	}
+	if depth < 0 {
+		return depth
+	}
+	boxTriangles := b.toMesh().triangles
+	center := b.pose.Point()
+	return m.closestDistance(
+		func(lo, hi r3.Vector) float64 { return aabbPointDistance(lo, hi, center) - b.boundingSphereR },
+		func(tri *triangle) float64 {
+			best := math.Inf(1)
+			for _, boxTri := range boxTriangles {
+				best = math.Min(best, triangleVsTriangleDistance(tri, boxTri))
+			}
+			return best
+		},
+	)
+}
+
+// meshVsMeshDistance returns the distance between the closest triangles of two meshes, which is zero if they intersect.
+func meshVsMeshDistance(a, b *mesh) float64 {
+	return a.closestDistance(
+		func(lo, hi r3.Vector) float64 {
+			b.once.Do(func() { b.bvh = newMeshNode(b.triangles) })
+			return aabbVsAabbDistance(lo, hi, b.bvh.lo, b.bvh.hi)
+		},
+		func(tri *triangle) float64 {
+			triLo, triHi := triangleBounds([]*triangle{tri})
+			return b.closestDistance(
+				func(lo, hi r3.Vector) float64 { return aabbVsAabbDistance(triLo, triHi, lo, hi) },
+				func(other *triangle) float64 { return triangleVsTriangleDistance(tri, other) },
+			)
+		},
+	)
+}
+
+// aabbVsAabbDistance returns the distance between two axis aligned boxes, which is zero if they overlap.
+func aabbVsAabbDistance(lo1, hi1, lo2, hi2 r3.Vector) float64 {
+	gap := r3.Vector{
+		X: math.Max(0, math.Max(lo1.X-hi2.X, lo2.X-hi1.X)),
+		Y: math.Max(0, math.Max(lo1.Y-hi2.Y, lo2.Y-hi1.Y)),
+		Z: math.Max(0, math.Max(lo1.Z-hi2.Z, lo2.Z-hi1.Z)),
+	}
+	return gap.Norm()
+}
+
+// triangleVsTriangleDistance returns the distance between two triangles. Two triangles that do not intersect are closest at an edge of
+// one of them, and two that intersect have an edge of one crossing the other, so checking every edge against the other triangle covers
+// both cases.
+func triangleVsTriangleDistance(a, b *triangle) float64 {
+	best := math.Inf(1)
+	for _, pair := range [2][2]*triangle{{a, b}, {b, a}} {
+		t, other := pair[0], pair[1]
+		for _, edge := range [3][2]r3.Vector{{t.p0, t.p1}, {t.p1, t.p2}, {t.p2, t.p0}} {
+			segPt, triPt := closestPointsSegmentTriangle(edge[0], edge[1], other)
+			if dist := segPt.Sub(triPt).Norm(); dist < best {
+				best = dist
+			}
+		}
+	}
+	return best
 }
 
 type triangle struct {
@@ -63,6 +410,18 @@ func (t *triangle) closestPointToCoplanarPoint(pt r3.Vector) r3.Vector {
 	return refPt
 }
 
+// support returns the vertex of the triangle that is furthest in the given direction.
+func (t *triangle) support(dir r3.Vector) r3.Vector {
+	best := t.p0
+	if t.p1.Dot(dir) > best.Dot(dir) {
+		best = t.p1
+	}
+	if t.p2.Dot(dir) > best.Dot(dir) {
+		best = t.p2
+	}
+	return best
+}
+
 // closestPointToPoint takes a point, and returns the closest point on the triangle to the given point, as well as whether the point
 // is on the edge of the triangle.
 // This is slower than closestPointToCoplanarPoint.
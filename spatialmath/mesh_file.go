@@ -0,0 +1,171 @@
+package spatialmath
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+const (
+	stlHeaderSize   = 80
+	stlTriangleSize = 50
+)
+
+// NewMeshFromFile instantiates a new mesh Geometry from an STL (ASCII or binary) or OBJ file, whose coordinates are taken to be in mm
+// relative to pose.
+func NewMeshFromFile(pose Pose, path, label string) (Geometry, error) {
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var triangles [][3]r3.Vector
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".stl":
+		triangles, err = parseSTL(data)
+	case ".obj":
+		triangles, err = parseOBJ(data)
+	default:
+		return nil, errors.Errorf("unsupported mesh file type %q, must be .stl or .obj", ext)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read mesh file %q", path)
+	}
+	g, err := NewMesh(pose, triangles, label)
+	if err != nil {
+		return nil, err
+	}
+	g.(*mesh).file = path
+	return g, nil
+}
+
+// parseSTL reads the triangles of a binary or ASCII STL file. Binary files may also begin with "solid", so a file is only read as
+// ASCII if its size does not match the triangle count in its binary header.
+func parseSTL(data []byte) ([][3]r3.Vector, error) {
+	if len(data) >= stlHeaderSize+4 {
+		count := binary.LittleEndian.Uint32(data[stlHeaderSize:])
+		if uint64(len(data)) == stlHeaderSize+4+uint64(count)*stlTriangleSize {
+			return parseBinarySTL(data[stlHeaderSize+4:], int(count)), nil
+		}
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		return nil, errors.New("not a valid STL file")
+	}
+	return parseASCIISTL(data)
+}
+
+func parseBinarySTL(data []byte, count int) [][3]r3.Vector {
+	readFloat := func(b []byte) float64 {
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	}
+	triangles := make([][3]r3.Vector, 0, count)
+	for i := 0; i < count; i++ {
+		// each triangle is a normal, three vertices and an attribute byte count; the normal is recomputed from the vertices
+		record := data[i*stlTriangleSize:]
+		var tri [3]r3.Vector
+		for v := range tri {
+			offset := 12 * (v + 1)
+			tri[v] = r3.Vector{X: readFloat(record[offset:]), Y: readFloat(record[offset+4:]), Z: readFloat(record[offset+8:])}
+		}
+		triangles = append(triangles, tri)
+	}
+	return triangles
+}
+
+func parseASCIISTL(data []byte) ([][3]r3.Vector, error) {
+	var triangles [][3]r3.Vector
+	var vertices []r3.Vector
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "vertex":
+			vertex, err := parseVector(fields[1:])
+			if err != nil {
+				return nil, err
+			}
+			vertices = append(vertices, vertex)
+		case "endfacet":
+			if len(vertices) != 3 {
+				return nil, errors.Errorf("facet has %d vertices, need exactly 3", len(vertices))
+			}
+			triangles = append(triangles, [3]r3.Vector{vertices[0], vertices[1], vertices[2]})
+			vertices = vertices[:0]
+		}
+	}
+	return triangles, scanner.Err()
+}
+
+// parseOBJ reads the faces of an OBJ file, splitting faces with more than three vertices into triangles. Everything other than
+// vertices and faces, such as normals and texture coordinates, is ignored.
+func parseOBJ(data []byte) ([][3]r3.Vector, error) {
+	var triangles [][3]r3.Vector
+	var vertices []r3.Vector
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "v":
+			if len(fields) < 4 {
+				return nil, errors.New("vertex needs 3 coordinates")
+			}
+			vertex, err := parseVector(fields[1:4])
+			if err != nil {
+				return nil, err
+			}
+			vertices = append(vertices, vertex)
+		case "f":
+			if len(fields) < 4 {
+				return nil, errors.New("face needs at least 3 vertices")
+			}
+			face := make([]r3.Vector, 0, len(fields)-1)
+			for _, field := range fields[1:] {
+				// a face vertex may be given as v, v/vt, v//vn or v/vt/vn, and negative indices count back from the latest vertex
+				index, err := strconv.Atoi(strings.SplitN(field, "/", 2)[0])
+				if err != nil {
+					return nil, err
+				}
+				if index < 0 {
+					index += len(vertices) + 1
+				}
+				if index < 1 || index > len(vertices) {
+					return nil, errors.Errorf("face refers to vertex %d, but there are %d", index, len(vertices))
+				}
+				face = append(face, vertices[index-1])
+			}
+			for i := 1; i < len(face)-1; i++ {
+				triangles = append(triangles, [3]r3.Vector{face[0], face[i], face[i+1]})
+			}
+		}
+	}
+	return triangles, scanner.Err()
+}
+
+func parseVector(fields []string) (r3.Vector, error) {
+	if len(fields) != 3 {
+		return r3.Vector{}, errors.Errorf("got %d coordinates, need exactly 3", len(fields))
+	}
+	var coords [3]float64
+	for i, field := range fields {
+		coord, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return r3.Vector{}, err
+		}
+		coords[i] = coord
+	}
+	return r3.Vector{X: coords[0], Y: coords[1], Z: coords[2]}, nil
+}
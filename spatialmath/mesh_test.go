@@ -1,6 +1,10 @@
 package spatialmath
 
 import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -21,3 +25,177 @@ func TestClosestPoint(t *testing.T) {
 	test.That(t, cp3.ApproxEqual(qp1), test.ShouldBeTrue)
 	test.That(t, cp1.ApproxEqual(cp2), test.ShouldBeTrue)
 }
+
+func makeTestCubeMesh(t *testing.T, pose Pose) Geometry {
+	t.Helper()
+	triangles := make([][3]r3.Vector, 0, len(boxTriangles))
+	for _, tri := range boxTriangles {
+		triangles = append(triangles, [3]r3.Vector{
+			boxVertices[tri[0]].Mul(5),
+			boxVertices[tri[1]].Mul(5),
+			boxVertices[tri[2]].Mul(5),
+		})
+	}
+	m, err := NewMesh(pose, triangles, "cube")
+	test.That(t, err, test.ShouldBeNil)
+	return m
+}
+
+func TestMeshDistances(t *testing.T) {
+	cube := makeTestCubeMesh(t, NewZeroPose())
+	distanceTo := func(g Geometry) float64 {
+		dist, err := cube.DistanceFrom(g)
+		test.That(t, err, test.ShouldBeNil)
+		reverse, err := g.DistanceFrom(cube)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, reverse, test.ShouldAlmostEqual, dist)
+		return dist
+	}
+	box := func(pt r3.Vector, size float64) Geometry {
+		b, err := NewBox(NewPoseFromPoint(pt), r3.Vector{size, size, size}, "")
+		test.That(t, err, test.ShouldBeNil)
+		return b
+	}
+
+	t.Run("point", func(t *testing.T) {
+		test.That(t, distanceTo(NewPoint(r3.Vector{10, 0, 0}, "")), test.ShouldAlmostEqual, 5)
+		// meshes are not solid, so a point inside is as far from the mesh as it is from the closest face
+		test.That(t, distanceTo(NewPoint(r3.Vector{1, 0, 0}, "")), test.ShouldAlmostEqual, 4)
+	})
+	t.Run("sphere", func(t *testing.T) {
+		s, err := NewSphere(NewPoseFromPoint(r3.Vector{10, 0, 0}), 6, "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, distanceTo(s), test.ShouldAlmostEqual, -1)
+		collides, err := s.CollidesWith(cube)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeTrue)
+	})
+	t.Run("capsule", func(t *testing.T) {
+		c, err := NewCapsule(NewPoseFromPoint(r3.Vector{20, 0, 0}), 2, 10, "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, distanceTo(c), test.ShouldAlmostEqual, 13)
+		// a capsule pointing at the mesh is closest at its end
+		c, err = NewCapsule(NewPose(r3.Vector{20, 1, 0}, &OrientationVector{OX: 1}), 2, 10, "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, distanceTo(c), test.ShouldAlmostEqual, 10)
+	})
+	t.Run("box", func(t *testing.T) {
+		test.That(t, distanceTo(box(r3.Vector{20, 0, 0}, 10)), test.ShouldAlmostEqual, 10)
+		test.That(t, distanceTo(box(r3.Vector{}, 2)), test.ShouldAlmostEqual, 4)
+		// a box is solid, so the mesh is in collision with one that surrounds it
+		test.That(t, distanceTo(box(r3.Vector{}, 20)), test.ShouldAlmostEqual, -5)
+		collides, err := cube.CollidesWith(box(r3.Vector{}, 20))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeTrue)
+	})
+	t.Run("mesh", func(t *testing.T) {
+		test.That(t, distanceTo(cube.Transform(NewPoseFromPoint(r3.Vector{12, 0, 0}))), test.ShouldAlmostEqual, 2)
+		collides, err := cube.CollidesWith(cube.Transform(NewPoseFromPoint(r3.Vector{8, 0, 0})))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeTrue)
+	})
+	t.Run("encompassed by", func(t *testing.T) {
+		inside, err := cube.EncompassedBy(box(r3.Vector{}, 20))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, inside, test.ShouldBeTrue)
+		inside, err = cube.EncompassedBy(box(r3.Vector{1, 0, 0}, 10))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, inside, test.ShouldBeFalse)
+	})
+}
+
+func TestMeshGeometry(t *testing.T) {
+	_, err := NewMesh(NewZeroPose(), [][3]r3.Vector{{{}, {1, 0, 0}, {2, 0, 0}}}, "")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cube := makeTestCubeMesh(t, NewZeroPose())
+	moved := cube.Transform(NewPoseFromPoint(r3.Vector{0, 0, 100}))
+	test.That(t, moved.Pose().Point(), test.ShouldResemble, r3.Vector{0, 0, 100})
+	test.That(t, moved.AlmostEqual(makeTestCubeMesh(t, NewPoseFromPoint(r3.Vector{0, 0, 100}))), test.ShouldBeTrue)
+	test.That(t, moved.AlmostEqual(cube), test.ShouldBeFalse)
+	for _, pt := range moved.ToPoints(0) {
+		test.That(t, pt.Z, test.ShouldBeBetweenOrEqual, 95, 105)
+	}
+
+	// meshes are sent over the API as their bounding box
+	bounds, err := NewGeometryFromProto(moved.ToProtobuf())
+	test.That(t, err, test.ShouldBeNil)
+	expected, err := NewBox(NewPoseFromPoint(r3.Vector{0, 0, 100}), r3.Vector{10, 10, 10}, "cube")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, bounds.AlmostEqual(expected), test.ShouldBeTrue)
+}
+
+func TestMeshFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		test.That(t, os.WriteFile(path, data, 0o600), test.ShouldBeNil)
+		return path
+	}
+	expected := [][3]r3.Vector{{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}}, {{0, 0, 0}, {0, 10, 0}, {0, 0, 10}}}
+
+	asciiSTL := `solid test
+facet normal 0 0 1
+  outer loop
+    vertex 0 0 0
+    vertex 10 0 0
+    vertex 0 10 0
+  endloop
+endfacet
+facet normal 1 0 0
+  outer loop
+    vertex 0 0 0
+    vertex 0 10 0
+    vertex 0 0 10
+  endloop
+endfacet
+endsolid test
+`
+	binarySTL := make([]byte, stlHeaderSize+4+len(expected)*stlTriangleSize)
+	copy(binarySTL, "solid but actually binary")
+	binary.LittleEndian.PutUint32(binarySTL[stlHeaderSize:], uint32(len(expected)))
+	for i, tri := range expected {
+		record := binarySTL[stlHeaderSize+4+i*stlTriangleSize:]
+		for v, vertex := range tri {
+			for c, coord := range []float64{vertex.X, vertex.Y, vertex.Z} {
+				binary.LittleEndian.PutUint32(record[12*(v+1)+4*c:], math.Float32bits(float32(coord)))
+			}
+		}
+	}
+	obj := `# a quad split into two triangles, given with negative indices
+v 0 0 0
+v 10 0 0
+v 0 10 0
+v 0 0 10
+vn 0 0 1
+f 1//1 2//1 3//1
+f -4/1 -2/1 -1/1
+`
+
+	for name, data := range map[string][]byte{"ascii.stl": []byte(asciiSTL), "binary.stl": binarySTL, "mesh.OBJ": []byte(obj)} {
+		t.Run(name, func(t *testing.T) {
+			path := write(name, data)
+			g, err := NewMeshFromFile(NewZeroPose(), path, "part")
+			test.That(t, err, test.ShouldBeNil)
+			m, err := NewMesh(NewZeroPose(), expected, "part")
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, g.AlmostEqual(m), test.ShouldBeTrue)
+
+			// meshes loaded from a file can be configured by the file's path
+			config, err := NewGeometryConfig(g)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, config.Type, test.ShouldEqual, MeshType)
+			test.That(t, config.MeshFile, test.ShouldEqual, path)
+			parsed, err := config.ParseConfig()
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, parsed.AlmostEqual(g), test.ShouldBeTrue)
+		})
+	}
+
+	_, err := NewMeshFromFile(NewZeroPose(), write("mesh.ply", []byte(obj)), "")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewMeshFromFile(NewZeroPose(), write("bad.stl", []byte("not an stl")), "")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewMeshFromFile(NewZeroPose(), write("bad.obj", []byte("v 0 0 0\nf 1 2 3\n")), "")
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -90,6 +90,12 @@ func (pt *point) CollidesWith(g Geometry) (bool, error) {
 	if other, ok := g.(*point); ok {
 		return pt.AlmostEqual(other), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsPointDistance(other, pt.position) <= CollisionBuffer, nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return cylinderVsPointDistance(other, pt.position) <= 0, nil
+	}
 	return true, newCollisionTypeUnsupportedError(pt, g)
 }
 
@@ -107,6 +113,12 @@ func (pt *point) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return pt.position.Sub(other.position).Norm(), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsPointDistance(other, pt.position), nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return cylinderVsPointDistance(other, pt.position), nil
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(pt, g)
 }
 
@@ -0,0 +1,224 @@
+package spatialmath
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/quat"
+)
+
+// poseErrorDims is the number of dimensions of the error of a pose, three of position and three of orientation.
+const poseErrorDims = 6
+
+// PoseWithCovariance is an estimate of a pose along with the covariance of its error, such as from SLAM, a GPS or sensor fusion, so
+// that estimates can be weighted by how certain they are. The covariance is a 6x6 matrix over the error of the position along X, Y and
+// Z, in mm, followed by the error of the orientation as a rotation about the X, Y and Z axes, in radians, all expressed in the frame the
+// pose is in. The true orientation is the estimated orientation followed by the error rotation.
+type PoseWithCovariance struct {
+	pose       Pose
+	covariance *mat.SymDense
+}
+
+// NewPoseWithCovariance creates an estimate of a pose with the given 6x6 covariance. A nil covariance means the pose is exact.
+func NewPoseWithCovariance(pose Pose, covariance *mat.SymDense) (*PoseWithCovariance, error) {
+	if pose == nil {
+		return nil, errors.New("pose is not allowed to be nil")
+	}
+	cov := mat.NewSymDense(poseErrorDims, nil)
+	if covariance != nil {
+		if n := covariance.SymmetricDim(); n != poseErrorDims {
+			return nil, errors.Errorf("pose covariance must be %dx%d, got %dx%d", poseErrorDims, poseErrorDims, n, n)
+		}
+		for i := 0; i < poseErrorDims; i++ {
+			if v := covariance.At(i, i); v < 0 || math.IsNaN(v) {
+				return nil, errors.Errorf("pose variances must be non-negative, got %v", v)
+			}
+		}
+		cov.CopySym(covariance)
+	}
+	return &PoseWithCovariance{pose: pose, covariance: cov}, nil
+}
+
+// NewPoseWithVariances creates an estimate of a pose whose errors are independent, given the variances of its position along X, Y and
+// Z in mm^2 and of its orientation about X, Y and Z in radians^2.
+func NewPoseWithVariances(pose Pose, position, orientation r3.Vector) (*PoseWithCovariance, error) {
+	cov := mat.NewSymDense(poseErrorDims, nil)
+	for i, v := range []float64{position.X, position.Y, position.Z, orientation.X, orientation.Y, orientation.Z} {
+		cov.SetSym(i, i, v)
+	}
+	return NewPoseWithCovariance(pose, cov)
+}
+
+// Pose returns the estimated pose.
+func (p *PoseWithCovariance) Pose() Pose {
+	return p.pose
+}
+
+// Covariance returns a copy of the covariance of the error of the pose.
+func (p *PoseWithCovariance) Covariance() *mat.SymDense {
+	cov := mat.NewSymDense(poseErrorDims, nil)
+	cov.CopySym(p.covariance)
+	return cov
+}
+
+// PositionVariance returns the variances of the position along X, Y and Z, in mm^2.
+func (p *PoseWithCovariance) PositionVariance() r3.Vector {
+	return r3.Vector{X: p.covariance.At(0, 0), Y: p.covariance.At(1, 1), Z: p.covariance.At(2, 2)}
+}
+
+// OrientationVariance returns the variances of the orientation about X, Y and Z, in radians^2.
+func (p *PoseWithCovariance) OrientationVariance() r3.Vector {
+	return r3.Vector{X: p.covariance.At(3, 3), Y: p.covariance.At(4, 4), Z: p.covariance.At(5, 5)}
+}
+
+// Transform premultiplies the pose with a transform, as Compose(toPremultiply, pose) does, rotating the covariance with it.
+func (p *PoseWithCovariance) Transform(toPremultiply Pose) *PoseWithCovariance {
+	rotation := NewPoseFromOrientation(toPremultiply.Orientation())
+	// the errors of both the position and the orientation are rotated, so the jacobian is the rotation twice along the diagonal
+	jacobian := mat.NewDense(poseErrorDims, poseErrorDims, nil)
+	for j, axis := range []r3.Vector{{X: 1}, {Y: 1}, {Z: 1}} {
+		col := Compose(rotation, NewPoseFromPoint(axis)).Point()
+		for i, v := range []float64{col.X, col.Y, col.Z} {
+			jacobian.Set(i, j, v)
+			jacobian.Set(i+3, j+3, v)
+		}
+	}
+	var rotated mat.Dense
+	rotated.Product(jacobian, p.covariance, jacobian.T())
+	cov := mat.NewSymDense(poseErrorDims, nil)
+	for i := 0; i < poseErrorDims; i++ {
+		for j := i; j < poseErrorDims; j++ {
+			// average the two halves to keep the result exactly symmetric
+			cov.SetSym(i, j, (rotated.At(i, j)+rotated.At(j, i))/2)
+		}
+	}
+	return &PoseWithCovariance{pose: Compose(toPremultiply, p.pose), covariance: cov}
+}
+
+// FusePoseEstimates combines independent estimates of the same pose into the most likely pose, weighting each estimate by the inverse
+// of its covariance, and returns it with the covariance of the combination, which is smaller than that of any of the estimates. Every
+// covariance must be positive definite. Orientations are combined as small rotations from one another, so the estimates should agree to
+// within a few tens of degrees.
+func FusePoseEstimates(estimates ...*PoseWithCovariance) (*PoseWithCovariance, error) {
+	if len(estimates) == 0 {
+		return nil, errors.New("cannot fuse zero pose estimates")
+	}
+	information := mat.NewSymDense(poseErrorDims, nil)
+	informations := make([]*mat.SymDense, 0, len(estimates))
+	for i, e := range estimates {
+		var chol mat.Cholesky
+		if ok := chol.Factorize(e.covariance); !ok {
+			return nil, errors.Errorf("covariance of pose estimate %d is not positive definite", i)
+		}
+		inverse := mat.NewSymDense(poseErrorDims, nil)
+		if err := chol.InverseTo(inverse); err != nil {
+			return nil, errors.Wrapf(err, "cannot invert covariance of pose estimate %d", i)
+		}
+		informations = append(informations, inverse)
+		information.AddSym(information, inverse)
+	}
+	var chol mat.Cholesky
+	if ok := chol.Factorize(information); !ok {
+		return nil, errors.New("could not fuse pose estimates")
+	}
+	cov := mat.NewSymDense(poseErrorDims, nil)
+	if err := chol.InverseTo(cov); err != nil {
+		return nil, errors.Wrap(err, "could not fuse pose estimates")
+	}
+
+	// minimize the weighted sum of squared errors to each estimate, relinearizing about the current pose until it stops moving
+	pose := estimates[0].pose
+	for iter := 0; iter < 10; iter++ {
+		sum := mat.NewVecDense(poseErrorDims, nil)
+		for i, e := range estimates {
+			var weighted mat.VecDense
+			weighted.MulVec(informations[i], poseError(pose, e.pose))
+			sum.AddVec(sum, &weighted)
+		}
+		var step mat.VecDense
+		if err := chol.SolveVecTo(&step, sum); err != nil {
+			return nil, errors.Wrap(err, "could not fuse pose estimates")
+		}
+		pose = applyPoseError(pose, &step)
+		if mat.Norm(&step, 2) < 1e-12 {
+			break
+		}
+	}
+	return &PoseWithCovariance{pose: pose, covariance: cov}, nil
+}
+
+// poseError returns the error that takes one pose to another, as the difference of their positions and the rotation from one
+// orientation to the other.
+func poseError(from, to Pose) *mat.VecDense {
+	delta := to.Point().Sub(from.Point())
+	rotation := QuatToR3AA(Normalize(quat.Mul(to.Orientation().Quaternion(), quat.Conj(from.Orientation().Quaternion()))))
+	return mat.NewVecDense(poseErrorDims, []float64{delta.X, delta.Y, delta.Z, rotation.X, rotation.Y, rotation.Z})
+}
+
+// applyPoseError returns the pose moved by the error, the inverse of poseError.
+func applyPoseError(p Pose, e mat.Vector) Pose {
+	o := quat.Mul(rotationVectorToQuat(r3.Vector{X: e.AtVec(3), Y: e.AtVec(4), Z: e.AtVec(5)}), p.Orientation().Quaternion())
+	return NewPose(p.Point().Add(r3.Vector{X: e.AtVec(0), Y: e.AtVec(1), Z: e.AtVec(2)}), (*Quaternion)(&o))
+}
+
+// ToMap converts the estimate into the form used in the DoCommand and extra fields of the API, which has no message for it. The pose
+// has the fields of a Pose message and the covariance is a list of its 36 entries in row-major order.
+func (p *PoseWithCovariance) ToMap() map[string]interface{} {
+	pb := PoseToProtobuf(p.pose)
+	cov := make([]interface{}, 0, poseErrorDims*poseErrorDims)
+	for i := 0; i < poseErrorDims; i++ {
+		for j := 0; j < poseErrorDims; j++ {
+			cov = append(cov, p.covariance.At(i, j))
+		}
+	}
+	return map[string]interface{}{
+		"pose": map[string]interface{}{
+			"x": pb.X, "y": pb.Y, "z": pb.Z, "o_x": pb.OX, "o_y": pb.OY, "o_z": pb.OZ, "theta": pb.Theta,
+		},
+		"covariance": cov,
+	}
+}
+
+// PoseWithCovarianceFromMap converts the form returned by ToMap back into the estimate.
+func PoseWithCovarianceFromMap(m map[string]interface{}) (*PoseWithCovariance, error) {
+	pose, ok := m["pose"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("pose estimate has no pose")
+	}
+	field := func(key string) (float64, error) {
+		v, ok := pose[key].(float64)
+		if !ok {
+			return 0, errors.Errorf("pose estimate has no %q", key)
+		}
+		return v, nil
+	}
+	var pb commonpb.Pose
+	for key, dst := range map[string]*float64{
+		"x": &pb.X, "y": &pb.Y, "z": &pb.Z, "o_x": &pb.OX, "o_y": &pb.OY, "o_z": &pb.OZ, "theta": &pb.Theta,
+	} {
+		v, err := field(key)
+		if err != nil {
+			return nil, err
+		}
+		*dst = v
+	}
+
+	entries, _ := m["covariance"].([]interface{})
+	if len(entries) != poseErrorDims*poseErrorDims {
+		return nil, errors.Errorf("pose covariance must have %d entries, got %d", poseErrorDims*poseErrorDims, len(entries))
+	}
+	cov := mat.NewSymDense(poseErrorDims, nil)
+	for i := 0; i < poseErrorDims; i++ {
+		for j := i; j < poseErrorDims; j++ {
+			v, ok := entries[i*poseErrorDims+j].(float64)
+			if !ok {
+				return nil, errors.New("pose covariance entries must be numbers")
+			}
+			cov.SetSym(i, j, v)
+		}
+	}
+	return NewPoseWithCovariance(NewPoseFromProtobuf(&pb), cov)
+}
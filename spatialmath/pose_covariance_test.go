@@ -0,0 +1,110 @@
+package spatialmath
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPoseWithCovariance(t *testing.T) {
+	pose := NewPose(r3.Vector{X: 100}, &OrientationVectorDegrees{OZ: 1})
+	estimate, err := NewPoseWithVariances(pose, r3.Vector{X: 4, Y: 1, Z: 9}, r3.Vector{Z: 0.01})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, estimate.PositionVariance(), test.ShouldResemble, r3.Vector{X: 4, Y: 1, Z: 9})
+	test.That(t, estimate.OrientationVariance(), test.ShouldResemble, r3.Vector{Z: 0.01})
+
+	// the covariance returned is a copy
+	estimate.Covariance().SetSym(0, 0, 100)
+	test.That(t, estimate.PositionVariance().X, test.ShouldEqual, 4)
+
+	exact, err := NewPoseWithCovariance(pose, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, exact.PositionVariance(), test.ShouldResemble, r3.Vector{})
+
+	_, err = NewPoseWithCovariance(nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewPoseWithCovariance(pose, mat.NewSymDense(3, nil))
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewPoseWithVariances(pose, r3.Vector{X: -1}, r3.Vector{})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestPoseWithCovarianceTransform(t *testing.T) {
+	cov := mat.NewSymDense(6, nil)
+	cov.SetSym(0, 0, 4)
+	cov.SetSym(1, 1, 1)
+	cov.SetSym(0, 1, 0.5)
+	cov.SetSym(3, 3, 0.01)
+	estimate, err := NewPoseWithCovariance(NewPoseFromPoint(r3.Vector{X: 10}), cov)
+	test.That(t, err, test.ShouldBeNil)
+
+	// a quarter turn about Z swaps the X and Y errors and negates their correlation
+	turned := estimate.Transform(NewPose(r3.Vector{Z: 5}, &OrientationVectorDegrees{OZ: 1, Theta: 90}))
+	test.That(t, R3VectorAlmostEqual(turned.Pose().Point(), r3.Vector{Y: 10, Z: 5}, 1e-9), test.ShouldBeTrue)
+	test.That(t, R3VectorAlmostEqual(turned.PositionVariance(), r3.Vector{X: 1, Y: 4}, 1e-9), test.ShouldBeTrue)
+	test.That(t, R3VectorAlmostEqual(turned.OrientationVariance(), r3.Vector{Y: 0.01}, 1e-9), test.ShouldBeTrue)
+	test.That(t, turned.Covariance().At(0, 1), test.ShouldAlmostEqual, -0.5)
+
+	// translations alone do not change the covariance
+	moved := estimate.Transform(NewPoseFromPoint(r3.Vector{X: 1, Y: 2, Z: 3}))
+	test.That(t, mat.EqualApprox(moved.Covariance(), cov, 1e-12), test.ShouldBeTrue)
+}
+
+func TestFusePoseEstimates(t *testing.T) {
+	// a precise estimate in X and a precise estimate in Y each dominate along their own axis
+	a, err := NewPoseWithVariances(
+		NewPose(r3.Vector{X: 0, Y: 0}, &OrientationVectorDegrees{OZ: 1, Theta: 0}),
+		r3.Vector{X: 1, Y: 100, Z: 1},
+		r3.Vector{X: 1, Y: 1, Z: 1},
+	)
+	test.That(t, err, test.ShouldBeNil)
+	b, err := NewPoseWithVariances(
+		NewPose(r3.Vector{X: 10, Y: 10}, &OrientationVectorDegrees{OZ: 1, Theta: 20}),
+		r3.Vector{X: 100, Y: 1, Z: 1},
+		r3.Vector{X: 1, Y: 1, Z: 1},
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	fused, err := FusePoseEstimates(a, b)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, R3VectorAlmostEqual(fused.Pose().Point(), r3.Vector{X: 10. / 101, Y: 1000. / 101}, 1e-9), test.ShouldBeTrue)
+	test.That(t, fused.Pose().Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 10, 1e-6)
+	test.That(t, R3VectorAlmostEqual(fused.PositionVariance(), r3.Vector{X: 100. / 101, Y: 100. / 101, Z: 0.5}, 1e-9), test.ShouldBeTrue)
+	test.That(t, R3VectorAlmostEqual(fused.OrientationVariance(), r3.Vector{X: 0.5, Y: 0.5, Z: 0.5}, 1e-9), test.ShouldBeTrue)
+
+	// one estimate fuses to itself
+	single, err := FusePoseEstimates(a)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, PoseAlmostEqual(single.Pose(), a.Pose()), test.ShouldBeTrue)
+
+	exact, err := NewPoseWithCovariance(NewZeroPose(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = FusePoseEstimates(a, exact)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = FusePoseEstimates()
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestPoseWithCovarianceMap(t *testing.T) {
+	cov := mat.NewSymDense(6, nil)
+	for i := 0; i < 6; i++ {
+		cov.SetSym(i, i, float64(i+1))
+	}
+	cov.SetSym(2, 4, 0.25)
+	estimate, err := NewPoseWithCovariance(NewPose(r3.Vector{X: 1, Y: 2, Z: 3}, &OrientationVectorDegrees{OX: 1, Theta: 30}), cov)
+	test.That(t, err, test.ShouldBeNil)
+
+	converted, err := PoseWithCovarianceFromMap(estimate.ToMap())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, PoseAlmostEqual(converted.Pose(), estimate.Pose()), test.ShouldBeTrue)
+	test.That(t, mat.Equal(converted.Covariance(), cov), test.ShouldBeTrue)
+
+	m := estimate.ToMap()
+	m["covariance"] = m["covariance"].([]interface{})[:35]
+	_, err = PoseWithCovarianceFromMap(m)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = PoseWithCovarianceFromMap(map[string]interface{}{})
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,128 @@
+package spatialmath
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/quat"
+
+	"go.viam.com/rdk/utils"
+)
+
+// InterpolatePoses returns steps+1 poses evenly spaced between from and to, starting with from and ending with to.
+// Positions are interpolated linearly and orientations with slerp, as with Interpolate.
+func InterpolatePoses(from, to Pose, steps int) []Pose {
+	if steps < 1 {
+		steps = 1
+	}
+	poses := make([]Pose, 0, steps+1)
+	poses = append(poses, from)
+	for i := 1; i < steps; i++ {
+		poses = append(poses, Interpolate(from, to, float64(i)/float64(steps)))
+	}
+	return append(poses, to)
+}
+
+// AveragePoses returns the weighted average of the poses. If weights is nil the poses are weighted equally, otherwise
+// there must be a non-negative weight for each pose. Positions are averaged linearly. Orientations are averaged by taking
+// the eigenvector of the largest eigenvalue of the weighted sum of the outer products of their quaternions, which,
+// unlike averaging the quaternions themselves, does not depend on which of the two quaternions of each orientation is used.
+// https://ntrs.nasa.gov/citations/20070017872
+func AveragePoses(poses []Pose, weights []float64) (Pose, error) {
+	if len(poses) == 0 {
+		return nil, errors.New("cannot average zero poses")
+	}
+	if weights != nil && len(weights) != len(poses) {
+		return nil, errors.Errorf("got %d weights for %d poses", len(weights), len(poses))
+	}
+	var total float64
+	var point r3.Vector
+	outer := mat.NewSymDense(4, nil)
+	for i, p := range poses {
+		w := 1.
+		if weights != nil {
+			w = weights[i]
+		}
+		if w < 0 || math.IsNaN(w) {
+			return nil, errors.Errorf("pose weights must be non-negative, got %v", w)
+		}
+		total += w
+		point = point.Add(p.Point().Mul(w))
+		q := Normalize(p.Orientation().Quaternion())
+		v := mat.NewVecDense(4, []float64{q.Real, q.Imag, q.Jmag, q.Kmag})
+		outer.SymRankOne(outer, w, v)
+	}
+	if total == 0 {
+		return nil, errors.New("pose weights must not all be zero")
+	}
+
+	var eig mat.EigenSym
+	if ok := eig.Factorize(outer, true); !ok {
+		return nil, errors.New("could not average pose orientations")
+	}
+	var vectors mat.Dense
+	eig.VectorsTo(&vectors)
+	// eigenvalues are in ascending order, so the last eigenvector is the average
+	avg := quat.Number{Real: vectors.At(0, 3), Imag: vectors.At(1, 3), Jmag: vectors.At(2, 3), Kmag: vectors.At(3, 3)}
+	if avg.Real < 0 {
+		avg = Flip(avg)
+	}
+	return NewPose(point.Mul(1/total), (*Quaternion)(&avg)), nil
+}
+
+// ExtrapolatePose returns where a pose moving with a constant twist will be after dt seconds. The linear velocity, in
+// mm/sec, and the angular velocity, in deg/sec, are both expressed in the frame the pose is in, and the rotation is
+// about the position of the pose.
+func ExtrapolatePose(p Pose, linear r3.Vector, angular AngularVelocity, dt float64) Pose {
+	rotation := r3.Vector{
+		X: utils.DegToRad(angular.X * dt),
+		Y: utils.DegToRad(angular.Y * dt),
+		Z: utils.DegToRad(angular.Z * dt),
+	}
+	o := quat.Mul(rotationVectorToQuat(rotation), p.Orientation().Quaternion())
+	return NewPose(p.Point().Add(linear.Mul(dt)), (*Quaternion)(&o))
+}
+
+// PoseTwist returns the constant linear velocity, in mm/sec, and angular velocity, in deg/sec, that take a pose from
+// one pose to another in dt seconds, both expressed in the frame the poses are in. ExtrapolatePose with the twist
+// returned moves from to to. The rotation is the shortest one between the two orientations.
+func PoseTwist(from, to Pose, dt float64) (r3.Vector, AngularVelocity, error) {
+	if dt <= 0 {
+		return r3.Vector{}, AngularVelocity{}, errors.Errorf("time between poses must be positive, got %v", dt)
+	}
+	linear := to.Point().Sub(from.Point()).Mul(1 / dt)
+	diff := quat.Mul(to.Orientation().Quaternion(), quat.Conj(from.Orientation().Quaternion()))
+	rotation := QuatToR3AA(Normalize(diff))
+	angular := AngularVelocity{
+		X: utils.RadToDeg(rotation.X) / dt,
+		Y: utils.RadToDeg(rotation.Y) / dt,
+		Z: utils.RadToDeg(rotation.Z) / dt,
+	}
+	return linear, angular, nil
+}
+
+// rotationVectorToQuat returns the quaternion of a rotation about the axis of the vector by its length in radians.
+func rotationVectorToQuat(v r3.Vector) quat.Number {
+	theta := v.Norm()
+	if theta < 1e-12 {
+		return quat.Number{Real: 1}
+	}
+	s := math.Sin(theta/2) / theta
+	return quat.Number{Real: math.Cos(theta / 2), Imag: v.X * s, Jmag: v.Y * s, Kmag: v.Z * s}
+}
+
+// TransformTwist returns the twist of a rigid body in another frame fixed to the body. The twist is given by the
+// linear velocity, in mm/sec, of the origin of a frame and the angular velocity, in deg/sec, of the body, both
+// expressed in that frame, and pose is the pose of that frame in the other one. The twist returned is the velocity of
+// the origin of the other frame and the angular velocity of the body, both expressed in the other frame.
+func TransformTwist(pose Pose, linear r3.Vector, angular AngularVelocity) (r3.Vector, AngularVelocity) {
+	rotation := NewPoseFromOrientation(pose.Orientation())
+	angularOut := Compose(rotation, NewPoseFromPoint(r3.Vector(angular))).Point()
+	linearOut := Compose(rotation, NewPoseFromPoint(linear)).Point()
+	// the origin of the other frame is offset from the origin of this one, so it also moves with the rotation of the body
+	radians := angularOut.Mul(math.Pi / 180)
+	linearOut = linearOut.Add(pose.Point().Cross(radians))
+	return linearOut, AngularVelocity(angularOut)
+}
@@ -0,0 +1,92 @@
+package spatialmath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestInterpolatePoses(t *testing.T) {
+	from := NewPose(r3.Vector{X: 0, Y: 0, Z: 0}, &OrientationVectorDegrees{OZ: 1})
+	to := NewPose(r3.Vector{X: 100, Y: -40, Z: 20}, &OrientationVectorDegrees{OZ: 1, Theta: 90})
+
+	poses := InterpolatePoses(from, to, 4)
+	test.That(t, poses, test.ShouldHaveLength, 5)
+	test.That(t, PoseAlmostEqual(poses[0], from), test.ShouldBeTrue)
+	test.That(t, PoseAlmostEqual(poses[4], to), test.ShouldBeTrue)
+	test.That(t, PoseAlmostEqual(poses[2], Interpolate(from, to, 0.5)), test.ShouldBeTrue)
+	test.That(t, R3VectorAlmostEqual(poses[1].Point(), r3.Vector{X: 25, Y: -10, Z: 5}, 1e-9), test.ShouldBeTrue)
+
+	test.That(t, InterpolatePoses(from, to, 0), test.ShouldHaveLength, 2)
+}
+
+func TestAveragePoses(t *testing.T) {
+	a := NewPose(r3.Vector{X: 10}, &OrientationVectorDegrees{OZ: 1, Theta: 10})
+	b := NewPose(r3.Vector{X: 30, Y: 20}, &OrientationVectorDegrees{OZ: 1, Theta: 30})
+
+	avg, err := AveragePoses([]Pose{a, b}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, R3VectorAlmostEqual(avg.Point(), r3.Vector{X: 20, Y: 10}, 1e-9), test.ShouldBeTrue)
+	test.That(t, avg.Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 20)
+
+	// the average does not depend on which of the two quaternions of an orientation is used
+	q := b.Orientation().Quaternion()
+	flipped := Flip(q)
+	avgFlipped, err := AveragePoses([]Pose{a, NewPose(b.Point(), (*Quaternion)(&flipped))}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, PoseAlmostEqual(avg, avgFlipped), test.ShouldBeTrue)
+
+	weighted, err := AveragePoses([]Pose{a, b}, []float64{3, 1})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, R3VectorAlmostEqual(weighted.Point(), r3.Vector{X: 15, Y: 5}, 1e-9), test.ShouldBeTrue)
+	// weighted averages of quaternions are close to, but not exactly, the weighted averages of their angles
+	test.That(t, weighted.Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 15, 0.1)
+
+	_, err = AveragePoses(nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = AveragePoses([]Pose{a, b}, []float64{1})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = AveragePoses([]Pose{a, b}, []float64{0, 0})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestExtrapolatePose(t *testing.T) {
+	start := NewPose(r3.Vector{X: 1, Y: 2, Z: 3}, &OrientationVectorDegrees{OZ: 1, Theta: 45})
+
+	// a quarter turn about z and 10 mm/sec along x for 2 seconds
+	end := ExtrapolatePose(start, r3.Vector{X: 10}, AngularVelocity{Z: 45}, 2)
+	test.That(t, R3VectorAlmostEqual(end.Point(), r3.Vector{X: 21, Y: 2, Z: 3}, 1e-9), test.ShouldBeTrue)
+	test.That(t, end.Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 135)
+
+	linear, angular, err := PoseTwist(start, end, 2)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, R3VectorAlmostEqual(linear, r3.Vector{X: 10}, 1e-9), test.ShouldBeTrue)
+	test.That(t, R3VectorAlmostEqual(r3.Vector(angular), r3.Vector{Z: 45}, 1e-9), test.ShouldBeTrue)
+
+	// the twist between any two poses extrapolates from one to the other
+	to := NewPose(r3.Vector{X: -5, Y: 7}, &OrientationVectorDegrees{OX: 1, OY: 2, OZ: 1, Theta: -30})
+	linear, angular, err = PoseTwist(start, to, 0.5)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, PoseAlmostEqual(ExtrapolatePose(start, linear, angular, 0.5), to), test.ShouldBeTrue)
+	halfway := ExtrapolatePose(start, linear, angular, 0.25)
+	test.That(t, PoseAlmostEqual(halfway, Interpolate(start, to, 0.5)), test.ShouldBeTrue)
+	test.That(t, math.IsNaN(ExtrapolatePose(start, r3.Vector{}, AngularVelocity{}, 1).Point().X), test.ShouldBeFalse)
+
+	_, _, err = PoseTwist(start, to, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestTransformTwist(t *testing.T) {
+	// a frame 100 mm ahead of another on the same body, turned to face left
+	pose := NewPose(r3.Vector{Y: 100}, &OrientationVectorDegrees{OZ: 1, Theta: -90})
+	linear, angular := TransformTwist(pose, r3.Vector{X: 10}, AngularVelocity{Z: 90})
+	test.That(t, R3VectorAlmostEqual(linear, r3.Vector{X: 50 * math.Pi, Y: -10}, 1e-9), test.ShouldBeTrue)
+	test.That(t, R3VectorAlmostEqual(r3.Vector(angular), r3.Vector{Z: 90}, 1e-9), test.ShouldBeTrue)
+
+	// transforming by the inverse pose recovers the twist
+	linear, angular = TransformTwist(PoseInverse(pose), linear, angular)
+	test.That(t, R3VectorAlmostEqual(linear, r3.Vector{X: 10}, 1e-9), test.ShouldBeTrue)
+	test.That(t, R3VectorAlmostEqual(r3.Vector(angular), r3.Vector{Z: 90}, 1e-9), test.ShouldBeTrue)
+}
@@ -102,6 +102,12 @@ func (s *sphere) CollidesWith(g Geometry) (bool, error) {
 	if other, ok := g.(*point); ok {
 		return sphereVsPointDistance(s, other.position) <= CollisionBuffer, nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsPointDistance(other, s.pose.Point())-s.radius <= CollisionBuffer, nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return cylinderVsSphereDistance(other, s) <= CollisionBuffer, nil
+	}
 	return true, newCollisionTypeUnsupportedError(s, g)
 }
 
@@ -118,6 +124,12 @@ func (s *sphere) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return sphereVsPointDistance(s, other.position), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return meshVsPointDistance(other, s.pose.Point()) - s.radius, nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return cylinderVsSphereDistance(other, s), nil
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(s, g)
 }
 
@@ -131,9 +143,15 @@ func (s *sphere) EncompassedBy(g Geometry) (bool, error) {
 	if other, ok := g.(*box); ok {
 		return sphereInBox(s, other), nil
 	}
+	if other, ok := g.(*cylinder); ok {
+		return sphereInCylinder(s, other), nil
+	}
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
 	return true, newCollisionTypeUnsupportedError(s, g)
 }
 
@@ -2,7 +2,9 @@ package inject
 
 import (
 	"context"
+	"errors"
 
+	geo "github.com/kellydunn/golang-geo"
 	servicepb "go.viam.com/api/service/motion/v1"
 
 	"go.viam.com/rdk/referenceframe"
@@ -32,6 +34,17 @@ type MotionService struct {
 		slamName resource.Name,
 		extra map[string]interface{},
 	) (bool, error)
+	MoveOnGlobeFunc func(
+		ctx context.Context,
+		componentName resource.Name,
+		destination *geo.Point,
+		heading float64,
+		movementSensorName resource.Name,
+		obstacles []*motion.GeoObstacle,
+		linearMmPerSec float64,
+		angularDegsPerSec float64,
+		extra map[string]interface{},
+	) (bool, error)
 	MoveSingleComponentFunc func(
 		ctx context.Context,
 		componentName resource.Name,
@@ -90,6 +103,32 @@ func (mgs *MotionService) MoveOnMap(
 	return mgs.MoveOnMap(ctx, componentName, destination, slamName, extra)
 }
 
+// MoveOnGlobe calls the injected MoveOnGlobe or the real variant.
+func (mgs *MotionService) MoveOnGlobe(
+	ctx context.Context,
+	componentName resource.Name,
+	destination *geo.Point,
+	heading float64,
+	movementSensorName resource.Name,
+	obstacles []*motion.GeoObstacle,
+	linearMmPerSec float64,
+	angularDegsPerSec float64,
+	extra map[string]interface{},
+) (bool, error) {
+	if mgs.MoveOnGlobeFunc == nil {
+		mover, ok := mgs.Service.(motion.GlobeMover)
+		if !ok {
+			return false, errors.New("MoveOnGlobe unimplemented")
+		}
+		return mover.MoveOnGlobe(
+			ctx, componentName, destination, heading, movementSensorName, obstacles, linearMmPerSec, angularDegsPerSec, extra,
+		)
+	}
+	return mgs.MoveOnGlobeFunc(
+		ctx, componentName, destination, heading, movementSensorName, obstacles, linearMmPerSec, angularDegsPerSec, extra,
+	)
+}
+
 // MoveSingleComponent calls the injected MoveSingleComponent or the real variant. It uses the same function as Move.
 func (mgs *MotionService) MoveSingleComponent(
 	ctx context.Context,
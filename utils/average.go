@@ -1,6 +1,10 @@
 package utils
 
-import "sync/atomic"
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
 
 // RollingAverage computes an average in a moving window
 // of a certain size. It is goroutine-safe but should be
@@ -37,3 +41,60 @@ func (ra *RollingAverage) Average() int {
 	}
 	return int(sum / int64(len(ra.data)))
 }
+
+// RollingMedian computes a median in a moving window of a certain
+// size. Unlike RollingAverage it is guarded by a mutex since a median
+// requires a consistent view of the whole window.
+type RollingMedian struct {
+	mu   sync.Mutex
+	data []int
+	pos  int
+	full bool
+}
+
+// NewRollingMedian returns a rolling median computed on the given
+// window size.
+func NewRollingMedian(windowSize int) *RollingMedian {
+	return &RollingMedian{data: make([]int, windowSize)}
+}
+
+// NumSamples returns the number of samples currently collected.
+func (rm *RollingMedian) NumSamples() int {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if rm.full {
+		return len(rm.data)
+	}
+	return rm.pos
+}
+
+// Add adds the given value to the samples.
+func (rm *RollingMedian) Add(x int) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.data[rm.pos] = x
+	rm.pos++
+	if rm.pos == len(rm.data) {
+		rm.pos = 0
+		rm.full = true
+	}
+}
+
+// Median recomputes and returns the current rolling median. For an
+// even number of samples the lower of the two middle values is returned.
+func (rm *RollingMedian) Median() int {
+	rm.mu.Lock()
+	n := rm.pos
+	if rm.full {
+		n = len(rm.data)
+	}
+	window := make([]int, n)
+	copy(window, rm.data[:n])
+	rm.mu.Unlock()
+
+	if n == 0 {
+		return 0
+	}
+	sort.Ints(window)
+	return window[(n-1)/2]
+}
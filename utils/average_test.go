@@ -17,3 +17,22 @@ func TestRolling1(t *testing.T) {
 
 	test.That(t, ra.NumSamples(), test.ShouldEqual, 2)
 }
+
+func TestRollingMedian(t *testing.T) {
+	rm := NewRollingMedian(3)
+	test.That(t, rm.Median(), test.ShouldEqual, 0)
+	test.That(t, rm.NumSamples(), test.ShouldEqual, 0)
+
+	rm.Add(5)
+	test.That(t, rm.Median(), test.ShouldEqual, 5)
+	rm.Add(100)
+	test.That(t, rm.Median(), test.ShouldEqual, 5)
+	rm.Add(7)
+	test.That(t, rm.Median(), test.ShouldEqual, 7)
+
+	// the outlier falls out of the window
+	rm.Add(6)
+	rm.Add(8)
+	test.That(t, rm.Median(), test.ShouldEqual, 7)
+	test.That(t, rm.NumSamples(), test.ShouldEqual, 3)
+}
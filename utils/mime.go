@@ -31,6 +31,10 @@ const (
 	// MimeTypeQOI is for .qoi "Quite OK Image" for lossless, fast encoding/decoding.
 	MimeTypeQOI = "image/qoi"
 
+	// MimeTypeWebP is for .webp images, which are smaller than JPEGs of the same quality. They can
+	// be decoded but not encoded.
+	MimeTypeWebP = "image/webp"
+
 	// MimeTypeTabular used to indicate tabular data, this is used mainly for filtering data.
 	MimeTypeTabular = "x-application/tabular"
 
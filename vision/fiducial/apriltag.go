@@ -0,0 +1,141 @@
+//go:build apriltag
+
+package fiducial
+
+/*
+#cgo LDFLAGS: -lapriltag
+#include <stdlib.h>
+#include <string.h>
+#include <apriltag/apriltag.h>
+#include <apriltag/tag36h11.h>
+#include <apriltag/tag25h9.h>
+#include <apriltag/tag16h5.h>
+#include <apriltag/tagStandard41h12.h>
+#include <apriltag/tagCircle21h7.h>
+
+static apriltag_family_t *family_create(const char *name) {
+	if (strcmp(name, "tag36h11") == 0) return tag36h11_create();
+	if (strcmp(name, "tag25h9") == 0) return tag25h9_create();
+	if (strcmp(name, "tag16h5") == 0) return tag16h5_create();
+	if (strcmp(name, "tagStandard41h12") == 0) return tagStandard41h12_create();
+	if (strcmp(name, "tagCircle21h7") == 0) return tagCircle21h7_create();
+	return NULL;
+}
+
+static void family_destroy(const char *name, apriltag_family_t *tf) {
+	if (strcmp(name, "tag36h11") == 0) tag36h11_destroy(tf);
+	else if (strcmp(name, "tag25h9") == 0) tag25h9_destroy(tf);
+	else if (strcmp(name, "tag16h5") == 0) tag16h5_destroy(tf);
+	else if (strcmp(name, "tagStandard41h12") == 0) tagStandard41h12_destroy(tf);
+	else if (strcmp(name, "tagCircle21h7") == 0) tagCircle21h7_destroy(tf);
+}
+
+static apriltag_detection_t *detection_at(zarray_t *detections, int i) {
+	apriltag_detection_t *det;
+	zarray_get(detections, i, &det);
+	return det;
+}
+*/
+import "C"
+
+import (
+	"image"
+	"sync"
+	"unsafe"
+
+	"github.com/golang/geo/r2"
+	"github.com/pkg/errors"
+)
+
+// aprilTagDetector finds tags with libapriltag.
+type aprilTagDetector struct {
+	family     string
+	familyName *C.char
+
+	mu       sync.Mutex
+	detector *C.apriltag_detector_t
+	tf       *C.apriltag_family_t
+}
+
+// NewDetector returns a detector of the tags of the given family, which uses numThreads threads, or one if 0.
+func NewDetector(family string, numThreads int) (Detector, error) {
+	if err := ValidateFamily(family); err != nil {
+		return nil, err
+	}
+	familyName := C.CString(aprilTagFamily(family))
+	tf := C.family_create(familyName)
+	if tf == nil {
+		C.free(unsafe.Pointer(familyName))
+		return nil, errors.Errorf("cannot create tag family %q", family)
+	}
+	detector := C.apriltag_detector_create()
+	if detector == nil {
+		C.family_destroy(familyName, tf)
+		C.free(unsafe.Pointer(familyName))
+		return nil, errors.New("cannot create AprilTag detector")
+	}
+	if numThreads > 0 {
+		detector.nthreads = C.int(numThreads)
+	}
+	C.apriltag_detector_add_family_bits(detector, tf, 2)
+	return &aprilTagDetector{family: family, familyName: familyName, detector: detector, tf: tf}, nil
+}
+
+// Detect returns the tags found in the image.
+func (d *aprilTagDetector) Detect(img image.Image) ([]Tag, error) {
+	gray := toGray(img)
+	if len(gray.Pix) == 0 {
+		return []Tag{}, nil
+	}
+	buf := C.CBytes(gray.Pix)
+	defer C.free(buf)
+	im := C.image_u8_t{
+		width:  C.int32_t(gray.Rect.Dx()),
+		height: C.int32_t(gray.Rect.Dy()),
+		stride: C.int32_t(gray.Stride),
+		buf:    (*C.uint8_t)(buf),
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.detector == nil {
+		return nil, errors.New("detector is closed")
+	}
+	detections := C.apriltag_detector_detect(d.detector, &im)
+	if detections == nil {
+		return nil, errors.New("AprilTag detection failed")
+	}
+	defer C.apriltag_detections_destroy(detections)
+
+	n := int(C.zarray_size(detections))
+	tags := make([]Tag, 0, n)
+	for i := 0; i < n; i++ {
+		det := C.detection_at(detections, C.int(i))
+		tag := Tag{
+			Family:         d.family,
+			ID:             int(det.id),
+			Hamming:        int(det.hamming),
+			DecisionMargin: float64(det.decision_margin),
+			Center:         r2.Point{X: float64(det.c[0]), Y: float64(det.c[1])},
+		}
+		for j := 0; j < 4; j++ {
+			tag.Corners[j] = r2.Point{X: float64(det.p[j][0]), Y: float64(det.p[j][1])}
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// Close frees the detector.
+func (d *aprilTagDetector) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.detector == nil {
+		return nil
+	}
+	C.apriltag_detector_destroy(d.detector)
+	C.family_destroy(d.familyName, d.tf)
+	C.free(unsafe.Pointer(d.familyName))
+	d.detector = nil
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !apriltag
+
+package fiducial
+
+import "github.com/pkg/errors"
+
+// NewDetector returns a detector of the tags of the given family, which uses numThreads threads, or one if 0.
+func NewDetector(family string, numThreads int) (Detector, error) {
+	if err := ValidateFamily(family); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("fiducial detection needs libapriltag and building with the apriltag build tag")
+}
@@ -0,0 +1,113 @@
+// Package fiducial detects fiducial markers, such as AprilTags, in images and estimates their poses
+// relative to the camera, so that they can be used as landmarks for docking and calibration.
+package fiducial
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/golang/geo/r2"
+	"github.com/pkg/errors"
+)
+
+// The supported tag families. The AprilTag families are also the DICT_APRILTAG_* dictionaries of ArUco.
+const (
+	FamilyTag36h11           = "tag36h11"
+	FamilyTag25h9            = "tag25h9"
+	FamilyTag16h5            = "tag16h5"
+	FamilyTagStandard41h12   = "tagStandard41h12"
+	FamilyTagCircle21h7      = "tagCircle21h7"
+	FamilyArucoAprilTag36h11 = "aruco_apriltag_36h11"
+)
+
+// Families are the tag families that can be detected.
+var Families = []string{
+	FamilyTag36h11,
+	FamilyTag25h9,
+	FamilyTag16h5,
+	FamilyTagStandard41h12,
+	FamilyTagCircle21h7,
+	FamilyArucoAprilTag36h11,
+}
+
+// ValidateFamily returns an error if the family is not one that can be detected.
+func ValidateFamily(family string) error {
+	for _, f := range Families {
+		if f == family {
+			return nil
+		}
+	}
+	return errors.Errorf("unsupported tag family %q, must be one of %v", family, Families)
+}
+
+// aprilTagFamily returns the AprilTag family of the given family, as the ArUco names are aliases.
+func aprilTagFamily(family string) string {
+	if family == FamilyArucoAprilTag36h11 {
+		return FamilyTag36h11
+	}
+	return family
+}
+
+// Tag is a fiducial marker found in an image.
+type Tag struct {
+	Family string
+	ID     int
+	// Hamming is how many bits of the code had to be corrected to decode the tag.
+	Hamming int
+	// DecisionMargin is how distinct the bits of the tag were, higher being better. It is only meaningful for
+	// small tags, and when comparing tags of the same image.
+	DecisionMargin float64
+	Center         r2.Point
+	// Corners are the corners of the tag in pixels, wrapping counterclockwise around the tag, with the tag frame
+	// coordinates (-1, 1), (1, 1), (1, -1) and (-1, -1), in units of half the tag size.
+	Corners [4]r2.Point
+}
+
+// Label returns the label of detections of the tag, which is the family and ID of the tag.
+func (t *Tag) Label() string {
+	return Label(t.Family, t.ID)
+}
+
+// Label returns the label of detections of the tag of the given family and ID, such as "tag36h11:3".
+func Label(family string, id int) string {
+	return fmt.Sprintf("%s:%d", family, id)
+}
+
+// BoundingBox returns the smallest rectangle that contains the corners of the tag.
+func (t *Tag) BoundingBox() image.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, c := range t.Corners {
+		minX, maxX = math.Min(minX, c.X), math.Max(maxX, c.X)
+		minY, maxY = math.Min(minY, c.Y), math.Max(maxY, c.Y)
+	}
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+}
+
+// Score returns a confidence score for the tag between 0 and 1, which drops with every corrected bit.
+func (t *Tag) Score() float64 {
+	return 1 / float64(1+t.Hamming)
+}
+
+// A Detector finds the tags of one family in images.
+type Detector interface {
+	Detect(img image.Image) ([]Tag, error)
+	Close() error
+}
+
+// toGray returns the image as 8 bit grayscale.
+func toGray(img image.Image) *image.Gray {
+	if gray, ok := img.(*image.Gray); ok {
+		return gray
+	}
+	bounds := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray.Set(x-bounds.Min.X, y-bounds.Min.Y, color.GrayModel.Convert(img.At(x, y)))
+		}
+	}
+	return gray
+}
@@ -0,0 +1,83 @@
+package fiducial
+
+import (
+	"math"
+
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// EstimatePose returns the pose of the tag in the frame of the camera that took the image it was found in, given
+// the intrinsics of the camera and the size in mm of the black square of the tag. The tag frame has its origin at
+// the center of the tag, with x to the right and y down when the tag is upright, and z pointing into the tag, the
+// same as the camera frame for a tag facing the camera. The image is assumed to be undistorted.
+func EstimatePose(tag *Tag, sizeMM float64, intrinsics *transform.PinholeCameraIntrinsics) (spatialmath.Pose, error) {
+	if err := intrinsics.CheckValid(); err != nil {
+		return nil, err
+	}
+	if sizeMM <= 0 {
+		return nil, errors.New("tag size must be positive")
+	}
+
+	// the homography from the tag plane in mm to normalized image coordinates is [r1 r2 t] up to scale
+	half := sizeMM / 2
+	tagPts := []r2.Point{{-half, half}, {half, half}, {half, -half}, {-half, -half}}
+	imgPts := make([]r2.Point, 4)
+	for i, c := range tag.Corners {
+		imgPts[i] = r2.Point{X: (c.X - intrinsics.Ppx) / intrinsics.Fx, Y: (c.Y - intrinsics.Ppy) / intrinsics.Fy}
+	}
+	h, err := transform.EstimateExactHomographyFrom8Points(tagPts, imgPts, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot estimate tag homography")
+	}
+	h1 := r3.Vector{X: h.At(0, 0), Y: h.At(1, 0), Z: h.At(2, 0)}
+	h2 := r3.Vector{X: h.At(0, 1), Y: h.At(1, 1), Z: h.At(2, 1)}
+	h3 := r3.Vector{X: h.At(0, 2), Y: h.At(1, 2), Z: h.At(2, 2)}
+	scale := (h1.Norm() + h2.Norm()) / 2
+	if scale == 0 {
+		return nil, errors.New("degenerate tag corners")
+	}
+	// the tag is in front of the camera
+	if h3.Z < 0 {
+		scale = -scale
+	}
+	r1, r2, t := h1.Mul(1/scale), h2.Mul(1/scale), h3.Mul(1/scale)
+	r3v := r1.Cross(r2)
+
+	// noise makes r1 and r2 not quite orthonormal, so use the closest rotation
+	var svd mat.SVD
+	if !svd.Factorize(mat.NewDense(3, 3, []float64{
+		r1.X, r2.X, r3v.X,
+		r1.Y, r2.Y, r3v.Y,
+		r1.Z, r2.Z, r3v.Z,
+	}), mat.SVDFull) {
+		return nil, errors.New("cannot orthonormalize tag rotation")
+	}
+	var u, v, rot mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	rot.Mul(&u, v.T())
+	if mat.Det(&rot) < 0 {
+		// flip the axis of the smallest singular value to get a proper rotation
+		for i := 0; i < 3; i++ {
+			u.Set(i, 2, -u.At(i, 2))
+		}
+		rot.Mul(&u, v.T())
+	}
+	// the orientations of spatialmath read rotation matrices in column major order
+	var colMajor mat.Dense
+	colMajor.CloneFrom(rot.T())
+	rm, err := spatialmath.NewRotationMatrix(colMajor.RawMatrix().Data)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(t.X) || math.IsNaN(t.Y) || math.IsNaN(t.Z) {
+		return nil, errors.New("degenerate tag corners")
+	}
+	return spatialmath.NewPose(t, rm), nil
+}
@@ -0,0 +1,67 @@
+package fiducial
+
+import (
+	"image"
+	"testing"
+
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/spatialmath"
+)
+
+var testIntrinsics = &transform.PinholeCameraIntrinsics{
+	Width:  1280,
+	Height: 720,
+	Fx:     900,
+	Fy:     900,
+	Ppx:    640,
+	Ppy:    360,
+}
+
+// projectTag returns the tag of the given size seen by the camera at the given pose.
+func projectTag(pose spatialmath.Pose, sizeMM float64) *Tag {
+	tag := &Tag{Family: FamilyTag36h11, ID: 7}
+	half := sizeMM / 2
+	for i, pt := range []r3.Vector{{-half, half, 0}, {half, half, 0}, {half, -half, 0}, {-half, -half, 0}} {
+		p := spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(pt)).Point()
+		x, y := p.X/p.Z*testIntrinsics.Fx+testIntrinsics.Ppx, p.Y/p.Z*testIntrinsics.Fy+testIntrinsics.Ppy
+		tag.Corners[i] = r2.Point{X: x, Y: y}
+	}
+	return tag
+}
+
+func TestEstimatePose(t *testing.T) {
+	for _, expected := range []spatialmath.Pose{
+		spatialmath.NewPoseFromPoint(r3.Vector{X: 0, Y: 0, Z: 1000}),
+		spatialmath.NewPose(r3.Vector{X: 120, Y: -80, Z: 750}, &spatialmath.EulerAngles{Roll: 0.3, Pitch: -0.2, Yaw: 0.5}),
+		spatialmath.NewPose(r3.Vector{X: -300, Y: 150, Z: 2000}, &spatialmath.EulerAngles{Roll: -0.4, Pitch: 0.5, Yaw: -2}),
+	} {
+		tag := projectTag(expected, 160)
+		pose, err := EstimatePose(tag, 160, testIntrinsics)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostEqualEps(pose, expected, 1e-3), test.ShouldBeTrue)
+	}
+
+	tag := projectTag(spatialmath.NewPoseFromPoint(r3.Vector{Z: 1000}), 160)
+	_, err := EstimatePose(tag, 0, testIntrinsics)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = EstimatePose(tag, 160, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = EstimatePose(&Tag{}, 160, testIntrinsics)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestTag(t *testing.T) {
+	tag := projectTag(spatialmath.NewPoseFromPoint(r3.Vector{Z: 900}), 100)
+	test.That(t, tag.Label(), test.ShouldEqual, "tag36h11:7")
+	test.That(t, tag.BoundingBox(), test.ShouldResemble, image.Rect(590, 310, 690, 410))
+	test.That(t, tag.Score(), test.ShouldEqual, 1.)
+	tag.Hamming = 1
+	test.That(t, tag.Score(), test.ShouldEqual, 0.5)
+
+	test.That(t, ValidateFamily(FamilyTag16h5), test.ShouldBeNil)
+	test.That(t, ValidateFamily("aruco_4x4_50"), test.ShouldNotBeNil)
+}
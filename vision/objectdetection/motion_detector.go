@@ -0,0 +1,255 @@
+package objectdetection
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+)
+
+// Default motion detector parameters.
+const (
+	DefaultMotionLearningRate      = 0.05
+	DefaultMotionSigmaThreshold    = 3.
+	DefaultMotionMinFraction       = 0.01
+	DefaultMotionDownscale         = 4
+	DefaultMotionLabel             = "motion"
+	motionInitialVariance          = 15 * 15
+	motionMinVariance              = 4 * 4
+	motionForegroundLearningFactor = 0.1
+)
+
+// Region is a rectangle of an image, in pixels.
+type Region struct {
+	XMin int `json:"x_min"`
+	YMin int `json:"y_min"`
+	XMax int `json:"x_max"`
+	YMax int `json:"y_max"`
+}
+
+// Rect returns the region as a rectangle.
+func (r Region) Rect() image.Rectangle {
+	return image.Rect(r.XMin, r.YMin, r.XMax, r.YMax)
+}
+
+// MotionDetectorConfig specifies the fields necessary for creating a motion detector.
+type MotionDetectorConfig struct {
+	// LearningRate is how quickly the background adapts to changes in the scene, between 0 and 1.
+	LearningRate float64 `json:"learning_rate,omitempty"`
+	// SigmaThreshold is how many standard deviations from the background a pixel must be to count as moving.
+	SigmaThreshold float64 `json:"sigma_threshold,omitempty"`
+	// MinMotionFraction is the fraction of the pixels of the regions of interest that must move for a frame to
+	// have motion.
+	MinMotionFraction float64 `json:"min_motion_fraction,omitempty"`
+	// MinAreaPx is the smallest area of moving pixels that is returned as a detection.
+	MinAreaPx int `json:"min_area_px,omitempty"`
+	// Downscale is the size of the square blocks of pixels that are averaged together before looking for motion.
+	Downscale int `json:"downscale,omitempty"`
+	// Regions are the regions of interest to look for motion in, which is the whole image if there are none.
+	Regions []Region `json:"regions,omitempty"`
+	// ExcludeRegions are the regions to ignore motion in, such as a busy road or a clock.
+	ExcludeRegions []Region `json:"exclude_regions,omitempty"`
+	Label          string   `json:"label,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *MotionDetectorConfig) Validate(path string) ([]string, error) {
+	if cfg.LearningRate < 0 || cfg.LearningRate > 1 {
+		return nil, utils.NewConfigValidationError(path, errors.Errorf("learning_rate must be between 0 and 1, got %v", cfg.LearningRate))
+	}
+	if cfg.SigmaThreshold < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("sigma_threshold cannot be negative"))
+	}
+	if cfg.MinMotionFraction < 0 || cfg.MinMotionFraction > 1 {
+		return nil, utils.NewConfigValidationError(path,
+			errors.Errorf("min_motion_fraction must be between 0 and 1, got %v", cfg.MinMotionFraction))
+	}
+	if cfg.MinAreaPx < 0 || cfg.Downscale < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("min_area_px and downscale cannot be negative"))
+	}
+	for i, r := range append(append([]Region{}, cfg.Regions...), cfg.ExcludeRegions...) {
+		if r.XMin >= r.XMax || r.YMin >= r.YMax {
+			return nil, utils.NewConfigValidationError(path, errors.Errorf("region %d is empty", i))
+		}
+	}
+	return nil, nil
+}
+
+// MotionDetector finds the parts of the frames of a camera that moved, by keeping a model of the background with
+// the running mean and variance of every pixel, and flagging the pixels that are far from it.
+type MotionDetector struct {
+	cfg MotionDetectorConfig
+
+	mu       sync.Mutex
+	bounds   image.Rectangle
+	mean     []float64
+	variance []float64
+	inRegion []bool
+}
+
+// NewMotionDetector returns a motion detector with the given parameters, replacing zero values by the defaults.
+func NewMotionDetector(cfg *MotionDetectorConfig) (*MotionDetector, error) {
+	if _, err := cfg.Validate("motion detector"); err != nil {
+		return nil, err
+	}
+	conf := *cfg
+	if conf.LearningRate == 0 {
+		conf.LearningRate = DefaultMotionLearningRate
+	}
+	if conf.SigmaThreshold == 0 {
+		conf.SigmaThreshold = DefaultMotionSigmaThreshold
+	}
+	if conf.MinMotionFraction == 0 {
+		conf.MinMotionFraction = DefaultMotionMinFraction
+	}
+	if conf.Downscale == 0 {
+		conf.Downscale = DefaultMotionDownscale
+	}
+	if conf.Label == "" {
+		conf.Label = DefaultMotionLabel
+	}
+	return &MotionDetector{cfg: conf}, nil
+}
+
+// Label returns the label of the detections and classifications of motion.
+func (md *MotionDetector) Label() string {
+	return md.cfg.Label
+}
+
+// MinMotionFraction returns the fraction of the regions of interest that must move for a frame to have motion.
+func (md *MotionDetector) MinMotionFraction() float64 {
+	return md.cfg.MinMotionFraction
+}
+
+// Update adds the frame to the background model and returns the mask of the blocks of pixels that moved, and the
+// fraction of the regions of interest that moved. The first frame, and the first after the size of the frames
+// changes, only starts the model and has no motion.
+func (md *MotionDetector) Update(img image.Image) (*image.Gray, float64) {
+	md.mu.Lock()
+	defer md.mu.Unlock()
+
+	ds := md.cfg.Downscale
+	bounds := img.Bounds()
+	width, height := (bounds.Dx()+ds-1)/ds, (bounds.Dy()+ds-1)/ds
+	mask := image.NewGray(image.Rect(0, 0, width, height))
+	reset := bounds != md.bounds
+	if reset {
+		md.reset(bounds, width, height)
+	}
+
+	alpha := md.cfg.LearningRate
+	k2 := md.cfg.SigmaThreshold * md.cfg.SigmaThreshold
+	moved, total := 0, 0
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			v := blockMean(img, bounds, x*ds, y*ds, ds)
+			if reset {
+				md.mean[i] = v
+				continue
+			}
+			d := v - md.mean[i]
+			foreground := d*d > k2*md.variance[i]
+			rate := alpha
+			if foreground {
+				// moving pixels still blend in slowly, so that things that stop moving become background
+				rate *= motionForegroundLearningFactor
+			}
+			md.mean[i] += rate * d
+			md.variance[i] += rate * (d*d - md.variance[i])
+			if md.variance[i] < motionMinVariance {
+				md.variance[i] = motionMinVariance
+			}
+			if !md.inRegion[i] {
+				continue
+			}
+			total++
+			if foreground {
+				moved++
+				mask.Pix[y*mask.Stride+x] = 255
+			}
+		}
+	}
+	if total == 0 {
+		return mask, 0
+	}
+	return mask, float64(moved) / float64(total)
+}
+
+func (md *MotionDetector) reset(bounds image.Rectangle, width, height int) {
+	ds := md.cfg.Downscale
+	md.bounds = bounds
+	md.mean = make([]float64, width*height)
+	md.variance = make([]float64, width*height)
+	md.inRegion = make([]bool, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			md.variance[i] = motionInitialVariance
+			// the block is in a region if its center is
+			center := image.Pt(bounds.Min.X+x*ds+ds/2, bounds.Min.Y+y*ds+ds/2)
+			in := len(md.cfg.Regions) == 0
+			for _, r := range md.cfg.Regions {
+				if center.In(r.Rect()) {
+					in = true
+					break
+				}
+			}
+			for _, r := range md.cfg.ExcludeRegions {
+				if center.In(r.Rect()) {
+					in = false
+					break
+				}
+			}
+			md.inRegion[i] = in
+		}
+	}
+}
+
+// blockMean returns the mean gray level of the size by size block of the image at the given offset from its corner.
+func blockMean(img image.Image, bounds image.Rectangle, x0, y0, size int) float64 {
+	sum, n := 0., 0
+	for y := bounds.Min.Y + y0; y < bounds.Min.Y+y0+size && y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X + x0; x < bounds.Min.X+x0+size && x < bounds.Max.X; x++ {
+			sum += float64(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+			n++
+		}
+	}
+	return sum / float64(n)
+}
+
+// Detect adds the frame to the background model and, if the frame has motion, returns a detection around each
+// connected area of moving pixels.
+func (md *MotionDetector) Detect(ctx context.Context, img image.Image) ([]Detection, error) {
+	mask, fraction := md.Update(img)
+	if fraction < md.cfg.MinMotionFraction {
+		return []Detection{}, nil
+	}
+	ccd := connectedComponentDetector{
+		valid: func(img image.Image, pt image.Point) bool {
+			gray, ok := img.(*image.Gray)
+			return ok && gray.GrayAt(pt.X, pt.Y).Y > 0
+		},
+		label: md.cfg.Label,
+	}
+	blobs, err := ccd.Inference(ctx, mask)
+	if err != nil {
+		return nil, err
+	}
+	ds := md.cfg.Downscale
+	bounds := img.Bounds()
+	dets := make([]Detection, 0, len(blobs))
+	for _, b := range blobs {
+		// the boxes of the connected components include their last row and column
+		box := b.BoundingBox()
+		rect := image.Rect(box.Min.X*ds, box.Min.Y*ds, (box.Max.X+1)*ds, (box.Max.Y+1)*ds).Add(bounds.Min).Intersect(bounds)
+		dets = append(dets, NewDetection(rect, b.Score(), md.cfg.Label))
+	}
+	if md.cfg.MinAreaPx > 0 {
+		dets = NewAreaFilter(md.cfg.MinAreaPx)(dets)
+	}
+	return dets, nil
+}
@@ -0,0 +1,92 @@
+package objectdetection
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+// motionFrame is a textured background with a bright square over the given rectangle.
+func motionFrame(square image.Rectangle) image.Image {
+	img := image.NewGray(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetGray(x, y, color.Gray{uint8(100 + (x+y)%3)})
+			if image.Pt(x, y).In(square) {
+				img.SetGray(x, y, color.Gray{250})
+			}
+		}
+	}
+	return img
+}
+
+func TestMotionDetectorConfigValidate(t *testing.T) {
+	cfg := MotionDetectorConfig{}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	cfg.LearningRate = 2
+	_, err = cfg.Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "learning_rate must be between 0 and 1")
+	cfg = MotionDetectorConfig{ExcludeRegions: []Region{{XMin: 10, XMax: 5, YMax: 10}}}
+	_, err = cfg.Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "region 0 is empty")
+}
+
+func TestMotionDetector(t *testing.T) {
+	ctx := context.Background()
+	md, err := NewMotionDetector(&MotionDetectorConfig{})
+	test.That(t, err, test.ShouldBeNil)
+
+	// a static scene has no motion
+	for i := 0; i < 5; i++ {
+		dets, err := md.Detect(ctx, motionFrame(image.Rectangle{}))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dets, test.ShouldHaveLength, 0)
+	}
+
+	square := image.Rect(20, 20, 36, 36)
+	dets, err := md.Detect(ctx, motionFrame(square))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dets, test.ShouldHaveLength, 1)
+	test.That(t, dets[0].BoundingBox(), test.ShouldResemble, &square)
+	test.That(t, dets[0].Label(), test.ShouldEqual, DefaultMotionLabel)
+
+	_, fraction := md.Update(motionFrame(square))
+	test.That(t, fraction, test.ShouldAlmostEqual, 16./192.)
+
+	// a frame of a new size starts the background over
+	dets, err = md.Detect(ctx, image.NewGray(image.Rect(0, 0, 32, 32)))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dets, test.ShouldHaveLength, 0)
+}
+
+func TestMotionDetectorRegions(t *testing.T) {
+	ctx := context.Background()
+	md, err := NewMotionDetector(&MotionDetectorConfig{
+		Label:          "person",
+		MinAreaPx:      100,
+		ExcludeRegions: []Region{{XMin: 0, YMin: 0, XMax: 32, YMax: 48}},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 5; i++ {
+		_, err := md.Detect(ctx, motionFrame(image.Rectangle{}))
+		test.That(t, err, test.ShouldBeNil)
+	}
+
+	// motion in the excluded region is ignored
+	_, fraction := md.Update(motionFrame(image.Rect(8, 8, 24, 24)))
+	test.That(t, fraction, test.ShouldEqual, 0)
+
+	// motion smaller than the minimum area is counted but not detected
+	dets, err := md.Detect(ctx, motionFrame(image.Rect(40, 8, 44, 12)))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dets, test.ShouldHaveLength, 0)
+
+	dets, err = md.Detect(ctx, motionFrame(image.Rect(40, 8, 56, 24)))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dets, test.ShouldHaveLength, 1)
+	test.That(t, dets[0].Label(), test.ShouldEqual, "person")
+}
@@ -1,6 +1,10 @@
 package objectdetection
 
-import "sort"
+import (
+	"image"
+	"sort"
+	"strings"
+)
 
 // Postprocessor defines a function that filters/modifies on an incoming array of Detections.
 type Postprocessor func([]Detection) []Detection
@@ -40,3 +44,105 @@ func SortByArea() Postprocessor {
 		return in
 	}
 }
+
+// NewLabelFilter returns a function that keeps only the detections with one of the given labels, ignoring case,
+// or, if exclude is true, removes them.
+func NewLabelFilter(labels []string, exclude bool) Postprocessor {
+	set := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		set[strings.ToLower(l)] = true
+	}
+	return func(in []Detection) []Detection {
+		out := make([]Detection, 0, len(in))
+		for _, d := range in {
+			if set[strings.ToLower(d.Label())] != exclude {
+				out = append(out, d)
+			}
+		}
+		return out
+	}
+}
+
+// NewRegionFilter returns a function that keeps only the detections that overlap the region of interest, with at
+// least minOverlap of the area of their bounding box inside it.
+func NewRegionFilter(region image.Rectangle, minOverlap float64) Postprocessor {
+	return func(in []Detection) []Detection {
+		out := make([]Detection, 0, len(in))
+		for _, d := range in {
+			box := d.BoundingBox()
+			inter := box.Intersect(region)
+			if inter.Empty() {
+				continue
+			}
+			area := box.Dx() * box.Dy()
+			if area > 0 && float64(inter.Dx()*inter.Dy())/float64(area) < minOverlap {
+				continue
+			}
+			out = append(out, d)
+		}
+		return out
+	}
+}
+
+// NewLabelScoreFilter returns a function that filters out detections below the confidence of their label, or below
+// defaultConf if their label has none.
+func NewLabelScoreFilter(confs map[string]float64, defaultConf float64) Postprocessor {
+	return func(in []Detection) []Detection {
+		out := make([]Detection, 0, len(in))
+		for _, d := range in {
+			conf, ok := confs[d.Label()]
+			if !ok {
+				conf = defaultConf
+			}
+			if d.Score() >= conf {
+				out = append(out, d)
+			}
+		}
+		return out
+	}
+}
+
+// NewLabelMapper returns a function that renames the labels of the detections that are keys of the mapping to their
+// values, such as to rename the classes of an off-the-shelf model or to merge several of them into one.
+func NewLabelMapper(mapping map[string]string) Postprocessor {
+	return func(in []Detection) []Detection {
+		out := make([]Detection, 0, len(in))
+		for _, d := range in {
+			if label, ok := mapping[d.Label()]; ok {
+				d = NewDetection(*d.BoundingBox(), d.Score(), label)
+			}
+			out = append(out, d)
+		}
+		return out
+	}
+}
+
+// NewNMSFilter returns a function that applies non-maximum suppression to the detections: of the detections whose
+// bounding boxes overlap with an IoU above iouThreshold, only the one with the highest score is kept. Only
+// detections with the same label suppress each other, unless classAgnostic is true.
+func NewNMSFilter(iouThreshold float64, classAgnostic bool) Postprocessor {
+	return func(in []Detection) []Detection {
+		sorted := make([]Detection, len(in))
+		copy(sorted, in)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Score() > sorted[j].Score()
+		})
+		out := make([]Detection, 0, len(sorted))
+		for _, d := range sorted {
+			suppressed := false
+			for _, kept := range out {
+				if !classAgnostic && kept.Label() != d.Label() {
+					continue
+				}
+				if IoU(*kept.BoundingBox(), *d.BoundingBox()) > iouThreshold {
+					suppressed = true
+					break
+				}
+			}
+			if !suppressed {
+				out = append(out, d)
+			}
+		}
+		return out
+	}
+}
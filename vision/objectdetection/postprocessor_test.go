@@ -44,4 +44,58 @@ func TestPostprocessors(t *testing.T) {
 	test.That(t, labelList, test.ShouldNotContain, "B")
 	test.That(t, labelList, test.ShouldContain, "C")
 	test.That(t, labelList, test.ShouldContain, "D")
+
+	labelFilt := NewLabelFilter([]string{"a", "C"}, false)
+	got = labelFilt(d)
+	test.That(t, len(got), test.ShouldEqual, 2)
+	test.That(t, got[0].Label(), test.ShouldEqual, "C")
+	test.That(t, got[1].Label(), test.ShouldEqual, "A")
+	got = NewLabelFilter([]string{"a", "C"}, true)(d)
+	test.That(t, len(got), test.ShouldEqual, 2)
+	test.That(t, got[0].Label(), test.ShouldEqual, "B")
+	test.That(t, got[1].Label(), test.ShouldEqual, "D")
+
+	regionFilt := NewRegionFilter(image.Rect(0, 0, 100, 100), 0.5)
+	got = regionFilt(d)
+	test.That(t, len(got), test.ShouldEqual, 1)
+	test.That(t, got[0].Label(), test.ShouldEqual, "A")
+	got = NewRegionFilter(image.Rect(0, 0, 100, 100), 0)(d)
+	test.That(t, len(got), test.ShouldEqual, 3)
+	test.That(t, got[0].Label(), test.ShouldEqual, "B")
+	test.That(t, got[1].Label(), test.ShouldEqual, "D")
+	test.That(t, got[2].Label(), test.ShouldEqual, "A")
+}
+
+func TestModelPostprocessors(t *testing.T) {
+	d := []Detection{
+		NewDetection(image.Rect(0, 0, 100, 100), 0.6, "dog"),
+		NewDetection(image.Rect(5, 5, 100, 100), 0.9, "dog"),
+		NewDetection(image.Rect(0, 0, 95, 95), 0.7, "puppy"),
+		NewDetection(image.Rect(200, 200, 300, 300), 0.4, "cat"),
+	}
+
+	got := NewNMSFilter(0.5, false)(d)
+	test.That(t, len(got), test.ShouldEqual, 3)
+	test.That(t, got[0].Score(), test.ShouldEqual, 0.9)
+	test.That(t, got[1].Label(), test.ShouldEqual, "puppy")
+	test.That(t, got[2].Label(), test.ShouldEqual, "cat")
+	got = NewNMSFilter(0.5, true)(d)
+	test.That(t, len(got), test.ShouldEqual, 2)
+	test.That(t, got[0].Score(), test.ShouldEqual, 0.9)
+	test.That(t, got[1].Label(), test.ShouldEqual, "cat")
+	// the input is left in its order
+	test.That(t, d[0].Score(), test.ShouldEqual, 0.6)
+
+	got = NewLabelScoreFilter(map[string]float64{"cat": 0.3, "dog": 0.8}, 0.65)(d)
+	test.That(t, len(got), test.ShouldEqual, 3)
+	test.That(t, got[0].Score(), test.ShouldEqual, 0.9)
+	test.That(t, got[1].Label(), test.ShouldEqual, "puppy")
+	test.That(t, got[2].Label(), test.ShouldEqual, "cat")
+
+	got = NewLabelMapper(map[string]string{"puppy": "dog"})(d)
+	test.That(t, len(got), test.ShouldEqual, 4)
+	test.That(t, got[2].Label(), test.ShouldEqual, "dog")
+	test.That(t, got[2].Score(), test.ShouldEqual, 0.7)
+	test.That(t, *got[2].BoundingBox(), test.ShouldResemble, image.Rect(0, 0, 95, 95))
+	test.That(t, got[3].Label(), test.ShouldEqual, "cat")
 }
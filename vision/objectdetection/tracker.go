@@ -0,0 +1,297 @@
+package objectdetection
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TrackedDetection is a detection that has been followed across frames. Its track ID stays the same for as
+// long as the tracker keeps matching the object, and its velocity is that of the bounding box center.
+type TrackedDetection interface {
+	Detection
+	TrackID() int
+	// Velocity returns the velocity of the bounding box center in pixels per second.
+	Velocity() (vx, vy float64)
+	// Hits returns how many frames the track has been matched in.
+	Hits() int
+}
+
+// TrackerConfig are the parameters of a Tracker. Zero values are replaced by the defaults.
+type TrackerConfig struct {
+	// IoUThreshold is the intersection over union a detection must have with the predicted box of a track to match it.
+	IoUThreshold float64 `json:"iou_threshold,omitempty"`
+	// MaxAge is how many frames a track lives on without being matched.
+	MaxAge int `json:"max_age_frames,omitempty"`
+	// MinHits is how many frames a track must be matched in before it is returned.
+	MinHits int `json:"min_hits,omitempty"`
+	// HighScoreThreshold splits detections into confident ones, which are matched first and may start new tracks,
+	// and low confidence ones, which are only used to keep existing tracks alive.
+	HighScoreThreshold float64 `json:"high_score_threshold,omitempty"`
+}
+
+// Default tracker parameters.
+const (
+	DefaultTrackerIoUThreshold       = 0.3
+	DefaultTrackerMaxAge             = 30
+	DefaultTrackerMinHits            = 3
+	DefaultTrackerHighScoreThreshold = 0.5
+)
+
+// velocitySmoothing is the weight given to the newest velocity measurement of a track.
+const velocitySmoothing = 0.5
+
+// Validate ensures all parts of the config are valid.
+func (cfg *TrackerConfig) Validate(path string) error {
+	if cfg.IoUThreshold < 0 || cfg.IoUThreshold > 1 {
+		return errors.Errorf("%s: iou_threshold must be between 0 and 1, got %v", path, cfg.IoUThreshold)
+	}
+	if cfg.HighScoreThreshold < 0 || cfg.HighScoreThreshold > 1 {
+		return errors.Errorf("%s: high_score_threshold must be between 0 and 1, got %v", path, cfg.HighScoreThreshold)
+	}
+	if cfg.MaxAge < 0 {
+		return errors.Errorf("%s: max_age_frames cannot be negative", path)
+	}
+	if cfg.MinHits < 0 {
+		return errors.Errorf("%s: min_hits cannot be negative", path)
+	}
+	return nil
+}
+
+// Tracker assigns stable IDs to detections across frames. It predicts where each track moved with a constant
+// velocity model, then greedily matches detections to the predictions by IoU, first the confident detections
+// and then the low confidence ones, in the manner of SORT and ByteTrack.
+type Tracker struct {
+	cfg TrackerConfig
+
+	mu     sync.Mutex
+	tracks []*track
+	nextID int
+	last   time.Time
+}
+
+// NewTracker returns a tracker with the given parameters.
+func NewTracker(cfg TrackerConfig) *Tracker {
+	if cfg.IoUThreshold == 0 {
+		cfg.IoUThreshold = DefaultTrackerIoUThreshold
+	}
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = DefaultTrackerMaxAge
+	}
+	if cfg.MinHits == 0 {
+		cfg.MinHits = DefaultTrackerMinHits
+	}
+	if cfg.HighScoreThreshold == 0 {
+		cfg.HighScoreThreshold = DefaultTrackerHighScoreThreshold
+	}
+	return &Tracker{cfg: cfg, nextID: 1}
+}
+
+// Update matches the detections of the frame taken at the given time to the existing tracks and returns the
+// confirmed tracks that were matched in this frame.
+func (t *Tracker) Update(dets []Detection, at time.Time) []TrackedDetection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	dt := 0.
+	if !t.last.IsZero() && at.After(t.last) {
+		dt = at.Sub(t.last).Seconds()
+	}
+	t.last = at
+	for _, tr := range t.tracks {
+		tr.predict(dt)
+		tr.matched = false
+	}
+
+	var high, low []Detection
+	for _, d := range dets {
+		if d.Score() >= t.cfg.HighScoreThreshold {
+			high = append(high, d)
+		} else {
+			low = append(low, d)
+		}
+	}
+	unmatched := t.match(t.tracks, high)
+	leftover := make([]*track, 0, len(t.tracks))
+	for _, tr := range t.tracks {
+		if !tr.matched {
+			leftover = append(leftover, tr)
+		}
+	}
+	t.match(leftover, low)
+	for _, tr := range leftover {
+		if !tr.matched {
+			tr.age++
+		}
+	}
+
+	for _, d := range unmatched {
+		t.tracks = append(t.tracks, newTrack(t.nextID, d))
+		t.nextID++
+	}
+
+	out := make([]TrackedDetection, 0, len(t.tracks))
+	alive := t.tracks[:0]
+	for _, tr := range t.tracks {
+		if tr.age > t.cfg.MaxAge {
+			continue
+		}
+		alive = append(alive, tr)
+		if tr.age == 0 && tr.hits >= t.cfg.MinHits {
+			out = append(out, tr.snapshot())
+		}
+	}
+	t.tracks = alive
+	return out
+}
+
+// Tracks returns the confirmed tracks that are still alive, whether or not they were matched in the last frame.
+func (t *Tracker) Tracks() []TrackedDetection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]TrackedDetection, 0, len(t.tracks))
+	for _, tr := range t.tracks {
+		if tr.hits >= t.cfg.MinHits {
+			out = append(out, tr.snapshot())
+		}
+	}
+	return out
+}
+
+// match greedily pairs tracks with detections, highest IoU first, updates the matched tracks and returns the
+// detections that matched no track.
+func (t *Tracker) match(tracks []*track, dets []Detection) []Detection {
+	type pair struct {
+		ti, di int
+		iou    float64
+	}
+	var pairs []pair
+	for ti, tr := range tracks {
+		for di, d := range dets {
+			if iou := IoU(tr.predicted, *d.BoundingBox()); iou >= t.cfg.IoUThreshold {
+				pairs = append(pairs, pair{ti, di, iou})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].iou > pairs[j].iou })
+
+	trackUsed := make([]bool, len(tracks))
+	detUsed := make([]bool, len(dets))
+	for _, p := range pairs {
+		if trackUsed[p.ti] || detUsed[p.di] {
+			continue
+		}
+		trackUsed[p.ti], detUsed[p.di] = true, true
+		tracks[p.ti].update(dets[p.di])
+	}
+
+	var unmatched []Detection
+	for di, d := range dets {
+		if !detUsed[di] {
+			unmatched = append(unmatched, d)
+		}
+	}
+	return unmatched
+}
+
+// IoU returns the intersection over union of two rectangles.
+func IoU(a, b image.Rectangle) float64 {
+	inter := a.Intersect(b)
+	interArea := inter.Dx() * inter.Dy()
+	union := a.Dx()*a.Dy() + b.Dx()*b.Dy() - interArea
+	if union <= 0 {
+		return 0
+	}
+	return float64(interArea) / float64(union)
+}
+
+// track is the state of one tracked object.
+type track struct {
+	id        int
+	det       Detection
+	box       image.Rectangle // the last matched box
+	predicted image.Rectangle // the box predicted for the current frame
+	elapsed   float64         // seconds since the last match
+	vx, vy    float64
+	hits      int
+	age       int  // frames since the last match
+	matched   bool // whether the track was matched in the current frame
+}
+
+func newTrack(id int, d Detection) *track {
+	box := *d.BoundingBox()
+	return &track{id: id, det: d, box: box, predicted: box, hits: 1}
+}
+
+func (tr *track) predict(dt float64) {
+	tr.elapsed += dt
+	tr.predicted = tr.box.Add(image.Pt(int(tr.vx*tr.elapsed), int(tr.vy*tr.elapsed)))
+}
+
+func (tr *track) update(d Detection) {
+	box := *d.BoundingBox()
+	if tr.elapsed > 0 {
+		oldX, oldY := center(tr.box)
+		newX, newY := center(box)
+		vx, vy := (newX-oldX)/tr.elapsed, (newY-oldY)/tr.elapsed
+		if tr.hits == 1 {
+			tr.vx, tr.vy = vx, vy
+		} else {
+			tr.vx = velocitySmoothing*vx + (1-velocitySmoothing)*tr.vx
+			tr.vy = velocitySmoothing*vy + (1-velocitySmoothing)*tr.vy
+		}
+	}
+	tr.det = d
+	tr.box = box
+	tr.predicted = box
+	tr.elapsed = 0
+	tr.hits++
+	tr.age = 0
+	tr.matched = true
+}
+
+func (tr *track) snapshot() TrackedDetection {
+	return &trackedDetection{
+		Detection: NewDetection(tr.box, tr.det.Score(), tr.det.Label()),
+		id:        tr.id,
+		vx:        tr.vx,
+		vy:        tr.vy,
+		hits:      tr.hits,
+	}
+}
+
+func center(r image.Rectangle) (float64, float64) {
+	return float64(r.Min.X+r.Max.X) / 2, float64(r.Min.Y+r.Max.Y) / 2
+}
+
+// trackedDetection is a detection together with the state of its track.
+type trackedDetection struct {
+	Detection
+	id     int
+	vx, vy float64
+	hits   int
+}
+
+// TrackID returns the ID of the track.
+func (d *trackedDetection) TrackID() int {
+	return d.id
+}
+
+// Velocity returns the velocity of the bounding box center in pixels per second.
+func (d *trackedDetection) Velocity() (float64, float64) {
+	return d.vx, d.vy
+}
+
+// Hits returns how many frames the track has been matched in.
+func (d *trackedDetection) Hits() int {
+	return d.hits
+}
+
+// String turns the tracked detection into a string.
+func (d *trackedDetection) String() string {
+	return fmt.Sprintf("Track: %d, %v, Velocity: (%.1f, %.1f)", d.id, d.Detection, d.vx, d.vy)
+}
@@ -0,0 +1,107 @@
+package objectdetection
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestIoU(t *testing.T) {
+	a := image.Rect(0, 0, 10, 10)
+	test.That(t, IoU(a, a), test.ShouldEqual, 1.)
+	test.That(t, IoU(a, image.Rect(20, 20, 30, 30)), test.ShouldEqual, 0.)
+	test.That(t, IoU(a, image.Rect(5, 0, 15, 10)), test.ShouldAlmostEqual, 50./150.)
+	test.That(t, IoU(image.Rectangle{}, image.Rectangle{}), test.ShouldEqual, 0.)
+}
+
+func TestTrackerConfigValidate(t *testing.T) {
+	cfg := TrackerConfig{}
+	test.That(t, cfg.Validate("path"), test.ShouldBeNil)
+	cfg.IoUThreshold = 1.5
+	test.That(t, cfg.Validate("path"), test.ShouldBeError, "path: iou_threshold must be between 0 and 1, got 1.5")
+	cfg = TrackerConfig{MaxAge: -1}
+	test.That(t, cfg.Validate("path"), test.ShouldBeError, "path: max_age_frames cannot be negative")
+}
+
+func TestTrackerStableIDs(t *testing.T) {
+	tracker := NewTracker(TrackerConfig{MinHits: 2})
+	start := time.Unix(0, 0)
+	frame := func(i int) []Detection {
+		// A moves right 5 pixels a frame and B stands still
+		return []Detection{
+			NewDetection(image.Rect(5*i, 0, 5*i+40, 40), 0.9, "A"),
+			NewDetection(image.Rect(200, 200, 240, 240), 0.8, "B"),
+		}
+	}
+
+	// tracks are not returned until they have been matched MinHits times
+	got := tracker.Update(frame(0), start)
+	test.That(t, got, test.ShouldBeEmpty)
+
+	ids := map[string]int{}
+	for i := 1; i < 10; i++ {
+		got = tracker.Update(frame(i), start.Add(time.Duration(i)*100*time.Millisecond))
+		test.That(t, got, test.ShouldHaveLength, 2)
+		for _, d := range got {
+			if i == 1 {
+				ids[d.Label()] = d.TrackID()
+			}
+			test.That(t, d.TrackID(), test.ShouldEqual, ids[d.Label()])
+			test.That(t, d.Hits(), test.ShouldEqual, i+1)
+		}
+	}
+	test.That(t, ids["A"], test.ShouldNotEqual, ids["B"])
+	for _, d := range got {
+		vx, vy := d.Velocity()
+		switch d.Label() {
+		case "A":
+			test.That(t, vx, test.ShouldAlmostEqual, 50.)
+		case "B":
+			test.That(t, vx, test.ShouldAlmostEqual, 0.)
+		}
+		test.That(t, vy, test.ShouldAlmostEqual, 0.)
+	}
+}
+
+func TestTrackerLowScoreAndAging(t *testing.T) {
+	tracker := NewTracker(TrackerConfig{MinHits: 1, MaxAge: 2, HighScoreThreshold: 0.5})
+	start := time.Unix(0, 0)
+	box := image.Rect(0, 0, 40, 40)
+
+	got := tracker.Update([]Detection{NewDetection(box, 0.9, "A")}, start)
+	test.That(t, got, test.ShouldHaveLength, 1)
+	id := got[0].TrackID()
+
+	// a low confidence detection keeps an existing track alive
+	got = tracker.Update([]Detection{NewDetection(box, 0.2, "A")}, start.Add(time.Second))
+	test.That(t, got, test.ShouldHaveLength, 1)
+	test.That(t, got[0].TrackID(), test.ShouldEqual, id)
+	test.That(t, got[0].Score(), test.ShouldEqual, 0.2)
+
+	// but cannot start a new one
+	got = tracker.Update([]Detection{
+		NewDetection(box, 0.9, "A"),
+		NewDetection(image.Rect(100, 100, 140, 140), 0.2, "B"),
+	}, start.Add(2*time.Second))
+	test.That(t, got, test.ShouldHaveLength, 1)
+	test.That(t, got[0].TrackID(), test.ShouldEqual, id)
+
+	// a missed track is kept for MaxAge frames and keeps its ID when it comes back
+	tracker.Update(nil, start.Add(3*time.Second))
+	tracker.Update(nil, start.Add(4*time.Second))
+	test.That(t, tracker.Tracks(), test.ShouldHaveLength, 1)
+	got = tracker.Update([]Detection{NewDetection(box, 0.9, "A")}, start.Add(5*time.Second))
+	test.That(t, got, test.ShouldHaveLength, 1)
+	test.That(t, got[0].TrackID(), test.ShouldEqual, id)
+
+	// after that it is dropped and the object gets a new ID
+	for i := 6; i < 9; i++ {
+		tracker.Update(nil, start.Add(time.Duration(i)*time.Second))
+	}
+	test.That(t, tracker.Tracks(), test.ShouldBeEmpty)
+	got = tracker.Update([]Detection{NewDetection(box, 0.9, "A")}, start.Add(9*time.Second))
+	test.That(t, got, test.ShouldHaveLength, 1)
+	test.That(t, got[0].TrackID(), test.ShouldNotEqual, id)
+}
@@ -2,6 +2,9 @@ package segmentation
 
 import (
 	"context"
+	"image"
+	"image/color"
+	"sort"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
@@ -115,3 +118,74 @@ func detectionToPointCloud(
 	}
 	return pc, nil
 }
+
+// DefaultDepthBandMM is how far from the median depth of a detection the points of the object may be by default.
+const DefaultDepthBandMM = 100.
+
+// DetectionsToObjects projects the detections of an image into 3D using the depth map aligned with it, and returns an
+// object for each detection with depth. The points of an object are those of its bounding box that are within
+// depthBandMM of their median depth, which leaves out the background and foreground around the object, as well as
+// the pixels that have no depth.
+func DetectionsToObjects(
+	dets []objectdetection.Detection,
+	img *rimage.Image,
+	dm *rimage.DepthMap,
+	proj transform.Projector,
+	depthBandMM float64,
+) ([]*vision.Object, error) {
+	if img == nil || dm == nil {
+		return nil, errors.New("detections need both a color image and a depth map to be projected to 3D")
+	}
+	if img.Bounds() != dm.Bounds() {
+		return nil, errors.Errorf("depth map and color dimensions don't match Depth(%d,%d) != Color(%d,%d)",
+			dm.Width(), dm.Height(), img.Width(), img.Height())
+	}
+	if depthBandMM <= 0 {
+		depthBandMM = DefaultDepthBandMM
+	}
+	objects := make([]*vision.Object, 0, len(dets))
+	for _, d := range dets {
+		bb := d.BoundingBox()
+		if bb == nil {
+			return nil, errors.New("detection bounding box cannot be nil")
+		}
+		box := bb.Intersect(dm.Bounds())
+		depths := make([]rimage.Depth, 0, box.Dx()*box.Dy())
+		for y := box.Min.Y; y < box.Max.Y; y++ {
+			for x := box.Min.X; x < box.Max.X; x++ {
+				if z := dm.GetDepth(x, y); z > 0 {
+					depths = append(depths, z)
+				}
+			}
+		}
+		if len(depths) == 0 {
+			continue
+		}
+		sort.Slice(depths, func(i, j int) bool { return depths[i] < depths[j] })
+		median := float64(depths[len(depths)/2])
+
+		pc := pointcloud.NewWithPrealloc(len(depths))
+		for y := box.Min.Y; y < box.Max.Y; y++ {
+			for x := box.Min.X; x < box.Max.X; x++ {
+				z := dm.GetDepth(x, y)
+				if z == 0 || float64(z) < median-depthBandMM || float64(z) > median+depthBandMM {
+					continue
+				}
+				pt, err := proj.ImagePointTo3DPoint(image.Point{x, y}, z)
+				if err != nil {
+					return nil, err
+				}
+				r, g, b := img.GetXY(x, y).RGB255()
+				if err := pc.Set(pt, pointcloud.NewColoredData(color.NRGBA{r, g, b, 255})); err != nil {
+					return nil, err
+				}
+			}
+		}
+		obj, err := vision.NewObjectWithLabel(pc, d.Label())
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}